@@ -279,6 +279,174 @@ func (c *clientImpl) RefreshWorkflowTasks(
 	return client.RefreshWorkflowTasks(ctx, request, opts...)
 }
 
+func (c *clientImpl) DeleteWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.DeleteWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DeleteWorkflowExecutionResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.DeleteWorkflowExecution(ctx, request, opts...)
+}
+
+func (c *clientImpl) ListDynamicConfig(
+	ctx context.Context,
+	request *adminservice.ListDynamicConfigRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ListDynamicConfigResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.ListDynamicConfig(ctx, request, opts...)
+}
+
+func (c *clientImpl) ExportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ExportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ExportWorkflowExecutionResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.ExportWorkflowExecution(ctx, request, opts...)
+}
+
+func (c *clientImpl) ImportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ImportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ImportWorkflowExecutionResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.ImportWorkflowExecution(ctx, request, opts...)
+}
+
+func (c *clientImpl) ListTaskListTasks(
+	ctx context.Context,
+	request *adminservice.ListTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ListTaskListTasksResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.ListTaskListTasks(ctx, request, opts...)
+}
+
+func (c *clientImpl) CountTaskListTasks(
+	ctx context.Context,
+	request *adminservice.CountTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.CountTaskListTasksResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.CountTaskListTasks(ctx, request, opts...)
+}
+
+func (c *clientImpl) DeleteTaskListTasks(
+	ctx context.Context,
+	request *adminservice.DeleteTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DeleteTaskListTasksResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.DeleteTaskListTasks(ctx, request, opts...)
+}
+
+func (c *clientImpl) MoveTaskListTasks(
+	ctx context.Context,
+	request *adminservice.MoveTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.MoveTaskListTasksResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.MoveTaskListTasks(ctx, request, opts...)
+}
+
+func (c *clientImpl) UpdateTaskListDispatchConfig(
+	ctx context.Context,
+	request *adminservice.UpdateTaskListDispatchConfigRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.UpdateTaskListDispatchConfigResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.UpdateTaskListDispatchConfig(ctx, request, opts...)
+}
+
+func (c *clientImpl) DescribeTaskListDispatchConfig(
+	ctx context.Context,
+	request *adminservice.DescribeTaskListDispatchConfigRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeTaskListDispatchConfigResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.DescribeTaskListDispatchConfig(ctx, request, opts...)
+}
+
+func (c *clientImpl) DescribeShardOwnership(
+	ctx context.Context,
+	request *adminservice.DescribeShardOwnershipRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeShardOwnershipResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.DescribeShardOwnership(ctx, request, opts...)
+}
+
+func (c *clientImpl) DescribeShardLoad(
+	ctx context.Context,
+	request *adminservice.DescribeShardLoadRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeShardLoadResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.DescribeShardLoad(ctx, request, opts...)
+}
+
 func (c *clientImpl) createContext(parent context.Context) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(parent, c.timeout)
 }
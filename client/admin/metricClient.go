@@ -323,3 +323,207 @@ func (c *metricClient) RefreshWorkflowTasks(
 	}
 	return resp, err
 }
+
+func (c *metricClient) DeleteWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.DeleteWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DeleteWorkflowExecutionResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientDeleteWorkflowExecutionScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientDeleteWorkflowExecutionScope, metrics.ClientLatency)
+	resp, err := c.client.DeleteWorkflowExecution(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientDeleteWorkflowExecutionScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) ListDynamicConfig(
+	ctx context.Context,
+	request *adminservice.ListDynamicConfigRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ListDynamicConfigResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientListDynamicConfigScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientListDynamicConfigScope, metrics.ClientLatency)
+	resp, err := c.client.ListDynamicConfig(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientListDynamicConfigScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) ExportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ExportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ExportWorkflowExecutionResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientExportWorkflowExecutionScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientExportWorkflowExecutionScope, metrics.ClientLatency)
+	resp, err := c.client.ExportWorkflowExecution(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientExportWorkflowExecutionScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) ImportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ImportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ImportWorkflowExecutionResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientImportWorkflowExecutionScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientImportWorkflowExecutionScope, metrics.ClientLatency)
+	resp, err := c.client.ImportWorkflowExecution(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientImportWorkflowExecutionScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) ListTaskListTasks(
+	ctx context.Context,
+	request *adminservice.ListTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ListTaskListTasksResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientListTaskListTasksScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientListTaskListTasksScope, metrics.ClientLatency)
+	resp, err := c.client.ListTaskListTasks(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientListTaskListTasksScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) CountTaskListTasks(
+	ctx context.Context,
+	request *adminservice.CountTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.CountTaskListTasksResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientCountTaskListTasksScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientCountTaskListTasksScope, metrics.ClientLatency)
+	resp, err := c.client.CountTaskListTasks(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientCountTaskListTasksScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) DeleteTaskListTasks(
+	ctx context.Context,
+	request *adminservice.DeleteTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DeleteTaskListTasksResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientDeleteTaskListTasksScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientDeleteTaskListTasksScope, metrics.ClientLatency)
+	resp, err := c.client.DeleteTaskListTasks(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientDeleteTaskListTasksScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) MoveTaskListTasks(
+	ctx context.Context,
+	request *adminservice.MoveTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.MoveTaskListTasksResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientMoveTaskListTasksScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientMoveTaskListTasksScope, metrics.ClientLatency)
+	resp, err := c.client.MoveTaskListTasks(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientMoveTaskListTasksScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) UpdateTaskListDispatchConfig(
+	ctx context.Context,
+	request *adminservice.UpdateTaskListDispatchConfigRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.UpdateTaskListDispatchConfigResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientUpdateTaskListDispatchConfigScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientUpdateTaskListDispatchConfigScope, metrics.ClientLatency)
+	resp, err := c.client.UpdateTaskListDispatchConfig(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientUpdateTaskListDispatchConfigScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) DescribeTaskListDispatchConfig(
+	ctx context.Context,
+	request *adminservice.DescribeTaskListDispatchConfigRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeTaskListDispatchConfigResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientDescribeTaskListDispatchConfigScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientDescribeTaskListDispatchConfigScope, metrics.ClientLatency)
+	resp, err := c.client.DescribeTaskListDispatchConfig(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientDescribeTaskListDispatchConfigScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) DescribeShardOwnership(
+	ctx context.Context,
+	request *adminservice.DescribeShardOwnershipRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeShardOwnershipResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientDescribeShardOwnershipScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientDescribeShardOwnershipScope, metrics.ClientLatency)
+	resp, err := c.client.DescribeShardOwnership(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientDescribeShardOwnershipScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) DescribeShardLoad(
+	ctx context.Context,
+	request *adminservice.DescribeShardLoadRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeShardLoadResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientDescribeShardLoadScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientDescribeShardLoadScope, metrics.ClientLatency)
+	resp, err := c.client.DescribeShardLoad(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientDescribeShardLoadScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
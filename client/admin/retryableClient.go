@@ -297,3 +297,195 @@ func (c *retryableClient) RefreshWorkflowTasks(
 	err := backoff.Retry(op, c.policy, c.isRetryable)
 	return resp, err
 }
+
+func (c *retryableClient) DeleteWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.DeleteWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DeleteWorkflowExecutionResponse, error) {
+
+	var resp *adminservice.DeleteWorkflowExecutionResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DeleteWorkflowExecution(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) ListDynamicConfig(
+	ctx context.Context,
+	request *adminservice.ListDynamicConfigRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ListDynamicConfigResponse, error) {
+
+	var resp *adminservice.ListDynamicConfigResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ListDynamicConfig(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) ExportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ExportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ExportWorkflowExecutionResponse, error) {
+
+	var resp *adminservice.ExportWorkflowExecutionResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ExportWorkflowExecution(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) ImportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ImportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ImportWorkflowExecutionResponse, error) {
+
+	var resp *adminservice.ImportWorkflowExecutionResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ImportWorkflowExecution(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) ListTaskListTasks(
+	ctx context.Context,
+	request *adminservice.ListTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ListTaskListTasksResponse, error) {
+
+	var resp *adminservice.ListTaskListTasksResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ListTaskListTasks(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) CountTaskListTasks(
+	ctx context.Context,
+	request *adminservice.CountTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.CountTaskListTasksResponse, error) {
+
+	var resp *adminservice.CountTaskListTasksResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.CountTaskListTasks(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) DeleteTaskListTasks(
+	ctx context.Context,
+	request *adminservice.DeleteTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DeleteTaskListTasksResponse, error) {
+
+	var resp *adminservice.DeleteTaskListTasksResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DeleteTaskListTasks(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) MoveTaskListTasks(
+	ctx context.Context,
+	request *adminservice.MoveTaskListTasksRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.MoveTaskListTasksResponse, error) {
+
+	var resp *adminservice.MoveTaskListTasksResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.MoveTaskListTasks(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) UpdateTaskListDispatchConfig(
+	ctx context.Context,
+	request *adminservice.UpdateTaskListDispatchConfigRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.UpdateTaskListDispatchConfigResponse, error) {
+
+	var resp *adminservice.UpdateTaskListDispatchConfigResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.UpdateTaskListDispatchConfig(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) DescribeTaskListDispatchConfig(
+	ctx context.Context,
+	request *adminservice.DescribeTaskListDispatchConfigRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeTaskListDispatchConfigResponse, error) {
+
+	var resp *adminservice.DescribeTaskListDispatchConfigResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribeTaskListDispatchConfig(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) DescribeShardOwnership(
+	ctx context.Context,
+	request *adminservice.DescribeShardOwnershipRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeShardOwnershipResponse, error) {
+
+	var resp *adminservice.DescribeShardOwnershipResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribeShardOwnership(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) DescribeShardLoad(
+	ctx context.Context,
+	request *adminservice.DescribeShardLoadRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeShardLoadResponse, error) {
+
+	var resp *adminservice.DescribeShardLoadResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribeShardLoad(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
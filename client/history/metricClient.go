@@ -88,6 +88,15 @@ func (c *metricClient) CloseShard(
 	return resp, err
 }
 
+func (c *metricClient) DescribeShardLoad(
+	context context.Context,
+	request *historyservice.DescribeShardLoadRequest,
+	opts ...grpc.CallOption) (*historyservice.DescribeShardLoadResponse, error) {
+	resp, err := c.client.DescribeShardLoad(context, request, opts...)
+
+	return resp, err
+}
+
 func (c *metricClient) DescribeMutableState(
 	context context.Context,
 	request *historyservice.DescribeMutableStateRequest,
@@ -573,6 +582,23 @@ func (c *metricClient) QueryWorkflow(
 	return resp, err
 }
 
+func (c *metricClient) UpdateWorkflowExecution(
+	context context.Context,
+	request *historyservice.UpdateWorkflowExecutionRequest,
+	opts ...grpc.CallOption) (*historyservice.UpdateWorkflowExecutionResponse, error) {
+	c.metricsClient.IncCounter(metrics.HistoryClientUpdateWorkflowExecutionScope, metrics.ClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.HistoryClientUpdateWorkflowExecutionScope, metrics.ClientLatency)
+	resp, err := c.client.UpdateWorkflowExecution(context, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.HistoryClientUpdateWorkflowExecutionScope, metrics.ClientFailures)
+	}
+
+	return resp, err
+}
+
 func (c *metricClient) ReapplyEvents(
 	context context.Context,
 	request *historyservice.ReapplyEventsRequest,
@@ -656,3 +682,20 @@ func (c *metricClient) RefreshWorkflowTasks(
 	}
 	return resp, err
 }
+
+func (c *metricClient) DeleteWorkflowExecution(
+	ctx context.Context,
+	request *historyservice.DeleteWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.DeleteWorkflowExecutionResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.HistoryClientDeleteWorkflowExecutionScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.HistoryClientDeleteWorkflowExecutionScope, metrics.ClientLatency)
+	resp, err := c.client.DeleteWorkflowExecution(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.HistoryClientDeleteWorkflowExecutionScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
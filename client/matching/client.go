@@ -24,6 +24,9 @@ import (
 	"context"
 	"time"
 
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/workflowservice"
 	"google.golang.org/grpc"
 
 	"github.com/temporalio/temporal/.gen/proto/matchingservice"
@@ -66,6 +69,12 @@ func (c *clientImpl) AddActivityTask(
 	ctx context.Context,
 	request *matchingservice.AddActivityTaskRequest,
 	opts ...grpc.CallOption) (*matchingservice.AddActivityTaskResponse, error) {
+	c.refreshPartitionConfig(
+		request.GetDomainUUID(),
+		*request.GetTaskList(),
+		persistence.TaskListTypeActivity,
+		request.GetForwardedFrom(),
+	)
 	partition := c.loadBalancer.PickWritePartition(
 		request.GetDomainUUID(),
 		*request.GetTaskList(),
@@ -86,6 +95,12 @@ func (c *clientImpl) AddDecisionTask(
 	ctx context.Context,
 	request *matchingservice.AddDecisionTaskRequest,
 	opts ...grpc.CallOption) (*matchingservice.AddDecisionTaskResponse, error) {
+	c.refreshPartitionConfig(
+		request.GetDomainUUID(),
+		*request.GetTaskList(),
+		persistence.TaskListTypeDecision,
+		request.GetForwardedFrom(),
+	)
 	partition := c.loadBalancer.PickWritePartition(
 		request.GetDomainUUID(),
 		*request.GetTaskList(),
@@ -106,6 +121,12 @@ func (c *clientImpl) PollForActivityTask(
 	ctx context.Context,
 	request *matchingservice.PollForActivityTaskRequest,
 	opts ...grpc.CallOption) (*matchingservice.PollForActivityTaskResponse, error) {
+	c.refreshPartitionConfig(
+		request.GetDomainUUID(),
+		*request.PollRequest.GetTaskList(),
+		persistence.TaskListTypeActivity,
+		request.GetForwardedFrom(),
+	)
 	partition := c.loadBalancer.PickReadPartition(
 		request.GetDomainUUID(),
 		*request.PollRequest.GetTaskList(),
@@ -126,6 +147,12 @@ func (c *clientImpl) PollForDecisionTask(
 	ctx context.Context,
 	request *matchingservice.PollForDecisionTaskRequest,
 	opts ...grpc.CallOption) (*matchingservice.PollForDecisionTaskResponse, error) {
+	c.refreshPartitionConfig(
+		request.GetDomainUUID(),
+		*request.PollRequest.GetTaskList(),
+		persistence.TaskListTypeDecision,
+		request.GetForwardedFrom(),
+	)
 	partition := c.loadBalancer.PickReadPartition(
 		request.GetDomainUUID(),
 		*request.PollRequest.GetTaskList(),
@@ -143,6 +170,12 @@ func (c *clientImpl) PollForDecisionTask(
 }
 
 func (c *clientImpl) QueryWorkflow(ctx context.Context, request *matchingservice.QueryWorkflowRequest, opts ...grpc.CallOption) (*matchingservice.QueryWorkflowResponse, error) {
+	c.refreshPartitionConfig(
+		request.GetDomainUUID(),
+		*request.GetTaskList(),
+		persistence.TaskListTypeDecision,
+		request.GetForwardedFrom(),
+	)
 	partition := c.loadBalancer.PickReadPartition(
 		request.GetDomainUUID(),
 		*request.GetTaskList(),
@@ -199,6 +232,72 @@ func (c *clientImpl) ListTaskListPartitions(ctx context.Context, request *matchi
 	return client.ListTaskListPartitions(ctx, request, opts...)
 }
 
+func (c *clientImpl) UpdateTaskListDispatchConfig(ctx context.Context, request *matchingservice.UpdateTaskListDispatchConfigRequest, opts ...grpc.CallOption) (*matchingservice.UpdateTaskListDispatchConfigResponse, error) {
+	client, err := c.getClientForTasklist(request.TaskList.GetName())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.UpdateTaskListDispatchConfig(ctx, request, opts...)
+}
+
+func (c *clientImpl) ListTaskListTasks(ctx context.Context, request *matchingservice.ListTaskListTasksRequest, opts ...grpc.CallOption) (*matchingservice.ListTaskListTasksResponse, error) {
+	client, err := c.getClientForTasklist(request.TaskList.GetName())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.ListTaskListTasks(ctx, request, opts...)
+}
+
+func (c *clientImpl) DeleteTaskListTasks(ctx context.Context, request *matchingservice.DeleteTaskListTasksRequest, opts ...grpc.CallOption) (*matchingservice.DeleteTaskListTasksResponse, error) {
+	client, err := c.getClientForTasklist(request.TaskList.GetName())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.DeleteTaskListTasks(ctx, request, opts...)
+}
+
+// refreshPartitionConfig fetches the partition config of an auto scaled task list
+// from its root partition in the background once the cached config expired
+func (c *clientImpl) refreshPartitionConfig(
+	domainID string,
+	taskList commonproto.TaskList,
+	taskListType int32,
+	forwardedFrom string,
+) {
+
+	if forwardedFrom != "" || !c.loadBalancer.ShouldRefreshPartitionConfig(domainID, taskList, taskListType) {
+		return
+	}
+
+	descTaskListType := enums.TaskListTypeDecision
+	if taskListType == persistence.TaskListTypeActivity {
+		descTaskListType = enums.TaskListTypeActivity
+	}
+	request := &matchingservice.DescribeTaskListRequest{
+		DomainUUID: domainID,
+		DescRequest: &workflowservice.DescribeTaskListRequest{
+			TaskList: &commonproto.TaskList{
+				Name: taskList.GetName(),
+				Kind: enums.TaskListKindNormal,
+			},
+			TaskListType: descTaskListType,
+		},
+	}
+	go func() {
+		resp, err := c.DescribeTaskList(context.Background(), request)
+		if err != nil || resp.GetPartitionConfig() == nil {
+			return
+		}
+		c.loadBalancer.UpdatePartitionConfig(domainID, taskList, taskListType, resp.GetPartitionConfig())
+	}()
+}
+
 func (c *clientImpl) createContext(parent context.Context) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(parent, c.timeout)
 }
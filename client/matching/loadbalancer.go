@@ -24,10 +24,14 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"sync"
+	"time"
 
 	commonproto "go.temporal.io/temporal-proto/common"
 	"go.temporal.io/temporal-proto/enums"
 
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
+	"github.com/temporalio/temporal/common/cache"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
 )
 
@@ -58,17 +62,58 @@ type (
 			taskListType int32,
 			forwardedFrom string,
 		) string
+
+		// ShouldRefreshPartitionConfig returns true when the partition config of the task list
+		// is auto scaled and the cached one has expired. It returns true only once per refresh
+		// interval, the caller is expected to fetch the config from the root partition and
+		// pass it to UpdatePartitionConfig
+		ShouldRefreshPartitionConfig(
+			domainID string,
+			taskList commonproto.TaskList,
+			taskListType int32,
+		) bool
+
+		// UpdatePartitionConfig caches the partition config reported by the root partition
+		// of the task list, it takes precedence over the configured number of partitions
+		UpdatePartitionConfig(
+			domainID string,
+			taskList commonproto.TaskList,
+			taskListType int32,
+			config *matchingservice.TaskListPartitionConfig,
+		)
 	}
 
 	defaultLoadBalancer struct {
 		nReadPartitions  dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 		nWritePartitions dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		enableAutoScale  dynamicconfig.BoolPropertyFnWithTaskListInfoFilters
+		refreshInterval  dynamicconfig.DurationPropertyFn
 		domainIDToName   func(string) (string, error)
+
+		// serializes refreshes of the partition configs, entries are never modified once cached
+		sync.Mutex
+		partitionConfigs cache.Cache // partitionConfigKey -> *partitionConfigEntry
+	}
+
+	partitionConfigKey struct {
+		domainID     string
+		taskList     string
+		taskListType int32
+	}
+
+	partitionConfigEntry struct {
+		config    *matchingservice.TaskListPartitionConfig
+		refreshAt time.Time
 	}
 )
 
 const (
 	taskListPartitionPrefix = "/__temporal_sys/"
+
+	// partition configs of task lists that are no longer used expire after partitionConfigCacheTTL,
+	// the TTL of a config restarts on every refresh
+	partitionConfigCacheTTL     = 10 * time.Minute
+	partitionConfigCacheMaxSize = 10000
 )
 
 // NewLoadBalancer returns an instance of matching load balancer that
@@ -81,6 +126,9 @@ func NewLoadBalancer(
 		domainIDToName:   domainIDToName,
 		nReadPartitions:  dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingNumTasklistReadPartitions, 1),
 		nWritePartitions: dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingNumTasklistWritePartitions, 1),
+		enableAutoScale:  dc.GetBoolPropertyFilteredByTaskListInfo(dynamicconfig.MatchingEnablePartitionAutoScaling, false),
+		refreshInterval:  dc.GetDurationProperty(dynamicconfig.MatchingPartitionConfigRefreshInterval, 30*time.Second),
+		partitionConfigs: cache.New(partitionConfigCacheMaxSize, &cache.Options{TTL: partitionConfigCacheTTL}),
 	}
 }
 
@@ -90,7 +138,8 @@ func (lb *defaultLoadBalancer) PickWritePartition(
 	taskListType int32,
 	forwardedFrom string,
 ) string {
-	return lb.pickPartition(domainID, taskList, taskListType, forwardedFrom, lb.nWritePartitions)
+	return lb.pickPartition(domainID, taskList, taskListType, forwardedFrom, lb.nWritePartitions,
+		(*matchingservice.TaskListPartitionConfig).GetNumWritePartitions)
 }
 
 func (lb *defaultLoadBalancer) PickReadPartition(
@@ -99,7 +148,87 @@ func (lb *defaultLoadBalancer) PickReadPartition(
 	taskListType int32,
 	forwardedFrom string,
 ) string {
-	return lb.pickPartition(domainID, taskList, taskListType, forwardedFrom, lb.nReadPartitions)
+	return lb.pickPartition(domainID, taskList, taskListType, forwardedFrom, lb.nReadPartitions,
+		(*matchingservice.TaskListPartitionConfig).GetNumReadPartitions)
+}
+
+func (lb *defaultLoadBalancer) ShouldRefreshPartitionConfig(
+	domainID string,
+	taskList commonproto.TaskList,
+	taskListType int32,
+) bool {
+
+	if !lb.isAutoScaled(domainID, taskList, taskListType) {
+		return false
+	}
+
+	key := partitionConfigKey{domainID: domainID, taskList: taskList.GetName(), taskListType: taskListType}
+	now := time.Now()
+	lb.Lock()
+	defer lb.Unlock()
+	refreshed := &partitionConfigEntry{}
+	if entry, ok := lb.partitionConfigs.Get(key).(*partitionConfigEntry); ok {
+		if now.Before(entry.refreshAt) {
+			return false
+		}
+		refreshed.config = entry.config
+	}
+	// the config stays in use until it is refreshed, refresh
+	// at most once per interval even if fetching it fails
+	refreshed.refreshAt = now.Add(lb.refreshInterval())
+	lb.partitionConfigs.Put(key, refreshed)
+	return true
+}
+
+func (lb *defaultLoadBalancer) UpdatePartitionConfig(
+	domainID string,
+	taskList commonproto.TaskList,
+	taskListType int32,
+	config *matchingservice.TaskListPartitionConfig,
+) {
+
+	key := partitionConfigKey{domainID: domainID, taskList: taskList.GetName(), taskListType: taskListType}
+	lb.Lock()
+	defer lb.Unlock()
+	if entry, ok := lb.partitionConfigs.Get(key).(*partitionConfigEntry); ok {
+		lb.partitionConfigs.Put(key, &partitionConfigEntry{config: config, refreshAt: entry.refreshAt})
+	}
+}
+
+func (lb *defaultLoadBalancer) isAutoScaled(
+	domainID string,
+	taskList commonproto.TaskList,
+	taskListType int32,
+) bool {
+
+	if taskList.GetKind() == enums.TaskListKindSticky || strings.HasPrefix(taskList.GetName(), taskListPartitionPrefix) {
+		return false
+	}
+	domainName, err := lb.domainIDToName(domainID)
+	if err != nil {
+		return false
+	}
+	return lb.enableAutoScale(domainName, taskList.GetName(), taskListType)
+}
+
+// scaledPartitions returns the number of partitions decided by the root partition
+// of the task list, or zero when the task list is not auto scaled
+func (lb *defaultLoadBalancer) scaledPartitions(
+	domainID string,
+	domainName string,
+	taskList commonproto.TaskList,
+	taskListType int32,
+	nPartitions func(*matchingservice.TaskListPartitionConfig) int32,
+) int {
+
+	if !lb.enableAutoScale(domainName, taskList.GetName(), taskListType) {
+		return 0
+	}
+	key := partitionConfigKey{domainID: domainID, taskList: taskList.GetName(), taskListType: taskListType}
+	if entry, ok := lb.partitionConfigs.Get(key).(*partitionConfigEntry); ok && entry.config != nil {
+		return int(nPartitions(entry.config))
+	}
+	return 0
 }
 
 func (lb *defaultLoadBalancer) pickPartition(
@@ -108,6 +237,7 @@ func (lb *defaultLoadBalancer) pickPartition(
 	taskListType int32,
 	forwardedFrom string,
 	nPartitions dynamicconfig.IntPropertyFnWithTaskListInfoFilters,
+	nScaledPartitions func(*matchingservice.TaskListPartitionConfig) int32,
 ) string {
 
 	if forwardedFrom != "" || taskList.GetKind() == enums.TaskListKindSticky {
@@ -124,7 +254,10 @@ func (lb *defaultLoadBalancer) pickPartition(
 		return taskList.GetName()
 	}
 
-	n := nPartitions(domainName, taskList.GetName(), taskListType)
+	n := lb.scaledPartitions(domainID, domainName, taskList, taskListType, nScaledPartitions)
+	if n <= 0 {
+		n = nPartitions(domainName, taskList.GetName(), taskListType)
+	}
 	if n <= 0 {
 		return taskList.GetName()
 	}
@@ -242,6 +242,60 @@ func (c *metricClient) ListTaskListPartitions(
 	return resp, err
 }
 
+func (c *metricClient) UpdateTaskListDispatchConfig(
+	ctx context.Context,
+	request *matchingservice.UpdateTaskListDispatchConfigRequest,
+	opts ...grpc.CallOption) (*matchingservice.UpdateTaskListDispatchConfigResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.MatchingClientUpdateTaskListDispatchConfigScope, metrics.ClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientUpdateTaskListDispatchConfigScope, metrics.ClientLatency)
+	resp, err := c.client.UpdateTaskListDispatchConfig(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientUpdateTaskListDispatchConfigScope, metrics.ClientFailures)
+	}
+
+	return resp, err
+}
+
+func (c *metricClient) ListTaskListTasks(
+	ctx context.Context,
+	request *matchingservice.ListTaskListTasksRequest,
+	opts ...grpc.CallOption) (*matchingservice.ListTaskListTasksResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.MatchingClientListTaskListTasksScope, metrics.ClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientListTaskListTasksScope, metrics.ClientLatency)
+	resp, err := c.client.ListTaskListTasks(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientListTaskListTasksScope, metrics.ClientFailures)
+	}
+
+	return resp, err
+}
+
+func (c *metricClient) DeleteTaskListTasks(
+	ctx context.Context,
+	request *matchingservice.DeleteTaskListTasksRequest,
+	opts ...grpc.CallOption) (*matchingservice.DeleteTaskListTasksResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.MatchingClientDeleteTaskListTasksScope, metrics.ClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientDeleteTaskListTasksScope, metrics.ClientLatency)
+	resp, err := c.client.DeleteTaskListTasks(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientDeleteTaskListTasksScope, metrics.ClientFailures)
+	}
+
+	return resp, err
+}
+
 func (c *metricClient) emitForwardedFromStats(scope int, forwardedFrom string, taskList *commonproto.TaskList) {
 	if taskList == nil {
 		return
@@ -189,3 +189,51 @@ func (c *retryableClient) ListTaskListPartitions(
 	err := backoff.Retry(op, c.policy, c.isRetryable)
 	return resp, err
 }
+
+func (c *retryableClient) UpdateTaskListDispatchConfig(
+	ctx context.Context,
+	request *matchingservice.UpdateTaskListDispatchConfigRequest,
+	opts ...grpc.CallOption) (*matchingservice.UpdateTaskListDispatchConfigResponse, error) {
+
+	var resp *matchingservice.UpdateTaskListDispatchConfigResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.UpdateTaskListDispatchConfig(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) ListTaskListTasks(
+	ctx context.Context,
+	request *matchingservice.ListTaskListTasksRequest,
+	opts ...grpc.CallOption) (*matchingservice.ListTaskListTasksResponse, error) {
+
+	var resp *matchingservice.ListTaskListTasksResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ListTaskListTasks(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) DeleteTaskListTasks(
+	ctx context.Context,
+	request *matchingservice.DeleteTaskListTasksRequest,
+	opts ...grpc.CallOption) (*matchingservice.DeleteTaskListTasksResponse, error) {
+
+	var resp *matchingservice.DeleteTaskListTasksResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DeleteTaskListTasks(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
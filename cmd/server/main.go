@@ -26,6 +26,7 @@ import (
 	"github.com/temporalio/temporal/cmd/server/temporal"
 	_ "github.com/temporalio/temporal/common/persistence/sql/sqlplugin/mysql"    // needed to load mysql plugin
 	_ "github.com/temporalio/temporal/common/persistence/sql/sqlplugin/postgres" // needed to load postgres plugin
+	_ "github.com/temporalio/temporal/common/persistence/sql/sqlplugin/sqlite"   // needed to load sqlite plugin
 )
 
 // main entry point for the cadence server
@@ -218,10 +218,25 @@ func (s *server) startService() common.Daemon {
 	)
 
 	params.ArchiverProvider = provider.NewArchiverProvider(s.cfg.Archival.History.Provider, s.cfg.Archival.Visibility.Provider)
+	params.HistoryArchivalEnvelope, err = archiver.NewBlobEnvelopeFromConfig(s.cfg.Archival.History.Envelope)
+	if err != nil {
+		log.Fatalf("error creating history archival envelope: %v", err)
+	}
+	params.VisibilityArchivalEnvelope, err = archiver.NewBlobEnvelopeFromConfig(s.cfg.Archival.Visibility.Envelope)
+	if err != nil {
+		log.Fatalf("error creating visibility archival envelope: %v", err)
+	}
 
 	params.PersistenceConfig.TransactionSizeLimit = dc.GetIntProperty(dynamicconfig.TransactionSizeLimit, common.DefaultTransactionSizeLimit)
 
-	params.Authorizer = authorization.NewNopAuthorizer()
+	params.Authorizer, err = authorization.NewAuthorizer(&s.cfg.Authorization)
+	if err != nil {
+		log.Fatalf("error creating authorizer: %v", err)
+	}
+	params.ClaimMapper, err = authorization.NewClaimMapper(&s.cfg.Authorization.ClaimMapper)
+	if err != nil {
+		log.Fatalf("error creating claim mapper: %v", err)
+	}
 
 	params.Logger.Info("Starting service " + s.name)
 
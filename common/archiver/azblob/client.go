@@ -0,0 +1,214 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azblob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	blob "github.com/Azure/azure-storage-blob-go/azblob"
+
+	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/service/config"
+)
+
+const (
+	defaultEndpointTemplate = "https://%s.blob.core.windows.net"
+	defaultBlobstoreTimeout = 60 * time.Second
+)
+
+var (
+	// ErrContainerNotFound is non retriable error that is returned when the container doesn't exist
+	ErrContainerNotFound = errors.New("container not found")
+	// ErrBlobNotFound is returned when the blob doesn't exist
+	ErrBlobNotFound = errors.New("blob not found")
+
+	errEmptyAccountName = errors.New("empty azure storage account name")
+)
+
+type (
+	// blobClient reads and writes the blobs under the container and path of an archival URI
+	blobClient interface {
+		Upload(ctx context.Context, URI archiver.URI, blobName string, blob []byte) error
+		Get(ctx context.Context, URI archiver.URI, blobName string) ([]byte, error)
+		// Exist checks if the container exists when blobName is empty and otherwise if the blob exists
+		Exist(ctx context.Context, URI archiver.URI, blobName string) (bool, error)
+		// Query returns the names of all blobs which start with the prefix, names are relative to the URI path
+		Query(ctx context.Context, URI archiver.URI, blobNamePrefix string) ([]string, error)
+	}
+
+	// storageClient is a blobClient backed by the azure storage blob SDK and authorized with the
+	// storage account key
+	storageClient struct {
+		serviceURL blob.ServiceURL
+	}
+)
+
+func newStorageClient(config *config.AzblobArchiver) (*storageClient, error) {
+	if len(config.AccountName) == 0 {
+		return nil, errEmptyAccountName
+	}
+	credential, err := blob.NewSharedKeyCredential(config.AccountName, config.AccountKey)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := config.Endpoint
+	if len(endpoint) == 0 {
+		endpoint = fmt.Sprintf(defaultEndpointTemplate, config.AccountName)
+	}
+	endpointURL, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	// requests are tried only once, the archivers retry transient errors with their own policy
+	pipeline := blob.NewPipeline(credential, blob.PipelineOptions{
+		Retry: blob.RetryOptions{MaxTries: 1, TryTimeout: defaultBlobstoreTimeout},
+	})
+	return &storageClient{
+		serviceURL: blob.NewServiceURL(*endpointURL, pipeline),
+	}, nil
+}
+
+// Upload writes the blob as a block blob, existing blobs are overwritten
+func (c *storageClient) Upload(ctx context.Context, URI archiver.URI, blobName string, data []byte) error {
+	ctx, cancel := ensureContextTimeout(ctx)
+	defer cancel()
+	blobURL := c.containerURL(URI).NewBlockBlobURL(formatBlobPath(URI.Path(), blobName))
+	_, err := blob.UploadBufferToBlockBlob(ctx, data, blobURL, blob.UploadToBlockBlobOptions{})
+	return convertStorageError(err)
+}
+
+func (c *storageClient) Get(ctx context.Context, URI archiver.URI, blobName string) ([]byte, error) {
+	ctx, cancel := ensureContextTimeout(ctx)
+	defer cancel()
+	blobURL := c.containerURL(URI).NewBlobURL(formatBlobPath(URI.Path(), blobName))
+	resp, err := blobURL.Download(ctx, 0, blob.CountToEnd, blob.BlobAccessConditions{}, false)
+	if err != nil {
+		return nil, convertStorageError(err)
+	}
+	body := resp.Body(blob.RetryReaderOptions{})
+	defer body.Close()
+	buffer := &bytes.Buffer{}
+	if _, err := buffer.ReadFrom(body); err != nil {
+		return nil, convertStorageError(err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func (c *storageClient) Exist(ctx context.Context, URI archiver.URI, blobName string) (bool, error) {
+	ctx, cancel := ensureContextTimeout(ctx)
+	defer cancel()
+	containerURL := c.containerURL(URI)
+	if _, err := containerURL.GetProperties(ctx, blob.LeaseAccessConditions{}); err != nil {
+		return false, convertStorageError(err)
+	}
+	if blobName == "" {
+		return true, nil
+	}
+
+	blobURL := containerURL.NewBlobURL(formatBlobPath(URI.Path(), blobName))
+	_, err := blobURL.GetProperties(ctx, blob.BlobAccessConditions{})
+	switch err := convertStorageError(err); err {
+	case nil:
+		return true, nil
+	case ErrBlobNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (c *storageClient) Query(ctx context.Context, URI archiver.URI, blobNamePrefix string) ([]string, error) {
+	ctx, cancel := ensureContextTimeout(ctx)
+	defer cancel()
+	containerURL := c.containerURL(URI)
+	pathPrefix := formatBlobPath(URI.Path(), "")
+	var blobNames []string
+	for marker := (blob.Marker{}); marker.NotDone(); {
+		resp, err := containerURL.ListBlobsFlatSegment(ctx, marker, blob.ListBlobsSegmentOptions{
+			Prefix: pathPrefix + blobNamePrefix,
+		})
+		if err != nil {
+			return nil, convertStorageError(err)
+		}
+		for _, item := range resp.Segment.BlobItems {
+			blobNames = append(blobNames, strings.TrimPrefix(item.Name, pathPrefix))
+		}
+		marker = resp.NextMarker
+	}
+	return blobNames, nil
+}
+
+// createContainer creates the container of the URI, it is a no-op when the container exists
+func (c *storageClient) createContainer(ctx context.Context, URI archiver.URI) error {
+	ctx, cancel := ensureContextTimeout(ctx)
+	defer cancel()
+	_, err := c.containerURL(URI).Create(ctx, blob.Metadata{}, blob.PublicAccessNone)
+	if storageErr, ok := err.(blob.StorageError); ok && storageErr.ServiceCode() == blob.ServiceCodeContainerAlreadyExists {
+		return nil
+	}
+	return convertStorageError(err)
+}
+
+func (c *storageClient) containerURL(URI archiver.URI) blob.ContainerURL {
+	return c.serviceURL.NewContainerURL(URI.Hostname())
+}
+
+// convertStorageError maps the not found errors of the blob service to ErrContainerNotFound and
+// ErrBlobNotFound, other errors are returned as is
+func convertStorageError(err error) error {
+	storageErr, ok := err.(blob.StorageError)
+	if !ok {
+		return err
+	}
+	switch storageErr.ServiceCode() {
+	case blob.ServiceCodeContainerNotFound:
+		return ErrContainerNotFound
+	case blob.ServiceCodeBlobNotFound:
+		return ErrBlobNotFound
+	}
+	// responses to HEAD requests have no body and may carry no error code
+	if resp := storageErr.Response(); resp != nil && resp.StatusCode == http.StatusNotFound {
+		return ErrBlobNotFound
+	}
+	return err
+}
+
+func formatBlobPath(path string, blobName string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return blobName
+	}
+	return path + "/" + blobName
+}
+
+func ensureContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, defaultBlobstoreTimeout)
+}
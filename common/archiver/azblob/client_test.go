@@ -0,0 +1,297 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azblob
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	blob "github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/service/config"
+)
+
+const (
+	testAccountName = "devstoreaccount1"
+	// well known key of the azure storage emulators
+	testAccountKey = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+	testContainer  = "test-container"
+)
+
+type (
+	clientSuite struct {
+		*require.Assertions
+		suite.Suite
+
+		service *fakeBlobService
+		server  *httptest.Server
+		client  *storageClient
+	}
+
+	// fakeBlobService is a minimal in memory blob service which serves the requests of the SDK
+	fakeBlobService struct {
+		sync.Mutex
+
+		accountName string
+		listPage    int
+		containers  map[string]map[string][]byte
+		// failWith is the status code returned for all requests when it is set
+		failWith int
+	}
+
+	fakeListBlobsResult struct {
+		XMLName    xml.Name            `xml:"EnumerationResults"`
+		Blobs      []fakeListBlobsItem `xml:"Blobs>Blob"`
+		NextMarker string              `xml:"NextMarker"`
+	}
+
+	fakeListBlobsItem struct {
+		Name string `xml:"Name"`
+	}
+)
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(clientSuite))
+}
+
+func (s *clientSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.service, s.server, s.client = newTestBlobService(s.T())
+}
+
+func (s *clientSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *clientSuite) TestNewStorageClient() {
+	_, err := newStorageClient(&config.AzblobArchiver{AccountKey: testAccountKey})
+	s.Equal(errEmptyAccountName, err)
+
+	_, err = newStorageClient(&config.AzblobArchiver{AccountName: testAccountName, AccountKey: "not base64"})
+	s.Error(err)
+
+	client, err := newStorageClient(&config.AzblobArchiver{AccountName: "account", AccountKey: testAccountKey})
+	s.NoError(err)
+	serviceURL := client.serviceURL.URL()
+	s.Equal("https://account.blob.core.windows.net", serviceURL.String())
+}
+
+func (s *clientSuite) TestContainerNotFound() {
+	URI := s.newURI("/archival")
+	exists, err := s.client.Exist(context.Background(), URI, "")
+	s.Equal(ErrContainerNotFound, err)
+	s.False(exists)
+
+	s.Equal(ErrContainerNotFound, s.client.Upload(context.Background(), URI, "blob", []byte("data")))
+	_, err = s.client.Get(context.Background(), URI, "blob")
+	s.Equal(ErrContainerNotFound, err)
+	_, err = s.client.Query(context.Background(), URI, "")
+	s.Equal(ErrContainerNotFound, err)
+}
+
+func (s *clientSuite) TestUploadGetExistQuery() {
+	URI := s.newURI("/archival/development")
+	s.NoError(s.client.createContainer(context.Background(), URI))
+	s.NoError(s.client.createContainer(context.Background(), URI))
+
+	exists, err := s.client.Exist(context.Background(), URI, "")
+	s.NoError(err)
+	s.True(exists)
+	exists, err = s.client.Exist(context.Background(), URI, "domain/history/1")
+	s.NoError(err)
+	s.False(exists)
+	_, err = s.client.Get(context.Background(), URI, "domain/history/1")
+	s.Equal(ErrBlobNotFound, err)
+
+	var expectedNames []string
+	for i := 0; i != 5; i++ {
+		name := fmt.Sprintf("domain/history/%v", i)
+		s.NoError(s.client.Upload(context.Background(), URI, name, []byte(name)))
+		expectedNames = append(expectedNames, name)
+	}
+	s.NoError(s.client.Upload(context.Background(), URI, "domain/visibility/0", []byte("visibility")))
+	s.NoError(s.client.Upload(context.Background(), s.newURI("/other"), "domain/history/0", []byte("other")))
+
+	exists, err = s.client.Exist(context.Background(), URI, "domain/history/1")
+	s.NoError(err)
+	s.True(exists)
+	blob, err := s.client.Get(context.Background(), URI, "domain/history/1")
+	s.NoError(err)
+	s.Equal([]byte("domain/history/1"), blob)
+
+	names, err := s.client.Query(context.Background(), URI, "domain/history/")
+	s.NoError(err)
+	s.Equal(expectedNames, names)
+
+	names, err = s.client.Query(context.Background(), s.newURI(""), "other/")
+	s.NoError(err)
+	s.Equal([]string{"other/domain/history/0"}, names)
+}
+
+func (s *clientSuite) TestIsRetryableError() {
+	URI := s.newURI("")
+	s.NoError(s.client.createContainer(context.Background(), URI))
+
+	for statusCode, retryable := range map[int]bool{
+		http.StatusInternalServerError: true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusTooManyRequests:     true,
+		http.StatusNotImplemented:      false,
+		http.StatusForbidden:           false,
+	} {
+		s.service.failWith = statusCode
+		_, err := s.client.Exist(context.Background(), URI, "")
+		s.Error(err)
+		s.Equal(retryable, isRetryableError(err), "status code %v", statusCode)
+	}
+	s.False(isRetryableError(ErrContainerNotFound))
+
+	s.server.Close()
+	_, err := s.client.Exist(context.Background(), URI, "")
+	s.True(isRetryableError(err))
+}
+
+func (s *clientSuite) newURI(path string) archiver.URI {
+	URI, err := archiver.NewURI(URIScheme + "://" + testContainer + path)
+	s.NoError(err)
+	return URI
+}
+
+// newTestBlobService starts an in memory blob service and returns a client which talks to it
+func newTestBlobService(t *testing.T) (*fakeBlobService, *httptest.Server, *storageClient) {
+	service := &fakeBlobService{
+		accountName: testAccountName,
+		listPage:    2,
+		containers:  make(map[string]map[string][]byte),
+	}
+	server := httptest.NewServer(service)
+	client, err := newStorageClient(&config.AzblobArchiver{
+		AccountName: testAccountName,
+		AccountKey:  testAccountKey,
+		Endpoint:    server.URL + "/" + testAccountName,
+	})
+	require.NoError(t, err)
+	return service, server, client
+}
+
+func (f *fakeBlobService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	if !f.authorized(r) {
+		f.writeError(w, http.StatusForbidden, "AuthenticationFailed")
+		return
+	}
+	if f.failWith != 0 {
+		f.writeError(w, f.failWith, "")
+		return
+	}
+
+	pieces := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"+f.accountName+"/"), "/", 2)
+	container, containerExists := f.containers[pieces[0]]
+	if len(pieces) == 1 {
+		query := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPut && !containerExists:
+			f.containers[pieces[0]] = make(map[string][]byte)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut:
+			f.writeError(w, http.StatusConflict, "ContainerAlreadyExists")
+		case !containerExists:
+			f.writeError(w, http.StatusNotFound, string(blob.ServiceCodeContainerNotFound))
+		case r.Method == http.MethodGet && query.Get("comp") == "list":
+			f.list(w, container, query.Get("prefix"), query.Get("marker"))
+		case r.Method == http.MethodGet || r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusOK)
+		default:
+			f.writeError(w, http.StatusBadRequest, "UnsupportedHttpVerb")
+		}
+		return
+	}
+
+	if !containerExists {
+		f.writeError(w, http.StatusNotFound, string(blob.ServiceCodeContainerNotFound))
+		return
+	}
+	data, blobExists := container[pieces[1]]
+	switch {
+	case r.Method == http.MethodPut && r.Header.Get("x-ms-blob-type") == "BlockBlob":
+		body, _ := ioutil.ReadAll(r.Body)
+		container[pieces[1]] = body
+		w.WriteHeader(http.StatusCreated)
+	case !blobExists:
+		f.writeError(w, http.StatusNotFound, string(blob.ServiceCodeBlobNotFound))
+	case r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet:
+		w.Write(data)
+	default:
+		f.writeError(w, http.StatusBadRequest, "UnsupportedHttpVerb")
+	}
+}
+
+// authorized only checks that the request is signed with the shared key of the account, the
+// signature itself is computed by the SDK
+func (f *fakeBlobService) authorized(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey "+f.accountName+":") &&
+		r.Header.Get("x-ms-version") != ""
+}
+
+func (f *fakeBlobService) list(w http.ResponseWriter, container map[string][]byte, prefix string, marker string) {
+	var names []string
+	for name := range container {
+		if strings.HasPrefix(name, prefix) && name > marker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	result := &fakeListBlobsResult{}
+	if len(names) > f.listPage {
+		names = names[:f.listPage]
+		result.NextMarker = names[len(names)-1]
+	}
+	for _, name := range names {
+		result.Blobs = append(result.Blobs, fakeListBlobsItem{Name: name})
+	}
+	data, _ := xml.Marshal(result)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
+
+func (f *fakeBlobService) writeError(w http.ResponseWriter, statusCode int, errorCode string) {
+	if errorCode != "" {
+		w.Header().Set("x-ms-error-code", errorCode)
+	}
+	w.WriteHeader(statusCode)
+}
@@ -0,0 +1,465 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azblob
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/serviceerror"
+	"go.uber.org/zap"
+
+	archiverproto "github.com/temporalio/temporal/.gen/proto/archiver"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/codec"
+	"github.com/temporalio/temporal/common/log/loggerimpl"
+	"github.com/temporalio/temporal/common/metrics"
+)
+
+const (
+	testDomainID             = "test-domain-id"
+	testDomainName           = "test-domain-name"
+	testWorkflowID           = "test-workflow-id"
+	testRunID                = "test-run-id"
+	testNextEventID          = 1800
+	testCloseFailoverVersion = int64(100)
+	testPageSize             = 100
+	testContainerURI         = URIScheme + "://" + testContainer + "/archival"
+)
+
+var (
+	testBranchToken = []byte{1, 2, 3}
+)
+
+type historyArchiverSuite struct {
+	*require.Assertions
+	suite.Suite
+
+	server             *httptest.Server
+	client             *storageClient
+	container          *archiver.HistoryBootstrapContainer
+	testArchivalURI    archiver.URI
+	historyBatchesV1   []*archiverproto.HistoryBlob
+	historyBatchesV100 []*archiverproto.HistoryBlob
+}
+
+func TestHistoryArchiverSuite(t *testing.T) {
+	suite.Run(t, new(historyArchiverSuite))
+}
+
+func (s *historyArchiverSuite) SetupSuite() {
+	var err error
+	_, s.server, s.client = newTestBlobService(s.T())
+	s.testArchivalURI, err = archiver.NewURI(testContainerURI)
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.createContainer(context.Background(), s.testArchivalURI))
+	s.setupHistoryDirectory()
+}
+
+func (s *historyArchiverSuite) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *historyArchiverSuite) SetupTest() {
+	scope := tally.NewTestScope("test", nil)
+	s.Assertions = require.New(s.T())
+	zapLogger := zap.NewNop()
+	s.container = &archiver.HistoryBootstrapContainer{
+		Logger:        loggerimpl.NewLogger(zapLogger),
+		MetricsClient: metrics.NewClient(scope, metrics.HistoryArchiverScope),
+	}
+}
+
+func (s *historyArchiverSuite) TestValidateURI() {
+	testCases := []struct {
+		URI         string
+		expectedErr error
+	}{
+		{
+			URI:         "wrongscheme:///a/b/c",
+			expectedErr: archiver.ErrURISchemeMismatch,
+		},
+		{
+			URI:         "azblob://",
+			expectedErr: errNoContainerSpecified,
+		},
+		{
+			URI:         "azblob://container/a/b/c",
+			expectedErr: ErrContainerNotFound,
+		},
+		{
+			URI:         testContainerURI,
+			expectedErr: nil,
+		},
+	}
+
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	for _, tc := range testCases {
+		URI, err := archiver.NewURI(tc.URI)
+		s.NoError(err)
+		s.Equal(tc.expectedErr, historyArchiver.ValidateURI(URI))
+	}
+}
+
+func (s *historyArchiverSuite) TestArchive_Fail_InvalidURI() {
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	URI, err := archiver.NewURI("wrongscheme://")
+	s.NoError(err)
+	err = historyArchiver.Archive(context.Background(), URI, s.newArchiveRequest())
+	s.Error(err)
+}
+
+func (s *historyArchiverSuite) TestArchive_Fail_InvalidRequest() {
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	request := s.newArchiveRequest()
+	request.WorkflowID = "" // an invalid request
+	err := historyArchiver.Archive(context.Background(), s.testArchivalURI, request)
+	s.Error(err)
+}
+
+func (s *historyArchiverSuite) TestArchive_Fail_ErrorOnReadHistory() {
+	mockCtrl := gomock.NewController(s.T())
+	defer mockCtrl.Finish()
+	historyIterator := archiver.NewMockHistoryIterator(mockCtrl)
+	gomock.InOrder(
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next().Return(nil, errors.New("some random error")),
+	)
+
+	historyArchiver := s.newTestHistoryArchiver(historyIterator)
+	err := historyArchiver.Archive(context.Background(), s.testArchivalURI, s.newArchiveRequest())
+	s.Error(err)
+}
+
+func (s *historyArchiverSuite) TestArchive_Fail_HistoryMutated() {
+	mockCtrl := gomock.NewController(s.T())
+	defer mockCtrl.Finish()
+	historyIterator := archiver.NewMockHistoryIterator(mockCtrl)
+	historyBlob := &archiverproto.HistoryBlob{
+		Header: &archiverproto.HistoryBlobHeader{
+			IsLast: true,
+		},
+		Body: []*commonproto.History{
+			{
+				Events: []*commonproto.HistoryEvent{
+					{
+						EventId:   common.FirstEventID + 1,
+						Timestamp: time.Now().UnixNano(),
+						Version:   testCloseFailoverVersion + 1,
+					},
+				},
+			},
+		},
+	}
+	gomock.InOrder(
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next().Return(historyBlob, nil),
+	)
+
+	historyArchiver := s.newTestHistoryArchiver(historyIterator)
+	err := historyArchiver.Archive(context.Background(), s.testArchivalURI, s.newArchiveRequest())
+	s.Equal(archiver.ErrHistoryMutated, err)
+}
+
+func (s *historyArchiverSuite) TestArchive_Fail_NonRetriableErrorOption() {
+	mockCtrl := gomock.NewController(s.T())
+	defer mockCtrl.Finish()
+	historyIterator := archiver.NewMockHistoryIterator(mockCtrl)
+	gomock.InOrder(
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next().Return(nil, errors.New("some random error")),
+	)
+
+	historyArchiver := s.newTestHistoryArchiver(historyIterator)
+	nonRetryableErr := errors.New("some non-retryable error")
+	err := historyArchiver.Archive(context.Background(), s.testArchivalURI, s.newArchiveRequest(), archiver.GetNonRetriableErrorOption(nonRetryableErr))
+	s.Equal(nonRetryableErr, err)
+}
+
+func (s *historyArchiverSuite) TestArchive_Fail_ContainerNotFound() {
+	mockCtrl := gomock.NewController(s.T())
+	defer mockCtrl.Finish()
+	historyIterator := archiver.NewMockHistoryIterator(mockCtrl)
+	gomock.InOrder(
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next().Return(s.historyBatchesV1[0], nil),
+	)
+
+	historyArchiver := s.newTestHistoryArchiver(historyIterator)
+	request := s.newArchiveRequest()
+	request.CloseFailoverVersion = 1
+	URI, err := archiver.NewURI("azblob://container-not-exist")
+	s.NoError(err)
+	err = historyArchiver.Archive(context.Background(), URI, request)
+	s.Equal(ErrContainerNotFound, err)
+	s.False(isRetryableError(err))
+}
+
+func (s *historyArchiverSuite) TestGet_Fail_InvalidURI() {
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	URI, err := archiver.NewURI("wrongscheme://")
+	s.NoError(err)
+	response, err := historyArchiver.Get(context.Background(), URI, s.newGetRequest())
+	s.Nil(response)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+}
+
+func (s *historyArchiverSuite) TestGet_Fail_InvalidRequest() {
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	request := s.newGetRequest()
+	request.PageSize = 0 // pageSize should be greater than 0
+	response, err := historyArchiver.Get(context.Background(), s.testArchivalURI, request)
+	s.Nil(response)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+}
+
+func (s *historyArchiverSuite) TestGet_Fail_InvalidToken() {
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	request := s.newGetRequest()
+	request.NextPageToken = []byte{'r', 'a', 'n', 'd', 'o', 'm'}
+	response, err := historyArchiver.Get(context.Background(), s.testArchivalURI, request)
+	s.Nil(response)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+}
+
+func (s *historyArchiverSuite) TestGet_Fail_BlobNotExist() {
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	request := s.newGetRequest()
+	closeFailoverVersion := int64(-1)
+	request.CloseFailoverVersion = &closeFailoverVersion
+	response, err := historyArchiver.Get(context.Background(), s.testArchivalURI, request)
+	s.Nil(response)
+	s.IsType(&serviceerror.NotFound{}, err)
+
+	request = s.newGetRequest()
+	request.RunID = "run-id-not-exist"
+	response, err = historyArchiver.Get(context.Background(), s.testArchivalURI, request)
+	s.Nil(response)
+	s.IsType(&serviceerror.NotFound{}, err)
+}
+
+func (s *historyArchiverSuite) TestGet_Success_PickHighestVersion() {
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	response, err := historyArchiver.Get(context.Background(), s.testArchivalURI, s.newGetRequest())
+	s.NoError(err)
+	s.Nil(response.NextPageToken)
+	s.Equal(append(s.historyBatchesV100[0].Body, s.historyBatchesV100[1].Body...), response.HistoryBatches)
+}
+
+func (s *historyArchiverSuite) TestGet_Success_UseProvidedVersion() {
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	request := s.newGetRequest()
+	closeFailoverVersion := int64(1)
+	request.CloseFailoverVersion = &closeFailoverVersion
+	response, err := historyArchiver.Get(context.Background(), s.testArchivalURI, request)
+	s.NoError(err)
+	s.Nil(response.NextPageToken)
+	s.Equal(s.historyBatchesV1[0].Body, response.HistoryBatches)
+}
+
+func (s *historyArchiverSuite) TestGet_Success_SmallPageSize() {
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	request := s.newGetRequest()
+	request.PageSize = 1
+	var combinedHistory []*commonproto.History
+
+	response, err := historyArchiver.Get(context.Background(), s.testArchivalURI, request)
+	s.NoError(err)
+	s.NotNil(response.NextPageToken)
+	s.Len(response.HistoryBatches, 1)
+	combinedHistory = append(combinedHistory, response.HistoryBatches...)
+
+	request.NextPageToken = response.NextPageToken
+	response, err = historyArchiver.Get(context.Background(), s.testArchivalURI, request)
+	s.NoError(err)
+	s.Nil(response.NextPageToken)
+	s.Len(response.HistoryBatches, 1)
+	combinedHistory = append(combinedHistory, response.HistoryBatches...)
+
+	s.Equal(append(s.historyBatchesV100[0].Body, s.historyBatchesV100[1].Body...), combinedHistory)
+}
+
+func (s *historyArchiverSuite) TestArchiveAndGet() {
+	mockCtrl := gomock.NewController(s.T())
+	defer mockCtrl.Finish()
+	historyIterator := archiver.NewMockHistoryIterator(mockCtrl)
+	gomock.InOrder(
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next().Return(s.historyBatchesV100[0], nil),
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next().Return(s.historyBatchesV100[1], nil),
+		historyIterator.EXPECT().HasNext().Return(false),
+	)
+
+	historyArchiver := s.newTestHistoryArchiver(historyIterator)
+	URI, err := archiver.NewURI(testContainerURI + "/TestArchiveAndGet")
+	s.NoError(err)
+	err = historyArchiver.Archive(context.Background(), URI, s.newArchiveRequest())
+	s.NoError(err)
+
+	for i := range s.historyBatchesV100 {
+		blobName := constructHistoryBlobName(testDomainID, testWorkflowID, testRunID, testCloseFailoverVersion, i)
+		exists, err := s.client.Exist(context.Background(), URI, blobName)
+		s.NoError(err)
+		s.True(exists)
+	}
+
+	response, err := historyArchiver.Get(context.Background(), URI, s.newGetRequest())
+	s.NoError(err)
+	s.Nil(response.NextPageToken)
+	s.Equal(append(s.historyBatchesV100[0].Body, s.historyBatchesV100[1].Body...), response.HistoryBatches)
+}
+
+func (s *historyArchiverSuite) TestArchiveAndGet_WithEnvelope() {
+	mockCtrl := gomock.NewController(s.T())
+	defer mockCtrl.Finish()
+	historyIterator := archiver.NewMockHistoryIterator(mockCtrl)
+	gomock.InOrder(
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next().Return(s.historyBatchesV1[0], nil),
+		historyIterator.EXPECT().HasNext().Return(false),
+	)
+
+	s.container.Envelope = archiver.NewBlobEnvelope(archiver.CompressionZstd, nil)
+	historyArchiver := s.newTestHistoryArchiver(historyIterator)
+	URI, err := archiver.NewURI(testContainerURI + "/TestArchiveAndGet_WithEnvelope")
+	s.NoError(err)
+	request := s.newArchiveRequest()
+	request.CloseFailoverVersion = 1
+	err = historyArchiver.Archive(context.Background(), URI, request)
+	s.NoError(err)
+
+	blob, err := s.client.Get(context.Background(), URI, constructHistoryBlobName(testDomainID, testWorkflowID, testRunID, 1, 0))
+	s.NoError(err)
+	s.NotContains(string(blob), testWorkflowID)
+
+	response, err := historyArchiver.Get(context.Background(), URI, s.newGetRequest())
+	s.NoError(err)
+	s.Equal(s.historyBatchesV1[0].Body, response.HistoryBatches)
+}
+
+func (s *historyArchiverSuite) newTestHistoryArchiver(historyIterator archiver.HistoryIterator) *historyArchiver {
+	return newHistoryArchiver(s.container, s.client, historyIterator)
+}
+
+func (s *historyArchiverSuite) newArchiveRequest() *archiver.ArchiveHistoryRequest {
+	return &archiver.ArchiveHistoryRequest{
+		DomainID:             testDomainID,
+		DomainName:           testDomainName,
+		WorkflowID:           testWorkflowID,
+		RunID:                testRunID,
+		BranchToken:          testBranchToken,
+		NextEventID:          testNextEventID,
+		CloseFailoverVersion: testCloseFailoverVersion,
+	}
+}
+
+func (s *historyArchiverSuite) newGetRequest() *archiver.GetHistoryRequest {
+	return &archiver.GetHistoryRequest{
+		DomainID:   testDomainID,
+		WorkflowID: testWorkflowID,
+		RunID:      testRunID,
+		PageSize:   testPageSize,
+	}
+}
+
+func (s *historyArchiverSuite) setupHistoryDirectory() {
+	s.historyBatchesV1 = []*archiverproto.HistoryBlob{
+		{
+			Header: &archiverproto.HistoryBlobHeader{
+				IsLast: true,
+			},
+			Body: []*commonproto.History{
+				{
+					Events: []*commonproto.HistoryEvent{
+						{
+							EventId:   testNextEventID - 1,
+							Timestamp: time.Now().UnixNano(),
+							Version:   1,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.historyBatchesV100 = []*archiverproto.HistoryBlob{
+		{
+			Header: &archiverproto.HistoryBlobHeader{
+				IsLast: false,
+			},
+			Body: []*commonproto.History{
+				{
+					Events: []*commonproto.HistoryEvent{
+						{
+							EventId:   common.FirstEventID + 1,
+							Timestamp: time.Now().UnixNano(),
+							Version:   testCloseFailoverVersion,
+						},
+						{
+							EventId:   common.FirstEventID + 1,
+							Timestamp: time.Now().UnixNano(),
+							Version:   testCloseFailoverVersion,
+						},
+					},
+				},
+			},
+		},
+		{
+			Header: &archiverproto.HistoryBlobHeader{
+				IsLast: true,
+			},
+			Body: []*commonproto.History{
+				{
+					Events: []*commonproto.HistoryEvent{
+						{
+							EventId:   testNextEventID - 1,
+							Timestamp: time.Now().UnixNano(),
+							Version:   testCloseFailoverVersion,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.writeHistoryBatchesForGetTest(s.historyBatchesV1, int64(1))
+	s.writeHistoryBatchesForGetTest(s.historyBatchesV100, testCloseFailoverVersion)
+}
+
+func (s *historyArchiverSuite) writeHistoryBatchesForGetTest(historyBatches []*archiverproto.HistoryBlob, version int64) {
+	for i, batch := range historyBatches {
+		encoder := codec.NewJSONPBEncoder()
+		data, err := encoder.Encode(batch)
+		s.Require().NoError(err)
+		blobName := constructHistoryBlobName(testDomainID, testWorkflowID, testRunID, version, i)
+		s.Require().NoError(s.client.Upload(context.Background(), s.testArchivalURI, blobName, data))
+	}
+}
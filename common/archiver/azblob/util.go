@@ -0,0 +1,182 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azblob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+	blob "github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/dgryski/go-farm"
+	"github.com/gogo/protobuf/types"
+	commonproto "go.temporal.io/temporal-proto/common"
+
+	archiverproto "github.com/temporalio/temporal/.gen/proto/archiver"
+	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/codec"
+)
+
+// encoding & decoding util
+
+func encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func decodeVisibilityRecord(data []byte) (*archiverproto.ArchiveVisibilityRequest, error) {
+	record := &archiverproto.ArchiveVisibilityRequest{}
+	encoder := codec.NewJSONPBEncoder()
+	err := encoder.Decode(data, record)
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func serializeToken(token interface{}) ([]byte, error) {
+	if token == nil {
+		return nil, nil
+	}
+	return json.Marshal(token)
+}
+
+func deserializeGetHistoryToken(bytes []byte) (*getHistoryToken, error) {
+	token := &getHistoryToken{}
+	err := json.Unmarshal(bytes, token)
+	return token, err
+}
+
+func deserializeQueryVisibilityToken(bytes []byte) (*queryVisibilityToken, error) {
+	token := &queryVisibilityToken{}
+	err := json.Unmarshal(bytes, token)
+	return token, err
+}
+
+// Blob name construction
+
+// Workflow and run IDs are hashed as they can contain characters which are not allowed in blob names
+func constructHistoryBlobNamePrefix(domainID, workflowID, runID string) string {
+	return strings.Join([]string{domainID, "history", hash(workflowID), hash(runID)}, "/") + "/"
+}
+
+func constructHistoryBlobName(domainID, workflowID, runID string, version int64, batchIdx int) string {
+	return fmt.Sprintf("%s%v/%v.history", constructHistoryBlobNamePrefix(domainID, workflowID, runID), version, batchIdx)
+}
+
+// extractCloseFailoverVersion extracts the version from a blob name relative to the history blob name prefix
+func extractCloseFailoverVersion(blobName string) (int64, error) {
+	pieces := strings.Split(blobName, "/")
+	if len(pieces) != 2 {
+		return 0, fmt.Errorf("failed to parse history blob name %s", blobName)
+	}
+	return strconv.ParseInt(pieces[0], 10, 64)
+}
+
+func constructVisibilityBlobNamePrefix(domainID string) string {
+	return strings.Join([]string{domainID, "visibility"}, "/") + "/"
+}
+
+// The blob name has the format: domainID/visibility/closeTimestamp_hash(runID).visibility
+// This format allows the archiver to sort all records without reading the blob contents
+func constructVisibilityBlobName(domainID string, closeTimestamp int64, runID string) string {
+	return fmt.Sprintf("%s%v_%s.visibility", constructVisibilityBlobNamePrefix(domainID), closeTimestamp, hash(runID))
+}
+
+func hash(s string) string {
+	return fmt.Sprintf("%v", farm.Fingerprint64([]byte(s)))
+}
+
+// Validation
+
+// validateURI only validates the scheme and that a container is given
+func validateURI(URI archiver.URI) error {
+	if URI.Scheme() != URIScheme {
+		return archiver.ErrURISchemeMismatch
+	}
+	if len(URI.Hostname()) == 0 {
+		return errNoContainerSpecified
+	}
+	return nil
+}
+
+func isRetryableError(err error) bool {
+	if storageErr, ok := err.(blob.StorageError); ok {
+		resp := storageErr.Response()
+		if resp == nil {
+			return false
+		}
+		return resp.StatusCode == http.StatusTooManyRequests ||
+			(resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented)
+	}
+	// errors of the SDK pipeline wrap the error of the failed HTTP request
+	_, ok := pipeline.Cause(err).(net.Error)
+	return ok
+}
+
+func historyMutated(request *archiver.ArchiveHistoryRequest, historyBatches []*commonproto.History, isLast bool) bool {
+	lastBatch := historyBatches[len(historyBatches)-1].Events
+	lastEvent := lastBatch[len(lastBatch)-1]
+	lastFailoverVersion := lastEvent.GetVersion()
+	if lastFailoverVersion > request.CloseFailoverVersion {
+		return true
+	}
+
+	if !isLast {
+		return false
+	}
+	lastEventID := lastEvent.GetEventId()
+	return lastFailoverVersion != request.CloseFailoverVersion || lastEventID+1 != request.NextEventID
+}
+
+func contextExpired(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func convertToExecutionInfo(record *archiverproto.ArchiveVisibilityRequest) *commonproto.WorkflowExecutionInfo {
+	return &commonproto.WorkflowExecutionInfo{
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: record.WorkflowID,
+			RunId:      record.RunID,
+		},
+		Type: &commonproto.WorkflowType{
+			Name: record.WorkflowTypeName,
+		},
+		StartTime:     &types.Int64Value{Value: record.StartTimestamp},
+		ExecutionTime: record.ExecutionTimestamp,
+		CloseTime:     &types.Int64Value{Value: record.CloseTimestamp},
+		CloseStatus:   record.CloseStatus,
+		HistoryLength: record.HistoryLength,
+		Memo:          record.Memo,
+		SearchAttributes: &commonproto.SearchAttributes{
+			IndexedFields: archiver.ConvertSearchAttrToBytes(record.SearchAttributes),
+		},
+	}
+}
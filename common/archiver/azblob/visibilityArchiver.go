@@ -0,0 +1,327 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azblob
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	archiverproto "github.com/temporalio/temporal/.gen/proto/archiver"
+	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/service/config"
+)
+
+const (
+	errEncodeVisibilityRecord = "failed to encode visibility record"
+	errWriteVisibilityRecord  = "failed to write visibility record to azure blob storage"
+)
+
+type (
+	visibilityArchiver struct {
+		container   *archiver.VisibilityBootstrapContainer
+		client      blobClient
+		queryParser archiver.QueryParser
+	}
+
+	queryVisibilityToken struct {
+		LastCloseTime int64
+		LastRunID     string
+	}
+
+	queryVisibilityRequest struct {
+		domainID      string
+		pageSize      int
+		nextPageToken []byte
+		parsedQuery   *archiver.ParsedQuery
+	}
+
+	parsedVisBlobName struct {
+		name        string
+		closeTime   int64
+		hashedRunID string
+	}
+)
+
+// NewVisibilityArchiver creates a new archiver.VisibilityArchiver based on azure blob storage
+func NewVisibilityArchiver(
+	container *archiver.VisibilityBootstrapContainer,
+	config *config.AzblobArchiver,
+) (archiver.VisibilityArchiver, error) {
+	client, err := newStorageClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return newVisibilityArchiver(container, client), nil
+}
+
+func newVisibilityArchiver(
+	container *archiver.VisibilityBootstrapContainer,
+	client blobClient,
+) *visibilityArchiver {
+	return &visibilityArchiver{
+		container:   container,
+		client:      client,
+		queryParser: archiver.NewQueryParser(),
+	}
+}
+
+func (v *visibilityArchiver) Archive(
+	ctx context.Context,
+	URI archiver.URI,
+	request *archiverproto.ArchiveVisibilityRequest,
+	opts ...archiver.ArchiveOption,
+) (err error) {
+	scope := v.container.MetricsClient.Scope(metrics.VisibilityArchiverScope, metrics.DomainTag(request.DomainName))
+	featureCatalog := archiver.GetFeatureCatalog(opts...)
+	sw := scope.StartTimer(metrics.ServiceLatency)
+	logger := archiver.TagLoggerWithArchiveVisibilityRequestAndURI(v.container.Logger, request, URI.String())
+	archiveFailReason := ""
+	defer func() {
+		sw.Stop()
+		if err != nil {
+			if isRetryableError(err) {
+				scope.IncCounter(metrics.VisibilityArchiverArchiveTransientErrorCount)
+				logger.Error(archiver.ArchiveTransientErrorMsg, tag.ArchivalArchiveFailReason(archiveFailReason), tag.Error(err))
+			} else {
+				scope.IncCounter(metrics.VisibilityArchiverArchiveNonRetryableErrorCount)
+				logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(archiveFailReason), tag.Error(err))
+				if featureCatalog.NonRetriableError != nil {
+					err = featureCatalog.NonRetriableError()
+				}
+			}
+		}
+	}()
+
+	if err := validateURI(URI); err != nil {
+		archiveFailReason = archiver.ErrReasonInvalidURI
+		return err
+	}
+
+	if err := archiver.ValidateVisibilityArchivalRequest(request); err != nil {
+		archiveFailReason = archiver.ErrReasonInvalidArchiveRequest
+		return err
+	}
+
+	encodedVisibilityRecord, err := encode(request)
+	if err != nil {
+		archiveFailReason = errEncodeVisibilityRecord
+		return err
+	}
+	envelope := v.container.Envelope
+	if featureCatalog.Envelope != nil {
+		envelope = featureCatalog.Envelope
+	}
+	encodedVisibilityRecord, err = envelope.Seal(ctx, encodedVisibilityRecord)
+	if err != nil {
+		archiveFailReason = archiver.ErrReasonSealVisibilityRecord
+		return err
+	}
+
+	blobName := constructVisibilityBlobName(request.DomainID, request.CloseTimestamp, request.RunID)
+	if err := v.client.Upload(ctx, URI, blobName, encodedVisibilityRecord); err != nil {
+		archiveFailReason = errWriteVisibilityRecord
+		return err
+	}
+
+	scope.IncCounter(metrics.VisibilityArchiveSuccessCount)
+	return nil
+}
+
+func (v *visibilityArchiver) Query(
+	ctx context.Context,
+	URI archiver.URI,
+	request *archiver.QueryVisibilityRequest,
+) (*archiver.QueryVisibilityResponse, error) {
+	if err := validateURI(URI); err != nil {
+		return nil, serviceerror.NewInvalidArgument(archiver.ErrInvalidURI.Error())
+	}
+
+	if err := archiver.ValidateQueryRequest(request); err != nil {
+		return nil, serviceerror.NewInvalidArgument(archiver.ErrInvalidQueryVisibilityRequest.Error())
+	}
+
+	parsedQuery, err := v.queryParser.Parse(request.Query)
+	if err != nil {
+		return nil, serviceerror.NewInvalidArgument(err.Error())
+	}
+
+	if parsedQuery.EmptyResult {
+		return &archiver.QueryVisibilityResponse{}, nil
+	}
+
+	return v.query(ctx, URI, &queryVisibilityRequest{
+		domainID:      request.DomainID,
+		pageSize:      request.PageSize,
+		nextPageToken: request.NextPageToken,
+		parsedQuery:   parsedQuery,
+	})
+}
+
+func (v *visibilityArchiver) query(
+	ctx context.Context,
+	URI archiver.URI,
+	request *queryVisibilityRequest,
+) (*archiver.QueryVisibilityResponse, error) {
+	var token *queryVisibilityToken
+	if request.nextPageToken != nil {
+		var err error
+		token, err = deserializeQueryVisibilityToken(request.nextPageToken)
+		if err != nil {
+			return nil, serviceerror.NewInvalidArgument(archiver.ErrNextPageTokenCorrupted.Error())
+		}
+	}
+
+	prefix := constructVisibilityBlobNamePrefix(request.domainID)
+	blobNames, err := v.client.Query(ctx, URI, prefix)
+	if err != nil {
+		if err == ErrContainerNotFound {
+			return nil, serviceerror.NewInvalidArgument(err.Error())
+		}
+		return nil, serviceerror.NewInternal(err.Error())
+	}
+
+	blobNames, err = sortAndFilterBlobNames(blobNames, prefix, token)
+	if err != nil {
+		return nil, serviceerror.NewInternal(err.Error())
+	}
+
+	response := &archiver.QueryVisibilityResponse{}
+	for _, blobName := range blobNames {
+		encodedRecord, err := v.client.Get(ctx, URI, blobName)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+
+		encodedRecord, err = v.container.Envelope.Open(ctx, encodedRecord)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+
+		record, err := decodeVisibilityRecord(encodedRecord)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+
+		if record.CloseTimestamp < request.parsedQuery.EarliestCloseTime {
+			break
+		}
+
+		if matchQuery(record, request.parsedQuery) {
+			response.Executions = append(response.Executions, convertToExecutionInfo(record))
+			if len(response.Executions) == request.pageSize {
+				newToken := &queryVisibilityToken{
+					LastCloseTime: record.CloseTimestamp,
+					LastRunID:     record.RunID,
+				}
+				encodedToken, err := serializeToken(newToken)
+				if err != nil {
+					return nil, serviceerror.NewInternal(err.Error())
+				}
+				response.NextPageToken = encodedToken
+				break
+			}
+		}
+	}
+
+	return response, nil
+}
+
+func (v *visibilityArchiver) ValidateURI(URI archiver.URI) error {
+	if err := validateURI(URI); err != nil {
+		return err
+	}
+	_, err := v.client.Exist(context.TODO(), URI, "")
+	return err
+}
+
+// sortAndFilterBlobNames sorts visibility record blob names based on close timestamp (desc) and uses hashed runID
+// to break ties. If a nextPageToken is given, it only returns blob names that come after the last returned record.
+func sortAndFilterBlobNames(blobNames []string, prefix string, token *queryVisibilityToken) ([]string, error) {
+	var parsedBlobNames []*parsedVisBlobName
+	for _, name := range blobNames {
+		pieces := strings.FieldsFunc(strings.TrimPrefix(name, prefix), func(r rune) bool {
+			return r == '_' || r == '.'
+		})
+		if len(pieces) != 3 {
+			return nil, fmt.Errorf("failed to parse visibility blob name %s", name)
+		}
+
+		closeTime, err := strconv.ParseInt(pieces[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse visibility blob name %s", name)
+		}
+		parsedBlobNames = append(parsedBlobNames, &parsedVisBlobName{
+			name:        name,
+			closeTime:   closeTime,
+			hashedRunID: pieces[1],
+		})
+	}
+
+	sort.Slice(parsedBlobNames, func(i, j int) bool {
+		if parsedBlobNames[i].closeTime == parsedBlobNames[j].closeTime {
+			return parsedBlobNames[i].hashedRunID > parsedBlobNames[j].hashedRunID
+		}
+		return parsedBlobNames[i].closeTime > parsedBlobNames[j].closeTime
+	})
+
+	startIdx := 0
+	if token != nil {
+		lastHashedRunID := hash(token.LastRunID)
+		startIdx = sort.Search(len(parsedBlobNames), func(i int) bool {
+			if parsedBlobNames[i].closeTime == token.LastCloseTime {
+				return parsedBlobNames[i].hashedRunID < lastHashedRunID
+			}
+			return parsedBlobNames[i].closeTime < token.LastCloseTime
+		})
+	}
+
+	var filteredBlobNames []string
+	for _, parsedBlobName := range parsedBlobNames[startIdx:] {
+		filteredBlobNames = append(filteredBlobNames, parsedBlobName.name)
+	}
+	return filteredBlobNames, nil
+}
+
+func matchQuery(record *archiverproto.ArchiveVisibilityRequest, query *archiver.ParsedQuery) bool {
+	if record.CloseTimestamp < query.EarliestCloseTime || record.CloseTimestamp > query.LatestCloseTime {
+		return false
+	}
+	if query.WorkflowID != nil && record.WorkflowID != *query.WorkflowID {
+		return false
+	}
+	if query.RunID != nil && record.RunID != *query.RunID {
+		return false
+	}
+	if query.WorkflowTypeName != nil && record.WorkflowTypeName != *query.WorkflowTypeName {
+		return false
+	}
+	if query.CloseStatus != nil && record.CloseStatus != *query.CloseStatus {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azblob
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+	"go.uber.org/zap"
+
+	archiverproto "github.com/temporalio/temporal/.gen/proto/archiver"
+	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/log/loggerimpl"
+	"github.com/temporalio/temporal/common/metrics"
+)
+
+const (
+	testWorkflowTypeName = "test-workflow-type"
+)
+
+type visibilityArchiverSuite struct {
+	*require.Assertions
+	suite.Suite
+
+	server            *httptest.Server
+	client            *storageClient
+	container         *archiver.VisibilityBootstrapContainer
+	testArchivalURI   archiver.URI
+	visibilityRecords []*archiverproto.ArchiveVisibilityRequest
+}
+
+func TestVisibilityArchiverSuite(t *testing.T) {
+	suite.Run(t, new(visibilityArchiverSuite))
+}
+
+func (s *visibilityArchiverSuite) SetupSuite() {
+	var err error
+	_, s.server, s.client = newTestBlobService(s.T())
+	s.testArchivalURI, err = archiver.NewURI(testContainerURI)
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.createContainer(context.Background(), s.testArchivalURI))
+
+	scope := tally.NewTestScope("test", nil)
+	s.container = &archiver.VisibilityBootstrapContainer{
+		Logger:        loggerimpl.NewLogger(zap.NewNop()),
+		MetricsClient: metrics.NewClient(scope, metrics.VisibilityArchiverScope),
+	}
+	s.setupVisibilityDirectory()
+}
+
+func (s *visibilityArchiverSuite) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *visibilityArchiverSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *visibilityArchiverSuite) TestValidateURI() {
+	testCases := []struct {
+		URI         string
+		expectedErr error
+	}{
+		{
+			URI:         "wrongscheme:///a/b/c",
+			expectedErr: archiver.ErrURISchemeMismatch,
+		},
+		{
+			URI:         "azblob://",
+			expectedErr: errNoContainerSpecified,
+		},
+		{
+			URI:         "azblob://container/a/b/c",
+			expectedErr: ErrContainerNotFound,
+		},
+		{
+			URI:         testContainerURI,
+			expectedErr: nil,
+		},
+	}
+
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	for _, tc := range testCases {
+		URI, err := archiver.NewURI(tc.URI)
+		s.NoError(err)
+		s.Equal(tc.expectedErr, visibilityArchiver.ValidateURI(URI))
+	}
+}
+
+func (s *visibilityArchiverSuite) TestArchive_Fail_InvalidURI() {
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	URI, err := archiver.NewURI("wrongscheme://")
+	s.NoError(err)
+	err = visibilityArchiver.Archive(context.Background(), URI, s.visibilityRecords[0])
+	s.Error(err)
+}
+
+func (s *visibilityArchiverSuite) TestArchive_Fail_InvalidRequest() {
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	err := visibilityArchiver.Archive(context.Background(), s.testArchivalURI, &archiverproto.ArchiveVisibilityRequest{})
+	s.Error(err)
+}
+
+func (s *visibilityArchiverSuite) TestArchive_Fail_NonRetriableErrorOption() {
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	nonRetryableErr := errors.New("some non-retryable error")
+	err := visibilityArchiver.Archive(context.Background(), s.testArchivalURI, &archiverproto.ArchiveVisibilityRequest{}, archiver.GetNonRetriableErrorOption(nonRetryableErr))
+	s.Equal(nonRetryableErr, err)
+}
+
+func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidURI() {
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	URI, err := archiver.NewURI("wrongscheme://")
+	s.NoError(err)
+	response, err := visibilityArchiver.Query(context.Background(), URI, s.newQueryRequest("CloseStatus = 'Failed'"))
+	s.Nil(response)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+}
+
+func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidQuery() {
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	response, err := visibilityArchiver.Query(context.Background(), s.testArchivalURI, s.newQueryRequest("some invalid query"))
+	s.Nil(response)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+}
+
+func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidToken() {
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	request := s.newQueryRequest("CloseStatus = 'Failed'")
+	request.NextPageToken = []byte{1, 2, 3}
+	response, err := visibilityArchiver.Query(context.Background(), s.testArchivalURI, request)
+	s.Nil(response)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+}
+
+func (s *visibilityArchiverSuite) TestQuery_Success_DomainNotExist() {
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	request := s.newQueryRequest("CloseStatus = 'Failed'")
+	request.DomainID = "domain-not-exist"
+	response, err := visibilityArchiver.Query(context.Background(), s.testArchivalURI, request)
+	s.NoError(err)
+	s.Empty(response.Executions)
+	s.Nil(response.NextPageToken)
+}
+
+func (s *visibilityArchiverSuite) TestQuery_Success_NoNextPageToken() {
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	request := s.newQueryRequest("WorkflowID = 'test-workflow-id' and CloseTime >= 1 and CloseTime <= 10001")
+	response, err := visibilityArchiver.Query(context.Background(), s.testArchivalURI, request)
+	s.NoError(err)
+	s.Nil(response.NextPageToken)
+	s.Len(response.Executions, 1)
+	s.Equal(convertToExecutionInfo(s.visibilityRecords[0]), response.Executions[0])
+}
+
+func (s *visibilityArchiverSuite) TestQuery_Success_SmallPageSize() {
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	request := s.newQueryRequest("CloseStatus = 'Failed' and CloseTime >= 1 and CloseTime <= 10001")
+	request.PageSize = 2
+	response, err := visibilityArchiver.Query(context.Background(), s.testArchivalURI, request)
+	s.NoError(err)
+	s.NotNil(response.NextPageToken)
+	s.Len(response.Executions, 2)
+	s.Equal(convertToExecutionInfo(s.visibilityRecords[0]), response.Executions[0])
+	s.Equal(convertToExecutionInfo(s.visibilityRecords[1]), response.Executions[1])
+
+	request.NextPageToken = response.NextPageToken
+	response, err = visibilityArchiver.Query(context.Background(), s.testArchivalURI, request)
+	s.NoError(err)
+	s.Nil(response.NextPageToken)
+	s.Len(response.Executions, 1)
+	s.Equal(convertToExecutionInfo(s.visibilityRecords[3]), response.Executions[0])
+}
+
+func (s *visibilityArchiverSuite) TestArchiveAndQuery() {
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	URI, err := archiver.NewURI(testContainerURI + "/TestArchiveAndQuery")
+	s.NoError(err)
+	for _, record := range s.visibilityRecords {
+		s.NoError(visibilityArchiver.Archive(context.Background(), URI, record))
+	}
+
+	request := s.newQueryRequest("CloseStatus = 'ContinuedAsNew'")
+	response, err := visibilityArchiver.Query(context.Background(), URI, request)
+	s.NoError(err)
+	s.Nil(response.NextPageToken)
+	s.Len(response.Executions, 1)
+	s.Equal(convertToExecutionInfo(s.visibilityRecords[2]), response.Executions[0])
+}
+
+func (s *visibilityArchiverSuite) TestSortAndFilterBlobNames() {
+	prefix := constructVisibilityBlobNamePrefix(testDomainID)
+	blobNames := []string{
+		prefix + "9_12345.visibility",
+		prefix + "5_0.visibility",
+		prefix + "9_54321.visibility",
+		prefix + "1000_4321.visibility",
+	}
+	sorted, err := sortAndFilterBlobNames(blobNames, prefix, nil)
+	s.NoError(err)
+	s.Equal([]string{blobNames[3], blobNames[2], blobNames[0], blobNames[1]}, sorted)
+
+	_, err = sortAndFilterBlobNames([]string{prefix + "invalid.visibility"}, prefix, nil)
+	s.Error(err)
+}
+
+func (s *visibilityArchiverSuite) newTestVisibilityArchiver() *visibilityArchiver {
+	return newVisibilityArchiver(s.container, s.client)
+}
+
+func (s *visibilityArchiverSuite) newQueryRequest(query string) *archiver.QueryVisibilityRequest {
+	return &archiver.QueryVisibilityRequest{
+		DomainID: testDomainID,
+		PageSize: 10,
+		Query:    query,
+	}
+}
+
+func (s *visibilityArchiverSuite) setupVisibilityDirectory() {
+	s.visibilityRecords = []*archiverproto.ArchiveVisibilityRequest{
+		{
+			DomainID:         testDomainID,
+			DomainName:       testDomainName,
+			WorkflowID:       testWorkflowID,
+			RunID:            testRunID,
+			WorkflowTypeName: testWorkflowTypeName,
+			StartTimestamp:   1,
+			CloseTimestamp:   10000,
+			CloseStatus:      enums.WorkflowExecutionCloseStatusFailed,
+			HistoryLength:    101,
+		},
+		{
+			DomainID:         testDomainID,
+			DomainName:       testDomainName,
+			WorkflowID:       "some random workflow ID",
+			RunID:            "some random run ID",
+			WorkflowTypeName: testWorkflowTypeName,
+			StartTimestamp:   2,
+			CloseTimestamp:   1000,
+			CloseStatus:      enums.WorkflowExecutionCloseStatusFailed,
+			HistoryLength:    123,
+		},
+		{
+			DomainID:         testDomainID,
+			DomainName:       testDomainName,
+			WorkflowID:       "another workflow ID",
+			RunID:            "another run ID",
+			WorkflowTypeName: testWorkflowTypeName,
+			StartTimestamp:   3,
+			CloseTimestamp:   10,
+			CloseStatus:      enums.WorkflowExecutionCloseStatusContinuedAsNew,
+			HistoryLength:    456,
+		},
+		{
+			DomainID:         testDomainID,
+			DomainName:       testDomainName,
+			WorkflowID:       "and another workflow ID",
+			RunID:            "and another run ID",
+			WorkflowTypeName: testWorkflowTypeName,
+			StartTimestamp:   3,
+			CloseTimestamp:   5,
+			CloseStatus:      enums.WorkflowExecutionCloseStatusFailed,
+			HistoryLength:    456,
+		},
+		{
+			DomainID:         "some random domain ID",
+			DomainName:       "some random domain name",
+			WorkflowID:       "another workflow ID",
+			RunID:            "another run ID",
+			WorkflowTypeName: testWorkflowTypeName,
+			StartTimestamp:   3,
+			CloseTimestamp:   10000,
+			CloseStatus:      enums.WorkflowExecutionCloseStatusContinuedAsNew,
+			HistoryLength:    456,
+		},
+	}
+
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	for _, record := range s.visibilityRecords {
+		s.Require().NoError(visibilityArchiver.Archive(context.Background(), s.testArchivalURI, record))
+	}
+}
@@ -40,6 +40,10 @@ const (
 	ErrReasonReadHistory = "failed to read history batches"
 	// ErrReasonHistoryMutated is the error reason for mutated history
 	ErrReasonHistoryMutated = "history was mutated"
+	// ErrReasonSealHistory is the error reason for failing to compress or encrypt history
+	ErrReasonSealHistory = "failed to seal history"
+	// ErrReasonSealVisibilityRecord is the error reason for failing to compress or encrypt a visibility record
+	ErrReasonSealVisibilityRecord = "failed to seal visibility record"
 )
 
 var (
@@ -0,0 +1,275 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/DataDog/zstd"
+
+	"github.com/temporalio/temporal/common/service/config"
+)
+
+type (
+	// Compression is the compression algorithm applied to archived blobs
+	Compression uint8
+
+	// KeyProvider provides the keys used to encrypt archived blobs. Keys are referenced by ID from the blobs
+	// they encrypted, so keys can be rotated as long as retired keys remain available for decryption.
+	KeyProvider interface {
+		// GetEncryptionKey returns the key new blobs are encrypted with together with its ID
+		GetEncryptionKey(ctx context.Context) (keyID string, key []byte, err error)
+		// GetDecryptionKey returns the key with the given ID
+		GetDecryptionKey(ctx context.Context, keyID string) ([]byte, error)
+	}
+
+	// BlobEnvelope compresses and encrypts archived blobs. Sealed blobs start with a versioned header which
+	// records how the blob was sealed, so blobs remain readable when the envelope settings change. Blobs
+	// archived before envelopes were introduced do not have a header and are returned as is when opened.
+	BlobEnvelope struct {
+		compression Compression
+		keyProvider KeyProvider
+	}
+)
+
+const (
+	// CompressionNone leaves blobs uncompressed
+	CompressionNone Compression = iota
+	// CompressionGzip compresses blobs with gzip
+	CompressionGzip
+	// CompressionZstd compresses blobs with zstd
+	CompressionZstd
+)
+
+const (
+	envelopeVersion1 = 1
+
+	encryptionNone   = 0
+	encryptionAESGCM = 1
+
+	envelopeMagicSize = 4
+	// magic, version, compression and encryption
+	envelopeHeaderSize = envelopeMagicSize + 3
+)
+
+// envelopeMagic starts with a zero byte, which never starts the JSON encoded blobs written before envelopes
+var envelopeMagic = [envelopeMagicSize]byte{0, 'T', 'A', 'E'}
+
+var (
+	errUnknownCompression        = errors.New("unknown archival compression")
+	errInvalidEnvelope           = errors.New("archived blob envelope is corrupted")
+	errUnsupportedEnvelope       = errors.New("archived blob envelope version is not supported")
+	errEnvelopeKeyProviderNotSet = errors.New("archived blob is encrypted but no key provider is configured")
+	errEnvelopeKeyIDTooLong      = errors.New("archival encryption key ID is too long")
+)
+
+// NewBlobEnvelope creates a new BlobEnvelope, blobs are not encrypted when keyProvider is nil
+func NewBlobEnvelope(compression Compression, keyProvider KeyProvider) *BlobEnvelope {
+	return &BlobEnvelope{
+		compression: compression,
+		keyProvider: keyProvider,
+	}
+}
+
+// NewBlobEnvelopeFromConfig creates a new BlobEnvelope from the archival envelope config,
+// it returns nil when no config is given
+func NewBlobEnvelopeFromConfig(cfg *config.ArchivalEnvelope) (*BlobEnvelope, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	compression, err := ParseCompression(cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+	var keyProvider KeyProvider
+	if cfg.KeyFile != "" {
+		if keyProvider, err = NewFileKeyProvider(cfg.KeyFile); err != nil {
+			return nil, err
+		}
+	}
+	return NewBlobEnvelope(compression, keyProvider), nil
+}
+
+// ParseCompression parses the name of a compression algorithm: none, gzip or zstd
+func ParseCompression(name string) (Compression, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return CompressionNone, nil
+	case "gzip":
+		return CompressionGzip, nil
+	case "zstd":
+		return CompressionZstd, nil
+	default:
+		return CompressionNone, errUnknownCompression
+	}
+}
+
+// Seal compresses and encrypts the blob, a nil envelope returns the blob unchanged
+func (e *BlobEnvelope) Seal(ctx context.Context, blob []byte) ([]byte, error) {
+	if e == nil {
+		return blob, nil
+	}
+
+	payload, err := compress(e.compression, blob)
+	if err != nil {
+		return nil, err
+	}
+
+	header := append([]byte{}, envelopeMagic[:]...)
+	header = append(header, envelopeVersion1, byte(e.compression))
+	if e.keyProvider == nil {
+		header = append(header, encryptionNone)
+		return append(header, payload...), nil
+	}
+
+	keyID, key, err := e.keyProvider.GetEncryptionKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyID) > 255 {
+		return nil, errEnvelopeKeyIDTooLong
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	header = append(header, encryptionAESGCM, byte(len(keyID)))
+	header = append(header, keyID...)
+	header = append(header, nonce...)
+	// the header is authenticated together with the payload
+	return aead.Seal(header, nonce, payload, header), nil
+}
+
+// Open decrypts and decompresses a blob sealed by any envelope. Blobs without envelope are returned as is.
+// A nil envelope can open blobs which are not encrypted.
+func (e *BlobEnvelope) Open(ctx context.Context, blob []byte) ([]byte, error) {
+	if !bytes.HasPrefix(blob, envelopeMagic[:]) {
+		return blob, nil
+	}
+	if len(blob) < envelopeHeaderSize {
+		return nil, errInvalidEnvelope
+	}
+	version := blob[envelopeMagicSize]
+	if version != envelopeVersion1 {
+		return nil, errUnsupportedEnvelope
+	}
+	compression := Compression(blob[envelopeMagicSize+1])
+	encryption := blob[envelopeMagicSize+2]
+
+	payload := blob[envelopeHeaderSize:]
+	switch encryption {
+	case encryptionNone:
+	case encryptionAESGCM:
+		if e == nil || e.keyProvider == nil {
+			return nil, errEnvelopeKeyProviderNotSet
+		}
+		if len(payload) < 1 {
+			return nil, errInvalidEnvelope
+		}
+		keyIDLen := int(payload[0])
+		if len(payload) < 1+keyIDLen {
+			return nil, errInvalidEnvelope
+		}
+		keyID := string(payload[1 : 1+keyIDLen])
+		key, err := e.keyProvider.GetDecryptionKey(ctx, keyID)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		headerSize := envelopeHeaderSize + 1 + keyIDLen + aead.NonceSize()
+		if len(blob) < headerSize {
+			return nil, errInvalidEnvelope
+		}
+		nonce := blob[headerSize-aead.NonceSize() : headerSize]
+		payload, err = aead.Open(nil, nonce, blob[headerSize:], blob[:headerSize])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt archived blob: %v", err)
+		}
+	default:
+		return nil, errUnsupportedEnvelope
+	}
+
+	return decompress(compression, payload)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func compress(compression Compression, data []byte) ([]byte, error) {
+	switch compression {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionZstd:
+		return zstd.Compress(nil, data)
+	default:
+		return nil, errUnknownCompression
+	}
+}
+
+func decompress(compression Compression, data []byte) ([]byte, error) {
+	switch compression {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	case CompressionZstd:
+		return zstd.Decompress(nil, data)
+	default:
+		return nil, errUnsupportedEnvelope
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archiver
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	envelopeSuite struct {
+		*require.Assertions
+		suite.Suite
+
+		blob []byte
+	}
+)
+
+func TestEnvelopeSuite(t *testing.T) {
+	suite.Run(t, new(envelopeSuite))
+}
+
+func (s *envelopeSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.blob = []byte(`[{"events":[{"eventId":1,"eventType":"WorkflowExecutionStarted"}]}]`)
+}
+
+func (s *envelopeSuite) TestSealAndOpen() {
+	keyProvider := s.newKeyProvider("key-1", "key-1")
+	for _, compression := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		for _, kp := range []KeyProvider{nil, keyProvider} {
+			envelope := NewBlobEnvelope(compression, kp)
+			sealed, err := envelope.Seal(context.Background(), s.blob)
+			s.NoError(err)
+			s.True(len(sealed) > envelopeHeaderSize)
+			if kp != nil {
+				s.NotContains(string(sealed), "WorkflowExecutionStarted")
+			}
+
+			opened, err := envelope.Open(context.Background(), sealed)
+			s.NoError(err)
+			s.Equal(s.blob, opened)
+		}
+	}
+}
+
+func (s *envelopeSuite) TestSeal_NilEnvelope() {
+	var envelope *BlobEnvelope
+	sealed, err := envelope.Seal(context.Background(), s.blob)
+	s.NoError(err)
+	s.Equal(s.blob, sealed)
+}
+
+func (s *envelopeSuite) TestOpen_LegacyBlob() {
+	opened, err := NewBlobEnvelope(CompressionGzip, s.newKeyProvider("key-1", "key-1")).Open(context.Background(), s.blob)
+	s.NoError(err)
+	s.Equal(s.blob, opened)
+}
+
+func (s *envelopeSuite) TestOpen_NilEnvelope() {
+	sealed, err := NewBlobEnvelope(CompressionZstd, nil).Seal(context.Background(), s.blob)
+	s.NoError(err)
+
+	var envelope *BlobEnvelope
+	opened, err := envelope.Open(context.Background(), sealed)
+	s.NoError(err)
+	s.Equal(s.blob, opened)
+
+	sealed, err = NewBlobEnvelope(CompressionZstd, s.newKeyProvider("key-1", "key-1")).Seal(context.Background(), s.blob)
+	s.NoError(err)
+	_, err = envelope.Open(context.Background(), sealed)
+	s.Equal(errEnvelopeKeyProviderNotSet, err)
+}
+
+func (s *envelopeSuite) TestOpen_Tampered() {
+	envelope := NewBlobEnvelope(CompressionGzip, s.newKeyProvider("key-1", "key-1"))
+	sealed, err := envelope.Seal(context.Background(), s.blob)
+	s.NoError(err)
+
+	payloadTampered := append([]byte{}, sealed...)
+	payloadTampered[len(payloadTampered)-1] ^= 1
+	_, err = envelope.Open(context.Background(), payloadTampered)
+	s.Error(err)
+
+	headerTampered := append([]byte{}, sealed...)
+	headerTampered[envelopeMagicSize+1] = byte(CompressionZstd)
+	_, err = envelope.Open(context.Background(), headerTampered)
+	s.Error(err)
+
+	_, err = envelope.Open(context.Background(), sealed[:envelopeHeaderSize+1])
+	s.Equal(errInvalidEnvelope, err)
+}
+
+func (s *envelopeSuite) TestOpen_UnsupportedVersion() {
+	sealed, err := NewBlobEnvelope(CompressionNone, nil).Seal(context.Background(), s.blob)
+	s.NoError(err)
+	sealed[envelopeMagicSize] = envelopeVersion1 + 1
+	_, err = NewBlobEnvelope(CompressionNone, nil).Open(context.Background(), sealed)
+	s.Equal(errUnsupportedEnvelope, err)
+}
+
+func (s *envelopeSuite) TestKeyRotation() {
+	sealed, err := NewBlobEnvelope(CompressionGzip, s.newKeyProvider("key-1", "key-1")).Seal(context.Background(), s.blob)
+	s.NoError(err)
+
+	rotated := NewBlobEnvelope(CompressionZstd, s.newKeyProvider("key-2", "key-1", "key-2"))
+	opened, err := rotated.Open(context.Background(), sealed)
+	s.NoError(err)
+	s.Equal(s.blob, opened)
+
+	retired := NewBlobEnvelope(CompressionZstd, s.newKeyProvider("key-2", "key-2"))
+	_, err = retired.Open(context.Background(), sealed)
+	s.Error(err)
+}
+
+func (s *envelopeSuite) TestParseCompression() {
+	testCases := []struct {
+		name        string
+		compression Compression
+		err         error
+	}{
+		{name: "", compression: CompressionNone},
+		{name: "none", compression: CompressionNone},
+		{name: "GZIP", compression: CompressionGzip},
+		{name: "zstd", compression: CompressionZstd},
+		{name: "lz4", err: errUnknownCompression},
+	}
+	for _, tc := range testCases {
+		compression, err := ParseCompression(tc.name)
+		s.Equal(tc.err, err)
+		s.Equal(tc.compression, compression)
+	}
+}
+
+func (s *envelopeSuite) TestNewFileKeyProvider_Invalid() {
+	testCases := []string{
+		"encryptionKeyID: key-1\nkeys:\n  key-1: \"not base64\"\n",
+		"encryptionKeyID: key-1\nkeys:\n  key-1: \"" + base64.StdEncoding.EncodeToString([]byte("short")) + "\"\n",
+		"encryptionKeyID: key-2\nkeys:\n  key-1: \"" + base64.StdEncoding.EncodeToString(make([]byte, 32)) + "\"\n",
+	}
+	for _, tc := range testCases {
+		_, err := newFileKeyProvider([]byte(tc))
+		s.Error(err)
+	}
+}
+
+func (s *envelopeSuite) TestFileKeyProvider() {
+	provider := s.newKeyProvider("key-2", "key-1", "key-2")
+	keyID, key, err := provider.GetEncryptionKey(context.Background())
+	s.NoError(err)
+	s.Equal("key-2", keyID)
+	s.Len(key, 32)
+
+	_, err = provider.GetDecryptionKey(context.Background(), "key-1")
+	s.NoError(err)
+	_, err = provider.GetDecryptionKey(context.Background(), "key-3")
+	s.Error(err)
+}
+
+func (s *envelopeSuite) newKeyProvider(encryptionKeyID string, keyIDs ...string) KeyProvider {
+	data := fmt.Sprintf("encryptionKeyID: %v\nkeys:\n", encryptionKeyID)
+	for i, keyID := range keyIDs {
+		key := make([]byte, 32)
+		key[0] = byte(i + 1)
+		data += fmt.Sprintf("  %v: \"%v\"\n", keyID, base64.StdEncoding.EncodeToString(key))
+	}
+	provider, err := newFileKeyProvider([]byte(data))
+	s.NoError(err)
+	return provider
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archiver
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+type (
+	// fileKeyProvider is a KeyProvider backed by a local key file in the following format, keys are base64 encoded
+	// AES keys of 16, 24 or 32 bytes. Retired keys are kept in the file to decrypt the blobs they encrypted.
+	//
+	//   encryptionKeyID: "key-2"
+	//   keys:
+	//     key-1: "..."
+	//     key-2: "..."
+	fileKeyProvider struct {
+		encryptionKeyID string
+		keys            map[string][]byte
+	}
+
+	keyFile struct {
+		EncryptionKeyID string            `yaml:"encryptionKeyID"`
+		Keys            map[string]string `yaml:"keys"`
+	}
+)
+
+// NewFileKeyProvider creates a KeyProvider which reads keys from the given key file
+func NewFileKeyProvider(path string) (KeyProvider, error) {
+	// #nosec
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newFileKeyProvider(data)
+}
+
+func newFileKeyProvider(data []byte) (*fileKeyProvider, error) {
+	var file keyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	provider := &fileKeyProvider{
+		encryptionKeyID: file.EncryptionKeyID,
+		keys:            make(map[string][]byte, len(file.Keys)),
+	}
+	for keyID, encodedKey := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("archival key %v is not base64 encoded: %v", keyID, err)
+		}
+		if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+			return nil, fmt.Errorf("archival key %v must be 16, 24 or 32 bytes long", keyID)
+		}
+		provider.keys[keyID] = key
+	}
+	if _, ok := provider.keys[provider.encryptionKeyID]; !ok {
+		return nil, fmt.Errorf("archival encryption key %v is not in the key file", provider.encryptionKeyID)
+	}
+	return provider, nil
+}
+
+func (p *fileKeyProvider) GetEncryptionKey(ctx context.Context) (string, []byte, error) {
+	return p.encryptionKeyID, p.keys[p.encryptionKeyID], nil
+}
+
+func (p *fileKeyProvider) GetDecryptionKey(ctx context.Context, keyID string) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("archival key %v is not in the key file", keyID)
+	}
+	return key, nil
+}
@@ -172,6 +172,16 @@ func (h *historyArchiver) Archive(
 		return err
 	}
 
+	envelope := h.container.Envelope
+	if featureCatalog.Envelope != nil {
+		envelope = featureCatalog.Envelope
+	}
+	encodedHistoryBatches, err = envelope.Seal(ctx, encodedHistoryBatches)
+	if err != nil {
+		logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(archiver.ErrReasonSealHistory), tag.Error(err))
+		return err
+	}
+
 	dirPath := URI.Path()
 	if err = mkdirAll(dirPath, h.dirMode); err != nil {
 		logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(errMakeDirectory), tag.Error(err))
@@ -246,6 +256,11 @@ func (h *historyArchiver) Get(
 		return nil, serviceerror.NewInternal(err.Error())
 	}
 
+	encodedHistoryBatches, err = h.container.Envelope.Open(ctx, encodedHistoryBatches)
+	if err != nil {
+		return nil, serviceerror.NewInternal(err.Error())
+	}
+
 	encoder := codec.NewJSONPBEncoder()
 	historyBatches, err := encoder.DecodeHistories(encodedHistoryBatches)
 	if err != nil {
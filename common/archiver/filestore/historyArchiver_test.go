@@ -22,6 +22,7 @@ package filestore
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"io/ioutil"
 	"os"
@@ -526,6 +527,69 @@ func (s *historyArchiverSuite) TestArchiveAndGet() {
 	s.Equal(s.historyBatchesV100, response.HistoryBatches)
 }
 
+func (s *historyArchiverSuite) TestArchiveAndGet_WithEnvelope() {
+	mockCtrl := gomock.NewController(s.T())
+	defer mockCtrl.Finish()
+	historyIterator := archiver.NewMockHistoryIterator(mockCtrl)
+	historyBlob := &archiverproto.HistoryBlob{
+		Header: &archiverproto.HistoryBlobHeader{
+			IsLast: true,
+		},
+		Body: s.historyBatchesV100,
+	}
+	gomock.InOrder(
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next().Return(historyBlob, nil),
+		historyIterator.EXPECT().HasNext().Return(false),
+	)
+
+	dir, err := ioutil.TempDir("", "TestArchiveAndGet_WithEnvelope")
+	s.NoError(err)
+	defer os.RemoveAll(dir)
+
+	keyFilePath := path.Join(dir, "keys.yaml")
+	keyFile := "encryptionKeyID: key-1\nkeys:\n  key-1: \"" + base64.StdEncoding.EncodeToString(make([]byte, 32)) + "\"\n"
+	s.NoError(ioutil.WriteFile(keyFilePath, []byte(keyFile), testFileMode))
+	keyProvider, err := archiver.NewFileKeyProvider(keyFilePath)
+	s.NoError(err)
+	s.container.Envelope = archiver.NewBlobEnvelope(archiver.CompressionGzip, keyProvider)
+
+	historyArchiver := s.newTestHistoryArchiver(historyIterator)
+	archiveRequest := &archiver.ArchiveHistoryRequest{
+		DomainID:             testDomainID,
+		DomainName:           testDomainName,
+		WorkflowID:           testWorkflowID,
+		RunID:                testRunID,
+		BranchToken:          testBranchToken,
+		NextEventID:          testNextEventID,
+		CloseFailoverVersion: testCloseFailoverVersion,
+	}
+	URI, err := archiver.NewURI("file://" + dir)
+	s.NoError(err)
+	err = historyArchiver.Archive(context.Background(), URI, archiveRequest)
+	s.NoError(err)
+
+	expectedFilename := constructHistoryFilename(testDomainID, testWorkflowID, testRunID, testCloseFailoverVersion)
+	data, err := ioutil.ReadFile(path.Join(dir, expectedFilename))
+	s.NoError(err)
+	s.NotContains(string(data), testWorkflowID)
+
+	getRequest := &archiver.GetHistoryRequest{
+		DomainID:   testDomainID,
+		WorkflowID: testWorkflowID,
+		RunID:      testRunID,
+		PageSize:   testPageSize,
+	}
+	response, err := historyArchiver.Get(context.Background(), URI, getRequest)
+	s.NoError(err)
+	s.NotNil(response)
+	s.Equal(s.historyBatchesV100, response.HistoryBatches)
+
+	s.container.Envelope = nil
+	_, err = s.newTestHistoryArchiver(nil).Get(context.Background(), URI, getRequest)
+	s.Error(err)
+}
+
 func (s *historyArchiverSuite) newTestHistoryArchiver(historyIterator archiver.HistoryIterator) *historyArchiver {
 	config := &config.FilestoreArchiver{
 		FileMode: testFileModeStr,
@@ -0,0 +1,799 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	archiverproto "github.com/temporalio/temporal/.gen/proto/archiver"
+	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/service/config"
+)
+
+const (
+	// IndexedURIScheme is the scheme for the indexed filestore visibility archiver
+	IndexedURIScheme = "indexedfile"
+
+	// visibility records are partitioned into one directory per UTC day of their close time
+	visibilityPartitionInterval = 24 * time.Hour
+	visibilityPartitionFormat   = "2006-01-02"
+
+	visibilityRecordFileSuffix = ".visibility"
+	segmentFileSuffix          = ".segment"
+	segmentIndexFileSuffix     = ".index"
+	tempFileSuffix             = ".tmp"
+	compactionLockFilename     = "compaction.lock"
+
+	// the compaction lock is refreshed while the compaction runs, a lock which was not refreshed
+	// for compactionLockTimeout is left over by a crashed compaction
+	compactionLockRefreshInterval = 10 * time.Second
+	compactionLockTimeout         = 6 * compactionLockRefreshInterval
+
+	defaultSegmentSize = 1000
+
+	errCompactPartition = "failed to compact visibility partition"
+)
+
+var (
+	errInvalidSegmentSize = errors.New("invalid segment size")
+	errCompactionLockLost = errors.New("compaction lock was taken over by another archiver")
+)
+
+type (
+	// indexedVisibilityArchiver stores visibility records in time partitioned directories. Each record is first
+	// written to its own file, the records of a partition are then compacted into a segment file together with
+	// a sidecar index, so queries only read the records matching the index.
+	indexedVisibilityArchiver struct {
+		container   *archiver.VisibilityBootstrapContainer
+		fileMode    os.FileMode
+		dirMode     os.FileMode
+		segmentSize int
+		queryParser archiver.QueryParser
+
+		sync.Mutex
+		// partitions this archiver wrote records to, keyed by their directory
+		partitions map[string]*visibilityPartition
+	}
+
+	// visibilityPartition tracks the number of record files of a partition which are not compacted yet.
+	// The partition directory is only listed when the archiver first writes to it, so the count does not
+	// include the records written by other archivers afterwards, those archivers compact them instead.
+	visibilityPartition struct {
+		start       time.Time
+		recordCount int
+		compacting  bool
+	}
+
+	// compactionLock is an exclusively created lock file which makes sure only one archiver compacts
+	// a partition at a time. The lock is refreshed until it is released, so it is only taken over
+	// once the archiver holding it stops.
+	compactionLock struct {
+		path    string
+		owner   string
+		stopCh  chan struct{}
+		stopped sync.WaitGroup
+	}
+
+	// segmentIndex is the sidecar index of a segment file
+	segmentIndex struct {
+		Segment string
+		Entries []*segmentIndexEntry
+	}
+
+	segmentIndexEntry struct {
+		CloseTime        int64
+		WorkflowID       string
+		RunID            string
+		WorkflowTypeName string
+		CloseStatus      enums.WorkflowExecutionCloseStatus
+		Offset           int64
+		Length           int64
+	}
+
+	// visibilityCandidate is a record of a partition which matches the index, it is read either
+	// from a segment or from its own record file
+	visibilityCandidate struct {
+		entry       *segmentIndexEntry
+		hashedRunID string
+		segment     string
+		record      *archiverproto.ArchiveVisibilityRequest
+	}
+)
+
+// NewIndexedVisibilityArchiver creates a new archiver.VisibilityArchiver based on filestore which keeps
+// records in indexed, time partitioned segment files
+func NewIndexedVisibilityArchiver(
+	container *archiver.VisibilityBootstrapContainer,
+	config *config.IndexedFilestoreArchiver,
+) (archiver.VisibilityArchiver, error) {
+	fileMode, err := strconv.ParseUint(config.FileMode, 0, 32)
+	if err != nil {
+		return nil, errInvalidFileMode
+	}
+	dirMode, err := strconv.ParseUint(config.DirMode, 0, 32)
+	if err != nil {
+		return nil, errInvalidDirMode
+	}
+	segmentSize := config.SegmentSize
+	if segmentSize == 0 {
+		segmentSize = defaultSegmentSize
+	}
+	if segmentSize < 0 {
+		return nil, errInvalidSegmentSize
+	}
+	return &indexedVisibilityArchiver{
+		container:   container,
+		fileMode:    os.FileMode(fileMode),
+		dirMode:     os.FileMode(dirMode),
+		segmentSize: segmentSize,
+		queryParser: archiver.NewQueryParser(),
+		partitions:  make(map[string]*visibilityPartition),
+	}, nil
+}
+
+func (v *indexedVisibilityArchiver) Archive(
+	ctx context.Context,
+	URI archiver.URI,
+	request *archiverproto.ArchiveVisibilityRequest,
+	opts ...archiver.ArchiveOption,
+) (err error) {
+	featureCatalog := archiver.GetFeatureCatalog(opts...)
+	defer func() {
+		if err != nil && featureCatalog.NonRetriableError != nil {
+			err = featureCatalog.NonRetriableError()
+		}
+	}()
+
+	logger := archiver.TagLoggerWithArchiveVisibilityRequestAndURI(v.container.Logger, request, URI.String())
+
+	if err := v.ValidateURI(URI); err != nil {
+		logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(archiver.ErrReasonInvalidURI), tag.Error(err))
+		return err
+	}
+
+	if err := archiver.ValidateVisibilityArchivalRequest(request); err != nil {
+		logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(archiver.ErrReasonInvalidArchiveRequest), tag.Error(err))
+		return err
+	}
+
+	partitionStart := visibilityPartitionStart(request.CloseTimestamp)
+	dirPath := path.Join(URI.Path(), request.DomainID, visibilityPartitionName(partitionStart))
+	if err = mkdirAll(dirPath, v.dirMode); err != nil {
+		logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(errMakeDirectory), tag.Error(err))
+		return err
+	}
+
+	encodedVisibilityRecord, err := encode(request)
+	if err != nil {
+		logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(errEncodeVisibilityRecord), tag.Error(err))
+		return err
+	}
+
+	envelope := v.container.Envelope
+	if featureCatalog.Envelope != nil {
+		envelope = featureCatalog.Envelope
+	}
+	encodedVisibilityRecord, err = envelope.Seal(ctx, encodedVisibilityRecord)
+	if err != nil {
+		logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(archiver.ErrReasonSealVisibilityRecord), tag.Error(err))
+		return err
+	}
+
+	// records are first written to their own file, which uses the same name as the records of the filestore
+	// visibility archiver, and are moved into a segment once enough records are collected
+	filename := constructVisibilityFilename(request.CloseTimestamp, request.RunID)
+	if err := writeFileAtomic(path.Join(dirPath, filename), encodedVisibilityRecord, v.fileMode); err != nil {
+		logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(errWriteFile), tag.Error(err))
+		return err
+	}
+
+	// the record is archived at this point, compaction failures are retried by the next archive call
+	v.compactPartitionIfNeeded(ctx, dirPath, partitionStart, 1, logger)
+	previousDirPath := path.Join(URI.Path(), request.DomainID, visibilityPartitionName(partitionStart.Add(-visibilityPartitionInterval)))
+	v.compactPartitionIfNeeded(ctx, previousDirPath, partitionStart.Add(-visibilityPartitionInterval), 0, logger)
+	return nil
+}
+
+func (v *indexedVisibilityArchiver) Query(
+	ctx context.Context,
+	URI archiver.URI,
+	request *archiver.QueryVisibilityRequest,
+) (*archiver.QueryVisibilityResponse, error) {
+	if err := v.ValidateURI(URI); err != nil {
+		return nil, serviceerror.NewInvalidArgument(archiver.ErrInvalidURI.Error())
+	}
+
+	if err := archiver.ValidateQueryRequest(request); err != nil {
+		return nil, serviceerror.NewInvalidArgument(archiver.ErrInvalidQueryVisibilityRequest.Error())
+	}
+
+	parsedQuery, err := v.queryParser.Parse(request.Query)
+	if err != nil {
+		return nil, serviceerror.NewInvalidArgument(err.Error())
+	}
+
+	if parsedQuery.EmptyResult {
+		return &archiver.QueryVisibilityResponse{}, nil
+	}
+
+	return v.query(ctx, URI, &queryVisibilityRequest{
+		domainID:      request.DomainID,
+		pageSize:      request.PageSize,
+		nextPageToken: request.NextPageToken,
+		parsedQuery:   parsedQuery,
+	})
+}
+
+func (v *indexedVisibilityArchiver) query(
+	ctx context.Context,
+	URI archiver.URI,
+	request *queryVisibilityRequest,
+) (*archiver.QueryVisibilityResponse, error) {
+	var token *queryVisibilityToken
+	if request.nextPageToken != nil {
+		var err error
+		token, err = deserializeQueryVisibilityToken(request.nextPageToken)
+		if err != nil {
+			return nil, serviceerror.NewInvalidArgument(archiver.ErrNextPageTokenCorrupted.Error())
+		}
+	}
+
+	domainPath := path.Join(URI.Path(), request.domainID)
+	exists, err := directoryExists(domainPath)
+	if err != nil {
+		return nil, serviceerror.NewInternal(err.Error())
+	}
+	if !exists {
+		return &archiver.QueryVisibilityResponse{}, nil
+	}
+
+	partitions, err := listVisibilityPartitions(domainPath, request.parsedQuery, token)
+	if err != nil {
+		return nil, serviceerror.NewInternal(err.Error())
+	}
+
+	response := &archiver.QueryVisibilityResponse{}
+	for idx, partition := range partitions {
+		dirPath := path.Join(domainPath, partition)
+		candidates, err := v.listVisibilityCandidates(ctx, dirPath, request.parsedQuery, token)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+
+		// the next page may turn out empty when later partitions have no matching records,
+		// which saves reading them to find out
+		hasMore := idx != len(partitions)-1
+		remaining := request.pageSize - len(response.Executions)
+		if len(candidates) > remaining {
+			candidates = candidates[:remaining]
+			hasMore = true
+		}
+		if err := v.readVisibilityCandidates(ctx, dirPath, candidates); err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+		for _, candidate := range candidates {
+			response.Executions = append(response.Executions, convertToExecutionInfo(candidate.record))
+		}
+
+		if len(response.Executions) == request.pageSize {
+			if hasMore {
+				last := candidates[len(candidates)-1].entry
+				encodedToken, err := serializeToken(&queryVisibilityToken{
+					LastCloseTime: last.CloseTime,
+					LastRunID:     last.RunID,
+				})
+				if err != nil {
+					return nil, serviceerror.NewInternal(err.Error())
+				}
+				response.NextPageToken = encodedToken
+			}
+			break
+		}
+	}
+
+	return response, nil
+}
+
+func (v *indexedVisibilityArchiver) ValidateURI(URI archiver.URI) error {
+	if URI.Scheme() != IndexedURIScheme {
+		return archiver.ErrURISchemeMismatch
+	}
+
+	return validateDirPath(URI.Path())
+}
+
+// compactPartitionIfNeeded compacts the record files of a partition once there are enough of them
+// to fill a segment, or once the partition has ended so no more records are expected
+func (v *indexedVisibilityArchiver) compactPartitionIfNeeded(
+	ctx context.Context,
+	dirPath string,
+	partitionStart time.Time,
+	archivedRecords int,
+	logger log.Logger,
+) {
+	partition, err := v.getPartition(dirPath, partitionStart, archivedRecords)
+	if err != nil {
+		logger.Warn(errCompactPartition, tag.Error(err))
+		return
+	}
+
+	v.Lock()
+	partitionEnded := time.Now().After(partitionStart.Add(visibilityPartitionInterval))
+	if partition.compacting || partition.recordCount == 0 || (partition.recordCount < v.segmentSize && !partitionEnded) {
+		v.Unlock()
+		return
+	}
+	partition.compacting = true
+	v.Unlock()
+
+	compactedRecords, err := v.compactPartition(ctx, dirPath)
+
+	v.Lock()
+	partition.compacting = false
+	partition.recordCount -= compactedRecords
+	if partition.recordCount < 0 {
+		// records of other archivers were compacted too
+		partition.recordCount = 0
+	}
+	v.Unlock()
+	if err != nil {
+		logger.Warn(errCompactPartition, tag.Error(err))
+	}
+}
+
+// getPartition returns the tracked partition after adding the records archived to it. The record
+// files of a partition are counted when it is first tracked, which already includes archived records.
+func (v *indexedVisibilityArchiver) getPartition(
+	dirPath string,
+	partitionStart time.Time,
+	archivedRecords int,
+) (*visibilityPartition, error) {
+	v.Lock()
+	if partition, ok := v.partitions[dirPath]; ok {
+		partition.recordCount += archivedRecords
+		v.Unlock()
+		return partition, nil
+	}
+	v.Unlock()
+
+	recordCount := 0
+	exists, err := directoryExists(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		recordFiles, err := listFilesBySuffix(dirPath, visibilityRecordFileSuffix)
+		if err != nil {
+			return nil, err
+		}
+		recordCount = len(recordFiles)
+	}
+
+	v.Lock()
+	defer v.Unlock()
+	if partition, ok := v.partitions[dirPath]; ok {
+		// tracked concurrently, the listing is not needed
+		partition.recordCount += archivedRecords
+		return partition, nil
+	}
+	v.removeOldPartitionsLocked()
+	partition := &visibilityPartition{start: partitionStart, recordCount: recordCount}
+	v.partitions[dirPath] = partition
+	return partition, nil
+}
+
+// removeOldPartitionsLocked stops tracking the partitions which ended before the previous partition,
+// records archived to them later on are compacted right away
+func (v *indexedVisibilityArchiver) removeOldPartitionsLocked() {
+	for dirPath, partition := range v.partitions {
+		if !partition.compacting && time.Now().After(partition.start.Add(2*visibilityPartitionInterval)) {
+			delete(v.partitions, dirPath)
+		}
+	}
+}
+
+// compactPartition moves the record files of a partition into a new segment. The segment is written before
+// its index, and the record files are removed only after the index, so a crash at any point leaves each
+// record readable. Records which end up both in a segment and in their own file are deduplicated by queries.
+// Records are moved into the segment as sealed by Archive. Returns the number of records compacted.
+func (v *indexedVisibilityArchiver) compactPartition(ctx context.Context, dirPath string) (int, error) {
+	lock, err := acquireCompactionLock(dirPath, v.fileMode)
+	if err != nil || lock == nil {
+		return 0, err
+	}
+	defer lock.release()
+
+	recordFiles, err := listFilesBySuffix(dirPath, visibilityRecordFileSuffix)
+	if err != nil {
+		return 0, err
+	}
+	if len(recordFiles) == 0 {
+		return 0, nil
+	}
+
+	var records []*archiverproto.ArchiveVisibilityRequest
+	sealedRecords := make(map[*archiverproto.ArchiveVisibilityRequest][]byte, len(recordFiles))
+	for _, file := range recordFiles {
+		sealedRecord, err := readFile(path.Join(dirPath, file))
+		if err != nil {
+			return 0, err
+		}
+		record, err := v.openVisibilityRecord(ctx, sealedRecord)
+		if err != nil {
+			return 0, err
+		}
+		records = append(records, record)
+		sealedRecords[record] = sealedRecord
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].CloseTimestamp == records[j].CloseTimestamp {
+			return hash(records[i].RunID) > hash(records[j].RunID)
+		}
+		return records[i].CloseTimestamp > records[j].CloseTimestamp
+	})
+
+	segmentName := uuid.New()
+	index := &segmentIndex{Segment: segmentName + segmentFileSuffix}
+	var segment []byte
+	for _, record := range records {
+		sealedRecord := sealedRecords[record]
+		index.Entries = append(index.Entries, &segmentIndexEntry{
+			CloseTime:        record.CloseTimestamp,
+			WorkflowID:       record.WorkflowID,
+			RunID:            record.RunID,
+			WorkflowTypeName: record.WorkflowTypeName,
+			CloseStatus:      record.CloseStatus,
+			Offset:           int64(len(segment)),
+			Length:           int64(len(sealedRecord)),
+		})
+		segment = append(segment, sealedRecord...)
+	}
+	encodedIndex, err := json.Marshal(index)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := writeFileAtomic(path.Join(dirPath, index.Segment), segment, v.fileMode); err != nil {
+		return 0, err
+	}
+	// another archiver which took over the lock compacts the same records, which leaves
+	// an unindexed segment behind
+	if !lock.isOwned() {
+		return 0, errCompactionLockLost
+	}
+	if err := writeFileAtomic(path.Join(dirPath, segmentName+segmentIndexFileSuffix), encodedIndex, v.fileMode); err != nil {
+		return 0, err
+	}
+	if !lock.isOwned() {
+		return 0, errCompactionLockLost
+	}
+	for _, file := range recordFiles {
+		if err := os.Remove(path.Join(dirPath, file)); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+	}
+	return len(recordFiles), nil
+}
+
+// acquireCompactionLock exclusively creates the compaction lock of a partition and refreshes it until released.
+// A lock which was not refreshed for compactionLockTimeout is taken over. Returns nil when another archiver
+// holds the lock.
+func acquireCompactionLock(dirPath string, fileMode os.FileMode) (*compactionLock, error) {
+	lock := &compactionLock{
+		path:   path.Join(dirPath, compactionLockFilename),
+		owner:  uuid.New(),
+		stopCh: make(chan struct{}),
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(lock.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fileMode)
+		if err == nil {
+			_, err = f.WriteString(lock.owner)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(lock.path)
+				return nil, err
+			}
+			lock.stopped.Add(1)
+			go lock.refreshLoop()
+			return lock, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		info, err := os.Stat(lock.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if time.Since(info.ModTime()) < compactionLockTimeout {
+			return nil, nil
+		}
+		if err := os.Remove(lock.path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (l *compactionLock) refreshLoop() {
+	defer l.stopped.Done()
+	ticker := time.NewTicker(compactionLockRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stopCh:
+			return
+		case <-ticker.C:
+			if !l.isOwned() {
+				return
+			}
+			now := time.Now()
+			// a failed refresh is retried on the next tick, well before the lock times out
+			os.Chtimes(l.path, now, now)
+		}
+	}
+}
+
+// isOwned returns false once the lock was taken over by another archiver
+func (l *compactionLock) isOwned() bool {
+	owner, err := readFile(l.path)
+	return err == nil && string(owner) == l.owner
+}
+
+// release stops refreshing the lock and removes the lock file unless it was taken over
+func (l *compactionLock) release() {
+	close(l.stopCh)
+	l.stopped.Wait()
+	if l.isOwned() {
+		os.Remove(l.path)
+	}
+}
+
+// listVisibilityPartitions returns the partitions of a domain which may contain records matching
+// the query, latest partition first
+func listVisibilityPartitions(
+	domainPath string,
+	query *archiver.ParsedQuery,
+	token *queryVisibilityToken,
+) ([]string, error) {
+	names, err := listFiles(domainPath)
+	if err != nil {
+		return nil, err
+	}
+
+	latestCloseTime := query.LatestCloseTime
+	if token != nil && token.LastCloseTime < latestCloseTime {
+		latestCloseTime = token.LastCloseTime
+	}
+	var partitions []string
+	for _, name := range names {
+		partitionStart, err := time.Parse(visibilityPartitionFormat, name)
+		if err != nil {
+			// not a partition directory
+			continue
+		}
+		partitionEnd := partitionStart.Add(visibilityPartitionInterval)
+		if partitionEnd.UnixNano() <= query.EarliestCloseTime || partitionStart.UnixNano() > latestCloseTime {
+			continue
+		}
+		partitions = append(partitions, name)
+	}
+	// partition names sort in the same order as their start times
+	sort.Sort(sort.Reverse(sort.StringSlice(partitions)))
+	return partitions, nil
+}
+
+// listVisibilityCandidates returns the records of a partition which match the query and come after the
+// page token, sorted by close time (desc) and hashed runID (desc) like the filestore visibility archiver
+func (v *indexedVisibilityArchiver) listVisibilityCandidates(
+	ctx context.Context,
+	dirPath string,
+	query *archiver.ParsedQuery,
+	token *queryVisibilityToken,
+) ([]*visibilityCandidate, error) {
+	files, err := listFiles(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastHashedRunID string
+	if token != nil {
+		lastHashedRunID = hash(token.LastRunID)
+	}
+	afterToken := func(closeTime int64, hashedRunID string) bool {
+		if token == nil {
+			return true
+		}
+		if closeTime == token.LastCloseTime {
+			return hashedRunID < lastHashedRunID
+		}
+		return closeTime < token.LastCloseTime
+	}
+
+	seenRunIDs := make(map[string]struct{})
+	var candidates []*visibilityCandidate
+	addCandidate := func(candidate *visibilityCandidate) {
+		if _, ok := seenRunIDs[candidate.entry.RunID]; ok {
+			return
+		}
+		if !matchIndexEntry(candidate.entry, query) || !afterToken(candidate.entry.CloseTime, candidate.hashedRunID) {
+			return
+		}
+		seenRunIDs[candidate.entry.RunID] = struct{}{}
+		candidates = append(candidates, candidate)
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file, segmentIndexFileSuffix) {
+			continue
+		}
+		encodedIndex, err := readFile(path.Join(dirPath, file))
+		if err != nil {
+			return nil, err
+		}
+		index := &segmentIndex{}
+		if err := json.Unmarshal(encodedIndex, index); err != nil {
+			return nil, err
+		}
+		for _, entry := range index.Entries {
+			addCandidate(&visibilityCandidate{
+				entry:       entry,
+				hashedRunID: hash(entry.RunID),
+				segment:     index.Segment,
+			})
+		}
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file, visibilityRecordFileSuffix) {
+			continue
+		}
+		encodedRecord, err := readFile(path.Join(dirPath, file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// compacted since the partition was listed
+				continue
+			}
+			return nil, err
+		}
+		record, err := v.openVisibilityRecord(ctx, encodedRecord)
+		if err != nil {
+			return nil, err
+		}
+		addCandidate(&visibilityCandidate{
+			entry: &segmentIndexEntry{
+				CloseTime:        record.CloseTimestamp,
+				WorkflowID:       record.WorkflowID,
+				RunID:            record.RunID,
+				WorkflowTypeName: record.WorkflowTypeName,
+				CloseStatus:      record.CloseStatus,
+			},
+			hashedRunID: hash(record.RunID),
+			record:      record,
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].entry.CloseTime == candidates[j].entry.CloseTime {
+			return candidates[i].hashedRunID > candidates[j].hashedRunID
+		}
+		return candidates[i].entry.CloseTime > candidates[j].entry.CloseTime
+	})
+	return candidates, nil
+}
+
+// readVisibilityCandidates reads the records of the given candidates from their segments
+func (v *indexedVisibilityArchiver) readVisibilityCandidates(
+	ctx context.Context,
+	dirPath string,
+	candidates []*visibilityCandidate,
+) error {
+	segments := make(map[string]*os.File)
+	defer func() {
+		for _, f := range segments {
+			f.Close()
+		}
+	}()
+
+	for _, candidate := range candidates {
+		if candidate.record != nil {
+			continue
+		}
+		f, ok := segments[candidate.segment]
+		if !ok {
+			var err error
+			// #nosec
+			f, err = os.Open(path.Join(dirPath, candidate.segment))
+			if err != nil {
+				return err
+			}
+			segments[candidate.segment] = f
+		}
+		encodedRecord := make([]byte, candidate.entry.Length)
+		if _, err := f.ReadAt(encodedRecord, candidate.entry.Offset); err != nil {
+			return err
+		}
+		record, err := v.openVisibilityRecord(ctx, encodedRecord)
+		if err != nil {
+			return err
+		}
+		candidate.record = record
+	}
+	return nil
+}
+
+// openVisibilityRecord opens and decodes a record sealed by Archive
+func (v *indexedVisibilityArchiver) openVisibilityRecord(
+	ctx context.Context,
+	sealedRecord []byte,
+) (*archiverproto.ArchiveVisibilityRequest, error) {
+	encodedRecord, err := v.container.Envelope.Open(ctx, sealedRecord)
+	if err != nil {
+		return nil, err
+	}
+	return decodeVisibilityRecord(encodedRecord)
+}
+
+func matchIndexEntry(entry *segmentIndexEntry, query *archiver.ParsedQuery) bool {
+	if entry.CloseTime < query.EarliestCloseTime || entry.CloseTime > query.LatestCloseTime {
+		return false
+	}
+	if query.WorkflowID != nil && entry.WorkflowID != *query.WorkflowID {
+		return false
+	}
+	if query.RunID != nil && entry.RunID != *query.RunID {
+		return false
+	}
+	if query.WorkflowTypeName != nil && entry.WorkflowTypeName != *query.WorkflowTypeName {
+		return false
+	}
+	if query.CloseStatus != nil && entry.CloseStatus != *query.CloseStatus {
+		return false
+	}
+	return true
+}
+
+func visibilityPartitionStart(closeTimestamp int64) time.Time {
+	return time.Unix(0, closeTimestamp).UTC().Truncate(visibilityPartitionInterval)
+}
+
+func visibilityPartitionName(partitionStart time.Time) string {
+	return partitionStart.Format(visibilityPartitionFormat)
+}
@@ -0,0 +1,384 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"go.uber.org/zap"
+
+	archiverproto "github.com/temporalio/temporal/.gen/proto/archiver"
+	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/log/loggerimpl"
+	"github.com/temporalio/temporal/common/service/config"
+)
+
+type indexedVisibilityArchiverSuite struct {
+	*require.Assertions
+	suite.Suite
+
+	container *archiver.VisibilityBootstrapContainer
+	testDir   string
+	testURI   archiver.URI
+	day       time.Time
+
+	controller *gomock.Controller
+}
+
+func TestIndexedVisibilityArchiverSuite(t *testing.T) {
+	suite.Run(t, new(indexedVisibilityArchiverSuite))
+}
+
+func (s *indexedVisibilityArchiverSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.container = &archiver.VisibilityBootstrapContainer{
+		Logger: loggerimpl.NewLogger(zap.NewNop()),
+	}
+	s.controller = gomock.NewController(s.T())
+
+	var err error
+	s.testDir, err = ioutil.TempDir("", "TestIndexedVisibilityArchiver")
+	s.NoError(err)
+	s.testURI, err = archiver.NewURI(IndexedURIScheme + "://" + s.testDir)
+	s.NoError(err)
+	s.day = time.Date(2020, 3, 10, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *indexedVisibilityArchiverSuite) TearDownTest() {
+	s.controller.Finish()
+	os.RemoveAll(s.testDir)
+}
+
+func (s *indexedVisibilityArchiverSuite) TestValidateURI() {
+	testCases := []struct {
+		URI         string
+		expectedErr error
+	}{
+		{
+			URI:         "file:///a/b/c",
+			expectedErr: archiver.ErrURISchemeMismatch,
+		},
+		{
+			URI:         "indexedfile://",
+			expectedErr: errEmptyDirectoryPath,
+		},
+		{
+			URI:         "indexedfile:///a/b/c",
+			expectedErr: nil,
+		},
+	}
+
+	visibilityArchiver := s.newTestIndexedVisibilityArchiver(2)
+	for _, tc := range testCases {
+		URI, err := archiver.NewURI(tc.URI)
+		s.NoError(err)
+		s.Equal(tc.expectedErr, visibilityArchiver.ValidateURI(URI))
+	}
+}
+
+func (s *indexedVisibilityArchiverSuite) TestNewIndexedVisibilityArchiver_InvalidSegmentSize() {
+	_, err := NewIndexedVisibilityArchiver(s.container, &config.IndexedFilestoreArchiver{
+		FileMode:    testFileModeStr,
+		DirMode:     testDirModeStr,
+		SegmentSize: -1,
+	})
+	s.Equal(errInvalidSegmentSize, err)
+}
+
+func (s *indexedVisibilityArchiverSuite) TestArchive_WritesRecordToPartition() {
+	visibilityArchiver := s.newTestIndexedVisibilityArchiver(2)
+	request := s.newTestRecord("workflow-1", time.Now(), enums.WorkflowExecutionCloseStatusCompleted)
+	s.NoError(visibilityArchiver.Archive(context.Background(), s.testURI, request))
+
+	dirPath := path.Join(s.testDir, testDomainID, visibilityPartitionName(visibilityPartitionStart(request.CloseTimestamp)))
+	files, err := listFiles(dirPath)
+	s.NoError(err)
+	s.Equal([]string{constructVisibilityFilename(request.CloseTimestamp, request.RunID)}, files)
+}
+
+func (s *indexedVisibilityArchiverSuite) TestArchive_CompactsFullPartition() {
+	visibilityArchiver := s.newTestIndexedVisibilityArchiver(2)
+	now := time.Now()
+	s.NoError(visibilityArchiver.Archive(context.Background(), s.testURI, s.newTestRecord("workflow-1", now, enums.WorkflowExecutionCloseStatusCompleted)))
+	s.NoError(visibilityArchiver.Archive(context.Background(), s.testURI, s.newTestRecord("workflow-2", now, enums.WorkflowExecutionCloseStatusFailed)))
+
+	dirPath := path.Join(s.testDir, testDomainID, visibilityPartitionName(visibilityPartitionStart(now.UnixNano())))
+	s.assertPartitionCompacted(dirPath, 1)
+}
+
+func (s *indexedVisibilityArchiverSuite) TestArchive_CompactsEndedPartition() {
+	visibilityArchiver := s.newTestIndexedVisibilityArchiver(10)
+	s.NoError(visibilityArchiver.Archive(context.Background(), s.testURI, s.newTestRecord("workflow-1", s.day.Add(time.Hour), enums.WorkflowExecutionCloseStatusCompleted)))
+	dirPath := path.Join(s.testDir, testDomainID, visibilityPartitionName(s.day))
+	s.assertPartitionCompacted(dirPath, 1)
+
+	// records archived late into an ended partition are compacted right away
+	s.NoError(visibilityArchiver.Archive(context.Background(), s.testURI, s.newTestRecord("workflow-2", s.day.Add(2*time.Hour), enums.WorkflowExecutionCloseStatusCompleted)))
+	s.assertPartitionCompacted(dirPath, 2)
+}
+
+func (s *indexedVisibilityArchiverSuite) TestArchive_SkipsLockedPartition() {
+	visibilityArchiver := s.newTestIndexedVisibilityArchiver(1)
+	dirPath := path.Join(s.testDir, testDomainID, visibilityPartitionName(s.day))
+	s.NoError(os.MkdirAll(dirPath, testDirMode))
+	s.NoError(writeFile(path.Join(dirPath, compactionLockFilename), nil, testFileMode))
+
+	s.NoError(visibilityArchiver.Archive(context.Background(), s.testURI, s.newTestRecord("workflow-1", s.day, enums.WorkflowExecutionCloseStatusCompleted)))
+	recordFiles, err := listFilesBySuffix(dirPath, visibilityRecordFileSuffix)
+	s.NoError(err)
+	s.Len(recordFiles, 1)
+
+	// a lock left over by a crashed compaction is taken over
+	staleTime := time.Now().Add(-2 * compactionLockTimeout)
+	s.NoError(os.Chtimes(path.Join(dirPath, compactionLockFilename), staleTime, staleTime))
+	compactedRecords, err := visibilityArchiver.compactPartition(context.Background(), dirPath)
+	s.NoError(err)
+	s.Equal(1, compactedRecords)
+	s.assertPartitionCompacted(dirPath, 1)
+	_, err = os.Stat(path.Join(dirPath, compactionLockFilename))
+	s.True(os.IsNotExist(err))
+}
+
+func (s *indexedVisibilityArchiverSuite) TestCompactionLock() {
+	dirPath := path.Join(s.testDir, testDomainID)
+	s.NoError(os.MkdirAll(dirPath, testDirMode))
+
+	lock, err := acquireCompactionLock(dirPath, testFileMode)
+	s.NoError(err)
+	s.NotNil(lock)
+	s.True(lock.isOwned())
+
+	// the lock is held until released
+	otherLock, err := acquireCompactionLock(dirPath, testFileMode)
+	s.NoError(err)
+	s.Nil(otherLock)
+
+	// once taken over, the lock is not removed on release
+	staleTime := time.Now().Add(-2 * compactionLockTimeout)
+	s.NoError(os.Chtimes(lock.path, staleTime, staleTime))
+	otherLock, err = acquireCompactionLock(dirPath, testFileMode)
+	s.NoError(err)
+	s.NotNil(otherLock)
+	s.False(lock.isOwned())
+	lock.release()
+	s.True(otherLock.isOwned())
+
+	otherLock.release()
+	_, err = os.Stat(otherLock.path)
+	s.True(os.IsNotExist(err))
+}
+
+func (s *indexedVisibilityArchiverSuite) TestArchive_TracksPartitionRecordCount() {
+	visibilityArchiver := s.newTestIndexedVisibilityArchiver(3)
+	now := time.Now()
+	dirPath := path.Join(s.testDir, testDomainID, visibilityPartitionName(visibilityPartitionStart(now.UnixNano())))
+	s.NoError(os.MkdirAll(dirPath, testDirMode))
+	record := s.newTestRecord("workflow-0", now, enums.WorkflowExecutionCloseStatusCompleted)
+	data, err := encode(record)
+	s.NoError(err)
+	s.NoError(writeFile(path.Join(dirPath, constructVisibilityFilename(record.CloseTimestamp, record.RunID)), data, testFileMode))
+
+	// the records in the partition are counted when the archiver first writes to it
+	s.NoError(visibilityArchiver.Archive(context.Background(), s.testURI, s.newTestRecord("workflow-1", now, enums.WorkflowExecutionCloseStatusCompleted)))
+	s.Equal(2, visibilityArchiver.partitions[dirPath].recordCount)
+
+	// records written by other archivers afterwards are not counted
+	record = s.newTestRecord("workflow-2", now, enums.WorkflowExecutionCloseStatusCompleted)
+	data, err = encode(record)
+	s.NoError(err)
+	s.NoError(writeFile(path.Join(dirPath, constructVisibilityFilename(record.CloseTimestamp, record.RunID)), data, testFileMode))
+	s.NoError(visibilityArchiver.Archive(context.Background(), s.testURI, s.newTestRecord("workflow-3", now, enums.WorkflowExecutionCloseStatusCompleted)))
+	s.assertPartitionCompacted(dirPath, 1)
+	s.Equal(0, visibilityArchiver.partitions[dirPath].recordCount)
+}
+
+func (s *indexedVisibilityArchiverSuite) TestListVisibilityPartitions() {
+	domainPath := path.Join(s.testDir, testDomainID)
+	for i := 0; i < 3; i++ {
+		s.NoError(os.MkdirAll(path.Join(domainPath, visibilityPartitionName(s.day.Add(time.Duration(i)*visibilityPartitionInterval))), testDirMode))
+	}
+	s.NoError(os.MkdirAll(path.Join(domainPath, "not-a-partition"), testDirMode))
+
+	partitions, err := listVisibilityPartitions(domainPath, &archiver.ParsedQuery{
+		EarliestCloseTime: 0,
+		LatestCloseTime:   s.day.Add(3 * visibilityPartitionInterval).UnixNano(),
+	}, nil)
+	s.NoError(err)
+	s.Equal([]string{"2020-03-12", "2020-03-11", "2020-03-10"}, partitions)
+
+	partitions, err = listVisibilityPartitions(domainPath, &archiver.ParsedQuery{
+		EarliestCloseTime: s.day.Add(visibilityPartitionInterval).UnixNano(),
+		LatestCloseTime:   s.day.Add(3 * visibilityPartitionInterval).UnixNano(),
+	}, &queryVisibilityToken{
+		LastCloseTime: s.day.Add(visibilityPartitionInterval + time.Hour).UnixNano(),
+	})
+	s.NoError(err)
+	s.Equal([]string{"2020-03-11"}, partitions)
+}
+
+func (s *indexedVisibilityArchiverSuite) TestArchiveAndQuery() {
+	visibilityArchiver := s.newTestIndexedVisibilityArchiver(4)
+	mockParser := archiver.NewMockQueryParser(s.controller)
+	mockParser.EXPECT().Parse(gomock.Any()).Return(&archiver.ParsedQuery{
+		EarliestCloseTime: s.day.UnixNano(),
+		LatestCloseTime:   s.day.Add(3 * visibilityPartitionInterval).UnixNano(),
+		CloseStatus:       toWorkflowExecutionCloseStatusPtr(enums.WorkflowExecutionCloseStatusFailed),
+	}, nil).AnyTimes()
+	visibilityArchiver.queryParser = mockParser
+
+	// the first two partitions have ended so each record is compacted into its own segment,
+	// the current partition keeps its records in their own files
+	var expected []*archiverproto.ArchiveVisibilityRequest
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			closeStatus := enums.WorkflowExecutionCloseStatusFailed
+			if j == 1 {
+				closeStatus = enums.WorkflowExecutionCloseStatusCompleted
+			}
+			closeTime := time.Now()
+			if i < 2 {
+				closeTime = s.day.Add(time.Duration(i)*visibilityPartitionInterval + time.Duration(j)*time.Hour)
+			}
+			record := s.newTestRecord(fmt.Sprintf("workflow-%v-%v", i, j), closeTime, closeStatus)
+			s.NoError(visibilityArchiver.Archive(context.Background(), s.testURI, record))
+			if closeStatus == enums.WorkflowExecutionCloseStatusFailed && i < 2 {
+				expected = append([]*archiverproto.ArchiveVisibilityRequest{record}, expected...)
+			}
+		}
+	}
+	s.assertPartitionCompacted(path.Join(s.testDir, testDomainID, visibilityPartitionName(s.day)), 3)
+
+	// a record left behind by a compaction which crashed before removing it is returned once
+	duplicate := expected[len(expected)-1]
+	data, err := encode(duplicate)
+	s.NoError(err)
+	s.NoError(writeFile(path.Join(s.testDir, testDomainID, visibilityPartitionName(s.day), constructVisibilityFilename(duplicate.CloseTimestamp, duplicate.RunID)), data, testFileMode))
+
+	request := &archiver.QueryVisibilityRequest{
+		DomainID: testDomainID,
+		PageSize: 3,
+		Query:    "parsed by mockParser",
+	}
+	var executions []*commonproto.WorkflowExecutionInfo
+	for pages := 0; pages == 0 || request.NextPageToken != nil; pages++ {
+		s.True(pages < 3)
+		response, err := visibilityArchiver.Query(context.Background(), s.testURI, request)
+		s.NoError(err)
+		executions = append(executions, response.Executions...)
+		request.NextPageToken = response.NextPageToken
+	}
+	s.Len(executions, len(expected))
+	for i, record := range expected {
+		s.Equal(convertToExecutionInfo(record), executions[i])
+	}
+}
+
+func (s *indexedVisibilityArchiverSuite) TestArchiveAndQuery_WithEnvelope() {
+	s.container.Envelope = archiver.NewBlobEnvelope(archiver.CompressionGzip, nil)
+	visibilityArchiver := s.newTestIndexedVisibilityArchiver(2)
+	mockParser := archiver.NewMockQueryParser(s.controller)
+	mockParser.EXPECT().Parse(gomock.Any()).Return(&archiver.ParsedQuery{
+		EarliestCloseTime: s.day.UnixNano(),
+		LatestCloseTime:   s.day.Add(visibilityPartitionInterval).UnixNano(),
+	}, nil).AnyTimes()
+	visibilityArchiver.queryParser = mockParser
+
+	records := []*archiverproto.ArchiveVisibilityRequest{
+		s.newTestRecord("workflow-1", s.day.Add(2*time.Hour), enums.WorkflowExecutionCloseStatusCompleted),
+		s.newTestRecord("workflow-2", s.day.Add(time.Hour), enums.WorkflowExecutionCloseStatusFailed),
+	}
+	for _, record := range records {
+		s.NoError(visibilityArchiver.Archive(context.Background(), s.testURI, record))
+	}
+
+	// the partition has ended so each record is compacted right away, sealed records are moved into segments as they are
+	dirPath := path.Join(s.testDir, testDomainID, visibilityPartitionName(s.day))
+	s.assertPartitionCompacted(dirPath, 2)
+	segmentFiles, err := listFilesBySuffix(dirPath, segmentFileSuffix)
+	s.NoError(err)
+	for _, segmentFile := range segmentFiles {
+		data, err := readFile(path.Join(dirPath, segmentFile))
+		s.NoError(err)
+		s.NotContains(string(data), testWorkflowTypeName)
+	}
+
+	response, err := visibilityArchiver.Query(context.Background(), s.testURI, &archiver.QueryVisibilityRequest{
+		DomainID: testDomainID,
+		PageSize: 10,
+		Query:    "parsed by mockParser",
+	})
+	s.NoError(err)
+	s.Equal([]*commonproto.WorkflowExecutionInfo{
+		convertToExecutionInfo(records[0]),
+		convertToExecutionInfo(records[1]),
+	}, response.Executions)
+}
+
+func (s *indexedVisibilityArchiverSuite) newTestIndexedVisibilityArchiver(segmentSize int) *indexedVisibilityArchiver {
+	archiver, err := NewIndexedVisibilityArchiver(s.container, &config.IndexedFilestoreArchiver{
+		FileMode:    testFileModeStr,
+		DirMode:     testDirModeStr,
+		SegmentSize: segmentSize,
+	})
+	s.NoError(err)
+	return archiver.(*indexedVisibilityArchiver)
+}
+
+func (s *indexedVisibilityArchiverSuite) newTestRecord(
+	workflowID string,
+	closeTime time.Time,
+	closeStatus enums.WorkflowExecutionCloseStatus,
+) *archiverproto.ArchiveVisibilityRequest {
+	return &archiverproto.ArchiveVisibilityRequest{
+		DomainID:         testDomainID,
+		DomainName:       testDomainName,
+		WorkflowID:       workflowID,
+		RunID:            workflowID + "-run",
+		WorkflowTypeName: testWorkflowTypeName,
+		StartTimestamp:   closeTime.Add(-time.Minute).UnixNano(),
+		CloseTimestamp:   closeTime.UnixNano(),
+		CloseStatus:      closeStatus,
+		HistoryLength:    10,
+	}
+}
+
+func (s *indexedVisibilityArchiverSuite) assertPartitionCompacted(dirPath string, expectedSegments int) {
+	recordFiles, err := listFilesBySuffix(dirPath, visibilityRecordFileSuffix)
+	s.NoError(err)
+	s.Empty(recordFiles)
+	segmentFiles, err := listFilesBySuffix(dirPath, segmentFileSuffix)
+	s.NoError(err)
+	s.Len(segmentFiles, expectedSegments)
+	indexFiles, err := listFilesBySuffix(dirPath, segmentIndexFileSuffix)
+	s.NoError(err)
+	s.Len(indexFiles, expectedSegments)
+}
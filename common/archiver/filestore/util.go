@@ -31,6 +31,7 @@ import (
 	"strings"
 
 	"github.com/dgryski/go-farm"
+	"github.com/pborman/uuid"
 	commonproto "go.temporal.io/temporal-proto/common"
 
 	archiverproto "github.com/temporalio/temporal/.gen/proto/archiver"
@@ -97,6 +98,17 @@ func writeFile(filepath string, data []byte, fileMode os.FileMode) (retErr error
 	return nil
 }
 
+// writeFileAtomic writes the file under a temporary name first and renames it, so that readers
+// never see a partially written file
+func writeFileAtomic(filepath string, data []byte, fileMode os.FileMode) error {
+	tempFilepath := fmt.Sprintf("%s.%s%s", filepath, uuid.New(), tempFileSuffix)
+	if err := writeFile(tempFilepath, data, fileMode); err != nil {
+		os.Remove(tempFilepath)
+		return err
+	}
+	return os.Rename(tempFilepath, filepath)
+}
+
 // readFile reads the contents of a file specified by filepath
 // WARNING: callers of this method should be extremely careful not to use it in a context where filepath is supplied by
 // the user.
@@ -139,6 +151,21 @@ func listFilesByPrefix(dirPath string, prefix string) ([]string, error) {
 	return filteredFileNames, nil
 }
 
+func listFilesBySuffix(dirPath string, suffix string) ([]string, error) {
+	fileNames, err := listFiles(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var filteredFileNames []string
+	for _, name := range fileNames {
+		if strings.HasSuffix(name, suffix) {
+			filteredFileNames = append(filteredFileNames, name)
+		}
+	}
+	return filteredFileNames, nil
+}
+
 // encoding & decoding util
 
 func encode(v interface{}) ([]byte, error) {
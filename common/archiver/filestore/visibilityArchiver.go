@@ -48,7 +48,7 @@ type (
 		container   *archiver.VisibilityBootstrapContainer
 		fileMode    os.FileMode
 		dirMode     os.FileMode
-		queryParser QueryParser
+		queryParser archiver.QueryParser
 	}
 
 	queryVisibilityToken struct {
@@ -60,7 +60,7 @@ type (
 		domainID      string
 		pageSize      int
 		nextPageToken []byte
-		parsedQuery   *parsedQuery
+		parsedQuery   *archiver.ParsedQuery
 	}
 )
 
@@ -81,7 +81,7 @@ func NewVisibilityArchiver(
 		container:   container,
 		fileMode:    os.FileMode(fileMode),
 		dirMode:     os.FileMode(dirMode),
-		queryParser: NewQueryParser(),
+		queryParser: archiver.NewQueryParser(),
 	}, nil
 }
 
@@ -122,6 +122,16 @@ func (v *visibilityArchiver) Archive(
 		return err
 	}
 
+	envelope := v.container.Envelope
+	if featureCatalog.Envelope != nil {
+		envelope = featureCatalog.Envelope
+	}
+	encodedVisibilityRecord, err = envelope.Seal(ctx, encodedVisibilityRecord)
+	if err != nil {
+		logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(archiver.ErrReasonSealVisibilityRecord), tag.Error(err))
+		return err
+	}
+
 	// The filename has the format: closeTimestamp_hash(runID).visibility
 	// This format allows the archiver to sort all records without reading the file contents
 	filename := constructVisibilityFilename(request.CloseTimestamp, request.RunID)
@@ -151,7 +161,7 @@ func (v *visibilityArchiver) Query(
 		return nil, serviceerror.NewInvalidArgument(err.Error())
 	}
 
-	if parsedQuery.emptyResult {
+	if parsedQuery.EmptyResult {
 		return &archiver.QueryVisibilityResponse{}, nil
 	}
 
@@ -206,12 +216,17 @@ func (v *visibilityArchiver) query(
 			return nil, serviceerror.NewInternal(err.Error())
 		}
 
+		encodedRecord, err = v.container.Envelope.Open(ctx, encodedRecord)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+
 		record, err := decodeVisibilityRecord(encodedRecord)
 		if err != nil {
 			return nil, serviceerror.NewInternal(err.Error())
 		}
 
-		if record.CloseTimestamp < request.parsedQuery.earliestCloseTime {
+		if record.CloseTimestamp < request.parsedQuery.EarliestCloseTime {
 			break
 		}
 
@@ -303,20 +318,20 @@ func sortAndFilterFiles(filenames []string, token *queryVisibilityToken) ([]stri
 	return filteredFilenames, nil
 }
 
-func matchQuery(record *archiverproto.ArchiveVisibilityRequest, query *parsedQuery) bool {
-	if record.CloseTimestamp < query.earliestCloseTime || record.CloseTimestamp > query.latestCloseTime {
+func matchQuery(record *archiverproto.ArchiveVisibilityRequest, query *archiver.ParsedQuery) bool {
+	if record.CloseTimestamp < query.EarliestCloseTime || record.CloseTimestamp > query.LatestCloseTime {
 		return false
 	}
-	if query.workflowID != nil && record.WorkflowID != *query.workflowID {
+	if query.WorkflowID != nil && record.WorkflowID != *query.WorkflowID {
 		return false
 	}
-	if query.runID != nil && record.RunID != *query.runID {
+	if query.RunID != nil && record.RunID != *query.RunID {
 		return false
 	}
-	if query.workflowTypeName != nil && record.WorkflowTypeName != *query.workflowTypeName {
+	if query.WorkflowTypeName != nil && record.WorkflowTypeName != *query.WorkflowTypeName {
 		return false
 	}
-	if query.closeStatus != nil && record.CloseStatus != *query.closeStatus {
+	if query.CloseStatus != nil && record.CloseStatus != *query.CloseStatus {
 		return false
 	}
 	return true
@@ -22,6 +22,7 @@ package filestore
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -202,14 +203,14 @@ func (s *visibilityArchiverSuite) TestArchive_Success() {
 
 func (s *visibilityArchiverSuite) TestMatchQuery() {
 	testCases := []struct {
-		query       *parsedQuery
+		query       *archiver.ParsedQuery
 		record      *archiverproto.ArchiveVisibilityRequest
 		shouldMatch bool
 	}{
 		{
-			query: &parsedQuery{
-				earliestCloseTime: int64(1000),
-				latestCloseTime:   int64(12345),
+			query: &archiver.ParsedQuery{
+				EarliestCloseTime: int64(1000),
+				LatestCloseTime:   int64(12345),
 			},
 			record: &archiverproto.ArchiveVisibilityRequest{
 				CloseTimestamp: int64(1999),
@@ -217,9 +218,9 @@ func (s *visibilityArchiverSuite) TestMatchQuery() {
 			shouldMatch: true,
 		},
 		{
-			query: &parsedQuery{
-				earliestCloseTime: int64(1000),
-				latestCloseTime:   int64(12345),
+			query: &archiver.ParsedQuery{
+				EarliestCloseTime: int64(1000),
+				LatestCloseTime:   int64(12345),
 			},
 			record: &archiverproto.ArchiveVisibilityRequest{
 				CloseTimestamp: int64(999),
@@ -227,10 +228,10 @@ func (s *visibilityArchiverSuite) TestMatchQuery() {
 			shouldMatch: false,
 		},
 		{
-			query: &parsedQuery{
-				earliestCloseTime: int64(1000),
-				latestCloseTime:   int64(12345),
-				workflowID:        common.StringPtr("random workflowID"),
+			query: &archiver.ParsedQuery{
+				EarliestCloseTime: int64(1000),
+				LatestCloseTime:   int64(12345),
+				WorkflowID:        common.StringPtr("random workflowID"),
 			},
 			record: &archiverproto.ArchiveVisibilityRequest{
 				CloseTimestamp: int64(2000),
@@ -238,11 +239,11 @@ func (s *visibilityArchiverSuite) TestMatchQuery() {
 			shouldMatch: false,
 		},
 		{
-			query: &parsedQuery{
-				earliestCloseTime: int64(1000),
-				latestCloseTime:   int64(12345),
-				workflowID:        common.StringPtr("random workflowID"),
-				runID:             common.StringPtr("random runID"),
+			query: &archiver.ParsedQuery{
+				EarliestCloseTime: int64(1000),
+				LatestCloseTime:   int64(12345),
+				WorkflowID:        common.StringPtr("random workflowID"),
+				RunID:             common.StringPtr("random runID"),
 			},
 			record: &archiverproto.ArchiveVisibilityRequest{
 				CloseTimestamp:   int64(12345),
@@ -253,10 +254,10 @@ func (s *visibilityArchiverSuite) TestMatchQuery() {
 			shouldMatch: true,
 		},
 		{
-			query: &parsedQuery{
-				earliestCloseTime: int64(1000),
-				latestCloseTime:   int64(12345),
-				workflowTypeName:  common.StringPtr("some random type name"),
+			query: &archiver.ParsedQuery{
+				EarliestCloseTime: int64(1000),
+				LatestCloseTime:   int64(12345),
+				WorkflowTypeName:  common.StringPtr("some random type name"),
 			},
 			record: &archiverproto.ArchiveVisibilityRequest{
 				CloseTimestamp: int64(12345),
@@ -264,11 +265,11 @@ func (s *visibilityArchiverSuite) TestMatchQuery() {
 			shouldMatch: false,
 		},
 		{
-			query: &parsedQuery{
-				earliestCloseTime: int64(1000),
-				latestCloseTime:   int64(12345),
-				workflowTypeName:  common.StringPtr("some random type name"),
-				closeStatus:       toWorkflowExecutionCloseStatusPtr(enums.WorkflowExecutionCloseStatusContinuedAsNew),
+			query: &archiver.ParsedQuery{
+				EarliestCloseTime: int64(1000),
+				LatestCloseTime:   int64(12345),
+				WorkflowTypeName:  common.StringPtr("some random type name"),
+				CloseStatus:       toWorkflowExecutionCloseStatusPtr(enums.WorkflowExecutionCloseStatusContinuedAsNew),
 			},
 			record: &archiverproto.ArchiveVisibilityRequest{
 				CloseTimestamp:   int64(12345),
@@ -346,7 +347,7 @@ func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidRequest() {
 
 func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidQuery() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
-	mockParser := NewMockQueryParser(s.controller)
+	mockParser := archiver.NewMockQueryParser(s.controller)
 	mockParser.EXPECT().Parse(gomock.Any()).Return(nil, errors.New("invalid query"))
 	visibilityArchiver.queryParser = mockParser
 	response, err := visibilityArchiver.Query(context.Background(), s.testArchivalURI, &archiver.QueryVisibilityRequest{
@@ -360,10 +361,10 @@ func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidQuery() {
 
 func (s *visibilityArchiverSuite) TestQuery_Success_DirectoryNotExist() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
-	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-		earliestCloseTime: int64(1),
-		latestCloseTime:   int64(101),
+	mockParser := archiver.NewMockQueryParser(s.controller)
+	mockParser.EXPECT().Parse(gomock.Any()).Return(&archiver.ParsedQuery{
+		EarliestCloseTime: int64(1),
+		LatestCloseTime:   int64(101),
 	}, nil)
 	visibilityArchiver.queryParser = mockParser
 	request := &archiver.QueryVisibilityRequest{
@@ -380,10 +381,10 @@ func (s *visibilityArchiverSuite) TestQuery_Success_DirectoryNotExist() {
 
 func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidToken() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
-	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-		earliestCloseTime: int64(1),
-		latestCloseTime:   int64(101),
+	mockParser := archiver.NewMockQueryParser(s.controller)
+	mockParser.EXPECT().Parse(gomock.Any()).Return(&archiver.ParsedQuery{
+		EarliestCloseTime: int64(1),
+		LatestCloseTime:   int64(101),
 	}, nil)
 	visibilityArchiver.queryParser = mockParser
 	request := &archiver.QueryVisibilityRequest{
@@ -399,11 +400,11 @@ func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidToken() {
 
 func (s *visibilityArchiverSuite) TestQuery_Success_NoNextPageToken() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
-	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-		earliestCloseTime: int64(1),
-		latestCloseTime:   int64(10001),
-		workflowID:        common.StringPtr(testWorkflowID),
+	mockParser := archiver.NewMockQueryParser(s.controller)
+	mockParser.EXPECT().Parse(gomock.Any()).Return(&archiver.ParsedQuery{
+		EarliestCloseTime: int64(1),
+		LatestCloseTime:   int64(10001),
+		WorkflowID:        common.StringPtr(testWorkflowID),
 	}, nil)
 	visibilityArchiver.queryParser = mockParser
 	request := &archiver.QueryVisibilityRequest{
@@ -423,11 +424,11 @@ func (s *visibilityArchiverSuite) TestQuery_Success_NoNextPageToken() {
 
 func (s *visibilityArchiverSuite) TestQuery_Success_SmallPageSize() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
-	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-		earliestCloseTime: int64(1),
-		latestCloseTime:   int64(10001),
-		closeStatus:       toWorkflowExecutionCloseStatusPtr(enums.WorkflowExecutionCloseStatusFailed),
+	mockParser := archiver.NewMockQueryParser(s.controller)
+	mockParser.EXPECT().Parse(gomock.Any()).Return(&archiver.ParsedQuery{
+		EarliestCloseTime: int64(1),
+		LatestCloseTime:   int64(10001),
+		CloseStatus:       toWorkflowExecutionCloseStatusPtr(enums.WorkflowExecutionCloseStatusFailed),
 	}, nil).AnyTimes()
 	visibilityArchiver.queryParser = mockParser
 	request := &archiver.QueryVisibilityRequest{
@@ -460,11 +461,11 @@ func (s *visibilityArchiverSuite) TestArchiveAndQuery() {
 	defer os.RemoveAll(dir)
 
 	visibilityArchiver := s.newTestVisibilityArchiver()
-	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-		earliestCloseTime: int64(10),
-		latestCloseTime:   int64(10001),
-		closeStatus:       toWorkflowExecutionCloseStatusPtr(enums.WorkflowExecutionCloseStatusFailed),
+	mockParser := archiver.NewMockQueryParser(s.controller)
+	mockParser.EXPECT().Parse(gomock.Any()).Return(&archiver.ParsedQuery{
+		EarliestCloseTime: int64(10),
+		LatestCloseTime:   int64(10001),
+		CloseStatus:       toWorkflowExecutionCloseStatusPtr(enums.WorkflowExecutionCloseStatusFailed),
 	}, nil).AnyTimes()
 	visibilityArchiver.queryParser = mockParser
 	URI, err := archiver.NewURI("file://" + dir)
@@ -492,6 +493,48 @@ func (s *visibilityArchiverSuite) TestArchiveAndQuery() {
 	s.Equal(convertToExecutionInfo(s.visibilityRecords[1]), executions[1])
 }
 
+func (s *visibilityArchiverSuite) TestArchiveAndQuery_WithEnvelope() {
+	dir, err := ioutil.TempDir("", "TestArchiveAndQuery_WithEnvelope")
+	s.NoError(err)
+	defer os.RemoveAll(dir)
+
+	keyFilePath := path.Join(dir, "keys.yaml")
+	keyFile := "encryptionKeyID: key-1\nkeys:\n  key-1: \"" + base64.StdEncoding.EncodeToString(make([]byte, 32)) + "\"\n"
+	s.NoError(ioutil.WriteFile(keyFilePath, []byte(keyFile), testFileMode))
+	keyProvider, err := archiver.NewFileKeyProvider(keyFilePath)
+	s.NoError(err)
+	s.container.Envelope = archiver.NewBlobEnvelope(archiver.CompressionGzip, keyProvider)
+
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	mockParser := archiver.NewMockQueryParser(s.controller)
+	mockParser.EXPECT().Parse(gomock.Any()).Return(&archiver.ParsedQuery{
+		EarliestCloseTime: int64(10),
+		LatestCloseTime:   int64(10001),
+	}, nil).AnyTimes()
+	visibilityArchiver.queryParser = mockParser
+	URI, err := archiver.NewURI("file://" + dir)
+	s.NoError(err)
+	record := s.visibilityRecords[0]
+	s.NoError(visibilityArchiver.Archive(context.Background(), URI, record))
+
+	data, err := ioutil.ReadFile(path.Join(dir, testDomainID, constructVisibilityFilename(record.CloseTimestamp, record.RunID)))
+	s.NoError(err)
+	s.NotContains(string(data), record.WorkflowID)
+
+	request := &archiver.QueryVisibilityRequest{
+		DomainID: testDomainID,
+		PageSize: 10,
+		Query:    "parsed by mockParser",
+	}
+	response, err := visibilityArchiver.Query(context.Background(), URI, request)
+	s.NoError(err)
+	s.Equal([]*commonproto.WorkflowExecutionInfo{convertToExecutionInfo(record)}, response.Executions)
+
+	s.container.Envelope = nil
+	_, err = visibilityArchiver.Query(context.Background(), URI, request)
+	s.Error(err)
+}
+
 func (s *visibilityArchiverSuite) newTestVisibilityArchiver() *visibilityArchiver {
 	config := &config.FilestoreArchiver{
 		FileMode: testFileModeStr,
@@ -135,6 +135,11 @@ func (h *historyArchiver) Archive(ctx context.Context, URI archiver.URI, request
 		return errUploadNonRetriable
 	}
 
+	envelope := h.container.Envelope
+	if featureCatalog.Envelope != nil {
+		envelope = featureCatalog.Envelope
+	}
+
 	var totalUploadSize int64
 	historyIterator := h.historyIterator
 	var progress progress
@@ -168,6 +173,11 @@ func (h *historyArchiver) Archive(ctx context.Context, URI archiver.URI, request
 			logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(errEncodeHistory), tag.Error(err))
 			return errUploadNonRetriable
 		}
+		encodedHistoryPart, err = envelope.Seal(ctx, encodedHistoryPart)
+		if err != nil {
+			logger.Error(archiver.ArchiveTransientErrorMsg, tag.ArchivalArchiveFailReason(archiver.ErrReasonSealHistory), tag.Error(err))
+			return err
+		}
 
 		filename := constructHistoryFilenameMultipart(request.DomainID, request.WorkflowID, request.RunID, request.CloseFailoverVersion, part)
 		if exist, _ := h.gcloudStorage.Exist(ctx, URI, filename); !exist {
@@ -241,6 +251,11 @@ outer:
 			return nil, serviceerror.NewInternal("Fail retrieving history file: " + URI.String() + "/" + filename)
 		}
 
+		encodedHistoryBatches, err = h.container.Envelope.Open(ctx, encodedHistoryBatches)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+
 		batches, err := encoder.DecodeHistories(encodedHistoryBatches)
 		if err != nil {
 			return nil, serviceerror.NewInternal(err.Error())
@@ -126,6 +126,16 @@ func (v *visibilityArchiver) Archive(ctx context.Context, URI archiver.URI, requ
 		return err
 	}
 
+	envelope := v.container.Envelope
+	if featureCatalog.Envelope != nil {
+		envelope = featureCatalog.Envelope
+	}
+	encodedVisibilityRecord, err = envelope.Seal(ctx, encodedVisibilityRecord)
+	if err != nil {
+		logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(archiver.ErrReasonSealVisibilityRecord), tag.Error(err))
+		return err
+	}
+
 	// The filename has the format: closeTimestamp_hash(runID).visibility
 	// This format allows the archiver to sort all records without reading the file contents
 	filename := constructVisibilityFilename(request.DomainID, request.WorkflowTypeName, request.WorkflowID, request.RunID, indexKeyCloseTimeout, request.CloseTimestamp)
@@ -218,6 +228,11 @@ func (v *visibilityArchiver) query(ctx context.Context, URI archiver.URI, reques
 			return nil, &serviceerror.InvalidArgument{Message: err.Error()}
 		}
 
+		encodedRecord, err = v.container.Envelope.Open(ctx, encodedRecord)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+
 		record, err := decodeVisibilityRecord(encodedRecord)
 		if err != nil {
 			return nil, &serviceerror.InvalidArgument{Message: err.Error()}
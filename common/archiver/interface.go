@@ -69,6 +69,9 @@ type (
 		MetricsClient    metrics.Client
 		ClusterMetadata  cluster.Metadata
 		DomainCache      cache.DomainCache
+		// Envelope seals archived histories unless overridden by the archive options,
+		// and opens archived histories on read
+		Envelope *BlobEnvelope
 	}
 
 	// HistoryArchiver is used to archive history and read archived history
@@ -84,6 +87,9 @@ type (
 		MetricsClient   metrics.Client
 		ClusterMetadata cluster.Metadata
 		DomainCache     cache.DomainCache
+		// Envelope seals archived visibility records unless overridden by the archive options,
+		// and opens archived visibility records on query
+		Envelope *BlobEnvelope
 	}
 
 	// QueryVisibilityRequest is the request to query archived visibility records
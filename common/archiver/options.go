@@ -37,6 +37,7 @@ type (
 	ArchiveFeatureCatalog struct {
 		ProgressManager   ProgressManager
 		NonRetriableError NonRetriableError
+		Envelope          *BlobEnvelope
 	}
 
 	// NonRetriableError returns an error indicating archiver has encountered an non-retriable error
@@ -95,3 +96,12 @@ func GetNonRetriableErrorOption(nonRetryableErr error) ArchiveOption {
 		}
 	}
 }
+
+// GetEnvelopeOption returns an ArchiveOption for sealing archived blobs with the given envelope instead of
+// the envelope of the bootstrap container. The envelope of the bootstrap container must still be able to
+// open the blobs, so it needs access to the same keys.
+func GetEnvelopeOption(envelope *BlobEnvelope) ArchiveOption {
+	return func(catalog *ArchiveFeatureCatalog) {
+		catalog.Envelope = envelope
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/temporalio/temporal/common/archiver/gcloud"
 
 	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/archiver/azblob"
 	"github.com/temporalio/temporal/common/archiver/filestore"
 	"github.com/temporalio/temporal/common/archiver/s3store"
 	"github.com/temporalio/temporal/common/service/config"
@@ -151,6 +152,18 @@ func (p *archiverProvider) GetHistoryArchiver(scheme, serviceName string) (histo
 			return nil, ErrArchiverConfigNotFound
 		}
 		historyArchiver, err = s3store.NewHistoryArchiver(container, p.historyArchiverConfigs.S3store)
+
+	case s3store.CompatibleURIScheme:
+		if p.historyArchiverConfigs.S3Compatible == nil {
+			return nil, ErrArchiverConfigNotFound
+		}
+		historyArchiver, err = s3store.NewCompatibleHistoryArchiver(container, p.historyArchiverConfigs.S3Compatible)
+
+	case azblob.URIScheme:
+		if p.historyArchiverConfigs.Azblob == nil {
+			return nil, ErrArchiverConfigNotFound
+		}
+		historyArchiver, err = azblob.NewHistoryArchiver(container, p.historyArchiverConfigs.Azblob)
 	default:
 		return nil, ErrUnknownScheme
 	}
@@ -191,16 +204,31 @@ func (p *archiverProvider) GetVisibilityArchiver(scheme, serviceName string) (ar
 			return nil, ErrArchiverConfigNotFound
 		}
 		visibilityArchiver, err = filestore.NewVisibilityArchiver(container, p.visibilityArchiverConfigs.Filestore)
+	case filestore.IndexedURIScheme:
+		if p.visibilityArchiverConfigs.IndexedFilestore == nil {
+			return nil, ErrArchiverConfigNotFound
+		}
+		visibilityArchiver, err = filestore.NewIndexedVisibilityArchiver(container, p.visibilityArchiverConfigs.IndexedFilestore)
 	case s3store.URIScheme:
 		if p.visibilityArchiverConfigs.S3store == nil {
 			return nil, ErrArchiverConfigNotFound
 		}
 		visibilityArchiver, err = s3store.NewVisibilityArchiver(container, p.visibilityArchiverConfigs.S3store)
+	case s3store.CompatibleURIScheme:
+		if p.visibilityArchiverConfigs.S3Compatible == nil {
+			return nil, ErrArchiverConfigNotFound
+		}
+		visibilityArchiver, err = s3store.NewCompatibleVisibilityArchiver(container, p.visibilityArchiverConfigs.S3Compatible)
 	case gcloud.URIScheme:
 		if p.visibilityArchiverConfigs.Gstorage == nil {
 			return nil, ErrArchiverConfigNotFound
 		}
 		visibilityArchiver, err = gcloud.NewVisibilityArchiver(container, p.visibilityArchiverConfigs.Gstorage)
+	case azblob.URIScheme:
+		if p.visibilityArchiverConfigs.Azblob == nil {
+			return nil, ErrArchiverConfigNotFound
+		}
+		visibilityArchiver, err = azblob.NewVisibilityArchiver(container, p.visibilityArchiverConfigs.Azblob)
 
 	default:
 		return nil, ErrUnknownScheme
@@ -18,9 +18,9 @@
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 // THE SOFTWARE.
 
-//go:generate mockgen -copyright_file ../../../LICENSE -package $GOPACKAGE -source queryParser.go -destination queryParser_mock.go -mock_names Interface=MockQueryParser
+//go:generate mockgen -copyright_file ../../LICENSE -package $GOPACKAGE -source queryParser.go -destination queryParser_mock.go -mock_names Interface=MockQueryParser
 
-package filestore
+package archiver
 
 import (
 	"errors"
@@ -38,29 +38,30 @@ import (
 type (
 	// QueryParser parses a limited SQL where clause into a struct
 	QueryParser interface {
-		Parse(query string) (*parsedQuery, error)
+		Parse(query string) (*ParsedQuery, error)
 	}
 
 	queryParser struct{}
 
-	parsedQuery struct {
-		earliestCloseTime int64
-		latestCloseTime   int64
-		workflowID        *string
-		runID             *string
-		workflowTypeName  *string
-		closeStatus       *enums.WorkflowExecutionCloseStatus
-		emptyResult       bool
+	// ParsedQuery holds the filters of a visibility query
+	ParsedQuery struct {
+		EarliestCloseTime int64
+		LatestCloseTime   int64
+		WorkflowID        *string
+		RunID             *string
+		WorkflowTypeName  *string
+		CloseStatus       *enums.WorkflowExecutionCloseStatus
+		EmptyResult       bool
 	}
 )
 
 // All allowed fields for filtering
 const (
-	WorkflowID   = "WorkflowID"
-	RunID        = "RunID"
-	WorkflowType = "WorkflowType"
-	CloseTime    = "CloseTime"
-	CloseStatus  = "CloseStatus"
+	WorkflowIDFilter   = "WorkflowID"
+	RunIDFilter        = "RunID"
+	WorkflowTypeFilter = "WorkflowType"
+	CloseTimeFilter    = "CloseTime"
+	CloseStatusFilter  = "CloseStatus"
 )
 
 const (
@@ -69,20 +70,20 @@ const (
 	defaultDateTimeFormat = time.RFC3339
 )
 
-// NewQueryParser creates a new query parser for filestore
+// NewQueryParser creates a new parser for the visibility queries of the archivers
 func NewQueryParser() QueryParser {
 	return &queryParser{}
 }
 
-func (p *queryParser) Parse(query string) (*parsedQuery, error) {
+func (p *queryParser) Parse(query string) (*ParsedQuery, error) {
 	stmt, err := sqlparser.Parse(fmt.Sprintf(queryTemplate, query))
 	if err != nil {
 		return nil, err
 	}
 	whereExpr := stmt.(*sqlparser.Select).Where.Expr
-	parsedQuery := &parsedQuery{
-		earliestCloseTime: 0,
-		latestCloseTime:   time.Now().UnixNano(),
+	parsedQuery := &ParsedQuery{
+		EarliestCloseTime: 0,
+		LatestCloseTime:   time.Now().UnixNano(),
 	}
 	if err := p.convertWhereExpr(whereExpr, parsedQuery); err != nil {
 		return nil, err
@@ -90,7 +91,7 @@ func (p *queryParser) Parse(query string) (*parsedQuery, error) {
 	return parsedQuery, nil
 }
 
-func (p *queryParser) convertWhereExpr(expr sqlparser.Expr, parsedQuery *parsedQuery) error {
+func (p *queryParser) convertWhereExpr(expr sqlparser.Expr, parsedQuery *ParsedQuery) error {
 	if expr == nil {
 		return errors.New("where expression is nil")
 	}
@@ -107,18 +108,18 @@ func (p *queryParser) convertWhereExpr(expr sqlparser.Expr, parsedQuery *parsedQ
 	}
 }
 
-func (p *queryParser) convertParenExpr(parenExpr *sqlparser.ParenExpr, parsedQuery *parsedQuery) error {
+func (p *queryParser) convertParenExpr(parenExpr *sqlparser.ParenExpr, parsedQuery *ParsedQuery) error {
 	return p.convertWhereExpr(parenExpr.Expr, parsedQuery)
 }
 
-func (p *queryParser) convertAndExpr(andExpr *sqlparser.AndExpr, parsedQuery *parsedQuery) error {
+func (p *queryParser) convertAndExpr(andExpr *sqlparser.AndExpr, parsedQuery *ParsedQuery) error {
 	if err := p.convertWhereExpr(andExpr.Left, parsedQuery); err != nil {
 		return err
 	}
 	return p.convertWhereExpr(andExpr.Right, parsedQuery)
 }
 
-func (p *queryParser) convertComparisonExpr(compExpr *sqlparser.ComparisonExpr, parsedQuery *parsedQuery) error {
+func (p *queryParser) convertComparisonExpr(compExpr *sqlparser.ComparisonExpr, parsedQuery *ParsedQuery) error {
 	colName, ok := compExpr.Left.(*sqlparser.ColName)
 	if !ok {
 		return fmt.Errorf("invalid filter name: %s", sqlparser.String(compExpr.Left))
@@ -132,63 +133,63 @@ func (p *queryParser) convertComparisonExpr(compExpr *sqlparser.ComparisonExpr,
 	valStr := sqlparser.String(valExpr)
 
 	switch colNameStr {
-	case WorkflowID:
+	case WorkflowIDFilter:
 		val, err := extractStringValue(valStr)
 		if err != nil {
 			return err
 		}
 		if op != "=" {
-			return fmt.Errorf("only operation = is support for %s", WorkflowID)
+			return fmt.Errorf("only operation = is support for %s", WorkflowIDFilter)
 		}
-		if parsedQuery.workflowID != nil && *parsedQuery.workflowID != val {
-			parsedQuery.emptyResult = true
+		if parsedQuery.WorkflowID != nil && *parsedQuery.WorkflowID != val {
+			parsedQuery.EmptyResult = true
 			return nil
 		}
-		parsedQuery.workflowID = common.StringPtr(val)
-	case RunID:
+		parsedQuery.WorkflowID = common.StringPtr(val)
+	case RunIDFilter:
 		val, err := extractStringValue(valStr)
 		if err != nil {
 			return err
 		}
 		if op != "=" {
-			return fmt.Errorf("only operation = is support for %s", RunID)
+			return fmt.Errorf("only operation = is support for %s", RunIDFilter)
 		}
-		if parsedQuery.runID != nil && *parsedQuery.runID != val {
-			parsedQuery.emptyResult = true
+		if parsedQuery.RunID != nil && *parsedQuery.RunID != val {
+			parsedQuery.EmptyResult = true
 			return nil
 		}
-		parsedQuery.runID = common.StringPtr(val)
-	case WorkflowType:
+		parsedQuery.RunID = common.StringPtr(val)
+	case WorkflowTypeFilter:
 		val, err := extractStringValue(valStr)
 		if err != nil {
 			return err
 		}
 		if op != "=" {
-			return fmt.Errorf("only operation = is support for %s", WorkflowType)
+			return fmt.Errorf("only operation = is support for %s", WorkflowTypeFilter)
 		}
-		if parsedQuery.workflowTypeName != nil && *parsedQuery.workflowTypeName != val {
-			parsedQuery.emptyResult = true
+		if parsedQuery.WorkflowTypeName != nil && *parsedQuery.WorkflowTypeName != val {
+			parsedQuery.EmptyResult = true
 			return nil
 		}
-		parsedQuery.workflowTypeName = common.StringPtr(val)
-	case CloseStatus:
+		parsedQuery.WorkflowTypeName = common.StringPtr(val)
+	case CloseStatusFilter:
 		val, err := extractStringValue(valStr)
 		if err != nil {
 			return err
 		}
 		if op != "=" {
-			return fmt.Errorf("only operation = is support for %s", CloseStatus)
+			return fmt.Errorf("only operation = is support for %s", CloseStatusFilter)
 		}
 		status, err := convertStatusStr(val)
 		if err != nil {
 			return err
 		}
-		if parsedQuery.closeStatus != nil && *parsedQuery.closeStatus != status {
-			parsedQuery.emptyResult = true
+		if parsedQuery.CloseStatus != nil && *parsedQuery.CloseStatus != status {
+			parsedQuery.EmptyResult = true
 			return nil
 		}
-		parsedQuery.closeStatus = &status
-	case CloseTime:
+		parsedQuery.CloseStatus = &status
+	case CloseTimeFilter:
 		timestamp, err := convertToTimestamp(valStr)
 		if err != nil {
 			return err
@@ -201,7 +202,7 @@ func (p *queryParser) convertComparisonExpr(compExpr *sqlparser.ComparisonExpr,
 	return nil
 }
 
-func (p *queryParser) convertCloseTime(timestamp int64, op string, parsedQuery *parsedQuery) error {
+func (p *queryParser) convertCloseTime(timestamp int64, op string, parsedQuery *ParsedQuery) error {
 	switch op {
 	case "=":
 		if err := p.convertCloseTime(timestamp, ">=", parsedQuery); err != nil {
@@ -211,13 +212,13 @@ func (p *queryParser) convertCloseTime(timestamp int64, op string, parsedQuery *
 			return err
 		}
 	case "<":
-		parsedQuery.latestCloseTime = common.MinInt64(parsedQuery.latestCloseTime, timestamp-1)
+		parsedQuery.LatestCloseTime = common.MinInt64(parsedQuery.LatestCloseTime, timestamp-1)
 	case "<=":
-		parsedQuery.latestCloseTime = common.MinInt64(parsedQuery.latestCloseTime, timestamp)
+		parsedQuery.LatestCloseTime = common.MinInt64(parsedQuery.LatestCloseTime, timestamp)
 	case ">":
-		parsedQuery.earliestCloseTime = common.MaxInt64(parsedQuery.earliestCloseTime, timestamp+1)
+		parsedQuery.EarliestCloseTime = common.MaxInt64(parsedQuery.EarliestCloseTime, timestamp+1)
 	case ">=":
-		parsedQuery.earliestCloseTime = common.MaxInt64(parsedQuery.earliestCloseTime, timestamp)
+		parsedQuery.EarliestCloseTime = common.MaxInt64(parsedQuery.EarliestCloseTime, timestamp)
 	default:
 		return fmt.Errorf("operator %s is not supported for close time", op)
 	}
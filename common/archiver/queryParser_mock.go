@@ -24,13 +24,12 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: queryParser.go
 
-// Package filestore is a generated GoMock package.
-package filestore
+// Package archiver is a generated GoMock package.
+package archiver
 
 import (
-	reflect "reflect"
-
 	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
 )
 
 // MockQueryParser is a mock of QueryParser interface
@@ -57,10 +56,10 @@ func (m *MockQueryParser) EXPECT() *MockQueryParserMockRecorder {
 }
 
 // Parse mocks base method
-func (m *MockQueryParser) Parse(query string) (*parsedQuery, error) {
+func (m *MockQueryParser) Parse(query string) (*ParsedQuery, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Parse", query)
-	ret0, _ := ret[0].(*parsedQuery)
+	ret0, _ := ret[0].(*ParsedQuery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -0,0 +1,205 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// +build archivalintegration
+
+// to run locally, start MinIO (see docker/docker-compose-archival.yml),
+// then run cmd `go test -v ./common/archiver/s3store -run TestCompatibleEmulatorSuite -tags archivalintegration`
+package s3store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"go.uber.org/zap"
+
+	archiverproto "github.com/temporalio/temporal/.gen/proto/archiver"
+	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/log/loggerimpl"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/service/config"
+	"github.com/temporalio/temporal/environment"
+)
+
+const (
+	testEmulatorBucket          = "temporal-archival"
+	testEmulatorAccessKeyID     = "minioadmin"
+	testEmulatorSecretAccessKey = "minioadmin"
+)
+
+type compatibleEmulatorSuite struct {
+	*require.Assertions
+	suite.Suite
+
+	config     *config.S3Archiver
+	controller *gomock.Controller
+	scope      tally.TestScope
+	URI        archiver.URI
+}
+
+func TestCompatibleEmulatorSuite(t *testing.T) {
+	suite.Run(t, new(compatibleEmulatorSuite))
+}
+
+func (s *compatibleEmulatorSuite) SetupSuite() {
+	s.config = &config.S3Archiver{
+		Endpoint:         aws.String(environment.GetS3CompatibleEndpoint()),
+		S3ForcePathStyle: true,
+		DisableSSL:       true,
+		AccessKeyID:      testEmulatorAccessKeyID,
+		SecretAccessKey:  testEmulatorSecretAccessKey,
+	}
+
+	s3cli, err := newS3Client(s.config)
+	s.Require().NoError(err)
+	_, err = s3cli.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(testEmulatorBucket)})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeBucketAlreadyOwnedByYou {
+		err = nil
+	}
+	s.Require().NoError(err)
+
+	// every run archives under its own path so that reruns against a long lived emulator do not interfere
+	s.URI, err = archiver.NewURI(CompatibleURIScheme + "://" + testEmulatorBucket + "/" + uuid.New())
+	s.Require().NoError(err)
+}
+
+func (s *compatibleEmulatorSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.scope = tally.NewTestScope("test", nil)
+}
+
+func (s *compatibleEmulatorSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *compatibleEmulatorSuite) TestValidateURI() {
+	historyArchiver, err := NewCompatibleHistoryArchiver(s.newHistoryBootstrapContainer(), s.config)
+	s.NoError(err)
+	s.NoError(historyArchiver.ValidateURI(s.URI))
+
+	URI, err := archiver.NewURI(CompatibleURIScheme + "://bucket-not-exist")
+	s.NoError(err)
+	s.Error(historyArchiver.ValidateURI(URI))
+}
+
+func (s *compatibleEmulatorSuite) TestArchiveAndGetHistory() {
+	batches := []*archiverproto.HistoryBlob{
+		{
+			Header: &archiverproto.HistoryBlobHeader{IsLast: false},
+			Body:   newEmulatorHistoryBody(testNextEventID-3, testNextEventID-2),
+		},
+		{
+			Header: &archiverproto.HistoryBlobHeader{IsLast: true},
+			Body:   newEmulatorHistoryBody(testNextEventID - 1),
+		},
+	}
+	historyIterator := archiver.NewMockHistoryIterator(s.controller)
+	gomock.InOrder(
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next().Return(batches[0], nil),
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next().Return(batches[1], nil),
+		historyIterator.EXPECT().HasNext().Return(false),
+	)
+
+	historyArchiver, err := newHistoryArchiver(s.newHistoryBootstrapContainer(), s.config, CompatibleURIScheme, historyIterator)
+	s.NoError(err)
+	err = historyArchiver.Archive(context.Background(), s.URI, &archiver.ArchiveHistoryRequest{
+		DomainID:             testDomainID,
+		DomainName:           testDomainName,
+		WorkflowID:           testWorkflowID,
+		RunID:                testRunID,
+		BranchToken:          testBranchToken,
+		NextEventID:          testNextEventID,
+		CloseFailoverVersion: testCloseFailoverVersion,
+	})
+	s.NoError(err)
+
+	response, err := historyArchiver.Get(context.Background(), s.URI, &archiver.GetHistoryRequest{
+		DomainID:   testDomainID,
+		WorkflowID: testWorkflowID,
+		RunID:      testRunID,
+		PageSize:   testPageSize,
+	})
+	s.NoError(err)
+	s.Nil(response.NextPageToken)
+	s.Equal(append(batches[0].Body, batches[1].Body...), response.HistoryBatches)
+}
+
+func (s *compatibleEmulatorSuite) TestArchiveAndQueryVisibility() {
+	visibilityArchiver, err := NewCompatibleVisibilityArchiver(&archiver.VisibilityBootstrapContainer{
+		Logger:        loggerimpl.NewLogger(zap.NewNop()),
+		MetricsClient: metrics.NewClient(s.scope, metrics.VisibilityArchiverScope),
+	}, s.config)
+	s.NoError(err)
+
+	record := &archiverproto.ArchiveVisibilityRequest{
+		DomainID:         testDomainID,
+		DomainName:       testDomainName,
+		WorkflowID:       testWorkflowID,
+		RunID:            testRunID,
+		WorkflowTypeName: testWorkflowTypeName,
+		StartTimestamp:   1,
+		CloseTimestamp:   2,
+		CloseStatus:      enums.WorkflowExecutionCloseStatusCompleted,
+		HistoryLength:    testNextEventID,
+	}
+	s.NoError(visibilityArchiver.Archive(context.Background(), s.URI, record))
+
+	response, err := visibilityArchiver.Query(context.Background(), s.URI, &archiver.QueryVisibilityRequest{
+		DomainID: testDomainID,
+		PageSize: testPageSize,
+		Query:    "WorkflowID = '" + testWorkflowID + "'",
+	})
+	s.NoError(err)
+	s.Len(response.Executions, 1)
+	s.Equal(convertToExecutionInfo(record), response.Executions[0])
+}
+
+func (s *compatibleEmulatorSuite) newHistoryBootstrapContainer() *archiver.HistoryBootstrapContainer {
+	return &archiver.HistoryBootstrapContainer{
+		Logger:        loggerimpl.NewLogger(zap.NewNop()),
+		MetricsClient: metrics.NewClient(s.scope, metrics.HistoryArchiverScope),
+	}
+}
+
+func newEmulatorHistoryBody(eventIDs ...int64) []*commonproto.History {
+	history := &commonproto.History{}
+	for _, eventID := range eventIDs {
+		history.Events = append(history.Events, &commonproto.HistoryEvent{
+			EventId:   eventID,
+			Timestamp: time.Now().UnixNano(),
+			Version:   testCloseFailoverVersion,
+		})
+	}
+	return []*commonproto.History{history}
+}
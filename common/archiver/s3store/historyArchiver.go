@@ -33,7 +33,6 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"go.temporal.io/temporal-proto/serviceerror"
@@ -51,7 +50,10 @@ import (
 
 const (
 	// URIScheme is the scheme for the s3 implementation
-	URIScheme               = "s3"
+	URIScheme = "s3"
+	// CompatibleURIScheme is the scheme for the implementation backed by S3 compatible stores like MinIO
+	CompatibleURIScheme = "s3compatible"
+
 	errEncodeHistory        = "failed to encode history batches"
 	errWriteKey             = "failed to write history to s3"
 	defaultBlobstoreTimeout = 60 * time.Second
@@ -62,12 +64,14 @@ var (
 	errNoBucketSpecified = errors.New("no bucket specified")
 	errBucketNotExists   = errors.New("requested bucket does not exist")
 	errEmptyAwsRegion    = errors.New("empty aws region")
+	errEmptyEndpoint     = errors.New("empty s3 compatible endpoint")
 )
 
 type (
 	historyArchiver struct {
 		container *archiver.HistoryBootstrapContainer
 		s3cli     s3iface.S3API
+		scheme    string
 		// only set in test code
 		historyIterator archiver.HistoryIterator
 		config          *config.S3Archiver
@@ -91,30 +95,38 @@ func NewHistoryArchiver(
 	container *archiver.HistoryBootstrapContainer,
 	config *config.S3Archiver,
 ) (archiver.HistoryArchiver, error) {
-	return newHistoryArchiver(container, config, nil)
+	if len(config.Region) == 0 {
+		return nil, errEmptyAwsRegion
+	}
+	return newHistoryArchiver(container, config, URIScheme, nil)
+}
+
+// NewCompatibleHistoryArchiver creates a new archiver.HistoryArchiver based on an S3 compatible store
+func NewCompatibleHistoryArchiver(
+	container *archiver.HistoryBootstrapContainer,
+	config *config.S3Archiver,
+) (archiver.HistoryArchiver, error) {
+	if config.Endpoint == nil || len(*config.Endpoint) == 0 {
+		return nil, errEmptyEndpoint
+	}
+	return newHistoryArchiver(container, config, CompatibleURIScheme, nil)
 }
 
 func newHistoryArchiver(
 	container *archiver.HistoryBootstrapContainer,
 	config *config.S3Archiver,
+	scheme string,
 	historyIterator archiver.HistoryIterator,
 ) (*historyArchiver, error) {
-	if len(config.Region) == 0 {
-		return nil, errEmptyAwsRegion
-	}
-	s3Config := &aws.Config{
-		Endpoint:         config.Endpoint,
-		Region:           aws.String(config.Region),
-		S3ForcePathStyle: aws.Bool(config.S3ForcePathStyle),
-	}
-	sess, err := session.NewSession(s3Config)
+	s3cli, err := newS3Client(config)
 	if err != nil {
 		return nil, err
 	}
 
 	return &historyArchiver{
 		container:       container,
-		s3cli:           s3.New(sess),
+		s3cli:           s3cli,
+		scheme:          scheme,
 		historyIterator: historyIterator,
 	}, nil
 }
@@ -143,7 +155,7 @@ func (h *historyArchiver) Archive(
 
 	logger := archiver.TagLoggerWithArchiveHistoryRequestAndURI(h.container.Logger, request, URI.String())
 
-	if err := softValidateURI(URI); err != nil {
+	if err := softValidateURI(URI, h.scheme); err != nil {
 		logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(archiver.ErrReasonInvalidURI), tag.Error(err))
 		return err
 	}
@@ -153,6 +165,11 @@ func (h *historyArchiver) Archive(
 		return err
 	}
 
+	envelope := h.container.Envelope
+	if featureCatalog.Envelope != nil {
+		envelope = featureCatalog.Envelope
+	}
+
 	var progress uploadProgress
 	historyIterator := h.historyIterator
 	if historyIterator == nil { // will only be set by testing code
@@ -181,6 +198,11 @@ func (h *historyArchiver) Archive(
 			logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(errEncodeHistory), tag.Error(err))
 			return err
 		}
+		encodedHistoryBlob, err = envelope.Seal(ctx, encodedHistoryBlob)
+		if err != nil {
+			logger.Error(archiver.ArchiveNonRetriableErrorMsg, tag.ArchivalArchiveFailReason(archiver.ErrReasonSealHistory), tag.Error(err))
+			return err
+		}
 		key := constructHistoryKey(URI.Path(), request.DomainID, request.WorkflowID, request.RunID, request.CloseFailoverVersion, progress.BatchIdx)
 
 		exists, err := keyExists(ctx, h.s3cli, URI, key)
@@ -260,7 +282,7 @@ func (h *historyArchiver) Get(
 	URI archiver.URI,
 	request *archiver.GetHistoryRequest,
 ) (*archiver.GetHistoryResponse, error) {
-	if err := softValidateURI(URI); err != nil {
+	if err := softValidateURI(URI, h.scheme); err != nil {
 		return nil, serviceerror.NewInvalidArgument(archiver.ErrInvalidURI.Error())
 	}
 
@@ -312,6 +334,11 @@ func (h *historyArchiver) Get(
 			}
 		}
 
+		encodedRecord, err = h.container.Envelope.Open(ctx, encodedRecord)
+		if err != nil {
+			return nil, &serviceerror.Internal{Message: err.Error()}
+		}
+
 		historyBlob := archiverproto.HistoryBlob{}
 		err = encoder.Decode(encodedRecord, &historyBlob)
 		if err != nil {
@@ -341,7 +368,7 @@ func (h *historyArchiver) Get(
 }
 
 func (h *historyArchiver) ValidateURI(URI archiver.URI) error {
-	err := softValidateURI(URI)
+	err := softValidateURI(URI, h.scheme)
 	if err != nil {
 		return err
 	}
@@ -53,6 +53,7 @@ import (
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/loggerimpl"
 	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/service/config"
 )
 
 const (
@@ -252,6 +253,53 @@ func (s *historyArchiverSuite) TestValidateURI() {
 	}
 }
 
+func (s *historyArchiverSuite) TestValidateURI_Compatible() {
+	testCases := []struct {
+		URI         string
+		expectedErr error
+	}{
+		{
+			URI:         testBucketURI,
+			expectedErr: archiver.ErrURISchemeMismatch,
+		},
+		{
+			URI:         "s3compatible://",
+			expectedErr: errNoBucketSpecified,
+		},
+		{
+			URI:         "s3compatible://test-bucket/a/b/c",
+			expectedErr: nil,
+		},
+	}
+
+	s.s3cli.On("HeadBucketWithContext", mock.Anything, mock.MatchedBy(func(input *s3.HeadBucketInput) bool {
+		return *input.Bucket != s.testArchivalURI.Hostname()
+	})).Return(nil, awserr.New("NotFound", "", nil))
+	s.s3cli.On("HeadBucketWithContext", mock.Anything, mock.Anything).Return(&s3.HeadBucketOutput{}, nil)
+
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	historyArchiver.scheme = CompatibleURIScheme
+	for _, tc := range testCases {
+		URI, err := archiver.NewURI(tc.URI)
+		s.NoError(err)
+		s.Equal(tc.expectedErr, historyArchiver.ValidateURI(URI))
+	}
+}
+
+func (s *historyArchiverSuite) TestNewCompatibleHistoryArchiver() {
+	_, err := NewCompatibleHistoryArchiver(s.container, &config.S3Archiver{})
+	s.Equal(errEmptyEndpoint, err)
+
+	compatibleArchiver, err := NewCompatibleHistoryArchiver(s.container, &config.S3Archiver{
+		Endpoint:         common.StringPtr("http://127.0.0.1:9000"),
+		S3ForcePathStyle: true,
+		AccessKeyID:      "minioadmin",
+		SecretAccessKey:  "minioadmin",
+	})
+	s.NoError(err)
+	s.Equal(CompatibleURIScheme, compatibleArchiver.(*historyArchiver).scheme)
+}
+
 func (s *historyArchiverSuite) TestArchive_Fail_InvalidURI() {
 	historyArchiver := s.newTestHistoryArchiver(nil)
 	request := &archiver.ArchiveHistoryRequest{
@@ -633,6 +681,7 @@ func (s *historyArchiverSuite) newTestHistoryArchiver(historyIterator archiver.H
 	archiver := &historyArchiver{
 		container:       s.container,
 		s3cli:           s.s3cli,
+		scheme:          URIScheme,
 		historyIterator: historyIterator,
 	}
 	return archiver
@@ -31,6 +31,8 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/gogo/protobuf/types"
@@ -41,6 +43,7 @@ import (
 	archiverproto "github.com/temporalio/temporal/.gen/proto/archiver"
 	"github.com/temporalio/temporal/common/archiver"
 	"github.com/temporalio/temporal/common/codec"
+	"github.com/temporalio/temporal/common/service/config"
 )
 
 // encoding & decoding util
@@ -81,8 +84,8 @@ func serializeQueryVisibilityToken(token string) []byte {
 }
 
 // Only validates the scheme and buckets are passed
-func softValidateURI(URI archiver.URI) error {
-	if URI.Scheme() != URIScheme {
+func softValidateURI(URI archiver.URI, scheme string) error {
+	if URI.Scheme() != scheme {
 		return archiver.ErrURISchemeMismatch
 	}
 	if len(URI.Hostname()) == 0 {
@@ -91,6 +94,30 @@ func softValidateURI(URI archiver.URI) error {
 	return nil
 }
 
+// S3 compatible stores usually ignore the region, but the sdk requires one to sign requests
+const defaultCompatibleRegion = "us-east-1"
+
+func newS3Client(config *config.S3Archiver) (s3iface.S3API, error) {
+	region := config.Region
+	if len(region) == 0 {
+		region = defaultCompatibleRegion
+	}
+	s3Config := &aws.Config{
+		Endpoint:         config.Endpoint,
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(config.S3ForcePathStyle),
+		DisableSSL:       aws.Bool(config.DisableSSL),
+	}
+	if len(config.AccessKeyID) != 0 {
+		s3Config.Credentials = credentials.NewStaticCredentials(config.AccessKeyID, config.SecretAccessKey, "")
+	}
+	sess, err := session.NewSession(s3Config)
+	if err != nil {
+		return nil, err
+	}
+	return s3.New(sess), nil
+}
+
 func bucketExists(ctx context.Context, s3cli s3iface.S3API, URI archiver.URI) error {
 	ctx, cancel := ensureContextTimeout(ctx)
 	defer cancel()
@@ -24,7 +24,6 @@ import (
 	"context"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"go.temporal.io/temporal-proto/serviceerror"
@@ -40,6 +39,7 @@ type (
 	visibilityArchiver struct {
 		container   *archiver.VisibilityBootstrapContainer
 		s3cli       s3iface.S3API
+		scheme      string
 		queryParser QueryParser
 	}
 
@@ -71,24 +71,33 @@ func NewVisibilityArchiver(
 	container *archiver.VisibilityBootstrapContainer,
 	config *config.S3Archiver,
 ) (archiver.VisibilityArchiver, error) {
-	return newVisibilityArchiver(container, config)
+	return newVisibilityArchiver(container, config, URIScheme)
 }
 
-func newVisibilityArchiver(
+// NewCompatibleVisibilityArchiver creates a new archiver.VisibilityArchiver based on an S3 compatible store
+func NewCompatibleVisibilityArchiver(
 	container *archiver.VisibilityBootstrapContainer,
-	config *config.S3Archiver) (*visibilityArchiver, error) {
-	s3Config := &aws.Config{
-		Endpoint:         config.Endpoint,
-		Region:           aws.String(config.Region),
-		S3ForcePathStyle: aws.Bool(config.S3ForcePathStyle),
+	config *config.S3Archiver,
+) (archiver.VisibilityArchiver, error) {
+	if config.Endpoint == nil || len(*config.Endpoint) == 0 {
+		return nil, errEmptyEndpoint
 	}
-	sess, err := session.NewSession(s3Config)
+	return newVisibilityArchiver(container, config, CompatibleURIScheme)
+}
+
+func newVisibilityArchiver(
+	container *archiver.VisibilityBootstrapContainer,
+	config *config.S3Archiver,
+	scheme string,
+) (*visibilityArchiver, error) {
+	s3cli, err := newS3Client(config)
 	if err != nil {
 		return nil, err
 	}
 	return &visibilityArchiver{
 		container:   container,
-		s3cli:       s3.New(sess),
+		s3cli:       s3cli,
+		scheme:      scheme,
 		queryParser: NewQueryParser(),
 	}, nil
 }
@@ -120,7 +129,7 @@ func (v *visibilityArchiver) Archive(
 		}
 	}()
 
-	if err := softValidateURI(URI); err != nil {
+	if err := softValidateURI(URI, v.scheme); err != nil {
 		archiveFailReason = archiver.ErrReasonInvalidURI
 		return err
 	}
@@ -135,6 +144,15 @@ func (v *visibilityArchiver) Archive(
 		archiveFailReason = errEncodeVisibilityRecord
 		return err
 	}
+	envelope := v.container.Envelope
+	if featureCatalog.Envelope != nil {
+		envelope = featureCatalog.Envelope
+	}
+	encodedVisibilityRecord, err = envelope.Seal(ctx, encodedVisibilityRecord)
+	if err != nil {
+		archiveFailReason = archiver.ErrReasonSealVisibilityRecord
+		return err
+	}
 	indexes := createIndexesToArchive(request)
 	// Upload archive to all indexes
 	for _, element := range indexes {
@@ -161,7 +179,7 @@ func (v *visibilityArchiver) Query(
 	URI archiver.URI,
 	request *archiver.QueryVisibilityRequest,
 ) (*archiver.QueryVisibilityResponse, error) {
-	if err := softValidateURI(URI); err != nil {
+	if err := softValidateURI(URI, v.scheme); err != nil {
 		return nil, serviceerror.NewInvalidArgument(archiver.ErrInvalidURI.Error())
 	}
 
@@ -233,6 +251,11 @@ func (v *visibilityArchiver) query(
 			return nil, serviceerror.NewInternal(err.Error())
 		}
 
+		encodedRecord, err = v.container.Envelope.Open(ctx, encodedRecord)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+
 		record, err := decodeVisibilityRecord(encodedRecord)
 		if err != nil {
 			return nil, serviceerror.NewInternal(err.Error())
@@ -243,7 +266,7 @@ func (v *visibilityArchiver) query(
 }
 
 func (v *visibilityArchiver) ValidateURI(URI archiver.URI) error {
-	err := softValidateURI(URI)
+	err := softValidateURI(URI, v.scheme)
 	if err != nil {
 		return err
 	}
@@ -109,6 +109,7 @@ func (s *visibilityArchiverSuite) newTestVisibilityArchiver() *visibilityArchive
 	archiver := &visibilityArchiver{
 		container:   s.container,
 		s3cli:       s.s3cli,
+		scheme:      URIScheme,
 		queryParser: NewQueryParser(),
 	}
 	return archiver
@@ -33,16 +33,26 @@ const (
 
 type (
 	// Attributes is input for authority to make decision.
-	// It can be extended in future if required auth on resources like WorkflowType and TaskList
+	// WorkflowType, TaskList and WorkflowID are only set for the APIs that carry them.
 	Attributes struct {
-		Actor      string
-		APIName    string
-		DomainName string
+		Actor        string
+		APIName      string
+		DomainName   string
+		WorkflowType string
+		TaskList     string
+		WorkflowID   string
+		// RequestMetadata holds the gRPC headers of the incoming request,
+		// keys are lower case as normalized by gRPC.
+		RequestMetadata map[string]string
+		// Principal is the verified identity of the caller, nil for requests without credentials
+		Principal *Principal
 	}
 
 	// Result is result from authority.
 	Result struct {
 		Decision Decision
+		// Reason is an optional human readable explanation of the decision
+		Reason string
 	}
 
 	// Decision is enum type for auth decision
@@ -0,0 +1,188 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -copyright_file ../../LICENSE -package $GOPACKAGE -source $GOFILE -destination claimMapper_mock.go -self_package github.com/temporalio/temporal/common/authorization
+
+package authorization
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"github.com/temporalio/temporal/common/clock"
+)
+
+const (
+	// PrincipalTypeJWT is the type of principals extracted from JSON web tokens
+	PrincipalTypeJWT PrincipalType = "jwt"
+	// PrincipalTypeTLS is the type of principals extracted from TLS client certificates
+	PrincipalTypeTLS PrincipalType = "tls"
+)
+
+// AuthorizationHeaderName is the name of the gRPC header carrying the bearer token
+const AuthorizationHeaderName = "authorization"
+
+const bearerPrefix = "bearer "
+
+type (
+	// PrincipalType is the kind of credential a principal was verified from
+	PrincipalType string
+
+	// Principal is the verified identity of the caller
+	Principal struct {
+		Type PrincipalType
+		// Name is the token subject or the certificate common name
+		Name string
+		// Roles are the policy roles granted by the credential
+		Roles []string
+		// Domains restricts the domains the principal can access, no restriction when empty
+		Domains []string
+	}
+
+	// AuthInfo contains the raw credentials of a request
+	AuthInfo struct {
+		// AuthToken is the value of the authorization header
+		AuthToken string
+		// TLSPeerCertificate is the verified client certificate of the TLS connection
+		TLSPeerCertificate *x509.Certificate
+	}
+
+	// ClaimMapper verifies the credentials of a request and maps them to a principal
+	ClaimMapper interface {
+		// GetPrincipal returns nil when the request carries no credentials
+		GetPrincipal(authInfo *AuthInfo) (*Principal, error)
+	}
+
+	// ClaimMapperConfig is the config of the claim mapper
+	ClaimMapperConfig struct {
+		// JWT enables bearer token validation when set
+		JWT *JWTConfig `yaml:"jwt"`
+		// TLSSubjects maps the common names of client certificates to roles and domains
+		TLSSubjects []TLSSubjectMapping `yaml:"tlsSubjects"`
+	}
+
+	// TLSSubjectMapping grants roles and domains to the certificates whose common name matches the pattern
+	TLSSubjectMapping struct {
+		CommonName string   `yaml:"commonName"`
+		Roles      []string `yaml:"roles"`
+		Domains    []string `yaml:"domains"`
+	}
+
+	defaultClaimMapper struct {
+		jwtValidator *jwtValidator
+		jwtConfig    *JWTConfig
+		tlsSubjects  []TLSSubjectMapping
+	}
+
+	nopClaimMapper struct{}
+)
+
+var _ ClaimMapper = (*defaultClaimMapper)(nil)
+
+// NewClaimMapper creates the claim mapper described by the config
+func NewClaimMapper(config *ClaimMapperConfig) (ClaimMapper, error) {
+	return newClaimMapper(config, clock.NewRealTimeSource())
+}
+
+func newClaimMapper(config *ClaimMapperConfig, timeSource clock.TimeSource) (ClaimMapper, error) {
+	if config == nil || (config.JWT == nil && len(config.TLSSubjects) == 0) {
+		return NewNopClaimMapper(), nil
+	}
+
+	mapper := &defaultClaimMapper{
+		tlsSubjects: config.TLSSubjects,
+	}
+	for _, subject := range config.TLSSubjects {
+		if err := validatePatterns([]string{subject.CommonName}); err != nil {
+			return nil, fmt.Errorf("tls subject mapping: %v", err)
+		}
+	}
+	if config.JWT != nil {
+		jwtConfig := *config.JWT
+		if jwtConfig.SubjectClaim == "" {
+			jwtConfig.SubjectClaim = defaultSubjectClaim
+		}
+		if jwtConfig.RolesClaim == "" {
+			jwtConfig.RolesClaim = defaultRolesClaim
+		}
+		if jwtConfig.DomainsClaim == "" {
+			jwtConfig.DomainsClaim = defaultDomainsClaim
+		}
+		validator, err := newJWTValidator(&jwtConfig, timeSource)
+		if err != nil {
+			return nil, err
+		}
+		mapper.jwtValidator = validator
+		mapper.jwtConfig = &jwtConfig
+	}
+	return mapper, nil
+}
+
+// NewNopClaimMapper creates a claim mapper which never returns a principal
+func NewNopClaimMapper() ClaimMapper {
+	return &nopClaimMapper{}
+}
+
+func (m *nopClaimMapper) GetPrincipal(_ *AuthInfo) (*Principal, error) {
+	return nil, nil
+}
+
+func (m *defaultClaimMapper) GetPrincipal(authInfo *AuthInfo) (*Principal, error) {
+	if authInfo.AuthToken != "" && m.jwtValidator != nil {
+		return m.principalFromToken(authInfo.AuthToken)
+	}
+	if authInfo.TLSPeerCertificate != nil {
+		return m.principalFromCertificate(authInfo.TLSPeerCertificate), nil
+	}
+	return nil, nil
+}
+
+func (m *defaultClaimMapper) principalFromToken(authToken string) (*Principal, error) {
+	if !strings.HasPrefix(strings.ToLower(authToken), bearerPrefix) {
+		return nil, errMalformedToken
+	}
+	claims, err := m.jwtValidator.Validate(strings.TrimSpace(authToken[len(bearerPrefix):]))
+	if err != nil {
+		return nil, err
+	}
+
+	subject, _ := claims[m.jwtConfig.SubjectClaim].(string)
+	return &Principal{
+		Type:    PrincipalTypeJWT,
+		Name:    subject,
+		Roles:   getStrings(claims[m.jwtConfig.RolesClaim]),
+		Domains: getStrings(claims[m.jwtConfig.DomainsClaim]),
+	}, nil
+}
+
+func (m *defaultClaimMapper) principalFromCertificate(certificate *x509.Certificate) *Principal {
+	principal := &Principal{
+		Type: PrincipalTypeTLS,
+		Name: certificate.Subject.CommonName,
+	}
+	for _, subject := range m.tlsSubjects {
+		if matchAny([]string{subject.CommonName}, principal.Name) {
+			principal.Roles = append(principal.Roles, subject.Roles...)
+			principal.Domains = append(principal.Domains, subject.Domains...)
+		}
+	}
+	return principal
+}
@@ -0,0 +1,293 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/common/clock"
+)
+
+type (
+	claimMapperSuite struct {
+		*require.Assertions
+		suite.Suite
+
+		rsaKey     *rsa.PrivateKey
+		ecKey      *ecdsa.PrivateKey
+		keysFile   string
+		timeSource *clock.EventTimeSource
+		mapper     ClaimMapper
+	}
+)
+
+func TestClaimMapperSuite(t *testing.T) {
+	suite.Run(t, new(claimMapperSuite))
+}
+
+func (s *claimMapperSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+
+	var err error
+	s.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	s.NoError(err)
+	s.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.NoError(err)
+
+	keySet := jsonWebKeySet{Keys: []jsonWebKey{
+		{
+			KeyType: "RSA",
+			KeyID:   "rsa-key",
+			N:       encodeSegment(s.rsaKey.N.Bytes()),
+			E:       encodeSegment(big.NewInt(int64(s.rsaKey.E)).Bytes()),
+		},
+		{
+			KeyType: "EC",
+			KeyID:   "ec-key",
+			Curve:   "P-256",
+			X:       encodeSegment(s.ecKey.X.Bytes()),
+			Y:       encodeSegment(s.ecKey.Y.Bytes()),
+		},
+	}}
+	content, err := json.Marshal(keySet)
+	s.NoError(err)
+	file, err := ioutil.TempFile("", "jwks-*.json")
+	s.NoError(err)
+	_, err = file.Write(content)
+	s.NoError(err)
+	s.NoError(file.Close())
+	s.keysFile = file.Name()
+}
+
+func (s *claimMapperSuite) TearDownSuite() {
+	os.Remove(s.keysFile)
+}
+
+func (s *claimMapperSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.timeSource = clock.NewEventTimeSource().Update(time.Unix(1580000000, 0))
+	var err error
+	s.mapper, err = newClaimMapper(&ClaimMapperConfig{
+		JWT: &JWTConfig{
+			KeysFile: s.keysFile,
+			Issuer:   "test-issuer",
+			Audience: "temporal",
+		},
+		TLSSubjects: []TLSSubjectMapping{
+			{CommonName: "worker-*", Roles: []string{"worker"}, Domains: []string{"team-a-*"}},
+		},
+	}, s.timeSource)
+	s.NoError(err)
+}
+
+func (s *claimMapperSuite) TestRSAToken() {
+	token := s.mintToken("RS256", "rsa-key", s.validClaims())
+	principal, err := s.mapper.GetPrincipal(&AuthInfo{AuthToken: "Bearer " + token})
+	s.NoError(err)
+	s.Equal(&Principal{
+		Type:    PrincipalTypeJWT,
+		Name:    "alice",
+		Roles:   []string{"team-a"},
+		Domains: []string{"team-a-payments", "team-a-orders"},
+	}, principal)
+}
+
+func (s *claimMapperSuite) TestECToken() {
+	token := s.mintToken("ES256", "ec-key", s.validClaims())
+	principal, err := s.mapper.GetPrincipal(&AuthInfo{AuthToken: "bearer " + token})
+	s.NoError(err)
+	s.Equal("alice", principal.Name)
+}
+
+func (s *claimMapperSuite) TestInvalidTokens() {
+	claims := s.validClaims()
+
+	_, err := s.mapper.GetPrincipal(&AuthInfo{AuthToken: s.mintToken("RS256", "rsa-key", claims)})
+	s.Equal(errMalformedToken, err, "missing bearer prefix")
+
+	_, err = s.mapper.GetPrincipal(&AuthInfo{AuthToken: "Bearer " + s.mintToken("RS256", "unknown-key", claims)})
+	s.Error(err)
+
+	_, err = s.mapper.GetPrincipal(&AuthInfo{AuthToken: "Bearer " + s.mintToken("ES256", "rsa-key", claims)})
+	s.Error(err, "algorithm does not match the key type")
+
+	_, err = s.mapper.GetPrincipal(&AuthInfo{AuthToken: "Bearer " + s.mintToken("HS256", "rsa-key", claims)})
+	s.Equal(errUnknownSigningAlg, err, "symmetric algorithms are not accepted")
+
+	tampered := s.mintToken("RS256", "rsa-key", claims)
+	claims["sub"] = "mallory"
+	forged := s.mintToken("RS256", "rsa-key", claims)
+	tampered = tampered[:len(tampered)-10] + forged[len(forged)-10:]
+	_, err = s.mapper.GetPrincipal(&AuthInfo{AuthToken: "Bearer " + tampered})
+	s.Equal(errInvalidSignature, err)
+}
+
+func (s *claimMapperSuite) TestRegisteredClaims() {
+	claims := s.validClaims()
+	claims["exp"] = s.timeSource.Now().Add(-time.Minute).Unix()
+	_, err := s.mapper.GetPrincipal(&AuthInfo{AuthToken: "Bearer " + s.mintToken("RS256", "rsa-key", claims)})
+	s.Equal(errTokenExpired, err)
+
+	claims = s.validClaims()
+	delete(claims, "exp")
+	_, err = s.mapper.GetPrincipal(&AuthInfo{AuthToken: "Bearer " + s.mintToken("RS256", "rsa-key", claims)})
+	s.Equal(errTokenWithoutExpiry, err)
+
+	claims = s.validClaims()
+	claims["nbf"] = s.timeSource.Now().Add(time.Minute).Unix()
+	_, err = s.mapper.GetPrincipal(&AuthInfo{AuthToken: "Bearer " + s.mintToken("RS256", "rsa-key", claims)})
+	s.Equal(errTokenNotValidYet, err)
+
+	claims = s.validClaims()
+	claims["iss"] = "other-issuer"
+	_, err = s.mapper.GetPrincipal(&AuthInfo{AuthToken: "Bearer " + s.mintToken("RS256", "rsa-key", claims)})
+	s.Error(err)
+
+	claims = s.validClaims()
+	claims["aud"] = []string{"other", "temporal"}
+	_, err = s.mapper.GetPrincipal(&AuthInfo{AuthToken: "Bearer " + s.mintToken("RS256", "rsa-key", claims)})
+	s.NoError(err)
+}
+
+func (s *claimMapperSuite) TestCertificate() {
+	certificate := s.selfSignedCertificate("worker-1")
+	principal, err := s.mapper.GetPrincipal(&AuthInfo{TLSPeerCertificate: certificate})
+	s.NoError(err)
+	s.Equal(&Principal{
+		Type:    PrincipalTypeTLS,
+		Name:    "worker-1",
+		Roles:   []string{"worker"},
+		Domains: []string{"team-a-*"},
+	}, principal)
+
+	principal, err = s.mapper.GetPrincipal(&AuthInfo{TLSPeerCertificate: s.selfSignedCertificate("ops")})
+	s.NoError(err)
+	s.Equal("ops", principal.Name)
+	s.Empty(principal.Roles)
+}
+
+func (s *claimMapperSuite) TestNoCredentials() {
+	principal, err := s.mapper.GetPrincipal(&AuthInfo{})
+	s.NoError(err)
+	s.Nil(principal)
+}
+
+func (s *claimMapperSuite) TestPrincipalAuthorization() {
+	authorizer, err := NewPolicyAuthorizer(&Policy{
+		Roles: []Role{
+			{Name: "worker", Rules: []Rule{{Effect: EffectAllow, APIGroups: []APIGroup{APIGroupWorker}}}},
+		},
+	})
+	s.NoError(err)
+
+	principal, err := s.mapper.GetPrincipal(&AuthInfo{TLSPeerCertificate: s.selfSignedCertificate("worker-1")})
+	s.NoError(err)
+
+	result, err := authorizer.Authorize(context.Background(), &Attributes{
+		Actor:      principal.Name,
+		APIName:    "PollForActivityTask",
+		DomainName: "team-a-payments",
+		Principal:  principal,
+	})
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	result, err = authorizer.Authorize(context.Background(), &Attributes{
+		Actor:      principal.Name,
+		APIName:    "PollForActivityTask",
+		DomainName: "team-b-payments",
+		Principal:  principal,
+	})
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *claimMapperSuite) validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":     "alice",
+		"iss":     "test-issuer",
+		"aud":     "temporal",
+		"exp":     s.timeSource.Now().Add(time.Hour).Unix(),
+		"roles":   []string{"team-a"},
+		"domains": []string{"team-a-payments", "team-a-orders"},
+	}
+}
+
+func (s *claimMapperSuite) mintToken(algorithm string, keyID string, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": algorithm, "kid": keyID, "typ": "JWT"})
+	s.NoError(err)
+	payload, err := json.Marshal(claims)
+	s.NoError(err)
+	signed := encodeSegment(header) + "." + encodeSegment(payload)
+
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+	var signature []byte
+	if algorithm == "ES256" {
+		r, ss, err := ecdsa.Sign(rand.Reader, s.ecKey, digest.Sum(nil))
+		s.NoError(err)
+		signature = make([]byte, 64)
+		rBytes, sBytes := r.Bytes(), ss.Bytes()
+		copy(signature[32-len(rBytes):32], rBytes)
+		copy(signature[64-len(sBytes):], sBytes)
+	} else {
+		signature, err = rsa.SignPKCS1v15(rand.Reader, s.rsaKey, crypto.SHA256, digest.Sum(nil))
+		s.NoError(err)
+	}
+	return signed + "." + encodeSegment(signature)
+}
+
+func (s *claimMapperSuite) selfSignedCertificate(commonName string) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    s.timeSource.Now().Add(-time.Hour),
+		NotAfter:     s.timeSource.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &s.ecKey.PublicKey, s.ecKey)
+	s.NoError(err)
+	certificate, err := x509.ParseCertificate(der)
+	s.NoError(err)
+	return certificate
+}
+
+func encodeSegment(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
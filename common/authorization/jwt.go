@@ -0,0 +1,274 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"github.com/temporalio/temporal/common/clock"
+)
+
+var (
+	errMalformedToken     = errors.New("malformed token")
+	errUnknownSigningAlg  = errors.New("unsupported token signing algorithm")
+	errInvalidSignature   = errors.New("invalid token signature")
+	errTokenExpired       = errors.New("token is expired")
+	errTokenNotValidYet   = errors.New("token is not valid yet")
+	errTokenWithoutExpiry = errors.New("token has no expiration time")
+)
+
+type (
+	// JWTConfig is the config for validating JSON web tokens and mapping their claims
+	JWTConfig struct {
+		// KeysFile is the path of a JSON web key set file containing the token verification keys
+		KeysFile string `yaml:"keysFile"`
+		// Issuer is the expected "iss" claim, it is not checked when empty
+		Issuer string `yaml:"issuer"`
+		// Audience is the expected "aud" claim, it is not checked when empty
+		Audience string `yaml:"audience"`
+		// SubjectClaim is the claim used as the principal name, defaults to "sub"
+		SubjectClaim string `yaml:"subjectClaim"`
+		// RolesClaim is the claim containing the list of policy roles, defaults to "roles"
+		RolesClaim string `yaml:"rolesClaim"`
+		// DomainsClaim is the claim containing the list of accessible domains, defaults to "domains"
+		DomainsClaim string `yaml:"domainsClaim"`
+		// MaxClockSkew is the tolerated clock difference when checking "exp" and "nbf"
+		MaxClockSkew time.Duration `yaml:"maxClockSkew"`
+	}
+
+	// jwtValidator verifies the signature and the registered claims of JSON web tokens
+	jwtValidator struct {
+		config     *JWTConfig
+		keys       map[string]crypto.PublicKey
+		timeSource clock.TimeSource
+	}
+
+	jsonWebKeySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	jsonWebKey struct {
+		KeyType string `json:"kty"`
+		KeyID   string `json:"kid"`
+		Use     string `json:"use"`
+		N       string `json:"n"`
+		E       string `json:"e"`
+		Curve   string `json:"crv"`
+		X       string `json:"x"`
+		Y       string `json:"y"`
+	}
+)
+
+const (
+	defaultSubjectClaim = "sub"
+	defaultRolesClaim   = "roles"
+	defaultDomainsClaim = "domains"
+)
+
+func newJWTValidator(config *JWTConfig, timeSource clock.TimeSource) (*jwtValidator, error) {
+	keys, err := loadJSONWebKeySet(config.KeysFile)
+	if err != nil {
+		return nil, err
+	}
+	return &jwtValidator{
+		config:     config,
+		keys:       keys,
+		timeSource: timeSource,
+	}, nil
+}
+
+// Validate verifies the token and returns its claims
+func (v *jwtValidator) Validate(token string) (map[string]interface{}, error) {
+	claims := jwt.MapClaims{}
+	// the registered claims are validated below, against the time source of the validator
+	// and with the configured clock skew
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	if _, err := parser.ParseWithClaims(token, claims, v.getKey); err != nil {
+		return nil, convertValidationError(err)
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *jwtValidator) getKey(token *jwt.Token) (interface{}, error) {
+	// only asymmetric algorithms are accepted, the algorithm must match the type of the key
+	// which is enforced by the signing method when verifying the signature
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+	default:
+		return nil, errUnknownSigningAlg
+	}
+
+	keyID, _ := token.Header["kid"].(string)
+	if key, ok := v.keys[keyID]; ok {
+		return key, nil
+	}
+	// tokens without key ID are accepted when the key set has a single key
+	if keyID == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown token key ID %q", keyID)
+}
+
+func (v *jwtValidator) validateClaims(claims jwt.MapClaims) error {
+	now := v.timeSource.Now()
+	if _, ok := claims["exp"]; !ok {
+		return errTokenWithoutExpiry
+	}
+	if !claims.VerifyExpiresAt(now.Add(-v.config.MaxClockSkew).Unix(), true) {
+		return errTokenExpired
+	}
+	if !claims.VerifyNotBefore(now.Add(v.config.MaxClockSkew).Unix(), false) {
+		return errTokenNotValidYet
+	}
+	if v.config.Issuer != "" && !claims.VerifyIssuer(v.config.Issuer, true) {
+		return fmt.Errorf("unexpected token issuer %q", claims["iss"])
+	}
+	if v.config.Audience != "" && !claims.VerifyAudience(v.config.Audience, true) {
+		return fmt.Errorf("token audience does not contain %q", v.config.Audience)
+	}
+	return nil
+}
+
+// convertValidationError maps the errors of the JWT parser to the errors of the validator
+func convertValidationError(err error) error {
+	validationErr, ok := err.(*jwt.ValidationError)
+	if !ok {
+		return err
+	}
+	switch {
+	case validationErr.Errors&jwt.ValidationErrorMalformed != 0:
+		return errMalformedToken
+	case validationErr.Errors&jwt.ValidationErrorUnverifiable != 0:
+		if validationErr.Inner != nil {
+			return validationErr.Inner
+		}
+		return errUnknownSigningAlg
+	case validationErr.Errors&jwt.ValidationErrorSignatureInvalid != 0:
+		return errInvalidSignature
+	default:
+		return err
+	}
+}
+
+func loadJSONWebKeySet(filePath string) (map[string]crypto.PublicKey, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON web key set file %v: %v", filePath, err)
+	}
+	keySet := jsonWebKeySet{}
+	if err := json.Unmarshal(content, &keySet); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON web key set file %v: %v", filePath, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(keySet.Keys))
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q in JSON web key set file %v: %v", jwk.KeyID, filePath, err)
+		}
+		keys[jwk.KeyID] = key
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+// getStrings converts a claim which is either a string or a list of strings
+func getStrings(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// EffectAllow grants access to the requests matched by a rule
+	EffectAllow Effect = "allow"
+	// EffectDeny denies access to the requests matched by a rule, deny always wins over allow
+	EffectDeny Effect = "deny"
+)
+
+const (
+	// APIGroupRead contains the APIs that only read workflow or domain state
+	APIGroupRead APIGroup = "read"
+	// APIGroupWrite contains the APIs that start or mutate workflow executions
+	APIGroupWrite APIGroup = "write"
+	// APIGroupWorker contains the APIs used by workers to poll and respond to tasks
+	APIGroupWorker APIGroup = "worker"
+	// APIGroupDomain contains the APIs that register or modify domains
+	APIGroupDomain APIGroup = "domain"
+	// APIGroupAdmin contains all the admin service APIs
+	APIGroupAdmin APIGroup = "admin"
+)
+
+// AdminAPIPrefix is prepended to the admin service method names to build the APIName
+// of an admin request, as the admin and workflow services share some method names.
+const AdminAPIPrefix = "Admin"
+
+type (
+	// Config is the authorization config of the frontend service
+	Config struct {
+		// PolicyFile is the path of the policy file. The nop authorizer is used when it is empty.
+		PolicyFile string `yaml:"policyFile"`
+		// ClaimMapper is the config for mapping verified credentials to principals
+		ClaimMapper ClaimMapperConfig `yaml:"claimMapper"`
+	}
+
+	// Effect is the effect of a policy rule
+	Effect string
+
+	// APIGroup is a named set of APIs which can be referenced by policy rules
+	APIGroup string
+
+	// Policy is the declarative authorization policy. Actors are bound to roles and
+	// every role is a list of allow and deny rules. A request is allowed only if at least
+	// one rule of the actor's roles allows it and none of them denies it.
+	// Verified principals additionally get the roles granted by their credentials.
+	Policy struct {
+		Roles    []Role    `yaml:"roles"`
+		Bindings []Binding `yaml:"bindings"`
+	}
+
+	// Role is a named list of rules
+	Role struct {
+		Name  string `yaml:"name"`
+		Rules []Rule `yaml:"rules"`
+	}
+
+	// Rule matches requests by their attributes. Empty fields match every request,
+	// non-empty fields are lists of patterns as accepted by path.Match. As in path.Match,
+	// * does not match /, a workflow ID pattern "orders-*" does not match "orders-eu/1".
+	// Not all APIs carry the workflow type, task list and workflow ID of a request, deny rules
+	// restricting an attribute which the request doesn't carry match the request anyway,
+	// so that such APIs cannot be used to get around them, allow rules don't match it.
+	Rule struct {
+		Effect        Effect     `yaml:"effect"`
+		APIs          []string   `yaml:"apis"`
+		APIGroups     []APIGroup `yaml:"apiGroups"`
+		Domains       []string   `yaml:"domains"`
+		WorkflowTypes []string   `yaml:"workflowTypes"`
+		TaskLists     []string   `yaml:"taskLists"`
+		WorkflowIDs   []string   `yaml:"workflowIDs"`
+		// Metadata is a map from request header name to the pattern its value must match,
+		// header names are case insensitive
+		Metadata map[string]string `yaml:"metadata"`
+	}
+
+	// Binding grants a role to the actors matching any of the patterns
+	Binding struct {
+		Role   string   `yaml:"role"`
+		Actors []string `yaml:"actors"`
+	}
+)
+
+var apiGroups = map[string]APIGroup{
+	"CountWorkflowExecutions":            APIGroupRead,
+	"DescribeDomain":                     APIGroupRead,
+	"DescribeSchedule":                   APIGroupRead,
+	"DescribeTaskList":                   APIGroupRead,
+	"DescribeWorkflowExecution":          APIGroupRead,
+	"GetClusterInfo":                     APIGroupRead,
+	"GetSearchAttributes":                APIGroupRead,
+	"GetWorkflowExecutionHistory":        APIGroupRead,
+	"GetWorkflowExecutionRawHistory":     APIGroupRead,
+	"PollForWorkflowExecutionRawHistory": APIGroupRead,
+	"ListArchivedWorkflowExecutions":     APIGroupRead,
+	"ListClosedWorkflowExecutions":       APIGroupRead,
+	"ListDomains":                        APIGroupRead,
+	"ListOpenWorkflowExecutions":         APIGroupRead,
+	"ListSchedules":                      APIGroupRead,
+	"ListTaskListPartitions":             APIGroupRead,
+	"ListWorkflowExecutions":             APIGroupRead,
+	"QueryWorkflow":                      APIGroupRead,
+	"ScanWorkflowExecutions":             APIGroupRead,
+
+	"BackfillSchedule":                 APIGroupWrite,
+	"CreateSchedule":                   APIGroupWrite,
+	"DeleteSchedule":                   APIGroupWrite,
+	"PauseSchedule":                    APIGroupWrite,
+	"RequestCancelWorkflowExecution":   APIGroupWrite,
+	"ResetStickyTaskList":              APIGroupWrite,
+	"ResetWorkflowExecution":           APIGroupWrite,
+	"ResumeSchedule":                   APIGroupWrite,
+	"SignalWithStartWorkflowExecution": APIGroupWrite,
+	"SignalWorkflowExecution":          APIGroupWrite,
+	"StartWorkflowExecution":           APIGroupWrite,
+	"TerminateWorkflowExecution":       APIGroupWrite,
+	"UpdateSchedule":                   APIGroupWrite,
+	"UpdateWorkflowExecution":          APIGroupWrite,
+
+	"PollForActivityTask":              APIGroupWorker,
+	"PollForDecisionTask":              APIGroupWorker,
+	"RecordActivityTaskHeartbeat":      APIGroupWorker,
+	"RecordActivityTaskHeartbeatByID":  APIGroupWorker,
+	"RespondActivityTaskCanceled":      APIGroupWorker,
+	"RespondActivityTaskCanceledByID":  APIGroupWorker,
+	"RespondActivityTaskCompleted":     APIGroupWorker,
+	"RespondActivityTaskCompletedByID": APIGroupWorker,
+	"RespondActivityTaskFailed":        APIGroupWorker,
+	"RespondActivityTaskFailedByID":    APIGroupWorker,
+	"RespondDecisionTaskCompleted":     APIGroupWorker,
+	"RespondDecisionTaskFailed":        APIGroupWorker,
+	"RespondQueryTaskCompleted":        APIGroupWorker,
+
+	"DeprecateDomain": APIGroupDomain,
+	"RegisterDomain":  APIGroupDomain,
+	"UpdateDomain":    APIGroupDomain,
+}
+
+// GetAPIGroup returns the group the API belongs to, or an empty group for unknown APIs
+func GetAPIGroup(apiName string) APIGroup {
+	if group, ok := apiGroups[apiName]; ok {
+		return group
+	}
+	if strings.HasPrefix(apiName, AdminAPIPrefix) {
+		return APIGroupAdmin
+	}
+	return ""
+}
+
+// LoadPolicy reads and validates the policy file
+func LoadPolicy(filePath string) (*Policy, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization policy file %v: %v", filePath, err)
+	}
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(content, policy); err != nil {
+		return nil, fmt.Errorf("failed to decode authorization policy file %v: %v", filePath, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid authorization policy file %v: %v", filePath, err)
+	}
+	return policy, nil
+}
+
+// Validate checks that all the roles referenced by bindings exist and all the patterns are well formed
+func (p *Policy) Validate() error {
+	roles := make(map[string]struct{}, len(p.Roles))
+	for _, role := range p.Roles {
+		if role.Name == "" {
+			return fmt.Errorf("role name is empty")
+		}
+		if _, ok := roles[role.Name]; ok {
+			return fmt.Errorf("duplicate role %v", role.Name)
+		}
+		roles[role.Name] = struct{}{}
+		for i, rule := range role.Rules {
+			if err := rule.validate(); err != nil {
+				return fmt.Errorf("role %v rule %v: %v", role.Name, i, err)
+			}
+		}
+	}
+	for _, binding := range p.Bindings {
+		if _, ok := roles[binding.Role]; !ok {
+			return fmt.Errorf("binding references unknown role %v", binding.Role)
+		}
+		if err := validatePatterns(binding.Actors); err != nil {
+			return fmt.Errorf("binding of role %v: %v", binding.Role, err)
+		}
+	}
+	return nil
+}
+
+func (r *Rule) validate() error {
+	switch r.Effect {
+	case EffectAllow, EffectDeny:
+	default:
+		return fmt.Errorf("unknown effect %q", r.Effect)
+	}
+	for _, group := range r.APIGroups {
+		switch group {
+		case APIGroupRead, APIGroupWrite, APIGroupWorker, APIGroupDomain, APIGroupAdmin:
+		default:
+			return fmt.Errorf("unknown api group %q", group)
+		}
+	}
+	for _, patterns := range [][]string{r.APIs, r.Domains, r.WorkflowTypes, r.TaskLists, r.WorkflowIDs} {
+		if err := validatePatterns(patterns); err != nil {
+			return err
+		}
+	}
+	keys := make(map[string]struct{}, len(r.Metadata))
+	for key, pattern := range r.Metadata {
+		if _, ok := keys[strings.ToLower(key)]; ok {
+			return fmt.Errorf("duplicate metadata key %q", key)
+		}
+		keys[strings.ToLower(key)] = struct{}{}
+		if err := validatePatterns([]string{pattern}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalized returns a copy of the rule with lower case metadata keys, as gRPC lower cases header names
+func (r Rule) normalized() Rule {
+	if len(r.Metadata) == 0 {
+		return r
+	}
+	metadata := make(map[string]string, len(r.Metadata))
+	for key, pattern := range r.Metadata {
+		metadata[strings.ToLower(key)] = pattern
+	}
+	r.Metadata = metadata
+	return r
+}
+
+func (r *Rule) matches(attributes *Attributes) bool {
+	hasAPIRestriction := len(r.APIs) > 0 || len(r.APIGroups) > 0
+	if hasAPIRestriction && !matchAny(r.APIs, attributes.APIName) && !r.matchesAPIGroup(attributes.APIName) {
+		return false
+	}
+	if len(r.Domains) > 0 && !matchAny(r.Domains, attributes.DomainName) {
+		return false
+	}
+	if !r.matchesOptional(r.WorkflowTypes, attributes.WorkflowType) {
+		return false
+	}
+	if !r.matchesOptional(r.TaskLists, attributes.TaskList) {
+		return false
+	}
+	if !r.matchesOptional(r.WorkflowIDs, attributes.WorkflowID) {
+		return false
+	}
+	for key, pattern := range r.Metadata {
+		value, ok := attributes.RequestMetadata[key]
+		if !ok || !matchAny([]string{pattern}, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesOptional matches an attribute which is only set for the APIs carrying it,
+// deny rules match requests without the attribute
+func (r *Rule) matchesOptional(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	if value == "" {
+		return r.Effect == EffectDeny
+	}
+	return matchAny(patterns, value)
+}
+
+func (r *Rule) matchesAPIGroup(apiName string) bool {
+	group := GetAPIGroup(apiName)
+	if group == "" {
+		return false
+	}
+	for _, g := range r.APIGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("malformed pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+)
+
+type policyAuthorizer struct {
+	roles    map[string]Role
+	bindings []Binding
+}
+
+var _ Authorizer = (*policyAuthorizer)(nil)
+
+// NewPolicyAuthorizer creates an authorizer which enforces the given policy
+func NewPolicyAuthorizer(policy *Policy) (Authorizer, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	roles := make(map[string]Role, len(policy.Roles))
+	for _, role := range policy.Roles {
+		rules := make([]Rule, len(role.Rules))
+		for i, rule := range role.Rules {
+			rules[i] = rule.normalized()
+		}
+		roles[role.Name] = Role{Name: role.Name, Rules: rules}
+	}
+	return &policyAuthorizer{
+		roles:    roles,
+		bindings: policy.Bindings,
+	}, nil
+}
+
+// NewAuthorizer creates the authorizer described by the config
+func NewAuthorizer(config *Config) (Authorizer, error) {
+	if config == nil || config.PolicyFile == "" {
+		return NewNopAuthorizer(), nil
+	}
+
+	policy, err := LoadPolicy(config.PolicyFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewPolicyAuthorizer(policy)
+}
+
+func (a *policyAuthorizer) Authorize(
+	ctx context.Context,
+	attributes *Attributes,
+) (Result, error) {
+
+	if principal := attributes.Principal; principal != nil && len(principal.Domains) > 0 && attributes.DomainName != "" {
+		if !matchAny(principal.Domains, attributes.DomainName) {
+			return Result{
+				Decision: DecisionDeny,
+				Reason:   fmt.Sprintf("domain %v is not granted to principal %v", attributes.DomainName, principal.Name),
+			}, nil
+		}
+	}
+
+	allowedBy := ""
+	for _, roleName := range a.rolesOf(attributes) {
+		for _, rule := range a.roles[roleName].Rules {
+			if !rule.matches(attributes) {
+				continue
+			}
+			if rule.Effect == EffectDeny {
+				return Result{
+					Decision: DecisionDeny,
+					Reason:   fmt.Sprintf("denied by role %v", roleName),
+				}, nil
+			}
+			if allowedBy == "" {
+				allowedBy = roleName
+			}
+		}
+	}
+
+	if allowedBy == "" {
+		return Result{
+			Decision: DecisionDeny,
+			Reason:   "no rule allows the request",
+		}, nil
+	}
+	return Result{
+		Decision: DecisionAllow,
+		Reason:   fmt.Sprintf("allowed by role %v", allowedBy),
+	}, nil
+}
+
+func (a *policyAuthorizer) rolesOf(attributes *Attributes) []string {
+	var roles []string
+	for _, binding := range a.bindings {
+		// requests without verified credentials have no actor, patterns like "*" must not bind them
+		if attributes.Actor != "" && matchAny(binding.Actors, attributes.Actor) {
+			roles = append(roles, binding.Role)
+		}
+	}
+	if attributes.Principal != nil {
+		for _, role := range attributes.Principal.Roles {
+			if _, ok := a.roles[role]; ok {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
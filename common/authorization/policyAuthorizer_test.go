@@ -0,0 +1,213 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const testPolicy = `
+roles:
+  - name: team-a
+    rules:
+      - effect: allow
+        apiGroups: [read, write, worker]
+        domains: ["team-a-*"]
+      - effect: deny
+        apis: [TerminateWorkflowExecution]
+        workflowIDs: ["critical-*"]
+  - name: operator
+    rules:
+      - effect: allow
+        apiGroups: [admin, domain]
+      - effect: allow
+        apiGroups: [read]
+        metadata:
+          Temporal-SDK-Name: "temporal-cli"
+bindings:
+  - role: team-a
+    actors: ["alice", "bob"]
+  - role: operator
+    actors: ["ops-*"]
+`
+
+type (
+	policyAuthorizerSuite struct {
+		*require.Assertions
+		suite.Suite
+
+		authorizer Authorizer
+	}
+)
+
+func TestPolicyAuthorizerSuite(t *testing.T) {
+	suite.Run(t, new(policyAuthorizerSuite))
+}
+
+func (s *policyAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	file, err := ioutil.TempFile("", "policy-*.yaml")
+	s.NoError(err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(testPolicy)
+	s.NoError(err)
+	s.NoError(file.Close())
+
+	s.authorizer, err = NewAuthorizer(&Config{PolicyFile: file.Name()})
+	s.NoError(err)
+}
+
+func (s *policyAuthorizerSuite) TestAllowedByAPIGroupAndDomain() {
+	s.assertDecision(DecisionAllow, &Attributes{
+		Actor:      "alice",
+		APIName:    "StartWorkflowExecution",
+		DomainName: "team-a-payments",
+		WorkflowID: "critical-1",
+	})
+	s.assertDecision(DecisionAllow, &Attributes{
+		Actor:      "bob",
+		APIName:    "PollForDecisionTask",
+		DomainName: "team-a-orders",
+		TaskList:   "orders",
+	})
+}
+
+func (s *policyAuthorizerSuite) TestDeniedOutsideOfDomain() {
+	s.assertDecision(DecisionDeny, &Attributes{
+		Actor:      "alice",
+		APIName:    "StartWorkflowExecution",
+		DomainName: "team-b-payments",
+	})
+}
+
+func (s *policyAuthorizerSuite) TestDenyWinsOverAllow() {
+	s.assertDecision(DecisionDeny, &Attributes{
+		Actor:      "alice",
+		APIName:    "TerminateWorkflowExecution",
+		DomainName: "team-a-payments",
+		WorkflowID: "critical-1",
+	})
+	s.assertDecision(DecisionAllow, &Attributes{
+		Actor:      "alice",
+		APIName:    "TerminateWorkflowExecution",
+		DomainName: "team-a-payments",
+		WorkflowID: "regular-1",
+	})
+}
+
+func (s *policyAuthorizerSuite) TestDenyAppliesToAPIsWithoutWorkflowID() {
+	// the deny rule of critical workflows cannot be checked without the workflow ID
+	s.assertDecision(DecisionDeny, &Attributes{
+		Actor:      "alice",
+		APIName:    "TerminateWorkflowExecution",
+		DomainName: "team-a-payments",
+	})
+}
+
+func (s *policyAuthorizerSuite) TestAPIGroupNotGranted() {
+	s.assertDecision(DecisionDeny, &Attributes{
+		Actor:      "alice",
+		APIName:    "UpdateDomain",
+		DomainName: "team-a-payments",
+	})
+	s.assertDecision(DecisionDeny, &Attributes{
+		Actor:   "alice",
+		APIName: AdminAPIPrefix + "CloseShard",
+	})
+	s.assertDecision(DecisionAllow, &Attributes{
+		Actor:   "ops-jane",
+		APIName: AdminAPIPrefix + "CloseShard",
+	})
+}
+
+func (s *policyAuthorizerSuite) TestRequestMetadata() {
+	attributes := &Attributes{
+		Actor:      "ops-jane",
+		APIName:    "DescribeWorkflowExecution",
+		DomainName: "team-b-payments",
+	}
+	s.assertDecision(DecisionDeny, attributes)
+
+	attributes.RequestMetadata = map[string]string{"temporal-sdk-name": "temporal-cli"}
+	s.assertDecision(DecisionAllow, attributes)
+}
+
+func (s *policyAuthorizerSuite) TestUnknownActor() {
+	s.assertDecision(DecisionDeny, &Attributes{
+		APIName:    "DescribeDomain",
+		DomainName: "team-a-payments",
+	})
+}
+
+func (s *policyAuthorizerSuite) TestWildcardBindingRequiresActor() {
+	authorizer, err := NewPolicyAuthorizer(&Policy{
+		Roles: []Role{
+			{Name: "reader", Rules: []Rule{{Effect: EffectAllow, APIGroups: []APIGroup{APIGroupRead}}}},
+		},
+		Bindings: []Binding{{Role: "reader", Actors: []string{"*"}}},
+	})
+	s.NoError(err)
+
+	result, err := authorizer.Authorize(context.Background(), &Attributes{
+		APIName:    "DescribeWorkflowExecution",
+		DomainName: "team-a-payments",
+	})
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *policyAuthorizerSuite) TestInvalidPolicy() {
+	_, err := NewPolicyAuthorizer(&Policy{
+		Bindings: []Binding{{Role: "missing", Actors: []string{"*"}}},
+	})
+	s.Error(err)
+
+	_, err = NewPolicyAuthorizer(&Policy{
+		Roles: []Role{{Name: "bad", Rules: []Rule{{Effect: "maybe"}}}},
+	})
+	s.Error(err)
+
+	_, err = NewPolicyAuthorizer(&Policy{
+		Roles: []Role{{Name: "bad", Rules: []Rule{{Effect: EffectAllow, Domains: []string{"["}}}}},
+	})
+	s.Error(err)
+}
+
+func (s *policyAuthorizerSuite) TestNoPolicyFile() {
+	authorizer, err := NewAuthorizer(&Config{})
+	s.NoError(err)
+	result, err := authorizer.Authorize(context.Background(), &Attributes{APIName: "StartWorkflowExecution"})
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *policyAuthorizerSuite) assertDecision(expected Decision, attributes *Attributes) {
+	result, err := s.authorizer.Authorize(context.Background(), attributes)
+	s.NoError(err)
+	s.Equal(expected, result.Decision, result.Reason)
+}
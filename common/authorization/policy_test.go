@@ -0,0 +1,111 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	policySuite struct {
+		*require.Assertions
+		suite.Suite
+	}
+)
+
+func TestPolicySuite(t *testing.T) {
+	suite.Run(t, new(policySuite))
+}
+
+func (s *policySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *policySuite) TestRule_KnownAttributes() {
+	for _, effect := range []Effect{EffectAllow, EffectDeny} {
+		rule := &Rule{
+			Effect:        effect,
+			WorkflowTypes: []string{"payment-*"},
+			TaskLists:     []string{"payments"},
+			WorkflowIDs:   []string{"critical-*"},
+		}
+		attributes := &Attributes{
+			APIName:      "SignalWithStartWorkflowExecution",
+			DomainName:   "team-a-payments",
+			WorkflowType: "payment-refund",
+			TaskList:     "payments",
+			WorkflowID:   "critical-1",
+		}
+		s.True(rule.matches(attributes), effect)
+
+		attributes.WorkflowType = "order-refund"
+		s.False(rule.matches(attributes), effect)
+	}
+}
+
+func (s *policySuite) TestRule_UnknownAttributes() {
+	// terminate requests carry the workflow ID but neither the workflow type nor the task list
+	attributes := &Attributes{
+		APIName:    "TerminateWorkflowExecution",
+		DomainName: "team-a-payments",
+		WorkflowID: "critical-1",
+	}
+	for _, rule := range []*Rule{
+		{WorkflowTypes: []string{"payment-*"}},
+		{TaskLists: []string{"payments"}},
+		{WorkflowTypes: []string{"payment-*"}, WorkflowIDs: []string{"critical-*"}},
+	} {
+		rule.Effect = EffectDeny
+		s.True(rule.matches(attributes), "deny rules must not be bypassed by APIs without the attribute")
+		rule.Effect = EffectAllow
+		s.False(rule.matches(attributes), "allow rules must not grant APIs without the attribute")
+	}
+
+	// a known attribute still has to match
+	rule := &Rule{Effect: EffectDeny, WorkflowTypes: []string{"payment-*"}, WorkflowIDs: []string{"critical-*"}}
+	attributes.WorkflowID = "regular-1"
+	s.False(rule.matches(attributes))
+}
+
+func (s *policySuite) TestRule_WorkflowIDPatternDoesNotMatchSlash() {
+	rule := &Rule{Effect: EffectDeny, WorkflowIDs: []string{"orders-*"}}
+	s.True(rule.matches(&Attributes{WorkflowID: "orders-1"}))
+	s.False(rule.matches(&Attributes{WorkflowID: "orders-eu/1"}))
+
+	rule.WorkflowIDs = append(rule.WorkflowIDs, "orders-*/*")
+	s.True(rule.matches(&Attributes{WorkflowID: "orders-eu/1"}))
+}
+
+func (s *policySuite) TestRule_MetadataKeysAreCaseInsensitive() {
+	rule := Rule{Effect: EffectAllow, Metadata: map[string]string{"Temporal-SDK-Name": "temporal-cli"}}
+	s.NoError(rule.validate())
+
+	normalized := rule.normalized()
+	s.True(normalized.matches(&Attributes{RequestMetadata: map[string]string{"temporal-sdk-name": "temporal-cli"}}))
+	// the rule of the policy is left untouched
+	s.Equal("temporal-cli", rule.Metadata["Temporal-SDK-Name"])
+
+	rule.Metadata["temporal-sdk-name"] = "temporal-cli"
+	s.Error(rule.validate())
+}
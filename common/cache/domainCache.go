@@ -26,6 +26,7 @@ import (
 	"hash/fnv"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -870,3 +871,112 @@ func (entry *DomainCacheEntry) IsSampledForLongerRetention(
 	}
 	return false
 }
+
+// DefaultWorkerBuildIDKey is key to specify the worker build ID new workflows are dispatched to
+var DefaultWorkerBuildIDKey = "default_worker_build_id"
+
+// CompatibleWorkerBuildIDsKey is key to specify comma separated worker build IDs which
+// are compatible with the default worker build ID
+var CompatibleWorkerBuildIDsKey = "compatible_worker_build_ids"
+
+// WorkerBuildIDRedirectsKey is key to specify comma separated from=to pairs of worker build IDs,
+// workflows pinned to a retired build ID are dispatched to the build ID it is redirected to
+var WorkerBuildIDRedirectsKey = "worker_build_id_redirects"
+
+// maxWorkerBuildIDRedirects bounds the redirects followed for a pinned build ID, so that
+// misconfigured redirect cycles do not loop
+const maxWorkerBuildIDRedirects = 10
+
+// IsWorkerVersioningEnabled return whether decision tasks of the domain are routed by worker build ID or not
+func (entry *DomainCacheEntry) IsWorkerVersioningEnabled() bool {
+
+	return entry.getDefaultWorkerBuildID() != ""
+}
+
+// GetDecisionTaskBuildID returns the worker build ID which decision tasks of a workflow
+// pinned to the given build ID are dispatched to, the default build ID is returned for
+// workflows which are not pinned yet or are pinned to a compatible build ID. Workflows
+// pinned to a retired build ID follow its redirects first.
+func (entry *DomainCacheEntry) GetDecisionTaskBuildID(
+	pinnedBuildID string,
+) string {
+
+	defaultBuildID := entry.getDefaultWorkerBuildID()
+	if defaultBuildID == "" {
+		return ""
+	}
+	redirects := entry.getWorkerBuildIDRedirects()
+	for i := 0; i < maxWorkerBuildIDRedirects; i++ {
+		redirect, ok := redirects[pinnedBuildID]
+		if !ok {
+			break
+		}
+		pinnedBuildID = redirect
+	}
+	if pinnedBuildID == "" || pinnedBuildID == defaultBuildID {
+		return defaultBuildID
+	}
+	for _, buildID := range entry.getCompatibleWorkerBuildIDs() {
+		if buildID == pinnedBuildID {
+			return defaultBuildID
+		}
+	}
+	return pinnedBuildID
+}
+
+// GetRoutedWorkerBuildIDs returns the known worker build IDs other than the given one whose decision
+// tasks are dispatched to the given build ID, the empty build ID stands for workflows which are not
+// pinned yet. Decision tasks added before the routing changed may still wait for workers of these builds.
+func (entry *DomainCacheEntry) GetRoutedWorkerBuildIDs(
+	buildID string,
+) []string {
+
+	if entry.getDefaultWorkerBuildID() == "" {
+		return nil
+	}
+	candidates := append([]string{""}, entry.getCompatibleWorkerBuildIDs()...)
+	for from := range entry.getWorkerBuildIDRedirects() {
+		candidates = append(candidates, from)
+	}
+	sort.Strings(candidates)
+
+	var routed []string
+	for i, candidate := range candidates {
+		if candidate == buildID || (i > 0 && candidate == candidates[i-1]) {
+			continue
+		}
+		if entry.GetDecisionTaskBuildID(candidate) == buildID {
+			routed = append(routed, candidate)
+		}
+	}
+	return routed
+}
+
+func (entry *DomainCacheEntry) getDefaultWorkerBuildID() string {
+	return strings.TrimSpace(entry.info.Data[DefaultWorkerBuildIDKey])
+}
+
+func (entry *DomainCacheEntry) getCompatibleWorkerBuildIDs() []string {
+	var buildIDs []string
+	for _, buildID := range strings.Split(entry.info.Data[CompatibleWorkerBuildIDsKey], ",") {
+		if buildID = strings.TrimSpace(buildID); buildID != "" {
+			buildIDs = append(buildIDs, buildID)
+		}
+	}
+	return buildIDs
+}
+
+func (entry *DomainCacheEntry) getWorkerBuildIDRedirects() map[string]string {
+	redirects := make(map[string]string)
+	for _, redirect := range strings.Split(entry.info.Data[WorkerBuildIDRedirectsKey], ",") {
+		pair := strings.SplitN(redirect, "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+		from, to := strings.TrimSpace(pair[0]), strings.TrimSpace(pair[1])
+		if from != "" && to != "" {
+			redirects[from] = to
+		}
+	}
+	return redirects
+}
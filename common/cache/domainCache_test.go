@@ -643,3 +643,51 @@ func Test_IsSampledForLongerRetention(t *testing.T) {
 	d.info.Data[SampleRateKey] = "invalid-value"
 	require.False(t, d.IsSampledForLongerRetention(wid))
 }
+
+func Test_GetDecisionTaskBuildID(t *testing.T) {
+	d := &DomainCacheEntry{
+		info: &persistence.DomainInfo{
+			Data: make(map[string]string),
+		},
+		config: &persistence.DomainConfig{
+			Retention: 7,
+		},
+	}
+	require.False(t, d.IsWorkerVersioningEnabled())
+	require.Equal(t, "", d.GetDecisionTaskBuildID(""))
+	require.Equal(t, "", d.GetDecisionTaskBuildID("v1"))
+
+	d.info.Data[DefaultWorkerBuildIDKey] = "v3"
+	d.info.Data[CompatibleWorkerBuildIDsKey] = "v2, v2.1"
+	require.True(t, d.IsWorkerVersioningEnabled())
+	require.Equal(t, "v3", d.GetDecisionTaskBuildID(""))
+	require.Equal(t, "v3", d.GetDecisionTaskBuildID("v3"))
+	require.Equal(t, "v3", d.GetDecisionTaskBuildID("v2"))
+	require.Equal(t, "v3", d.GetDecisionTaskBuildID("v2.1"))
+	require.Equal(t, "v1", d.GetDecisionTaskBuildID("v1")) // incompatible builds stay pinned
+
+	d.info.Data[WorkerBuildIDRedirectsKey] = "v0=v1, v1=v1.1, v0.5=v2, loop1=loop2, loop2=loop1"
+	require.Equal(t, "v1.1", d.GetDecisionTaskBuildID("v0"))
+	require.Equal(t, "v1.1", d.GetDecisionTaskBuildID("v1"))
+	require.Equal(t, "v3", d.GetDecisionTaskBuildID("v0.5"))
+	require.Equal(t, "loop1", d.GetDecisionTaskBuildID("loop1"))
+}
+
+func Test_GetRoutedWorkerBuildIDs(t *testing.T) {
+	d := &DomainCacheEntry{
+		info: &persistence.DomainInfo{
+			Data: make(map[string]string),
+		},
+		config: &persistence.DomainConfig{
+			Retention: 7,
+		},
+	}
+	require.Empty(t, d.GetRoutedWorkerBuildIDs("v1"))
+
+	d.info.Data[DefaultWorkerBuildIDKey] = "v3"
+	d.info.Data[CompatibleWorkerBuildIDsKey] = "v2, v2.1"
+	d.info.Data[WorkerBuildIDRedirectsKey] = "v0=v1, v1=v1.1, v0.5=v2"
+	require.Equal(t, []string{"", "v0.5", "v2", "v2.1"}, d.GetRoutedWorkerBuildIDs("v3"))
+	require.Equal(t, []string{"v0", "v1"}, d.GetRoutedWorkerBuildIDs("v1.1"))
+	require.Empty(t, d.GetRoutedWorkerBuildIDs("v1"))
+}
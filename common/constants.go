@@ -136,6 +136,21 @@ const (
 	ArchivalPaused = "paused"
 )
 
+const (
+	// TaskPriorityHeaderKey is the header field of ScheduleActivityTask decisions and StartWorkflowExecution
+	// requests which carries the dispatch priority of the activity or decision tasks, as a decimal integer
+	TaskPriorityHeaderKey = "temporal-task-priority"
+	// DefaultTaskPriority is the priority of tasks without a priority header
+	DefaultTaskPriority int32 = 0
+	// MaxTaskPriority is the highest task priority, tasks with a higher priority are dispatched first
+	MaxTaskPriority int32 = 4
+	// FairnessKeyHeaderKey is the header field of ScheduleActivityTask decisions and StartWorkflowExecution
+	// requests which carries the fairness key, e.g. the tenant, that matching shares dispatch among
+	FairnessKeyHeaderKey = "temporal-fairness-key"
+	// MaxFairnessKeyLength is the maximum length of a fairness key
+	MaxFairnessKeyLength = 255
+)
+
 // enum for dynamic config AdvancedVisibilityWritingMode
 const (
 	// AdvancedVisibilityWritingModeOff means do not write to advanced visibility store
@@ -44,6 +44,11 @@ const (
 	// ClientImplHeaderName refers to the name of the
 	// header that contains the client implementation
 	ClientImplHeaderName = "temporal-sdk-name"
+
+	// WorkerBuildIDHeaderName refers to the name of the header that
+	// contains the build ID of the worker polling for decision tasks,
+	// it is used for routing tasks to compatible worker builds
+	WorkerBuildIDHeaderName = "temporal-worker-build-id"
 )
 
 // GetValues returns header values for passed header names.
@@ -60,6 +65,21 @@ func GetValues(ctx context.Context, headerNames ...string) []string {
 	return headerValues
 }
 
+// GetAll returns all the headers of the incoming context.
+// Only the first value of every header is returned.
+func GetAll(ctx context.Context) map[string]string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	headerValues := make(map[string]string, len(md))
+	for headerName := range md {
+		headerValues[headerName] = getSingleHeaderValue(md, headerName)
+	}
+	return headerValues
+}
+
 // PropagateVersions propagates version headers from incoming context to outgoing context.
 // It copies all version headers to outgoing context only if they are exist in incoming context
 // and doesn't exist in outgoing context already.
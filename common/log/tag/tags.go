@@ -841,3 +841,13 @@ func QueryLevel(s time.Time) Tag {
 func TaskListInfo(s interface{}) Tag {
 	return newObjectTag("task-list-info", s)
 }
+
+// TaskListReadPartitions returns tag for the number of read partitions of a task list
+func TaskListReadPartitions(n int) Tag {
+	return newInt("task-list-read-partitions", n)
+}
+
+// TaskListWritePartitions returns tag for the number of write partitions of a task list
+func TaskListWritePartitions(n int) Tag {
+	return newInt("task-list-write-partitions", n)
+}
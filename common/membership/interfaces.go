@@ -70,6 +70,12 @@ type (
 		RemoveListener(service string, name string) error
 		// GetReachableMembers returns addresses of all members of the ring
 		GetReachableMembers() ([]string, error)
+		// SetSelfLabel sets a label of the local host, the label is gossiped
+		// to all the members of the ring
+		SetSelfLabel(key string, value string) error
+		// GetMemberLabels returns the value of the label of the reachable
+		// members of the service which have the label set, keyed by member address
+		GetMemberLabels(service string, key string) (map[string]string, error)
 	}
 
 	// ServiceResolver provides membership information for a specific cadence service.
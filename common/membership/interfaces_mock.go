@@ -168,6 +168,35 @@ func (mr *MockMonitorMockRecorder) GetReachableMembers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReachableMembers", reflect.TypeOf((*MockMonitor)(nil).GetReachableMembers))
 }
 
+// SetSelfLabel mocks base method
+func (m *MockMonitor) SetSelfLabel(key, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSelfLabel", key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSelfLabel indicates an expected call of SetSelfLabel
+func (mr *MockMonitorMockRecorder) SetSelfLabel(key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSelfLabel", reflect.TypeOf((*MockMonitor)(nil).SetSelfLabel), key, value)
+}
+
+// GetMemberLabels mocks base method
+func (m *MockMonitor) GetMemberLabels(service, key string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberLabels", service, key)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberLabels indicates an expected call of GetMemberLabels
+func (mr *MockMonitorMockRecorder) GetMemberLabels(service, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberLabels", reflect.TypeOf((*MockMonitor)(nil).GetMemberLabels), service, key)
+}
+
 // MockServiceResolver is a mock of ServiceResolver interface
 type MockServiceResolver struct {
 	ctrl     *gomock.Controller
@@ -31,6 +31,7 @@ import (
 	"github.com/temporalio/temporal/common/primitives"
 
 	"github.com/pborman/uuid"
+	"github.com/uber/ringpop-go/swim"
 
 	"github.com/temporalio/temporal/common/persistence"
 
@@ -353,6 +354,31 @@ func (rpo *ringpopMonitor) GetReachableMembers() ([]string, error) {
 	return rpo.rp.GetReachableMembers()
 }
 
+func (rpo *ringpopMonitor) SetSelfLabel(key string, value string) error {
+	labels, err := rpo.rp.Labels()
+	if err != nil {
+		return err
+	}
+	return labels.Set(key, value)
+}
+
+func (rpo *ringpopMonitor) GetMemberLabels(service string, key string) (map[string]string, error) {
+	values := make(map[string]string)
+	_, err := rpo.rp.GetReachableMembers(
+		swim.MemberWithLabelAndValue(RoleKey, service),
+		func(member swim.Member) bool {
+			if value, ok := member.Label(key); ok {
+				values[member.Address] = value
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
 func replaceServicePort(address string, servicePort int) (string, error) {
 	parts := strings.Split(address, ":")
 	if len(parts) != 2 {
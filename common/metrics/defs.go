@@ -334,6 +334,8 @@ const (
 	HistoryClientGetDLQReplicationTasksScope
 	// HistoryClientQueryWorkflowScope tracks RPC calls to history service
 	HistoryClientQueryWorkflowScope
+	// HistoryClientUpdateWorkflowExecutionScope tracks RPC calls to history service
+	HistoryClientUpdateWorkflowExecutionScope
 	// HistoryClientReapplyEventsScope tracks RPC calls to history service
 	HistoryClientReapplyEventsScope
 	// HistoryClientReadDLQMessagesScope tracks RPC calls to history service
@@ -344,6 +346,8 @@ const (
 	HistoryClientMergeDLQMessagesScope
 	// HistoryClientRefreshWorkflowTasksScope tracks RPC calls to history service
 	HistoryClientRefreshWorkflowTasksScope
+	// HistoryClientDeleteWorkflowExecutionScope tracks RPC calls to history service
+	HistoryClientDeleteWorkflowExecutionScope
 	// MatchingClientPollForDecisionTaskScope tracks RPC calls to matching service
 	MatchingClientPollForDecisionTaskScope
 	// MatchingClientPollForActivityTaskScope tracks RPC calls to matching service
@@ -362,6 +366,12 @@ const (
 	MatchingClientDescribeTaskListScope
 	// MatchingClientListTaskListPartitionsScope tracks RPC calls to matching service
 	MatchingClientListTaskListPartitionsScope
+	// MatchingClientUpdateTaskListDispatchConfigScope tracks RPC calls to matching service
+	MatchingClientUpdateTaskListDispatchConfigScope
+	// MatchingClientListTaskListTasksScope tracks RPC calls to matching service
+	MatchingClientListTaskListTasksScope
+	// MatchingClientDeleteTaskListTasksScope tracks RPC calls to matching service
+	MatchingClientDeleteTaskListTasksScope
 	// FrontendClientDeprecateDomainScope tracks RPC calls to frontend service
 	FrontendClientDeprecateDomainScope
 	// FrontendClientDescribeDomainScope tracks RPC calls to frontend service
@@ -472,6 +482,30 @@ const (
 	AdminClientMergeDLQMessagesScope
 	// AdminClientRefreshWorkflowTasksScope tracks RPC calls to admin service
 	AdminClientRefreshWorkflowTasksScope
+	// AdminClientDeleteWorkflowExecutionScope tracks RPC calls to admin service
+	AdminClientDeleteWorkflowExecutionScope
+	// AdminClientListDynamicConfigScope tracks RPC calls to admin service
+	AdminClientListDynamicConfigScope
+	// AdminClientExportWorkflowExecutionScope tracks RPC calls to admin service
+	AdminClientExportWorkflowExecutionScope
+	// AdminClientImportWorkflowExecutionScope tracks RPC calls to admin service
+	AdminClientImportWorkflowExecutionScope
+	// AdminClientListTaskListTasksScope tracks RPC calls to admin service
+	AdminClientListTaskListTasksScope
+	// AdminClientCountTaskListTasksScope tracks RPC calls to admin service
+	AdminClientCountTaskListTasksScope
+	// AdminClientDeleteTaskListTasksScope tracks RPC calls to admin service
+	AdminClientDeleteTaskListTasksScope
+	// AdminClientMoveTaskListTasksScope tracks RPC calls to admin service
+	AdminClientMoveTaskListTasksScope
+	// AdminClientUpdateTaskListDispatchConfigScope tracks RPC calls to admin service
+	AdminClientUpdateTaskListDispatchConfigScope
+	// AdminClientDescribeTaskListDispatchConfigScope tracks RPC calls to admin service
+	AdminClientDescribeTaskListDispatchConfigScope
+	// AdminClientDescribeShardOwnershipScope tracks RPC calls to admin service
+	AdminClientDescribeShardOwnershipScope
+	// AdminClientDescribeShardLoadScope tracks RPC calls to admin service
+	AdminClientDescribeShardLoadScope
 	// DCRedirectionDeprecateDomainScope tracks RPC calls for dc redirection
 	DCRedirectionDeprecateDomainScope
 	// DCRedirectionDescribeDomainScope tracks RPC calls for dc redirection
@@ -673,6 +707,30 @@ const (
 	AdminReapplyEventsScope
 	// AdminRefreshWorkflowTasksScope is the metric scope for admin.RefreshWorkflowTasks
 	AdminRefreshWorkflowTasksScope
+	// AdminDeleteWorkflowExecutionScope is the metric scope for admin.DeleteWorkflowExecution
+	AdminDeleteWorkflowExecutionScope
+	// AdminListDynamicConfigScope is the metric scope for admin.ListDynamicConfig
+	AdminListDynamicConfigScope
+	// AdminExportWorkflowExecutionScope is the metric scope for admin.ExportWorkflowExecution
+	AdminExportWorkflowExecutionScope
+	// AdminImportWorkflowExecutionScope is the metric scope for admin.ImportWorkflowExecution
+	AdminImportWorkflowExecutionScope
+	// AdminListTaskListTasksScope is the metric scope for admin.ListTaskListTasks
+	AdminListTaskListTasksScope
+	// AdminCountTaskListTasksScope is the metric scope for admin.CountTaskListTasks
+	AdminCountTaskListTasksScope
+	// AdminDeleteTaskListTasksScope is the metric scope for admin.DeleteTaskListTasks
+	AdminDeleteTaskListTasksScope
+	// AdminMoveTaskListTasksScope is the metric scope for admin.MoveTaskListTasks
+	AdminMoveTaskListTasksScope
+	// AdminUpdateTaskListDispatchConfigScope is the metric scope for admin.UpdateTaskListDispatchConfig
+	AdminUpdateTaskListDispatchConfigScope
+	// AdminDescribeTaskListDispatchConfigScope is the metric scope for admin.DescribeTaskListDispatchConfig
+	AdminDescribeTaskListDispatchConfigScope
+	// AdminDescribeShardOwnershipScope is the metric scope for admin.DescribeShardOwnership
+	AdminDescribeShardOwnershipScope
+	// AdminDescribeShardLoadScope is the metric scope for admin.DescribeShardLoad
+	AdminDescribeShardLoadScope
 	// AdminRemoveTaskScope is the metric scope for admin.AdminRemoveTaskScope
 	AdminRemoveTaskScope
 	//AdminCloseShardTaskScope is the metric scope for admin.AdminRemoveTaskScope
@@ -767,6 +825,24 @@ const (
 	FrontendResetWorkflowExecutionScope
 	// FrontendGetSearchAttributesScope is the metric scope for frontend.GetSearchAttributes
 	FrontendGetSearchAttributesScope
+	// FrontendCreateScheduleScope is the metric scope for frontend.CreateSchedule
+	FrontendCreateScheduleScope
+	// FrontendDescribeScheduleScope is the metric scope for frontend.DescribeSchedule
+	FrontendDescribeScheduleScope
+	// FrontendUpdateScheduleScope is the metric scope for frontend.UpdateSchedule
+	FrontendUpdateScheduleScope
+	// FrontendPauseScheduleScope is the metric scope for frontend.PauseSchedule
+	FrontendPauseScheduleScope
+	// FrontendResumeScheduleScope is the metric scope for frontend.ResumeSchedule
+	FrontendResumeScheduleScope
+	// FrontendBackfillScheduleScope is the metric scope for frontend.BackfillSchedule
+	FrontendBackfillScheduleScope
+	// FrontendDeleteScheduleScope is the metric scope for frontend.DeleteSchedule
+	FrontendDeleteScheduleScope
+	// FrontendListSchedulesScope is the metric scope for frontend.ListSchedules
+	FrontendListSchedulesScope
+	// FrontendUpdateWorkflowExecutionScope is the metric scope for frontend.UpdateWorkflowExecution
+	FrontendUpdateWorkflowExecutionScope
 
 	NumFrontendScopes
 )
@@ -841,6 +917,8 @@ const (
 	HistoryReapplyEventsScope
 	// HistoryRefreshWorkflowTasksScope is the scope used by refresh workflow tasks API
 	HistoryRefreshWorkflowTasksScope
+	// HistoryDeleteWorkflowExecutionScope is the scope used by delete workflow execution API
+	HistoryDeleteWorkflowExecutionScope
 	// TaskPriorityAssignerScope is the scope used by all metric emitted by task priority assigner
 	TaskPriorityAssignerScope
 	// TransferQueueProcessorScope is the scope used by all metric emitted by transfer queue processor
@@ -961,6 +1039,8 @@ const (
 	HistoryResetWorkflowExecutionScope
 	// HistoryQueryWorkflowScope tracks QueryWorkflow API calls received by service
 	HistoryQueryWorkflowScope
+	// HistoryUpdateWorkflowExecutionScope tracks UpdateWorkflowExecution API calls received by service
+	HistoryUpdateWorkflowExecutionScope
 	// HistoryProcessDeleteHistoryEventScope tracks ProcessDeleteHistoryEvent processing calls
 	HistoryProcessDeleteHistoryEventScope
 	// WorkflowCompletionStatsScope tracks workflow completion updates
@@ -999,6 +1079,12 @@ const (
 	MatchingDescribeTaskListScope
 	// MatchingListTaskListPartitionsScope tracks ListTaskListPartitions API calls received by service
 	MatchingListTaskListPartitionsScope
+	// MatchingUpdateTaskListDispatchConfigScope tracks UpdateTaskListDispatchConfig API calls received by service
+	MatchingUpdateTaskListDispatchConfigScope
+	// MatchingListTaskListTasksScope tracks ListTaskListTasks API calls received by service
+	MatchingListTaskListTasksScope
+	// MatchingDeleteTaskListTasksScope tracks DeleteTaskListTasks API calls received by service
+	MatchingDeleteTaskListTasksScope
 
 	NumMatchingScopes
 )
@@ -1045,6 +1131,8 @@ const (
 	HistoryScavengerScope
 	// ParentClosePolicyProcessorScope is scope used by all metrics emitted by worker.ParentClosePolicyProcessor
 	ParentClosePolicyProcessorScope
+	// SchedulerScope is scope used by all metrics emitted by worker.Scheduler module
+	SchedulerScope
 
 	NumWorkerScopes
 )
@@ -1167,11 +1255,13 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		HistoryClientGetReplicationTasksScope:                 {operation: "HistoryClientGetReplicationTasksScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientGetDLQReplicationTasksScope:              {operation: "HistoryClientGetDLQReplicationTasksScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientQueryWorkflowScope:                       {operation: "HistoryClientQueryWorkflowScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
+		HistoryClientUpdateWorkflowExecutionScope:             {operation: "HistoryClientUpdateWorkflowExecutionScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientReapplyEventsScope:                       {operation: "HistoryClientReapplyEventsScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientReadDLQMessagesScope:                     {operation: "HistoryClientReadDLQMessagesScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientPurgeDLQMessagesScope:                    {operation: "HistoryClientPurgeDLQMessagesScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientMergeDLQMessagesScope:                    {operation: "HistoryClientMergeDLQMessagesScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientRefreshWorkflowTasksScope:                {operation: "HistoryClientRefreshWorkflowTasksScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
+		HistoryClientDeleteWorkflowExecutionScope:             {operation: "HistoryClientDeleteWorkflowExecution", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		MatchingClientPollForDecisionTaskScope:                {operation: "MatchingClientPollForDecisionTask", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
 		MatchingClientPollForActivityTaskScope:                {operation: "MatchingClientPollForActivityTask", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
 		MatchingClientAddActivityTaskScope:                    {operation: "MatchingClientAddActivityTask", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
@@ -1181,6 +1271,9 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		MatchingClientCancelOutstandingPollScope:              {operation: "MatchingClientCancelOutstandingPoll", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
 		MatchingClientDescribeTaskListScope:                   {operation: "MatchingClientDescribeTaskList", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
 		MatchingClientListTaskListPartitionsScope:             {operation: "MatchingClientListTaskListPartitions", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
+		MatchingClientUpdateTaskListDispatchConfigScope:       {operation: "MatchingClientUpdateTaskListDispatchConfig", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
+		MatchingClientListTaskListTasksScope:                  {operation: "MatchingClientListTaskListTasks", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
+		MatchingClientDeleteTaskListTasksScope:                {operation: "MatchingClientDeleteTaskListTasks", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
 		FrontendClientDeprecateDomainScope:                    {operation: "FrontendClientDeprecateDomain", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
 		FrontendClientDescribeDomainScope:                     {operation: "FrontendClientDescribeDomain", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
 		FrontendClientDescribeTaskListScope:                   {operation: "FrontendClientDescribeTaskList", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
@@ -1232,6 +1325,18 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		AdminClientGetWorkflowExecutionRawHistoryV2Scope:      {operation: "AdminClientGetWorkflowExecutionRawHistoryV2", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
 		AdminClientDescribeClusterScope:                       {operation: "AdminClientDescribeCluster", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
 		AdminClientRefreshWorkflowTasksScope:                  {operation: "AdminClientRefreshWorkflowTasks", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientDeleteWorkflowExecutionScope:               {operation: "AdminClientDeleteWorkflowExecution", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientListDynamicConfigScope:                     {operation: "AdminClientListDynamicConfig", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientExportWorkflowExecutionScope:               {operation: "AdminClientExportWorkflowExecution", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientImportWorkflowExecutionScope:               {operation: "AdminClientImportWorkflowExecution", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientListTaskListTasksScope:                     {operation: "AdminClientListTaskListTasks", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientCountTaskListTasksScope:                    {operation: "AdminClientCountTaskListTasks", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientDeleteTaskListTasksScope:                   {operation: "AdminClientDeleteTaskListTasks", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientMoveTaskListTasksScope:                     {operation: "AdminClientMoveTaskListTasks", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientUpdateTaskListDispatchConfigScope:          {operation: "AdminClientUpdateTaskListDispatchConfig", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientDescribeTaskListDispatchConfigScope:        {operation: "AdminClientDescribeTaskListDispatchConfig", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientDescribeShardOwnershipScope:                {operation: "AdminClientDescribeShardOwnership", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientDescribeShardLoadScope:                     {operation: "AdminClientDescribeShardLoad", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
 		AdminClientCloseShardScope:                            {operation: "AdminClientCloseShard", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
 		AdminClientReadDLQMessagesScope:                       {operation: "AdminClientReadDLQMessages", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
 		AdminClientPurgeDLQMessagesScope:                      {operation: "AdminClientPurgeDLQMessages", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
@@ -1333,6 +1438,18 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		AdminGetDLQReplicationMessagesScope:        {operation: "AdminGetDLQReplicationMessages"},
 		AdminReapplyEventsScope:                    {operation: "ReapplyEvents"},
 		AdminRefreshWorkflowTasksScope:             {operation: "RefreshWorkflowTasks"},
+		AdminDeleteWorkflowExecutionScope:          {operation: "DeleteWorkflowExecution"},
+		AdminListDynamicConfigScope:                {operation: "ListDynamicConfig"},
+		AdminExportWorkflowExecutionScope:          {operation: "ExportWorkflowExecution"},
+		AdminImportWorkflowExecutionScope:          {operation: "ImportWorkflowExecution"},
+		AdminListTaskListTasksScope:                {operation: "ListTaskListTasks"},
+		AdminCountTaskListTasksScope:               {operation: "CountTaskListTasks"},
+		AdminDeleteTaskListTasksScope:              {operation: "DeleteTaskListTasks"},
+		AdminMoveTaskListTasksScope:                {operation: "MoveTaskListTasks"},
+		AdminUpdateTaskListDispatchConfigScope:     {operation: "UpdateTaskListDispatchConfig"},
+		AdminDescribeTaskListDispatchConfigScope:   {operation: "DescribeTaskListDispatchConfig"},
+		AdminDescribeShardOwnershipScope:           {operation: "DescribeShardOwnership"},
+		AdminDescribeShardLoadScope:                {operation: "DescribeShardLoad"},
 
 		FrontendStartWorkflowExecutionScope:             {operation: "StartWorkflowExecution"},
 		FrontendPollForDecisionTaskScope:                {operation: "PollForDecisionTask"},
@@ -1373,6 +1490,15 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		FrontendDescribeTaskListScope:                   {operation: "DescribeTaskList"},
 		FrontendResetStickyTaskListScope:                {operation: "ResetStickyTaskList"},
 		FrontendGetSearchAttributesScope:                {operation: "GetSearchAttributes"},
+		FrontendCreateScheduleScope:                     {operation: "CreateSchedule"},
+		FrontendDescribeScheduleScope:                   {operation: "DescribeSchedule"},
+		FrontendUpdateScheduleScope:                     {operation: "UpdateSchedule"},
+		FrontendPauseScheduleScope:                      {operation: "PauseSchedule"},
+		FrontendResumeScheduleScope:                     {operation: "ResumeSchedule"},
+		FrontendBackfillScheduleScope:                   {operation: "BackfillSchedule"},
+		FrontendDeleteScheduleScope:                     {operation: "DeleteSchedule"},
+		FrontendListSchedulesScope:                      {operation: "ListSchedules"},
+		FrontendUpdateWorkflowExecutionScope:            {operation: "UpdateWorkflowExecution"},
 	},
 	// History Scope Names
 	History: {
@@ -1395,6 +1521,7 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		HistoryTerminateWorkflowExecutionScope:                 {operation: "TerminateWorkflowExecution"},
 		HistoryResetWorkflowExecutionScope:                     {operation: "ResetWorkflowExecution"},
 		HistoryQueryWorkflowScope:                              {operation: "QueryWorkflow"},
+		HistoryUpdateWorkflowExecutionScope:                    {operation: "UpdateWorkflowExecution"},
 		HistoryProcessDeleteHistoryEventScope:                  {operation: "ProcessDeleteHistoryEvent"},
 		HistoryScheduleDecisionTaskScope:                       {operation: "ScheduleDecisionTask"},
 		HistoryRecordChildExecutionCompletedScope:              {operation: "RecordChildExecutionCompleted"},
@@ -1413,6 +1540,7 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		HistoryShardControllerScope:                            {operation: "ShardController"},
 		HistoryReapplyEventsScope:                              {operation: "EventReapplication"},
 		HistoryRefreshWorkflowTasksScope:                       {operation: "RefreshWorkflowTasks"},
+		HistoryDeleteWorkflowExecutionScope:                    {operation: "DeleteWorkflowExecution"},
 		TaskPriorityAssignerScope:                              {operation: "TaskPriorityAssigner"},
 		TransferQueueProcessorScope:                            {operation: "TransferQueueProcessor"},
 		TransferActiveQueueProcessorScope:                      {operation: "TransferActiveQueueProcessor"},
@@ -1479,16 +1607,19 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 	},
 	// Matching Scope Names
 	Matching: {
-		MatchingPollForDecisionTaskScope:       {operation: "PollForDecisionTask"},
-		MatchingPollForActivityTaskScope:       {operation: "PollForActivityTask"},
-		MatchingAddActivityTaskScope:           {operation: "AddActivityTask"},
-		MatchingAddDecisionTaskScope:           {operation: "AddDecisionTask"},
-		MatchingTaskListMgrScope:               {operation: "TaskListMgr"},
-		MatchingQueryWorkflowScope:             {operation: "QueryWorkflow"},
-		MatchingRespondQueryTaskCompletedScope: {operation: "RespondQueryTaskCompleted"},
-		MatchingCancelOutstandingPollScope:     {operation: "CancelOutstandingPoll"},
-		MatchingDescribeTaskListScope:          {operation: "DescribeTaskList"},
-		MatchingListTaskListPartitionsScope:    {operation: "ListTaskListPartitions"},
+		MatchingPollForDecisionTaskScope:          {operation: "PollForDecisionTask"},
+		MatchingPollForActivityTaskScope:          {operation: "PollForActivityTask"},
+		MatchingAddActivityTaskScope:              {operation: "AddActivityTask"},
+		MatchingAddDecisionTaskScope:              {operation: "AddDecisionTask"},
+		MatchingTaskListMgrScope:                  {operation: "TaskListMgr"},
+		MatchingQueryWorkflowScope:                {operation: "QueryWorkflow"},
+		MatchingRespondQueryTaskCompletedScope:    {operation: "RespondQueryTaskCompleted"},
+		MatchingCancelOutstandingPollScope:        {operation: "CancelOutstandingPoll"},
+		MatchingDescribeTaskListScope:             {operation: "DescribeTaskList"},
+		MatchingListTaskListPartitionsScope:       {operation: "ListTaskListPartitions"},
+		MatchingUpdateTaskListDispatchConfigScope: {operation: "UpdateTaskListDispatchConfig"},
+		MatchingListTaskListTasksScope:            {operation: "ListTaskListTasks"},
+		MatchingDeleteTaskListTasksScope:          {operation: "DeleteTaskListTasks"},
 	},
 	// Worker Scope Names
 	Worker: {
@@ -1512,6 +1643,7 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		HistoryScavengerScope:                  {operation: "historyscavenger"},
 		BatcherScope:                           {operation: "batcher"},
 		ParentClosePolicyProcessorScope:        {operation: "ParentClosePolicyProcessor"},
+		SchedulerScope:                         {operation: "scheduler"},
 	},
 }
 
@@ -1673,6 +1805,15 @@ const (
 	ShardItemCreatedCounter
 	ShardItemRemovedCounter
 	ShardItemAcquisitionLatency
+	ShardHandoffWaitLatency
+	ShardHandedOffCounter
+	ShardStolenCounter
+	ShardReleasedCounter
+	ShardReleaseFailedCounter
+	HotShardDetectedCounter
+	HotWorkflowThrottledCounter
+	HotWorkflowRequestThrottledCounter
+	WorkflowRateLimitedCounter
 	ShardInfoReplicationPendingTasksTimer
 	ShardInfoTransferActivePendingTasksTimer
 	ShardInfoTransferStandbyPendingTasksTimer
@@ -1792,6 +1933,7 @@ const (
 const (
 	PollSuccessCounter = iota + NumCommonMetrics
 	PollTimeoutCounter
+	PollThrottledCounter
 	PollSuccessWithSyncCounter
 	LeaseRequestCounter
 	LeaseFailureCounter
@@ -1812,6 +1954,12 @@ const (
 	ForwardPollCalls
 	ForwardPollErrors
 	ForwardPollLatency
+	ForwardBackoffCounter
+	ForwardTaskRateGauge
+	ForwardQueryRateGauge
+	ForwardPollRateGauge
+	TaskListReadPartitionsGauge
+	TaskListWritePartitionsGauge
 	LocalToLocalMatchCounter
 	LocalToRemoteMatchCounter
 	RemoteToLocalMatchCounter
@@ -1877,6 +2025,8 @@ const (
 	HistoryScavengerSkipCount
 	ParentClosePolicyProcessorSuccess
 	ParentClosePolicyProcessorFailures
+	SchedulerActionsTaken
+	SchedulerActionFailures
 	DomainReplicationEnqueueDLQCount
 
 	NumWorkerMetrics
@@ -2021,6 +2171,15 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		ShardItemCreatedCounter:                           {metricName: "sharditem_created_count", metricType: Counter},
 		ShardItemRemovedCounter:                           {metricName: "sharditem_removed_count", metricType: Counter},
 		ShardItemAcquisitionLatency:                       {metricName: "sharditem_acquisition_latency", metricType: Timer},
+		ShardHandoffWaitLatency:                           {metricName: "shard_handoff_wait_latency", metricType: Timer},
+		ShardHandedOffCounter:                             {metricName: "shard_handed_off_count", metricType: Counter},
+		ShardStolenCounter:                                {metricName: "shard_stolen_count", metricType: Counter},
+		ShardReleasedCounter:                              {metricName: "shard_released_count", metricType: Counter},
+		ShardReleaseFailedCounter:                         {metricName: "shard_release_failed_count", metricType: Counter},
+		HotShardDetectedCounter:                           {metricName: "hot_shard_detected_count", metricType: Counter},
+		HotWorkflowThrottledCounter:                       {metricName: "hot_workflow_throttled_count", metricType: Counter},
+		HotWorkflowRequestThrottledCounter:                {metricName: "hot_workflow_request_throttled_count", metricType: Counter},
+		WorkflowRateLimitedCounter:                        {metricName: "workflow_rate_limited_count", metricType: Counter},
 		ShardInfoReplicationPendingTasksTimer:             {metricName: "shardinfo_replication_pending_task", metricType: Timer},
 		ShardInfoTransferActivePendingTasksTimer:          {metricName: "shardinfo_transfer_active_pending_task", metricType: Timer},
 		ShardInfoTransferStandbyPendingTasksTimer:         {metricName: "shardinfo_transfer_standby_pending_task", metricType: Timer},
@@ -2136,6 +2295,7 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 	Matching: {
 		PollSuccessCounter:            {metricName: "poll_success"},
 		PollTimeoutCounter:            {metricName: "poll_timeouts"},
+		PollThrottledCounter:          {metricName: "poll_throttled"},
 		PollSuccessWithSyncCounter:    {metricName: "poll_success_sync"},
 		LeaseRequestCounter:           {metricName: "lease_requests"},
 		LeaseFailureCounter:           {metricName: "lease_failures"},
@@ -2156,6 +2316,12 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		ForwardTaskLatency:            {metricName: "forward_task_latency"},
 		ForwardQueryLatency:           {metricName: "forward_query_latency"},
 		ForwardPollLatency:            {metricName: "forward_poll_latency"},
+		ForwardBackoffCounter:         {metricName: "forward_backoff"},
+		ForwardTaskRateGauge:          {metricName: "forward_task_rate", metricType: Gauge},
+		ForwardQueryRateGauge:         {metricName: "forward_query_rate", metricType: Gauge},
+		ForwardPollRateGauge:          {metricName: "forward_poll_rate", metricType: Gauge},
+		TaskListReadPartitionsGauge:   {metricName: "tasklist_read_partitions", metricType: Gauge},
+		TaskListWritePartitionsGauge:  {metricName: "tasklist_write_partitions", metricType: Gauge},
 		LocalToLocalMatchCounter:      {metricName: "local_to_local_matches"},
 		LocalToRemoteMatchCounter:     {metricName: "local_to_remote_matches"},
 		RemoteToLocalMatchCounter:     {metricName: "remote_to_local_matches"},
@@ -2217,10 +2383,20 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		HistoryScavengerSkipCount:                     {metricName: "scavenger_skips", metricType: Counter},
 		ParentClosePolicyProcessorSuccess:             {metricName: "parent_close_policy_processor_requests", metricType: Counter},
 		ParentClosePolicyProcessorFailures:            {metricName: "parent_close_policy_processor_errors", metricType: Counter},
+		SchedulerActionsTaken:                         {metricName: "scheduler_actions_taken", metricType: Counter},
+		SchedulerActionFailures:                       {metricName: "scheduler_action_errors", metricType: Counter},
 		DomainReplicationEnqueueDLQCount:              {metricName: "domain_replication_dlq_enqueue_requests", metricType: Counter},
 	},
 }
 
+// GetScopeOperation returns the operation name of a scope of the given service
+func GetScopeOperation(serviceIdx ServiceIdx, scopeIdx int) string {
+	if def, ok := ScopeDefs[Common][scopeIdx]; ok {
+		return def.operation
+	}
+	return ScopeDefs[serviceIdx][scopeIdx].operation
+}
+
 // ErrorClass is an enum to help with classifying SLA vs. non-SLA errors (SLA = "service level agreement")
 type ErrorClass uint8
 
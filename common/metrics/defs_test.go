@@ -105,3 +105,9 @@ func TestMetricDefs(t *testing.T) {
 		}
 	}
 }
+
+func TestGetScopeOperation(t *testing.T) {
+	assert.Equal(t, "CreateShard", GetScopeOperation(History, PersistenceCreateShardScope))
+	assert.Equal(t, "SignalWorkflowExecution", GetScopeOperation(History, HistorySignalWorkflowExecutionScope))
+	assert.Equal(t, "PollForDecisionTask", GetScopeOperation(Frontend, FrontendPollForDecisionTaskScope))
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/temporalio/temporal/common/metrics"
 	p "github.com/temporalio/temporal/common/persistence"
 	"github.com/temporalio/temporal/common/persistence/cassandra"
+	"github.com/temporalio/temporal/common/persistence/memory"
 	"github.com/temporalio/temporal/common/persistence/sql"
 	"github.com/temporalio/temporal/common/quotas"
 	"github.com/temporalio/temporal/common/service/config"
@@ -307,7 +308,7 @@ func (f *factoryImpl) Close() {
 
 func (f *factoryImpl) isCassandra() bool {
 	cfg := f.config
-	return cfg.DataStores[cfg.VisibilityStore].SQL == nil
+	return cfg.DataStores[cfg.VisibilityStore].Cassandra != nil
 }
 
 func (f *factoryImpl) getCassandraConfig() *config.Cassandra {
@@ -324,10 +325,12 @@ func (f *factoryImpl) init(clusterName string, limiters map[string]quotas.Limite
 		defaultDataStore.factory = cassandra.NewFactory(*defaultCfg.Cassandra, clusterName, f.logger)
 	case defaultCfg.SQL != nil:
 		defaultDataStore.factory = sql.NewFactory(*defaultCfg.SQL, clusterName, f.logger)
+	case defaultCfg.Memory != nil:
+		defaultDataStore.factory = memory.NewFactory(*defaultCfg.Memory, clusterName, f.logger)
 	case defaultCfg.CustomDataStoreConfig != nil:
 		defaultDataStore.factory = f.abstractDataStoreFactory.NewFactory(*defaultCfg.CustomDataStoreConfig, clusterName, f.logger)
 	default:
-		f.logger.Fatal("invalid config: one of cassandra or sql params must be specified")
+		f.logger.Fatal("invalid config: one of cassandra, sql or memory params must be specified")
 	}
 
 	for _, st := range storeTypes {
@@ -343,8 +346,10 @@ func (f *factoryImpl) init(clusterName string, limiters map[string]quotas.Limite
 		visibilityDataStore.factory = cassandra.NewFactory(*visibilityCfg.Cassandra, clusterName, f.logger)
 	case visibilityCfg.SQL != nil:
 		visibilityDataStore.factory = sql.NewFactory(*visibilityCfg.SQL, clusterName, f.logger)
+	case visibilityCfg.Memory != nil:
+		visibilityDataStore.factory = memory.NewFactory(*visibilityCfg.Memory, clusterName, f.logger)
 	default:
-		f.logger.Fatal("invalid config: one of cassandra or sql params must be specified")
+		f.logger.Fatal("invalid config: one of cassandra, sql or memory params must be specified")
 	}
 
 	f.datastores[storeTypeVisibility] = visibilityDataStore
@@ -316,6 +316,9 @@ type (
 		ClientLibraryVersion               string
 		ClientFeatureVersion               string
 		ClientImpl                         string
+		WorkerBuildID                      string
+		TaskPriority                       int32
+		TaskFairnessKey                    string
 		AutoResetPoints                    *commonproto.ResetPoints
 		Memo                               map[string][]byte
 		SearchAttributes                   map[string][]byte
@@ -610,6 +613,9 @@ type (
 		LastFailureReason  string
 		LastWorkerIdentity string
 		LastFailureDetails []byte
+		// Dispatch priority and fairness key of the activity tasks in matching
+		Priority    int32
+		FairnessKey string
 		// Not written to database - This is used only for deduping heartbeat timer creation
 		LastHeartbeatTimeoutVisibility int64
 	}
@@ -166,6 +166,9 @@ func (m *executionManagerImpl) DeserializeExecutionInfo(
 		ClientLibraryVersion:               info.ClientLibraryVersion,
 		ClientFeatureVersion:               info.ClientFeatureVersion,
 		ClientImpl:                         info.ClientImpl,
+		WorkerBuildID:                      info.WorkerBuildID,
+		TaskPriority:                       info.TaskPriority,
+		TaskFairnessKey:                    info.TaskFairnessKey,
 		Attempt:                            info.Attempt,
 		HasRetryPolicy:                     info.HasRetryPolicy,
 		InitialInterval:                    info.InitialInterval,
@@ -298,6 +301,8 @@ func (m *executionManagerImpl) DeserializeActivityInfos(
 			LastFailureReason:              v.LastFailureReason,
 			LastWorkerIdentity:             v.LastWorkerIdentity,
 			LastFailureDetails:             v.LastFailureDetails,
+			Priority:                       v.Priority,
+			FairnessKey:                    v.FairnessKey,
 			LastHeartbeatTimeoutVisibility: v.LastHeartbeatTimeoutVisibility,
 		}
 		newInfos[k] = a
@@ -419,6 +424,8 @@ func (m *executionManagerImpl) SerializeUpsertActivityInfos(
 			LastFailureReason:              v.LastFailureReason,
 			LastWorkerIdentity:             v.LastWorkerIdentity,
 			LastFailureDetails:             v.LastFailureDetails,
+			Priority:                       v.Priority,
+			FairnessKey:                    v.FairnessKey,
 			LastHeartbeatTimeoutVisibility: v.LastHeartbeatTimeoutVisibility,
 		}
 		newInfos = append(newInfos, i)
@@ -486,6 +493,9 @@ func (m *executionManagerImpl) SerializeExecutionInfo(
 		ClientLibraryVersion:               info.ClientLibraryVersion,
 		ClientFeatureVersion:               info.ClientFeatureVersion,
 		ClientImpl:                         info.ClientImpl,
+		WorkerBuildID:                      info.WorkerBuildID,
+		TaskPriority:                       info.TaskPriority,
+		TaskFairnessKey:                    info.TaskFairnessKey,
 		AutoResetPoints:                    resetPoints,
 		Attempt:                            info.Attempt,
 		HasRetryPolicy:                     info.HasRetryPolicy,
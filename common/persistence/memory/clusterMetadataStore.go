@@ -0,0 +1,183 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pborman/uuid"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log"
+	p "github.com/temporalio/temporal/common/persistence"
+)
+
+type (
+	clusterMetadataStore struct {
+		memoryStore
+	}
+
+	// clusterMemberRow is keyed by host ID. Every upsert assigns a new insertion
+	// order, the same way REPLACE INTO does with the SQL auto increment column.
+	clusterMemberRow struct {
+		p.ClusterMember
+		insertionOrder int64
+	}
+)
+
+var _ p.ClusterMetadataStore = (*clusterMetadataStore)(nil)
+
+func newClusterMetadataPersistence(
+	db *database,
+	logger log.Logger,
+) p.ClusterMetadataStore {
+
+	return &clusterMetadataStore{
+		memoryStore: memoryStore{
+			db:     db,
+			logger: logger,
+		},
+	}
+}
+
+func (s *clusterMetadataStore) InitializeImmutableClusterMetadata(request *p.InternalInitializeImmutableClusterMetadataRequest) (*p.InternalInitializeImmutableClusterMetadataResponse, error) {
+	s.db.Lock()
+	defer s.db.Unlock()
+
+	if row := s.db.clusterMetadata; row != nil {
+		return &p.InternalInitializeImmutableClusterMetadataResponse{
+			PersistedImmutableMetadata: p.NewDataBlob(copyBytes(row.data), common.EncodingType(row.encoding)),
+			RequestApplied:             false,
+		}, nil
+	}
+
+	s.db.clusterMetadata = newBlobRow(request.ImmutableClusterMetadata.Data, string(request.ImmutableClusterMetadata.Encoding))
+	return &p.InternalInitializeImmutableClusterMetadataResponse{
+		PersistedImmutableMetadata: request.ImmutableClusterMetadata,
+		RequestApplied:             true,
+	}, nil
+}
+
+func (s *clusterMetadataStore) GetImmutableClusterMetadata() (*p.InternalGetImmutableClusterMetadataResponse, error) {
+	s.db.Lock()
+	defer s.db.Unlock()
+
+	row := s.db.clusterMetadata
+	if row == nil {
+		return nil, serviceerror.NewNotFound("GetImmutableClusterMetadata failed. Cluster metadata is not initialized.")
+	}
+	return &p.InternalGetImmutableClusterMetadataResponse{
+		ImmutableClusterMetadata: p.NewDataBlob(copyBytes(row.data), common.EncodingType(row.encoding)),
+	}, nil
+}
+
+func (s *clusterMetadataStore) GetClusterMembers(request *p.GetClusterMembersRequest) (*p.GetClusterMembersResponse, error) {
+	var pageToken int64
+	if len(request.NextPageToken) > 0 {
+		var err error
+		if pageToken, err = deserializePageToken(request.NextPageToken); err != nil {
+			return nil, serviceerror.NewInternal("GetClusterMembers operation failed. Invalid next page token.")
+		}
+	}
+	now := time.Now().UTC()
+	var lastHeartbeatAfter time.Time
+	if request.LastHeartbeatWithin > 0 {
+		lastHeartbeatAfter = now.Add(-request.LastHeartbeatWithin)
+	}
+
+	s.db.Lock()
+	var rows []*clusterMemberRow
+	for _, row := range s.db.clusterMembers {
+		switch {
+		case request.HostIDEquals != nil && !uuid.Equal(row.HostID, request.HostIDEquals):
+		case request.RPCAddressEquals != nil && !row.RPCAddress.Equal(request.RPCAddressEquals):
+		case request.RoleEquals != p.All && row.Role != request.RoleEquals:
+		case !lastHeartbeatAfter.IsZero() && !row.LastHeartbeat.After(lastHeartbeatAfter):
+		case !row.RecordExpiry.After(now):
+		case !request.SessionStartedAfter.IsZero() && !row.SessionStart.After(request.SessionStartedAfter):
+		case row.insertionOrder <= pageToken:
+		default:
+			rows = append(rows, row)
+		}
+	}
+	s.db.Unlock()
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].insertionOrder < rows[j].insertionOrder })
+	if request.PageSize > 0 && len(rows) > request.PageSize {
+		rows = rows[:request.PageSize]
+	}
+
+	members := make([]*p.ClusterMember, 0, len(rows))
+	for _, row := range rows {
+		member := row.ClusterMember
+		members = append(members, &member)
+	}
+
+	var nextPageToken []byte
+	if request.PageSize > 0 && len(rows) == request.PageSize {
+		nextPageToken = serializePageToken(rows[len(rows)-1].insertionOrder)
+	}
+
+	return &p.GetClusterMembersResponse{ActiveMembers: members, NextPageToken: nextPageToken}, nil
+}
+
+func (s *clusterMetadataStore) UpsertClusterMembership(request *p.UpsertClusterMembershipRequest) error {
+	now := time.Now().UTC()
+
+	s.db.Lock()
+	defer s.db.Unlock()
+
+	s.db.clusterMemberSeqNo++
+	s.db.clusterMembers[request.HostID.String()] = &clusterMemberRow{
+		ClusterMember: p.ClusterMember{
+			Role:          request.Role,
+			HostID:        append([]byte(nil), request.HostID...),
+			RPCAddress:    append([]byte(nil), request.RPCAddress...),
+			RPCPort:       request.RPCPort,
+			SessionStart:  request.SessionStart,
+			LastHeartbeat: now,
+			RecordExpiry:  now.Add(request.RecordExpiry),
+		},
+		insertionOrder: s.db.clusterMemberSeqNo,
+	}
+	return nil
+}
+
+func (s *clusterMetadataStore) PruneClusterMembership(request *p.PruneClusterMembershipRequest) error {
+	now := time.Now().UTC()
+
+	s.db.Lock()
+	defer s.db.Unlock()
+
+	pruned := 0
+	for hostID, row := range s.db.clusterMembers {
+		if request.MaxRecordsPruned > 0 && pruned >= request.MaxRecordsPruned {
+			break
+		}
+		if row.RecordExpiry.Before(now) {
+			delete(s.db.clusterMembers, hostID)
+			pruned++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/temporalio/temporal/common/log"
+)
+
+const storeName = "memory"
+
+type (
+	memoryStore struct {
+		db     *database
+		logger log.Logger
+	}
+
+	// taskRow is a blob row together with the ID it is keyed by
+	taskRow struct {
+		taskID int64
+		*blobRow
+	}
+)
+
+func (m *memoryStore) GetName() string {
+	return storeName
+}
+
+// Close is a noop, the data belongs to the database and not to the store
+func (m *memoryStore) Close() {
+}
+
+func serializePageToken(offset int64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, uint64(offset))
+	return b
+}
+
+func deserializePageToken(payload []byte) (int64, error) {
+	if len(payload) != 8 {
+		return 0, fmt.Errorf("Invalid token of %v length", len(payload))
+	}
+	return int64(binary.LittleEndian.Uint64(payload)), nil
+}
+
+// selectTasks returns the rows with minExclusive < ID <= maxInclusive in ID order,
+// at most pageSize of them unless pageSize is zero. Callers must hold the database lock.
+func selectTasks(tasks map[int64]*blobRow, minExclusive int64, maxInclusive int64, pageSize int) []taskRow {
+	var rows []taskRow
+	for id, row := range tasks {
+		if id > minExclusive && id <= maxInclusive {
+			rows = append(rows, taskRow{taskID: id, blobRow: row})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].taskID < rows[j].taskID })
+	if pageSize > 0 && len(rows) > pageSize {
+		rows = rows[:pageSize]
+	}
+	return rows
+}
+
+// deleteTasks removes the rows with minExclusive < ID <= maxInclusive and returns
+// how many were removed. Callers must hold the database lock.
+func deleteTasks(tasks map[int64]*blobRow, minExclusive int64, maxInclusive int64) int {
+	deleted := 0
+	for id := range tasks {
+		if id > minExclusive && id <= maxInclusive {
+			delete(tasks, id)
+			deleted++
+		}
+	}
+	return deleted
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"sync"
+
+	p "github.com/temporalio/temporal/common/persistence"
+)
+
+type (
+	// database holds all the tables of one named in-memory database. A single
+	// mutex guards the whole database: every store operation validates its
+	// conditions and applies its writes while holding it, which gives the same
+	// all-or-nothing behavior the SQL stores get from transactions.
+	database struct {
+		sync.Mutex
+		name string
+
+		shards            map[int]*shardRow
+		executions        map[executionKey]*executionRow
+		currentExecutions map[currentExecutionKey]*currentExecutionRow
+		transferTasks     map[int]map[int64]*blobRow
+		timerTasks        map[int]map[timerTaskKey]*blobRow
+		replicationTasks  map[int]map[int64]*blobRow
+		replicationDLQ    map[replicationDLQKey]map[int64]*blobRow
+
+		taskLists map[taskListKey]*taskListRow
+		tasks     map[taskListKey]map[int64]*blobRow
+
+		historyNodes map[historyBranchKey]map[historyNodeKey]*blobRow
+		historyTrees map[historyTreeKey]map[string]*blobRow
+
+		domains         map[string]*domainRow
+		domainNames     map[string]string
+		metadataVersion int64
+
+		clusterMetadata    *blobRow
+		clusterMembers     map[string]*clusterMemberRow
+		clusterMemberSeqNo int64
+
+		queues map[p.QueueType]*queueTable
+
+		visibility map[visibilityKey]*visibilityRow
+	}
+
+	// blobRow is a row whose payload is a serialized blob
+	blobRow struct {
+		data     []byte
+		encoding string
+	}
+)
+
+var databases = struct {
+	sync.Mutex
+	byName map[string]*database
+}{byName: make(map[string]*database)}
+
+// getDatabase returns the database with the given name, creating it on first use
+func getDatabase(name string) *database {
+	databases.Lock()
+	defer databases.Unlock()
+	db, ok := databases.byName[name]
+	if !ok {
+		db = newDatabase(name)
+		databases.byName[name] = db
+	}
+	return db
+}
+
+// DropDatabase discards all the data of the named database. Stores created
+// before the drop keep the old data; stores created afterwards start empty.
+func DropDatabase(name string) {
+	databases.Lock()
+	defer databases.Unlock()
+	delete(databases.byName, name)
+}
+
+func newDatabase(name string) *database {
+	return &database{
+		name:              name,
+		shards:            make(map[int]*shardRow),
+		executions:        make(map[executionKey]*executionRow),
+		currentExecutions: make(map[currentExecutionKey]*currentExecutionRow),
+		transferTasks:     make(map[int]map[int64]*blobRow),
+		timerTasks:        make(map[int]map[timerTaskKey]*blobRow),
+		replicationTasks:  make(map[int]map[int64]*blobRow),
+		replicationDLQ:    make(map[replicationDLQKey]map[int64]*blobRow),
+		taskLists:         make(map[taskListKey]*taskListRow),
+		tasks:             make(map[taskListKey]map[int64]*blobRow),
+		historyNodes:      make(map[historyBranchKey]map[historyNodeKey]*blobRow),
+		historyTrees:      make(map[historyTreeKey]map[string]*blobRow),
+		domains:           make(map[string]*domainRow),
+		domainNames:       make(map[string]string),
+		clusterMembers:    make(map[string]*clusterMemberRow),
+		queues:            make(map[p.QueueType]*queueTable),
+		visibility:        make(map[visibilityKey]*visibilityRow),
+	}
+}
+
+// newBlobRow copies the payload so later changes made by the caller to its
+// buffers cannot leak into the stored row
+func newBlobRow(data []byte, encoding string) *blobRow {
+	return &blobRow{data: copyBytes(data), encoding: encoding}
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
@@ -0,0 +1,965 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/.gen/proto/replication"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/collection"
+	"github.com/temporalio/temporal/common/log"
+	p "github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/persistence/serialization"
+)
+
+type (
+	executionStore struct {
+		memoryStore
+		shardID int
+	}
+
+	timerTaskPageToken struct {
+		TaskID    int64
+		Timestamp time.Time
+	}
+)
+
+var _ p.ExecutionStore = (*executionStore)(nil)
+
+// newExecutionPersistence creates an instance of ExecutionStore
+func newExecutionPersistence(
+	db *database,
+	logger log.Logger,
+	shardID int,
+) p.ExecutionStore {
+
+	return &executionStore{
+		shardID: shardID,
+		memoryStore: memoryStore{
+			db:     db,
+			logger: logger,
+		},
+	}
+}
+
+// txExecuteShardLocked runs fn against a new transaction after checking the shard
+// range ID, and commits the transaction only if fn succeeds
+func (m *executionStore) txExecuteShardLocked(
+	operation string,
+	rangeID int64,
+	fn func(tx *executionTx) error,
+) error {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	if err := m.db.checkShardRangeID(m.shardID, rangeID); err != nil {
+		return err
+	}
+	tx := newExecutionTx(m.db, m.shardID)
+	if err := fn(tx); err != nil {
+		switch err.(type) {
+		case *p.ConditionFailedError,
+			*p.CurrentWorkflowConditionFailedError,
+			*serviceerror.Internal,
+			*p.WorkflowExecutionAlreadyStartedError,
+			*p.ShardOwnershipLostError:
+			return err
+		default:
+			return serviceerror.NewInternal(fmt.Sprintf("%v: %v", operation, err))
+		}
+	}
+	tx.commit()
+	return nil
+}
+
+func (m *executionStore) GetShardID() int {
+	return m.shardID
+}
+
+func (m *executionStore) CreateWorkflowExecution(
+	request *p.InternalCreateWorkflowExecutionRequest,
+) (response *p.CreateWorkflowExecutionResponse, err error) {
+
+	err = m.txExecuteShardLocked("CreateWorkflowExecution", request.RangeID, func(tx *executionTx) error {
+		response, err = m.createWorkflowExecutionTx(tx, request)
+		return err
+	})
+	return
+}
+
+func (m *executionStore) createWorkflowExecutionTx(
+	tx *executionTx,
+	request *p.InternalCreateWorkflowExecutionRequest,
+) (*p.CreateWorkflowExecutionResponse, error) {
+
+	newWorkflow := request.NewWorkflowSnapshot
+	executionInfo := newWorkflow.ExecutionInfo
+	domainID := executionInfo.DomainID
+	workflowID := executionInfo.WorkflowID
+
+	if err := p.ValidateCreateWorkflowModeState(
+		request.Mode,
+		newWorkflow,
+	); err != nil {
+		return nil, err
+	}
+
+	switch request.Mode {
+	case p.CreateWorkflowModeContinueAsNew:
+		// cannot create workflow with continue as new mode
+		return nil, serviceerror.NewInternal("CreateWorkflowExecution: operation failed, encounter invalid CreateWorkflowModeContinueAsNew")
+	}
+
+	// current workflow record check
+	if row := tx.lockCurrentExecutionIfExists(domainID, workflowID); row != nil {
+		// current run ID, last write version, current workflow state check
+		switch request.Mode {
+		case p.CreateWorkflowModeBrandNew:
+			return nil, &p.WorkflowExecutionAlreadyStartedError{
+				Msg:              fmt.Sprintf("Workflow execution already running. WorkflowId: %v", workflowID),
+				StartRequestID:   row.createRequestID,
+				RunID:            row.runID,
+				State:            row.state,
+				CloseStatus:      row.closeStatus,
+				LastWriteVersion: row.lastWriteVersion,
+			}
+
+		case p.CreateWorkflowModeWorkflowIDReuse:
+			if request.PreviousLastWriteVersion != row.lastWriteVersion {
+				return nil, &p.CurrentWorkflowConditionFailedError{
+					Msg: fmt.Sprintf("Workflow execution creation condition failed. WorkflowId: %v, "+
+						"LastWriteVersion: %v, PreviousLastWriteVersion: %v",
+						workflowID, row.lastWriteVersion, request.PreviousLastWriteVersion),
+				}
+			}
+			if row.state != p.WorkflowStateCompleted {
+				return nil, &p.CurrentWorkflowConditionFailedError{
+					Msg: fmt.Sprintf("Workflow execution creation condition failed. WorkflowId: %v, "+
+						"State: %v, Expected: %v",
+						workflowID, row.state, p.WorkflowStateCompleted),
+				}
+			}
+			if row.runID != request.PreviousRunID {
+				return nil, &p.CurrentWorkflowConditionFailedError{
+					Msg: fmt.Sprintf("Workflow execution creation condition failed. WorkflowId: %v, "+
+						"RunID: %v, PreviousRunID: %v",
+						workflowID, row.runID, request.PreviousRunID),
+				}
+			}
+
+		case p.CreateWorkflowModeZombie:
+			// zombie workflow creation with existence of current record, this is a noop
+			if err := assertRunIDMismatch(executionInfo.RunID, row.runID); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, serviceerror.NewInternal(fmt.Sprintf("CreteWorkflowExecution: unknown mode: %v", request.Mode))
+		}
+	}
+
+	if err := tx.createOrUpdateCurrentExecution(
+		request.Mode,
+		domainID,
+		workflowID,
+		newCurrentExecutionRow(
+			executionInfo.RunID,
+			executionInfo.CreateRequestID,
+			executionInfo.State,
+			executionInfo.CloseStatus,
+			newWorkflow.StartVersion,
+			newWorkflow.LastWriteVersion,
+		)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.applyWorkflowSnapshotAsNew(&request.NewWorkflowSnapshot); err != nil {
+		return nil, err
+	}
+
+	return &p.CreateWorkflowExecutionResponse{}, nil
+}
+
+func (m *executionStore) GetWorkflowExecution(
+	request *p.GetWorkflowExecutionRequest,
+) (*p.InternalGetWorkflowExecutionResponse, error) {
+
+	// committed rows are never modified in place, so the row can be decoded after unlocking
+	m.db.Lock()
+	execution, ok := m.db.executions[executionKey{
+		shardID:    m.shardID,
+		domainID:   request.DomainID,
+		workflowID: request.Execution.WorkflowId,
+		runID:      request.Execution.RunId,
+	}]
+	m.db.Unlock()
+
+	if !ok {
+		return nil, serviceerror.NewNotFound(fmt.Sprintf("Workflow execution not found.  WorkflowId: %v, RunId: %v",
+			request.Execution.GetWorkflowId(),
+			request.Execution.GetRunId()))
+	}
+
+	info, err := serialization.WorkflowExecutionInfoFromBlob(execution.info.data, execution.info.encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	executionState, err := serialization.WorkflowExecutionStateFromBlob(execution.state.data, execution.state.encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build partial from proto
+	executionInfo := p.ProtoWorkflowExecutionToPartialInternalExecution(info, executionState, execution.nextEventID)
+
+	state := &p.InternalWorkflowMutableState{ExecutionInfo: executionInfo}
+
+	if info.LastWriteEventID != nil {
+		state.ReplicationState = &p.ReplicationState{}
+		state.ReplicationState.StartVersion = info.GetStartVersion()
+		state.ReplicationState.CurrentVersion = info.GetCurrentVersion()
+		state.ReplicationState.LastWriteVersion = execution.lastWriteVersion
+		state.ReplicationState.LastWriteEventID = info.GetLastWriteEventID().Value
+		state.ReplicationState.LastReplicationInfo = make(map[string]*replication.ReplicationInfo, len(info.LastReplicationInfo))
+		for k, v := range info.LastReplicationInfo {
+			state.ReplicationState.LastReplicationInfo[k] = &replication.ReplicationInfo{Version: v.GetVersion(), LastEventId: v.LastEventId}
+		}
+	}
+
+	if info.GetVersionHistories() != nil {
+		state.VersionHistories = p.NewDataBlob(
+			info.GetVersionHistories(),
+			common.EncodingType(info.GetVersionHistoriesEncoding()),
+		)
+	}
+
+	state.ActivityInfos = make(map[int64]*p.InternalActivityInfo, len(execution.activityInfos))
+	for k, v := range execution.activityInfos {
+		decoded, err := serialization.ActivityInfoFromBlob(v.data, v.encoding)
+		if err != nil {
+			return nil, err
+		}
+		state.ActivityInfos[k] = p.ProtoActivityInfoToInternalActivityInfo(decoded)
+	}
+
+	state.TimerInfos = make(map[string]*persistenceblobs.TimerInfo, len(execution.timerInfos))
+	for k, v := range execution.timerInfos {
+		info, err := serialization.TimerInfoFromBlob(v.data, v.encoding)
+		if err != nil {
+			return nil, err
+		}
+		state.TimerInfos[k] = info
+	}
+
+	state.ChildExecutionInfos = make(map[int64]*p.InternalChildExecutionInfo, len(execution.childExecutionInfos))
+	for k, v := range execution.childExecutionInfos {
+		rowInfo, err := serialization.ChildExecutionInfoFromBlob(v.data, v.encoding)
+		if err != nil {
+			return nil, err
+		}
+		info := p.ProtoChildExecutionInfoToInternal(rowInfo)
+		if rowInfo.InitiatedEvent != nil {
+			info.InitiatedEvent = p.NewDataBlob(rowInfo.InitiatedEvent, common.EncodingType(rowInfo.GetInitiatedEventEncoding()))
+		}
+		if rowInfo.StartedEvent != nil {
+			info.StartedEvent = p.NewDataBlob(rowInfo.StartedEvent, common.EncodingType(rowInfo.GetStartedEventEncoding()))
+		}
+		state.ChildExecutionInfos[k] = info
+	}
+
+	state.RequestCancelInfos = make(map[int64]*persistenceblobs.RequestCancelInfo, len(execution.requestCancelInfos))
+	for k, v := range execution.requestCancelInfos {
+		rowInfo, err := serialization.RequestCancelInfoFromBlob(v.data, v.encoding)
+		if err != nil {
+			return nil, err
+		}
+		state.RequestCancelInfos[k] = &persistenceblobs.RequestCancelInfo{
+			Version:               rowInfo.GetVersion(),
+			InitiatedID:           k,
+			InitiatedEventBatchID: rowInfo.GetInitiatedEventBatchID(),
+			CancelRequestID:       rowInfo.GetCancelRequestID(),
+		}
+	}
+
+	state.SignalInfos = make(map[int64]*persistenceblobs.SignalInfo, len(execution.signalInfos))
+	for k, v := range execution.signalInfos {
+		rowInfo, err := serialization.SignalInfoFromBlob(v.data, v.encoding)
+		if err != nil {
+			return nil, err
+		}
+		state.SignalInfos[k] = rowInfo
+	}
+
+	state.SignalRequestedIDs = make(map[string]struct{}, len(execution.signalsRequested))
+	for k := range execution.signalsRequested {
+		state.SignalRequestedIDs[k] = struct{}{}
+	}
+
+	for _, v := range execution.bufferedEvents {
+		state.BufferedEvents = append(state.BufferedEvents, p.NewDataBlob(copyBytes(v.data), common.EncodingType(v.encoding)))
+	}
+
+	return &p.InternalGetWorkflowExecutionResponse{State: state}, nil
+}
+
+func (m *executionStore) UpdateWorkflowExecution(
+	request *p.InternalUpdateWorkflowExecutionRequest,
+) error {
+
+	return m.txExecuteShardLocked("UpdateWorkflowExecution", request.RangeID, func(tx *executionTx) error {
+		return m.updateWorkflowExecutionTx(tx, request)
+	})
+}
+
+func (m *executionStore) updateWorkflowExecutionTx(
+	tx *executionTx,
+	request *p.InternalUpdateWorkflowExecutionRequest,
+) error {
+
+	updateWorkflow := request.UpdateWorkflowMutation
+	newWorkflow := request.NewWorkflowSnapshot
+
+	executionInfo := updateWorkflow.ExecutionInfo
+	domainID := executionInfo.DomainID
+	workflowID := executionInfo.WorkflowID
+	runID := executionInfo.RunID
+
+	if err := p.ValidateUpdateWorkflowModeState(
+		request.Mode,
+		updateWorkflow,
+		newWorkflow,
+	); err != nil {
+		return err
+	}
+
+	switch request.Mode {
+	case p.UpdateWorkflowModeBypassCurrent:
+		if err := tx.assertNotCurrentExecution(
+			domainID,
+			workflowID,
+			runID); err != nil {
+			return err
+		}
+
+	case p.UpdateWorkflowModeUpdateCurrent:
+		if newWorkflow != nil {
+			newExecutionInfo := newWorkflow.ExecutionInfo
+
+			if domainID != newExecutionInfo.DomainID {
+				return serviceerror.NewInternal(fmt.Sprintf("UpdateWorkflowExecution: cannot continue as new to another domain"))
+			}
+
+			if err := tx.assertRunIDAndUpdateCurrentExecution(
+				domainID,
+				workflowID,
+				runID,
+				newCurrentExecutionRow(
+					newExecutionInfo.RunID,
+					newExecutionInfo.CreateRequestID,
+					newExecutionInfo.State,
+					newExecutionInfo.CloseStatus,
+					newWorkflow.StartVersion,
+					newWorkflow.LastWriteVersion,
+				)); err != nil {
+				return serviceerror.NewInternal(fmt.Sprintf("UpdateWorkflowExecution: failed to continue as new current execution. Error: %v", err))
+			}
+		} else {
+			// this is only to update the current record
+			if err := tx.assertRunIDAndUpdateCurrentExecution(
+				domainID,
+				workflowID,
+				runID,
+				newCurrentExecutionRow(
+					runID,
+					executionInfo.CreateRequestID,
+					executionInfo.State,
+					executionInfo.CloseStatus,
+					updateWorkflow.StartVersion,
+					updateWorkflow.LastWriteVersion,
+				)); err != nil {
+				return serviceerror.NewInternal(fmt.Sprintf("UpdateWorkflowExecution: failed to update current execution. Error: %v", err))
+			}
+		}
+
+	default:
+		return serviceerror.NewInternal(fmt.Sprintf("UpdateWorkflowExecution: unknown mode: %v", request.Mode))
+	}
+
+	if err := tx.applyWorkflowMutation(&updateWorkflow); err != nil {
+		return err
+	}
+	if newWorkflow != nil {
+		if err := tx.applyWorkflowSnapshotAsNew(newWorkflow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *executionStore) ResetWorkflowExecution(
+	request *p.InternalResetWorkflowExecutionRequest,
+) error {
+
+	return m.txExecuteShardLocked("ResetWorkflowExecution", request.RangeID, func(tx *executionTx) error {
+		return m.resetWorkflowExecutionTx(tx, request)
+	})
+}
+
+func (m *executionStore) resetWorkflowExecutionTx(
+	tx *executionTx,
+	request *p.InternalResetWorkflowExecutionRequest,
+) error {
+
+	newExecutionInfo := request.NewWorkflowSnapshot.ExecutionInfo
+	domainID := newExecutionInfo.DomainID
+	workflowID := newExecutionInfo.WorkflowID
+
+	// 1. update current execution
+	if err := tx.updateCurrentExecution(
+		domainID,
+		workflowID,
+		newCurrentExecutionRow(
+			newExecutionInfo.RunID,
+			newExecutionInfo.CreateRequestID,
+			newExecutionInfo.State,
+			newExecutionInfo.CloseStatus,
+			request.NewWorkflowSnapshot.StartVersion,
+			request.NewWorkflowSnapshot.LastWriteVersion,
+		)); err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("ResetWorkflowExecution operation failed. Failed at updateCurrentExecution. Error: %v", err))
+	}
+
+	// 2. check base run: it is only needed when base run is not current run,
+	// because the current run is checked anyway
+	if request.BaseRunID != request.CurrentRunID {
+		key := tx.executionKey(domainID, workflowID, request.BaseRunID)
+		if _, err := tx.lockAndCheckNextEventID(key, request.BaseRunNextEventID); err != nil {
+			switch err.(type) {
+			case *p.ConditionFailedError:
+				return err
+			default:
+				return serviceerror.NewInternal(fmt.Sprintf("ResetWorkflowExecution operation failed. Failed to lock executions row. Error: %v", err))
+			}
+		}
+	}
+
+	// 3. update or check current run
+	if request.CurrentWorkflowMutation != nil {
+		if err := tx.applyWorkflowMutation(request.CurrentWorkflowMutation); err != nil {
+			return err
+		}
+	} else {
+		// even the current run is not running, we need to check the current run:
+		// 1). in case it is changed by conflict resolution
+		// 2). in case delete history timer kicks in if the base is current
+		key := tx.executionKey(domainID, workflowID, request.CurrentRunID)
+		if _, err := tx.lockAndCheckNextEventID(key, request.CurrentRunNextEventID); err != nil {
+			switch err.(type) {
+			case *p.ConditionFailedError:
+				return err
+			default:
+				return serviceerror.NewInternal(fmt.Sprintf("ResetWorkflowExecution operation failed. Failed to lock executions row. Error: %v", err))
+			}
+		}
+	}
+
+	// 4. create the new reset workflow
+	return tx.applyWorkflowSnapshotAsNew(&request.NewWorkflowSnapshot)
+}
+
+func (m *executionStore) ConflictResolveWorkflowExecution(
+	request *p.InternalConflictResolveWorkflowExecutionRequest,
+) error {
+
+	return m.txExecuteShardLocked("ConflictResolveWorkflowExecution", request.RangeID, func(tx *executionTx) error {
+		return m.conflictResolveWorkflowExecutionTx(tx, request)
+	})
+}
+
+func (m *executionStore) conflictResolveWorkflowExecutionTx(
+	tx *executionTx,
+	request *p.InternalConflictResolveWorkflowExecutionRequest,
+) error {
+
+	currentWorkflow := request.CurrentWorkflowMutation
+	resetWorkflow := request.ResetWorkflowSnapshot
+	newWorkflow := request.NewWorkflowSnapshot
+
+	domainID := resetWorkflow.ExecutionInfo.DomainID
+	workflowID := resetWorkflow.ExecutionInfo.WorkflowID
+
+	if err := p.ValidateConflictResolveWorkflowModeState(
+		request.Mode,
+		resetWorkflow,
+		newWorkflow,
+		currentWorkflow,
+	); err != nil {
+		return err
+	}
+
+	switch request.Mode {
+	case p.ConflictResolveWorkflowModeBypassCurrent:
+		if err := tx.assertNotCurrentExecution(
+			domainID,
+			workflowID,
+			resetWorkflow.ExecutionInfo.RunID); err != nil {
+			return err
+		}
+
+	case p.ConflictResolveWorkflowModeUpdateCurrent:
+		executionInfo := resetWorkflow.ExecutionInfo
+		startVersion := resetWorkflow.StartVersion
+		lastWriteVersion := resetWorkflow.LastWriteVersion
+		if newWorkflow != nil {
+			executionInfo = newWorkflow.ExecutionInfo
+			startVersion = newWorkflow.StartVersion
+			lastWriteVersion = newWorkflow.LastWriteVersion
+		}
+		row := newCurrentExecutionRow(
+			executionInfo.RunID,
+			executionInfo.CreateRequestID,
+			executionInfo.State,
+			executionInfo.CloseStatus,
+			startVersion,
+			lastWriteVersion,
+		)
+
+		if request.CurrentWorkflowCAS != nil {
+			if err := tx.assertAndUpdateCurrentExecution(
+				domainID,
+				workflowID,
+				request.CurrentWorkflowCAS.PrevRunID,
+				request.CurrentWorkflowCAS.PrevLastWriteVersion,
+				request.CurrentWorkflowCAS.PrevState,
+				row); err != nil {
+				return serviceerror.NewInternal(fmt.Sprintf("ConflictResolveWorkflowExecution. Failed to comare and swap the current record. Error: %v", err))
+			}
+		} else if currentWorkflow != nil {
+			if err := tx.assertRunIDAndUpdateCurrentExecution(
+				domainID,
+				workflowID,
+				currentWorkflow.ExecutionInfo.RunID,
+				row); err != nil {
+				return serviceerror.NewInternal(fmt.Sprintf("ConflictResolveWorkflowExecution. Failed to comare and swap the current record. Error: %v", err))
+			}
+		} else {
+			// reset workflow is current
+			if err := tx.assertRunIDAndUpdateCurrentExecution(
+				domainID,
+				workflowID,
+				resetWorkflow.ExecutionInfo.RunID,
+				row); err != nil {
+				return serviceerror.NewInternal(fmt.Sprintf("ConflictResolveWorkflowExecution. Failed to comare and swap the current record. Error: %v", err))
+			}
+		}
+
+	default:
+		return serviceerror.NewInternal(fmt.Sprintf("ConflictResolveWorkflowExecution: unknown mode: %v", request.Mode))
+	}
+
+	if err := tx.applyWorkflowSnapshotAsReset(&resetWorkflow); err != nil {
+		return err
+	}
+	if currentWorkflow != nil {
+		if err := tx.applyWorkflowMutation(currentWorkflow); err != nil {
+			return err
+		}
+	}
+	if newWorkflow != nil {
+		if err := tx.applyWorkflowSnapshotAsNew(newWorkflow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *executionStore) DeleteTask(request *p.DeleteTaskRequest) error {
+	// same as the SQL stores, corrupted tasks are not removed individually
+	return nil
+}
+
+func (m *executionStore) DeleteWorkflowExecution(
+	request *p.DeleteWorkflowExecutionRequest,
+) error {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	delete(m.db.executions, executionKey{
+		shardID:    m.shardID,
+		domainID:   request.DomainID,
+		workflowID: request.WorkflowID,
+		runID:      request.RunID,
+	})
+	return nil
+}
+
+// its possible for a new run of the same workflow to have started after the run we are deleting
+// here was finished. In that case, the current execution will have the same workflowID but different
+// runID. The following code will delete the current execution if and only if the runID is
+// same as the one we are trying to delete here
+func (m *executionStore) DeleteCurrentWorkflowExecution(
+	request *p.DeleteCurrentWorkflowExecutionRequest,
+) error {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	key := currentExecutionKey{
+		shardID:    m.shardID,
+		domainID:   request.DomainID,
+		workflowID: request.WorkflowID,
+	}
+	if row, ok := m.db.currentExecutions[key]; ok && row.runID == request.RunID {
+		delete(m.db.currentExecutions, key)
+	}
+	return nil
+}
+
+func (m *executionStore) GetCurrentExecution(
+	request *p.GetCurrentExecutionRequest,
+) (*p.GetCurrentExecutionResponse, error) {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	row, ok := m.db.currentExecutions[currentExecutionKey{
+		shardID:    m.shardID,
+		domainID:   request.DomainID,
+		workflowID: request.WorkflowID,
+	}]
+	if !ok {
+		return nil, serviceerror.NewNotFound(fmt.Sprintf("GetCurrentExecution operation failed. Current execution of workflow %v not found.", request.WorkflowID))
+	}
+	return &p.GetCurrentExecutionResponse{
+		StartRequestID:   row.createRequestID,
+		RunID:            row.runID,
+		State:            row.state,
+		CloseStatus:      int(row.closeStatus),
+		LastWriteVersion: row.lastWriteVersion,
+	}, nil
+}
+
+func (m *executionStore) GetTransferTasks(
+	request *p.GetTransferTasksRequest,
+) (*p.GetTransferTasksResponse, error) {
+
+	m.db.Lock()
+	rows := selectTasks(m.db.transferTasks[m.shardID], request.ReadLevel, request.MaxReadLevel, 0)
+	m.db.Unlock()
+
+	resp := &p.GetTransferTasksResponse{Tasks: make([]*persistenceblobs.TransferTaskInfo, len(rows))}
+	for i, row := range rows {
+		info, err := serialization.TransferTaskInfoFromBlob(row.data, row.encoding)
+		if err != nil {
+			return nil, err
+		}
+		resp.Tasks[i] = info
+	}
+
+	return resp, nil
+}
+
+func (m *executionStore) CompleteTransferTask(
+	request *p.CompleteTransferTaskRequest,
+) error {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	delete(m.db.transferTasks[m.shardID], request.TaskID)
+	return nil
+}
+
+func (m *executionStore) RangeCompleteTransferTask(
+	request *p.RangeCompleteTransferTaskRequest,
+) error {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	deleteTasks(m.db.transferTasks[m.shardID], request.ExclusiveBeginTaskID, request.InclusiveEndTaskID)
+	return nil
+}
+
+func (m *executionStore) GetReplicationTasks(
+	request *p.GetReplicationTasksRequest,
+) (*p.GetReplicationTasksResponse, error) {
+
+	readLevel, maxReadLevelInclusive, err := getReadLevels(request)
+	if err != nil {
+		return nil, err
+	}
+
+	m.db.Lock()
+	rows := selectTasks(m.db.replicationTasks[m.shardID], readLevel, maxReadLevelInclusive, request.BatchSize)
+	m.db.Unlock()
+
+	return populateGetReplicationTasksResponse(rows, request.MaxReadLevel)
+}
+
+func getReadLevels(request *p.GetReplicationTasksRequest) (readLevel int64, maxReadLevelInclusive int64, err error) {
+	readLevel = request.ReadLevel
+	if len(request.NextPageToken) > 0 {
+		readLevel, err = deserializePageToken(request.NextPageToken)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	maxReadLevelInclusive = collection.MaxInt64(readLevel+int64(request.BatchSize), request.MaxReadLevel)
+	return readLevel, maxReadLevelInclusive, nil
+}
+
+func populateGetReplicationTasksResponse(
+	rows []taskRow,
+	requestMaxReadLevel int64,
+) (*p.GetReplicationTasksResponse, error) {
+
+	if len(rows) == 0 {
+		return &p.GetReplicationTasksResponse{}, nil
+	}
+
+	var tasks = make([]*persistenceblobs.ReplicationTaskInfo, len(rows))
+	for i, row := range rows {
+		info, err := serialization.ReplicationTaskInfoFromBlob(row.data, row.encoding)
+		if err != nil {
+			return nil, err
+		}
+		tasks[i] = info
+	}
+	var nextPageToken []byte
+	lastTaskID := rows[len(rows)-1].taskID
+	if lastTaskID < requestMaxReadLevel {
+		nextPageToken = serializePageToken(lastTaskID)
+	}
+	return &p.GetReplicationTasksResponse{
+		Tasks:         tasks,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (m *executionStore) CompleteReplicationTask(
+	request *p.CompleteReplicationTaskRequest,
+) error {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	delete(m.db.replicationTasks[m.shardID], request.TaskID)
+	return nil
+}
+
+func (m *executionStore) RangeCompleteReplicationTask(
+	request *p.RangeCompleteReplicationTaskRequest,
+) error {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	deleteTasks(m.db.replicationTasks[m.shardID], math.MinInt64, request.InclusiveEndTaskID)
+	return nil
+}
+
+func (m *executionStore) PutReplicationTaskToDLQ(request *p.PutReplicationTaskToDLQRequest) error {
+	replicationTask := request.TaskInfo
+	blob, err := serialization.ReplicationTaskInfoToBlob(replicationTask)
+	if err != nil {
+		return err
+	}
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	key := replicationDLQKey{shardID: m.shardID, sourceClusterName: request.SourceClusterName}
+	tasks, ok := m.db.replicationDLQ[key]
+	if !ok {
+		tasks = make(map[int64]*blobRow)
+		m.db.replicationDLQ[key] = tasks
+	}
+	// Tasks are immutable. So it's fine if we already persisted it before.
+	// This can happen when tasks are retried (ack and cleanup can have lag on source side).
+	if _, ok := tasks[replicationTask.TaskID]; !ok {
+		tasks[replicationTask.TaskID] = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+	}
+	return nil
+}
+
+func (m *executionStore) GetReplicationTasksFromDLQ(
+	request *p.GetReplicationTasksFromDLQRequest,
+) (*p.GetReplicationTasksFromDLQResponse, error) {
+
+	readLevel, maxReadLevelInclusive, err := getReadLevels(&request.GetReplicationTasksRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	m.db.Lock()
+	key := replicationDLQKey{shardID: m.shardID, sourceClusterName: request.SourceClusterName}
+	rows := selectTasks(m.db.replicationDLQ[key], readLevel, maxReadLevelInclusive, request.BatchSize)
+	m.db.Unlock()
+
+	return populateGetReplicationTasksResponse(rows, request.MaxReadLevel)
+}
+
+func (m *executionStore) DeleteReplicationTaskFromDLQ(
+	request *p.DeleteReplicationTaskFromDLQRequest,
+) error {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	key := replicationDLQKey{shardID: m.shardID, sourceClusterName: request.SourceClusterName}
+	delete(m.db.replicationDLQ[key], request.TaskID)
+	return nil
+}
+
+func (m *executionStore) RangeDeleteReplicationTaskFromDLQ(
+	request *p.RangeDeleteReplicationTaskFromDLQRequest,
+) error {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	key := replicationDLQKey{shardID: m.shardID, sourceClusterName: request.SourceClusterName}
+	deleteTasks(m.db.replicationDLQ[key], request.ExclusiveBeginTaskID, request.InclusiveEndTaskID)
+	return nil
+}
+
+func (t *timerTaskPageToken) serialize() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+func (t *timerTaskPageToken) deserialize(payload []byte) error {
+	return json.Unmarshal(payload, t)
+}
+
+func (m *executionStore) GetTimerIndexTasks(
+	request *p.GetTimerIndexTasksRequest,
+) (*p.GetTimerIndexTasksResponse, error) {
+
+	pageToken := &timerTaskPageToken{TaskID: math.MinInt64, Timestamp: request.MinTimestamp}
+	if len(request.NextPageToken) > 0 {
+		if err := pageToken.deserialize(request.NextPageToken); err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("error deserializing timerTaskPageToken: %v", err))
+		}
+	}
+
+	minKey := timerTaskKey{visibilityTimestamp: pageToken.Timestamp.UnixNano(), taskID: pageToken.TaskID}
+	maxTimestamp := request.MaxTimestamp.UnixNano()
+
+	m.db.Lock()
+	var keys []timerTaskKey
+	for k := range m.db.timerTasks[m.shardID] {
+		if !k.less(minKey) && k.visibilityTimestamp < maxTimestamp {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+	if len(keys) > request.BatchSize+1 {
+		keys = keys[:request.BatchSize+1]
+	}
+	rows := make([]*blobRow, len(keys))
+	for i, k := range keys {
+		rows[i] = m.db.timerTasks[m.shardID][k]
+	}
+	m.db.Unlock()
+
+	resp := &p.GetTimerIndexTasksResponse{Timers: make([]*persistenceblobs.TimerTaskInfo, len(rows))}
+	for i, row := range rows {
+		info, err := serialization.TimerTaskInfoFromBlob(row.data, row.encoding)
+		if err != nil {
+			return nil, err
+		}
+		resp.Timers[i] = info
+	}
+
+	if len(resp.Timers) > request.BatchSize {
+		goVisibilityTimestamp, err := types.TimestampFromProto(resp.Timers[request.BatchSize].VisibilityTimestamp)
+		if err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("GetTimerTasks: error converting time for page token: %v", err))
+		}
+
+		pageToken = &timerTaskPageToken{
+			TaskID:    resp.Timers[request.BatchSize].TaskID,
+			Timestamp: goVisibilityTimestamp,
+		}
+		resp.Timers = resp.Timers[:request.BatchSize]
+		nextToken, err := pageToken.serialize()
+		if err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("GetTimerTasks: error serializing page token: %v", err))
+		}
+		resp.NextPageToken = nextToken
+	}
+
+	return resp, nil
+}
+
+func (m *executionStore) CompleteTimerTask(
+	request *p.CompleteTimerTaskRequest,
+) error {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	delete(m.db.timerTasks[m.shardID], timerTaskKey{
+		visibilityTimestamp: request.VisibilityTimestamp.UnixNano(),
+		taskID:              request.TaskID,
+	})
+	return nil
+}
+
+func (m *executionStore) RangeCompleteTimerTask(
+	request *p.RangeCompleteTimerTaskRequest,
+) error {
+
+	start := request.InclusiveBeginTimestamp.UnixNano()
+	end := request.ExclusiveEndTimestamp.UnixNano()
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	tasks := m.db.timerTasks[m.shardID]
+	for k := range tasks {
+		if k.visibilityTimestamp >= start && k.visibilityTimestamp < end {
+			delete(tasks, k)
+		}
+	}
+	return nil
+}
+
+func (k timerTaskKey) less(other timerTaskKey) bool {
+	if k.visibilityTimestamp != other.visibilityTimestamp {
+		return k.visibilityTimestamp < other.visibilityTimestamp
+	}
+	return k.taskID < other.taskID
+}
@@ -0,0 +1,1093 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/.gen/proto/replication"
+	"github.com/temporalio/temporal/common"
+	p "github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/persistence/serialization"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+type (
+	executionKey struct {
+		shardID    int
+		domainID   string
+		workflowID string
+		runID      string
+	}
+
+	currentExecutionKey struct {
+		shardID    int
+		domainID   string
+		workflowID string
+	}
+
+	timerTaskKey struct {
+		visibilityTimestamp int64
+		taskID              int64
+	}
+
+	replicationDLQKey struct {
+		shardID           int
+		sourceClusterName string
+	}
+
+	// executionRow holds a workflow execution together with all of its
+	// mutable state maps, stored the same way the SQL tables store them
+	executionRow struct {
+		nextEventID      int64
+		lastWriteVersion int64
+		info             *blobRow
+		state            *blobRow
+
+		activityInfos       map[int64]*blobRow
+		timerInfos          map[string]*blobRow
+		childExecutionInfos map[int64]*blobRow
+		requestCancelInfos  map[int64]*blobRow
+		signalInfos         map[int64]*blobRow
+		signalsRequested    map[string]struct{}
+		bufferedEvents      []*blobRow
+	}
+
+	currentExecutionRow struct {
+		runID            string
+		createRequestID  string
+		state            int
+		closeStatus      enums.WorkflowExecutionCloseStatus
+		startVersion     int64
+		lastWriteVersion int64
+	}
+
+	// executionTx stages the writes of a single execution store operation.
+	// Reads go through the staged writes first, and nothing reaches the
+	// database until commit is called, so a failed condition anywhere in the
+	// operation leaves the database untouched.
+	executionTx struct {
+		db      *database
+		shardID int
+
+		executions        map[executionKey]*executionRow
+		currentExecutions map[currentExecutionKey]*currentExecutionRow
+		transferTasks     map[int64]*blobRow
+		timerTasks        map[timerTaskKey]*blobRow
+		replicationTasks  map[int64]*blobRow
+	}
+)
+
+func newExecutionRow() *executionRow {
+	return &executionRow{
+		activityInfos:       make(map[int64]*blobRow),
+		timerInfos:          make(map[string]*blobRow),
+		childExecutionInfos: make(map[int64]*blobRow),
+		requestCancelInfos:  make(map[int64]*blobRow),
+		signalInfos:         make(map[int64]*blobRow),
+		signalsRequested:    make(map[string]struct{}),
+	}
+}
+
+// clone returns a copy of the row which can be modified independently;
+// stored blob rows are never modified in place so they are shared
+func (r *executionRow) clone() *executionRow {
+	c := newExecutionRow()
+	c.nextEventID = r.nextEventID
+	c.lastWriteVersion = r.lastWriteVersion
+	c.info = r.info
+	c.state = r.state
+	for k, v := range r.activityInfos {
+		c.activityInfos[k] = v
+	}
+	for k, v := range r.timerInfos {
+		c.timerInfos[k] = v
+	}
+	for k, v := range r.childExecutionInfos {
+		c.childExecutionInfos[k] = v
+	}
+	for k, v := range r.requestCancelInfos {
+		c.requestCancelInfos[k] = v
+	}
+	for k, v := range r.signalInfos {
+		c.signalInfos[k] = v
+	}
+	for k := range r.signalsRequested {
+		c.signalsRequested[k] = struct{}{}
+	}
+	c.bufferedEvents = append(c.bufferedEvents, r.bufferedEvents...)
+	return c
+}
+
+// newExecutionTx starts a transaction on the given shard. Callers must hold the database lock.
+func newExecutionTx(db *database, shardID int) *executionTx {
+	return &executionTx{
+		db:                db,
+		shardID:           shardID,
+		executions:        make(map[executionKey]*executionRow),
+		currentExecutions: make(map[currentExecutionKey]*currentExecutionRow),
+		transferTasks:     make(map[int64]*blobRow),
+		timerTasks:        make(map[timerTaskKey]*blobRow),
+		replicationTasks:  make(map[int64]*blobRow),
+	}
+}
+
+func (tx *executionTx) executionKey(domainID string, workflowID string, runID string) executionKey {
+	return executionKey{shardID: tx.shardID, domainID: domainID, workflowID: workflowID, runID: runID}
+}
+
+func (tx *executionTx) currentExecutionKey(domainID string, workflowID string) currentExecutionKey {
+	return currentExecutionKey{shardID: tx.shardID, domainID: domainID, workflowID: workflowID}
+}
+
+// execution returns the row of an execution for reading, or nil if it does not exist
+func (tx *executionTx) execution(key executionKey) *executionRow {
+	if row, ok := tx.executions[key]; ok {
+		return row
+	}
+	return tx.db.executions[key]
+}
+
+// mutableExecution returns the staged copy of an execution, or nil if it does not exist
+func (tx *executionTx) mutableExecution(key executionKey) *executionRow {
+	if row, ok := tx.executions[key]; ok {
+		return row
+	}
+	row, ok := tx.db.executions[key]
+	if !ok {
+		return nil
+	}
+	row = row.clone()
+	tx.executions[key] = row
+	return row
+}
+
+func (tx *executionTx) currentExecution(key currentExecutionKey) *currentExecutionRow {
+	if row, ok := tx.currentExecutions[key]; ok {
+		return row
+	}
+	return tx.db.currentExecutions[key]
+}
+
+func (tx *executionTx) commit() {
+	for k, v := range tx.executions {
+		tx.db.executions[k] = v
+	}
+	for k, v := range tx.currentExecutions {
+		tx.db.currentExecutions[k] = v
+	}
+	if len(tx.transferTasks) > 0 {
+		tasks := tx.db.shardTransferTasks(tx.shardID)
+		for k, v := range tx.transferTasks {
+			tasks[k] = v
+		}
+	}
+	if len(tx.timerTasks) > 0 {
+		tasks := tx.db.shardTimerTasks(tx.shardID)
+		for k, v := range tx.timerTasks {
+			tasks[k] = v
+		}
+	}
+	if len(tx.replicationTasks) > 0 {
+		tasks := tx.db.shardReplicationTasks(tx.shardID)
+		for k, v := range tx.replicationTasks {
+			tasks[k] = v
+		}
+	}
+}
+
+func (db *database) shardTransferTasks(shardID int) map[int64]*blobRow {
+	tasks, ok := db.transferTasks[shardID]
+	if !ok {
+		tasks = make(map[int64]*blobRow)
+		db.transferTasks[shardID] = tasks
+	}
+	return tasks
+}
+
+func (db *database) shardTimerTasks(shardID int) map[timerTaskKey]*blobRow {
+	tasks, ok := db.timerTasks[shardID]
+	if !ok {
+		tasks = make(map[timerTaskKey]*blobRow)
+		db.timerTasks[shardID] = tasks
+	}
+	return tasks
+}
+
+func (db *database) shardReplicationTasks(shardID int) map[int64]*blobRow {
+	tasks, ok := db.replicationTasks[shardID]
+	if !ok {
+		tasks = make(map[int64]*blobRow)
+		db.replicationTasks[shardID] = tasks
+	}
+	return tasks
+}
+
+func (tx *executionTx) applyWorkflowMutation(
+	workflowMutation *p.InternalWorkflowMutation,
+) error {
+
+	executionInfo := workflowMutation.ExecutionInfo
+	replicationState := workflowMutation.ReplicationState
+	lastWriteVersion := workflowMutation.LastWriteVersion
+	key := tx.executionKey(executionInfo.DomainID, executionInfo.WorkflowID, executionInfo.RunID)
+
+	// TODO remove once 2DC is deprecated
+	//  since current version is only used by 2DC
+	currentVersion := lastWriteVersion
+	if replicationState != nil {
+		currentVersion = replicationState.CurrentVersion
+	}
+
+	row, err := tx.lockAndCheckNextEventID(key, workflowMutation.Condition)
+	if err != nil {
+		switch err.(type) {
+		case *p.ConditionFailedError:
+			return err
+		default:
+			return serviceerror.NewInternal(fmt.Sprintf("applyWorkflowMutation failed. Failed to lock executions row. Error: %v", err))
+		}
+	}
+
+	if err := updateExecution(row,
+		executionInfo,
+		replicationState,
+		workflowMutation.VersionHistories,
+		workflowMutation.StartVersion,
+		lastWriteVersion,
+		currentVersion); err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("applyWorkflowMutation failed. Failed to update executions row. Error: %v", err))
+	}
+
+	if err := tx.applyTasks(key,
+		workflowMutation.TransferTasks,
+		workflowMutation.ReplicationTasks,
+		workflowMutation.TimerTasks); err != nil {
+		return err
+	}
+
+	if err := updateMutableStateMaps(row,
+		workflowMutation.UpsertActivityInfos,
+		workflowMutation.DeleteActivityInfos,
+		workflowMutation.UpsertTimerInfos,
+		workflowMutation.DeleteTimerInfos,
+		workflowMutation.UpsertChildExecutionInfos,
+		workflowMutation.DeleteChildExecutionInfo,
+		workflowMutation.UpsertRequestCancelInfos,
+		workflowMutation.DeleteRequestCancelInfo,
+		workflowMutation.UpsertSignalInfos,
+		workflowMutation.DeleteSignalInfo,
+		workflowMutation.UpsertSignalRequestedIDs,
+		workflowMutation.DeleteSignalRequestedID); err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("applyWorkflowMutation failed. Error: %v", err))
+	}
+
+	if workflowMutation.ClearBufferedEvents {
+		row.bufferedEvents = nil
+	}
+	if batch := workflowMutation.NewBufferedEvents; batch != nil {
+		row.bufferedEvents = append(row.bufferedEvents, newBlobRow(batch.Data, string(batch.Encoding)))
+	}
+	return nil
+}
+
+func (tx *executionTx) applyWorkflowSnapshotAsReset(
+	workflowSnapshot *p.InternalWorkflowSnapshot,
+) error {
+
+	executionInfo := workflowSnapshot.ExecutionInfo
+	replicationState := workflowSnapshot.ReplicationState
+	lastWriteVersion := workflowSnapshot.LastWriteVersion
+	key := tx.executionKey(executionInfo.DomainID, executionInfo.WorkflowID, executionInfo.RunID)
+
+	// TODO remove once 2DC is deprecated
+	//  since current version is only used by 2DC
+	currentVersion := lastWriteVersion
+	if replicationState != nil {
+		currentVersion = replicationState.CurrentVersion
+	}
+
+	row, err := tx.lockAndCheckNextEventID(key, workflowSnapshot.Condition)
+	if err != nil {
+		switch err.(type) {
+		case *p.ConditionFailedError:
+			return err
+		default:
+			return serviceerror.NewInternal(fmt.Sprintf("applyWorkflowSnapshotAsReset failed. Failed to lock executions row. Error: %v", err))
+		}
+	}
+
+	if err := updateExecution(row,
+		executionInfo,
+		replicationState,
+		workflowSnapshot.VersionHistories,
+		workflowSnapshot.StartVersion,
+		lastWriteVersion,
+		currentVersion); err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("applyWorkflowSnapshotAsReset failed. Failed to update executions row. Error: %v", err))
+	}
+
+	if err := tx.applyTasks(key,
+		workflowSnapshot.TransferTasks,
+		workflowSnapshot.ReplicationTasks,
+		workflowSnapshot.TimerTasks); err != nil {
+		return err
+	}
+
+	reset := newExecutionRow()
+	if err := updateMutableStateMaps(reset,
+		workflowSnapshot.ActivityInfos,
+		nil,
+		workflowSnapshot.TimerInfos,
+		nil,
+		workflowSnapshot.ChildExecutionInfos,
+		nil,
+		workflowSnapshot.RequestCancelInfos,
+		nil,
+		workflowSnapshot.SignalInfos,
+		nil,
+		workflowSnapshot.SignalRequestedIDs,
+		""); err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("applyWorkflowSnapshotAsReset failed. Failed to reset mutable state maps. Error: %v", err))
+	}
+	row.activityInfos = reset.activityInfos
+	row.timerInfos = reset.timerInfos
+	row.childExecutionInfos = reset.childExecutionInfos
+	row.requestCancelInfos = reset.requestCancelInfos
+	row.signalInfos = reset.signalInfos
+	row.signalsRequested = reset.signalsRequested
+	row.bufferedEvents = nil
+	return nil
+}
+
+func (tx *executionTx) applyWorkflowSnapshotAsNew(
+	workflowSnapshot *p.InternalWorkflowSnapshot,
+) error {
+
+	executionInfo := workflowSnapshot.ExecutionInfo
+	replicationState := workflowSnapshot.ReplicationState
+	lastWriteVersion := workflowSnapshot.LastWriteVersion
+	key := tx.executionKey(executionInfo.DomainID, executionInfo.WorkflowID, executionInfo.RunID)
+
+	// TODO remove once 2DC is deprecated
+	//  since current version is only used by 2DC
+	currentVersion := lastWriteVersion
+	if replicationState != nil {
+		currentVersion = replicationState.CurrentVersion
+	}
+
+	row, err := tx.createExecution(key,
+		executionInfo,
+		replicationState,
+		workflowSnapshot.VersionHistories,
+		workflowSnapshot.StartVersion,
+		lastWriteVersion,
+		currentVersion)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.applyTasks(key,
+		workflowSnapshot.TransferTasks,
+		workflowSnapshot.ReplicationTasks,
+		workflowSnapshot.TimerTasks); err != nil {
+		return err
+	}
+
+	if err := updateMutableStateMaps(row,
+		workflowSnapshot.ActivityInfos,
+		nil,
+		workflowSnapshot.TimerInfos,
+		nil,
+		workflowSnapshot.ChildExecutionInfos,
+		nil,
+		workflowSnapshot.RequestCancelInfos,
+		nil,
+		workflowSnapshot.SignalInfos,
+		nil,
+		workflowSnapshot.SignalRequestedIDs,
+		""); err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("applyWorkflowSnapshotAsNew failed. Failed to insert mutable state maps. Error: %v", err))
+	}
+	return nil
+}
+
+func (tx *executionTx) applyTasks(
+	key executionKey,
+	transferTasks []p.Task,
+	replicationTasks []p.Task,
+	timerTasks []p.Task,
+) error {
+
+	if err := tx.createTransferTasks(transferTasks, key); err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("applyTasks failed. Failed to create transfer tasks. Error: %v", err))
+	}
+	if err := tx.createReplicationTasks(replicationTasks, key); err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("applyTasks failed. Failed to create replication tasks. Error: %v", err))
+	}
+	if err := tx.createTimerTasks(timerTasks, key); err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("applyTasks failed. Failed to create timer tasks. Error: %v", err))
+	}
+	return nil
+}
+
+// lockCurrentExecutionIfExists returns current execution or nil if none is found for the workflowID.
+// Like its SQL counterpart, the current record is only visible while the run it points to exists,
+// and the last write version is taken from that run.
+func (tx *executionTx) lockCurrentExecutionIfExists(
+	domainID string,
+	workflowID string,
+) *currentExecutionRow {
+
+	row := tx.currentExecution(tx.currentExecutionKey(domainID, workflowID))
+	if row == nil {
+		return nil
+	}
+	execution := tx.execution(tx.executionKey(domainID, workflowID, row.runID))
+	if execution == nil {
+		return nil
+	}
+	joined := *row
+	joined.lastWriteVersion = execution.lastWriteVersion
+	return &joined
+}
+
+func (tx *executionTx) createOrUpdateCurrentExecution(
+	createMode p.CreateWorkflowMode,
+	domainID string,
+	workflowID string,
+	row *currentExecutionRow,
+) error {
+
+	switch createMode {
+	case p.CreateWorkflowModeContinueAsNew:
+		if err := tx.updateCurrentExecution(domainID, workflowID, row); err != nil {
+			return serviceerror.NewInternal(fmt.Sprintf("createOrUpdateCurrentExecution failed. Failed to continue as new. Error: %v", err))
+		}
+	case p.CreateWorkflowModeWorkflowIDReuse:
+		if err := tx.updateCurrentExecution(domainID, workflowID, row); err != nil {
+			return serviceerror.NewInternal(fmt.Sprintf("createOrUpdateCurrentExecution failed. Failed to reuse workflow ID. Error: %v", err))
+		}
+	case p.CreateWorkflowModeBrandNew:
+		key := tx.currentExecutionKey(domainID, workflowID)
+		if tx.currentExecution(key) != nil {
+			return serviceerror.NewInternal(fmt.Sprintf("createOrUpdateCurrentExecution failed. Failed to insert into current_executions table. Error: duplicate entry for workflow %v", workflowID))
+		}
+		tx.currentExecutions[key] = row
+	case p.CreateWorkflowModeZombie:
+		// noop
+	default:
+		return fmt.Errorf("createOrUpdateCurrentExecution failed. Unknown workflow creation mode: %v", createMode)
+	}
+
+	return nil
+}
+
+// lockAndCheckNextEventID returns the staged copy of the execution after verifying its next event ID
+func (tx *executionTx) lockAndCheckNextEventID(
+	key executionKey,
+	condition int64,
+) (*executionRow, error) {
+
+	row := tx.mutableExecution(key)
+	if row == nil {
+		return nil, serviceerror.NewNotFound(fmt.Sprintf("lockNextEventID failed. Unable to lock executions row with (shard, domain, workflow, run) = (%v,%v,%v,%v) which does not exist.",
+			key.shardID,
+			key.domainID,
+			key.workflowID,
+			key.runID))
+	}
+	if row.nextEventID != condition {
+		return nil, &p.ConditionFailedError{
+			Msg: fmt.Sprintf("lockAndCheckNextEventID failed. Next_event_id was %v when it should have been %v.", row.nextEventID, condition),
+		}
+	}
+	return row, nil
+}
+
+func (tx *executionTx) createTransferTasks(
+	transferTasks []p.Task,
+	key executionKey,
+) error {
+
+	domainID := primitives.MustParseUUID(key.domainID)
+	runID := primitives.MustParseUUID(key.runID)
+	for _, task := range transferTasks {
+		info := &persistenceblobs.TransferTaskInfo{
+			DomainID:         domainID,
+			WorkflowID:       key.workflowID,
+			RunID:            runID,
+			TargetDomainID:   domainID,
+			TargetWorkflowID: p.TransferTaskTransferTargetWorkflowID,
+			ScheduleID:       0,
+			TaskID:           task.GetTaskID(),
+		}
+
+		switch task.GetType() {
+		case p.TransferTaskTypeActivityTask:
+			info.TargetDomainID = primitives.MustParseUUID(task.(*p.ActivityTask).DomainID)
+			info.TaskList = task.(*p.ActivityTask).TaskList
+			info.ScheduleID = task.(*p.ActivityTask).ScheduleID
+
+		case p.TransferTaskTypeDecisionTask:
+			info.TargetDomainID = primitives.MustParseUUID(task.(*p.DecisionTask).DomainID)
+			info.TaskList = task.(*p.DecisionTask).TaskList
+			info.ScheduleID = task.(*p.DecisionTask).ScheduleID
+
+		case p.TransferTaskTypeCancelExecution:
+			info.TargetDomainID = primitives.MustParseUUID(task.(*p.CancelExecutionTask).TargetDomainID)
+			info.TargetWorkflowID = task.(*p.CancelExecutionTask).TargetWorkflowID
+			if task.(*p.CancelExecutionTask).TargetRunID != "" {
+				info.TargetRunID = primitives.MustParseUUID(task.(*p.CancelExecutionTask).TargetRunID)
+			}
+			info.TargetChildWorkflowOnly = task.(*p.CancelExecutionTask).TargetChildWorkflowOnly
+			info.ScheduleID = task.(*p.CancelExecutionTask).InitiatedID
+
+		case p.TransferTaskTypeSignalExecution:
+			info.TargetDomainID = primitives.MustParseUUID(task.(*p.SignalExecutionTask).TargetDomainID)
+			info.TargetWorkflowID = task.(*p.SignalExecutionTask).TargetWorkflowID
+			if task.(*p.SignalExecutionTask).TargetRunID != "" {
+				info.TargetRunID = primitives.MustParseUUID(task.(*p.SignalExecutionTask).TargetRunID)
+			}
+			info.TargetChildWorkflowOnly = task.(*p.SignalExecutionTask).TargetChildWorkflowOnly
+			info.ScheduleID = task.(*p.SignalExecutionTask).InitiatedID
+
+		case p.TransferTaskTypeStartChildExecution:
+			info.TargetDomainID = primitives.MustParseUUID(task.(*p.StartChildExecutionTask).TargetDomainID)
+			info.TargetWorkflowID = task.(*p.StartChildExecutionTask).TargetWorkflowID
+			info.ScheduleID = task.(*p.StartChildExecutionTask).InitiatedID
+
+		case p.TransferTaskTypeCloseExecution,
+			p.TransferTaskTypeRecordWorkflowStarted,
+			p.TransferTaskTypeResetWorkflow,
+			p.TransferTaskTypeUpsertWorkflowSearchAttributes:
+			// No explicit property needs to be set
+
+		default:
+			return serviceerror.NewInternal(fmt.Sprintf("createTransferTasks failed. Unknow transfer type: %v", task.GetType()))
+		}
+
+		info.TaskType = int32(task.GetType())
+		info.Version = task.GetVersion()
+
+		t, err := types.TimestampProto(task.GetVisibilityTimestamp().UTC())
+		if err != nil {
+			return err
+		}
+		info.VisibilityTimestamp = t
+
+		blob, err := serialization.TransferTaskInfoToBlob(info)
+		if err != nil {
+			return err
+		}
+
+		taskID := task.GetTaskID()
+		_, staged := tx.transferTasks[taskID]
+		_, stored := tx.db.transferTasks[tx.shardID][taskID]
+		if staged || stored {
+			return serviceerror.NewInternal(fmt.Sprintf("createTransferTasks failed. Duplicate task ID %v", taskID))
+		}
+		tx.transferTasks[taskID] = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+	}
+
+	return nil
+}
+
+func (tx *executionTx) createReplicationTasks(
+	replicationTasks []p.Task,
+	key executionKey,
+) error {
+
+	domainID := primitives.MustParseUUID(key.domainID)
+	runID := primitives.MustParseUUID(key.runID)
+	for _, task := range replicationTasks {
+
+		firstEventID := common.EmptyEventID
+		nextEventID := common.EmptyEventID
+		version := common.EmptyVersion
+		activityScheduleID := common.EmptyEventID
+		var lastReplicationInfo map[string]*replication.ReplicationInfo
+
+		var branchToken, newRunBranchToken []byte
+		var resetWorkflow bool
+
+		switch task.GetType() {
+		case p.ReplicationTaskTypeHistory:
+			historyReplicationTask, ok := task.(*p.HistoryReplicationTask)
+			if !ok {
+				return serviceerror.NewInternal(fmt.Sprintf("createReplicationTasks failed. Failed to cast %v to HistoryReplicationTask", task))
+			}
+			firstEventID = historyReplicationTask.FirstEventID
+			nextEventID = historyReplicationTask.NextEventID
+			version = task.GetVersion()
+			branchToken = historyReplicationTask.BranchToken
+			newRunBranchToken = historyReplicationTask.NewRunBranchToken
+			resetWorkflow = historyReplicationTask.ResetWorkflow
+			lastReplicationInfo = make(map[string]*replication.ReplicationInfo, len(historyReplicationTask.LastReplicationInfo))
+			for k, v := range historyReplicationTask.LastReplicationInfo {
+				lastReplicationInfo[k] = &replication.ReplicationInfo{Version: v.Version, LastEventId: v.LastEventId}
+			}
+
+		case p.ReplicationTaskTypeSyncActivity:
+			version = task.GetVersion()
+			activityScheduleID = task.(*p.SyncActivityTask).ScheduledID
+			lastReplicationInfo = map[string]*replication.ReplicationInfo{}
+
+		default:
+			return serviceerror.NewInternal(fmt.Sprintf("Unknown replication task: %v", task.GetType()))
+		}
+
+		blob, err := serialization.ReplicationTaskInfoToBlob(&persistenceblobs.ReplicationTaskInfo{
+			TaskID:                  task.GetTaskID(),
+			DomainID:                domainID,
+			WorkflowID:              key.workflowID,
+			RunID:                   runID,
+			TaskType:                int32(task.GetType()),
+			FirstEventID:            firstEventID,
+			NextEventID:             nextEventID,
+			Version:                 version,
+			LastReplicationInfo:     lastReplicationInfo,
+			ScheduledID:             activityScheduleID,
+			EventStoreVersion:       p.EventStoreVersion,
+			NewRunEventStoreVersion: p.EventStoreVersion,
+			BranchToken:             branchToken,
+			NewRunBranchToken:       newRunBranchToken,
+			ResetWorkflow:           resetWorkflow,
+		})
+		if err != nil {
+			return err
+		}
+
+		taskID := task.GetTaskID()
+		_, staged := tx.replicationTasks[taskID]
+		_, stored := tx.db.replicationTasks[tx.shardID][taskID]
+		if staged || stored {
+			return serviceerror.NewInternal(fmt.Sprintf("createReplicationTasks failed. Duplicate task ID %v", taskID))
+		}
+		tx.replicationTasks[taskID] = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+	}
+
+	return nil
+}
+
+func (tx *executionTx) createTimerTasks(
+	timerTasks []p.Task,
+	key executionKey,
+) error {
+
+	domainID := primitives.MustParseUUID(key.domainID)
+	runID := primitives.MustParseUUID(key.runID)
+	for _, task := range timerTasks {
+		info := &persistenceblobs.TimerTaskInfo{}
+		switch t := task.(type) {
+		case *p.DecisionTimeoutTask:
+			info.EventID = t.EventID
+			info.TimeoutType = int32(t.TimeoutType)
+			info.ScheduleAttempt = t.ScheduleAttempt
+
+		case *p.ActivityTimeoutTask:
+			info.EventID = t.EventID
+			info.TimeoutType = int32(t.TimeoutType)
+			info.ScheduleAttempt = t.Attempt
+
+		case *p.UserTimerTask:
+			info.EventID = t.EventID
+
+		case *p.ActivityRetryTimerTask:
+			info.EventID = t.EventID
+			info.ScheduleAttempt = int64(t.Attempt)
+
+		case *p.WorkflowBackoffTimerTask:
+			info.EventID = t.EventID
+			info.TimeoutType = int32(t.TimeoutType)
+
+		case *p.WorkflowTimeoutTask:
+			// noop
+
+		case *p.DeleteHistoryEventTask:
+			// noop
+
+		default:
+			return serviceerror.NewInternal(fmt.Sprintf("createTimerTasks failed. Unknown timer task: %v", task.GetType()))
+		}
+
+		info.DomainID = domainID
+		info.WorkflowID = key.workflowID
+		info.RunID = runID
+		info.Version = task.GetVersion()
+		info.TaskType = int32(task.GetType())
+		info.TaskID = task.GetTaskID()
+
+		goVisTs := task.GetVisibilityTimestamp()
+		protoVisTs, err := types.TimestampProto(goVisTs)
+		if err != nil {
+			return err
+		}
+		info.VisibilityTimestamp = protoVisTs
+
+		blob, err := serialization.TimerTaskInfoToBlob(info)
+		if err != nil {
+			return err
+		}
+
+		taskKey := timerTaskKey{visibilityTimestamp: goVisTs.UnixNano(), taskID: task.GetTaskID()}
+		_, staged := tx.timerTasks[taskKey]
+		_, stored := tx.db.timerTasks[tx.shardID][taskKey]
+		if staged || stored {
+			return serviceerror.NewInternal(fmt.Sprintf("createTimerTasks failed. Duplicate task ID %v", taskKey.taskID))
+		}
+		tx.timerTasks[taskKey] = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+	}
+
+	return nil
+}
+
+func (tx *executionTx) assertNotCurrentExecution(
+	domainID string,
+	workflowID string,
+	runID string,
+) error {
+
+	currentRow := tx.currentExecution(tx.currentExecutionKey(domainID, workflowID))
+	if currentRow == nil {
+		// allow bypassing no current record
+		return nil
+	}
+	return assertRunIDMismatch(runID, currentRow.runID)
+}
+
+func (tx *executionTx) assertRunIDAndUpdateCurrentExecution(
+	domainID string,
+	workflowID string,
+	previousRunID string,
+	row *currentExecutionRow,
+) error {
+
+	assertFn := func(currentRow *currentExecutionRow) error {
+		if currentRow.runID != previousRunID {
+			return &p.ConditionFailedError{Msg: fmt.Sprintf(
+				"assertRunIDAndUpdateCurrentExecution failed. Current run ID was %v, expected %v",
+				currentRow.runID,
+				previousRunID,
+			)}
+		}
+		return nil
+	}
+	if err := tx.assertCurrentExecution(domainID, workflowID, assertFn); err != nil {
+		return err
+	}
+
+	return tx.updateCurrentExecution(domainID, workflowID, row)
+}
+
+func (tx *executionTx) assertAndUpdateCurrentExecution(
+	domainID string,
+	workflowID string,
+	previousRunID string,
+	previousLastWriteVersion int64,
+	previousState int,
+	row *currentExecutionRow,
+) error {
+
+	assertFn := func(currentRow *currentExecutionRow) error {
+		if currentRow.runID != previousRunID {
+			return &p.ConditionFailedError{Msg: fmt.Sprintf(
+				"assertAndUpdateCurrentExecution failed. Current run ID was %v, expected %v",
+				currentRow.runID,
+				previousRunID,
+			)}
+		}
+		if currentRow.lastWriteVersion != previousLastWriteVersion {
+			return &p.ConditionFailedError{Msg: fmt.Sprintf(
+				"assertAndUpdateCurrentExecution failed. Current last write version was %v, expected %v",
+				currentRow.lastWriteVersion,
+				previousLastWriteVersion,
+			)}
+		}
+		if currentRow.state != previousState {
+			return &p.ConditionFailedError{Msg: fmt.Sprintf(
+				"assertAndUpdateCurrentExecution failed. Current state %v, expected %v",
+				currentRow.state,
+				previousState,
+			)}
+		}
+		return nil
+	}
+	if err := tx.assertCurrentExecution(domainID, workflowID, assertFn); err != nil {
+		return err
+	}
+
+	return tx.updateCurrentExecution(domainID, workflowID, row)
+}
+
+func (tx *executionTx) assertCurrentExecution(
+	domainID string,
+	workflowID string,
+	assertFn func(currentRow *currentExecutionRow) error,
+) error {
+
+	currentRow := tx.currentExecution(tx.currentExecutionKey(domainID, workflowID))
+	if currentRow == nil {
+		return serviceerror.NewInternal(fmt.Sprintf("assertCurrentExecution failed. Unable to load current record for workflow %v.", workflowID))
+	}
+	return assertFn(currentRow)
+}
+
+func assertRunIDMismatch(runID string, currentRunID string) error {
+	// zombie workflow creation with existence of current record, this is a noop
+	if currentRunID == runID {
+		return &p.ConditionFailedError{Msg: fmt.Sprintf(
+			"assertRunIDMismatch failed. Current run ID was %v, input %v",
+			currentRunID,
+			runID,
+		)}
+	}
+	return nil
+}
+
+func (tx *executionTx) updateCurrentExecution(
+	domainID string,
+	workflowID string,
+	row *currentExecutionRow,
+) error {
+
+	key := tx.currentExecutionKey(domainID, workflowID)
+	if tx.currentExecution(key) == nil {
+		return serviceerror.NewInternal("updateCurrentExecution failed. 0 rows of current_executions updated instead of 1.")
+	}
+	tx.currentExecutions[key] = row
+	return nil
+}
+
+func newCurrentExecutionRow(
+	runID string,
+	createRequestID string,
+	state int,
+	closeStatus enums.WorkflowExecutionCloseStatus,
+	startVersion int64,
+	lastWriteVersion int64,
+) *currentExecutionRow {
+
+	return &currentExecutionRow{
+		runID:            runID,
+		createRequestID:  createRequestID,
+		state:            state,
+		closeStatus:      closeStatus,
+		startVersion:     startVersion,
+		lastWriteVersion: lastWriteVersion,
+	}
+}
+
+// setExecutionRow serializes the execution the same way the SQL stores do,
+// so reads go through the same partial proto conversion
+func setExecutionRow(
+	row *executionRow,
+	executionInfo *p.InternalWorkflowExecutionInfo,
+	replicationState *p.ReplicationState,
+	versionHistories *serialization.DataBlob,
+	startVersion int64,
+	lastWriteVersion int64,
+	currentVersion int64,
+) error {
+
+	info, state, err := p.InternalWorkflowExecutionInfoToProto(executionInfo, startVersion, currentVersion, replicationState, versionHistories)
+	if err != nil {
+		return err
+	}
+
+	infoBlob, err := serialization.WorkflowExecutionInfoToBlob(info)
+	if err != nil {
+		return err
+	}
+
+	stateBlob, err := serialization.WorkflowExecutionStateToBlob(state)
+	if err != nil {
+		return err
+	}
+
+	row.nextEventID = executionInfo.NextEventID
+	row.lastWriteVersion = lastWriteVersion
+	row.info = &blobRow{data: infoBlob.Data, encoding: infoBlob.Encoding.String()}
+	row.state = &blobRow{data: stateBlob.Data, encoding: stateBlob.Encoding.String()}
+	return nil
+}
+
+func (tx *executionTx) createExecution(
+	key executionKey,
+	executionInfo *p.InternalWorkflowExecutionInfo,
+	replicationState *p.ReplicationState,
+	versionHistories *serialization.DataBlob,
+	startVersion int64,
+	lastWriteVersion int64,
+	currentVersion int64,
+) (*executionRow, error) {
+
+	// validate workflow state & close status
+	if err := p.ValidateCreateWorkflowStateCloseStatus(
+		executionInfo.State,
+		executionInfo.CloseStatus); err != nil {
+		return nil, err
+	}
+
+	if tx.execution(key) != nil {
+		return nil, &p.WorkflowExecutionAlreadyStartedError{
+			Msg:              fmt.Sprintf("Workflow execution already running. WorkflowId: %v", executionInfo.WorkflowID),
+			StartRequestID:   executionInfo.CreateRequestID,
+			RunID:            executionInfo.RunID,
+			State:            executionInfo.State,
+			CloseStatus:      executionInfo.CloseStatus,
+			LastWriteVersion: lastWriteVersion,
+		}
+	}
+
+	// TODO we should set the start time and last update time on business logic layer
+	executionInfo.StartTimestamp = time.Now()
+	executionInfo.LastUpdatedTimestamp = executionInfo.StartTimestamp
+
+	row := newExecutionRow()
+	if err := setExecutionRow(row,
+		executionInfo,
+		replicationState,
+		versionHistories,
+		startVersion,
+		lastWriteVersion,
+		currentVersion); err != nil {
+		return nil, err
+	}
+	tx.executions[key] = row
+	return row, nil
+}
+
+func updateExecution(
+	row *executionRow,
+	executionInfo *p.InternalWorkflowExecutionInfo,
+	replicationState *p.ReplicationState,
+	versionHistories *serialization.DataBlob,
+	startVersion int64,
+	lastWriteVersion int64,
+	currentVersion int64,
+) error {
+
+	// validate workflow state & close status
+	if err := p.ValidateUpdateWorkflowStateCloseStatus(
+		executionInfo.State,
+		executionInfo.CloseStatus); err != nil {
+		return err
+	}
+
+	// TODO we should set the last update time on business logic layer
+	executionInfo.LastUpdatedTimestamp = time.Now()
+
+	return setExecutionRow(row,
+		executionInfo,
+		replicationState,
+		versionHistories,
+		startVersion,
+		lastWriteVersion,
+		currentVersion)
+}
+
+func updateMutableStateMaps(
+	row *executionRow,
+	activityInfos []*p.InternalActivityInfo,
+	deleteActivityInfos []int64,
+	timerInfos []*persistenceblobs.TimerInfo,
+	deleteTimerInfos []string,
+	childExecutionInfos []*p.InternalChildExecutionInfo,
+	deleteChildExecutionInfo *int64,
+	requestCancelInfos []*persistenceblobs.RequestCancelInfo,
+	deleteRequestCancelInfo *int64,
+	signalInfos []*persistenceblobs.SignalInfo,
+	deleteSignalInfo *int64,
+	signalRequestedIDs []string,
+	deleteSignalRequestedID string,
+) error {
+
+	for _, v := range activityInfos {
+		blob, err := serialization.ActivityInfoToBlob(v.ToProto())
+		if err != nil {
+			return err
+		}
+		row.activityInfos[v.ScheduleID] = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+	}
+	for _, v := range deleteActivityInfos {
+		delete(row.activityInfos, v)
+	}
+
+	for _, v := range timerInfos {
+		blob, err := serialization.TimerInfoToBlob(v)
+		if err != nil {
+			return err
+		}
+		row.timerInfos[v.TimerID] = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+	}
+	for _, v := range deleteTimerInfos {
+		delete(row.timerInfos, v)
+	}
+
+	for _, v := range childExecutionInfos {
+		blob, err := serialization.ChildExecutionInfoToBlob(v.ToProto())
+		if err != nil {
+			return err
+		}
+		row.childExecutionInfos[v.InitiatedID] = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+	}
+	if deleteChildExecutionInfo != nil {
+		delete(row.childExecutionInfos, *deleteChildExecutionInfo)
+	}
+
+	for _, v := range requestCancelInfos {
+		blob, err := serialization.RequestCancelInfoToBlob(v)
+		if err != nil {
+			return err
+		}
+		row.requestCancelInfos[v.InitiatedID] = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+	}
+	if deleteRequestCancelInfo != nil {
+		delete(row.requestCancelInfos, *deleteRequestCancelInfo)
+	}
+
+	for _, v := range signalInfos {
+		blob, err := serialization.SignalInfoToBlob(v)
+		if err != nil {
+			return err
+		}
+		row.signalInfos[v.InitiatedID] = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+	}
+	if deleteSignalInfo != nil {
+		delete(row.signalInfos, *deleteSignalInfo)
+	}
+
+	for _, v := range signalRequestedIDs {
+		row.signalsRequested[v] = struct{}{}
+	}
+	if deleteSignalRequestedID != "" {
+		delete(row.signalsRequested, deleteSignalRequestedID)
+	}
+
+	return nil
+}
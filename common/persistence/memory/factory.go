@@ -0,0 +1,96 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"github.com/temporalio/temporal/common/log"
+	p "github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/service/config"
+)
+
+type (
+	// Factory vends datastore implementations backed by process memory.
+	// Factories configured with the same database name share their data,
+	// so every service of a onebox cluster observes the same state.
+	Factory struct {
+		cfg         config.Memory
+		clusterName string
+		logger      log.Logger
+		db          *database
+	}
+)
+
+// NewFactory returns an instance of a factory object which can be used to create
+// datastores that are backed by memory
+func NewFactory(cfg config.Memory, clusterName string, logger log.Logger) *Factory {
+	return &Factory{
+		cfg:         cfg,
+		clusterName: clusterName,
+		logger:      logger,
+		db:          getDatabase(cfg.DatabaseName),
+	}
+}
+
+// NewTaskStore returns a new task store
+func (f *Factory) NewTaskStore() (p.TaskStore, error) {
+	return newTaskPersistence(f.db, f.logger), nil
+}
+
+// NewShardStore returns a new shard store
+func (f *Factory) NewShardStore() (p.ShardStore, error) {
+	return newShardPersistence(f.db, f.clusterName, f.logger), nil
+}
+
+// NewHistoryV2Store returns a new history store
+func (f *Factory) NewHistoryV2Store() (p.HistoryStore, error) {
+	return newHistoryV2Persistence(f.db, f.logger), nil
+}
+
+// NewMetadataStore returns a new metadata store
+func (f *Factory) NewMetadataStore() (p.MetadataStore, error) {
+	return newMetadataPersistenceV2(f.db, f.clusterName, f.logger), nil
+}
+
+// NewClusterMetadataStore returns a new cluster metadata store
+func (f *Factory) NewClusterMetadataStore() (p.ClusterMetadataStore, error) {
+	return newClusterMetadataPersistence(f.db, f.logger), nil
+}
+
+// NewExecutionStore returns an ExecutionStore for a given shardID
+func (f *Factory) NewExecutionStore(shardID int) (p.ExecutionStore, error) {
+	return newExecutionPersistence(f.db, f.logger, shardID), nil
+}
+
+// NewVisibilityStore returns a visibility store
+func (f *Factory) NewVisibilityStore() (p.VisibilityStore, error) {
+	return newVisibilityPersistence(f.db, f.logger), nil
+}
+
+// NewQueue returns a new queue backed by memory
+func (f *Factory) NewQueue(queueType p.QueueType) (p.Queue, error) {
+	return newQueue(f.db, f.logger, queueType), nil
+}
+
+// Close closes the factory. The data outlives the factory so that other
+// factories sharing the same database keep working; use DropDatabase to
+// release it.
+func (f *Factory) Close() {
+}
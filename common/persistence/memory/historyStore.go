@@ -0,0 +1,500 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gogo/protobuf/types"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log"
+	p "github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/persistence/serialization"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+type (
+	historyV2Store struct {
+		memoryStore
+	}
+
+	historyTreeKey struct {
+		shardID int
+		treeID  string
+	}
+
+	historyBranchKey struct {
+		shardID  int
+		treeID   string
+		branchID string
+	}
+
+	historyNodeKey struct {
+		nodeID int64
+		txnID  int64
+	}
+
+	historyNodeRow struct {
+		historyNodeKey
+		*blobRow
+	}
+
+	historyTreeBranchPageToken struct {
+		ShardID  int
+		TreeID   []byte
+		BranchID []byte
+	}
+)
+
+// newHistoryV2Persistence creates an instance of HistoryManager
+func newHistoryV2Persistence(
+	db *database,
+	logger log.Logger,
+) p.HistoryStore {
+
+	return &historyV2Store{
+		memoryStore: memoryStore{
+			db:     db,
+			logger: logger,
+		},
+	}
+}
+
+func newHistoryBranchKey(shardID int, treeID primitives.UUID, branchID primitives.UUID) historyBranchKey {
+	return historyBranchKey{shardID: shardID, treeID: string(treeID), branchID: string(branchID)}
+}
+
+// AppendHistoryNodes add(or override) a node to a history branch
+func (m *historyV2Store) AppendHistoryNodes(
+	request *p.InternalAppendHistoryNodesRequest,
+) error {
+
+	branchInfo := request.BranchInfo
+	beginNodeID := p.GetBeginNodeID(branchInfo)
+
+	if request.NodeID < beginNodeID {
+		return &p.InvalidPersistenceRequestError{
+			Msg: "cannot append to ancestors' nodes",
+		}
+	}
+
+	branchKey := newHistoryBranchKey(request.ShardID, branchInfo.GetTreeID(), branchInfo.GetBranchID())
+	nodeKey := historyNodeKey{nodeID: request.NodeID, txnID: request.TransactionID}
+	nodeRow := newBlobRow(request.Events.Data, string(request.Events.Encoding))
+
+	var treeRow *blobRow
+	if request.IsNewBranch {
+		treeInfo := &persistenceblobs.HistoryTreeInfo{
+			BranchInfo: branchInfo,
+			Info:       request.Info,
+			ForkTime:   types.TimestampNow(),
+		}
+		blob, err := serialization.HistoryTreeInfoToBlob(treeInfo)
+		if err != nil {
+			return err
+		}
+		treeRow = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+	}
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	if _, ok := m.db.historyNodes[branchKey][nodeKey]; ok {
+		return &p.ConditionFailedError{Msg: fmt.Sprintf("AppendHistoryNodes: row already exist: node %v, transaction %v", request.NodeID, request.TransactionID)}
+	}
+	treeKey := historyTreeKey{shardID: request.ShardID, treeID: branchKey.treeID}
+	if treeRow != nil {
+		if _, ok := m.db.historyTrees[treeKey][branchKey.branchID]; ok {
+			return serviceerror.NewInternal("AppendHistoryNodes: branch already exists in tree table")
+		}
+		branches, ok := m.db.historyTrees[treeKey]
+		if !ok {
+			branches = make(map[string]*blobRow)
+			m.db.historyTrees[treeKey] = branches
+		}
+		branches[branchKey.branchID] = treeRow
+	}
+
+	nodes, ok := m.db.historyNodes[branchKey]
+	if !ok {
+		nodes = make(map[historyNodeKey]*blobRow)
+		m.db.historyNodes[branchKey] = nodes
+	}
+	nodes[nodeKey] = nodeRow
+	return nil
+}
+
+// ReadHistoryBranch returns history node data for a branch
+func (m *historyV2Store) ReadHistoryBranch(
+	request *p.InternalReadHistoryBranchRequest,
+) (*p.InternalReadHistoryBranchResponse, error) {
+
+	minNodeID := request.MinNodeID
+	maxNodeID := request.MaxNodeID
+
+	lastNodeID := request.LastNodeID
+	lastTxnID := request.LastTransactionID
+
+	if request.NextPageToken != nil && len(request.NextPageToken) > 0 {
+		var lastNodeID int64
+		var err error
+		// TODO the inner pagination token can be replaced by a dummy token
+		//  since lastNodeID & lastTxnID are both provided
+		if lastNodeID, err = deserializePageToken(request.NextPageToken); err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("invalid next page token %v", request.NextPageToken))
+		}
+		minNodeID = lastNodeID + 1
+	}
+
+	m.db.Lock()
+	var rows []historyNodeRow
+	for k, v := range m.db.historyNodes[newHistoryBranchKey(request.ShardID, request.TreeID, request.BranchID)] {
+		if k.nodeID >= minNodeID && k.nodeID < maxNodeID {
+			rows = append(rows, historyNodeRow{historyNodeKey: k, blobRow: v})
+		}
+	}
+	m.db.Unlock()
+
+	if len(rows) == 0 {
+		return &p.InternalReadHistoryBranchResponse{}, nil
+	}
+	// node ID ascending, and for the same node the latest transaction first
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].nodeID != rows[j].nodeID {
+			return rows[i].nodeID < rows[j].nodeID
+		}
+		return rows[i].txnID > rows[j].txnID
+	})
+	if len(rows) > request.PageSize {
+		rows = rows[:request.PageSize]
+	}
+
+	history := make([]*serialization.DataBlob, 0, int(request.PageSize))
+
+	for _, row := range rows {
+		if row.txnID < lastTxnID {
+			// assuming that business logic layer is correct and transaction ID only increase
+			// thus, valid event batch will come with increasing transaction ID
+
+			// event batches with smaller node ID
+			//  -> should not be possible since records are already sorted
+			// event batches with same node ID
+			//  -> batch with higher transaction ID is valid
+			// event batches with larger node ID
+			//  -> batch with lower transaction ID is invalid (happens before)
+			//  -> batch with higher transaction ID is valid
+			if row.nodeID < lastNodeID {
+				return nil, serviceerror.NewInternal(fmt.Sprintf("corrupted data, nodeID cannot decrease"))
+			} else if row.nodeID > lastNodeID {
+				// update lastNodeID so that our pagination can make progress in the corner case that
+				// the page are all rows with smaller txnID
+				// because next page we always have minNodeID = lastNodeID+1
+				lastNodeID = row.nodeID
+			}
+			continue
+		}
+
+		switch {
+		case row.nodeID < lastNodeID:
+			return nil, serviceerror.NewInternal(fmt.Sprintf("corrupted data, nodeID cannot decrease"))
+		case row.nodeID == lastNodeID:
+			return nil, serviceerror.NewInternal(fmt.Sprintf("corrupted data, same nodeID must have smaller txnID"))
+		default: // row.nodeID > lastNodeID:
+			// NOTE: when row.nodeID > lastNodeID, we expect the one with largest txnID comes first
+			lastTxnID = row.txnID
+			lastNodeID = row.nodeID
+			history = append(history, p.NewDataBlob(copyBytes(row.data), common.EncodingType(row.encoding)))
+		}
+	}
+
+	var pagingToken []byte
+	if len(rows) >= request.PageSize {
+		pagingToken = serializePageToken(lastNodeID)
+	}
+
+	return &p.InternalReadHistoryBranchResponse{
+		History:           history,
+		NextPageToken:     pagingToken,
+		LastNodeID:        lastNodeID,
+		LastTransactionID: lastTxnID,
+	}, nil
+}
+
+// ForkHistoryBranch forks a new branch from an existing branch.
+// See the SQL implementation for a description of the valid forking node IDs.
+func (m *historyV2Store) ForkHistoryBranch(
+	request *p.InternalForkHistoryBranchRequest,
+) (*p.InternalForkHistoryBranchResponse, error) {
+
+	forkB := request.ForkBranchInfo
+	treeID := forkB.TreeID
+	newAncestors := make([]*persistenceblobs.HistoryBranchRange, 0, len(forkB.Ancestors)+1)
+
+	beginNodeID := p.GetBeginNodeID(forkB)
+	if beginNodeID >= request.ForkNodeID {
+		// this is the case that new branch's ancestors doesn't include the forking branch
+		for _, br := range forkB.Ancestors {
+			if br.EndNodeID >= request.ForkNodeID {
+				newAncestors = append(newAncestors, &persistenceblobs.HistoryBranchRange{
+					BranchID:    br.BranchID,
+					BeginNodeID: br.BeginNodeID,
+					EndNodeID:   request.ForkNodeID,
+				})
+				break
+			} else {
+				newAncestors = append(newAncestors, br)
+			}
+		}
+	} else {
+		// this is the case the new branch will inherit all ancestors from forking branch
+		newAncestors = forkB.Ancestors
+		newAncestors = append(newAncestors, &persistenceblobs.HistoryBranchRange{
+			BranchID:    forkB.BranchID,
+			BeginNodeID: beginNodeID,
+			EndNodeID:   request.ForkNodeID,
+		})
+	}
+
+	treeInfo := &persistenceblobs.HistoryTreeInfo{
+		BranchInfo: &persistenceblobs.HistoryBranch{
+			TreeID:    treeID,
+			BranchID:  request.NewBranchID,
+			Ancestors: newAncestors,
+		},
+		Info:     request.Info,
+		ForkTime: types.TimestampNow(),
+	}
+
+	blob, err := serialization.HistoryTreeInfoToBlob(treeInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	treeKey := historyTreeKey{shardID: request.ShardID, treeID: string(treeID)}
+	branches, ok := m.db.historyTrees[treeKey]
+	if !ok {
+		branches = make(map[string]*blobRow)
+		m.db.historyTrees[treeKey] = branches
+	}
+	if _, ok := branches[string(request.NewBranchID)]; ok {
+		return nil, serviceerror.NewInternal("ForkHistoryBranch: branch already exists in tree table")
+	}
+	branches[string(request.NewBranchID)] = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+
+	return &p.InternalForkHistoryBranchResponse{
+		NewBranchInfo: treeInfo.BranchInfo,
+	}, nil
+}
+
+// DeleteHistoryBranch removes a branch
+func (m *historyV2Store) DeleteHistoryBranch(
+	request *p.InternalDeleteHistoryBranchRequest,
+) error {
+
+	branch := request.BranchInfo
+	treeID := branch.TreeID
+	brsToDelete := branch.Ancestors
+	beginNodeID := p.GetBeginNodeID(branch)
+	brsToDelete = append(brsToDelete, &persistenceblobs.HistoryBranchRange{
+		BranchID:    branch.BranchID,
+		BeginNodeID: beginNodeID,
+	})
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	treeKey := historyTreeKey{shardID: request.ShardID, treeID: string(treeID)}
+	branches, err := m.getHistoryTree(treeKey)
+	if err != nil {
+		return err
+	}
+
+	// validBRsMaxEndNode is to for each branch range that is being used, we want to know what is the max nodeID referred by other valid branch
+	validBRsMaxEndNode := map[string]int64{}
+	for _, b := range branches {
+		for _, br := range b.Ancestors {
+			curr, ok := validBRsMaxEndNode[string(br.BranchID)]
+			if !ok || curr < br.EndNodeID {
+				validBRsMaxEndNode[string(br.BranchID)] = br.EndNodeID
+			}
+		}
+	}
+
+	delete(m.db.historyTrees[treeKey], string(branch.BranchID))
+	if len(m.db.historyTrees[treeKey]) == 0 {
+		delete(m.db.historyTrees, treeKey)
+	}
+
+	// for each branch range to delete, we iterate from bottom to up, and delete up to the point according to validBRsEndNode
+	for i := len(brsToDelete) - 1; i >= 0; i-- {
+		br := brsToDelete[i]
+		if _, ok := m.db.historyTrees[treeKey][string(br.BranchID)]; ok {
+			// the ancestor is still a branch of the tree, all of its nodes are in use
+			// and it can be forked again from any of them
+			break
+		}
+		maxReferredEndNodeID, ok := validBRsMaxEndNode[string(br.BranchID)]
+		minNodeID := br.BeginNodeID
+		if ok {
+			// we can only delete from the maxEndNode and stop here
+			minNodeID = maxReferredEndNodeID
+		}
+
+		branchKey := newHistoryBranchKey(request.ShardID, treeID, br.BranchID)
+		nodes := m.db.historyNodes[branchKey]
+		for k := range nodes {
+			if k.nodeID >= minNodeID {
+				delete(nodes, k)
+			}
+		}
+		if len(nodes) == 0 {
+			delete(m.db.historyNodes, branchKey)
+		}
+
+		if ok {
+			break
+		}
+	}
+	return nil
+}
+
+// GetAllHistoryTreeBranches returns all branches of all trees, ordered by shard, tree and branch
+func (m *historyV2Store) GetAllHistoryTreeBranches(
+	request *p.GetAllHistoryTreeBranchesRequest,
+) (*p.GetAllHistoryTreeBranchesResponse, error) {
+
+	var after *historyBranchKey
+	if len(request.NextPageToken) > 0 {
+		var token historyTreeBranchPageToken
+		if err := json.Unmarshal(request.NextPageToken, &token); err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("GetAllHistoryTreeBranches: invalid next page token: %v", err))
+		}
+		after = &historyBranchKey{shardID: token.ShardID, treeID: string(token.TreeID), branchID: string(token.BranchID)}
+	}
+
+	m.db.Lock()
+	var keys []historyBranchKey
+	for treeKey, branches := range m.db.historyTrees {
+		for branchID := range branches {
+			key := historyBranchKey{shardID: treeKey.shardID, treeID: treeKey.treeID, branchID: branchID}
+			if after == nil || after.less(key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+	if len(keys) > request.PageSize {
+		keys = keys[:request.PageSize]
+	}
+	rows := make([]*blobRow, len(keys))
+	for i, k := range keys {
+		rows[i] = m.db.historyTrees[historyTreeKey{shardID: k.shardID, treeID: k.treeID}][k.branchID]
+	}
+	m.db.Unlock()
+
+	branches := make([]p.HistoryBranchDetail, 0, len(rows))
+	for i, row := range rows {
+		treeInfo, err := serialization.HistoryTreeInfoFromBlob(row.data, row.encoding)
+		if err != nil {
+			return nil, err
+		}
+		forkTime, err := types.TimestampFromProto(treeInfo.ForkTime)
+		if err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("GetAllHistoryTreeBranches: invalid fork time: %v", err))
+		}
+		branches = append(branches, p.HistoryBranchDetail{
+			TreeID:   primitives.UUID(keys[i].treeID).String(),
+			BranchID: primitives.UUID(keys[i].branchID).String(),
+			ForkTime: forkTime,
+			Info:     treeInfo.Info,
+		})
+	}
+
+	var nextPageToken []byte
+	if len(keys) > 0 && len(keys) >= request.PageSize {
+		lastKey := keys[len(keys)-1]
+		var err error
+		nextPageToken, err = json.Marshal(&historyTreeBranchPageToken{
+			ShardID:  lastKey.shardID,
+			TreeID:   []byte(lastKey.treeID),
+			BranchID: []byte(lastKey.branchID),
+		})
+		if err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("GetAllHistoryTreeBranches: failed to serialize next page token: %v", err))
+		}
+	}
+
+	return &p.GetAllHistoryTreeBranchesResponse{
+		Branches:      branches,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// GetHistoryTree returns all branch information of a tree
+func (m *historyV2Store) GetHistoryTree(
+	request *p.GetHistoryTreeRequest,
+) (*p.GetHistoryTreeResponse, error) {
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	branches, err := m.getHistoryTree(historyTreeKey{shardID: *request.ShardID, treeID: string(request.TreeID)})
+	if err != nil {
+		return nil, err
+	}
+	if len(branches) == 0 {
+		return &p.GetHistoryTreeResponse{}, nil
+	}
+	return &p.GetHistoryTreeResponse{
+		Branches: branches,
+	}, nil
+}
+
+// getHistoryTree decodes the branches of a tree. Callers must hold the database lock.
+func (m *historyV2Store) getHistoryTree(key historyTreeKey) ([]*persistenceblobs.HistoryBranch, error) {
+	branches := make([]*persistenceblobs.HistoryBranch, 0)
+	for _, row := range m.db.historyTrees[key] {
+		treeInfo, err := serialization.HistoryTreeInfoFromBlob(row.data, row.encoding)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, treeInfo.BranchInfo)
+	}
+	return branches, nil
+}
+
+func (k historyBranchKey) less(other historyBranchKey) bool {
+	if k.shardID != other.shardID {
+		return k.shardID < other.shardID
+	}
+	if k.treeID != other.treeID {
+		return k.treeID < other.treeID
+	}
+	return k.branchID < other.branchID
+}
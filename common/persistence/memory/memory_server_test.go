@@ -0,0 +1,92 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	pt "github.com/temporalio/temporal/common/persistence/persistence-tests"
+)
+
+func TestMemoryHistoryV2Persistence(t *testing.T) {
+	s := new(pt.HistoryV2PersistenceSuite)
+	s.TestBase = pt.NewTestBaseWithMemory(&pt.TestBaseOptions{})
+	s.TestBase.Setup()
+	suite.Run(t, s)
+}
+
+func TestMemoryMatchingPersistence(t *testing.T) {
+	s := new(pt.MatchingPersistenceSuite)
+	s.TestBase = pt.NewTestBaseWithMemory(&pt.TestBaseOptions{})
+	s.TestBase.Setup()
+	suite.Run(t, s)
+}
+
+func TestMemoryMetadataPersistenceV2(t *testing.T) {
+	s := new(pt.MetadataPersistenceSuiteV2)
+	s.TestBase = pt.NewTestBaseWithMemory(&pt.TestBaseOptions{})
+	s.TestBase.Setup()
+	suite.Run(t, s)
+}
+
+func TestMemoryShardPersistence(t *testing.T) {
+	s := new(pt.ShardPersistenceSuite)
+	s.TestBase = pt.NewTestBaseWithMemory(&pt.TestBaseOptions{})
+	s.TestBase.Setup()
+	suite.Run(t, s)
+}
+
+func TestMemoryVisibilityPersistence(t *testing.T) {
+	s := new(pt.VisibilityPersistenceSuite)
+	s.TestBase = pt.NewTestBaseWithMemory(&pt.TestBaseOptions{})
+	s.TestBase.Setup()
+	suite.Run(t, s)
+}
+
+func TestMemoryExecutionManager(t *testing.T) {
+	s := new(pt.ExecutionManagerSuite)
+	s.TestBase = pt.NewTestBaseWithMemory(&pt.TestBaseOptions{})
+	s.TestBase.Setup()
+	suite.Run(t, s)
+}
+
+func TestMemoryExecutionManagerWithEventsV2(t *testing.T) {
+	s := new(pt.ExecutionManagerSuiteForEventsV2)
+	s.TestBase = pt.NewTestBaseWithMemory(&pt.TestBaseOptions{})
+	s.TestBase.Setup()
+	suite.Run(t, s)
+}
+
+func TestMemoryQueuePersistence(t *testing.T) {
+	s := new(pt.QueuePersistenceSuite)
+	s.TestBase = pt.NewTestBaseWithMemory(&pt.TestBaseOptions{})
+	s.TestBase.Setup()
+	suite.Run(t, s)
+}
+
+func TestMemoryClusterMetadataPersistence(t *testing.T) {
+	s := new(pt.ClusterMetadataManagerSuite)
+	s.TestBase = pt.NewTestBaseWithMemory(&pt.TestBaseOptions{})
+	s.TestBase.Setup()
+	suite.Run(t, s)
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/persistence/serialization"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+type (
+	metadataStore struct {
+		memoryStore
+		activeClusterName string
+	}
+
+	// domainRow is keyed by the raw bytes of the domain ID, so that listing
+	// domains in key order matches the ordering of the SQL stores
+	domainRow struct {
+		id       primitives.UUID
+		name     string
+		isGlobal bool
+		blobRow
+	}
+)
+
+// newMetadataPersistenceV2 creates an instance of metadataStore
+func newMetadataPersistenceV2(
+	db *database,
+	currentClusterName string,
+	logger log.Logger,
+) persistence.MetadataStore {
+
+	return &metadataStore{
+		memoryStore: memoryStore{
+			db:     db,
+			logger: logger,
+		},
+		activeClusterName: currentClusterName,
+	}
+}
+
+// updateMetadata bumps the metadata notification version, provided it still is
+// oldNotificationVersion. Callers must hold the database lock.
+func (db *database) updateMetadata(oldNotificationVersion int64) error {
+	if db.metadataVersion != oldNotificationVersion {
+		return serviceerror.NewInternal(fmt.Sprintf("Failed to update domain metadata. Notification version %v does not match %v.",
+			oldNotificationVersion, db.metadataVersion))
+	}
+	db.metadataVersion++
+	return nil
+}
+
+func (m *metadataStore) CreateDomain(request *persistence.InternalCreateDomainRequest) (*persistence.CreateDomainResponse, error) {
+	metadata, err := m.GetMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]string, len(request.ReplicationConfig.Clusters))
+	for i := range clusters {
+		clusters[i] = request.ReplicationConfig.Clusters[i].ClusterName
+	}
+
+	var badBinaries []byte
+	var badBinariesEncoding string
+	if request.Config.BadBinaries != nil {
+		badBinaries = request.Config.BadBinaries.Data
+		badBinariesEncoding = string(request.Config.BadBinaries.GetEncoding())
+	}
+	domainInfo := &persistenceblobs.DomainInfo{
+		Status:                      int32(request.Info.Status),
+		Description:                 request.Info.Description,
+		Owner:                       request.Info.OwnerEmail,
+		Data:                        request.Info.Data,
+		RetentionDays:               request.Config.Retention,
+		EmitMetric:                  request.Config.EmitMetric,
+		ArchivalBucket:              request.Config.ArchivalBucket,
+		ArchivalStatus:              int32(request.Config.ArchivalStatus),
+		HistoryArchivalStatus:       int32(request.Config.HistoryArchivalStatus),
+		HistoryArchivalURI:          request.Config.HistoryArchivalURI,
+		VisibilityArchivalStatus:    int32(request.Config.VisibilityArchivalStatus),
+		VisibilityArchivalURI:       request.Config.VisibilityArchivalURI,
+		ActiveClusterName:           request.ReplicationConfig.ActiveClusterName,
+		Clusters:                    clusters,
+		ConfigVersion:               request.ConfigVersion,
+		FailoverVersion:             request.FailoverVersion,
+		NotificationVersion:         metadata.NotificationVersion,
+		FailoverNotificationVersion: persistence.InitialFailoverNotificationVersion,
+		BadBinaries:                 badBinaries,
+		BadBinariesEncoding:         badBinariesEncoding,
+	}
+
+	blob, err := serialization.DomainInfoToBlob(domainInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	id := primitives.MustParseUUID(request.Info.ID)
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	if _, ok := m.db.domainNames[request.Info.Name]; ok {
+		return nil, serviceerror.NewDomainAlreadyExists(fmt.Sprintf("name: %v", request.Info.Name))
+	}
+	if _, ok := m.db.domains[string(id)]; ok {
+		return nil, serviceerror.NewDomainAlreadyExists(fmt.Sprintf("name: %v", request.Info.Name))
+	}
+	if err := m.db.updateMetadata(metadata.NotificationVersion); err != nil {
+		return nil, err
+	}
+	m.db.domains[string(id)] = &domainRow{
+		id:       id,
+		name:     request.Info.Name,
+		isGlobal: request.IsGlobalDomain,
+		blobRow:  blobRow{data: blob.Data, encoding: string(blob.Encoding)},
+	}
+	m.db.domainNames[request.Info.Name] = string(id)
+
+	return &persistence.CreateDomainResponse{ID: request.Info.ID}, nil
+}
+
+func (m *metadataStore) GetDomain(request *persistence.GetDomainRequest) (*persistence.InternalGetDomainResponse, error) {
+	m.db.Lock()
+	var row *domainRow
+	switch {
+	case request.Name != "" && request.ID != "":
+		m.db.Unlock()
+		return nil, serviceerror.NewInvalidArgument("GetDomain operation failed.  Both ID and Name specified in request.")
+	case request.Name != "":
+		if id, ok := m.db.domainNames[request.Name]; ok {
+			row = m.db.domains[id]
+		}
+	case request.ID != "":
+		row = m.db.domains[string(primitives.MustParseUUID(request.ID))]
+	default:
+		m.db.Unlock()
+		return nil, serviceerror.NewInvalidArgument("GetDomain operation failed.  Both ID and Name are empty.")
+	}
+	m.db.Unlock()
+
+	if row == nil {
+		identity := request.Name
+		if len(request.ID) > 0 {
+			identity = request.ID
+		}
+		return nil, serviceerror.NewNotFound(fmt.Sprintf("Domain %s does not exist.", identity))
+	}
+
+	return m.domainRowToGetDomainResponse(row)
+}
+
+func (m *metadataStore) domainRowToGetDomainResponse(row *domainRow) (*persistence.InternalGetDomainResponse, error) {
+	domainInfo, err := serialization.DomainInfoFromBlob(row.data, row.encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]*persistence.ClusterReplicationConfig, len(domainInfo.Clusters))
+	for i := range domainInfo.Clusters {
+		clusters[i] = &persistence.ClusterReplicationConfig{ClusterName: domainInfo.Clusters[i]}
+	}
+
+	var badBinaries *serialization.DataBlob
+	if domainInfo.BadBinaries != nil {
+		badBinaries = persistence.NewDataBlob(domainInfo.BadBinaries, common.EncodingType(domainInfo.BadBinariesEncoding))
+	}
+
+	return &persistence.InternalGetDomainResponse{
+		Info: &persistence.DomainInfo{
+			ID:          row.id.String(),
+			Name:        row.name,
+			Status:      int(domainInfo.GetStatus()),
+			Description: domainInfo.GetDescription(),
+			OwnerEmail:  domainInfo.GetOwner(),
+			Data:        domainInfo.GetData(),
+		},
+		Config: &persistence.InternalDomainConfig{
+			Retention:                domainInfo.GetRetentionDays(),
+			EmitMetric:               domainInfo.GetEmitMetric(),
+			ArchivalBucket:           domainInfo.GetArchivalBucket(),
+			ArchivalStatus:           enums.ArchivalStatus(domainInfo.GetArchivalStatus()),
+			HistoryArchivalStatus:    enums.ArchivalStatus(domainInfo.GetHistoryArchivalStatus()),
+			HistoryArchivalURI:       domainInfo.GetHistoryArchivalURI(),
+			VisibilityArchivalStatus: enums.ArchivalStatus(domainInfo.GetVisibilityArchivalStatus()),
+			VisibilityArchivalURI:    domainInfo.GetVisibilityArchivalURI(),
+			BadBinaries:              badBinaries,
+		},
+		ReplicationConfig: &persistence.DomainReplicationConfig{
+			ActiveClusterName: persistence.GetOrUseDefaultActiveCluster(m.activeClusterName, domainInfo.GetActiveClusterName()),
+			Clusters:          persistence.GetOrUseDefaultClusters(m.activeClusterName, clusters),
+		},
+		IsGlobalDomain:              row.isGlobal,
+		FailoverVersion:             domainInfo.GetFailoverVersion(),
+		ConfigVersion:               domainInfo.GetConfigVersion(),
+		NotificationVersion:         domainInfo.GetNotificationVersion(),
+		FailoverNotificationVersion: domainInfo.GetFailoverNotificationVersion(),
+	}, nil
+}
+
+func (m *metadataStore) UpdateDomain(request *persistence.InternalUpdateDomainRequest) error {
+	clusters := make([]string, len(request.ReplicationConfig.Clusters))
+	for i := range clusters {
+		clusters[i] = request.ReplicationConfig.Clusters[i].ClusterName
+	}
+
+	var badBinaries []byte
+	var badBinariesEncoding string
+	if request.Config.BadBinaries != nil {
+		badBinaries = request.Config.BadBinaries.Data
+		badBinariesEncoding = string(request.Config.BadBinaries.GetEncoding())
+	}
+	domainInfo := &persistenceblobs.DomainInfo{
+		Status:                      int32(request.Info.Status),
+		Description:                 request.Info.Description,
+		Owner:                       request.Info.OwnerEmail,
+		Data:                        request.Info.Data,
+		RetentionDays:               request.Config.Retention,
+		EmitMetric:                  request.Config.EmitMetric,
+		ArchivalBucket:              request.Config.ArchivalBucket,
+		ArchivalStatus:              int32(request.Config.ArchivalStatus),
+		HistoryArchivalStatus:       int32(request.Config.HistoryArchivalStatus),
+		HistoryArchivalURI:          request.Config.HistoryArchivalURI,
+		VisibilityArchivalStatus:    int32(request.Config.VisibilityArchivalStatus),
+		VisibilityArchivalURI:       request.Config.VisibilityArchivalURI,
+		ActiveClusterName:           request.ReplicationConfig.ActiveClusterName,
+		Clusters:                    clusters,
+		ConfigVersion:               request.ConfigVersion,
+		FailoverVersion:             request.FailoverVersion,
+		NotificationVersion:         request.NotificationVersion,
+		FailoverNotificationVersion: request.FailoverNotificationVersion,
+		BadBinaries:                 badBinaries,
+		BadBinariesEncoding:         badBinariesEncoding,
+	}
+
+	blob, err := serialization.DomainInfoToBlob(domainInfo)
+	if err != nil {
+		return err
+	}
+
+	id := primitives.MustParseUUID(request.Info.ID)
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	row, ok := m.db.domains[string(id)]
+	if !ok {
+		return fmt.Errorf("0 rows updated instead of one")
+	}
+	if otherID, ok := m.db.domainNames[request.Info.Name]; ok && otherID != string(id) {
+		return serviceerror.NewDomainAlreadyExists(fmt.Sprintf("name: %v", request.Info.Name))
+	}
+	if err := m.db.updateMetadata(request.NotificationVersion); err != nil {
+		return err
+	}
+	delete(m.db.domainNames, row.name)
+	m.db.domains[string(id)] = &domainRow{
+		id:       id,
+		name:     request.Info.Name,
+		isGlobal: row.isGlobal,
+		blobRow:  blobRow{data: blob.Data, encoding: string(blob.Encoding)},
+	}
+	m.db.domainNames[request.Info.Name] = string(id)
+	return nil
+}
+
+func (m *metadataStore) DeleteDomain(request *persistence.DeleteDomainRequest) error {
+	id := string(primitives.MustParseUUID(request.ID))
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	if row, ok := m.db.domains[id]; ok {
+		delete(m.db.domainNames, row.name)
+		delete(m.db.domains, id)
+	}
+	return nil
+}
+
+func (m *metadataStore) DeleteDomainByName(request *persistence.DeleteDomainByNameRequest) error {
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	if id, ok := m.db.domainNames[request.Name]; ok {
+		delete(m.db.domains, id)
+		delete(m.db.domainNames, request.Name)
+	}
+	return nil
+}
+
+func (m *metadataStore) GetMetadata() (*persistence.GetMetadataResponse, error) {
+	m.db.Lock()
+	defer m.db.Unlock()
+	return &persistence.GetMetadataResponse{NotificationVersion: m.db.metadataVersion}, nil
+}
+
+func (m *metadataStore) ListDomains(request *persistence.ListDomainsRequest) (*persistence.InternalListDomainsResponse, error) {
+	m.db.Lock()
+	var rows []*domainRow
+	for id, row := range m.db.domains {
+		if request.NextPageToken == nil || id > string(request.NextPageToken) {
+			rows = append(rows, row)
+		}
+	}
+	m.db.Unlock()
+
+	sort.Slice(rows, func(i, j int) bool { return string(rows[i].id) < string(rows[j].id) })
+	if len(rows) > request.PageSize {
+		rows = rows[:request.PageSize]
+	}
+
+	var domains []*persistence.InternalGetDomainResponse
+	for _, row := range rows {
+		resp, err := m.domainRowToGetDomainResponse(row)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, resp)
+	}
+
+	resp := &persistence.InternalListDomainsResponse{Domains: domains}
+	if len(rows) > 0 && len(rows) >= request.PageSize {
+		resp.NextPageToken = copyBytes(rows[len(rows)-1].id)
+	}
+	return resp, nil
+}
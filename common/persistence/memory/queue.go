@@ -0,0 +1,270 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/persistence"
+)
+
+const (
+	emptyMessageID = -1
+)
+
+type (
+	queue struct {
+		memoryStore
+		queueType persistence.QueueType
+	}
+
+	// queueTable holds the messages and ack levels of one queue type. Message
+	// IDs are never reused, even after all the messages have been deleted.
+	queueTable struct {
+		messages      map[int][]byte
+		lastMessageID int
+		ackLevels     map[string]int
+	}
+)
+
+func newQueue(
+	db *database,
+	logger log.Logger,
+	queueType persistence.QueueType,
+) persistence.Queue {
+
+	return &queue{
+		memoryStore: memoryStore{
+			db:     db,
+			logger: logger,
+		},
+		queueType: queueType,
+	}
+}
+
+// getQueueTable returns the table of the queue type, creating it on first use.
+// Callers must hold the database lock.
+func (db *database) getQueueTable(queueType persistence.QueueType) *queueTable {
+	table, ok := db.queues[queueType]
+	if !ok {
+		table = &queueTable{
+			messages:      make(map[int][]byte),
+			lastMessageID: emptyMessageID,
+		}
+		db.queues[queueType] = table
+	}
+	return table
+}
+
+func (t *queueTable) enqueue(messagePayload []byte) int {
+	t.lastMessageID++
+	t.messages[t.lastMessageID] = copyBytes(messagePayload)
+	return t.lastMessageID
+}
+
+// selectMessages returns the messages with minExclusive < ID <= maxInclusive in
+// ID order, at most pageSize of them
+func (t *queueTable) selectMessages(minExclusive int, maxInclusive int, pageSize int) []*persistence.QueueMessage {
+	var messages []*persistence.QueueMessage
+	for id, payload := range t.messages {
+		if id > minExclusive && id <= maxInclusive {
+			messages = append(messages, &persistence.QueueMessage{ID: id, Payload: copyBytes(payload)})
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ID < messages[j].ID })
+	if len(messages) > pageSize {
+		messages = messages[:pageSize]
+	}
+	return messages
+}
+
+// updateAckLevel moves the ack level of the cluster forward, ignoring possibly
+// delayed updates to an older level
+func (t *queueTable) updateAckLevel(messageID int, clusterName string) {
+	if t.ackLevels == nil {
+		t.ackLevels = make(map[string]int)
+	}
+	if level, ok := t.ackLevels[clusterName]; ok && level > messageID {
+		return
+	}
+	t.ackLevels[clusterName] = messageID
+}
+
+func (t *queueTable) getAckLevels() map[string]int {
+	if t.ackLevels == nil {
+		return nil
+	}
+	ackLevels := make(map[string]int, len(t.ackLevels))
+	for clusterName, level := range t.ackLevels {
+		ackLevels[clusterName] = level
+	}
+	return ackLevels
+}
+
+func (q *queue) EnqueueMessage(
+	messagePayload []byte,
+) error {
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	q.db.getQueueTable(q.queueType).enqueue(messagePayload)
+	return nil
+}
+
+func (q *queue) ReadMessages(
+	lastMessageID int,
+	maxCount int,
+) ([]*persistence.QueueMessage, error) {
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	table := q.db.getQueueTable(q.queueType)
+	return table.selectMessages(lastMessageID, table.lastMessageID, maxCount), nil
+}
+
+func (q *queue) DeleteMessagesBefore(
+	messageID int,
+) error {
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	table := q.db.getQueueTable(q.queueType)
+	for id := range table.messages {
+		if id < messageID {
+			delete(table.messages, id)
+		}
+	}
+	return nil
+}
+
+func (q *queue) UpdateAckLevel(
+	messageID int,
+	clusterName string,
+) error {
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	q.db.getQueueTable(q.queueType).updateAckLevel(messageID, clusterName)
+	return nil
+}
+
+func (q *queue) GetAckLevels() (map[string]int, error) {
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	return q.db.getQueueTable(q.queueType).getAckLevels(), nil
+}
+
+func (q *queue) EnqueueMessageToDLQ(
+	messagePayload []byte,
+) (int, error) {
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	return q.db.getQueueTable(q.getDLQTypeFromQueueType()).enqueue(messagePayload), nil
+}
+
+func (q *queue) ReadMessagesFromDLQ(
+	firstMessageID int,
+	lastMessageID int,
+	pageSize int,
+	pageToken []byte,
+) ([]*persistence.QueueMessage, []byte, error) {
+
+	if pageToken != nil && len(pageToken) != 0 {
+		lastReadMessageID, err := deserializePageToken(pageToken)
+		if err != nil {
+			return nil, nil, serviceerror.NewInternal(fmt.Sprintf("invalid next page token %v", pageToken))
+		}
+		firstMessageID = int(lastReadMessageID)
+	}
+
+	q.db.Lock()
+	messages := q.db.getQueueTable(q.getDLQTypeFromQueueType()).selectMessages(firstMessageID, lastMessageID, pageSize)
+	q.db.Unlock()
+
+	var newPagingToken []byte
+	if messages != nil && len(messages) >= pageSize {
+		lastReadMessageID := messages[len(messages)-1].ID
+		newPagingToken = serializePageToken(int64(lastReadMessageID))
+	}
+	return messages, newPagingToken, nil
+}
+
+func (q *queue) DeleteMessageFromDLQ(
+	messageID int,
+) error {
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	delete(q.db.getQueueTable(q.getDLQTypeFromQueueType()).messages, messageID)
+	return nil
+}
+
+func (q *queue) RangeDeleteMessagesFromDLQ(
+	firstMessageID int,
+	lastMessageID int,
+) error {
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	table := q.db.getQueueTable(q.getDLQTypeFromQueueType())
+	for id := range table.messages {
+		if id > firstMessageID && id <= lastMessageID {
+			delete(table.messages, id)
+		}
+	}
+	return nil
+}
+
+func (q *queue) UpdateDLQAckLevel(
+	messageID int,
+	clusterName string,
+) error {
+
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	q.db.getQueueTable(q.getDLQTypeFromQueueType()).updateAckLevel(messageID, clusterName)
+	return nil
+}
+
+func (q *queue) GetDLQAckLevels() (map[string]int, error) {
+	q.db.Lock()
+	defer q.db.Unlock()
+
+	return q.db.getQueueTable(q.getDLQTypeFromQueueType()).getAckLevels(), nil
+}
+
+func (q *queue) getDLQTypeFromQueueType() persistence.QueueType {
+	return -q.queueType
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"fmt"
+
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/common/log"
+	p "github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/persistence/serialization"
+)
+
+type (
+	shardStore struct {
+		memoryStore
+		currentClusterName string
+	}
+
+	shardRow struct {
+		rangeID int64
+		blobRow
+	}
+)
+
+// newShardPersistence creates an instance of ShardManager
+func newShardPersistence(db *database, currentClusterName string, logger log.Logger) p.ShardManager {
+	return &shardStore{
+		memoryStore: memoryStore{
+			db:     db,
+			logger: logger,
+		},
+		currentClusterName: currentClusterName,
+	}
+}
+
+func (m *shardStore) CreateShard(request *p.CreateShardRequest) error {
+	blob, err := serialization.ShardInfoToBlob(request.ShardInfo)
+	if err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("CreateShard operation failed. Error: %v", err))
+	}
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	shardID := int(request.ShardInfo.ShardID)
+	if _, ok := m.db.shards[shardID]; ok {
+		return &p.ShardAlreadyExistError{
+			Msg: fmt.Sprintf("CreateShard operaiton failed. Shard with ID %v already exists.", shardID),
+		}
+	}
+	m.db.shards[shardID] = newShardRow(request.ShardInfo.RangeID, blob)
+	return nil
+}
+
+func (m *shardStore) GetShard(request *p.GetShardRequest) (*p.GetShardResponse, error) {
+	m.db.Lock()
+	row, ok := m.db.shards[int(request.ShardID)]
+	m.db.Unlock()
+
+	if !ok {
+		return nil, serviceerror.NewNotFound(fmt.Sprintf("GetShard operation failed. Shard with ID %v not found.", request.ShardID))
+	}
+
+	shardInfo, err := serialization.ShardInfoFromBlob(row.data, row.encoding, m.currentClusterName)
+	if err != nil {
+		return nil, err
+	}
+	return &p.GetShardResponse{ShardInfo: shardInfo}, nil
+}
+
+func (m *shardStore) UpdateShard(request *p.UpdateShardRequest) error {
+	blob, err := serialization.ShardInfoToBlob(request.ShardInfo)
+	if err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("UpdateShard operation failed. Error: %v", err))
+	}
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	shardID := int(request.ShardInfo.ShardID)
+	row, ok := m.db.shards[shardID]
+	if !ok {
+		return serviceerror.NewInternal(fmt.Sprintf("Failed to lock shard with ID %v that does not exist.", shardID))
+	}
+	if row.rangeID != request.PreviousRangeID {
+		return &p.ShardOwnershipLostError{
+			ShardID: shardID,
+			Msg:     fmt.Sprintf("Failed to update shard. Previous range ID: %v; new range ID: %v", request.PreviousRangeID, row.rangeID),
+		}
+	}
+	m.db.shards[shardID] = newShardRow(request.ShardInfo.RangeID, blob)
+	return nil
+}
+
+// checkShardRangeID fails unless the shard is still owned at the given range ID.
+// Callers must hold the database lock.
+func (db *database) checkShardRangeID(shardID int, rangeID int64) error {
+	row, ok := db.shards[shardID]
+	if !ok {
+		return serviceerror.NewInternal(fmt.Sprintf("Failed to lock shard with ID %v that does not exist.", shardID))
+	}
+	if row.rangeID != rangeID {
+		return &p.ShardOwnershipLostError{
+			ShardID: shardID,
+			Msg:     fmt.Sprintf("Failed to lock shard. Previous range ID: %v; new range ID: %v", rangeID, row.rangeID),
+		}
+	}
+	return nil
+}
+
+func newShardRow(rangeID int64, blob serialization.DataBlob) *shardRow {
+	return &shardRow{
+		rangeID: rangeID,
+		blobRow: blobRow{data: blob.Data, encoding: string(blob.Encoding)},
+	}
+}
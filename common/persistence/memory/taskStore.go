@@ -0,0 +1,353 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/persistence/serialization"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+type (
+	taskStore struct {
+		memoryStore
+	}
+
+	taskListKey struct {
+		domainID string
+		name     string
+		taskType int32
+	}
+
+	taskListRow struct {
+		rangeID int64
+		*blobRow
+	}
+
+	taskListPageToken struct {
+		DomainID string
+		Name     string
+		TaskType int32
+	}
+)
+
+// newTaskPersistence creates a new instance of TaskManager
+func newTaskPersistence(db *database, logger log.Logger) persistence.TaskManager {
+	return &taskStore{
+		memoryStore: memoryStore{
+			db:     db,
+			logger: logger,
+		},
+	}
+}
+
+func newTaskListKey(domainID primitives.UUID, name string, taskType int32) taskListKey {
+	return taskListKey{domainID: domainID.String(), name: name, taskType: taskType}
+}
+
+func (k taskListKey) less(other taskListKey) bool {
+	if k.domainID != other.domainID {
+		return k.domainID < other.domainID
+	}
+	if k.name != other.name {
+		return k.name < other.name
+	}
+	return k.taskType < other.taskType
+}
+
+func (m *taskStore) LeaseTaskList(request *persistence.LeaseTaskListRequest) (*persistence.LeaseTaskListResponse, error) {
+	key := newTaskListKey(request.DomainID, request.TaskList, request.TaskType)
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	row, ok := m.db.taskLists[key]
+	if !ok {
+		tlInfo := &persistenceblobs.TaskListInfo{
+			DomainID:    request.DomainID,
+			Name:        request.TaskList,
+			TaskType:    request.TaskType,
+			AckLevel:    0,
+			Kind:        request.TaskListKind,
+			Expiry:      nil,
+			LastUpdated: types.TimestampNow(),
+		}
+		blob, err := serialization.TaskListInfoToBlob(tlInfo)
+		if err != nil {
+			return nil, err
+		}
+		row = &taskListRow{blobRow: &blobRow{data: blob.Data, encoding: blob.Encoding.String()}}
+		m.db.taskLists[key] = row
+	}
+
+	if request.RangeID > 0 && request.RangeID != row.rangeID {
+		return nil, &persistence.ConditionFailedError{
+			Msg: fmt.Sprintf("leaseTaskList:renew failed:taskList:%v, taskListType:%v, haveRangeID:%v, gotRangeID:%v",
+				request.TaskList, request.TaskType, request.RangeID, row.rangeID),
+		}
+	}
+
+	tlInfo, err := serialization.TaskListInfoFromBlob(row.data, row.encoding)
+	if err != nil {
+		return nil, err
+	}
+	tlInfo.LastUpdated = types.TimestampNow()
+	blob, err := serialization.TaskListInfoToBlob(tlInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	m.db.taskLists[key] = &taskListRow{
+		rangeID: row.rangeID + 1,
+		blobRow: &blobRow{data: blob.Data, encoding: string(blob.Encoding)},
+	}
+	return &persistence.LeaseTaskListResponse{TaskListInfo: &persistence.PersistedTaskListInfo{
+		Data:    tlInfo,
+		RangeID: row.rangeID + 1,
+	}}, nil
+}
+
+func (m *taskStore) UpdateTaskList(request *persistence.UpdateTaskListRequest) (*persistence.UpdateTaskListResponse, error) {
+	tl := request.TaskListInfo
+	tl.LastUpdated = types.TimestampNow()
+	key := newTaskListKey(tl.DomainID, tl.Name, tl.TaskType)
+
+	if tl.Kind == persistence.TaskListKindSticky {
+		var err error
+		tl.Expiry, err = types.TimestampProto(stickyTaskListTTL())
+		if err != nil {
+			return nil, err
+		}
+	}
+	blob, err := serialization.TaskListInfoToBlob(tl)
+	if err != nil {
+		return nil, err
+	}
+	newRow := &taskListRow{
+		rangeID: request.RangeID,
+		blobRow: &blobRow{data: blob.Data, encoding: string(blob.Encoding)},
+	}
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	// sticky task lists are created on demand
+	if tl.Kind == persistence.TaskListKindSticky {
+		m.db.taskLists[key] = newRow
+		return &persistence.UpdateTaskListResponse{}, nil
+	}
+
+	if err := m.checkTaskListRangeID(key, request.RangeID); err != nil {
+		return nil, err
+	}
+	m.db.taskLists[key] = newRow
+	return &persistence.UpdateTaskListResponse{}, nil
+}
+
+func (m *taskStore) ListTaskList(request *persistence.ListTaskListRequest) (*persistence.ListTaskListResponse, error) {
+	var after *taskListKey
+	if request.PageToken != nil {
+		var pageToken taskListPageToken
+		if err := json.Unmarshal(request.PageToken, &pageToken); err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("error deserializing page token: %v", err))
+		}
+		after = &taskListKey{domainID: pageToken.DomainID, name: pageToken.Name, taskType: pageToken.TaskType}
+	}
+
+	m.db.Lock()
+	var keys []taskListKey
+	for k := range m.db.taskLists {
+		if after == nil || after.less(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+	if len(keys) > request.PageSize {
+		keys = keys[:request.PageSize]
+	}
+	rows := make([]*taskListRow, len(keys))
+	for i, k := range keys {
+		rows[i] = m.db.taskLists[k]
+	}
+	m.db.Unlock()
+
+	var nextPageToken []byte
+	if len(keys) > 0 && len(keys) >= request.PageSize {
+		lastKey := keys[len(keys)-1]
+		var err error
+		nextPageToken, err = json.Marshal(&taskListPageToken{
+			DomainID: lastKey.domainID,
+			Name:     lastKey.name,
+			TaskType: lastKey.taskType,
+		})
+		if err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("error serializing nextPageToken:%v", err))
+		}
+	}
+
+	resp := &persistence.ListTaskListResponse{
+		Items:         make([]*persistence.PersistedTaskListInfo, len(rows)),
+		NextPageToken: nextPageToken,
+	}
+	for i, row := range rows {
+		info, err := serialization.TaskListInfoFromBlob(row.data, row.encoding)
+		if err != nil {
+			return nil, err
+		}
+		resp.Items[i] = &persistence.PersistedTaskListInfo{
+			Data:    info,
+			RangeID: row.rangeID,
+		}
+	}
+
+	return resp, nil
+}
+
+func (m *taskStore) DeleteTaskList(request *persistence.DeleteTaskListRequest) error {
+	key := newTaskListKey(request.TaskList.DomainID, request.TaskList.Name, request.TaskList.TaskType)
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	row, ok := m.db.taskLists[key]
+	if !ok || row.rangeID != request.RangeID {
+		return serviceerror.NewInternal("delete failed: 0 rows affected instead of 1")
+	}
+	delete(m.db.taskLists, key)
+	return nil
+}
+
+func (m *taskStore) CreateTasks(request *persistence.CreateTasksRequest) (*persistence.CreateTasksResponse, error) {
+	keys := make([]taskListKey, len(request.Tasks))
+	rows := make([]*blobRow, len(request.Tasks))
+	for i, v := range request.Tasks {
+		blob, err := serialization.TaskInfoToBlob(v)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = newTaskListKey(v.Data.DomainID, request.TaskListInfo.Data.Name, request.TaskListInfo.Data.TaskType)
+		rows[i] = &blobRow{data: blob.Data, encoding: string(blob.Encoding)}
+	}
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	taskListInfo := request.TaskListInfo.Data
+	if err := m.checkTaskListRangeID(
+		newTaskListKey(taskListInfo.DomainID, taskListInfo.Name, taskListInfo.TaskType),
+		request.TaskListInfo.RangeID,
+	); err != nil {
+		return nil, err
+	}
+
+	for i, v := range request.Tasks {
+		if _, ok := m.db.tasks[keys[i]][v.TaskID]; ok {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("CreateTasks operation failed. Duplicate task ID %v", v.TaskID))
+		}
+	}
+	for i, v := range request.Tasks {
+		tasks, ok := m.db.tasks[keys[i]]
+		if !ok {
+			tasks = make(map[int64]*blobRow)
+			m.db.tasks[keys[i]] = tasks
+		}
+		tasks[v.TaskID] = rows[i]
+	}
+	return &persistence.CreateTasksResponse{}, nil
+}
+
+func (m *taskStore) GetTasks(request *persistence.GetTasksRequest) (*persistence.GetTasksResponse, error) {
+	maxReadLevel := int64(math.MaxInt64)
+	if request.MaxReadLevel != nil {
+		maxReadLevel = *request.MaxReadLevel
+	}
+
+	m.db.Lock()
+	key := newTaskListKey(request.DomainID, request.TaskList, request.TaskType)
+	rows := selectTasks(m.db.tasks[key], request.ReadLevel, maxReadLevel, request.BatchSize)
+	m.db.Unlock()
+
+	var tasks = make([]*persistenceblobs.AllocatedTaskInfo, len(rows))
+	for i, v := range rows {
+		info, err := serialization.TaskInfoFromBlob(v.data, v.encoding)
+		if err != nil {
+			return nil, err
+		}
+		tasks[i] = info
+	}
+
+	return &persistence.GetTasksResponse{Tasks: tasks}, nil
+}
+
+func (m *taskStore) CompleteTask(request *persistence.CompleteTaskRequest) error {
+	taskList := request.TaskList
+	key := newTaskListKey(taskList.DomainID, taskList.Name, taskList.TaskType)
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	delete(m.db.tasks[key], request.TaskID)
+	return nil
+}
+
+func (m *taskStore) CompleteTasksLessThan(request *persistence.CompleteTasksLessThanRequest) (int, error) {
+	key := newTaskListKey(request.DomainID, request.TaskListName, request.TaskType)
+
+	m.db.Lock()
+	defer m.db.Unlock()
+
+	tasks := m.db.tasks[key]
+	rows := selectTasks(tasks, math.MinInt64, request.TaskID, request.Limit)
+	for _, row := range rows {
+		delete(tasks, row.taskID)
+	}
+	return len(rows), nil
+}
+
+// checkTaskListRangeID fails unless the task list is still owned at the given range ID.
+// Callers must hold the database lock.
+func (m *taskStore) checkTaskListRangeID(key taskListKey, rangeID int64) error {
+	row, ok := m.db.taskLists[key]
+	if !ok {
+		return serviceerror.NewInternal(fmt.Sprintf("Failed to lock task list %v of type %v that does not exist.", key.name, key.taskType))
+	}
+	if row.rangeID != rangeID {
+		return &persistence.ConditionFailedError{
+			Msg: fmt.Sprintf("Task list range ID was %v when it was should have been %v", row.rangeID, rangeID),
+		}
+	}
+	return nil
+}
+
+func stickyTaskListTTL() time.Time {
+	return time.Now().Add(24 * time.Hour)
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log"
+	p "github.com/temporalio/temporal/common/persistence"
+)
+
+type (
+	visibilityStore struct {
+		memoryStore
+	}
+
+	visibilityKey struct {
+		domainID string
+		runID    string
+	}
+
+	// visibilityRow is open while closeStatus is nil
+	visibilityRow struct {
+		workflowID       string
+		runID            string
+		startTime        time.Time
+		executionTime    time.Time
+		workflowTypeName string
+		closeTime        time.Time
+		closeStatus      *enums.WorkflowExecutionCloseStatus
+		historyLength    int64
+		memo             blobRow
+	}
+
+	visibilityPageToken struct {
+		Time  time.Time
+		RunID string
+	}
+)
+
+// newVisibilityPersistence creates an instance of VisibilityStore
+func newVisibilityPersistence(
+	db *database,
+	logger log.Logger,
+) p.VisibilityStore {
+
+	return &visibilityStore{
+		memoryStore: memoryStore{
+			db:     db,
+			logger: logger,
+		},
+	}
+}
+
+func (s *visibilityStore) RecordWorkflowExecutionStarted(request *p.InternalRecordWorkflowExecutionStartedRequest) error {
+	key := visibilityKey{domainID: request.DomainUUID, runID: request.RunID}
+
+	s.db.Lock()
+	defer s.db.Unlock()
+
+	if _, ok := s.db.visibility[key]; ok {
+		// started records never overwrite an existing record, which may already be closed
+		return nil
+	}
+	s.db.visibility[key] = &visibilityRow{
+		workflowID:       request.WorkflowID,
+		runID:            request.RunID,
+		startTime:        time.Unix(0, request.StartTimestamp),
+		executionTime:    time.Unix(0, request.ExecutionTimestamp),
+		workflowTypeName: request.WorkflowTypeName,
+		memo:             *newBlobRow(request.Memo.Data, string(request.Memo.GetEncoding())),
+	}
+	return nil
+}
+
+func (s *visibilityStore) RecordWorkflowExecutionClosed(request *p.InternalRecordWorkflowExecutionClosedRequest) error {
+	key := visibilityKey{domainID: request.DomainUUID, runID: request.RunID}
+	status := request.Status
+
+	s.db.Lock()
+	defer s.db.Unlock()
+
+	s.db.visibility[key] = &visibilityRow{
+		workflowID:       request.WorkflowID,
+		runID:            request.RunID,
+		startTime:        time.Unix(0, request.StartTimestamp),
+		executionTime:    time.Unix(0, request.ExecutionTimestamp),
+		workflowTypeName: request.WorkflowTypeName,
+		closeTime:        time.Unix(0, request.CloseTimestamp),
+		closeStatus:      &status,
+		historyLength:    request.HistoryLength,
+		memo:             *newBlobRow(request.Memo.Data, string(request.Memo.GetEncoding())),
+	}
+	return nil
+}
+
+func (s *visibilityStore) UpsertWorkflowExecution(request *p.InternalUpsertWorkflowExecutionRequest) error {
+	if p.IsNopUpsertWorkflowRequest(request) {
+		return nil
+	}
+	return p.NewOperationNotSupportErrorForVis()
+}
+
+func (s *visibilityStore) ListOpenWorkflowExecutions(request *p.ListWorkflowExecutionsRequest) (*p.InternalListWorkflowExecutionsResponse, error) {
+	return s.listWorkflowExecutions("ListOpenWorkflowExecutions", request, false, func(row *visibilityRow) bool {
+		return true
+	})
+}
+
+func (s *visibilityStore) ListClosedWorkflowExecutions(request *p.ListWorkflowExecutionsRequest) (*p.InternalListWorkflowExecutionsResponse, error) {
+	return s.listWorkflowExecutions("ListClosedWorkflowExecutions", request, true, func(row *visibilityRow) bool {
+		return true
+	})
+}
+
+func (s *visibilityStore) ListOpenWorkflowExecutionsByType(request *p.ListWorkflowExecutionsByTypeRequest) (*p.InternalListWorkflowExecutionsResponse, error) {
+	return s.listWorkflowExecutions("ListOpenWorkflowExecutionsByType", &request.ListWorkflowExecutionsRequest, false, func(row *visibilityRow) bool {
+		return row.workflowTypeName == request.WorkflowTypeName
+	})
+}
+
+func (s *visibilityStore) ListClosedWorkflowExecutionsByType(request *p.ListWorkflowExecutionsByTypeRequest) (*p.InternalListWorkflowExecutionsResponse, error) {
+	return s.listWorkflowExecutions("ListClosedWorkflowExecutionsByType", &request.ListWorkflowExecutionsRequest, true, func(row *visibilityRow) bool {
+		return row.workflowTypeName == request.WorkflowTypeName
+	})
+}
+
+func (s *visibilityStore) ListOpenWorkflowExecutionsByWorkflowID(request *p.ListWorkflowExecutionsByWorkflowIDRequest) (*p.InternalListWorkflowExecutionsResponse, error) {
+	return s.listWorkflowExecutions("ListOpenWorkflowExecutionsByWorkflowID", &request.ListWorkflowExecutionsRequest, false, func(row *visibilityRow) bool {
+		return row.workflowID == request.WorkflowID
+	})
+}
+
+func (s *visibilityStore) ListClosedWorkflowExecutionsByWorkflowID(request *p.ListWorkflowExecutionsByWorkflowIDRequest) (*p.InternalListWorkflowExecutionsResponse, error) {
+	return s.listWorkflowExecutions("ListClosedWorkflowExecutionsByWorkflowID", &request.ListWorkflowExecutionsRequest, true, func(row *visibilityRow) bool {
+		return row.workflowID == request.WorkflowID
+	})
+}
+
+func (s *visibilityStore) ListClosedWorkflowExecutionsByStatus(request *p.ListClosedWorkflowExecutionsByStatusRequest) (*p.InternalListWorkflowExecutionsResponse, error) {
+	return s.listWorkflowExecutions("ListClosedWorkflowExecutionsByStatus", &request.ListWorkflowExecutionsRequest, true, func(row *visibilityRow) bool {
+		return *row.closeStatus == request.Status
+	})
+}
+
+func (s *visibilityStore) GetClosedWorkflowExecution(request *p.GetClosedWorkflowExecutionRequest) (*p.InternalGetClosedWorkflowExecutionResponse, error) {
+	execution := request.Execution
+
+	s.db.Lock()
+	row, ok := s.db.visibility[visibilityKey{domainID: request.DomainUUID, runID: execution.GetRunId()}]
+	s.db.Unlock()
+
+	if !ok || row.closeStatus == nil {
+		return nil, serviceerror.NewNotFound(fmt.Sprintf("Workflow execution not found.  WorkflowId: %v, RunId: %v",
+			execution.GetWorkflowId(), execution.GetRunId()))
+	}
+	info := s.rowToInfo(row)
+	info.WorkflowID = execution.GetWorkflowId()
+	return &p.InternalGetClosedWorkflowExecutionResponse{Execution: info}, nil
+}
+
+func (s *visibilityStore) DeleteWorkflowExecution(request *p.VisibilityDeleteWorkflowExecutionRequest) error {
+	s.db.Lock()
+	defer s.db.Unlock()
+
+	delete(s.db.visibility, visibilityKey{domainID: request.DomainID, runID: request.RunID})
+	return nil
+}
+
+func (s *visibilityStore) ListWorkflowExecutions(request *p.ListWorkflowExecutionsRequestV2) (*p.InternalListWorkflowExecutionsResponse, error) {
+	return nil, p.NewOperationNotSupportErrorForVis()
+}
+
+func (s *visibilityStore) ScanWorkflowExecutions(request *p.ListWorkflowExecutionsRequestV2) (*p.InternalListWorkflowExecutionsResponse, error) {
+	return nil, p.NewOperationNotSupportErrorForVis()
+}
+
+func (s *visibilityStore) CountWorkflowExecutions(request *p.CountWorkflowExecutionsRequest) (*p.CountWorkflowExecutionsResponse, error) {
+	return nil, p.NewOperationNotSupportErrorForVis()
+}
+
+func (s *visibilityStore) rowToInfo(row *visibilityRow) *p.VisibilityWorkflowExecutionInfo {
+	executionTime := row.executionTime
+	if executionTime.UnixNano() == 0 {
+		executionTime = row.startTime
+	}
+	info := &p.VisibilityWorkflowExecutionInfo{
+		WorkflowID:    row.workflowID,
+		RunID:         row.runID,
+		TypeName:      row.workflowTypeName,
+		StartTime:     row.startTime,
+		ExecutionTime: executionTime,
+		Memo:          p.NewDataBlob(copyBytes(row.memo.data), common.EncodingType(row.memo.encoding)),
+	}
+	if row.closeStatus != nil {
+		status := *row.closeStatus
+		info.Status = &status
+		info.CloseTime = row.closeTime
+		info.HistoryLength = row.historyLength
+	}
+	return info
+}
+
+// listWorkflowExecutions returns the records of the domain started within the
+// requested time range that also satisfy filter, newest first. Records with the
+// same start time are ordered by run ID, which the page token relies on.
+func (s *visibilityStore) listWorkflowExecutions(
+	opName string,
+	request *p.ListWorkflowExecutionsRequest,
+	closed bool,
+	filter func(row *visibilityRow) bool,
+) (*p.InternalListWorkflowExecutionsResponse, error) {
+
+	readLevel := &visibilityPageToken{Time: time.Unix(0, request.LatestStartTime), RunID: ""}
+	if len(request.NextPageToken) > 0 {
+		if err := json.Unmarshal(request.NextPageToken, readLevel); err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("%v operation failed. Invalid next page token: %v", opName, err))
+		}
+	}
+	minStartTime := time.Unix(0, request.EarliestStartTime)
+
+	s.db.Lock()
+	var rows []*visibilityRow
+	for key, row := range s.db.visibility {
+		switch {
+		case key.domainID != request.DomainUUID:
+		case (row.closeStatus != nil) != closed:
+		case row.startTime.Before(minStartTime) || row.startTime.After(readLevel.Time):
+		case !(row.runID > readLevel.RunID || row.startTime.Before(readLevel.Time)):
+		case !filter(row):
+		default:
+			rows = append(rows, row)
+		}
+	}
+	s.db.Unlock()
+
+	if len(rows) == 0 {
+		return &p.InternalListWorkflowExecutionsResponse{}, nil
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].startTime.Equal(rows[j].startTime) {
+			return rows[i].startTime.After(rows[j].startTime)
+		}
+		return rows[i].runID < rows[j].runID
+	})
+	if len(rows) > request.PageSize {
+		rows = rows[:request.PageSize]
+	}
+
+	infos := make([]*p.VisibilityWorkflowExecutionInfo, len(rows))
+	for i, row := range rows {
+		infos[i] = s.rowToInfo(row)
+	}
+
+	var nextPageToken []byte
+	lastRow := rows[len(rows)-1]
+	if lastRow.startTime.After(minStartTime) {
+		var err error
+		nextPageToken, err = json.Marshal(&visibilityPageToken{
+			Time:  lastRow.startTime,
+			RunID: lastRow.runID,
+		})
+		if err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("%v operation failed. Failed to serialize next page token: %v", opName, err))
+		}
+	}
+	return &p.InternalListWorkflowExecutionsResponse{
+		Executions:    infos,
+		NextPageToken: nextPageToken,
+	}, nil
+}
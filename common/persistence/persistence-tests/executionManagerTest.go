@@ -1389,6 +1389,9 @@ func (s *ExecutionManagerSuite) TestUpdateWorkflow() {
 	s.Empty(info0.ClientLibraryVersion)
 	s.Empty(info0.ClientFeatureVersion)
 	s.Empty(info0.ClientImpl)
+	s.Empty(info0.WorkerBuildID)
+	s.Equal(int32(0), info0.TaskPriority)
+	s.Empty(info0.TaskFairnessKey)
 	s.Equal(int32(0), info0.SignalCount)
 	s.True(reflect.DeepEqual(info0.AutoResetPoints, &commonproto.ResetPoints{}))
 	s.True(len(info0.SearchAttributes) == 0)
@@ -1412,6 +1415,9 @@ func (s *ExecutionManagerSuite) TestUpdateWorkflow() {
 	updatedInfo.ClientLibraryVersion = "random client library version"
 	updatedInfo.ClientFeatureVersion = "random client feature version"
 	updatedInfo.ClientImpl = "random client impl"
+	updatedInfo.WorkerBuildID = "random worker build id"
+	updatedInfo.TaskPriority = 3
+	updatedInfo.TaskFairnessKey = "random fairness key"
 	updatedInfo.SignalCount = 9
 	updatedInfo.InitialInterval = math.MaxInt32
 	updatedInfo.BackoffCoefficient = 4.45
@@ -1462,6 +1468,9 @@ func (s *ExecutionManagerSuite) TestUpdateWorkflow() {
 	s.Equal(updatedInfo.ClientLibraryVersion, info1.ClientLibraryVersion)
 	s.Equal(updatedInfo.ClientFeatureVersion, info1.ClientFeatureVersion)
 	s.Equal(updatedInfo.ClientImpl, info1.ClientImpl)
+	s.Equal(updatedInfo.WorkerBuildID, info1.WorkerBuildID)
+	s.Equal(updatedInfo.TaskPriority, info1.TaskPriority)
+	s.Equal(updatedInfo.TaskFairnessKey, info1.TaskFairnessKey)
 	s.Equal(updatedInfo.SignalCount, info1.SignalCount)
 	s.EqualValues(updatedStats.HistorySize, state1.ExecutionStats.HistorySize)
 	s.Equal(updatedInfo.InitialInterval, info1.InitialInterval)
@@ -2630,6 +2639,8 @@ func (s *ExecutionManagerSuite) TestWorkflowMutableStateActivities() {
 		LastFailureReason:        "some random error",
 		LastWorkerIdentity:       uuid.New(),
 		LastFailureDetails:       []byte(uuid.New()),
+		Priority:                 2,
+		FairnessKey:              "tenant",
 	}}
 	err2 := s.UpdateWorkflowExecution(updatedInfo, updatedStats, nil, []int64{int64(4)}, nil, int64(3), nil, activityInfos, nil, nil, nil)
 	s.NoError(err2)
@@ -2675,6 +2686,8 @@ func (s *ExecutionManagerSuite) TestWorkflowMutableStateActivities() {
 	s.Equal(activityInfos[0].LastFailureReason, ai.LastFailureReason)
 	s.Equal(activityInfos[0].LastWorkerIdentity, ai.LastWorkerIdentity)
 	s.Equal(activityInfos[0].LastFailureDetails, ai.LastFailureDetails)
+	s.Equal(activityInfos[0].Priority, ai.Priority)
+	s.Equal(activityInfos[0].FairnessKey, ai.FairnessKey)
 
 	err2 = s.UpdateWorkflowExecution(updatedInfo, updatedStats, nil, nil, nil, int64(5), nil, nil, []int64{1}, nil, nil)
 	s.NoError(err2)
@@ -46,6 +46,7 @@ import (
 	p "github.com/temporalio/temporal/common/persistence"
 	"github.com/temporalio/temporal/common/persistence/cassandra"
 	"github.com/temporalio/temporal/common/persistence/client"
+	"github.com/temporalio/temporal/common/persistence/memory"
 	"github.com/temporalio/temporal/common/persistence/sql"
 	"github.com/temporalio/temporal/common/primitives"
 	"github.com/temporalio/temporal/common/primitives/timestamp"
@@ -60,15 +61,16 @@ type (
 
 	// TestBaseOptions options to configure workflow test base.
 	TestBaseOptions struct {
-		SQLDBPluginName string
-		DBName          string
-		DBUsername      string
-		DBPassword      string
-		DBHost          string
-		DBPort          int              `yaml:"-"`
-		StoreType       string           `yaml:"-"`
-		SchemaDir       string           `yaml:"-"`
-		ClusterMetadata cluster.Metadata `yaml:"-"`
+		SQLDBPluginName   string
+		DBName            string
+		DBUsername        string
+		DBPassword        string
+		DBHost            string
+		DBPort            int               `yaml:"-"`
+		ConnectAttributes map[string]string `yaml:"-"`
+		StoreType         string            `yaml:"-"`
+		SchemaDir         string            `yaml:"-"`
+		ClusterMetadata   cluster.Metadata  `yaml:"-"`
 	}
 
 	// TestBase wraps the base setup needed to create workflows over persistence layer.
@@ -125,17 +127,28 @@ func NewTestBaseWithSQL(options *TestBaseOptions) TestBase {
 	if options.DBName == "" {
 		options.DBName = "test_" + GenerateRandomDBName(3)
 	}
-	testCluster := sql.NewTestCluster(options.SQLDBPluginName, options.DBName, options.DBUsername, options.DBPassword, options.DBHost, options.DBPort, options.SchemaDir)
+	testCluster := sql.NewTestCluster(options.SQLDBPluginName, options.DBName, options.DBUsername, options.DBPassword, options.DBHost, options.DBPort, options.ConnectAttributes, options.SchemaDir)
 	return newTestBase(options, testCluster)
 }
 
-// NewTestBase returns a persistence test base backed by either cassandra or sql
+// NewTestBaseWithMemory returns a new persistence test base backed by process memory
+func NewTestBaseWithMemory(options *TestBaseOptions) TestBase {
+	if options.DBName == "" {
+		options.DBName = "test_" + GenerateRandomDBName(3)
+	}
+	testCluster := memory.NewTestCluster(options.DBName)
+	return newTestBase(options, testCluster)
+}
+
+// NewTestBase returns a persistence test base backed by cassandra, sql or memory
 func NewTestBase(options *TestBaseOptions) TestBase {
 	switch options.StoreType {
 	case config.StoreTypeSQL:
 		return NewTestBaseWithSQL(options)
 	case config.StoreTypeCassandra:
 		return NewTestBaseWithCassandra(options)
+	case config.StoreTypeMemory:
+		return NewTestBaseWithMemory(options)
 	default:
 		panic("invalid storeType " + options.StoreType)
 	}
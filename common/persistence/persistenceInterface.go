@@ -244,6 +244,9 @@ type (
 		ClientLibraryVersion               string
 		ClientFeatureVersion               string
 		ClientImpl                         string
+		WorkerBuildID                      string
+		TaskPriority                       int32
+		TaskFairnessKey                    string
 		AutoResetPoints                    *serialization.DataBlob
 		// for retry
 		Attempt            int32
@@ -317,6 +320,8 @@ type (
 		LastFailureReason  string
 		LastWorkerIdentity string
 		LastFailureDetails []byte
+		Priority           int32
+		FairnessKey        string
 		// Not written to database - This is used only for deduping heartbeat timer creation
 		LastHeartbeatTimeoutVisibility int64
 	}
@@ -739,7 +744,10 @@ func NewDataBlobFromProto(blob *commonproto.DataBlob) *serialization.DataBlob {
 			Data:     blob.Data,
 		}
 	case enums.EncodingTypeProto3:
-		panic("EncodingTypeProto is not supported")
+		return &serialization.DataBlob{
+			Encoding: common.EncodingTypeProto3,
+			Data:     blob.Data,
+		}
 	default:
 		panic(fmt.Sprintf("NewDataBlobFromThrift seeing unsupported enconding type: %v", blob.GetEncodingType()))
 	}
@@ -780,6 +788,9 @@ func InternalWorkflowExecutionInfoToProto(executionInfo *InternalWorkflowExecuti
 		ClientLibraryVersion:                    executionInfo.ClientLibraryVersion,
 		ClientFeatureVersion:                    executionInfo.ClientFeatureVersion,
 		ClientImpl:                              executionInfo.ClientImpl,
+		WorkerBuildId:                           executionInfo.WorkerBuildID,
+		TaskPriority:                            executionInfo.TaskPriority,
+		TaskFairnessKey:                         executionInfo.TaskFairnessKey,
 		SignalCount:                             int64(executionInfo.SignalCount),
 		HistorySize:                             executionInfo.HistorySize,
 		CronSchedule:                            executionInfo.CronSchedule,
@@ -873,6 +884,9 @@ func ProtoWorkflowExecutionToPartialInternalExecution(info *persistenceblobs.Wor
 		ClientLibraryVersion:               info.GetClientLibraryVersion(),
 		ClientFeatureVersion:               info.GetClientFeatureVersion(),
 		ClientImpl:                         info.GetClientImpl(),
+		WorkerBuildID:                      info.GetWorkerBuildId(),
+		TaskPriority:                       info.GetTaskPriority(),
+		TaskFairnessKey:                    info.GetTaskFairnessKey(),
 		SignalCount:                        int32(info.GetSignalCount()),
 		HistorySize:                        info.GetHistorySize(),
 		CronSchedule:                       info.GetCronSchedule(),
@@ -957,6 +971,8 @@ func ProtoActivityInfoToInternalActivityInfo(decoded *persistenceblobs.ActivityI
 		LastFailureReason:        decoded.GetRetryLastFailureReason(),
 		LastWorkerIdentity:       decoded.GetRetryLastWorkerIdentity(),
 		LastFailureDetails:       decoded.GetRetryLastFailureDetails(),
+		Priority:                 decoded.GetPriority(),
+		FairnessKey:              decoded.GetFairnessKey(),
 	}
 	if decoded.GetRetryExpirationTimeNanos() != 0 {
 		info.ExpirationTime = time.Unix(0, decoded.GetRetryExpirationTimeNanos())
@@ -1006,6 +1022,8 @@ func (v *InternalActivityInfo) ToProto() *persistenceblobs.ActivityInfo {
 		RetryLastFailureReason:        v.LastFailureReason,
 		RetryLastWorkerIdentity:       v.LastWorkerIdentity,
 		RetryLastFailureDetails:       v.LastFailureDetails,
+		Priority:                      v.Priority,
+		FairnessKey:                   v.FairnessKey,
 	}
 	if !v.ExpirationTime.IsZero() {
 		info.RetryExpirationTimeNanos = v.ExpirationTime.UnixNano()
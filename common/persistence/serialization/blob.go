@@ -280,6 +280,11 @@ func (d *DataBlob) ToProto() *commonproto.DataBlob {
 			EncodingType: enums.EncodingTypeThriftRW,
 			Data:         d.Data,
 		}
+	case common.EncodingTypeProto3:
+		return &commonproto.DataBlob{
+			EncodingType: enums.EncodingTypeProto3,
+			Data:         d.Data,
+		}
 	default:
 		panic(fmt.Sprintf("DataBlob seeing unsupported enconding type: %v", d.Encoding))
 	}
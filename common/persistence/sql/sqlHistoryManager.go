@@ -366,7 +366,11 @@ func (m *sqlHistoryV2Manager) DeleteHistoryBranch(
 
 	// validBRsMaxEndNode is to for each branch range that is being used, we want to know what is the max nodeID referred by other valid branch
 	validBRsMaxEndNode := map[string]int64{}
+	validBranches := map[string]struct{}{}
 	for _, b := range rsp.Branches {
+		if string(b.BranchID) != string(branch.BranchID) {
+			validBranches[string(b.BranchID)] = struct{}{}
+		}
 		for _, br := range b.Ancestors {
 			curr, ok := validBRsMaxEndNode[string(br.BranchID)]
 			if !ok || curr < br.EndNodeID {
@@ -391,6 +395,11 @@ func (m *sqlHistoryV2Manager) DeleteHistoryBranch(
 		// for each branch range to delete, we iterate from bottom to up, and delete up to the point according to validBRsEndNode
 		for i := len(brsToDelete) - 1; i >= 0; i-- {
 			br := brsToDelete[i]
+			if _, ok := validBranches[string(br.BranchID)]; ok {
+				// the ancestor is still a branch of the tree, all of its nodes are in use
+				// and it can be forked again from any of them
+				break
+			}
 			maxReferredEndNodeID, ok := validBRsMaxEndNode[string(br.BranchID)]
 			nodeFilter := &sqlplugin.HistoryNodeFilter{
 				TreeID:   treeID,
@@ -42,7 +42,7 @@ type TestCluster struct {
 }
 
 // NewTestCluster returns a new SQL test cluster
-func NewTestCluster(pluginName, dbName, username, password, host string, port int, schemaDir string) *TestCluster {
+func NewTestCluster(pluginName, dbName, username, password, host string, port int, connectAttributes map[string]string, schemaDir string) *TestCluster {
 	var result TestCluster
 	result.dbName = dbName
 	if port == 0 {
@@ -53,13 +53,14 @@ func NewTestCluster(pluginName, dbName, username, password, host string, port in
 	}
 	result.schemaDir = schemaDir
 	result.cfg = config.SQL{
-		User:            username,
-		Password:        password,
-		ConnectAddr:     fmt.Sprintf("%v:%v", host, port),
-		ConnectProtocol: "tcp",
-		PluginName:      pluginName,
-		DatabaseName:    dbName,
-		NumShards:       4,
+		User:              username,
+		Password:          password,
+		ConnectAddr:       fmt.Sprintf("%v:%v", host, port),
+		ConnectProtocol:   "tcp",
+		PluginName:        pluginName,
+		DatabaseName:      dbName,
+		NumShards:         4,
+		ConnectAttributes: connectAttributes,
 	}
 	return &result
 }
@@ -0,0 +1,147 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlite
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	readSchemaVersionQuery = `SELECT curr_version from schema_version where db_name=?`
+
+	writeSchemaVersionQuery = `REPLACE into schema_version(db_name, creation_time, curr_version, min_compatible_version) VALUES (?,?,?,?)`
+
+	writeSchemaUpdateHistoryQuery = `INSERT into schema_update_history(year, month, update_time, old_version, new_version, manifest_md5, description) VALUES(?,?,?,?,?,?,?)`
+
+	createSchemaVersionTableQuery = `CREATE TABLE schema_version(db_name VARCHAR(255) not null PRIMARY KEY, ` +
+		`creation_time TIMESTAMP, ` +
+		`curr_version VARCHAR(64), ` +
+		`min_compatible_version VARCHAR(64));`
+
+	createSchemaUpdateHistoryTableQuery = `CREATE TABLE schema_update_history(` +
+		`year INTEGER not null, ` +
+		`month INTEGER not null, ` +
+		`update_time TIMESTAMP not null, ` +
+		`description VARCHAR(255), ` +
+		`manifest_md5 VARCHAR(64), ` +
+		`new_version VARCHAR(64), ` +
+		`old_version VARCHAR(64), ` +
+		`PRIMARY KEY (year, month, update_time));`
+
+	// sqlite_sequence backs AUTOINCREMENT columns and is owned by sqlite itself
+	listTablesQuery = `SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%'`
+
+	dropTableQuery = "DROP TABLE %v"
+)
+
+// databaseFileSuffixes are the files sqlite keeps next to a database file
+var databaseFileSuffixes = []string{"-journal", "-wal", "-shm"}
+
+// CreateSchemaVersionTables sets up the schema version tables
+func (mdb *db) CreateSchemaVersionTables() error {
+	if err := mdb.Exec(createSchemaVersionTableQuery); err != nil {
+		return err
+	}
+	return mdb.Exec(createSchemaUpdateHistoryTableQuery)
+}
+
+// ReadSchemaVersion returns the current schema version for the keyspace
+func (mdb *db) ReadSchemaVersion(database string) (string, error) {
+	var version string
+	err := mdb.db.Get(&version, readSchemaVersionQuery, database)
+	return version, err
+}
+
+// UpdateSchemaVersion updates the schema version for the keyspace
+func (mdb *db) UpdateSchemaVersion(database string, newVersion string, minCompatibleVersion string) error {
+	return mdb.Exec(writeSchemaVersionQuery, database, mdb.converter.ToSQLiteDateTime(time.Now()), newVersion, minCompatibleVersion)
+}
+
+// WriteSchemaUpdateLog adds an entry to the schema update history table
+func (mdb *db) WriteSchemaUpdateLog(oldVersion string, newVersion string, manifestMD5 string, desc string) error {
+	now := time.Now().UTC()
+	return mdb.Exec(writeSchemaUpdateHistoryQuery, now.Year(), int(now.Month()), now, oldVersion, newVersion, manifestMD5, desc)
+}
+
+// Exec executes a sql statement
+func (mdb *db) Exec(stmt string, args ...interface{}) error {
+	_, err := mdb.db.Exec(stmt, args...)
+	return err
+}
+
+// ListTables returns a list of tables in this database, the connection
+// is bound to a single database so the name is not needed
+func (mdb *db) ListTables(database string) ([]string, error) {
+	var tables []string
+	err := mdb.db.Select(&tables, listTablesQuery)
+	return tables, err
+}
+
+// DropTable drops a given table from the database
+func (mdb *db) DropTable(name string) error {
+	return mdb.Exec(fmt.Sprintf(dropTableQuery, name))
+}
+
+// DropAllTables drops all tables from this database
+func (mdb *db) DropAllTables(database string) error {
+	tables, err := mdb.ListTables(database)
+	if err != nil {
+		return err
+	}
+	for _, tab := range tables {
+		if err := mdb.DropTable(tab); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateDatabase creates a database if it doesn't exist. In memory databases stay
+// alive until they are dropped, otherwise the name is the path of the database file.
+func (mdb *db) CreateDatabase(name string) error {
+	if isInMemory(mdb.cfg) {
+		return connections.pin(connKey(mdb.cfg, name), buildDSN(mdb.cfg, name))
+	}
+	// sqlite treats an empty file as an empty database
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// DropDatabase drops a database
+func (mdb *db) DropDatabase(name string) error {
+	if isInMemory(mdb.cfg) {
+		return connections.unpin(connKey(mdb.cfg, name))
+	}
+	if err := os.Remove(name); err != nil {
+		return err
+	}
+	for _, suffix := range databaseFileSuffixes {
+		if err := os.Remove(name + suffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
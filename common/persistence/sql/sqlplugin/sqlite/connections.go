@@ -0,0 +1,125 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlite
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	// connRegistry hands out a single shared connection per SQLite database. The history,
+	// matching and visibility stores of a single binary open the same database
+	// independently, and SQLite serializes writers per database, so sharing one
+	// connection keeps them from failing each other with SQLITE_BUSY. The registry also
+	// keeps named in-memory databases alive between CreateDatabase and DropDatabase.
+	connRegistry struct {
+		sync.Mutex
+		conns map[string]*sharedConn
+	}
+
+	sharedConn struct {
+		db       *sqlx.DB
+		refCount int
+		// pinned is set for in-memory databases which must outlive their users
+		pinned bool
+	}
+)
+
+var connections = &connRegistry{conns: make(map[string]*sharedConn)}
+
+// acquire returns the connection to the database identified by key, opening it on first use
+func (r *connRegistry) acquire(key string, dsn string) (*sqlx.DB, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	conn, err := r.getOrOpenLocked(key, dsn)
+	if err != nil {
+		return nil, err
+	}
+	conn.refCount++
+	return conn.db, nil
+}
+
+// release gives back a connection obtained through acquire
+func (r *connRegistry) release(key string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	conn, ok := r.conns[key]
+	if !ok {
+		return fmt.Errorf("sqlite connection %v is not open", key)
+	}
+	conn.refCount--
+	return r.closeIfUnusedLocked(key, conn)
+}
+
+// pin opens the database identified by key and keeps it open until unpin is called
+func (r *connRegistry) pin(key string, dsn string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if conn, ok := r.conns[key]; ok && conn.pinned {
+		return fmt.Errorf("database %v already exists", key)
+	}
+	conn, err := r.getOrOpenLocked(key, dsn)
+	if err != nil {
+		return err
+	}
+	conn.pinned = true
+	return nil
+}
+
+// unpin undoes pin, the database is closed as soon as nobody else is using it
+func (r *connRegistry) unpin(key string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	conn, ok := r.conns[key]
+	if !ok || !conn.pinned {
+		return fmt.Errorf("database %v does not exist", key)
+	}
+	conn.pinned = false
+	return r.closeIfUnusedLocked(key, conn)
+}
+
+func (r *connRegistry) getOrOpenLocked(key string, dsn string) (*sharedConn, error) {
+	if conn, ok := r.conns[key]; ok {
+		return conn, nil
+	}
+	db, err := openDB(dsn)
+	if err != nil {
+		return nil, err
+	}
+	conn := &sharedConn{db: db}
+	r.conns[key] = conn
+	return conn, nil
+}
+
+func (r *connRegistry) closeIfUnusedLocked(key string, conn *sharedConn) error {
+	if conn.refCount > 0 || conn.pinned {
+		return nil
+	}
+	delete(r.conns, key)
+	return conn.db.Close()
+}
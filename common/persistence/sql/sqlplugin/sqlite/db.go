@@ -0,0 +1,141 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/temporalio/temporal/common/persistence/sql/sqlplugin"
+	"github.com/temporalio/temporal/common/service/config"
+)
+
+// db represents a logical connection to sqlite database
+type db struct {
+	db        *sqlx.DB
+	tx        *sqlx.Tx
+	conn      sqlplugin.Conn
+	converter DataConverter
+	cfg       *config.SQL
+	release   func() error
+}
+
+// maxRowsPerInsert bounds multi row inserts, the sqlite bundled with the driver allows at most
+// 999 bound parameters per statement and the widest batched row has 9 columns
+const maxRowsPerInsert = 64
+
+// batchResult is the sql.Result of an insert which was split into several statements
+type batchResult struct {
+	rowsAffected int64
+}
+
+var errBatchLastInsertID = errors.New("LastInsertId is not supported for batched inserts")
+
+var _ sqlplugin.AdminDB = (*db)(nil)
+var _ sqlplugin.DB = (*db)(nil)
+var _ sqlplugin.Tx = (*db)(nil)
+
+// IsDupEntryError returns true if the error is a primary key or unique constraint violation
+// i.e. the row already exists, so we don't do the insert and return a ConditionalUpdate error.
+func (mdb *db) IsDupEntryError(err error) bool {
+	sqlErr, ok := err.(sqlite3.Error)
+	return ok && (sqlErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey ||
+		sqlErr.ExtendedCode == sqlite3.ErrConstraintUnique)
+}
+
+// newDB returns an instance of DB, which is a logical
+// connection to the underlying sqlite database
+func newDB(xdb *sqlx.DB, tx *sqlx.Tx, cfg *config.SQL, release func() error) *db {
+	mdb := &db{db: xdb, tx: tx, cfg: cfg, release: release}
+	mdb.conn = xdb
+	if tx != nil {
+		mdb.conn = tx
+	}
+	mdb.converter = &converter{}
+	return mdb
+}
+
+// BeginTx starts a new transaction and returns a reference to the Tx object
+func (mdb *db) BeginTx() (sqlplugin.Tx, error) {
+	xtx, err := mdb.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	return newDB(mdb.db, xtx, mdb.cfg, nil), nil
+}
+
+// Commit commits a previously started transaction
+func (mdb *db) Commit() error {
+	return mdb.tx.Commit()
+}
+
+// Rollback triggers rollback of a previously started transaction
+func (mdb *db) Rollback() error {
+	return mdb.tx.Rollback()
+}
+
+// Close gives the shared connection to the sqlite db back, the connection itself
+// is closed once every user of the database has closed it
+func (mdb *db) Close() error {
+	return mdb.release()
+}
+
+// PluginName returns the name of the sqlite plugin
+func (mdb *db) PluginName() string {
+	return PluginName
+}
+
+// namedExecBatch runs a multi row named insert, splitting rows into chunks of maxRowsPerInsert
+func (mdb *db) namedExecBatch(query string, rows interface{}) (sql.Result, error) {
+	v := reflect.ValueOf(rows)
+	if v.Len() <= maxRowsPerInsert {
+		return mdb.conn.NamedExec(query, rows)
+	}
+	var result batchResult
+	for start := 0; start < v.Len(); start += maxRowsPerInsert {
+		end := start + maxRowsPerInsert
+		if end > v.Len() {
+			end = v.Len()
+		}
+		res, err := mdb.conn.NamedExec(query, v.Slice(start, end).Interface())
+		if err != nil {
+			return nil, err
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		result.rowsAffected += rowsAffected
+	}
+	return &result, nil
+}
+
+func (r *batchResult) LastInsertId() (int64, error) {
+	return 0, errBatchLastInsertID
+}
+
+func (r *batchResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
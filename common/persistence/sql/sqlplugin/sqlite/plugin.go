@@ -0,0 +1,173 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlite
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/iancoleman/strcase"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3" // needed to register the sqlite3 driver
+
+	pt "github.com/temporalio/temporal/common/persistence/persistence-tests"
+	"github.com/temporalio/temporal/common/persistence/sql"
+	"github.com/temporalio/temporal/common/persistence/sql/sqlplugin"
+	"github.com/temporalio/temporal/common/service/config"
+)
+
+const (
+	// PluginName is the name of the plugin
+	PluginName = "sqlite"
+	driverName = "sqlite3"
+
+	// modeAttrName is the connect attribute which selects where the database lives,
+	// a database file on disk (default) or a named database in process memory
+	modeAttrName = "mode"
+	// modeMemory keeps the database in memory, it lives from CreateDatabase until DropDatabase
+	modeMemory = "memory"
+
+	busyTimeoutAttrName  = "_busy_timeout"
+	defaultBusyTimeoutMS = "10000"
+	txLockAttrName       = "_txlock"
+	// transactions take the write lock up front, this stands in for the row locks
+	// taken by the other plugins
+	defaultTxLock = "immediate"
+)
+
+type plugin struct{}
+
+var _ sqlplugin.Plugin = (*plugin)(nil)
+
+func init() {
+	sql.RegisterPlugin(PluginName, &plugin{})
+}
+
+// CreateDB initialize the db object
+func (p *plugin) CreateDB(cfg *config.SQL) (sqlplugin.DB, error) {
+	conn, release, err := p.createDBConnection(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db := newDB(conn, nil, cfg, release)
+	return db, nil
+}
+
+// CreateAdminDB initialize the db object
+func (p *plugin) CreateAdminDB(cfg *config.SQL) (sqlplugin.AdminDB, error) {
+	conn, release, err := p.createDBConnection(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db := newDB(conn, nil, cfg, release)
+	return db, nil
+}
+
+// createDBConnection returns a reference to a logical connection to the SQLite database
+// named by cfg.DatabaseName, along with the func that gives it back once the caller is done.
+// Connections are shared by every caller in the process, see connRegistry. An empty
+// database name yields a private scratch connection, which is what admin callers use
+// to create and drop databases.
+func (p *plugin) createDBConnection(cfg *config.SQL) (*sqlx.DB, func() error, error) {
+	if cfg.DatabaseName == "" {
+		db, err := openDB(buildDSN(cfg, ":memory:"))
+		if err != nil {
+			return nil, nil, err
+		}
+		return db, db.Close, nil
+	}
+
+	key := connKey(cfg, cfg.DatabaseName)
+	db, err := connections.acquire(key, buildDSN(cfg, cfg.DatabaseName))
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, func() error { return connections.release(key) }, nil
+}
+
+func openDB(dsn string) (*sqlx.DB, error) {
+	db, err := sqlx.Connect(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer per database, so every caller goes through one connection
+	// instead of racing each other into SQLITE_BUSY. This also keeps in-memory databases,
+	// which are private to the connection that opened them, consistent across callers.
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+
+	// Maps struct names in CamelCase to snake without need for db struct tags.
+	db.MapperFunc(strcase.ToSnake)
+	return db, nil
+}
+
+func isInMemory(cfg *config.SQL) bool {
+	return cfg.ConnectAttributes[modeAttrName] == modeMemory
+}
+
+func connKey(cfg *config.SQL, database string) string {
+	if isInMemory(cfg) {
+		return modeMemory + ":" + database
+	}
+	return database
+}
+
+func buildDSN(cfg *config.SQL, database string) string {
+	attrs := url.Values{}
+	for k, v := range cfg.ConnectAttributes {
+		if k == modeAttrName {
+			continue
+		}
+		attrs.Set(k, v)
+	}
+	if attrs.Get(busyTimeoutAttrName) == "" {
+		attrs.Set(busyTimeoutAttrName, defaultBusyTimeoutMS)
+	}
+	if attrs.Get(txLockAttrName) == "" {
+		attrs.Set(txLockAttrName, defaultTxLock)
+	}
+
+	switch {
+	case database == ":memory:":
+	case isInMemory(cfg):
+		attrs.Set("mode", "memory")
+		attrs.Set("cache", "shared")
+	default:
+		// the database file must have been created through CreateDatabase
+		attrs.Set("mode", "rw")
+	}
+	return fmt.Sprintf("file:%s?%s", database, attrs.Encode())
+}
+
+const (
+	testSchemaDir = "schema/sqlite"
+)
+
+// GetTestClusterOption return test options
+func GetTestClusterOption() *pt.TestBaseOptions {
+	return &pt.TestBaseOptions{
+		SQLDBPluginName:   PluginName,
+		SchemaDir:         testSchemaDir,
+		StoreType:         config.StoreTypeSQL,
+		ConnectAttributes: map[string]string{modeAttrName: modeMemory},
+	}
+}
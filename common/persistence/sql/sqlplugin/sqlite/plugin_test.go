@@ -0,0 +1,187 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlite
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/common/service/config"
+)
+
+type (
+	pluginSuite struct {
+		*require.Assertions
+		suite.Suite
+		plugin *plugin
+	}
+)
+
+const testTableQuery = `CREATE TABLE test_table (id INTEGER NOT NULL, PRIMARY KEY (id))`
+
+func TestPluginSuite(t *testing.T) {
+	suite.Run(t, new(pluginSuite))
+}
+
+func (s *pluginSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.plugin = &plugin{}
+}
+
+func (s *pluginSuite) TestBuildDSN() {
+	testCases := []struct {
+		cfg          config.SQL
+		outPath      string
+		outAttrs     map[string]string
+		outNotExists []string
+	}{
+		{
+			cfg:      config.SQL{DatabaseName: "/tmp/temporal.db"},
+			outPath:  "file:/tmp/temporal.db",
+			outAttrs: map[string]string{"mode": "rw", busyTimeoutAttrName: defaultBusyTimeoutMS, txLockAttrName: defaultTxLock},
+		},
+		{
+			cfg: config.SQL{
+				DatabaseName:      "temporal",
+				ConnectAttributes: map[string]string{modeAttrName: modeMemory, busyTimeoutAttrName: "5"},
+			},
+			outPath:  "file:temporal",
+			outAttrs: map[string]string{"mode": "memory", "cache": "shared", busyTimeoutAttrName: "5", txLockAttrName: defaultTxLock},
+		},
+		{
+			cfg: config.SQL{
+				DatabaseName:      "/tmp/temporal.db",
+				ConnectAttributes: map[string]string{"_journal_mode": "WAL"},
+			},
+			outPath:  "file:/tmp/temporal.db",
+			outAttrs: map[string]string{"mode": "rw", "_journal_mode": "WAL"},
+		},
+	}
+
+	for _, tc := range testCases {
+		dsn := buildDSN(&tc.cfg, tc.cfg.DatabaseName)
+		tokens := strings.SplitN(dsn, "?", 2)
+		s.Equal(tc.outPath, tokens[0])
+		attrs, err := url.ParseQuery(tokens[1])
+		s.NoError(err)
+		for k, v := range tc.outAttrs {
+			s.Equal(v, attrs.Get(k), k)
+		}
+	}
+}
+
+func (s *pluginSuite) TestInMemoryDatabase() {
+	cfg := config.SQL{
+		PluginName:        PluginName,
+		ConnectAttributes: map[string]string{modeAttrName: modeMemory},
+	}
+	adminDB, err := s.plugin.CreateAdminDB(&cfg)
+	s.NoError(err)
+	defer adminDB.Close()
+
+	s.NoError(adminDB.CreateDatabase("test_in_memory"))
+	s.Error(adminDB.CreateDatabase("test_in_memory"))
+
+	cfg.DatabaseName = "test_in_memory"
+	db, err := s.plugin.CreateAdminDB(&cfg)
+	s.NoError(err)
+	s.NoError(db.Exec(testTableQuery))
+	s.NoError(db.Close())
+
+	// the database outlives its connections until it is dropped
+	db, err = s.plugin.CreateAdminDB(&cfg)
+	s.NoError(err)
+	tables, err := db.ListTables(cfg.DatabaseName)
+	s.NoError(err)
+	s.Equal([]string{"test_table"}, tables)
+	s.NoError(db.Close())
+
+	s.NoError(adminDB.DropDatabase("test_in_memory"))
+	s.Error(adminDB.DropDatabase("test_in_memory"))
+
+	db, err = s.plugin.CreateAdminDB(&cfg)
+	s.NoError(err)
+	tables, err = db.ListTables(cfg.DatabaseName)
+	s.NoError(err)
+	s.Empty(tables)
+	s.NoError(db.Close())
+}
+
+func (s *pluginSuite) TestFileDatabase() {
+	dir, err := ioutil.TempDir("", "sqlite_plugin_test")
+	s.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cfg := config.SQL{PluginName: PluginName}
+	adminDB, err := s.plugin.CreateAdminDB(&cfg)
+	s.NoError(err)
+	defer adminDB.Close()
+
+	cfg.DatabaseName = filepath.Join(dir, "temporal.db")
+	_, err = s.plugin.CreateDB(&cfg)
+	s.Error(err)
+
+	s.NoError(adminDB.CreateDatabase(cfg.DatabaseName))
+	s.Error(adminDB.CreateDatabase(cfg.DatabaseName))
+
+	db, err := s.plugin.CreateAdminDB(&cfg)
+	s.NoError(err)
+	s.NoError(db.Exec(testTableQuery))
+	s.NoError(db.DropAllTables(cfg.DatabaseName))
+	tables, err := db.ListTables(cfg.DatabaseName)
+	s.NoError(err)
+	s.Empty(tables)
+	s.NoError(db.Close())
+
+	s.NoError(adminDB.DropDatabase(cfg.DatabaseName))
+	_, err = os.Stat(cfg.DatabaseName)
+	s.True(os.IsNotExist(err))
+}
+
+func (s *pluginSuite) TestSharedConnection() {
+	cfg := config.SQL{
+		PluginName:        PluginName,
+		DatabaseName:      "test_shared_connection",
+		ConnectAttributes: map[string]string{modeAttrName: modeMemory},
+	}
+	db1, err := s.plugin.CreateDB(&cfg)
+	s.NoError(err)
+	db2, err := s.plugin.CreateDB(&cfg)
+	s.NoError(err)
+
+	s.NoError(db1.(*db).Exec(testTableQuery))
+	_, err = db1.(*db).conn.Exec(`INSERT INTO test_table (id) VALUES (1)`)
+	s.NoError(err)
+	s.NoError(db1.Close())
+
+	// closing one handle leaves the database open for the other
+	_, err = db2.(*db).conn.Exec(`INSERT INTO test_table (id) VALUES (1)`)
+	s.True(db2.IsDupEntryError(err))
+	s.False(db2.IsDupEntryError(os.ErrNotExist))
+	s.NoError(db2.Close())
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package quotas
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/temporalio/temporal/common/clock"
+)
+
+// minAdaptiveBackoffInterval is the minimum time between two rate reductions, errors
+// of calls that were in flight when the rate was reduced must not reduce it again
+const minAdaptiveBackoffInterval = time.Second
+
+type (
+	// AdaptiveRateLimiterParams are the parameters of an AdaptiveRateLimiter, they are
+	// evaluated on every call so that they can be backed by dynamic config
+	AdaptiveRateLimiterParams struct {
+		// MaxRPS is the rate the limiter starts with and recovers to
+		MaxRPS RPSFunc
+		// MinRPS is the rate the limiter never backs off below
+		MinRPS RPSFunc
+		// RecoveryRPS is the rate added back for every second since the last backoff
+		RecoveryRPS RPSFunc
+		// BackoffRatio is the factor the rate is multiplied with on backoff
+		BackoffRatio func() float64
+		// OnRateChange is optional, it is called with the new rate whenever
+		// the rate is backed off or recovers
+		OnRateChange func(rps float64)
+	}
+
+	// AdaptiveRateLimiter is a rate limiter which adjusts its rate with additive increase
+	// and multiplicative decrease (AIMD). The rate is multiplied by BackoffRatio whenever
+	// the caller reports that the downstream is overloaded, and recovers linearly by
+	// RecoveryRPS per second up to MaxRPS.
+	AdaptiveRateLimiter struct {
+		params     AdaptiveRateLimiterParams
+		timeSource clock.TimeSource
+
+		sync.Mutex
+		rps         float64
+		lastUpdate  time.Time
+		lastBackoff time.Time
+		limiter     *rate.Limiter
+	}
+)
+
+var _ Limiter = (*AdaptiveRateLimiter)(nil)
+
+// NewAdaptiveRateLimiter returns a new AdaptiveRateLimiter starting at MaxRPS
+func NewAdaptiveRateLimiter(params AdaptiveRateLimiterParams) *AdaptiveRateLimiter {
+	return newAdaptiveRateLimiter(params, clock.NewRealTimeSource())
+}
+
+func newAdaptiveRateLimiter(params AdaptiveRateLimiterParams, timeSource clock.TimeSource) *AdaptiveRateLimiter {
+	rps := params.MaxRPS()
+	return &AdaptiveRateLimiter{
+		params:     params,
+		timeSource: timeSource,
+		rps:        rps,
+		lastUpdate: timeSource.Now(),
+		limiter:    rate.NewLimiter(rate.Limit(rps), adaptiveBurst(rps)),
+	}
+}
+
+// Allow immediately returns with true or false indicating if a rate limit
+// token is available or not
+func (l *AdaptiveRateLimiter) Allow() bool {
+	l.Lock()
+	defer l.Unlock()
+	now := l.timeSource.Now()
+	l.recover(now)
+	return l.limiter.AllowN(now, 1)
+}
+
+// Wait waits up till deadline for a rate limit token
+func (l *AdaptiveRateLimiter) Wait(ctx context.Context) error {
+	l.Lock()
+	l.recover(l.timeSource.Now())
+	l.Unlock()
+	return l.limiter.Wait(ctx)
+}
+
+// Backoff reduces the rate after the downstream reported that it is overloaded
+func (l *AdaptiveRateLimiter) Backoff() {
+	l.Lock()
+	defer l.Unlock()
+	now := l.timeSource.Now()
+	l.recover(now)
+	if now.Sub(l.lastBackoff) < minAdaptiveBackoffInterval {
+		return
+	}
+	l.lastBackoff = now
+	l.setRPS(now, l.rps*l.params.BackoffRatio())
+}
+
+// Throttled returns true if the rate is currently reduced below MaxRPS
+func (l *AdaptiveRateLimiter) Throttled() bool {
+	l.Lock()
+	defer l.Unlock()
+	l.recover(l.timeSource.Now())
+	return l.rps < l.params.MaxRPS()
+}
+
+// Limit returns the current rate per second limit of this rate limiter
+func (l *AdaptiveRateLimiter) Limit() float64 {
+	l.Lock()
+	defer l.Unlock()
+	l.recover(l.timeSource.Now())
+	return l.rps
+}
+
+func (l *AdaptiveRateLimiter) recover(now time.Time) {
+	elapsed := now.Sub(l.lastUpdate)
+	if elapsed <= 0 {
+		return
+	}
+	l.setRPS(now, l.rps+l.params.RecoveryRPS()*elapsed.Seconds())
+}
+
+func (l *AdaptiveRateLimiter) setRPS(now time.Time, rps float64) {
+	rps = math.Max(math.Min(rps, l.params.MaxRPS()), l.params.MinRPS())
+	l.lastUpdate = now
+	if rps == l.rps {
+		return
+	}
+	l.rps = rps
+	l.limiter.SetLimitAt(now, rate.Limit(rps))
+	l.limiter.SetBurstAt(now, adaptiveBurst(rps))
+	if l.params.OnRateChange != nil {
+		l.params.OnRateChange(rps)
+	}
+}
+
+func adaptiveBurst(rps float64) int {
+	// same as RateLimiter, the burst is the rate but at least one
+	if burst := int(rps); burst > _burstSize {
+		return burst
+	}
+	return _burstSize
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package quotas
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+)
+
+// minDemandShareRatio is the min fraction of the equal share a host gets when
+// the quota is rebalanced, so that a host which has seen no requests recently
+// is still able to serve some of them
+const minDemandShareRatio = 0.1
+
+// TotalDemandKey is the key of the demand of all the domains in the demands
+// shared by the hosts
+const TotalDemandKey = ""
+
+// maxTrackedDemandDomains bounds the number of domains a host accounts the
+// demand of between two rebalances, the requests of the domains showing up
+// once the limit is reached are only accounted in the total demand
+const maxTrackedDemandDomains = 1000
+
+type (
+	// MemberCounter returns the number of hosts which share a cluster-wide quota
+	MemberCounter interface {
+		MemberCount() int
+	}
+
+	// DemandExchanger shares the request rate observed by each host with the
+	// other hosts sharing a cluster-wide quota. Demands map a domain to its
+	// request rate, the rate of all the domains is keyed by TotalDemandKey.
+	DemandExchanger interface {
+		// PublishDemand publishes the request rates observed by the local host
+		PublishDemand(demand map[string]float64) error
+		// CollectDemand returns the latest request rates published by every host
+		CollectDemand() (map[string]map[string]float64, error)
+	}
+
+	// ClusterQuota divides cluster-wide rate limits among the hosts of a
+	// service. The quota is split equally by the number of members of the
+	// ring, or when rebalancing is enabled, by the share of the cluster-wide
+	// demand each host observes, overall and for each domain.
+	ClusterQuota struct {
+		status            int32
+		members           MemberCounter
+		exchanger         DemandExchanger
+		rebalance         func() bool
+		rebalanceInterval func() time.Duration
+		logger            log.Logger
+
+		sync.Mutex
+		requestCounts map[string]int64 // domain -> requests since the last rebalance
+
+		// map[string]float64 of domain -> share of the demand, nil when unknown
+		demandShares atomic.Value
+		shutdownCh   chan struct{}
+	}
+
+	demandTrackingPolicy struct {
+		Policy
+		quota *ClusterQuota
+	}
+)
+
+// NewClusterQuota returns a new cluster quota
+func NewClusterQuota(
+	members MemberCounter,
+	exchanger DemandExchanger,
+	rebalance func() bool,
+	rebalanceInterval func() time.Duration,
+	logger log.Logger,
+) *ClusterQuota {
+	q := &ClusterQuota{
+		status:            common.DaemonStatusInitialized,
+		members:           members,
+		exchanger:         exchanger,
+		rebalance:         rebalance,
+		rebalanceInterval: rebalanceInterval,
+		logger:            logger,
+		requestCounts:     make(map[string]int64),
+		shutdownCh:        make(chan struct{}),
+	}
+	q.demandShares.Store(map[string]float64(nil))
+	return q
+}
+
+// Start starts rebalancing the quota
+func (q *ClusterQuota) Start() {
+	if !atomic.CompareAndSwapInt32(&q.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+	go q.rebalanceLoop()
+}
+
+// Stop stops rebalancing the quota
+func (q *ClusterQuota) Stop() {
+	if !atomic.CompareAndSwapInt32(&q.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+	close(q.shutdownCh)
+}
+
+// Share returns the fraction of the cluster-wide quota the local host can use
+func (q *ClusterQuota) Share() float64 {
+	return q.DomainShare(TotalDemandKey)
+}
+
+// DomainShare returns the fraction of the cluster-wide quota of a domain the
+// local host can use
+func (q *ClusterQuota) DomainShare(domain string) float64 {
+	numMembers := q.members.MemberCount()
+	if numMembers < 1 {
+		numMembers = 1
+	}
+	equalShare := 1 / float64(numMembers)
+	if !q.rebalance() {
+		return equalShare
+	}
+
+	// the demand of a domain no host has seen recently is not known
+	demandShare, ok := q.demandShares.Load().(map[string]float64)[domain]
+	if !ok {
+		return equalShare
+	}
+	return math.Min(1, math.Max(demandShare, equalShare*minDemandShareRatio))
+}
+
+// Track returns a policy which counts the requests checked by the given policy
+// as the demand of the local host
+func (q *ClusterQuota) Track(policy Policy) Policy {
+	return &demandTrackingPolicy{
+		Policy: policy,
+		quota:  q,
+	}
+}
+
+func (q *ClusterQuota) rebalanceLoop() {
+	timer := time.NewTimer(q.rebalanceInterval())
+	defer timer.Stop()
+
+	lastRebalanceTime := time.Now()
+	for {
+		select {
+		case <-q.shutdownCh:
+			return
+		case <-timer.C:
+			now := time.Now()
+			q.rebalanceOnce(now.Sub(lastRebalanceTime))
+			lastRebalanceTime = now
+			timer.Reset(q.rebalanceInterval())
+		}
+	}
+}
+
+func (q *ClusterQuota) rebalanceOnce(elapsed time.Duration) {
+	q.Lock()
+	requestCounts := q.requestCounts
+	q.requestCounts = make(map[string]int64)
+	q.Unlock()
+	if !q.rebalance() || elapsed <= 0 {
+		q.demandShares.Store(map[string]float64(nil))
+		return
+	}
+
+	demand := make(map[string]float64, len(requestCounts)+1)
+	demand[TotalDemandKey] = 0
+	for domain, requestCount := range requestCounts {
+		demand[domain] = float64(requestCount) / elapsed.Seconds()
+	}
+	if err := q.exchanger.PublishDemand(demand); err != nil {
+		q.logger.Warn("Failed to publish quota demand", tag.Error(err))
+	}
+	demands, err := q.exchanger.CollectDemand()
+	if err != nil {
+		q.logger.Warn("Failed to collect quota demand", tag.Error(err))
+		q.demandShares.Store(map[string]float64(nil))
+		return
+	}
+
+	// fall back to the equal share until every host has published its demand
+	if len(demands) < q.members.MemberCount() {
+		q.demandShares.Store(map[string]float64(nil))
+		return
+	}
+	totalDemands := make(map[string]float64)
+	for _, hostDemand := range demands {
+		for domain, rps := range hostDemand {
+			totalDemands[domain] += rps
+		}
+	}
+	demandShares := make(map[string]float64, len(totalDemands))
+	for domain, totalDemand := range totalDemands {
+		if totalDemand > 0 {
+			demandShares[domain] = demand[domain] / totalDemand
+		}
+	}
+	q.demandShares.Store(demandShares)
+}
+
+func (q *ClusterQuota) recordRequest(domain string) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.requestCounts[TotalDemandKey]++
+	if domain == TotalDemandKey {
+		return
+	}
+	if _, ok := q.requestCounts[domain]; ok || len(q.requestCounts) <= maxTrackedDemandDomains {
+		q.requestCounts[domain]++
+	}
+}
+
+func (p *demandTrackingPolicy) Allow(info Info) bool {
+	p.quota.recordRequest(info.Domain)
+	return p.Policy.Allow(info)
+}
+
+func (p *demandTrackingPolicy) Wait(ctx context.Context, info Info) error {
+	p.quota.recordRequest(info.Domain)
+	return p.Policy.Wait(ctx, info)
+}
@@ -20,7 +20,14 @@
 
 package quotas
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrRateLimited is returned by Policy.Wait when a rate limit token can not be
+// obtained before the deadline of the context
+var ErrRateLimited = errors.New("rate limit exceeded")
 
 // RPSFunc returns a float64 as the RPS
 type RPSFunc func() float64
@@ -28,9 +35,14 @@ type RPSFunc func() float64
 // RPSKeyFunc returns a float64 as the RPS for the given key
 type RPSKeyFunc func(key string) float64
 
+// BurstKeyFunc returns an int as the burst for the given key
+type BurstKeyFunc func(key string) int
+
 // Info corresponds to information required to determine rate limits
 type Info struct {
-	Domain string
+	Domain   string
+	API      string
+	TaskList string
 }
 
 // Limiter corresponds to basic rate limiting functionality.
@@ -52,4 +64,9 @@ type Policy interface {
 	// immediately with a true or false indicating if the request can make
 	// progress
 	Allow(info Info) bool
+
+	// Wait waits for rate limit tokens to allow the request to go through. If
+	// the tokens will not be available before the deadline of the context the
+	// method returns ErrRateLimited right away instead of waiting.
+	Wait(ctx context.Context, info Info) error
 }
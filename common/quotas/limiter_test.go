@@ -21,12 +21,16 @@
 package quotas
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/time/rate"
+
+	"github.com/temporalio/temporal/common/clock"
+	"github.com/temporalio/temporal/common/log"
 )
 
 const (
@@ -42,6 +46,41 @@ func TestNewRateLimiter(t *testing.T) {
 	assert.Equal(t, _minBurst, limiter.Burst())
 }
 
+func TestRateLimiterSetMaxDispatch(t *testing.T) {
+	maxDispatch := 10.0
+	rl := NewRateLimiter(&maxDispatch, time.Minute, _minBurst)
+
+	raised := 100.0
+	rl.UpdateMaxDispatch(&raised)
+	assert.Equal(t, 10.0, rl.Limit())
+
+	rl.SetMaxDispatch(&raised)
+	assert.Equal(t, 100.0, rl.Limit())
+	limiter := rl.goRateLimiter.Load().(*rate.Limiter)
+	assert.Equal(t, rate.Limit(100), limiter.Limit())
+}
+
+func TestRateLimiterReserveN(t *testing.T) {
+	rl := NewSimpleRateLimiter(10)
+	assert.Equal(t, 10, rl.Burst())
+	now := time.Now()
+	assert.Equal(t, time.Duration(0), rl.ReserveN(now, 6).DelayFrom(now))
+	r := rl.ReserveN(now, 6)
+	assert.True(t, r.DelayFrom(now) > 0)
+	// the tokens of a cancelled reservation are available again
+	r.CancelAt(now)
+	assert.Equal(t, time.Duration(0), rl.ReserveN(now, 4).DelayFrom(now))
+}
+
+func TestDynamicRateLimiterReserveN(t *testing.T) {
+	rl := NewDynamicRateLimiter(func() float64 { return 5 })
+	assert.Equal(t, 5, rl.Burst())
+	now := time.Now()
+	assert.True(t, rl.ReserveN(now, 5).OK())
+	assert.True(t, rl.ReserveN(now, 1).DelayFrom(now) > 0)
+	assert.False(t, rl.ReserveN(now, 6).OK())
+}
+
 func TestMultiStageRateLimiterBlockedByDomainRps(t *testing.T) {
 	policy := newFixedRpsMultiStageRateLimiter(2, 1)
 	var result []bool
@@ -86,6 +125,235 @@ func TestMultiStageRateLimiterBlockedByGlobalRps(t *testing.T) {
 	assert.Equal(t, 2, numAllowed)
 }
 
+func TestMultiStageRateLimiterWaitShedsBeyondDeadline(t *testing.T) {
+	policy := newFixedRpsMultiStageRateLimiter(10, 10)
+	for n := 0; n < 10; n++ {
+		assert.True(t, policy.Allow(Info{Domain: defaultDomain}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	assert.Equal(t, ErrRateLimited, policy.Wait(ctx, Info{Domain: defaultDomain}))
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, policy.Wait(ctx, Info{Domain: defaultDomain}))
+}
+
+func TestPriorityRateLimiterLowPriorityDoesNotStarveHighPriority(t *testing.T) {
+	policy := newFixedRpsPriorityRateLimiter(10, 10, 0)
+	var numLowAllowed int
+	for n := 0; n < 10; n++ {
+		if policy.Allow(Info{Domain: defaultDomain, API: "ListOpenWorkflowExecutions"}) {
+			numLowAllowed++
+		}
+	}
+	assert.Equal(t, 2, numLowAllowed)
+
+	var numNormalAllowed int
+	for n := 0; n < 10; n++ {
+		if policy.Allow(Info{Domain: defaultDomain, API: "StartWorkflowExecution"}) {
+			numNormalAllowed++
+		}
+	}
+	assert.Equal(t, 3, numNormalAllowed)
+
+	var numHighAllowed int
+	for n := 0; n < 10; n++ {
+		if policy.Allow(Info{Domain: defaultDomain, API: "PollForDecisionTask"}) {
+			numHighAllowed++
+		}
+	}
+	assert.Equal(t, 5, numHighAllowed)
+}
+
+func TestPriorityRateLimiterBlockedByRule(t *testing.T) {
+	policy := newFixedRpsPriorityRateLimiter(100, 100, 1)
+	info := Info{Domain: defaultDomain, API: "PollForActivityTask", TaskList: "tl"}
+	assert.True(t, policy.Allow(info))
+	assert.False(t, policy.Allow(info))
+
+	// rules are keyed by task list
+	info.TaskList = "other-tl"
+	assert.True(t, policy.Allow(info))
+	assert.False(t, policy.Allow(info))
+}
+
+func TestPriorityRateLimiterDomainBurst(t *testing.T) {
+	params := newFixedRpsPriorityRateLimiterParams(100, 1, 0)
+	params.DomainBurst = func(domain string) int {
+		return 5
+	}
+	policy := NewPriorityRateLimiter(params)
+	var numAllowed int
+	for n := 0; n < 10; n++ {
+		if policy.Allow(Info{Domain: defaultDomain, API: "SignalWorkflowExecution"}) {
+			numAllowed++
+		}
+	}
+	assert.Equal(t, 5, numAllowed)
+}
+
+func TestPriorityRateLimiterHighPriorityNotLimitedByDomain(t *testing.T) {
+	policy := newFixedRpsPriorityRateLimiter(100, 1, 0)
+	for n := 0; n < 10; n++ {
+		assert.True(t, policy.Allow(Info{Domain: defaultDomain, API: "PollForDecisionTask", TaskList: "tl"}))
+	}
+	assert.True(t, policy.Allow(Info{Domain: defaultDomain, API: "StartWorkflowExecution"}))
+	assert.False(t, policy.Allow(Info{Domain: defaultDomain, API: "StartWorkflowExecution"}))
+}
+
+func TestPriorityRateLimiterRuleLimitersBounded(t *testing.T) {
+	policy := NewPriorityRateLimiter(newFixedRpsPriorityRateLimiterParams(1000000, 1000000, 1))
+	for n := 0; n <= maxRuleLimiters; n++ {
+		policy.Allow(Info{Domain: defaultDomain, API: "PollForActivityTask", TaskList: fmt.Sprintf("tl-%v", n)})
+	}
+	assert.True(t, len(policy.ruleLimiters) <= maxRuleLimiters)
+}
+
+func TestPriorityRateLimiterWaitShedsBeyondDeadline(t *testing.T) {
+	policy := newFixedRpsPriorityRateLimiter(100, 100, 10)
+	info := Info{Domain: defaultDomain, API: "RespondDecisionTaskCompleted", TaskList: "tl"}
+	for n := 0; n < 10; n++ {
+		assert.True(t, policy.Allow(info))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.Equal(t, ErrRateLimited, policy.Wait(ctx, info))
+	assert.True(t, time.Since(start) < 50*time.Millisecond)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, policy.Wait(ctx, info))
+}
+
+func TestClusterQuotaEqualShare(t *testing.T) {
+	members := &fakeMemberCounter{count: 4}
+	quota := NewClusterQuota(members, &fakeDemandExchanger{}, func() bool { return false }, func() time.Duration { return time.Second }, log.NewNoop())
+	assert.Equal(t, 0.25, quota.Share())
+
+	members.count = 0
+	assert.Equal(t, 1.0, quota.Share())
+}
+
+func TestClusterQuotaDemandShare(t *testing.T) {
+	members := &fakeMemberCounter{count: 2}
+	exchanger := &fakeDemandExchanger{}
+	quota := NewClusterQuota(members, exchanger, func() bool { return true }, func() time.Duration { return time.Second }, log.NewNoop())
+	policy := quota.Track(newFixedRpsMultiStageRateLimiter(100, 100))
+
+	// only the local host has published its demand so far
+	for n := 0; n < 10; n++ {
+		policy.Allow(Info{Domain: defaultDomain})
+	}
+	quota.rebalanceOnce(time.Second)
+	assert.Equal(t, 0.5, quota.Share())
+
+	exchanger.demands = map[string]map[string]float64{"other": {TotalDemandKey: 30, defaultDomain: 30}}
+	for n := 0; n < 10; n++ {
+		policy.Allow(Info{Domain: defaultDomain})
+	}
+	quota.rebalanceOnce(time.Second)
+	assert.Equal(t, map[string]float64{TotalDemandKey: 10, defaultDomain: 10}, exchanger.published)
+	assert.Equal(t, 0.25, quota.Share())
+	assert.Equal(t, 0.25, quota.DomainShare(defaultDomain))
+
+	// an idle host still gets a fraction of the equal share
+	quota.rebalanceOnce(time.Second)
+	assert.Equal(t, 0.5*minDemandShareRatio, quota.Share())
+}
+
+func TestClusterQuotaDomainDemandShare(t *testing.T) {
+	members := &fakeMemberCounter{count: 2}
+	exchanger := &fakeDemandExchanger{
+		demands: map[string]map[string]float64{"other": {TotalDemandKey: 40, "other-domain": 40}},
+	}
+	quota := NewClusterQuota(members, exchanger, func() bool { return true }, func() time.Duration { return time.Second }, log.NewNoop())
+	policy := quota.Track(newFixedRpsMultiStageRateLimiter(100, 100))
+
+	for n := 0; n < 10; n++ {
+		policy.Allow(Info{Domain: defaultDomain})
+	}
+	quota.rebalanceOnce(time.Second)
+	assert.Equal(t, 0.2, quota.Share())
+	// the local host serves all the requests of its domain, and none of the other domain
+	assert.Equal(t, 1.0, quota.DomainShare(defaultDomain))
+	assert.Equal(t, 0.5*minDemandShareRatio, quota.DomainShare("other-domain"))
+	// no host has seen the domain, its quota is split equally
+	assert.Equal(t, 0.5, quota.DomainShare("idle-domain"))
+}
+
+func TestAdaptiveRateLimiterBackoffAndRecovery(t *testing.T) {
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	limiter := newAdaptiveRateLimiter(AdaptiveRateLimiterParams{
+		MaxRPS:       func() float64 { return 8 },
+		MinRPS:       func() float64 { return 1 },
+		RecoveryRPS:  func() float64 { return 2 },
+		BackoffRatio: func() float64 { return 0.5 },
+	}, timeSource)
+	assert.False(t, limiter.Throttled())
+
+	limiter.Backoff()
+	assert.Equal(t, 4.0, limiter.Limit())
+	assert.True(t, limiter.Throttled())
+
+	// errors of calls in flight during the backoff are ignored
+	limiter.Backoff()
+	assert.Equal(t, 4.0, limiter.Limit())
+
+	timeSource.Update(timeSource.Now().Add(time.Second))
+	assert.Equal(t, 6.0, limiter.Limit())
+	for i := 0; i < 3; i++ {
+		limiter.Backoff()
+		timeSource.Update(timeSource.Now().Add(minAdaptiveBackoffInterval))
+	}
+	assert.Equal(t, 4.25, limiter.Limit())
+
+	timeSource.Update(timeSource.Now().Add(time.Minute))
+	assert.Equal(t, 8.0, limiter.Limit())
+	assert.False(t, limiter.Throttled())
+}
+
+func TestAdaptiveRateLimiterOnRateChange(t *testing.T) {
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	var rates []float64
+	limiter := newAdaptiveRateLimiter(AdaptiveRateLimiterParams{
+		MaxRPS:       func() float64 { return 8 },
+		MinRPS:       func() float64 { return 1 },
+		RecoveryRPS:  func() float64 { return 2 },
+		BackoffRatio: func() float64 { return 0.5 },
+		OnRateChange: func(rps float64) { rates = append(rates, rps) },
+	}, timeSource)
+
+	limiter.Backoff()
+	timeSource.Update(timeSource.Now().Add(time.Second))
+	limiter.Allow()
+	timeSource.Update(timeSource.Now().Add(time.Minute))
+	limiter.Allow()
+	limiter.Allow()
+	assert.Equal(t, []float64{4, 6, 8}, rates)
+}
+
+func TestAdaptiveRateLimiterAllow(t *testing.T) {
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	limiter := newAdaptiveRateLimiter(AdaptiveRateLimiterParams{
+		MaxRPS:       func() float64 { return 2 },
+		MinRPS:       func() float64 { return 1 },
+		RecoveryRPS:  func() float64 { return 0 },
+		BackoffRatio: func() float64 { return 0.5 },
+	}, timeSource)
+	assert.True(t, limiter.Allow())
+	assert.True(t, limiter.Allow())
+	assert.False(t, limiter.Allow())
+
+	limiter.Backoff()
+	timeSource.Update(timeSource.Now().Add(time.Second))
+	assert.True(t, limiter.Allow())
+	assert.False(t, limiter.Allow())
+}
+
 func BenchmarkRateLimiter(b *testing.B) {
 	rps := float64(defaultRps)
 	limiter := NewRateLimiter(&rps, 2*time.Minute, defaultRps)
@@ -138,6 +406,68 @@ func newFixedRpsMultiStageRateLimiter(globalRps, domainRps float64) Policy {
 		},
 	)
 }
+
+func newFixedRpsPriorityRateLimiter(globalRps, domainRps, ruleRps float64) Policy {
+	return NewPriorityRateLimiter(newFixedRpsPriorityRateLimiterParams(globalRps, domainRps, ruleRps))
+}
+
+func newFixedRpsPriorityRateLimiterParams(globalRps, domainRps, ruleRps float64) PriorityRateLimiterParams {
+	return PriorityRateLimiterParams{
+		RPS: func() float64 {
+			return globalRps
+		},
+		PriorityRPSRatio: func(priority Priority) float64 {
+			if priority == PriorityLow {
+				return 0.2
+			}
+			return 0.5
+		},
+		DomainRPS: func(domain string) float64 {
+			return domainRps
+		},
+		DomainBurst: func(domain string) int {
+			return 0
+		},
+		RuleRPS: func(info Info) float64 {
+			return ruleRps
+		},
+		RuleBurst: func(info Info) int {
+			return 0
+		},
+		APIPriority: func(api string) Priority {
+			return DefaultAPIPriority(api)
+		},
+	}
+}
+
+type fakeMemberCounter struct {
+	count int
+}
+
+func (c *fakeMemberCounter) MemberCount() int {
+	return c.count
+}
+
+// fakeDemandExchanger returns the published demand of the local host along
+// with the demands of the other hosts
+type fakeDemandExchanger struct {
+	published map[string]float64
+	demands   map[string]map[string]float64
+}
+
+func (e *fakeDemandExchanger) PublishDemand(demand map[string]float64) error {
+	e.published = demand
+	return nil
+}
+
+func (e *fakeDemandExchanger) CollectDemand() (map[string]map[string]float64, error) {
+	demands := map[string]map[string]float64{"self": e.published}
+	for host, demand := range e.demands {
+		demands[host] = demand
+	}
+	return demands, nil
+}
+
 func getDomains(n int) []string {
 	domains := make([]string, n)
 	for i := 0; i < n; i++ {
@@ -21,6 +21,7 @@
 package quotas
 
 import (
+	"context"
 	"sync"
 )
 
@@ -54,6 +55,42 @@ func (d *MultiStageRateLimiter) Allow(info Info) bool {
 		return d.globalLimiter.Allow()
 	}
 
+	limiter := d.getDomainLimiter(domain)
+
+	// take a reservation with the domain limiter first
+	rsv := limiter.Reserve()
+	if !rsv.OK() {
+		return false
+	}
+
+	// check whether the reservation is valid now, otherwise
+	// cancel and return right away so we can drop the request
+	if rsv.Delay() != 0 {
+		rsv.Cancel()
+		return false
+	}
+
+	// ensure that the reservation does not break the global rate limit, if it
+	// does, cancel the reservation and do not allow to proceed.
+	if !d.globalLimiter.Allow() {
+		rsv.Cancel()
+		return false
+	}
+	return true
+}
+
+// Wait waits for tokens of both the domain and the global limiter, or returns
+// ErrRateLimited right away if they will not be available before the deadline
+// of the context
+func (d *MultiStageRateLimiter) Wait(ctx context.Context, info Info) error {
+	domain := info.Domain
+	if len(domain) == 0 {
+		return waitAll(ctx, d.globalLimiter)
+	}
+	return waitAll(ctx, d.getDomainLimiter(domain), d.globalLimiter)
+}
+
+func (d *MultiStageRateLimiter) getDomainLimiter(domain string) *DynamicRateLimiter {
 	// check if we have a per-domain limiter - if not create a default one for
 	// the domain.
 	d.RLock()
@@ -77,25 +114,5 @@ func (d *MultiStageRateLimiter) Allow(info Info) bool {
 		}
 		d.Unlock()
 	}
-
-	// take a reservation with the domain limiter first
-	rsv := limiter.Reserve()
-	if !rsv.OK() {
-		return false
-	}
-
-	// check whether the reservation is valid now, otherwise
-	// cancel and return right away so we can drop the request
-	if rsv.Delay() != 0 {
-		rsv.Cancel()
-		return false
-	}
-
-	// ensure that the reservation does not break the global rate limit, if it
-	// does, cancel the reservation and do not allow to proceed.
-	if !d.globalLimiter.Allow() {
-		rsv.Cancel()
-		return false
-	}
-	return true
+	return limiter
 }
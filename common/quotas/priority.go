@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package quotas
+
+import (
+	"strings"
+)
+
+// Priority is the priority class of an API. Requests of a lower priority
+// class can only use a fraction of the overall rate limit, so they are not
+// able to starve requests of a higher priority class.
+type Priority int
+
+const (
+	// PriorityHigh is the priority of the APIs used by workers to poll and respond to tasks
+	PriorityHigh Priority = iota
+	// PriorityNormal is the priority of the APIs which start, query or mutate workflows
+	PriorityNormal
+	// PriorityLow is the priority of the list, scan and count visibility APIs
+	PriorityLow
+
+	numPriorities
+)
+
+// IsValid returns whether the priority is a known priority class
+func (p Priority) IsValid() bool {
+	return p >= PriorityHigh && p < numPriorities
+}
+
+// DefaultAPIPriority returns the default priority class of an API
+func DefaultAPIPriority(api string) Priority {
+	switch {
+	case strings.HasPrefix(api, "PollFor") && api != "PollForWorkflowExecutionRawHistory",
+		strings.HasPrefix(api, "Respond"),
+		strings.HasPrefix(api, "RecordActivityTaskHeartbeat"):
+		return PriorityHigh
+	case strings.HasPrefix(api, "List"),
+		strings.HasPrefix(api, "Scan"),
+		strings.HasPrefix(api, "Count"):
+		return PriorityLow
+	default:
+		return PriorityNormal
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package quotas
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type (
+	// PriorityRateLimiterParams contains the dynamic configuration of a PriorityRateLimiter
+	PriorityRateLimiterParams struct {
+		// RPS is the rate limit of all the requests
+		RPS RPSFunc
+		// PriorityRPSRatio is the fraction of RPS which requests of the priority
+		// class and all the lower priority classes can use together
+		PriorityRPSRatio func(priority Priority) float64
+		// DomainRPS and DomainBurst are the rate limit of each domain, a burst
+		// of zero means the same as the RPS
+		DomainRPS   RPSKeyFunc
+		DomainBurst BurstKeyFunc
+		// RuleRPS and RuleBurst are the rate limit of each (domain, API, task
+		// list) key, a RPS of zero means the key is not limited
+		RuleRPS   func(info Info) float64
+		RuleBurst func(info Info) int
+		// APIPriority returns the priority class of an API
+		APIPriority func(api string) Priority
+	}
+
+	// PriorityRateLimiter is a quota policy with token buckets for (domain, API,
+	// task list) rules, domains, priority classes and all requests. A request
+	// is allowed only if every bucket it belongs to has a token available.
+	// Requests of the high priority class are not limited per domain, so that
+	// a domain busy starting or listing workflows does not starve its workers.
+	PriorityRateLimiter struct {
+		params PriorityRateLimiterParams
+
+		globalLimiter    *burstRateLimiter
+		priorityLimiters []*burstRateLimiter
+
+		sync.RWMutex
+		domainLimiters map[string]*burstRateLimiter
+		ruleLimiters   map[Info]*burstRateLimiter
+	}
+
+	// burstRateLimiter is a token bucket whose rate and burst follow the
+	// dynamic configuration
+	burstRateLimiter struct {
+		rps     func() float64
+		burst   func() int
+		limiter *rate.Limiter
+		// unix nanos of the last reservation
+		lastReserved int64
+	}
+)
+
+const (
+	// bounds the number of (domain, API, task list) keys a PriorityRateLimiter
+	// keeps a token bucket for, task lists are created by the callers so the
+	// keys can not be trusted to be a small set
+	maxRuleLimiters = 10000
+	// rule limiters not used for that long are evicted first once the limit is
+	// reached, an evicted key starts again with a full burst
+	ruleLimiterIdleTimeout = time.Minute
+)
+
+var _ Policy = (*PriorityRateLimiter)(nil)
+
+// NewPriorityRateLimiter returns a new priority aware quota policy
+func NewPriorityRateLimiter(params PriorityRateLimiterParams) *PriorityRateLimiter {
+	rl := &PriorityRateLimiter{
+		params:           params,
+		globalLimiter:    newBurstRateLimiter(params.RPS, nil),
+		priorityLimiters: make([]*burstRateLimiter, numPriorities),
+		domainLimiters:   make(map[string]*burstRateLimiter),
+		ruleLimiters:     make(map[Info]*burstRateLimiter),
+	}
+	// the high priority class is only limited by the global limiter
+	for priority := PriorityHigh + 1; priority < numPriorities; priority++ {
+		ratio := func(priority Priority) func() float64 {
+			return func() float64 {
+				return params.RPS() * params.PriorityRPSRatio(priority)
+			}
+		}(priority)
+		rl.priorityLimiters[priority] = newBurstRateLimiter(ratio, nil)
+	}
+	return rl
+}
+
+// Allow attempts to allow a request to go through. The method returns
+// immediately with a true or false indicating if the request can make
+// progress
+func (p *PriorityRateLimiter) Allow(info Info) bool {
+	return allowAll(p.getLimiters(info)...)
+}
+
+// Wait waits for the tokens of all the limiters of the request, or returns
+// ErrRateLimited right away if they will not be available before the deadline
+// of the context
+func (p *PriorityRateLimiter) Wait(ctx context.Context, info Info) error {
+	return waitAll(ctx, p.getLimiters(info)...)
+}
+
+// getLimiters returns the limiters of the request ordered from the most to
+// the least specific one
+func (p *PriorityRateLimiter) getLimiters(info Info) []reserver {
+	limiters := make([]reserver, 0, 4)
+	if p.params.RuleRPS(info) > 0 {
+		limiters = append(limiters, p.getRuleLimiter(info))
+	}
+	priority := p.params.APIPriority(info.API)
+	if !priority.IsValid() {
+		priority = DefaultAPIPriority(info.API)
+	}
+	if len(info.Domain) != 0 && priority != PriorityHigh {
+		limiters = append(limiters, p.getDomainLimiter(info.Domain))
+	}
+	for i := priority; i > PriorityHigh; i-- {
+		limiters = append(limiters, p.priorityLimiters[i])
+	}
+	return append(limiters, p.globalLimiter)
+}
+
+func (p *PriorityRateLimiter) getDomainLimiter(domain string) *burstRateLimiter {
+	p.RLock()
+	limiter, ok := p.domainLimiters[domain]
+	p.RUnlock()
+	if ok {
+		return limiter
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	if limiter, ok = p.domainLimiters[domain]; !ok {
+		limiter = newBurstRateLimiter(
+			func() float64 { return p.params.DomainRPS(domain) },
+			func() int { return p.params.DomainBurst(domain) },
+		)
+		p.domainLimiters[domain] = limiter
+	}
+	return limiter
+}
+
+func (p *PriorityRateLimiter) getRuleLimiter(info Info) *burstRateLimiter {
+	p.RLock()
+	limiter, ok := p.ruleLimiters[info]
+	p.RUnlock()
+	if ok {
+		return limiter
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	if limiter, ok = p.ruleLimiters[info]; !ok {
+		if len(p.ruleLimiters) >= maxRuleLimiters {
+			p.evictRuleLimitersLocked()
+		}
+		limiter = newBurstRateLimiter(
+			func() float64 { return p.params.RuleRPS(info) },
+			func() int { return p.params.RuleBurst(info) },
+		)
+		p.ruleLimiters[info] = limiter
+	}
+	return limiter
+}
+
+// evictRuleLimitersLocked evicts the idle rule limiters, or an arbitrary
+// tenth of the rule limiters if none of them is idle
+func (p *PriorityRateLimiter) evictRuleLimitersLocked() {
+	idleSince := time.Now().Add(-ruleLimiterIdleTimeout).UnixNano()
+	for info, limiter := range p.ruleLimiters {
+		if atomic.LoadInt64(&limiter.lastReserved) < idleSince {
+			delete(p.ruleLimiters, info)
+		}
+	}
+
+	for info := range p.ruleLimiters {
+		if len(p.ruleLimiters) < maxRuleLimiters*9/10 {
+			return
+		}
+		delete(p.ruleLimiters, info)
+	}
+}
+
+func newBurstRateLimiter(rps RPSFunc, burst func() int) *burstRateLimiter {
+	l := &burstRateLimiter{
+		rps:   rps,
+		burst: burst,
+	}
+	limit, b := l.config()
+	l.limiter = rate.NewLimiter(limit, b)
+	return l
+}
+
+// Reserve reserves a rate limit token after applying the latest configuration
+func (l *burstRateLimiter) Reserve() *rate.Reservation {
+	limit, burst := l.config()
+	if limit != l.limiter.Limit() {
+		l.limiter.SetLimit(limit)
+	}
+	if burst != l.limiter.Burst() {
+		l.limiter.SetBurst(burst)
+	}
+	atomic.StoreInt64(&l.lastReserved, time.Now().UnixNano())
+	return l.limiter.Reserve()
+}
+
+func (l *burstRateLimiter) config() (rate.Limit, int) {
+	rps := l.rps()
+	var burst int
+	if l.burst != nil {
+		burst = l.burst()
+	}
+	if burst <= 0 {
+		// same as RateLimiter, throttling to zero also means a burst of zero
+		burst = int(rps)
+		if rps != 0 && burst < _burstSize {
+			burst = _burstSize
+		}
+	}
+	return rate.Limit(rps), burst
+}
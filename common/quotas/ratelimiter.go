@@ -81,6 +81,18 @@ func (rl *RateLimiter) UpdateMaxDispatch(maxDispatchPerSecond *float64) {
 	}
 }
 
+// SetMaxDispatch sets the max dispatch rate of the rate limiter immediately,
+// without waiting for the TTL to expire when the rate is being raised
+func (rl *RateLimiter) SetMaxDispatch(maxDispatchPerSecond *float64) {
+	if maxDispatchPerSecond == nil {
+		return
+	}
+	rl.Lock()
+	rl.maxDispatchPerSecond = maxDispatchPerSecond
+	rl.storeLimiter(maxDispatchPerSecond)
+	rl.Unlock()
+}
+
 // Wait waits up till deadline for a rate limit token
 func (rl *RateLimiter) Wait(ctx context.Context) error {
 	limiter := rl.goRateLimiter.Load().(*rate.Limiter)
@@ -100,6 +112,18 @@ func (rl *RateLimiter) Allow() bool {
 	return limiter.Allow()
 }
 
+// ReserveN reserves n rate limit tokens at the given time
+func (rl *RateLimiter) ReserveN(now time.Time, n int) *rate.Reservation {
+	limiter := rl.goRateLimiter.Load().(*rate.Limiter)
+	return limiter.ReserveN(now, n)
+}
+
+// Burst returns the max number of tokens which can be consumed at once
+func (rl *RateLimiter) Burst() int {
+	limiter := rl.goRateLimiter.Load().(*rate.Limiter)
+	return limiter.Burst()
+}
+
 // Limit returns the current rate per second limit for this ratelimiter
 func (rl *RateLimiter) Limit() float64 {
 	if rl.maxDispatchPerSecond != nil {
@@ -156,6 +180,18 @@ func (d *DynamicRateLimiter) Allow() bool {
 	return d.rl.Allow()
 }
 
+// ReserveN reserves n rate limit tokens at the given time
+func (d *DynamicRateLimiter) ReserveN(now time.Time, n int) *rate.Reservation {
+	rps := d.rps()
+	d.rl.UpdateMaxDispatch(&rps)
+	return d.rl.ReserveN(now, n)
+}
+
+// Burst returns the max number of tokens which can be consumed at once
+func (d *DynamicRateLimiter) Burst() int {
+	return d.rl.Burst()
+}
+
 // Wait waits up till deadline for a rate limit token
 func (d *DynamicRateLimiter) Wait(ctx context.Context) error {
 	rps := d.rps()
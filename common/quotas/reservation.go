@@ -0,0 +1,108 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package quotas
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type reserver interface {
+	Reserve() *rate.Reservation
+}
+
+// allowAll takes a token from every reserver if all of them have a token
+// available now, otherwise none of the tokens are taken
+func allowAll(reservers ...reserver) bool {
+	start := time.Now()
+	rsvs, delay, ok := reserveAll(start, reservers...)
+	if !ok {
+		return false
+	}
+	if delay != 0 {
+		cancelAll(start, rsvs)
+		return false
+	}
+	return true
+}
+
+// waitAll takes a token from every reserver and waits until all of them are
+// available. Requests which can not make progress before the deadline of the
+// context are shed right away, so that they do not hold on to tokens which
+// other requests could use.
+func waitAll(ctx context.Context, reservers ...reserver) error {
+	start := time.Now()
+	rsvs, delay, ok := reserveAll(start, reservers...)
+	if !ok {
+		return ErrRateLimited
+	}
+	if delay == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		cancelAll(start, rsvs)
+		return ErrRateLimited
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		cancelAll(start, rsvs)
+		return ctx.Err()
+	}
+}
+
+// reserveAll takes a reservation from every reserver and returns the longest
+// delay among them. All the reservations are made after start.
+func reserveAll(start time.Time, reservers ...reserver) ([]*rate.Reservation, time.Duration, bool) {
+	rsvs := make([]*rate.Reservation, 0, len(reservers))
+	for _, r := range reservers {
+		rsv := r.Reserve()
+		if !rsv.OK() {
+			cancelAll(start, rsvs)
+			return nil, 0, false
+		}
+		rsvs = append(rsvs, rsv)
+	}
+
+	var delay time.Duration
+	now := time.Now()
+	for _, rsv := range rsvs {
+		if d := rsv.DelayFrom(now); d > delay {
+			delay = d
+		}
+	}
+	return rsvs, delay, true
+}
+
+// cancelAll returns the tokens of the reservations made after start. Canceling
+// at start rather than now also returns the tokens of the reservations which
+// are already due, which rate.Reservation.Cancel would keep.
+func cancelAll(start time.Time, rsvs []*rate.Reservation) {
+	for _, rsv := range rsvs {
+		rsv.CancelAt(start)
+	}
+}
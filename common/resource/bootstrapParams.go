@@ -64,7 +64,10 @@ type (
 		PublicClient                 sdkclient.Client
 		ArchivalMetadata             archiver.ArchivalMetadata
 		ArchiverProvider             provider.ArchiverProvider
+		HistoryArchivalEnvelope      *archiver.BlobEnvelope
+		VisibilityArchivalEnvelope   *archiver.BlobEnvelope
 		Authorizer                   authorization.Authorizer
+		ClaimMapper                  authorization.ClaimMapper
 	}
 
 	// MembershipMonitorFactory provides a bootstrapped membership monitor
@@ -253,12 +253,14 @@ func New(
 		MetricsClient:    params.MetricsClient,
 		ClusterMetadata:  params.ClusterMetadata,
 		DomainCache:      domainCache,
+		Envelope:         params.HistoryArchivalEnvelope,
 	}
 	visibilityArchiverBootstrapContainer := &archiver.VisibilityBootstrapContainer{
 		Logger:          logger,
 		MetricsClient:   params.MetricsClient,
 		ClusterMetadata: params.ClusterMetadata,
 		DomainCache:     domainCache,
+		Envelope:        params.VisibilityArchivalEnvelope,
 	}
 	if err := params.ArchiverProvider.RegisterBootstrapContainer(
 		serviceName,
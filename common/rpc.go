@@ -21,6 +21,7 @@
 package common
 
 import (
+	"crypto/tls"
 	"net"
 
 	"github.com/uber/tchannel-go"
@@ -33,5 +34,7 @@ type (
 		GetGRPCListener() net.Listener
 		GetRingpopChannel() *tchannel.Channel
 		CreateGRPCConnection(hostName string) *grpc.ClientConn
+		// GetGRPCServerTLSConfig returns the TLS config of the gRPC server, nil when TLS is disabled
+		GetGRPCServerTLSConfig() *tls.Config
 	}
 )
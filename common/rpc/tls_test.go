@@ -0,0 +1,176 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/temporalio/temporal/common/auth"
+)
+
+type (
+	tlsSuite struct {
+		*require.Assertions
+		suite.Suite
+
+		dir        string
+		caCert     *x509.Certificate
+		caKey      *ecdsa.PrivateKey
+		serverTLS  *auth.TLS
+		clientCert tls.Certificate
+	}
+)
+
+func TestTLSSuite(t *testing.T) {
+	suite.Run(t, new(tlsSuite))
+}
+
+func (s *tlsSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	var err error
+	s.dir, err = ioutil.TempDir("", "rpc-tls")
+	s.NoError(err)
+
+	s.caKey, s.caCert = s.newCertificate("test-ca", nil, nil)
+	serverKey, serverCert := s.newCertificate("localhost", s.caCert, s.caKey)
+	clientKey, clientCert := s.newCertificate("worker-1", s.caCert, s.caKey)
+
+	s.serverTLS = &auth.TLS{
+		Enabled:  true,
+		CertFile: s.writePEM("server.crt", "CERTIFICATE", serverCert.Raw),
+		KeyFile:  s.writeKey("server.key", serverKey),
+		CaFile:   s.writePEM("ca.crt", "CERTIFICATE", s.caCert.Raw),
+	}
+	s.clientCert = tls.Certificate{Certificate: [][]byte{clientCert.Raw}, PrivateKey: clientKey}
+}
+
+func (s *tlsSuite) TearDownTest() {
+	os.RemoveAll(s.dir)
+}
+
+func (s *tlsSuite) TestDisabled() {
+	config, err := NewServerTLSConfig(&auth.TLS{})
+	s.NoError(err)
+	s.Nil(config)
+}
+
+func (s *tlsSuite) TestPeerCertificate() {
+	serverConfig, err := NewServerTLSConfig(s.serverTLS)
+	s.NoError(err)
+	s.Equal(tls.RequireAndVerifyClientCert, serverConfig.ClientAuth)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(s.caCert)
+	state := s.handshake(serverConfig, &tls.Config{
+		RootCAs:      roots,
+		ServerName:   "localhost",
+		Certificates: []tls.Certificate{s.clientCert},
+	})
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+	certificate := GetPeerCertificate(ctx)
+	s.NotNil(certificate)
+	s.Equal("worker-1", certificate.Subject.CommonName)
+}
+
+func (s *tlsSuite) TestNoPeerCertificate() {
+	s.Nil(GetPeerCertificate(context.Background()))
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{})
+	s.Nil(GetPeerCertificate(ctx))
+}
+
+func (s *tlsSuite) handshake(serverConfig *tls.Config, clientConfig *tls.Config) tls.ConnectionState {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	server := tls.Server(serverConn, serverConfig)
+	client := tls.Client(clientConn, clientConfig)
+	clientErrCh := make(chan error, 1)
+	go func() {
+		clientErrCh <- client.Handshake()
+	}()
+	s.NoError(server.Handshake())
+	s.NoError(<-clientErrCh)
+	return server.ConnectionState()
+}
+
+func (s *tlsSuite) newCertificate(
+	commonName string,
+	parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey,
+) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	s.NoError(err)
+	certificate, err := x509.ParseCertificate(der)
+	s.NoError(err)
+	return key, certificate
+}
+
+func (s *tlsSuite) writeKey(name string, key *ecdsa.PrivateKey) string {
+	der, err := x509.MarshalECPrivateKey(key)
+	s.NoError(err)
+	return s.writePEM(name, "EC PRIVATE KEY", der)
+}
+
+func (s *tlsSuite) writePEM(name string, blockType string, der []byte) string {
+	path := filepath.Join(s.dir, name)
+	s.NoError(ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+	return path
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/uber-go/tally/prometheus"
 
 	"github.com/temporalio/temporal/common/auth"
+	"github.com/temporalio/temporal/common/authorization"
 	"github.com/temporalio/temporal/common/elasticsearch"
 	"github.com/temporalio/temporal/common/messaging"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
@@ -66,6 +67,8 @@ type (
 		DynamicConfigClient dynamicconfig.FileBasedClientConfig `yaml:"dynamicConfigClient"`
 		// DomainDefaults is the default config for every domain
 		DomainDefaults DomainDefaults `yaml:"domainDefaults"`
+		// Authorization is the config for the authorizer of the frontend service
+		Authorization authorization.Config `yaml:"authorization"`
 	}
 
 	// Service contains the service specific config items
@@ -98,6 +101,8 @@ type (
 		DisableLogging bool `yaml:"disableLogging"`
 		// LogLevel is the desired log level
 		LogLevel string `yaml:"logLevel"`
+		// TLS is the TLS config of the gRPC listener, client certificates are verified when a CA file is set
+		TLS auth.TLS `yaml:"tls"`
 	}
 
 	// Server contains config items that apply process-wide to all services
@@ -148,6 +153,8 @@ type (
 		Cassandra *Cassandra `yaml:"cassandra"`
 		// SQL contains the config for a SQL based datastore
 		SQL *SQL `yaml:"sql"`
+		// Memory contains the config for an in-memory datastore
+		Memory *Memory `yaml:"memory"`
 		// Custom contains the config for custom datastore implementation
 		CustomDataStoreConfig *CustomDatastoreConfig `yaml:"customDatastore"`
 		// ElasticSearch contains the config for a ElasticSearch datastore
@@ -194,6 +201,14 @@ type (
 		TLS *auth.TLS `yaml:"tls"`
 	}
 
+	// Memory is the configuration for an in-memory datastore. Datastores with the
+	// same DatabaseName share their data within a single process, which lets all
+	// the services of a onebox cluster see the same state.
+	Memory struct {
+		// DatabaseName is the name of the in-memory database to use
+		DatabaseName string `yaml:"databaseName" validate:"nonzero"`
+	}
+
 	// SQL is the configuration for connecting to a SQL backed datastore
 	SQL struct {
 		// User is the username to be used for the conn
@@ -336,13 +351,26 @@ type (
 		EnableRead bool `yaml:"enableRead"`
 		// Provider contains the config for all history archivers
 		Provider *HistoryArchiverProvider `yaml:"provider"`
+		// Envelope contains the config for compressing and encrypting archived histories
+		Envelope *ArchivalEnvelope `yaml:"envelope"`
+	}
+
+	// ArchivalEnvelope contains the config for compressing and encrypting archived blobs
+	ArchivalEnvelope struct {
+		// Compression is the compression applied to archived blobs: none, gzip or zstd
+		Compression string `yaml:"compression"`
+		// KeyFile is the path of the file with the keys used to encrypt archived blobs,
+		// blobs are not encrypted when it is empty
+		KeyFile string `yaml:"keyFile"`
 	}
 
 	// HistoryArchiverProvider contains the config for all history archivers
 	HistoryArchiverProvider struct {
-		Filestore *FilestoreArchiver `yaml:"filestore"`
-		Gstorage  *GstorageArchiver  `yaml:"gstorage"`
-		S3store   *S3Archiver        `yaml:"s3store"`
+		Filestore    *FilestoreArchiver `yaml:"filestore"`
+		Gstorage     *GstorageArchiver  `yaml:"gstorage"`
+		S3store      *S3Archiver        `yaml:"s3store"`
+		S3Compatible *S3Archiver        `yaml:"s3compatible"`
+		Azblob       *AzblobArchiver    `yaml:"azblob"`
 	}
 
 	// VisibilityArchival contains the config for visibility archival
@@ -353,13 +381,18 @@ type (
 		EnableRead bool `yaml:"enableRead"`
 		// Provider contains the config for all visibility archivers
 		Provider *VisibilityArchiverProvider `yaml:"provider"`
+		// Envelope contains the config for compressing and encrypting archived visibility records
+		Envelope *ArchivalEnvelope `yaml:"envelope"`
 	}
 
 	// VisibilityArchiverProvider contains the config for all visibility archivers
 	VisibilityArchiverProvider struct {
-		Filestore *FilestoreArchiver `yaml:"filestore"`
-		S3store   *S3Archiver        `yaml:"s3store"`
-		Gstorage  *GstorageArchiver  `yaml:"gstorage"`
+		Filestore        *FilestoreArchiver        `yaml:"filestore"`
+		IndexedFilestore *IndexedFilestoreArchiver `yaml:"indexedFilestore"`
+		S3store          *S3Archiver               `yaml:"s3store"`
+		S3Compatible     *S3Archiver               `yaml:"s3compatible"`
+		Gstorage         *GstorageArchiver         `yaml:"gstorage"`
+		Azblob           *AzblobArchiver           `yaml:"azblob"`
 	}
 
 	// FilestoreArchiver contain the config for filestore archiver
@@ -368,6 +401,15 @@ type (
 		DirMode  string `yaml:"dirMode"`
 	}
 
+	// IndexedFilestoreArchiver contains the config for the filestore visibility archiver which
+	// compacts records into indexed, time partitioned segment files
+	IndexedFilestoreArchiver struct {
+		FileMode string `yaml:"fileMode"`
+		DirMode  string `yaml:"dirMode"`
+		// SegmentSize is the number of records after which the records of a partition are compacted into a segment
+		SegmentSize int `yaml:"segmentSize"`
+	}
+
 	// GstorageArchiver contain the config for google storage archiver
 	GstorageArchiver struct {
 		CredentialsPath string `yaml:"credentialsPath"`
@@ -378,6 +420,20 @@ type (
 		Region           string  `yaml:"region"`
 		Endpoint         *string `yaml:"endpoint"`
 		S3ForcePathStyle bool    `yaml:"s3ForcePathStyle"`
+		// DisableSSL uses http when the endpoint has no scheme
+		DisableSSL bool `yaml:"disableSSL"`
+		// AccessKeyID and SecretAccessKey are static credentials,
+		// the default AWS credential chain is used when they are not set
+		AccessKeyID     string `yaml:"accessKeyID"`
+		SecretAccessKey string `yaml:"secretAccessKey"`
+	}
+
+	// AzblobArchiver contains the config for Azure Blob storage archiver
+	AzblobArchiver struct {
+		AccountName string `yaml:"accountName"`
+		AccountKey  string `yaml:"accountKey"`
+		// Endpoint is the blob service endpoint, defaults to https://<accountName>.blob.core.windows.net
+		Endpoint string `yaml:"endpoint"`
 	}
 
 	// PublicClient is config for connecting to cadence frontend
@@ -27,6 +27,8 @@ const (
 	StoreTypeSQL = "sql"
 	// StoreTypeCassandra refers to cassandra as persistence store
 	StoreTypeCassandra = "cassandra"
+	// StoreTypeMemory refers to the in-memory store, intended for tests
+	StoreTypeMemory = "memory"
 )
 
 // DefaultStoreType returns the storeType for the default persistence store
@@ -34,6 +36,9 @@ func (c *Persistence) DefaultStoreType() string {
 	if c.DataStores[c.DefaultStore].SQL != nil {
 		return StoreTypeSQL
 	}
+	if c.DataStores[c.DefaultStore].Memory != nil {
+		return StoreTypeMemory
+	}
 	return StoreTypeCassandra
 }
 
@@ -45,11 +50,17 @@ func (c *Persistence) Validate() error {
 		if !ok {
 			return fmt.Errorf("persistence config: missing config for datastore %v", st)
 		}
-		if ds.SQL == nil && ds.Cassandra == nil {
-			return fmt.Errorf("persistence config: datastore %v: must provide config for one of cassandra or sql stores", st)
+		configured := 0
+		for _, set := range []bool{ds.SQL != nil, ds.Cassandra != nil, ds.Memory != nil} {
+			if set {
+				configured++
+			}
+		}
+		if configured == 0 {
+			return fmt.Errorf("persistence config: datastore %v: must provide config for one of cassandra, sql or memory stores", st)
 		}
-		if ds.SQL != nil && ds.Cassandra != nil {
-			return fmt.Errorf("persistence config: datastore %v: only one of SQL or cassandra can be specified", st)
+		if configured > 1 {
+			return fmt.Errorf("persistence config: datastore %v: only one of SQL, cassandra or memory can be specified", st)
 		}
 		if ds.SQL != nil && ds.SQL.NumShards == 0 {
 			ds.SQL.NumShards = 1
@@ -21,6 +21,7 @@
 package config
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"sync"
@@ -42,6 +43,7 @@ type RPCFactory struct {
 	sync.Mutex
 	grpcListener   net.Listener
 	ringpopChannel *tchannel.Channel
+	serverTLS      *tls.Config
 }
 
 // NewFactory builds a new RPCFactory
@@ -106,6 +108,26 @@ func (d *RPCFactory) GetRingpopChannel() *tchannel.Channel {
 	return d.ringpopChannel
 }
 
+// GetGRPCServerTLSConfig returns cached TLS config of the gRPC server or creates one
+func (d *RPCFactory) GetGRPCServerTLSConfig() *tls.Config {
+	if !d.config.TLS.Enabled {
+		return nil
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	if d.serverTLS == nil {
+		var err error
+		d.serverTLS, err = rpc.NewServerTLSConfig(&d.config.TLS)
+		if err != nil {
+			d.logger.Fatal("Failed to create gRPC server TLS config", tag.Error(err), tag.Service(d.serviceName))
+		}
+	}
+
+	return d.serverTLS
+}
+
 func (d *RPCFactory) getListenIP() net.IP {
 	if d.config.BindOnLocalHost && len(d.config.BindOnIP) > 0 {
 		d.logger.Fatal("ListenIP failed, bindOnLocalHost and bindOnIP are mutually exclusive")
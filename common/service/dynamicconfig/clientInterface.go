@@ -42,6 +42,9 @@ type Client interface {
 	GetDurationValue(
 		name Key, filters map[Filter]interface{}, defaultValue time.Duration,
 	) (time.Duration, error)
+	// ListValues returns the values of all the keys set in the dynamic configuration system which
+	// apply to the filters, keys without a value for the filters are left out
+	ListValues(filters map[Filter]interface{}) (map[Key]interface{}, error)
 	// UpdateValue takes value as map and updates by overriding. It doesn't support update with filters.
 	UpdateValue(name Key, value interface{}) error
 }
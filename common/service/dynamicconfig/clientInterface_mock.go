@@ -177,6 +177,21 @@ func (mr *MockClientMockRecorder) GetDurationValue(name, filters, defaultValue i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDurationValue", reflect.TypeOf((*MockClient)(nil).GetDurationValue), name, filters, defaultValue)
 }
 
+// ListValues mocks base method
+func (m *MockClient) ListValues(filters map[Filter]interface{}) (map[Key]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListValues", filters)
+	ret0, _ := ret[0].(map[Key]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListValues indicates an expected call of ListValues
+func (mr *MockClientMockRecorder) ListValues(filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListValues", reflect.TypeOf((*MockClient)(nil).ListValues), filters)
+}
+
 // UpdateValue mocks base method
 func (m *MockClient) UpdateValue(name Key, value interface{}) error {
 	m.ctrl.T.Helper()
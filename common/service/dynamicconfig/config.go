@@ -86,9 +86,24 @@ type IntPropertyFnWithDomainFilter func(domain string) int
 // IntPropertyFnWithTaskListInfoFilters is a wrapper to get int property from dynamic config with three filters: domain, taskList, taskType
 type IntPropertyFnWithTaskListInfoFilters func(domain string, taskList string, taskType int32) int
 
+// IntPropertyFnWithAPIFilter is a wrapper to get int property from dynamic config with API name as filter
+type IntPropertyFnWithAPIFilter func(api string) int
+
+// IntPropertyFnWithAPIFilters is a wrapper to get int property from dynamic config with three filters: domain, taskList, api
+type IntPropertyFnWithAPIFilters func(domain string, taskList string, api string) int
+
+// IntPropertyFnWithShardIDFilter is a wrapper to get int property from dynamic config with shardID as filter
+type IntPropertyFnWithShardIDFilter func(shardID int32) int
+
+// IntPropertyFnWithWorkflowTypeFilter is a wrapper to get int property from dynamic config with domain and workflow type as filters
+type IntPropertyFnWithWorkflowTypeFilter func(domain string, workflowType string) int
+
 // FloatPropertyFn is a wrapper to get float property from dynamic config
 type FloatPropertyFn func(opts ...FilterOption) float64
 
+// FloatPropertyFnWithAPIFilters is a wrapper to get float property from dynamic config with three filters: domain, taskList, api
+type FloatPropertyFnWithAPIFilters func(domain string, taskList string, api string) float64
+
 // DurationPropertyFn is a wrapper to get duration property from dynamic config
 type DurationPropertyFn func(opts ...FilterOption) time.Duration
 
@@ -107,6 +122,9 @@ type StringPropertyFn func(opts ...FilterOption) string
 // MapPropertyFn is a wrapper to get map property from dynamic config
 type MapPropertyFn func(opts ...FilterOption) map[string]interface{}
 
+// MapPropertyFnWithTaskListInfoFilters is a wrapper to get map property from dynamic config with three filters: domain, taskList, taskType
+type MapPropertyFnWithTaskListInfoFilters func(domain string, taskList string, taskType int32) map[string]interface{}
+
 // StringPropertyFnWithDomainFilter is a wrapper to get string property from dynamic config
 type StringPropertyFnWithDomainFilter func(domain string) string
 
@@ -177,6 +195,66 @@ func (c *Collection) GetIntPropertyFilteredByTaskListInfo(key Key, defaultValue
 	}
 }
 
+// GetIntPropertyFilteredByAPI gets property with API name filter and asserts that it's an integer
+func (c *Collection) GetIntPropertyFilteredByAPI(key Key, defaultValue int) IntPropertyFnWithAPIFilter {
+	return func(api string) int {
+		val, err := c.client.GetIntValue(key, getFilterMap(APIFilter(api)), defaultValue)
+		if err != nil {
+			c.logError(key, err)
+		}
+		c.logValue(key, val, defaultValue, intCompareEquals)
+		return val
+	}
+}
+
+// GetIntPropertyFilteredByAPIInfo gets property with domain, task list and API name as filters and asserts
+// that it's an integer. The most specific value set for the filters wins, e.g. from (domain, taskList, api)
+// to (domain, api) to (api).
+func (c *Collection) GetIntPropertyFilteredByAPIInfo(key Key, defaultValue int) IntPropertyFnWithAPIFilters {
+	return func(domain string, taskList string, api string) int {
+		val, err := c.client.GetIntValue(
+			key,
+			getFilterMap(DomainFilter(domain), TaskListFilter(taskList), APIFilter(api)),
+			defaultValue,
+		)
+		if err != nil {
+			c.logError(key, err)
+		}
+		c.logValue(key, val, defaultValue, intCompareEquals)
+		return val
+	}
+}
+
+// GetIntPropertyFilteredByShardID gets property with shardID as filter and asserts that it's an integer
+func (c *Collection) GetIntPropertyFilteredByShardID(key Key, defaultValue int) IntPropertyFnWithShardIDFilter {
+	return func(shardID int32) int {
+		val, err := c.client.GetIntValue(key, getFilterMap(ShardIDFilter(shardID)), defaultValue)
+		if err != nil {
+			c.logError(key, err)
+		}
+		c.logValue(key, val, defaultValue, intCompareEquals)
+		return val
+	}
+}
+
+// GetIntPropertyFilteredByWorkflowType gets property with domain and workflow type as filters and asserts that
+// it's an integer. The most specific value set for the filters wins, from (domain, workflowType) to
+// (workflowType) to (domain).
+func (c *Collection) GetIntPropertyFilteredByWorkflowType(key Key, defaultValue int) IntPropertyFnWithWorkflowTypeFilter {
+	return func(domain string, workflowType string) int {
+		val, err := c.client.GetIntValue(
+			key,
+			getFilterMap(DomainFilter(domain), WorkflowTypeFilter(workflowType)),
+			defaultValue,
+		)
+		if err != nil {
+			c.logError(key, err)
+		}
+		c.logValue(key, val, defaultValue, intCompareEquals)
+		return val
+	}
+}
+
 // GetFloat64Property gets property and asserts that it's a float64
 func (c *Collection) GetFloat64Property(key Key, defaultValue float64) FloatPropertyFn {
 	return func(opts ...FilterOption) float64 {
@@ -189,6 +267,24 @@ func (c *Collection) GetFloat64Property(key Key, defaultValue float64) FloatProp
 	}
 }
 
+// GetFloat64PropertyFilteredByAPIInfo gets property with domain, task list and API name as filters and asserts
+// that it's a float64. The most specific value set for the filters wins, e.g. from (domain, taskList, api)
+// to (domain, api) to (api).
+func (c *Collection) GetFloat64PropertyFilteredByAPIInfo(key Key, defaultValue float64) FloatPropertyFnWithAPIFilters {
+	return func(domain string, taskList string, api string) float64 {
+		val, err := c.client.GetFloatValue(
+			key,
+			getFilterMap(DomainFilter(domain), TaskListFilter(taskList), APIFilter(api)),
+			defaultValue,
+		)
+		if err != nil {
+			c.logError(key, err)
+		}
+		c.logValue(key, val, defaultValue, float64CompareEquals)
+		return val
+	}
+}
+
 // GetDurationProperty gets property and asserts that it's a duration
 func (c *Collection) GetDurationProperty(key Key, defaultValue time.Duration) DurationPropertyFn {
 	return func(opts ...FilterOption) time.Duration {
@@ -265,6 +361,22 @@ func (c *Collection) GetMapProperty(key Key, defaultValue map[string]interface{}
 	}
 }
 
+// GetMapPropertyFilteredByTaskListInfo gets property with taskListInfo as filters and asserts that it's a map
+func (c *Collection) GetMapPropertyFilteredByTaskListInfo(key Key, defaultValue map[string]interface{}) MapPropertyFnWithTaskListInfoFilters {
+	return func(domain string, taskList string, taskType int32) map[string]interface{} {
+		val, err := c.client.GetMapValue(
+			key,
+			getFilterMap(DomainFilter(domain), TaskListFilter(taskList), TaskTypeFilter(taskType)),
+			defaultValue,
+		)
+		if err != nil {
+			c.logError(key, err)
+		}
+		c.logValue(key, val, defaultValue, reflect.DeepEqual)
+		return val
+	}
+}
+
 // GetStringPropertyFnWithDomainFilter gets property with domain filter and asserts that its domain
 func (c *Collection) GetStringPropertyFnWithDomainFilter(key Key, defaultValue string) StringPropertyFnWithDomainFilter {
 	return func(domain string) string {
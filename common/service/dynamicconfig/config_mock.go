@@ -39,6 +39,16 @@ func GetIntPropertyFilteredByTaskListInfo(value int) func(domain string, taskLis
 	return func(domain string, taskList string, taskType int32) int { return value }
 }
 
+// GetIntPropertyFilteredByShardID returns value as IntPropertyFnWithShardIDFilter
+func GetIntPropertyFilteredByShardID(value int) func(shardID int32) int {
+	return func(shardID int32) int { return value }
+}
+
+// GetIntPropertyFilteredByWorkflowType returns value as IntPropertyFnWithWorkflowTypeFilter
+func GetIntPropertyFilteredByWorkflowType(value int) func(domain string, workflowType string) int {
+	return func(domain string, workflowType string) int { return value }
+}
+
 // GetFloatPropertyFn returns value as FloatPropertyFn
 func GetFloatPropertyFn(value float64) func(opts ...FilterOption) float64 {
 	return func(...FilterOption) float64 { return value }
@@ -54,6 +64,11 @@ func GetBoolPropertyFnFilteredByDomain(value bool) func(domain string) bool {
 	return func(domain string) bool { return value }
 }
 
+// GetBoolPropertyFnFilteredByTaskListInfo returns value as BoolPropertyFnWithTaskListInfoFilters
+func GetBoolPropertyFnFilteredByTaskListInfo(value bool) func(domain string, taskList string, taskType int32) bool {
+	return func(domain string, taskList string, taskType int32) bool { return value }
+}
+
 // GetDurationPropertyFnFilteredByDomain returns value as DurationPropertyFnFilteredByDomain
 func GetDurationPropertyFnFilteredByDomain(value time.Duration) func(domain string) time.Duration {
 	return func(domain string) time.Duration { return value }
@@ -78,3 +93,8 @@ func GetStringPropertyFn(value string) func(opts ...FilterOption) string {
 func GetMapPropertyFn(value map[string]interface{}) func(opts ...FilterOption) map[string]interface{} {
 	return func(...FilterOption) map[string]interface{} { return value }
 }
+
+// GetMapPropertyFnFilteredByTaskListInfo returns value as MapPropertyFnWithTaskListInfoFilters
+func GetMapPropertyFnFilteredByTaskListInfo(value map[string]interface{}) func(domain string, taskList string, taskType int32) map[string]interface{} {
+	return func(domain string, taskList string, taskType int32) map[string]interface{} { return value }
+}
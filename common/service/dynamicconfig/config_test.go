@@ -114,6 +114,15 @@ func (mc *inMemoryClient) GetDurationValue(
 	return defaultValue, errors.New("unable to find key")
 }
 
+func (mc *inMemoryClient) ListValues(filters map[Filter]interface{}) (map[Key]interface{}, error) {
+	v := mc.globalValues.Load().(map[Key]interface{})
+	values := make(map[Key]interface{}, len(v))
+	for key, val := range v {
+		values[key] = val
+	}
+	return values, nil
+}
+
 func (mc *inMemoryClient) UpdateValue(key Key, value interface{}) error {
 	mc.SetValue(key, value)
 	return nil
@@ -246,6 +255,19 @@ func (s *configSuite) TestGetMapProperty() {
 	s.Equal("321", value()["testKey"])
 }
 
+func (s *configSuite) TestGetMapPropertyFilteredByTaskListInfo() {
+	key := testGetMapPropertyFilteredByTaskListInfoKey
+	domain := "testDomain"
+	taskList := "testTaskList"
+	val := map[string]interface{}{
+		"testKey": 123,
+	}
+	value := s.cln.GetMapPropertyFilteredByTaskListInfo(key, nil)
+	s.Nil(value(domain, taskList, 0))
+	s.client.SetValue(key, val)
+	s.Equal(val, value(domain, taskList, 0))
+}
+
 func (s *configSuite) TestUpdateConfig() {
 	key := testGetBoolPropertyKey
 	value := s.cln.GetBoolProperty(key, true)
@@ -265,6 +287,14 @@ func TestDynamicConfigKeyIsMapped(t *testing.T) {
 	}
 }
 
+func TestDynamicConfigKeyHasValueType(t *testing.T) {
+	for i := unknownKey + 1; i < lastKeyForTest; i++ {
+		valueType, ok := keyTypes[i]
+		require.True(t, ok, i.String())
+		require.NotEqual(t, unknownType, valueType)
+	}
+}
+
 func TestDynamicConfigFilterTypeIsMapped(t *testing.T) {
 	require.Equal(t, int(lastFilterTypeForTest), len(filters))
 	for i := unknownFilter; i < lastFilterTypeForTest; i++ {
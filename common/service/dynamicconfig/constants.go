@@ -31,6 +31,23 @@ func (k Key) String() string {
 	return keyName
 }
 
+// ParseKey returns the key of the given name, or unknownKey if there is none
+func ParseKey(name string) Key {
+	if key, ok := keyNames[name]; ok {
+		return key
+	}
+	return unknownKey
+}
+
+// Mapping from keyName to Key, built from keys
+var keyNames = func() map[string]Key {
+	names := make(map[string]Key, len(keys))
+	for key, name := range keys {
+		names[name] = key
+	}
+	return names
+}()
+
 // Mapping from Key to keyName, where keyName are used dynamic config source.
 var keys = map[Key]string{
 	unknownKey: "unknownKey",
@@ -48,6 +65,10 @@ var keys = map[Key]string{
 	testGetIntPropertyFilteredByTaskListInfoKey:      "testGetIntPropertyFilteredByTaskListInfoKey",
 	testGetDurationPropertyFilteredByTaskListInfoKey: "testGetDurationPropertyFilteredByTaskListInfoKey",
 	testGetBoolPropertyFilteredByTaskListInfoKey:     "testGetBoolPropertyFilteredByTaskListInfoKey",
+	testGetFloat64PropertyFilteredByAPIInfoKey:       "testGetFloat64PropertyFilteredByAPIInfoKey",
+	testGetIntPropertyFilteredByShardIDKey:           "testGetIntPropertyFilteredByShardIDKey",
+	testGetIntPropertyFilteredByWorkflowTypeKey:      "testGetIntPropertyFilteredByWorkflowTypeKey",
+	testGetMapPropertyFilteredByTaskListInfoKey:      "testGetMapPropertyFilteredByTaskListInfoKey",
 
 	// system settings
 	EnableGlobalDomain:                  "system.enableGlobalDomain",
@@ -67,6 +88,7 @@ var keys = map[Key]string{
 	MaxDecisionStartToCloseSeconds:      "system.maxDecisionStartToCloseSeconds",
 	DisallowQuery:                       "system.disallowQuery",
 	EnableBatcher:                       "worker.enableBatcher",
+	EnableScheduler:                     "worker.enableScheduler",
 	EnableParentClosePolicyWorker:       "system.enableParentClosePolicyWorker",
 	EnableStickyQuery:                   "system.enableStickyQuery",
 
@@ -80,26 +102,37 @@ var keys = map[Key]string{
 	MaxIDLengthLimit:       "limit.maxIDLength",
 
 	// frontend settings
-	FrontendPersistenceMaxQPS:             "frontend.persistenceMaxQPS",
-	FrontendVisibilityMaxPageSize:         "frontend.visibilityMaxPageSize",
-	FrontendVisibilityListMaxQPS:          "frontend.visibilityListMaxQPS",
-	FrontendESVisibilityListMaxQPS:        "frontend.esVisibilityListMaxQPS",
-	FrontendMaxBadBinaries:                "frontend.maxBadBinaries",
-	FrontendESIndexMaxResultWindow:        "frontend.esIndexMaxResultWindow",
-	FrontendHistoryMaxPageSize:            "frontend.historyMaxPageSize",
-	FrontendRPS:                           "frontend.rps",
-	FrontendDomainRPS:                     "frontend.domainrps",
-	FrontendHistoryMgrNumConns:            "frontend.historyMgrNumConns",
-	DisableListVisibilityByFilter:         "frontend.disableListVisibilityByFilter",
-	FrontendThrottledLogRPS:               "frontend.throttledLogRPS",
-	EnableClientVersionCheck:              "frontend.enableClientVersionCheck",
-	ValidSearchAttributes:                 "frontend.validSearchAttributes",
-	SearchAttributesNumberOfKeysLimit:     "frontend.searchAttributesNumberOfKeysLimit",
-	SearchAttributesSizeOfValueLimit:      "frontend.searchAttributesSizeOfValueLimit",
-	SearchAttributesTotalSizeLimit:        "frontend.searchAttributesTotalSizeLimit",
-	VisibilityArchivalQueryMaxPageSize:    "frontend.visibilityArchivalQueryMaxPageSize",
-	VisibilityArchivalQueryMaxRangeInDays: "frontend.visibilityArchivalQueryMaxRangeInDays",
-	VisibilityArchivalQueryMaxQPS:         "frontend.visibilityArchivalQueryMaxQPS",
+	FrontendPersistenceMaxQPS:                "frontend.persistenceMaxQPS",
+	FrontendVisibilityMaxPageSize:            "frontend.visibilityMaxPageSize",
+	FrontendVisibilityListMaxQPS:             "frontend.visibilityListMaxQPS",
+	FrontendESVisibilityListMaxQPS:           "frontend.esVisibilityListMaxQPS",
+	FrontendMaxBadBinaries:                   "frontend.maxBadBinaries",
+	FrontendESIndexMaxResultWindow:           "frontend.esIndexMaxResultWindow",
+	FrontendHistoryMaxPageSize:               "frontend.historyMaxPageSize",
+	FrontendRPS:                              "frontend.rps",
+	FrontendDomainRPS:                        "frontend.domainrps",
+	FrontendDomainBurst:                      "frontend.domainBurst",
+	FrontendAPIRPS:                           "frontend.apiRPS",
+	FrontendAPIBurst:                         "frontend.apiBurst",
+	FrontendAPIPriority:                      "frontend.apiPriority",
+	FrontendNormalPriorityRPSRatio:           "frontend.normalPriorityRPSRatio",
+	FrontendLowPriorityRPSRatio:              "frontend.lowPriorityRPSRatio",
+	FrontendRateLimitMaxWait:                 "frontend.rateLimitMaxWait",
+	FrontendGlobalRPS:                        "frontend.globalRPS",
+	FrontendGlobalDomainRPS:                  "frontend.globalDomainrps",
+	FrontendGlobalRateLimitRebalance:         "frontend.globalRateLimitRebalance",
+	FrontendGlobalRateLimitRebalanceInterval: "frontend.globalRateLimitRebalanceInterval",
+	FrontendHistoryMgrNumConns:               "frontend.historyMgrNumConns",
+	DisableListVisibilityByFilter:            "frontend.disableListVisibilityByFilter",
+	FrontendThrottledLogRPS:                  "frontend.throttledLogRPS",
+	EnableClientVersionCheck:                 "frontend.enableClientVersionCheck",
+	ValidSearchAttributes:                    "frontend.validSearchAttributes",
+	SearchAttributesNumberOfKeysLimit:        "frontend.searchAttributesNumberOfKeysLimit",
+	SearchAttributesSizeOfValueLimit:         "frontend.searchAttributesSizeOfValueLimit",
+	SearchAttributesTotalSizeLimit:           "frontend.searchAttributesTotalSizeLimit",
+	VisibilityArchivalQueryMaxPageSize:       "frontend.visibilityArchivalQueryMaxPageSize",
+	VisibilityArchivalQueryMaxRangeInDays:    "frontend.visibilityArchivalQueryMaxRangeInDays",
+	VisibilityArchivalQueryMaxQPS:            "frontend.visibilityArchivalQueryMaxQPS",
 
 	// matching settings
 	MatchingRPS:                             "matching.rps",
@@ -121,6 +154,21 @@ var keys = map[Key]string{
 	MatchingForwarderMaxOutstandingTasks:    "matching.forwarderMaxOutstandingTasks",
 	MatchingForwarderMaxRatePerSecond:       "matching.forwarderMaxRatePerSecond",
 	MatchingForwarderMaxChildrenPerNode:     "matching.forwarderMaxChildrenPerNode",
+	MatchingForwarderMinRatePerSecond:       "matching.forwarderMinRatePerSecond",
+	MatchingForwarderRateRecoveryPerSecond:  "matching.forwarderRateRecoveryPerSecond",
+	MatchingForwarderRateBackoffRatio:       "matching.forwarderRateBackoffRatio",
+	MatchingEnablePartitionAutoScaling:      "matching.enablePartitionAutoScaling",
+	MatchingPartitionAutoScalingInterval:    "matching.partitionAutoScalingInterval",
+	MatchingMaxTasklistPartitions:           "matching.maxTasklistPartitions",
+	MatchingPartitionTargetAddRate:          "matching.partitionTargetAddRate",
+	MatchingPartitionTargetBacklog:          "matching.partitionTargetBacklog",
+	MatchingMinPollersPerPartition:          "matching.minPollersPerPartition",
+	MatchingPartitionConfigRefreshInterval:  "matching.partitionConfigRefreshInterval",
+	MatchingTaskPriorityWeightRatio:         "matching.taskPriorityWeightRatio",
+	MatchingEnableFairnessDispatch:          "matching.enableFairnessDispatch",
+	MatchingFairnessKeyWeights:              "matching.fairnessKeyWeights",
+	MatchingFairnessKeyDefaultWeight:        "matching.fairnessKeyDefaultWeight",
+	MatchingMaxFairnessKeys:                 "matching.maxFairnessKeys",
 
 	// history settings
 	HistoryRPS:                                            "history.rps",
@@ -137,6 +185,15 @@ var keys = map[Key]string{
 	EventsCacheTTL:                                        "history.eventsCacheTTL",
 	AcquireShardInterval:                                  "history.acquireShardInterval",
 	AcquireShardConcurrency:                               "history.acquireShardConcurrency",
+	EnableGracefulShardHandoff:                            "history.enableGracefulShardHandoff",
+	ShardLeaseDuration:                                    "history.shardLeaseDuration",
+	ShardHandoffTimeout:                                   "history.shardHandoffTimeout",
+	ShardLoadWindow:                                       "history.shardLoadWindow",
+	HotShardRPSThreshold:                                  "history.hotShardRPSThreshold",
+	EnableHotWorkflowThrottling:                           "history.enableHotWorkflowThrottling",
+	HotWorkflowShareThreshold:                             "history.hotWorkflowShareThreshold",
+	HotWorkflowMaxThrottled:                               "history.hotWorkflowMaxThrottled",
+	HotWorkflowThrottledRPS:                               "history.hotWorkflowThrottledRPS",
 	StandbyClusterDelay:                                   "history.standbyClusterDelay",
 	StandbyTaskMissingEventsResendDelay:                   "history.standbyTaskMissingEventsResendDelay",
 	StandbyTaskMissingEventsDiscardDelay:                  "history.standbyTaskMissingEventsDiscardDelay",
@@ -183,6 +240,9 @@ var keys = map[Key]string{
 	HistoryMgrNumConns:                                    "history.historyMgrNumConns",
 	MaximumBufferedEventsBatch:                            "history.maximumBufferedEventsBatch",
 	MaximumSignalsPerExecution:                            "history.maximumSignalsPerExecution",
+	WorkflowSignalRPS:                                     "history.workflowSignalRPS",
+	WorkflowDecisionCompletionRPS:                         "history.workflowDecisionCompletionRPS",
+	WorkflowActivityScheduleRPS:                           "history.workflowActivityScheduleRPS",
 	ShardUpdateMinInterval:                                "history.shardUpdateMinInterval",
 	ShardSyncMinInterval:                                  "history.shardSyncMinInterval",
 	ShardSyncTimerJitterCoefficient:                       "history.shardSyncMinInterval",
@@ -259,6 +319,10 @@ const (
 	testGetIntPropertyFilteredByTaskListInfoKey
 	testGetDurationPropertyFilteredByTaskListInfoKey
 	testGetBoolPropertyFilteredByTaskListInfoKey
+	testGetFloat64PropertyFilteredByAPIInfoKey
+	testGetIntPropertyFilteredByShardIDKey
+	testGetIntPropertyFilteredByWorkflowTypeKey
+	testGetMapPropertyFilteredByTaskListInfoKey
 
 	// EnableGlobalDomain is key for enable global domain
 	EnableGlobalDomain
@@ -333,6 +397,31 @@ const (
 	FrontendRPS
 	// FrontendDomainRPS is workflow domain rate limit per second
 	FrontendDomainRPS
+	// FrontendDomainBurst is workflow domain rate limit burst, zero means the same as FrontendDomainRPS
+	FrontendDomainBurst
+	// FrontendAPIRPS is the rate limit per second of an API, optionally narrowed by domain and task list, zero means no limit
+	FrontendAPIRPS
+	// FrontendAPIBurst is the rate limit burst of an API, optionally narrowed by domain and task list, zero means the same as FrontendAPIRPS
+	FrontendAPIBurst
+	// FrontendAPIPriority overrides the priority of an API (0:High, 1:Normal, 2:Low)
+	FrontendAPIPriority
+	// FrontendNormalPriorityRPSRatio is the fraction of FrontendRPS normal and low priority APIs can use together
+	FrontendNormalPriorityRPSRatio
+	// FrontendLowPriorityRPSRatio is the fraction of FrontendRPS low priority APIs can use
+	FrontendLowPriorityRPSRatio
+	// FrontendRateLimitMaxWait is the max time a request waits for a rate limit token before being rejected, zero means no waiting
+	FrontendRateLimitMaxWait
+	// FrontendGlobalRPS is workflow rate limit per second of the whole cluster, which is divided among the
+	// frontend hosts. Zero means FrontendRPS is used instead
+	FrontendGlobalRPS
+	// FrontendGlobalDomainRPS is workflow domain rate limit per second of the whole cluster, which is divided
+	// among the frontend hosts. Zero means FrontendDomainRPS is used instead
+	FrontendGlobalDomainRPS
+	// FrontendGlobalRateLimitRebalance divides the global rate limits by the demand observed by each frontend
+	// host, overall and for each domain, instead of equally
+	FrontendGlobalRateLimitRebalance
+	// FrontendGlobalRateLimitRebalanceInterval is the interval at which frontend hosts exchange their demand
+	FrontendGlobalRateLimitRebalanceInterval
 	// FrontendHistoryMgrNumConns is for persistence cluster.NumConns
 	FrontendHistoryMgrNumConns
 	// FrontendThrottledLogRPS is the rate limit on number of log messages emitted per second for throttled logger
@@ -396,6 +485,36 @@ const (
 	MatchingForwarderMaxRatePerSecond
 	// MatchingForwarderMaxChildrenPerNode is the max number of children per node in the task list partition tree
 	MatchingForwarderMaxChildrenPerNode
+	// MatchingForwarderMinRatePerSecond is the rate the forwarder never backs off below when the parent partition is busy
+	MatchingForwarderMinRatePerSecond
+	// MatchingForwarderRateRecoveryPerSecond is the forwarding rate recovered per second after backing off
+	MatchingForwarderRateRecoveryPerSecond
+	// MatchingForwarderRateBackoffRatio is the ratio the forwarding rate is multiplied with when the parent partition is busy
+	MatchingForwarderRateBackoffRatio
+	// MatchingEnablePartitionAutoScaling enables the root partition of a task list to scale the number of partitions
+	MatchingEnablePartitionAutoScaling
+	// MatchingPartitionAutoScalingInterval is the interval at which the root partition re-evaluates the number of partitions
+	MatchingPartitionAutoScalingInterval
+	// MatchingMaxTasklistPartitions is the max number of partitions a task list is scaled up to
+	MatchingMaxTasklistPartitions
+	// MatchingPartitionTargetAddRate is the add task rate per second a single partition is sized for
+	MatchingPartitionTargetAddRate
+	// MatchingPartitionTargetBacklog is the backlog a single partition is sized for
+	MatchingPartitionTargetBacklog
+	// MatchingMinPollersPerPartition is the min number of pollers every partition must have when scaling up
+	MatchingMinPollersPerPartition
+	// MatchingPartitionConfigRefreshInterval is the interval at which matching clients refresh the partition counts of a task list
+	MatchingPartitionConfigRefreshInterval
+	// MatchingTaskPriorityWeightRatio is the dispatch weight of a task priority relative to the next lower priority
+	MatchingTaskPriorityWeightRatio
+	// MatchingEnableFairnessDispatch enables sharing the backlog dispatch of a task list among fairness keys
+	MatchingEnableFairnessDispatch
+	// MatchingFairnessKeyWeights is the map of fairness key to its dispatch weight
+	MatchingFairnessKeyWeights
+	// MatchingFairnessKeyDefaultWeight is the dispatch weight of fairness keys without a configured weight
+	MatchingFairnessKeyDefaultWeight
+	// MatchingMaxFairnessKeys is the max number of fairness keys with a backlog of their own in a task list
+	MatchingMaxFairnessKeys
 
 	// key for history
 
@@ -425,6 +544,24 @@ const (
 	AcquireShardInterval
 	// AcquireShardConcurrency is number of goroutines that can be used to acquire shards in the shard controller.
 	AcquireShardConcurrency
+	// EnableGracefulShardHandoff is whether a history host drains and releases its shards before they are acquired by another host
+	EnableGracefulShardHandoff
+	// ShardLeaseDuration is how long a history host may write to a shard after it last renewed its lease on it
+	ShardLeaseDuration
+	// ShardHandoffTimeout is the max time a history host waits for the previous owner to release a shard before stealing it
+	ShardHandoffTimeout
+	// ShardLoadWindow is the length of the window over which the load of a history shard is measured
+	ShardLoadWindow
+	// HotShardRPSThreshold is the shard RPS above which a history shard is considered hot, 0 disables hot shard detection
+	HotShardRPSThreshold
+	// EnableHotWorkflowThrottling is whether the workflows issuing most of the requests of a hot shard are throttled
+	EnableHotWorkflowThrottling
+	// HotWorkflowShareThreshold is the share of the requests of a hot shard above which a workflow is throttled
+	HotWorkflowShareThreshold
+	// HotWorkflowMaxThrottled is the max number of workflows throttled on a hot shard
+	HotWorkflowMaxThrottled
+	// HotWorkflowThrottledRPS is the RPS a throttled workflow is limited to
+	HotWorkflowThrottledRPS
 	// StandbyClusterDelay is the artificial delay added to standby cluster's view of active cluster's time
 	StandbyClusterDelay
 	// StandbyTaskMissingEventsResendDelay is the amount of time standby cluster's will wait (if events are missing)
@@ -519,6 +656,12 @@ const (
 	MaximumBufferedEventsBatch
 	// MaximumSignalsPerExecution is max number of signals supported by single execution
 	MaximumSignalsPerExecution
+	// WorkflowSignalRPS is the max rate at which a single workflow can be signaled by domain and workflow type, 0 means unlimited
+	WorkflowSignalRPS
+	// WorkflowDecisionCompletionRPS is the max rate at which a single workflow can complete decision tasks by domain and workflow type, 0 means unlimited
+	WorkflowDecisionCompletionRPS
+	// WorkflowActivityScheduleRPS is the max rate at which a single workflow can schedule activities by domain and workflow type, 0 means unlimited
+	WorkflowActivityScheduleRPS
 	// ShardUpdateMinInterval is the minimal time interval which the shard info can be updated
 	ShardUpdateMinInterval
 	// ShardSyncMinInterval is the minimal time interval which the shard info should be sync to remote
@@ -612,6 +755,8 @@ const (
 	ExecutionsScannerEnabled
 	// EnableBatcher decides whether start batcher in our worker
 	EnableBatcher
+	// EnableScheduler decides whether start scheduler in our worker, the frontend only creates schedules when it is enabled
+	EnableScheduler
 	// EnableParentClosePolicyWorker decides whether or not enable system workers for processing parent close policy task
 	EnableParentClosePolicyWorker
 	// EnableStickyQuery indicates if sticky query should be enabled per domain
@@ -656,7 +801,7 @@ const (
 type Filter int
 
 func (f Filter) String() string {
-	if f <= unknownFilter || f > TaskType {
+	if f <= unknownFilter || f > WorkflowType {
 		return filters[unknownFilter]
 	}
 	return filters[f]
@@ -667,6 +812,9 @@ var filters = []string{
 	"domainName",
 	"taskListName",
 	"taskType",
+	"apiName",
+	"shardID",
+	"workflowType",
 }
 
 const (
@@ -677,6 +825,12 @@ const (
 	TaskListName
 	// TaskType is the task type (0:Decision, 1:Activity)
 	TaskType
+	// APIName is the name of the frontend API
+	APIName
+	// ShardID is the history shard ID
+	ShardID
+	// WorkflowType is the workflow type name
+	WorkflowType
 
 	// lastFilterTypeForTest must be the last one in this const group for testing purpose
 	lastFilterTypeForTest
@@ -705,3 +859,34 @@ func TaskTypeFilter(taskType int32) FilterOption {
 		filterMap[TaskType] = taskType
 	}
 }
+
+// APIFilter filters by API name
+func APIFilter(name string) FilterOption {
+	return func(filterMap map[Filter]interface{}) {
+		filterMap[APIName] = name
+	}
+}
+
+// ShardIDFilter filters by shard ID
+func ShardIDFilter(shardID int32) FilterOption {
+	return func(filterMap map[Filter]interface{}) {
+		filterMap[ShardID] = shardID
+	}
+}
+
+// WorkflowTypeFilter filters by workflow type name
+func WorkflowTypeFilter(name string) FilterOption {
+	return func(filterMap map[Filter]interface{}) {
+		filterMap[WorkflowType] = name
+	}
+}
+
+// ParseFilter returns the filter of the given name, or unknownFilter if there is none
+func ParseFilter(name string) Filter {
+	for f := DomainName; f < lastFilterTypeForTest; f++ {
+		if filters[f] == name {
+			return f
+		}
+	}
+	return unknownFilter
+}
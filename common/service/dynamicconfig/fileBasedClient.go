@@ -185,7 +185,23 @@ func (fc *fileBasedClient) GetDurationValue(
 	return durationVal, nil
 }
 
+func (fc *fileBasedClient) ListValues(filters map[Filter]interface{}) (map[Key]interface{}, error) {
+	values := fc.values.Load().(map[string][]*constrainedValue)
+	result := make(map[Key]interface{}, len(values))
+	for keyName := range values {
+		key := ParseKey(keyName)
+		if val, err := fc.getValueWithFilters(key, filters, nil); err == nil && val != nil {
+			result[key] = val
+		}
+	}
+	return result, nil
+}
+
 func (fc *fileBasedClient) UpdateValue(name Key, value interface{}) error {
+	if err := validateValue(name, value); err != nil {
+		return err
+	}
+
 	keyName := keys[name]
 	currentValues := make(map[string][]*constrainedValue)
 
@@ -254,15 +270,26 @@ func (fc *fileBasedClient) storeValues(newValues map[string][]*constrainedValue)
 		}
 	}
 
+	// reject the whole config if any of the values is invalid, so that a typo does not
+	// silently fall back to the default value and the last good config is kept
+	if err := validateValues(newValues); err != nil {
+		return fmt.Errorf("invalid dynamic config: %v", err)
+	}
+
 	fc.values.Store(newValues)
 	fc.logger.Info("Updated dynamic config")
 	return nil
 }
 
+// getValueWithFilters returns the most specific value whose constraints all match the filters,
+// or the value without constraints if there is none. Values with more constraints are more specific,
+// ties are broken by the most specific filter, e.g. a value constrained by workflow type wins over
+// a value constrained by domain.
 func (fc *fileBasedClient) getValueWithFilters(key Key, filters map[Filter]interface{}, defaultValue interface{}) (interface{}, error) {
 	keyName := keys[key]
 	values := fc.values.Load().(map[string][]*constrainedValue)
 	found := false
+	var matched *constrainedValue
 	for _, constrainedValue := range values[keyName] {
 		if len(constrainedValue.Constraints) == 0 {
 			// special handling for default value (value without any constraints)
@@ -270,30 +297,56 @@ func (fc *fileBasedClient) getValueWithFilters(key Key, filters map[Filter]inter
 			found = true
 			continue
 		}
-		if match(constrainedValue, filters) {
-			return constrainedValue.Value, nil
+		if match(constrainedValue, filters) && (matched == nil || moreSpecific(constrainedValue, matched)) {
+			matched = constrainedValue
 		}
 	}
+	if matched != nil {
+		return matched.Value, nil
+	}
 	if !found {
 		return defaultValue, errors.New("unable to find key")
 	}
 	return defaultValue, nil
 }
 
-// match will return true if the constraints matches the filters exactly
+// match will return true if all the constraints match the filters
 func match(v *constrainedValue, filters map[Filter]interface{}) bool {
-	if len(v.Constraints) != len(filters) {
+	if len(v.Constraints) == 0 || len(v.Constraints) > len(filters) {
 		return false
 	}
 
-	for filter, filterValue := range filters {
-		if v.Constraints[filter.String()] != filterValue {
+	for constraint, constraintValue := range v.Constraints {
+		filter := ParseFilter(constraint)
+		if filter == unknownFilter {
+			return false
+		}
+		filterValue, ok := filters[filter]
+		if !ok || !filterValueEquals(constraintValue, filterValue) {
 			return false
 		}
 	}
 	return true
 }
 
+// moreSpecific returns whether value v is more specific than value o, both matching the same filters
+func moreSpecific(v *constrainedValue, o *constrainedValue) bool {
+	if len(v.Constraints) != len(o.Constraints) {
+		return len(v.Constraints) > len(o.Constraints)
+	}
+	return constraintsMask(v) > constraintsMask(o)
+}
+
+// constraintsMask returns a bit mask of the filters constraining a value, filters declared
+// later are more specific and have a higher bit
+func constraintsMask(v *constrainedValue) int {
+	mask := 0
+	for constraint := range v.Constraints {
+		mask |= 1 << uint(ParseFilter(constraint))
+	}
+	return mask
+}
+
 func convertKeyTypeToString(v interface{}) (interface{}, error) {
 	switch v := v.(type) {
 	case map[interface{}]interface{}:
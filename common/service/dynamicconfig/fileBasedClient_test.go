@@ -21,6 +21,8 @@
 package dynamicconfig
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
@@ -88,13 +90,14 @@ func (s *fileBasedClientSuite) TestGetValueWithFilters() {
 	s.NoError(err)
 	s.Equal(false, v)
 
+	// values constrained by a subset of the filters apply
 	filters = map[Filter]interface{}{
 		DomainName:   "samples-domain",
 		TaskListName: "non-exist-tasklist",
 	}
 	v, err = s.client.GetValueWithFilters(testGetBoolPropertyKey, filters, false)
 	s.NoError(err)
-	s.Equal(false, v)
+	s.Equal(true, v)
 }
 
 func (s *fileBasedClientSuite) TestGetValueWithFilters_UnknownFilter() {
@@ -104,7 +107,7 @@ func (s *fileBasedClientSuite) TestGetValueWithFilters_UnknownFilter() {
 	}
 	v, err := s.client.GetValueWithFilters(testGetBoolPropertyKey, filters, false)
 	s.NoError(err)
-	s.Equal(false, v)
+	s.Equal(true, v)
 }
 
 func (s *fileBasedClientSuite) TestGetIntValue() {
@@ -148,6 +151,32 @@ func (s *fileBasedClientSuite) TestGetFloatValue_WrongType() {
 	s.Equal(defaultValue, v)
 }
 
+func (s *fileBasedClientSuite) TestGetFloat64PropertyFilteredByAPIInfo() {
+	value := NewCollection(s.client, log.NewNoop()).GetFloat64PropertyFilteredByAPIInfo(testGetFloat64PropertyFilteredByAPIInfoKey, 0)
+	s.Equal(1.0, value("samples-domain", "samples-tasklist", "StartWorkflowExecution"))
+	s.Equal(10.0, value("samples-domain", "other-tasklist", "StartWorkflowExecution"))
+	s.Equal(10.0, value("samples-domain", "", "StartWorkflowExecution"))
+	s.Equal(100.0, value("other-domain", "samples-tasklist", "StartWorkflowExecution"))
+	s.Equal(0.0, value("samples-domain", "samples-tasklist", "SignalWorkflowExecution"))
+}
+
+func (s *fileBasedClientSuite) TestGetIntPropertyFilteredByShardID() {
+	value := NewCollection(s.client, log.NewNoop()).GetIntPropertyFilteredByShardID(testGetIntPropertyFilteredByShardIDKey, 0)
+	s.Equal(10, value(7))
+	s.Equal(100, value(8))
+}
+
+func (s *fileBasedClientSuite) TestGetIntPropertyFilteredByWorkflowType() {
+	value := NewCollection(s.client, log.NewNoop()).GetIntPropertyFilteredByWorkflowType(testGetIntPropertyFilteredByWorkflowTypeKey, 0)
+	s.Equal(1, value("samples-domain", "samples-workflow"))
+	s.Equal(10, value("other-domain", "samples-workflow"))
+	s.Equal(100, value("samples-domain", "other-workflow"))
+	s.Equal(100, value("samples-domain", ""))
+	s.Equal(1000, value("other-domain", "other-workflow"))
+	// a value set to the default value still wins over less specific values
+	s.Equal(0, value("zero-domain", "samples-workflow"))
+}
+
 func (s *fileBasedClientSuite) TestGetBoolValue() {
 	v, err := s.client.GetBoolValue(testGetBoolPropertyKey, nil, true)
 	s.NoError(err)
@@ -237,6 +266,90 @@ func (s *fileBasedClientSuite) TestValidateConfig_ShortPollInterval() {
 	s.Error(err)
 }
 
+func (s *fileBasedClientSuite) TestValidateConfig_InvalidValues() {
+	testCases := []string{
+		"frontend.rsp:\n- value: 100\n  constraints: {}\n",
+		"frontend.rps:\n- value: high\n  constraints: {}\n",
+		"frontend.rps:\n- value: 100\n  constraints:\n    domain: samples-domain\n",
+		"frontend.rps:\n- value: 100\n  constraints:\n    shardID: one\n",
+		"matching.longPollExpirationInterval:\n- value: 1 minute\n  constraints: {}\n",
+	}
+
+	for _, tc := range testCases {
+		path := s.writeConfigFile(tc)
+		defer os.Remove(path)
+		_, err := NewFileBasedClient(&FileBasedClientConfig{
+			Filepath:     path,
+			PollInterval: time.Second * 5,
+		}, log.NewNoop(), s.doneCh)
+		s.Error(err, tc)
+	}
+}
+
+func (s *fileBasedClientSuite) TestUpdate_InvalidConfigKeepsLastGoodConfig() {
+	path := s.writeConfigFile("frontend.rps:\n- value: 100\n  constraints: {}\n")
+	defer os.Remove(path)
+	client, err := NewFileBasedClient(&FileBasedClientConfig{
+		Filepath:     path,
+		PollInterval: time.Second * 5,
+	}, log.NewNoop(), s.doneCh)
+	s.NoError(err)
+
+	s.NoError(ioutil.WriteFile(path, []byte("frontend.rps:\n- value: 1OO\n  constraints: {}\n"), fileMode))
+	future := time.Now().Add(time.Minute)
+	s.NoError(os.Chtimes(path, future, future))
+	s.Error(client.(*fileBasedClient).update())
+
+	v, err := client.GetIntValue(FrontendRPS, nil, 0)
+	s.NoError(err)
+	s.Equal(100, v)
+}
+
+func (s *fileBasedClientSuite) TestListValues() {
+	values, err := s.client.ListValues(map[Filter]interface{}{
+		DomainName: "global-samples-domain",
+	})
+	s.NoError(err)
+	s.Equal(true, values[testGetBoolPropertyKey])
+	s.Equal(1000.1, values[testGetIntPropertyKey])
+	_, ok := values[testGetFloat64PropertyFilteredByAPIInfoKey]
+	s.False(ok)
+
+	// values are resolved the same way as by the typed getters
+	values, err = s.client.ListValues(map[Filter]interface{}{
+		DomainName:   "samples-domain",
+		TaskListName: "other-tasklist",
+		APIName:      "StartWorkflowExecution",
+		WorkflowType: "other-workflow",
+	})
+	s.NoError(err)
+	s.Equal(10, values[testGetFloat64PropertyFilteredByAPIInfoKey])
+	s.Equal(100, values[testGetIntPropertyFilteredByWorkflowTypeKey])
+	s.Equal(true, values[testGetBoolPropertyKey])
+}
+
+func (s *fileBasedClientSuite) TestDevelopmentConfigIsValid() {
+	for _, path := range []string{
+		"../../../config/dynamicconfig/development.yaml",
+		"../../../config/dynamicconfig/development_es.yaml",
+	} {
+		_, err := NewFileBasedClient(&FileBasedClientConfig{
+			Filepath:     path,
+			PollInterval: time.Second * 5,
+		}, log.NewNoop(), s.doneCh)
+		s.NoError(err, path)
+	}
+}
+
+func (s *fileBasedClientSuite) writeConfigFile(content string) string {
+	f, err := ioutil.TempFile("", "dynamicconfig-*.yaml")
+	s.NoError(err)
+	defer f.Close()
+	_, err = f.WriteString(content)
+	s.NoError(err)
+	return f.Name()
+}
+
 func (s *fileBasedClientSuite) TestMatch() {
 	testCases := []struct {
 		v       *constrainedValue
@@ -305,6 +418,40 @@ func (s *fileBasedClientSuite) TestMatch() {
 			},
 			matched: false,
 		},
+		{
+			v: &constrainedValue{
+				Constraints: map[string]interface{}{
+					"domainName": "samples-domain",
+				},
+			},
+			filters: map[Filter]interface{}{
+				DomainName:   "samples-domain",
+				TaskListName: "sample-task-list",
+			},
+			matched: true,
+		},
+		{
+			v: &constrainedValue{
+				Constraints: map[string]interface{}{
+					"taskType": 1,
+				},
+			},
+			filters: map[Filter]interface{}{
+				TaskType: int32(1),
+			},
+			matched: true,
+		},
+		{
+			v: &constrainedValue{
+				Constraints: map[string]interface{}{
+					"shardID": 1,
+				},
+			},
+			filters: map[Filter]interface{}{
+				ShardID: int32(1),
+			},
+			matched: true,
+		},
 	}
 
 	for _, tc := range testCases {
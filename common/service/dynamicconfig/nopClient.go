@@ -68,6 +68,10 @@ func (mc *nopClient) GetDurationValue(
 	return defaultValue, errors.New("unable to find key")
 }
 
+func (mc *nopClient) ListValues(filters map[Filter]interface{}) (map[Key]interface{}, error) {
+	return map[Key]interface{}{}, nil
+}
+
 func (mc *nopClient) UpdateValue(name Key, value interface{}) error {
 	return errors.New("unable to update key")
 }
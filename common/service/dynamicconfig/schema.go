@@ -0,0 +1,435 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+// valueType is the type of the value of a dynamic config key
+type valueType int
+
+const (
+	unknownType valueType = iota
+	// anyType accepts a value of any type
+	anyType
+	intType
+	floatType
+	boolType
+	stringType
+	mapType
+	// durationType is a string in the format accepted by time.ParseDuration
+	durationType
+)
+
+// keyTypes is the schema used to validate the values loaded from the dynamic config file,
+// every key must have the type of the Collection getter it is read with
+var keyTypes = map[Key]valueType{
+	// some of the test keys hold values of a wrong type on purpose
+	testGetPropertyKey:                                    anyType,
+	testGetIntPropertyKey:                                 anyType,
+	testGetFloat64PropertyKey:                             anyType,
+	testGetDurationPropertyKey:                            anyType,
+	testGetBoolPropertyKey:                                boolType,
+	testGetStringPropertyKey:                              stringType,
+	testGetMapPropertyKey:                                 anyType,
+	testGetIntPropertyFilteredByDomainKey:                 intType,
+	testGetDurationPropertyFilteredByDomainKey:            durationType,
+	testGetIntPropertyFilteredByTaskListInfoKey:           intType,
+	testGetDurationPropertyFilteredByTaskListInfoKey:      durationType,
+	testGetBoolPropertyFilteredByTaskListInfoKey:          boolType,
+	testGetFloat64PropertyFilteredByAPIInfoKey:            floatType,
+	testGetIntPropertyFilteredByShardIDKey:                intType,
+	testGetIntPropertyFilteredByWorkflowTypeKey:           intType,
+	testGetMapPropertyFilteredByTaskListInfoKey:           mapType,
+	EnableGlobalDomain:                                    boolType,
+	EnableNDC:                                             boolType,
+	EnableNewKafkaClient:                                  boolType,
+	EnableVisibilitySampling:                              boolType,
+	EnableReadFromClosedExecutionV2:                       boolType,
+	AdvancedVisibilityWritingMode:                         stringType,
+	EnableReadVisibilityFromES:                            boolType,
+	HistoryArchivalStatus:                                 stringType,
+	EnableReadFromHistoryArchival:                         boolType,
+	VisibilityArchivalStatus:                              stringType,
+	EnableReadFromVisibilityArchival:                      boolType,
+	EnableDomainNotActiveAutoForwarding:                   boolType,
+	TransactionSizeLimit:                                  intType,
+	MinRetentionDays:                                      intType,
+	MaxDecisionStartToCloseSeconds:                        intType,
+	DisallowQuery:                                         boolType,
+	EnableBatcher:                                         boolType,
+	EnableScheduler:                                       boolType,
+	EnableParentClosePolicyWorker:                         boolType,
+	EnableStickyQuery:                                     boolType,
+	BlobSizeLimitError:                                    intType,
+	BlobSizeLimitWarn:                                     intType,
+	HistorySizeLimitError:                                 intType,
+	HistorySizeLimitWarn:                                  intType,
+	HistoryCountLimitError:                                intType,
+	HistoryCountLimitWarn:                                 intType,
+	MaxIDLengthLimit:                                      intType,
+	FrontendPersistenceMaxQPS:                             intType,
+	FrontendVisibilityMaxPageSize:                         intType,
+	FrontendVisibilityListMaxQPS:                          intType,
+	FrontendESVisibilityListMaxQPS:                        intType,
+	FrontendMaxBadBinaries:                                intType,
+	FrontendESIndexMaxResultWindow:                        intType,
+	FrontendHistoryMaxPageSize:                            intType,
+	FrontendRPS:                                           intType,
+	FrontendDomainRPS:                                     intType,
+	FrontendDomainBurst:                                   intType,
+	FrontendAPIRPS:                                        floatType,
+	FrontendAPIBurst:                                      intType,
+	FrontendAPIPriority:                                   intType,
+	FrontendNormalPriorityRPSRatio:                        floatType,
+	FrontendLowPriorityRPSRatio:                           floatType,
+	FrontendRateLimitMaxWait:                              durationType,
+	FrontendGlobalRPS:                                     intType,
+	FrontendGlobalDomainRPS:                               intType,
+	FrontendGlobalRateLimitRebalance:                      boolType,
+	FrontendGlobalRateLimitRebalanceInterval:              durationType,
+	FrontendHistoryMgrNumConns:                            intType,
+	DisableListVisibilityByFilter:                         boolType,
+	FrontendThrottledLogRPS:                               intType,
+	EnableClientVersionCheck:                              boolType,
+	ValidSearchAttributes:                                 mapType,
+	SearchAttributesNumberOfKeysLimit:                     intType,
+	SearchAttributesSizeOfValueLimit:                      intType,
+	SearchAttributesTotalSizeLimit:                        intType,
+	VisibilityArchivalQueryMaxPageSize:                    intType,
+	VisibilityArchivalQueryMaxRangeInDays:                 intType,
+	VisibilityArchivalQueryMaxQPS:                         intType,
+	MatchingRPS:                                           intType,
+	MatchingPersistenceMaxQPS:                             intType,
+	MatchingMinTaskThrottlingBurstSize:                    intType,
+	MatchingGetTasksBatchSize:                             intType,
+	MatchingLongPollExpirationInterval:                    durationType,
+	MatchingEnableSyncMatch:                               boolType,
+	MatchingUpdateAckInterval:                             durationType,
+	MatchingIdleTasklistCheckInterval:                     durationType,
+	MaxTasklistIdleTime:                                   durationType,
+	MatchingOutstandingTaskAppendsThreshold:               intType,
+	MatchingMaxTaskBatchSize:                              intType,
+	MatchingMaxTaskDeleteBatchSize:                        intType,
+	MatchingThrottledLogRPS:                               intType,
+	MatchingNumTasklistWritePartitions:                    intType,
+	MatchingNumTasklistReadPartitions:                     intType,
+	MatchingForwarderMaxOutstandingPolls:                  intType,
+	MatchingForwarderMaxOutstandingTasks:                  intType,
+	MatchingForwarderMaxRatePerSecond:                     intType,
+	MatchingForwarderMaxChildrenPerNode:                   intType,
+	MatchingForwarderMinRatePerSecond:                     intType,
+	MatchingForwarderRateRecoveryPerSecond:                intType,
+	MatchingForwarderRateBackoffRatio:                     floatType,
+	MatchingEnablePartitionAutoScaling:                    boolType,
+	MatchingPartitionAutoScalingInterval:                  durationType,
+	MatchingMaxTasklistPartitions:                         intType,
+	MatchingPartitionTargetAddRate:                        intType,
+	MatchingPartitionTargetBacklog:                        intType,
+	MatchingMinPollersPerPartition:                        intType,
+	MatchingPartitionConfigRefreshInterval:                durationType,
+	MatchingTaskPriorityWeightRatio:                       intType,
+	MatchingEnableFairnessDispatch:                        boolType,
+	MatchingFairnessKeyWeights:                            mapType,
+	MatchingFairnessKeyDefaultWeight:                      intType,
+	MatchingMaxFairnessKeys:                               intType,
+	HistoryRPS:                                            intType,
+	HistoryPersistenceMaxQPS:                              intType,
+	HistoryVisibilityOpenMaxQPS:                           intType,
+	HistoryVisibilityClosedMaxQPS:                         intType,
+	HistoryLongPollExpirationInterval:                     durationType,
+	HistoryCacheInitialSize:                               intType,
+	HistoryMaxAutoResetPoints:                             intType,
+	HistoryCacheMaxSize:                                   intType,
+	HistoryCacheTTL:                                       durationType,
+	EventsCacheInitialSize:                                intType,
+	EventsCacheMaxSize:                                    intType,
+	EventsCacheTTL:                                        durationType,
+	AcquireShardInterval:                                  durationType,
+	AcquireShardConcurrency:                               intType,
+	EnableGracefulShardHandoff:                            boolType,
+	ShardLeaseDuration:                                    durationType,
+	ShardHandoffTimeout:                                   durationType,
+	ShardLoadWindow:                                       durationType,
+	HotShardRPSThreshold:                                  intType,
+	EnableHotWorkflowThrottling:                           boolType,
+	HotWorkflowShareThreshold:                             floatType,
+	HotWorkflowMaxThrottled:                               intType,
+	HotWorkflowThrottledRPS:                               intType,
+	StandbyClusterDelay:                                   durationType,
+	StandbyTaskMissingEventsResendDelay:                   durationType,
+	StandbyTaskMissingEventsDiscardDelay:                  durationType,
+	TaskProcessRPS:                                        intType,
+	TimerTaskBatchSize:                                    intType,
+	TimerTaskWorkerCount:                                  intType,
+	TimerTaskMaxRetryCount:                                intType,
+	TimerProcessorGetFailureRetryCount:                    intType,
+	TimerProcessorCompleteTimerFailureRetryCount:          intType,
+	TimerProcessorUpdateShardTaskCount:                    intType,
+	TimerProcessorUpdateAckInterval:                       durationType,
+	TimerProcessorUpdateAckIntervalJitterCoefficient:      floatType,
+	TimerProcessorCompleteTimerInterval:                   durationType,
+	TimerProcessorFailoverMaxPollRPS:                      intType,
+	TimerProcessorMaxPollRPS:                              intType,
+	TimerProcessorMaxPollInterval:                         durationType,
+	TimerProcessorMaxPollIntervalJitterCoefficient:        floatType,
+	TimerProcessorMaxTimeShift:                            durationType,
+	TimerProcessorHistoryArchivalSizeLimit:                intType,
+	TimerProcessorArchivalTimeLimit:                       durationType,
+	TransferTaskBatchSize:                                 intType,
+	TransferProcessorFailoverMaxPollRPS:                   intType,
+	TransferProcessorMaxPollRPS:                           intType,
+	TransferTaskWorkerCount:                               intType,
+	TransferTaskMaxRetryCount:                             intType,
+	TransferProcessorCompleteTransferFailureRetryCount:    intType,
+	TransferProcessorUpdateShardTaskCount:                 intType,
+	TransferProcessorMaxPollInterval:                      durationType,
+	TransferProcessorMaxPollIntervalJitterCoefficient:     floatType,
+	TransferProcessorUpdateAckInterval:                    durationType,
+	TransferProcessorUpdateAckIntervalJitterCoefficient:   floatType,
+	TransferProcessorCompleteTransferInterval:             durationType,
+	TransferProcessorVisibilityArchivalTimeLimit:          durationType,
+	ReplicatorTaskBatchSize:                               intType,
+	ReplicatorTaskWorkerCount:                             intType,
+	ReplicatorTaskMaxRetryCount:                           intType,
+	ReplicatorProcessorMaxPollRPS:                         intType,
+	ReplicatorProcessorUpdateShardTaskCount:               intType,
+	ReplicatorProcessorMaxPollInterval:                    durationType,
+	ReplicatorProcessorMaxPollIntervalJitterCoefficient:   floatType,
+	ReplicatorProcessorUpdateAckInterval:                  durationType,
+	ReplicatorProcessorUpdateAckIntervalJitterCoefficient: floatType,
+	ExecutionMgrNumConns:                                  intType,
+	HistoryMgrNumConns:                                    intType,
+	MaximumBufferedEventsBatch:                            intType,
+	MaximumSignalsPerExecution:                            intType,
+	WorkflowSignalRPS:                                     intType,
+	WorkflowDecisionCompletionRPS:                         intType,
+	WorkflowActivityScheduleRPS:                           intType,
+	ShardUpdateMinInterval:                                durationType,
+	ShardSyncMinInterval:                                  durationType,
+	ShardSyncTimerJitterCoefficient:                       floatType,
+	DefaultEventEncoding:                                  stringType,
+	EnableAdminProtection:                                 boolType,
+	AdminOperationToken:                                   stringType,
+	EnableParentClosePolicy:                               boolType,
+	NumArchiveSystemWorkflows:                             intType,
+	ArchiveRequestRPS:                                     intType,
+	EmitShardDiffLog:                                      boolType,
+	HistoryThrottledLogRPS:                                intType,
+	StickyTTL:                                             durationType,
+	DecisionHeartbeatTimeout:                              durationType,
+	ParentClosePolicyThreshold:                            intType,
+	NumParentClosePolicySystemWorkflows:                   intType,
+	ReplicationTaskFetcherParallelism:                     intType,
+	ReplicationTaskFetcherAggregationInterval:             durationType,
+	ReplicationTaskFetcherTimerJitterCoefficient:          floatType,
+	ReplicationTaskFetcherErrorRetryWait:                  durationType,
+	ReplicationTaskProcessorErrorRetryWait:                durationType,
+	ReplicationTaskProcessorErrorRetryMaxAttempts:         intType,
+	ReplicationTaskProcessorNoTaskInitialWait:             durationType,
+	ReplicationTaskProcessorCleanupInterval:               durationType,
+	ReplicationTaskProcessorCleanupJitterCoefficient:      floatType,
+	EnableConsistentQuery:                                 boolType,
+	EnableConsistentQueryByDomain:                         boolType,
+	MaxBufferedQueryCount:                                 intType,
+	MutableStateChecksumGenProbability:                    intType,
+	MutableStateChecksumVerifyProbability:                 intType,
+	MutableStateChecksumInvalidateBefore:                  floatType,
+	WorkerPersistenceMaxQPS:                               intType,
+	WorkerReplicatorMetaTaskConcurrency:                   intType,
+	WorkerReplicatorTaskConcurrency:                       intType,
+	WorkerReplicatorMessageConcurrency:                    intType,
+	WorkerReplicatorActivityBufferRetryCount:              intType,
+	WorkerReplicatorHistoryBufferRetryCount:               intType,
+	WorkerReplicationTaskMaxRetryCount:                    intType,
+	WorkerReplicationTaskMaxRetryDuration:                 durationType,
+	WorkerReplicationTaskContextDuration:                  durationType,
+	WorkerIndexerConcurrency:                              intType,
+	WorkerESProcessorNumOfWorkers:                         intType,
+	WorkerESProcessorBulkActions:                          intType,
+	WorkerESProcessorBulkSize:                             intType,
+	WorkerESProcessorFlushInterval:                        durationType,
+	EnableArchivalCompression:                             boolType,
+	WorkerHistoryPageSize:                                 intType,
+	WorkerTargetArchivalBlobSize:                          intType,
+	WorkerArchiverConcurrency:                             intType,
+	WorkerArchivalsPerIteration:                           intType,
+	WorkerDeterministicConstructionCheckProbability:       floatType,
+	WorkerBlobIntegrityCheckProbability:                   floatType,
+	WorkerTimeLimitPerArchivalIteration:                   durationType,
+	WorkerThrottledLogRPS:                                 intType,
+	ScannerPersistenceMaxQPS:                              intType,
+	TaskListScannerEnabled:                                boolType,
+	HistoryScannerEnabled:                                 boolType,
+	ExecutionsScannerEnabled:                              boolType,
+}
+
+func (t valueType) String() string {
+	switch t {
+	case anyType:
+		return "any"
+	case intType:
+		return "int"
+	case floatType:
+		return "float"
+	case boolType:
+		return "bool"
+	case stringType:
+		return "string"
+	case mapType:
+		return "map"
+	case durationType:
+		return "duration"
+	default:
+		return "unknown"
+	}
+}
+
+// validateValue returns an error if the value is not of the type of the key
+func validateValue(key Key, value interface{}) error {
+	t, ok := keyTypes[key]
+	if !ok {
+		return fmt.Errorf("no value type defined for key %v", key)
+	}
+
+	valid := false
+	switch t {
+	case anyType:
+		valid = true
+	case intType:
+		_, valid = value.(int)
+	case floatType:
+		switch value.(type) {
+		case float64, int:
+			valid = true
+		}
+	case boolType:
+		_, valid = value.(bool)
+	case stringType:
+		_, valid = value.(string)
+	case mapType:
+		_, valid = value.(map[string]interface{})
+	case durationType:
+		if s, ok := value.(string); ok {
+			_, err := time.ParseDuration(s)
+			valid = err == nil
+		}
+	}
+	if !valid {
+		return fmt.Errorf("value %v of key %v is not of type %v", value, key, t)
+	}
+	return nil
+}
+
+// validateValues returns the errors of all the keys, constraints and values which do not
+// match the schema
+func validateValues(values map[string][]*constrainedValue) error {
+	var errs error
+	for keyName, constrainedValues := range values {
+		key := ParseKey(keyName)
+		if key == unknownKey {
+			errs = multierr.Append(errs, fmt.Errorf("unknown key %v", keyName))
+			continue
+		}
+		for _, cv := range constrainedValues {
+			if err := validateValue(key, cv.Value); err != nil {
+				errs = multierr.Append(errs, err)
+			}
+			for filterName, filterValue := range cv.Constraints {
+				if err := validateConstraint(filterName, filterValue); err != nil {
+					errs = multierr.Append(errs, fmt.Errorf("key %v: %v", keyName, err))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// validateConstraint returns an error if the filter is unknown or the value is not of the
+// type of the filter
+func validateConstraint(filterName string, value interface{}) error {
+	filter := ParseFilter(filterName)
+	valid := false
+	switch filter {
+	case unknownFilter:
+		return fmt.Errorf("unknown filter %v", filterName)
+	case TaskType, ShardID:
+		_, valid = value.(int)
+	default:
+		_, valid = value.(string)
+	}
+	if !valid {
+		return fmt.Errorf("value %v of filter %v is not of type %v", value, filterName, filterValueType(filter))
+	}
+	return nil
+}
+
+func filterValueType(filter Filter) valueType {
+	switch filter {
+	case TaskType, ShardID:
+		return intType
+	default:
+		return stringType
+	}
+}
+
+// ParseFilterValue converts the string form of a filter value, e.g. from the command line, to
+// the type used by the filter options
+func ParseFilterValue(filter Filter, value string) (interface{}, error) {
+	switch filterValueType(filter) {
+	case intType:
+		intVal, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("value %q of filter %v is not an integer", value, filter)
+		}
+		return int32(intVal), nil
+	default:
+		return value, nil
+	}
+}
+
+// filterValueEquals compares a constraint loaded from the config with a filter value, integer
+// filter values are compared regardless of their size
+func filterValueEquals(constraint interface{}, filterValue interface{}) bool {
+	if c, ok := toInt64(constraint); ok {
+		v, ok := toInt64(filterValue)
+		return ok && c == v
+	}
+	return constraint == filterValue
+}
+
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
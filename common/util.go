@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"math/rand"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -376,6 +377,48 @@ func ValidateRetryPolicy(policy *commonproto.RetryPolicy) error {
 	return nil
 }
 
+// ValidateTaskPriority validates the task priority header field if it is set
+func ValidateTaskPriority(header *commonproto.Header) error {
+	_, err := parseTaskPriority(header)
+	return err
+}
+
+// GetTaskPriority returns the task priority from the header, or the default priority if it is not set or invalid
+func GetTaskPriority(header *commonproto.Header) int32 {
+	priority, err := parseTaskPriority(header)
+	if err != nil {
+		return DefaultTaskPriority
+	}
+	return priority
+}
+
+func parseTaskPriority(header *commonproto.Header) (int32, error) {
+	value, ok := header.GetFields()[TaskPriorityHeaderKey]
+	if !ok {
+		return DefaultTaskPriority, nil
+	}
+	priority, err := strconv.Atoi(strings.TrimSpace(string(value)))
+	if err != nil || priority < int(DefaultTaskPriority) || priority > int(MaxTaskPriority) {
+		return DefaultTaskPriority, serviceerror.NewInvalidArgument(
+			fmt.Sprintf("Task priority must be an integer between %v and %v.", DefaultTaskPriority, MaxTaskPriority))
+	}
+	return int32(priority), nil
+}
+
+// ValidateFairnessKey validates the fairness key header field if it is set
+func ValidateFairnessKey(header *commonproto.Header) error {
+	if len(GetFairnessKey(header)) > MaxFairnessKeyLength {
+		return serviceerror.NewInvalidArgument(
+			fmt.Sprintf("Fairness key exceeds length limit of %v.", MaxFairnessKeyLength))
+	}
+	return nil
+}
+
+// GetFairnessKey returns the fairness key from the header, or empty string if it is not set
+func GetFairnessKey(header *commonproto.Header) string {
+	return strings.TrimSpace(string(header.GetFields()[FairnessKeyHeaderKey]))
+}
+
 // CreateHistoryStartWorkflowRequest create a start workflow request for history
 func CreateHistoryStartWorkflowRequest(
 	domainID string,
@@ -64,6 +64,16 @@ const (
 	PostgresPort = "POSTGRES_PORT"
 	// PostgresDefaultPort Postgres default port
 	PostgresDefaultPort = "5432"
+
+	// S3CompatibleEndpoint env
+	S3CompatibleEndpoint = "S3_COMPATIBLE_ENDPOINT"
+	// S3CompatibleDefaultEndpoint MinIO default endpoint
+	S3CompatibleDefaultEndpoint = "http://127.0.0.1:9000"
+
+	// AzuriteEndpoint env
+	AzuriteEndpoint = "AZURITE_ENDPOINT"
+	// AzuriteDefaultEndpoint Azurite default blob endpoint
+	AzuriteDefaultEndpoint = "http://127.0.0.1:10000/devstoreaccount1"
 )
 
 // SetupEnv setup the necessary env
@@ -248,3 +258,21 @@ func GetESPort() int {
 	}
 	return p
 }
+
+// GetS3CompatibleEndpoint return the S3 compatible storage endpoint
+func GetS3CompatibleEndpoint() string {
+	endpoint := os.Getenv(S3CompatibleEndpoint)
+	if endpoint == "" {
+		endpoint = S3CompatibleDefaultEndpoint
+	}
+	return endpoint
+}
+
+// GetAzuriteEndpoint return the Azurite blob endpoint
+func GetAzuriteEndpoint() string {
+	endpoint := os.Getenv(AzuriteEndpoint)
+	if endpoint == "" {
+		endpoint = AzuriteDefaultEndpoint
+	}
+	return endpoint
+}
@@ -152,6 +152,20 @@ func (d *dynamicClient) GetDurationValue(
 	return d.client.GetDurationValue(name, filters, defaultValue)
 }
 
+func (d *dynamicClient) ListValues(filters map[dynamicconfig.Filter]interface{}) (map[dynamicconfig.Key]interface{}, error) {
+	values, err := d.client.ListValues(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	d.RLock()
+	defer d.RUnlock()
+	for key, val := range d.overrides {
+		values[key] = val
+	}
+	return values, nil
+}
+
 func (d *dynamicClient) UpdateValue(name dynamicconfig.Key, value interface{}) error {
 	if name == dynamicconfig.AdvancedVisibilityWritingMode { // override for es integration tests
 		d.Lock()
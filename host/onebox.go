@@ -21,6 +21,7 @@
 package host
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -820,6 +821,10 @@ func (c *rpcFactoryImpl) GetGRPCListener() net.Listener {
 	return c.listener
 }
 
+func (c *rpcFactoryImpl) GetGRPCServerTLSConfig() *tls.Config {
+	return nil
+}
+
 func (c *rpcFactoryImpl) GetRingpopChannel() *tchannel.Channel {
 	if c.ringpopChannel != nil {
 		return c.ringpopChannel
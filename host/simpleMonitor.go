@@ -75,3 +75,11 @@ func (s *simpleMonitor) RemoveListener(service string, name string) error {
 func (s *simpleMonitor) GetReachableMembers() ([]string, error) {
 	return nil, nil
 }
+
+func (s *simpleMonitor) SetSelfLabel(key string, value string) error {
+	return nil
+}
+
+func (s *simpleMonitor) GetMemberLabels(service string, key string) (map[string]string, error) {
+	return nil, nil
+}
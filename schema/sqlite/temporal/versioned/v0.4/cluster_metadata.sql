@@ -0,0 +1,26 @@
+CREATE TABLE cluster_metadata (
+  metadata_partition        INTEGER NOT NULL,
+  immutable_data            BLOB NOT NULL,
+  immutable_data_encoding   VARCHAR(16) NOT NULL,
+  PRIMARY KEY(metadata_partition)
+);
+
+-- SQLite only auto increments the rowid alias, so insertion_order is the primary key
+-- and host_id is kept unique instead.
+CREATE TABLE cluster_membership
+(
+    insertion_order      INTEGER PRIMARY KEY AUTOINCREMENT,
+    host_id              BLOB NOT NULL UNIQUE,
+    rpc_address          VARCHAR(15) NOT NULL,
+    rpc_port             INTEGER NOT NULL,
+    role                 INTEGER NOT NULL,
+    session_start        TIMESTAMP DEFAULT '1970-01-01 00:00:01+00:00',
+    last_heartbeat       TIMESTAMP DEFAULT '1970-01-01 00:00:01+00:00',
+    record_expiry        TIMESTAMP DEFAULT '1970-01-01 00:00:01+00:00'
+);
+
+CREATE UNIQUE INDEX cm_idx_rolehost ON cluster_membership (role, host_id);
+CREATE INDEX cm_idx_rolelasthb ON cluster_membership (role, last_heartbeat);
+CREATE INDEX cm_idx_rpchost ON cluster_membership (rpc_address, role);
+CREATE INDEX cm_idx_lasthb ON cluster_membership (last_heartbeat);
+CREATE INDEX cm_idx_recordexpiry ON cluster_membership (record_expiry);
@@ -0,0 +1,647 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/common/authorization"
+)
+
+var _ adminservice.AdminServiceServer = (*AccessControlledAdminHandler)(nil)
+
+type (
+	// AccessControlledAdminHandler admin handler wrapper for authentication and authorization
+	AccessControlledAdminHandler struct {
+		adminHandler adminservice.AdminServiceServer
+		authorizer   authorization.Authorizer
+		claimMapper  authorization.ClaimMapper
+	}
+)
+
+// NewAccessControlledAdminHandler creates admin handler with authorization support
+func NewAccessControlledAdminHandler(
+	adminHandler adminservice.AdminServiceServer,
+	authorizer authorization.Authorizer,
+	claimMapper authorization.ClaimMapper,
+) *AccessControlledAdminHandler {
+	if authorizer == nil {
+		authorizer = authorization.NewNopAuthorizer()
+	}
+	if claimMapper == nil {
+		claimMapper = authorization.NewNopClaimMapper()
+	}
+
+	return &AccessControlledAdminHandler{
+		adminHandler: adminHandler,
+		authorizer:   authorizer,
+		claimMapper:  claimMapper,
+	}
+}
+
+// DescribeWorkflowExecution API call
+func (a *AccessControlledAdminHandler) DescribeWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.DescribeWorkflowExecutionRequest,
+) (*adminservice.DescribeWorkflowExecutionResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "DescribeWorkflowExecution",
+		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.DescribeWorkflowExecution(ctx, request)
+}
+
+// DescribeHistoryHost API call
+func (a *AccessControlledAdminHandler) DescribeHistoryHost(
+	ctx context.Context,
+	request *adminservice.DescribeHistoryHostRequest,
+) (*adminservice.DescribeHistoryHostResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "DescribeHistoryHost",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.DescribeHistoryHost(ctx, request)
+}
+
+// CloseShard API call
+func (a *AccessControlledAdminHandler) CloseShard(
+	ctx context.Context,
+	request *adminservice.CloseShardRequest,
+) (*adminservice.CloseShardResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "CloseShard",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.CloseShard(ctx, request)
+}
+
+// RemoveTask API call
+func (a *AccessControlledAdminHandler) RemoveTask(
+	ctx context.Context,
+	request *adminservice.RemoveTaskRequest,
+) (*adminservice.RemoveTaskResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "RemoveTask",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.RemoveTask(ctx, request)
+}
+
+// GetWorkflowExecutionRawHistory API call
+func (a *AccessControlledAdminHandler) GetWorkflowExecutionRawHistory(
+	ctx context.Context,
+	request *adminservice.GetWorkflowExecutionRawHistoryRequest,
+) (*adminservice.GetWorkflowExecutionRawHistoryResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "GetWorkflowExecutionRawHistory",
+		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.GetWorkflowExecutionRawHistory(ctx, request)
+}
+
+// GetWorkflowExecutionRawHistoryV2 API call
+func (a *AccessControlledAdminHandler) GetWorkflowExecutionRawHistoryV2(
+	ctx context.Context,
+	request *adminservice.GetWorkflowExecutionRawHistoryV2Request,
+) (*adminservice.GetWorkflowExecutionRawHistoryV2Response, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "GetWorkflowExecutionRawHistoryV2",
+		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.GetWorkflowExecutionRawHistoryV2(ctx, request)
+}
+
+// GetReplicationMessages API call
+func (a *AccessControlledAdminHandler) GetReplicationMessages(
+	ctx context.Context,
+	request *adminservice.GetReplicationMessagesRequest,
+) (*adminservice.GetReplicationMessagesResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "GetReplicationMessages",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.GetReplicationMessages(ctx, request)
+}
+
+// GetDomainReplicationMessages API call
+func (a *AccessControlledAdminHandler) GetDomainReplicationMessages(
+	ctx context.Context,
+	request *adminservice.GetDomainReplicationMessagesRequest,
+) (*adminservice.GetDomainReplicationMessagesResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "GetDomainReplicationMessages",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.GetDomainReplicationMessages(ctx, request)
+}
+
+// GetDLQReplicationMessages API call
+func (a *AccessControlledAdminHandler) GetDLQReplicationMessages(
+	ctx context.Context,
+	request *adminservice.GetDLQReplicationMessagesRequest,
+) (*adminservice.GetDLQReplicationMessagesResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "GetDLQReplicationMessages",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.GetDLQReplicationMessages(ctx, request)
+}
+
+// ReapplyEvents API call
+func (a *AccessControlledAdminHandler) ReapplyEvents(
+	ctx context.Context,
+	request *adminservice.ReapplyEventsRequest,
+) (*adminservice.ReapplyEventsResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "ReapplyEvents",
+		DomainName: request.GetDomainName(),
+		WorkflowID: request.GetWorkflowExecution().GetWorkflowId(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.ReapplyEvents(ctx, request)
+}
+
+// AddSearchAttribute API call
+func (a *AccessControlledAdminHandler) AddSearchAttribute(
+	ctx context.Context,
+	request *adminservice.AddSearchAttributeRequest,
+) (*adminservice.AddSearchAttributeResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "AddSearchAttribute",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.AddSearchAttribute(ctx, request)
+}
+
+// DescribeCluster API call
+func (a *AccessControlledAdminHandler) DescribeCluster(
+	ctx context.Context,
+	request *adminservice.DescribeClusterRequest,
+) (*adminservice.DescribeClusterResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "DescribeCluster",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.DescribeCluster(ctx, request)
+}
+
+// ReadDLQMessages API call
+func (a *AccessControlledAdminHandler) ReadDLQMessages(
+	ctx context.Context,
+	request *adminservice.ReadDLQMessagesRequest,
+) (*adminservice.ReadDLQMessagesResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "ReadDLQMessages",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.ReadDLQMessages(ctx, request)
+}
+
+// PurgeDLQMessages API call
+func (a *AccessControlledAdminHandler) PurgeDLQMessages(
+	ctx context.Context,
+	request *adminservice.PurgeDLQMessagesRequest,
+) (*adminservice.PurgeDLQMessagesResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "PurgeDLQMessages",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.PurgeDLQMessages(ctx, request)
+}
+
+// MergeDLQMessages API call
+func (a *AccessControlledAdminHandler) MergeDLQMessages(
+	ctx context.Context,
+	request *adminservice.MergeDLQMessagesRequest,
+) (*adminservice.MergeDLQMessagesResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "MergeDLQMessages",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.MergeDLQMessages(ctx, request)
+}
+
+// RefreshWorkflowTasks API call
+func (a *AccessControlledAdminHandler) RefreshWorkflowTasks(
+	ctx context.Context,
+	request *adminservice.RefreshWorkflowTasksRequest,
+) (*adminservice.RefreshWorkflowTasksResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "RefreshWorkflowTasks",
+		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.RefreshWorkflowTasks(ctx, request)
+}
+
+// DeleteWorkflowExecution API call
+func (a *AccessControlledAdminHandler) DeleteWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.DeleteWorkflowExecutionRequest,
+) (*adminservice.DeleteWorkflowExecutionResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "DeleteWorkflowExecution",
+		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.DeleteWorkflowExecution(ctx, request)
+}
+
+// ListDynamicConfig API call
+func (a *AccessControlledAdminHandler) ListDynamicConfig(
+	ctx context.Context,
+	request *adminservice.ListDynamicConfigRequest,
+) (*adminservice.ListDynamicConfigResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "ListDynamicConfig",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.ListDynamicConfig(ctx, request)
+}
+
+// ExportWorkflowExecution API call
+func (a *AccessControlledAdminHandler) ExportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ExportWorkflowExecutionRequest,
+) (*adminservice.ExportWorkflowExecutionResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "ExportWorkflowExecution",
+		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.ExportWorkflowExecution(ctx, request)
+}
+
+// ImportWorkflowExecution API call
+func (a *AccessControlledAdminHandler) ImportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ImportWorkflowExecutionRequest,
+) (*adminservice.ImportWorkflowExecutionResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "ImportWorkflowExecution",
+		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.ImportWorkflowExecution(ctx, request)
+}
+
+// ListTaskListTasks API call
+func (a *AccessControlledAdminHandler) ListTaskListTasks(
+	ctx context.Context,
+	request *adminservice.ListTaskListTasksRequest,
+) (*adminservice.ListTaskListTasksResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "ListTaskListTasks",
+		DomainName: request.GetDomain(),
+		TaskList:   request.GetTaskList().GetName(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.ListTaskListTasks(ctx, request)
+}
+
+// CountTaskListTasks API call
+func (a *AccessControlledAdminHandler) CountTaskListTasks(
+	ctx context.Context,
+	request *adminservice.CountTaskListTasksRequest,
+) (*adminservice.CountTaskListTasksResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "CountTaskListTasks",
+		DomainName: request.GetDomain(),
+		TaskList:   request.GetTaskList().GetName(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.CountTaskListTasks(ctx, request)
+}
+
+// DeleteTaskListTasks API call
+func (a *AccessControlledAdminHandler) DeleteTaskListTasks(
+	ctx context.Context,
+	request *adminservice.DeleteTaskListTasksRequest,
+) (*adminservice.DeleteTaskListTasksResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "DeleteTaskListTasks",
+		DomainName: request.GetDomain(),
+		TaskList:   request.GetTaskList().GetName(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.DeleteTaskListTasks(ctx, request)
+}
+
+// MoveTaskListTasks API call
+func (a *AccessControlledAdminHandler) MoveTaskListTasks(
+	ctx context.Context,
+	request *adminservice.MoveTaskListTasksRequest,
+) (*adminservice.MoveTaskListTasksResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "MoveTaskListTasks",
+		DomainName: request.GetDomain(),
+		TaskList:   request.GetTaskList().GetName(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.MoveTaskListTasks(ctx, request)
+}
+
+// UpdateTaskListDispatchConfig API call
+func (a *AccessControlledAdminHandler) UpdateTaskListDispatchConfig(
+	ctx context.Context,
+	request *adminservice.UpdateTaskListDispatchConfigRequest,
+) (*adminservice.UpdateTaskListDispatchConfigResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "UpdateTaskListDispatchConfig",
+		DomainName: request.GetDomain(),
+		TaskList:   request.GetTaskList().GetName(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.UpdateTaskListDispatchConfig(ctx, request)
+}
+
+// DescribeTaskListDispatchConfig API call
+func (a *AccessControlledAdminHandler) DescribeTaskListDispatchConfig(
+	ctx context.Context,
+	request *adminservice.DescribeTaskListDispatchConfigRequest,
+) (*adminservice.DescribeTaskListDispatchConfigResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    authorization.AdminAPIPrefix + "DescribeTaskListDispatchConfig",
+		DomainName: request.GetDomain(),
+		TaskList:   request.GetTaskList().GetName(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.DescribeTaskListDispatchConfig(ctx, request)
+}
+
+// DescribeShardOwnership API call
+func (a *AccessControlledAdminHandler) DescribeShardOwnership(
+	ctx context.Context,
+	request *adminservice.DescribeShardOwnershipRequest,
+) (*adminservice.DescribeShardOwnershipResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "DescribeShardOwnership",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.DescribeShardOwnership(ctx, request)
+}
+
+// DescribeShardLoad API call
+func (a *AccessControlledAdminHandler) DescribeShardLoad(
+	ctx context.Context,
+	request *adminservice.DescribeShardLoadRequest,
+) (*adminservice.DescribeShardLoadResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName: authorization.AdminAPIPrefix + "DescribeShardLoad",
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.adminHandler.DescribeShardLoad(ctx, request)
+}
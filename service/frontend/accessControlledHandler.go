@@ -22,12 +22,16 @@ package frontend
 
 import (
 	"context"
+	"fmt"
 
+	"go.temporal.io/temporal-proto/serviceerror"
 	"go.temporal.io/temporal-proto/workflowservice"
 
 	"github.com/temporalio/temporal/.gen/proto/healthservice"
 	"github.com/temporalio/temporal/common/authorization"
+	"github.com/temporalio/temporal/common/headers"
 	"github.com/temporalio/temporal/common/resource"
+	"github.com/temporalio/temporal/common/rpc"
 )
 
 // TODO(vancexu): add metrics
@@ -38,20 +42,29 @@ type AccessControlledWorkflowHandler struct {
 
 	frontendHandler workflowservice.WorkflowServiceServer
 	authorizer      authorization.Authorizer
+	claimMapper     authorization.ClaimMapper
 }
 
 var _ workflowservice.WorkflowServiceServer = (*AccessControlledWorkflowHandler)(nil)
 
 // NewAccessControlledHandlerImpl creates frontend handler with authentication support
-func NewAccessControlledHandlerImpl(wfHandler *DCRedirectionHandlerImpl, authorizer authorization.Authorizer) *AccessControlledWorkflowHandler {
+func NewAccessControlledHandlerImpl(
+	wfHandler *DCRedirectionHandlerImpl,
+	authorizer authorization.Authorizer,
+	claimMapper authorization.ClaimMapper,
+) *AccessControlledWorkflowHandler {
 	if authorizer == nil {
 		authorizer = authorization.NewNopAuthorizer()
 	}
+	if claimMapper == nil {
+		claimMapper = authorization.NewNopClaimMapper()
+	}
 
 	return &AccessControlledWorkflowHandler{
 		Resource:        wfHandler.Resource,
 		frontendHandler: wfHandler,
 		authorizer:      authorizer,
+		claimMapper:     claimMapper,
 	}
 }
 
@@ -136,6 +149,7 @@ func (a *AccessControlledWorkflowHandler) DescribeTaskList(
 	attr := &authorization.Attributes{
 		APIName:    "DescribeTaskList",
 		DomainName: request.GetDomain(),
+		TaskList:   request.GetTaskList().GetName(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -157,6 +171,7 @@ func (a *AccessControlledWorkflowHandler) DescribeWorkflowExecution(
 	attr := &authorization.Attributes{
 		APIName:    "DescribeWorkflowExecution",
 		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -186,6 +201,7 @@ func (a *AccessControlledWorkflowHandler) GetWorkflowExecutionHistory(
 	attr := &authorization.Attributes{
 		APIName:    "GetWorkflowExecutionHistory",
 		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -207,6 +223,7 @@ func (a *AccessControlledWorkflowHandler) GetWorkflowExecutionRawHistory(
 	attr := &authorization.Attributes{
 		APIName:    "GetWorkflowExecutionRawHistory",
 		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -228,6 +245,7 @@ func (a *AccessControlledWorkflowHandler) PollForWorkflowExecutionRawHistory(
 	attr := &authorization.Attributes{
 		APIName:    "PollForWorkflowExecutionRawHistory",
 		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -353,6 +371,7 @@ func (a *AccessControlledWorkflowHandler) PollForActivityTask(
 	attr := &authorization.Attributes{
 		APIName:    "PollForActivityTask",
 		DomainName: request.GetDomain(),
+		TaskList:   request.GetTaskList().GetName(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -374,6 +393,7 @@ func (a *AccessControlledWorkflowHandler) PollForDecisionTask(
 	attr := &authorization.Attributes{
 		APIName:    "PollForDecisionTask",
 		DomainName: request.GetDomain(),
+		TaskList:   request.GetTaskList().GetName(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -395,6 +415,7 @@ func (a *AccessControlledWorkflowHandler) QueryWorkflow(
 	attr := &authorization.Attributes{
 		APIName:    "QueryWorkflow",
 		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -462,6 +483,7 @@ func (a *AccessControlledWorkflowHandler) RequestCancelWorkflowExecution(
 	attr := &authorization.Attributes{
 		APIName:    "RequestCancelWorkflowExecution",
 		DomainName: request.GetDomain(),
+		WorkflowID: request.GetWorkflowExecution().GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -483,6 +505,7 @@ func (a *AccessControlledWorkflowHandler) ResetStickyTaskList(
 	attr := &authorization.Attributes{
 		APIName:    "ResetStickyTaskList",
 		DomainName: request.GetDomain(),
+		WorkflowID: request.GetExecution().GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -504,6 +527,7 @@ func (a *AccessControlledWorkflowHandler) ResetWorkflowExecution(
 	attr := &authorization.Attributes{
 		APIName:    "ResetWorkflowExecution",
 		DomainName: request.GetDomain(),
+		WorkflowID: request.GetWorkflowExecution().GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -616,8 +640,11 @@ func (a *AccessControlledWorkflowHandler) SignalWithStartWorkflowExecution(
 ) (*workflowservice.SignalWithStartWorkflowExecutionResponse, error) {
 
 	attr := &authorization.Attributes{
-		APIName:    "SignalWithStartWorkflowExecution",
-		DomainName: request.GetDomain(),
+		APIName:      "SignalWithStartWorkflowExecution",
+		DomainName:   request.GetDomain(),
+		WorkflowType: request.GetWorkflowType().GetName(),
+		TaskList:     request.GetTaskList().GetName(),
+		WorkflowID:   request.GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -639,6 +666,7 @@ func (a *AccessControlledWorkflowHandler) SignalWorkflowExecution(
 	attr := &authorization.Attributes{
 		APIName:    "SignalWorkflowExecution",
 		DomainName: request.GetDomain(),
+		WorkflowID: request.GetWorkflowExecution().GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -658,8 +686,11 @@ func (a *AccessControlledWorkflowHandler) StartWorkflowExecution(
 ) (*workflowservice.StartWorkflowExecutionResponse, error) {
 
 	attr := &authorization.Attributes{
-		APIName:    "StartWorkflowExecution",
-		DomainName: request.GetDomain(),
+		APIName:      "StartWorkflowExecution",
+		DomainName:   request.GetDomain(),
+		WorkflowType: request.GetWorkflowType().GetName(),
+		TaskList:     request.GetTaskList().GetName(),
+		WorkflowID:   request.GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -681,6 +712,7 @@ func (a *AccessControlledWorkflowHandler) TerminateWorkflowExecution(
 	attr := &authorization.Attributes{
 		APIName:    "TerminateWorkflowExecution",
 		DomainName: request.GetDomain(),
+		WorkflowID: request.GetWorkflowExecution().GetWorkflowId(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -702,6 +734,7 @@ func (a *AccessControlledWorkflowHandler) ListTaskListPartitions(
 	attr := &authorization.Attributes{
 		APIName:    "ListTaskListPartitions",
 		DomainName: request.GetDomain(),
+		TaskList:   request.GetTaskList().GetName(),
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr)
 	if err != nil {
@@ -739,7 +772,37 @@ func (a *AccessControlledWorkflowHandler) isAuthorized(
 	ctx context.Context,
 	attr *authorization.Attributes,
 ) (bool, error) {
-	result, err := a.authorizer.Authorize(ctx, attr)
+	return isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+}
+
+// isAuthorized fills in the request metadata and the principal of the attributes and asks the authorizer for a decision.
+// It is shared by the workflow and admin access controlled handlers.
+func isAuthorized(
+	ctx context.Context,
+	authorizer authorization.Authorizer,
+	claimMapper authorization.ClaimMapper,
+	attr *authorization.Attributes,
+) (bool, error) {
+	attr.RequestMetadata = headers.GetAll(ctx)
+	authToken := attr.RequestMetadata[authorization.AuthorizationHeaderName]
+	// the token must not be visible to the authorizer
+	delete(attr.RequestMetadata, authorization.AuthorizationHeaderName)
+
+	principal, err := claimMapper.GetPrincipal(&authorization.AuthInfo{
+		AuthToken:          authToken,
+		TLSPeerCertificate: rpc.GetPeerCertificate(ctx),
+	})
+	if err != nil {
+		return false, serviceerror.NewPermissionDenied(fmt.Sprintf("Invalid credentials: %v.", err))
+	}
+	// the actor is only ever taken from verified credentials, requests without credentials
+	// have no actor and are not bound to any role of the policy
+	if principal != nil {
+		attr.Principal = principal
+		attr.Actor = principal.Name
+	}
+
+	result, err := authorizer.Authorize(ctx, attr)
 	if err != nil {
 		return false, err
 	}
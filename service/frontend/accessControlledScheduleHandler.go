@@ -0,0 +1,229 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+
+	"github.com/temporalio/temporal/.gen/proto/scheduleservice"
+	"github.com/temporalio/temporal/common/authorization"
+)
+
+var _ scheduleservice.ScheduleServiceServer = (*AccessControlledScheduleHandler)(nil)
+
+type (
+	// AccessControlledScheduleHandler schedule handler wrapper for authentication and authorization
+	AccessControlledScheduleHandler struct {
+		scheduleHandler scheduleservice.ScheduleServiceServer
+		authorizer      authorization.Authorizer
+		claimMapper     authorization.ClaimMapper
+	}
+)
+
+// NewAccessControlledScheduleHandler creates schedule handler with authorization support
+func NewAccessControlledScheduleHandler(
+	scheduleHandler scheduleservice.ScheduleServiceServer,
+	authorizer authorization.Authorizer,
+	claimMapper authorization.ClaimMapper,
+) *AccessControlledScheduleHandler {
+	if authorizer == nil {
+		authorizer = authorization.NewNopAuthorizer()
+	}
+	if claimMapper == nil {
+		claimMapper = authorization.NewNopClaimMapper()
+	}
+
+	return &AccessControlledScheduleHandler{
+		scheduleHandler: scheduleHandler,
+		authorizer:      authorizer,
+		claimMapper:     claimMapper,
+	}
+}
+
+// CreateSchedule API call
+func (a *AccessControlledScheduleHandler) CreateSchedule(
+	ctx context.Context,
+	request *scheduleservice.CreateScheduleRequest,
+) (*scheduleservice.CreateScheduleResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    "CreateSchedule",
+		DomainName: request.GetDomain(),
+		WorkflowID: request.GetSchedule().GetAction().GetWorkflowId(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.scheduleHandler.CreateSchedule(ctx, request)
+}
+
+// DescribeSchedule API call
+func (a *AccessControlledScheduleHandler) DescribeSchedule(
+	ctx context.Context,
+	request *scheduleservice.DescribeScheduleRequest,
+) (*scheduleservice.DescribeScheduleResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    "DescribeSchedule",
+		DomainName: request.GetDomain(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.scheduleHandler.DescribeSchedule(ctx, request)
+}
+
+// UpdateSchedule API call
+func (a *AccessControlledScheduleHandler) UpdateSchedule(
+	ctx context.Context,
+	request *scheduleservice.UpdateScheduleRequest,
+) (*scheduleservice.UpdateScheduleResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    "UpdateSchedule",
+		DomainName: request.GetDomain(),
+		WorkflowID: request.GetSchedule().GetAction().GetWorkflowId(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.scheduleHandler.UpdateSchedule(ctx, request)
+}
+
+// PauseSchedule API call
+func (a *AccessControlledScheduleHandler) PauseSchedule(
+	ctx context.Context,
+	request *scheduleservice.PauseScheduleRequest,
+) (*scheduleservice.PauseScheduleResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    "PauseSchedule",
+		DomainName: request.GetDomain(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.scheduleHandler.PauseSchedule(ctx, request)
+}
+
+// ResumeSchedule API call
+func (a *AccessControlledScheduleHandler) ResumeSchedule(
+	ctx context.Context,
+	request *scheduleservice.ResumeScheduleRequest,
+) (*scheduleservice.ResumeScheduleResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    "ResumeSchedule",
+		DomainName: request.GetDomain(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.scheduleHandler.ResumeSchedule(ctx, request)
+}
+
+// BackfillSchedule API call
+func (a *AccessControlledScheduleHandler) BackfillSchedule(
+	ctx context.Context,
+	request *scheduleservice.BackfillScheduleRequest,
+) (*scheduleservice.BackfillScheduleResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    "BackfillSchedule",
+		DomainName: request.GetDomain(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.scheduleHandler.BackfillSchedule(ctx, request)
+}
+
+// DeleteSchedule API call
+func (a *AccessControlledScheduleHandler) DeleteSchedule(
+	ctx context.Context,
+	request *scheduleservice.DeleteScheduleRequest,
+) (*scheduleservice.DeleteScheduleResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    "DeleteSchedule",
+		DomainName: request.GetDomain(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.scheduleHandler.DeleteSchedule(ctx, request)
+}
+
+// ListSchedules API call
+func (a *AccessControlledScheduleHandler) ListSchedules(
+	ctx context.Context,
+	request *scheduleservice.ListSchedulesRequest,
+) (*scheduleservice.ListSchedulesResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    "ListSchedules",
+		DomainName: request.GetDomain(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.scheduleHandler.ListSchedules(ctx, request)
+}
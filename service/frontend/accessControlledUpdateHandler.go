@@ -0,0 +1,81 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+
+	"github.com/temporalio/temporal/.gen/proto/updateservice"
+	"github.com/temporalio/temporal/common/authorization"
+)
+
+var _ updateservice.UpdateServiceServer = (*AccessControlledUpdateHandler)(nil)
+
+type (
+	// AccessControlledUpdateHandler update handler wrapper for authentication and authorization
+	AccessControlledUpdateHandler struct {
+		updateHandler updateservice.UpdateServiceServer
+		authorizer    authorization.Authorizer
+		claimMapper   authorization.ClaimMapper
+	}
+)
+
+// NewAccessControlledUpdateHandler creates update handler with authorization support
+func NewAccessControlledUpdateHandler(
+	updateHandler updateservice.UpdateServiceServer,
+	authorizer authorization.Authorizer,
+	claimMapper authorization.ClaimMapper,
+) *AccessControlledUpdateHandler {
+	if authorizer == nil {
+		authorizer = authorization.NewNopAuthorizer()
+	}
+	if claimMapper == nil {
+		claimMapper = authorization.NewNopClaimMapper()
+	}
+
+	return &AccessControlledUpdateHandler{
+		updateHandler: updateHandler,
+		authorizer:    authorizer,
+		claimMapper:   claimMapper,
+	}
+}
+
+// UpdateWorkflowExecution API call
+func (a *AccessControlledUpdateHandler) UpdateWorkflowExecution(
+	ctx context.Context,
+	request *updateservice.UpdateWorkflowExecutionRequest,
+) (*updateservice.UpdateWorkflowExecutionResponse, error) {
+
+	attr := &authorization.Attributes{
+		APIName:    "UpdateWorkflowExecution",
+		DomainName: request.GetDomain(),
+		WorkflowID: request.GetWorkflowExecution().GetWorkflowId(),
+	}
+	isAuthorized, err := isAuthorized(ctx, a.authorizer, a.claimMapper, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.updateHandler.UpdateWorkflowExecution(ctx, request)
+}
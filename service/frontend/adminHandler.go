@@ -22,18 +22,24 @@ package frontend
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
 	"strconv"
 	"time"
 
+	"github.com/gogo/protobuf/types"
 	"github.com/olivere/elastic"
 	"github.com/pborman/uuid"
 	commonproto "go.temporal.io/temporal-proto/common"
 	"go.temporal.io/temporal-proto/enums"
 	"go.temporal.io/temporal-proto/serviceerror"
+	"go.temporal.io/temporal-proto/workflowservice"
 
 	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
 	"github.com/temporalio/temporal/.gen/proto/replication"
 	"github.com/temporalio/temporal/.gen/proto/token"
 	"github.com/temporalio/temporal/common"
@@ -45,6 +51,8 @@ import (
 	"github.com/temporalio/temporal/common/log/tag"
 	"github.com/temporalio/temporal/common/metrics"
 	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+	"github.com/temporalio/temporal/common/primitives/timestamp"
 	"github.com/temporalio/temporal/common/resource"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
 	"github.com/temporalio/temporal/service/history"
@@ -978,6 +986,734 @@ func (adh *AdminHandler) RefreshWorkflowTasks(
 	return &adminservice.RefreshWorkflowTasksResponse{}, nil
 }
 
+// DeleteWorkflowExecution deletes the mutable state, the history and the visibility record of a closed workflow execution
+func (adh *AdminHandler) DeleteWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.DeleteWorkflowExecutionRequest,
+) (_ *adminservice.DeleteWorkflowExecutionResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminDeleteWorkflowExecutionScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateExecution(request.Execution); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	_, err = adh.GetHistoryClient().DeleteWorkflowExecution(ctx, &historyservice.DeleteWorkflowExecutionRequest{
+		DomainUUID: domainID,
+		Request:    request,
+	})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.DeleteWorkflowExecutionResponse{}, nil
+}
+
+// ExportWorkflowExecution returns the raw history of the current branch of a workflow execution so that it can be
+// imported into another cluster
+func (adh *AdminHandler) ExportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ExportWorkflowExecutionRequest,
+) (_ *adminservice.ExportWorkflowExecutionResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminExportWorkflowExecutionScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateExecution(request.Execution); err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	rawHistoryRequest := &adminservice.GetWorkflowExecutionRawHistoryV2Request{
+		Domain:          request.GetDomain(),
+		Execution:       request.Execution,
+		MaximumPageSize: request.GetMaximumPageSize(),
+		NextPageToken:   request.NextPageToken,
+	}
+	if request.NextPageToken == nil {
+		domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+		if err != nil {
+			return nil, adh.error(err, scope)
+		}
+		response, err := adh.GetHistoryClient().GetMutableState(ctx, &historyservice.GetMutableStateRequest{
+			DomainUUID: domainID,
+			Execution:  request.Execution,
+		})
+		if err != nil {
+			return nil, adh.error(err, scope)
+		}
+		currentVersionHistory, err := persistence.NewVersionHistoriesFromProto(
+			response.GetVersionHistories(),
+		).GetCurrentVersionHistory()
+		if err != nil {
+			return nil, adh.error(err, scope)
+		}
+		lastItem, err := currentVersionHistory.GetLastItem()
+		if err != nil {
+			return nil, adh.error(err, scope)
+		}
+
+		// export the whole current branch, the raw history API is exclusive-exclusive
+		rawHistoryRequest.Execution = response.GetExecution()
+		rawHistoryRequest.StartEventId = common.EmptyEventID
+		rawHistoryRequest.StartEventVersion = common.EmptyVersion
+		rawHistoryRequest.EndEventId = lastItem.GetEventID() + 1
+		rawHistoryRequest.EndEventVersion = lastItem.GetVersion()
+	} else {
+		pageToken, err := deserializeRawHistoryToken(request.NextPageToken)
+		if err != nil {
+			return nil, adh.error(errInvalidNextPageToken, scope)
+		}
+		if request.Execution.GetRunId() == "" {
+			rawHistoryRequest.Execution = &commonproto.WorkflowExecution{
+				WorkflowId: request.Execution.GetWorkflowId(),
+				RunId:      pageToken.GetRunId(),
+			}
+		}
+		rawHistoryRequest.StartEventId = pageToken.GetStartEventId()
+		rawHistoryRequest.StartEventVersion = pageToken.GetStartEventVersion()
+		rawHistoryRequest.EndEventId = pageToken.GetEndEventId()
+		rawHistoryRequest.EndEventVersion = pageToken.GetEndEventVersion()
+	}
+
+	rawHistoryResponse, err := adh.GetWorkflowExecutionRawHistoryV2(ctx, rawHistoryRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &adminservice.ExportWorkflowExecutionResponse{
+		Execution:      rawHistoryRequest.Execution,
+		VersionHistory: rawHistoryResponse.GetVersionHistory(),
+		HistoryBatches: rawHistoryResponse.GetHistoryBatches(),
+		NextPageToken:  rawHistoryResponse.GetNextPageToken(),
+	}, nil
+}
+
+// ImportWorkflowExecution recreates a workflow execution from exported raw history through the replication path
+func (adh *AdminHandler) ImportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ImportWorkflowExecutionRequest,
+) (_ *adminservice.ImportWorkflowExecutionResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminImportWorkflowExecutionScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateExecution(request.Execution); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if request.Execution.GetRunId() == "" {
+		return nil, adh.error(errInvalidRunID, scope)
+	}
+	if len(request.GetVersionHistory().GetItems()) == 0 {
+		return nil, adh.error(errInvalidVersionHistories, scope)
+	}
+	if len(request.HistoryBatches) == 0 {
+		return nil, adh.error(errHistoryBatchesNotSet, scope)
+	}
+	// domain IDs are not shared by independent clusters, the events are imported into the domain of the same name
+	domainEntry, err := adh.GetDomainCache().GetDomain(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if err := domainEntry.GetDomainNotActiveErr(); err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	// the failover versions of the source cluster are unknown to this cluster, so the events are rewritten to the
+	// failover version of the target domain as if the workflow had always run in it
+	targetVersion := domainEntry.GetFailoverVersion()
+	items := request.VersionHistory.GetItems()
+	versionHistoryItems := []*commonproto.VersionHistoryItem{{
+		EventID: items[len(items)-1].GetEventID(),
+		Version: targetVersion,
+	}}
+	for _, batch := range request.HistoryBatches {
+		events, err := adh.rewriteEventVersions(batch, targetVersion)
+		if err != nil {
+			return nil, adh.error(err, scope)
+		}
+		if _, err := adh.GetHistoryClient().ReplicateEventsV2(ctx, &historyservice.ReplicateEventsV2Request{
+			DomainUUID:          domainEntry.GetInfo().ID,
+			WorkflowExecution:   request.Execution,
+			VersionHistoryItems: versionHistoryItems,
+			Events:              events,
+		}); err != nil {
+			return nil, adh.error(err, scope)
+		}
+	}
+	return &adminservice.ImportWorkflowExecutionResponse{}, nil
+}
+
+func (adh *AdminHandler) rewriteEventVersions(
+	batch *commonproto.DataBlob,
+	version int64,
+) (*commonproto.DataBlob, error) {
+
+	blob := persistence.NewDataBlobFromProto(batch)
+	events, err := adh.GetPayloadSerializer().DeserializeBatchEvents(blob)
+	if err != nil {
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("Invalid history batch: %v", err))
+	}
+	for _, event := range events {
+		event.Version = version
+	}
+	blob, err = adh.GetPayloadSerializer().SerializeBatchEvents(events, blob.GetEncoding())
+	if err != nil {
+		return nil, err
+	}
+	return blob.ToProto(), nil
+}
+
+// ListTaskListTasks returns the backlog tasks of a task list and its subqueues page by page
+func (adh *AdminHandler) ListTaskListTasks(
+	ctx context.Context,
+	request *adminservice.ListTaskListTasksRequest,
+) (_ *adminservice.ListTaskListTasksResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminListTaskListTasksScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateTaskListBacklogRequest(request.TaskList, request.GetTaskListType()); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	tasks, nextPageToken, err := adh.readTaskListTasks(
+		ctx,
+		domainID,
+		request.TaskList,
+		request.GetTaskListType(),
+		request.GetMaximumPageSize(),
+		request.NextPageToken,
+	)
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	response := &adminservice.ListTaskListTasksResponse{
+		NextPageToken: nextPageToken,
+	}
+	for _, task := range tasks {
+		response.Tasks = append(response.Tasks, &adminservice.TaskListTask{
+			TaskId: task.Task.GetTaskID(),
+			Execution: &commonproto.WorkflowExecution{
+				WorkflowId: task.Task.Data.GetWorkflowID(),
+				RunId:      primitives.UUIDString(task.Task.Data.GetRunID()),
+			},
+			ScheduleId:          task.Task.Data.GetScheduleID(),
+			CreatedTimeUnixNano: timestampToUnixNano(task.Task.Data.GetCreatedTime()),
+			ExpiryUnixNano:      timestampToUnixNano(task.Task.Data.GetExpiry()),
+			Priority:            task.Task.Data.GetPriority(),
+			FairnessKey:         task.Task.Data.GetFairnessKey(),
+			Backlog:             task.GetBacklog(),
+		})
+	}
+	return response, nil
+}
+
+// CountTaskListTasks counts the backlog tasks of a task list and its subqueues by workflow type page by page
+func (adh *AdminHandler) CountTaskListTasks(
+	ctx context.Context,
+	request *adminservice.CountTaskListTasksRequest,
+) (_ *adminservice.CountTaskListTasksResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminCountTaskListTasksScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateTaskListBacklogRequest(request.TaskList, request.GetTaskListType()); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	tasks, nextPageToken, err := adh.readTaskListTasks(
+		ctx,
+		domainID,
+		request.TaskList,
+		request.GetTaskListType(),
+		request.GetMaximumPageSize(),
+		request.NextPageToken,
+	)
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	// tasks written before the workflow type was persisted with them need a lookup, done once per run
+	workflowTypes := make(map[string]string)
+	counts := make(map[string]int64)
+	for _, task := range tasks {
+		workflowType := task.Task.Data.GetWorkflowType()
+		if workflowType == "" {
+			runID := primitives.UUIDString(task.Task.Data.GetRunID())
+			var ok bool
+			if workflowType, ok = workflowTypes[runID]; !ok {
+				resp, err := adh.GetHistoryClient().GetMutableState(ctx, &historyservice.GetMutableStateRequest{
+					DomainUUID: primitives.UUIDString(task.Task.Data.GetDomainID()),
+					Execution: &commonproto.WorkflowExecution{
+						WorkflowId: task.Task.Data.GetWorkflowID(),
+						RunId:      runID,
+					},
+				})
+				if err != nil {
+					if _, ok := err.(*serviceerror.NotFound); !ok {
+						return nil, adh.error(err, scope)
+					}
+				}
+				workflowType = resp.GetWorkflowType().GetName()
+				workflowTypes[runID] = workflowType
+			}
+		}
+		counts[workflowType]++
+	}
+	return &adminservice.CountTaskListTasksResponse{
+		CountByWorkflowType: counts,
+		NextPageToken:       nextPageToken,
+	}, nil
+}
+
+// DeleteTaskListTasks drops the given tasks from the backlog of a task list or one of its subqueues. The
+// tasks are dropped by the matching host owning the task list, so tasks it already read are not dispatched.
+func (adh *AdminHandler) DeleteTaskListTasks(
+	ctx context.Context,
+	request *adminservice.DeleteTaskListTasksRequest,
+) (_ *adminservice.DeleteTaskListTasksResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminDeleteTaskListTasksScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateTaskListBacklogRequest(request.TaskList, request.GetTaskListType()); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if len(request.TaskIds) == 0 {
+		return nil, adh.error(errTaskIDsNotSet, scope)
+	}
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	if _, err := adh.GetMatchingClient().DeleteTaskListTasks(ctx, &matchingservice.DeleteTaskListTasksRequest{
+		DomainUUID:   domainID,
+		TaskListType: int32(request.GetTaskListType()),
+		TaskList:     request.TaskList,
+		Backlog:      request.GetBacklog(),
+		TaskIds:      request.TaskIds,
+	}); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.DeleteTaskListTasksResponse{}, nil
+}
+
+// MoveTaskListTasks moves the given tasks from the backlog of a task list or one of its subqueues to another
+// task list. The tasks are added to the target task list through matching, so they get task IDs of the target
+// task list, and are then dropped from the source backlog by the matching host owning the source task list.
+func (adh *AdminHandler) MoveTaskListTasks(
+	ctx context.Context,
+	request *adminservice.MoveTaskListTasksRequest,
+) (_ *adminservice.MoveTaskListTasksResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminMoveTaskListTasksScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateTaskListBacklogRequest(request.TaskList, request.GetTaskListType()); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if len(request.TaskIds) == 0 {
+		return nil, adh.error(errTaskIDsNotSet, scope)
+	}
+	if request.TargetTaskList.GetName() == "" {
+		return nil, adh.error(errTargetTaskListNotSet, scope)
+	}
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	var movedCount int32
+	for _, taskID := range request.TaskIds {
+		moved, err := adh.moveTaskListTask(ctx, domainID, request, taskID)
+		if err != nil {
+			return nil, adh.error(err, scope)
+		}
+		if moved {
+			movedCount++
+		}
+	}
+	return &adminservice.MoveTaskListTasksResponse{MovedCount: movedCount}, nil
+}
+
+func (adh *AdminHandler) moveTaskListTask(
+	ctx context.Context,
+	domainID string,
+	request *adminservice.MoveTaskListTasksRequest,
+	taskID int64,
+) (bool, error) {
+
+	// the backlog of a subqueue is persisted as a task list named after the subqueue
+	backlog := request.GetBacklog()
+	if backlog == "" {
+		backlog = request.TaskList.GetName()
+	}
+	resp, err := adh.GetTaskManager().GetTasks(&persistence.GetTasksRequest{
+		DomainID:     primitives.MustParseUUID(domainID),
+		TaskList:     backlog,
+		TaskType:     int32(request.GetTaskListType()),
+		ReadLevel:    taskID - 1,
+		MaxReadLevel: common.Int64Ptr(taskID),
+		BatchSize:    1,
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(resp.Tasks) == 0 || resp.Tasks[0].GetTaskID() != taskID {
+		return false, nil
+	}
+	task := resp.Tasks[0].Data
+
+	scheduleToStartTimeout := int32(common.MaxTaskTimeout)
+	if task.GetExpiry() != nil {
+		expiry := time.Unix(0, timestampToUnixNano(task.GetExpiry()))
+		remaining := int64(expiry.Sub(adh.GetTimeSource().Now()) / time.Second)
+		if remaining <= 0 {
+			// expired tasks are dropped by matching on dispatch anyway
+			return false, nil
+		}
+		scheduleToStartTimeout = int32(common.MinInt64(remaining, common.MaxTaskTimeout))
+	}
+
+	execution := &commonproto.WorkflowExecution{
+		WorkflowId: task.GetWorkflowID(),
+		RunId:      primitives.UUIDString(task.GetRunID()),
+	}
+	if request.GetTaskListType() == enums.TaskListTypeActivity {
+		_, err = adh.GetMatchingClient().AddActivityTask(ctx, &matchingservice.AddActivityTaskRequest{
+			DomainUUID:                    domainID,
+			SourceDomainUUID:              primitives.UUIDString(task.GetDomainID()),
+			Execution:                     execution,
+			TaskList:                      request.TargetTaskList,
+			ScheduleId:                    task.GetScheduleID(),
+			ScheduleToStartTimeoutSeconds: scheduleToStartTimeout,
+			Priority:                      task.GetPriority(),
+			FairnessKey:                   task.GetFairnessKey(),
+			WorkflowType:                  task.GetWorkflowType(),
+		})
+	} else {
+		_, err = adh.GetMatchingClient().AddDecisionTask(ctx, &matchingservice.AddDecisionTaskRequest{
+			DomainUUID:                    primitives.UUIDString(task.GetDomainID()),
+			Execution:                     execution,
+			TaskList:                      request.TargetTaskList,
+			ScheduleId:                    task.GetScheduleID(),
+			ScheduleToStartTimeoutSeconds: scheduleToStartTimeout,
+			Priority:                      task.GetPriority(),
+			FairnessKey:                   task.GetFairnessKey(),
+			WorkflowType:                  task.GetWorkflowType(),
+		})
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := adh.GetMatchingClient().DeleteTaskListTasks(ctx, &matchingservice.DeleteTaskListTasksRequest{
+		DomainUUID:   domainID,
+		TaskListType: int32(request.GetTaskListType()),
+		TaskList:     request.TaskList,
+		Backlog:      request.GetBacklog(),
+		TaskIds:      []int64{taskID},
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// readTaskListTasks reads a page of the backlog of a task list and its subqueues from the matching host owning the task list
+func (adh *AdminHandler) readTaskListTasks(
+	ctx context.Context,
+	domainID string,
+	taskList *commonproto.TaskList,
+	taskListType enums.TaskListType,
+	pageSize int32,
+	nextPageToken []byte,
+) ([]*matchingservice.BacklogTask, []byte, error) {
+
+	if pageSize <= 0 {
+		return nil, nil, errInvalidPageSize
+	}
+
+	resp, err := adh.GetMatchingClient().ListTaskListTasks(ctx, &matchingservice.ListTaskListTasksRequest{
+		DomainUUID:      domainID,
+		TaskListType:    int32(taskListType),
+		TaskList:        taskList,
+		MaximumPageSize: pageSize,
+		NextPageToken:   nextPageToken,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Tasks, resp.NextPageToken, nil
+}
+
+// UpdateTaskListDispatchConfig sets the dispatch rate and the max concurrent pollers of a task list. The config is
+// persisted with the root partition, the other partitions read it from there and each applies its share of the limits.
+func (adh *AdminHandler) UpdateTaskListDispatchConfig(
+	ctx context.Context,
+	request *adminservice.UpdateTaskListDispatchConfigRequest,
+) (_ *adminservice.UpdateTaskListDispatchConfigResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminUpdateTaskListDispatchConfigScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateTaskListBacklogRequest(request.TaskList, request.GetTaskListType()); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if err := validateTaskListDispatchConfig(request.DispatchConfig); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	if _, err := adh.GetMatchingClient().UpdateTaskListDispatchConfig(ctx, &matchingservice.UpdateTaskListDispatchConfigRequest{
+		DomainUUID:     domainID,
+		TaskListType:   int32(request.GetTaskListType()),
+		TaskList:       &commonproto.TaskList{Name: request.TaskList.GetName(), Kind: enums.TaskListKindNormal},
+		DispatchConfig: request.DispatchConfig,
+	}); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.UpdateTaskListDispatchConfigResponse{}, nil
+}
+
+// DescribeTaskListDispatchConfig returns the dispatch config of a task list together with the current
+// dispatch rate and outstanding pollers of each of its partitions
+func (adh *AdminHandler) DescribeTaskListDispatchConfig(
+	ctx context.Context,
+	request *adminservice.DescribeTaskListDispatchConfigRequest,
+) (_ *adminservice.DescribeTaskListDispatchConfigResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminDescribeTaskListDispatchConfigScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateTaskListBacklogRequest(request.TaskList, request.GetTaskListType()); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	partitions, err := adh.getTaskListPartitions(ctx, request.GetDomain(), request.TaskList, request.GetTaskListType())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	response := &adminservice.DescribeTaskListDispatchConfigResponse{}
+	for _, partition := range partitions {
+		resp, err := adh.GetMatchingClient().DescribeTaskList(ctx, &matchingservice.DescribeTaskListRequest{
+			DomainUUID: domainID,
+			DescRequest: &workflowservice.DescribeTaskListRequest{
+				Domain:                request.GetDomain(),
+				TaskList:              &commonproto.TaskList{Name: partition, Kind: enums.TaskListKindNormal},
+				TaskListType:          request.GetTaskListType(),
+				IncludeTaskListStatus: true,
+			},
+		})
+		if err != nil {
+			return nil, adh.error(err, scope)
+		}
+		// partitions are listed root first, the root holds the config as last set by operators
+		if response.DispatchConfig == nil {
+			response.DispatchConfig = resp.GetDispatchConfig()
+		}
+		response.Partitions = append(response.Partitions, &adminservice.TaskListPartitionDispatchStatus{
+			Partition:          partition,
+			RatePerSecond:      resp.GetTaskListStatus().GetRatePerSecond(),
+			OutstandingPollers: resp.GetOutstandingPollers(),
+		})
+	}
+	return response, nil
+}
+
+// DescribeShardOwnership returns the current owner, lease and ownership history of a history shard
+func (adh *AdminHandler) DescribeShardOwnership(
+	ctx context.Context,
+	request *adminservice.DescribeShardOwnershipRequest,
+) (_ *adminservice.DescribeShardOwnershipResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminDescribeShardOwnershipScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetShardID() < 0 || int(request.GetShardID()) >= adh.numberOfHistoryShards {
+		return nil, adh.error(errInvalidShardID, scope)
+	}
+
+	resp, err := adh.GetShardManager().GetShard(&persistence.GetShardRequest{ShardID: request.GetShardID()})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.DescribeShardOwnershipResponse{
+		Owner:            resp.ShardInfo.GetOwner(),
+		RangeID:          resp.ShardInfo.GetRangeID(),
+		LeaseExpiry:      resp.ShardInfo.GetLeaseExpiry(),
+		Released:         resp.ShardInfo.GetReleased(),
+		OwnershipHistory: resp.ShardInfo.GetOwnershipHistory(),
+	}, nil
+}
+
+// DescribeShardLoad returns the request rates, cache and persistence usage and queue lag of a history shard
+func (adh *AdminHandler) DescribeShardLoad(
+	ctx context.Context,
+	request *adminservice.DescribeShardLoadRequest,
+) (_ *adminservice.DescribeShardLoadResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminDescribeShardLoadScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetShardID() < 0 || int(request.GetShardID()) >= adh.numberOfHistoryShards {
+		return nil, adh.error(errInvalidShardID, scope)
+	}
+
+	resp, err := adh.GetHistoryClient().DescribeShardLoad(ctx, &historyservice.DescribeShardLoadRequest{
+		ShardID: request.GetShardID(),
+	})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.DescribeShardLoadResponse{
+		ShardLoad: resp.ShardLoad,
+	}, nil
+}
+
+// getTaskListPartitions returns the names of the partitions of a task list, starting with the root partition
+func (adh *AdminHandler) getTaskListPartitions(
+	ctx context.Context,
+	domainName string,
+	taskList *commonproto.TaskList,
+	taskListType enums.TaskListType,
+) ([]string, error) {
+
+	resp, err := adh.GetMatchingClient().ListTaskListPartitions(ctx, &matchingservice.ListTaskListPartitionsRequest{
+		Domain:   domainName,
+		TaskList: taskList,
+	})
+	if err != nil {
+		return nil, err
+	}
+	partitionMetadata := resp.GetDecisionTaskListPartitions()
+	if taskListType == enums.TaskListTypeActivity {
+		partitionMetadata = resp.GetActivityTaskListPartitions()
+	}
+	if len(partitionMetadata) == 0 {
+		return []string{taskList.GetName()}, nil
+	}
+	partitions := make([]string, 0, len(partitionMetadata))
+	for _, partition := range partitionMetadata {
+		partitions = append(partitions, partition.GetKey())
+	}
+	return partitions, nil
+}
+
+// ListDynamicConfig returns the effective dynamic config values for the given set of filters
+func (adh *AdminHandler) ListDynamicConfig(
+	ctx context.Context,
+	request *adminservice.ListDynamicConfigRequest,
+) (_ *adminservice.ListDynamicConfigResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminListDynamicConfigScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+
+	filters := make(map[dynamicconfig.Filter]interface{}, len(request.Filters))
+	for _, f := range request.Filters {
+		filter := dynamicconfig.ParseFilter(f.GetName())
+		if filter.String() != f.GetName() {
+			return nil, adh.error(errUnknownDynamicConfigFilter.MessageArgs(f.GetName()), scope)
+		}
+		value, err := dynamicconfig.ParseFilterValue(filter, f.GetValue())
+		if err != nil {
+			return nil, adh.error(errInvalidDynamicConfigFilter.MessageArgs(err), scope)
+		}
+		filters[filter] = value
+	}
+
+	var key dynamicconfig.Key
+	if request.GetName() != "" {
+		key = dynamicconfig.ParseKey(request.GetName())
+		if key.String() != request.GetName() {
+			return nil, adh.error(errUnknownDynamicConfigKey.MessageArgs(request.GetName()), scope)
+		}
+	}
+
+	values, err := adh.params.DynamicConfig.ListValues(filters)
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	var entries []*adminservice.DynamicConfigEntry
+	for k, v := range values {
+		if request.GetName() != "" && k != key {
+			continue
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, adh.error(serviceerror.NewInternal(err.Error()), scope)
+		}
+		entries = append(entries, &adminservice.DynamicConfigEntry{
+			Name:  k.String(),
+			Value: string(data),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return &adminservice.ListDynamicConfigResponse{
+		Entries: entries,
+	}, nil
+}
+
 func (adh *AdminHandler) validateGetWorkflowExecutionRawHistoryV2Request(
 	request *adminservice.GetWorkflowExecutionRawHistoryV2Request,
 ) error {
@@ -1168,3 +1904,10 @@ func (adh *AdminHandler) checkPermission(
 	}
 	return nil
 }
+
+func timestampToUnixNano(ts *types.Timestamp) int64 {
+	if ts == nil {
+		return 0
+	}
+	return timestamp.TimestampFromProto(ts).UnixNano()
+}
@@ -25,10 +25,12 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/temporalio/temporal/.gen/proto/replication"
 	"github.com/temporalio/temporal/common/persistence/serialization"
 
+	"github.com/gogo/protobuf/types"
 	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/mock"
@@ -41,14 +43,18 @@ import (
 	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
 	"github.com/temporalio/temporal/.gen/proto/historyservicemock"
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/cluster"
 	"github.com/temporalio/temporal/common/definition"
 	"github.com/temporalio/temporal/common/elasticsearch"
 	esmock "github.com/temporalio/temporal/common/elasticsearch/mocks"
 	"github.com/temporalio/temporal/common/metrics"
 	"github.com/temporalio/temporal/common/mocks"
 	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
 	"github.com/temporalio/temporal/common/resource"
 	"github.com/temporalio/temporal/common/service/config"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
@@ -300,6 +306,451 @@ func (s *adminHandlerSuite) Test_GetWorkflowExecutionRawHistoryV2_SameStartIDAnd
 	s.NoError(err)
 }
 
+func (s *adminHandlerSuite) Test_ExportWorkflowExecution() {
+	ctx := context.Background()
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(s.domainID, nil).AnyTimes()
+	branchToken := []byte{1}
+	versionHistory := persistence.NewVersionHistory(branchToken, []*persistence.VersionHistoryItem{
+		persistence.NewVersionHistoryItem(int64(10), int64(100)),
+	})
+	execution := &commonproto.WorkflowExecution{
+		WorkflowId: "workflowID",
+		RunId:      uuid.New(),
+	}
+	mState := &historyservice.GetMutableStateResponse{
+		Execution:          execution,
+		NextEventId:        11,
+		CurrentBranchToken: branchToken,
+		VersionHistories:   persistence.NewVersionHistories(versionHistory).ToProto(),
+		ReplicationInfo:    make(map[string]*replication.ReplicationInfo),
+	}
+	s.mockHistoryClient.EXPECT().GetMutableState(gomock.Any(), gomock.Any()).Return(mState, nil).AnyTimes()
+	s.mockHistoryV2Mgr.On("ReadRawHistoryBranch", mock.MatchedBy(func(request *persistence.ReadHistoryBranchRequest) bool {
+		return request.MinEventID == common.FirstEventID && request.MaxEventID == 11
+	})).Return(&persistence.ReadRawHistoryBranchResponse{
+		HistoryEventBlobs: []*serialization.DataBlob{{Encoding: common.EncodingTypeProto3, Data: []byte{1}}},
+		NextPageToken:     []byte{},
+		Size:              1,
+	}, nil)
+
+	resp, err := s.handler.ExportWorkflowExecution(ctx, &adminservice.ExportWorkflowExecutionRequest{
+		Domain: s.domainName,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: "workflowID",
+		},
+		MaximumPageSize: 10,
+	})
+	s.NoError(err)
+	s.Equal(execution, resp.Execution)
+	s.Equal([]*commonproto.DataBlob{{EncodingType: enums.EncodingTypeProto3, Data: []byte{1}}}, resp.HistoryBatches)
+	s.Equal(versionHistory.ToProto(), resp.VersionHistory)
+	s.Nil(resp.NextPageToken)
+}
+
+func (s *adminHandlerSuite) Test_ImportWorkflowExecution_FailedOnMissingHistory() {
+	ctx := context.Background()
+	_, err := s.handler.ImportWorkflowExecution(ctx, &adminservice.ImportWorkflowExecutionRequest{
+		Domain: s.domainName,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: "workflowID",
+			RunId:      uuid.New(),
+		},
+		VersionHistory: &commonproto.VersionHistory{
+			Items: []*commonproto.VersionHistoryItem{{EventID: 10, Version: 100}},
+		},
+	})
+	s.Equal(errHistoryBatchesNotSet, err)
+}
+
+func (s *adminHandlerSuite) Test_ImportWorkflowExecution_FailedOnPassiveDomain() {
+	ctx := context.Background()
+	s.mockDomainCache.EXPECT().GetDomain(s.domainName).Return(cache.NewGlobalDomainCacheEntryForTest(
+		&persistence.DomainInfo{ID: s.domainID, Name: s.domainName},
+		&persistence.DomainConfig{},
+		&persistence.DomainReplicationConfig{ActiveClusterName: cluster.TestAlternativeClusterName},
+		int64(2),
+		s.mockResource.ClusterMetadata,
+	), nil).Times(1)
+	s.mockResource.ClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+
+	_, err := s.handler.ImportWorkflowExecution(ctx, &adminservice.ImportWorkflowExecutionRequest{
+		Domain: s.domainName,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: "workflowID",
+			RunId:      uuid.New(),
+		},
+		VersionHistory: &commonproto.VersionHistory{
+			Items: []*commonproto.VersionHistoryItem{{EventID: 10, Version: 100}},
+		},
+		HistoryBatches: []*commonproto.DataBlob{{EncodingType: enums.EncodingTypeProto3, Data: []byte{1}}},
+	})
+	s.IsType(&serviceerror.DomainNotActive{}, err)
+}
+
+func (s *adminHandlerSuite) Test_ExportImportWorkflowExecution_RoundTrip() {
+	ctx := context.Background()
+	serializer := persistence.NewPayloadSerializer()
+	sourceVersion := int64(100)
+	targetVersion := int64(2)
+	execution := &commonproto.WorkflowExecution{
+		WorkflowId: "workflowID",
+		RunId:      uuid.New(),
+	}
+	var exportedBlobs []*serialization.DataBlob
+	for _, batch := range [][]int64{{1, 2, 3}, {4, 5}} {
+		var events []*commonproto.HistoryEvent
+		for _, eventID := range batch {
+			events = append(events, &commonproto.HistoryEvent{EventId: eventID, Version: sourceVersion})
+		}
+		blob, err := serializer.SerializeBatchEvents(events, common.EncodingTypeProto3)
+		s.NoError(err)
+		exportedBlobs = append(exportedBlobs, blob)
+	}
+
+	// export the workflow execution from the source cluster
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(s.domainID, nil).AnyTimes()
+	branchToken := []byte{1}
+	versionHistory := persistence.NewVersionHistory(branchToken, []*persistence.VersionHistoryItem{
+		persistence.NewVersionHistoryItem(int64(5), sourceVersion),
+	})
+	s.mockHistoryClient.EXPECT().GetMutableState(gomock.Any(), gomock.Any()).Return(&historyservice.GetMutableStateResponse{
+		Execution:          execution,
+		NextEventId:        6,
+		CurrentBranchToken: branchToken,
+		VersionHistories:   persistence.NewVersionHistories(versionHistory).ToProto(),
+		ReplicationInfo:    make(map[string]*replication.ReplicationInfo),
+	}, nil).AnyTimes()
+	s.mockHistoryV2Mgr.On("ReadRawHistoryBranch", mock.Anything).Return(&persistence.ReadRawHistoryBranchResponse{
+		HistoryEventBlobs: exportedBlobs,
+		NextPageToken:     []byte{},
+		Size:              1,
+	}, nil)
+	exported, err := s.handler.ExportWorkflowExecution(ctx, &adminservice.ExportWorkflowExecutionRequest{
+		Domain:          s.domainName,
+		Execution:       &commonproto.WorkflowExecution{WorkflowId: execution.GetWorkflowId()},
+		MaximumPageSize: 10,
+	})
+	s.NoError(err)
+
+	// import it into the target cluster, the events are rewritten to the failover version of the target domain
+	s.mockDomainCache.EXPECT().GetDomain(s.domainName).Return(cache.NewGlobalDomainCacheEntryForTest(
+		&persistence.DomainInfo{ID: s.domainID, Name: s.domainName},
+		&persistence.DomainConfig{},
+		&persistence.DomainReplicationConfig{ActiveClusterName: cluster.TestCurrentClusterName},
+		targetVersion,
+		s.mockResource.ClusterMetadata,
+	), nil).Times(1)
+	s.mockResource.ClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	var importedEventIDs []int64
+	s.mockHistoryClient.EXPECT().ReplicateEventsV2(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *historyservice.ReplicateEventsV2Request, _ ...interface{}) (*historyservice.ReplicateEventsV2Response, error) {
+			s.Equal(s.domainID, request.GetDomainUUID())
+			s.Equal(execution, request.GetWorkflowExecution())
+			s.Equal([]*commonproto.VersionHistoryItem{{EventID: 5, Version: targetVersion}}, request.GetVersionHistoryItems())
+			events, err := serializer.DeserializeBatchEvents(persistence.NewDataBlobFromProto(request.GetEvents()))
+			s.NoError(err)
+			for _, event := range events {
+				s.Equal(targetVersion, event.GetVersion())
+				importedEventIDs = append(importedEventIDs, event.GetEventId())
+			}
+			return &historyservice.ReplicateEventsV2Response{}, nil
+		}).Times(2)
+
+	_, err = s.handler.ImportWorkflowExecution(ctx, &adminservice.ImportWorkflowExecutionRequest{
+		Domain:         s.domainName,
+		Execution:      exported.GetExecution(),
+		VersionHistory: exported.GetVersionHistory(),
+		HistoryBatches: exported.GetHistoryBatches(),
+	})
+	s.NoError(err)
+	s.Equal([]int64{1, 2, 3, 4, 5}, importedEventIDs)
+}
+
+func (s *adminHandlerSuite) Test_ListTaskListTasks() {
+	ctx := context.Background()
+	domainID := uuid.New()
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(domainID, nil).Times(1)
+	taskList := &commonproto.TaskList{Name: "taskList"}
+	s.mockResource.MatchingClient.EXPECT().ListTaskListTasks(gomock.Any(), &matchingservice.ListTaskListTasksRequest{
+		DomainUUID:      domainID,
+		TaskListType:    persistence.TaskListTypeActivity,
+		TaskList:        taskList,
+		MaximumPageSize: 2,
+		NextPageToken:   []byte("token"),
+	}).Return(&matchingservice.ListTaskListTasksResponse{
+		Tasks: []*matchingservice.BacklogTask{
+			{Task: newAdminTestTask(domainID, 11)},
+			{Backlog: "subqueue", Task: newAdminTestTask(domainID, 12)},
+		},
+		NextPageToken: []byte("nextToken"),
+	}, nil).Times(1)
+
+	resp, err := s.handler.ListTaskListTasks(ctx, &adminservice.ListTaskListTasksRequest{
+		Domain:          s.domainName,
+		TaskList:        taskList,
+		TaskListType:    enums.TaskListTypeActivity,
+		MaximumPageSize: 2,
+		NextPageToken:   []byte("token"),
+	})
+	s.NoError(err)
+	s.Len(resp.Tasks, 2)
+	s.Equal(int64(11), resp.Tasks[0].TaskId)
+	s.Equal("workflowID", resp.Tasks[0].Execution.GetWorkflowId())
+	s.Equal("", resp.Tasks[0].Backlog)
+	s.Equal(int64(12), resp.Tasks[1].TaskId)
+	s.Equal("subqueue", resp.Tasks[1].Backlog)
+	s.Equal([]byte("nextToken"), resp.NextPageToken)
+}
+
+func (s *adminHandlerSuite) Test_CountTaskListTasks() {
+	ctx := context.Background()
+	domainID := uuid.New()
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(domainID, nil).Times(1)
+	typedTask := newAdminTestTask(domainID, 1)
+	typedTask.Data.WorkflowType = "typedWorkflowType"
+	runningTask := newAdminTestTask(domainID, 2)
+	deletedTask := newAdminTestTask(domainID, 4)
+	deletedTask.Data.RunID = primitives.MustParseUUID(uuid.New())
+	s.mockResource.MatchingClient.EXPECT().ListTaskListTasks(gomock.Any(), gomock.Any()).Return(&matchingservice.ListTaskListTasksResponse{
+		Tasks: []*matchingservice.BacklogTask{
+			{Task: typedTask},
+			{Task: runningTask},
+			{Backlog: "subqueue", Task: newAdminTestTask(domainID, 3)},
+			{Task: deletedTask},
+		},
+	}, nil).Times(1)
+	s.mockHistoryClient.EXPECT().GetMutableState(gomock.Any(), &historyservice.GetMutableStateRequest{
+		DomainUUID: domainID,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: "workflowID",
+			RunId:      primitives.UUIDString(runningTask.Data.RunID),
+		},
+	}).Return(&historyservice.GetMutableStateResponse{
+		WorkflowType: &commonproto.WorkflowType{Name: "workflowType"},
+	}, nil).Times(1)
+	s.mockHistoryClient.EXPECT().GetMutableState(gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewNotFound("")).Times(1)
+
+	resp, err := s.handler.CountTaskListTasks(ctx, &adminservice.CountTaskListTasksRequest{
+		Domain:          s.domainName,
+		TaskList:        &commonproto.TaskList{Name: "taskList"},
+		TaskListType:    enums.TaskListTypeDecision,
+		MaximumPageSize: 10,
+	})
+	s.NoError(err)
+	s.Equal(map[string]int64{"typedWorkflowType": 1, "workflowType": 2, "": 1}, resp.CountByWorkflowType)
+	s.Nil(resp.NextPageToken)
+}
+
+func (s *adminHandlerSuite) Test_DeleteTaskListTasks_FailedOnMissingTaskIDs() {
+	ctx := context.Background()
+	_, err := s.handler.DeleteTaskListTasks(ctx, &adminservice.DeleteTaskListTasksRequest{
+		Domain:       s.domainName,
+		TaskList:     &commonproto.TaskList{Name: "taskList"},
+		TaskListType: enums.TaskListTypeActivity,
+	})
+	s.Equal(errTaskIDsNotSet, err)
+}
+
+func (s *adminHandlerSuite) Test_DeleteTaskListTasks() {
+	ctx := context.Background()
+	domainID := uuid.New()
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(domainID, nil).Times(1)
+	taskList := &commonproto.TaskList{Name: "taskList"}
+	s.mockResource.MatchingClient.EXPECT().DeleteTaskListTasks(gomock.Any(), &matchingservice.DeleteTaskListTasksRequest{
+		DomainUUID:   domainID,
+		TaskListType: persistence.TaskListTypeActivity,
+		TaskList:     taskList,
+		Backlog:      "subqueue",
+		TaskIds:      []int64{5, 6},
+	}).Return(&matchingservice.DeleteTaskListTasksResponse{}, nil).Times(1)
+
+	_, err := s.handler.DeleteTaskListTasks(ctx, &adminservice.DeleteTaskListTasksRequest{
+		Domain:       s.domainName,
+		TaskList:     taskList,
+		TaskListType: enums.TaskListTypeActivity,
+		TaskIds:      []int64{5, 6},
+		Backlog:      "subqueue",
+	})
+	s.NoError(err)
+}
+
+func (s *adminHandlerSuite) Test_MoveTaskListTasks() {
+	ctx := context.Background()
+	domainID := uuid.New()
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(domainID, nil).Times(1)
+	task := newAdminTestTask(domainID, 5)
+	task.Data.WorkflowType = "workflowType"
+	s.mockResource.TaskMgr.On("GetTasks", mock.MatchedBy(func(request *persistence.GetTasksRequest) bool {
+		return request.TaskList == "subqueue" && request.ReadLevel == 4 && *request.MaxReadLevel == 5
+	})).Return(&persistence.GetTasksResponse{
+		Tasks: []*persistenceblobs.AllocatedTaskInfo{task},
+	}, nil).Once()
+	s.mockResource.TaskMgr.On("GetTasks", mock.MatchedBy(func(request *persistence.GetTasksRequest) bool {
+		return request.TaskList == "subqueue" && request.ReadLevel == 5 && *request.MaxReadLevel == 6
+	})).Return(&persistence.GetTasksResponse{}, nil).Once()
+	taskList := &commonproto.TaskList{Name: "taskList"}
+	targetTaskList := &commonproto.TaskList{Name: "targetTaskList"}
+	s.mockResource.MatchingClient.EXPECT().AddActivityTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, request *matchingservice.AddActivityTaskRequest) (*matchingservice.AddActivityTaskResponse, error) {
+			s.Equal(targetTaskList, request.TaskList)
+			s.Equal(int64(10), request.ScheduleId)
+			s.Equal("workflowType", request.WorkflowType)
+			s.True(request.ScheduleToStartTimeoutSeconds > 0)
+			return &matchingservice.AddActivityTaskResponse{}, nil
+		}).Times(1)
+	s.mockResource.MatchingClient.EXPECT().DeleteTaskListTasks(gomock.Any(), &matchingservice.DeleteTaskListTasksRequest{
+		DomainUUID:   domainID,
+		TaskListType: persistence.TaskListTypeActivity,
+		TaskList:     taskList,
+		Backlog:      "subqueue",
+		TaskIds:      []int64{5},
+	}).Return(&matchingservice.DeleteTaskListTasksResponse{}, nil).Times(1)
+
+	resp, err := s.handler.MoveTaskListTasks(ctx, &adminservice.MoveTaskListTasksRequest{
+		Domain:         s.domainName,
+		TaskList:       taskList,
+		TaskListType:   enums.TaskListTypeActivity,
+		TaskIds:        []int64{5, 6},
+		TargetTaskList: targetTaskList,
+		Backlog:        "subqueue",
+	})
+	s.NoError(err)
+	s.Equal(int32(1), resp.MovedCount)
+}
+
+func (s *adminHandlerSuite) Test_UpdateTaskListDispatchConfig_FailedOnInvalidConfig() {
+	ctx := context.Background()
+	_, err := s.handler.UpdateTaskListDispatchConfig(ctx, &adminservice.UpdateTaskListDispatchConfigRequest{
+		Domain:         s.domainName,
+		TaskList:       &commonproto.TaskList{Name: "taskList"},
+		TaskListType:   enums.TaskListTypeActivity,
+		DispatchConfig: &persistenceblobs.TaskListDispatchConfig{MaxTasksPerSecond: -1},
+	})
+	s.Equal(errInvalidDispatchConfig, err)
+}
+
+func (s *adminHandlerSuite) Test_UpdateTaskListDispatchConfig() {
+	ctx := context.Background()
+	domainID := uuid.New()
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(domainID, nil).Times(1)
+	dispatchConfig := &persistenceblobs.TaskListDispatchConfig{
+		MaxTasksPerSecond:       5,
+		MaxTasksPerSecondPinned: true,
+		MaxConcurrentPollers:    10,
+	}
+	s.mockResource.MatchingClient.EXPECT().UpdateTaskListDispatchConfig(gomock.Any(), &matchingservice.UpdateTaskListDispatchConfigRequest{
+		DomainUUID:     domainID,
+		TaskListType:   persistence.TaskListTypeActivity,
+		TaskList:       &commonproto.TaskList{Name: "taskList", Kind: enums.TaskListKindNormal},
+		DispatchConfig: dispatchConfig,
+	}).Return(&matchingservice.UpdateTaskListDispatchConfigResponse{}, nil).Times(1)
+
+	_, err := s.handler.UpdateTaskListDispatchConfig(ctx, &adminservice.UpdateTaskListDispatchConfigRequest{
+		Domain:         s.domainName,
+		TaskList:       &commonproto.TaskList{Name: "taskList"},
+		TaskListType:   enums.TaskListTypeActivity,
+		DispatchConfig: dispatchConfig,
+	})
+	s.NoError(err)
+}
+
+func (s *adminHandlerSuite) Test_DescribeTaskListDispatchConfig() {
+	ctx := context.Background()
+	domainID := uuid.New()
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(domainID, nil).Times(1)
+	s.mockResource.MatchingClient.EXPECT().ListTaskListPartitions(gomock.Any(), gomock.Any()).Return(&matchingservice.ListTaskListPartitionsResponse{
+		DecisionTaskListPartitions: []*commonproto.TaskListPartitionMetadata{{Key: "taskList"}, {Key: "/__temporal_sys/taskList/1"}},
+	}, nil).Times(1)
+	dispatchConfig := &persistenceblobs.TaskListDispatchConfig{MaxTasksPerSecond: 20}
+	s.mockResource.MatchingClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, request *matchingservice.DescribeTaskListRequest) (*matchingservice.DescribeTaskListResponse, error) {
+			s.True(request.DescRequest.GetIncludeTaskListStatus())
+			return &matchingservice.DescribeTaskListResponse{
+				TaskListStatus:     &commonproto.TaskListStatus{RatePerSecond: 10},
+				DispatchConfig:     dispatchConfig,
+				OutstandingPollers: 2,
+			}, nil
+		}).Times(2)
+
+	resp, err := s.handler.DescribeTaskListDispatchConfig(ctx, &adminservice.DescribeTaskListDispatchConfigRequest{
+		Domain:       s.domainName,
+		TaskList:     &commonproto.TaskList{Name: "taskList"},
+		TaskListType: enums.TaskListTypeDecision,
+	})
+	s.NoError(err)
+	s.Equal(dispatchConfig, resp.DispatchConfig)
+	s.Len(resp.Partitions, 2)
+	s.Equal("/__temporal_sys/taskList/1", resp.Partitions[1].Partition)
+	s.Equal(10.0, resp.Partitions[1].RatePerSecond)
+	s.Equal(int32(2), resp.Partitions[1].OutstandingPollers)
+}
+
+func (s *adminHandlerSuite) Test_DescribeShardOwnership_FailedOnInvalidShardID() {
+	_, err := s.handler.DescribeShardOwnership(context.Background(), &adminservice.DescribeShardOwnershipRequest{ShardID: 1})
+	s.Equal(errInvalidShardID, err)
+}
+
+func (s *adminHandlerSuite) Test_DescribeShardOwnership() {
+	leaseExpiry := types.TimestampNow()
+	ownershipHistory := []*persistenceblobs.ShardOwnershipRecord{
+		{
+			Owner:           "host-1",
+			RangeID:         5,
+			AcquiredAt:      types.TimestampNow(),
+			ReleasedAt:      types.TimestampNow(),
+			AcquisitionType: persistenceblobs.AcquisitionCreated,
+		},
+		{
+			Owner:           "host-2",
+			RangeID:         6,
+			AcquiredAt:      types.TimestampNow(),
+			AcquisitionType: persistenceblobs.AcquisitionHandedOff,
+			PreviousOwner:   "host-1",
+		},
+	}
+	s.mockResource.ShardMgr.On("GetShard", &persistence.GetShardRequest{ShardID: 0}).Return(&persistence.GetShardResponse{
+		ShardInfo: &persistenceblobs.ShardInfo{
+			ShardID:          0,
+			Owner:            "host-2",
+			RangeID:          6,
+			LeaseExpiry:      leaseExpiry,
+			OwnershipHistory: ownershipHistory,
+		},
+	}, nil).Once()
+
+	resp, err := s.handler.DescribeShardOwnership(context.Background(), &adminservice.DescribeShardOwnershipRequest{ShardID: 0})
+	s.NoError(err)
+	s.Equal("host-2", resp.Owner)
+	s.Equal(int64(6), resp.RangeID)
+	s.Equal(leaseExpiry, resp.LeaseExpiry)
+	s.False(resp.Released)
+	s.Equal(ownershipHistory, resp.OwnershipHistory)
+}
+
+func (s *adminHandlerSuite) Test_DescribeShardLoad_FailedOnInvalidShardID() {
+	_, err := s.handler.DescribeShardLoad(context.Background(), &adminservice.DescribeShardLoadRequest{ShardID: 1})
+	s.Equal(errInvalidShardID, err)
+}
+
+func (s *adminHandlerSuite) Test_DescribeShardLoad() {
+	shardLoad := &adminservice.ShardLoad{
+		ShardID:           0,
+		Address:           "host-1",
+		WindowSeconds:     60,
+		RequestsPerSecond: 1200,
+		Hot:               true,
+		TopWorkflows: []*adminservice.ShardWorkflowLoad{
+			{WorkflowID: "workflowID", Requests: 60000, RequestsPerSecond: 1000, Share: 0.83, Throttled: true},
+		},
+	}
+	s.mockHistoryClient.EXPECT().DescribeShardLoad(gomock.Any(), &historyservice.DescribeShardLoadRequest{ShardID: 0}).
+		Return(&historyservice.DescribeShardLoadResponse{ShardLoad: shardLoad}, nil).Times(1)
+
+	resp, err := s.handler.DescribeShardLoad(context.Background(), &adminservice.DescribeShardLoadRequest{ShardID: 0})
+	s.NoError(err)
+	s.Equal(shardLoad, resp.ShardLoad)
+}
+
 func (s *adminHandlerSuite) Test_SetRequestDefaultValueAndGetTargetVersionHistory_DefinedStartAndEnd() {
 	inputStartEventID := int64(1)
 	inputStartVersion := int64(10)
@@ -600,3 +1051,17 @@ func (s *adminHandlerSuite) Test_AddSearchAttribute_Permission() {
 		s.Nil(resp)
 	}
 }
+
+func newAdminTestTask(domainID string, taskID int64) *persistenceblobs.AllocatedTaskInfo {
+	return &persistenceblobs.AllocatedTaskInfo{
+		Data: &persistenceblobs.TaskInfo{
+			DomainID:    primitives.MustParseUUID(domainID),
+			WorkflowID:  "workflowID",
+			RunID:       primitives.MustParseUUID("c4d9cb3c-2b54-4ec4-9e49-8a1b2d4f6d10"),
+			ScheduleID:  10,
+			CreatedTime: types.TimestampNow(),
+			Expiry:      &types.Timestamp{Seconds: time.Now().Add(time.Hour).Unix()},
+		},
+		TaskID: taskID,
+	}
+}
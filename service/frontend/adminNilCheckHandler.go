@@ -193,3 +193,111 @@ func (adh *AdminNilCheckHandler) RefreshWorkflowTasks(ctx context.Context, reque
 	}
 	return resp, err
 }
+
+// DeleteWorkflowExecution deletes a closed workflow execution
+func (adh *AdminNilCheckHandler) DeleteWorkflowExecution(ctx context.Context, request *adminservice.DeleteWorkflowExecutionRequest) (*adminservice.DeleteWorkflowExecutionResponse, error) {
+	resp, err := adh.parentHandler.DeleteWorkflowExecution(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.DeleteWorkflowExecutionResponse{}
+	}
+	return resp, err
+}
+
+// ListDynamicConfig returns the effective dynamic config values for the given set of filters
+func (adh *AdminNilCheckHandler) ListDynamicConfig(ctx context.Context, request *adminservice.ListDynamicConfigRequest) (*adminservice.ListDynamicConfigResponse, error) {
+	resp, err := adh.parentHandler.ListDynamicConfig(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.ListDynamicConfigResponse{}
+	}
+	return resp, err
+}
+
+// ExportWorkflowExecution exports the history and the mutable state of a workflow execution
+func (adh *AdminNilCheckHandler) ExportWorkflowExecution(ctx context.Context, request *adminservice.ExportWorkflowExecutionRequest) (*adminservice.ExportWorkflowExecutionResponse, error) {
+	resp, err := adh.parentHandler.ExportWorkflowExecution(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.ExportWorkflowExecutionResponse{}
+	}
+	return resp, err
+}
+
+// ImportWorkflowExecution imports a workflow execution from exported history
+func (adh *AdminNilCheckHandler) ImportWorkflowExecution(ctx context.Context, request *adminservice.ImportWorkflowExecutionRequest) (*adminservice.ImportWorkflowExecutionResponse, error) {
+	resp, err := adh.parentHandler.ImportWorkflowExecution(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.ImportWorkflowExecutionResponse{}
+	}
+	return resp, err
+}
+
+// ListTaskListTasks returns the backlog tasks of a task list
+func (adh *AdminNilCheckHandler) ListTaskListTasks(ctx context.Context, request *adminservice.ListTaskListTasksRequest) (*adminservice.ListTaskListTasksResponse, error) {
+	resp, err := adh.parentHandler.ListTaskListTasks(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.ListTaskListTasksResponse{}
+	}
+	return resp, err
+}
+
+// CountTaskListTasks counts the backlog tasks of a task list by workflow type
+func (adh *AdminNilCheckHandler) CountTaskListTasks(ctx context.Context, request *adminservice.CountTaskListTasksRequest) (*adminservice.CountTaskListTasksResponse, error) {
+	resp, err := adh.parentHandler.CountTaskListTasks(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.CountTaskListTasksResponse{}
+	}
+	return resp, err
+}
+
+// DeleteTaskListTasks drops tasks from the backlog of a task list
+func (adh *AdminNilCheckHandler) DeleteTaskListTasks(ctx context.Context, request *adminservice.DeleteTaskListTasksRequest) (*adminservice.DeleteTaskListTasksResponse, error) {
+	resp, err := adh.parentHandler.DeleteTaskListTasks(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.DeleteTaskListTasksResponse{}
+	}
+	return resp, err
+}
+
+// MoveTaskListTasks moves tasks from the backlog of a task list to another task list
+func (adh *AdminNilCheckHandler) MoveTaskListTasks(ctx context.Context, request *adminservice.MoveTaskListTasksRequest) (*adminservice.MoveTaskListTasksResponse, error) {
+	resp, err := adh.parentHandler.MoveTaskListTasks(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.MoveTaskListTasksResponse{}
+	}
+	return resp, err
+}
+
+// UpdateTaskListDispatchConfig sets the dispatch rate and max concurrent pollers of a task list
+func (adh *AdminNilCheckHandler) UpdateTaskListDispatchConfig(ctx context.Context, request *adminservice.UpdateTaskListDispatchConfigRequest) (*adminservice.UpdateTaskListDispatchConfigResponse, error) {
+	resp, err := adh.parentHandler.UpdateTaskListDispatchConfig(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.UpdateTaskListDispatchConfigResponse{}
+	}
+	return resp, err
+}
+
+// DescribeTaskListDispatchConfig returns the dispatch config and dispatch status of a task list
+func (adh *AdminNilCheckHandler) DescribeTaskListDispatchConfig(ctx context.Context, request *adminservice.DescribeTaskListDispatchConfigRequest) (*adminservice.DescribeTaskListDispatchConfigResponse, error) {
+	resp, err := adh.parentHandler.DescribeTaskListDispatchConfig(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.DescribeTaskListDispatchConfigResponse{}
+	}
+	return resp, err
+}
+
+// DescribeShardOwnership returns the current owner, lease and ownership history of a history shard
+func (adh *AdminNilCheckHandler) DescribeShardOwnership(ctx context.Context, request *adminservice.DescribeShardOwnershipRequest) (*adminservice.DescribeShardOwnershipResponse, error) {
+	resp, err := adh.parentHandler.DescribeShardOwnership(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.DescribeShardOwnershipResponse{}
+	}
+	return resp, err
+}
+
+// DescribeShardLoad returns the load of a history shard
+func (adh *AdminNilCheckHandler) DescribeShardLoad(ctx context.Context, request *adminservice.DescribeShardLoadRequest) (*adminservice.DescribeShardLoadResponse, error) {
+	resp, err := adh.parentHandler.DescribeShardLoad(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.DescribeShardLoadResponse{}
+	}
+	return resp, err
+}
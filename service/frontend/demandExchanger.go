@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"encoding/json"
+
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/membership"
+	"github.com/temporalio/temporal/common/quotas"
+)
+
+// quotaDemandLabel is the membership label holding the request rates observed by a frontend host,
+// encoded as a JSON object of domain -> rate
+const quotaDemandLabel = "quotaDemand"
+
+type (
+	// membershipDemandExchanger shares the request rates observed by the frontend hosts through
+	// membership labels, which are gossiped to all the members of the ring
+	membershipDemandExchanger struct {
+		monitor membership.Monitor
+	}
+)
+
+var _ quotas.DemandExchanger = (*membershipDemandExchanger)(nil)
+
+func newMembershipDemandExchanger(monitor membership.Monitor) *membershipDemandExchanger {
+	return &membershipDemandExchanger{
+		monitor: monitor,
+	}
+}
+
+func (e *membershipDemandExchanger) PublishDemand(demand map[string]float64) error {
+	label, err := json.Marshal(demand)
+	if err != nil {
+		return err
+	}
+	return e.monitor.SetSelfLabel(quotaDemandLabel, string(label))
+}
+
+func (e *membershipDemandExchanger) CollectDemand() (map[string]map[string]float64, error) {
+	labels, err := e.monitor.GetMemberLabels(common.FrontendServiceName, quotaDemandLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	demands := make(map[string]map[string]float64, len(labels))
+	for host, label := range labels {
+		var demand map[string]float64
+		if err := json.Unmarshal([]byte(label), &demand); err != nil {
+			continue
+		}
+		demands[host] = demand
+	}
+	return demands, nil
+}
@@ -72,6 +72,26 @@ var (
 	errInvalidEventQueryRange                             = serviceerror.NewInvalidArgument("Invalid event query range.")
 	errUnknownValueType                                   = serviceerror.NewInvalidArgument("Unknown value type, %v.")
 	errDLQTypeIsNotSupported                              = serviceerror.NewInvalidArgument("The DLQ type is not supported.")
+	errScheduleIDNotSet                                   = serviceerror.NewInvalidArgument("ScheduleId is not set on request.")
+	errScheduleIDTooLong                                  = serviceerror.NewInvalidArgument("ScheduleId length exceeds limit.")
+	errScheduleNotSet                                     = serviceerror.NewInvalidArgument("Schedule is not set on request.")
+	errScheduleAlreadyExists                              = serviceerror.NewInvalidArgument("Schedule already exists.")
+	errInvalidSchedule                                    = serviceerror.NewInvalidArgument("Invalid schedule: %v.")
+	errSchedulerNotEnabled                                = serviceerror.NewInvalidArgument("Scheduler is not enabled for this cluster.")
+	errUpdateNameNotSet                                   = serviceerror.NewInvalidArgument("UpdateName is not set on request.")
+	errUpdateNameTooLong                                  = serviceerror.NewInvalidArgument("UpdateName length exceeds limit.")
+	errUnknownDynamicConfigKey                            = serviceerror.NewInvalidArgument("Unknown dynamic config key [%s].")
+	errUnknownDynamicConfigFilter                         = serviceerror.NewInvalidArgument("Unknown dynamic config filter [%s].")
+	errInvalidDynamicConfigFilter                         = serviceerror.NewInvalidArgument("Invalid dynamic config filter: %v.")
+	errHistoryBatchesNotSet                               = serviceerror.NewInvalidArgument("HistoryBatches are not set on request.")
+	errInvalidTaskListType                                = serviceerror.NewInvalidArgument("Invalid TaskListType.")
+	errTaskIDsNotSet                                      = serviceerror.NewInvalidArgument("TaskIds are not set on request.")
+	errTargetTaskListNotSet                               = serviceerror.NewInvalidArgument("TargetTaskList is not set on request.")
+	errDispatchConfigNotSet                               = serviceerror.NewInvalidArgument("DispatchConfig is not set on request.")
+	errInvalidDispatchConfig                              = serviceerror.NewInvalidArgument("MaxTasksPerSecond and MaxConcurrentPollers must not be negative.")
+	errInvalidShardID                                     = serviceerror.NewInvalidArgument("Invalid ShardId.")
+
+	errScheduleNotFound = serviceerror.NewNotFound("Schedule not found.")
 
 	errFailedUpdateDynamicConfig = serviceerror.NewInternal("Failed to update dynamic config, err: %v.")
 	errFailedToCreateESIndex     = serviceerror.NewInternal("Failed to create ES index, err: %v.")
@@ -0,0 +1,485 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/serviceerror"
+	"go.temporal.io/temporal-proto/workflowservice"
+	sdkclient "go.temporal.io/temporal/client"
+
+	"github.com/temporalio/temporal/.gen/proto/scheduleservice"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/resource"
+	"github.com/temporalio/temporal/service/worker/scheduler"
+)
+
+const (
+	scheduleWorkflowTaskTimeout  = time.Minute
+	defaultListSchedulesPageSize = 100
+)
+
+type (
+	// ScheduleHandler - gRPC handler interface for scheduleservice
+	// Every schedule is a system workflow executed by the scheduler of the worker service.
+	ScheduleHandler struct {
+		resource.Resource
+
+		config *Config
+	}
+)
+
+var _ scheduleservice.ScheduleServiceServer = (*ScheduleHandler)(nil)
+
+// NewScheduleHandler creates a gRPC handler for the scheduleservice
+func NewScheduleHandler(
+	resource resource.Resource,
+	config *Config,
+) *ScheduleHandler {
+	return &ScheduleHandler{
+		Resource: resource,
+		config:   config,
+	}
+}
+
+// CreateSchedule creates a new schedule
+func (sh *ScheduleHandler) CreateSchedule(ctx context.Context, request *scheduleservice.CreateScheduleRequest) (_ *scheduleservice.CreateScheduleResponse, retError error) {
+	defer log.CapturePanicGRPC(sh.GetLogger(), &retError)
+
+	scope, sw := sh.startRequestProfile(metrics.FrontendCreateScheduleScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, sh.error(errRequestNotSet, scope)
+	}
+	// nothing would run the schedule workflow
+	if !sh.config.EnableScheduler() {
+		return nil, sh.error(errSchedulerNotEnabled, scope)
+	}
+	if err := sh.validateScheduleID(request.GetDomain(), request.GetScheduleId()); err != nil {
+		return nil, sh.error(err, scope)
+	}
+	schedule, err := sh.toSchedule(request.GetSchedule())
+	if err != nil {
+		return nil, sh.error(err, scope)
+	}
+
+	options := sdkclient.StartWorkflowOptions{
+		ID:                              scheduler.WorkflowID(request.GetDomain(), request.GetScheduleId()),
+		TaskList:                        scheduler.SchedulerTaskListName,
+		ExecutionStartToCloseTimeout:    scheduler.InfiniteDuration,
+		DecisionTaskStartToCloseTimeout: scheduleWorkflowTaskTimeout,
+		WorkflowIDReusePolicy:           sdkclient.WorkflowIDReusePolicyAllowDuplicate,
+	}
+	params := scheduler.Params{
+		DomainName: request.GetDomain(),
+		ScheduleID: request.GetScheduleId(),
+		Schedule:   schedule,
+	}
+	if _, err := sh.GetSDKClient().ExecuteWorkflow(ctx, options, scheduler.SchedulerWFTypeName, params); err != nil {
+		if _, ok := err.(*serviceerror.WorkflowExecutionAlreadyStarted); ok {
+			return nil, sh.error(errScheduleAlreadyExists, scope)
+		}
+		return nil, sh.error(err, scope)
+	}
+	return &scheduleservice.CreateScheduleResponse{}, nil
+}
+
+// DescribeSchedule returns the definition and the runtime information of a schedule
+func (sh *ScheduleHandler) DescribeSchedule(ctx context.Context, request *scheduleservice.DescribeScheduleRequest) (_ *scheduleservice.DescribeScheduleResponse, retError error) {
+	defer log.CapturePanicGRPC(sh.GetLogger(), &retError)
+
+	scope, sw := sh.startRequestProfile(metrics.FrontendDescribeScheduleScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, sh.error(errRequestNotSet, scope)
+	}
+	if err := sh.validateScheduleID(request.GetDomain(), request.GetScheduleId()); err != nil {
+		return nil, sh.error(err, scope)
+	}
+
+	workflowID := scheduler.WorkflowID(request.GetDomain(), request.GetScheduleId())
+	value, err := sh.GetSDKClient().QueryWorkflow(ctx, workflowID, "", scheduler.QueryNameDescribe)
+	if err != nil {
+		return nil, sh.error(convertScheduleError(err), scope)
+	}
+	var result scheduler.DescribeResult
+	if err := value.Get(&result); err != nil {
+		return nil, sh.error(serviceerror.NewInternal(err.Error()), scope)
+	}
+	return &scheduleservice.DescribeScheduleResponse{
+		Schedule: fromSchedule(result.Schedule),
+		Info:     fromScheduleInfo(result.Info),
+	}, nil
+}
+
+// UpdateSchedule replaces the definition of a schedule
+func (sh *ScheduleHandler) UpdateSchedule(ctx context.Context, request *scheduleservice.UpdateScheduleRequest) (_ *scheduleservice.UpdateScheduleResponse, retError error) {
+	defer log.CapturePanicGRPC(sh.GetLogger(), &retError)
+
+	scope, sw := sh.startRequestProfile(metrics.FrontendUpdateScheduleScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, sh.error(errRequestNotSet, scope)
+	}
+	if err := sh.validateScheduleID(request.GetDomain(), request.GetScheduleId()); err != nil {
+		return nil, sh.error(err, scope)
+	}
+	schedule, err := sh.toSchedule(request.GetSchedule())
+	if err != nil {
+		return nil, sh.error(err, scope)
+	}
+
+	if err := sh.signal(ctx, request.GetDomain(), request.GetScheduleId(), scheduler.SignalNameUpdate, schedule); err != nil {
+		return nil, sh.error(err, scope)
+	}
+	return &scheduleservice.UpdateScheduleResponse{}, nil
+}
+
+// PauseSchedule stops a schedule from taking actions until it is resumed
+func (sh *ScheduleHandler) PauseSchedule(ctx context.Context, request *scheduleservice.PauseScheduleRequest) (_ *scheduleservice.PauseScheduleResponse, retError error) {
+	defer log.CapturePanicGRPC(sh.GetLogger(), &retError)
+
+	scope, sw := sh.startRequestProfile(metrics.FrontendPauseScheduleScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, sh.error(errRequestNotSet, scope)
+	}
+	if err := sh.validateScheduleID(request.GetDomain(), request.GetScheduleId()); err != nil {
+		return nil, sh.error(err, scope)
+	}
+
+	if err := sh.signal(ctx, request.GetDomain(), request.GetScheduleId(), scheduler.SignalNamePause, request.GetNotes()); err != nil {
+		return nil, sh.error(err, scope)
+	}
+	return &scheduleservice.PauseScheduleResponse{}, nil
+}
+
+// ResumeSchedule resumes a paused schedule
+func (sh *ScheduleHandler) ResumeSchedule(ctx context.Context, request *scheduleservice.ResumeScheduleRequest) (_ *scheduleservice.ResumeScheduleResponse, retError error) {
+	defer log.CapturePanicGRPC(sh.GetLogger(), &retError)
+
+	scope, sw := sh.startRequestProfile(metrics.FrontendResumeScheduleScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, sh.error(errRequestNotSet, scope)
+	}
+	if err := sh.validateScheduleID(request.GetDomain(), request.GetScheduleId()); err != nil {
+		return nil, sh.error(err, scope)
+	}
+
+	if err := sh.signal(ctx, request.GetDomain(), request.GetScheduleId(), scheduler.SignalNameResume, request.GetNotes()); err != nil {
+		return nil, sh.error(err, scope)
+	}
+	return &scheduleservice.ResumeScheduleResponse{}, nil
+}
+
+// BackfillSchedule takes the actions of all the nominal times of a past time range
+func (sh *ScheduleHandler) BackfillSchedule(ctx context.Context, request *scheduleservice.BackfillScheduleRequest) (_ *scheduleservice.BackfillScheduleResponse, retError error) {
+	defer log.CapturePanicGRPC(sh.GetLogger(), &retError)
+
+	scope, sw := sh.startRequestProfile(metrics.FrontendBackfillScheduleScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, sh.error(errRequestNotSet, scope)
+	}
+	if err := sh.validateScheduleID(request.GetDomain(), request.GetScheduleId()); err != nil {
+		return nil, sh.error(err, scope)
+	}
+	backfill := scheduler.BackfillRequest{
+		StartTime:     unixNanoToTime(request.GetStartTime()),
+		EndTime:       unixNanoToTime(request.GetEndTime()),
+		OverlapPolicy: request.GetOverlapPolicy(),
+	}
+	if err := backfill.Validate(); err != nil {
+		return nil, sh.error(errInvalidSchedule.MessageArgs(err), scope)
+	}
+
+	if err := sh.signal(ctx, request.GetDomain(), request.GetScheduleId(), scheduler.SignalNameBackfill, backfill); err != nil {
+		return nil, sh.error(err, scope)
+	}
+	return &scheduleservice.BackfillScheduleResponse{}, nil
+}
+
+// DeleteSchedule deletes a schedule, the workflows it started are not affected
+func (sh *ScheduleHandler) DeleteSchedule(ctx context.Context, request *scheduleservice.DeleteScheduleRequest) (_ *scheduleservice.DeleteScheduleResponse, retError error) {
+	defer log.CapturePanicGRPC(sh.GetLogger(), &retError)
+
+	scope, sw := sh.startRequestProfile(metrics.FrontendDeleteScheduleScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, sh.error(errRequestNotSet, scope)
+	}
+	if err := sh.validateScheduleID(request.GetDomain(), request.GetScheduleId()); err != nil {
+		return nil, sh.error(err, scope)
+	}
+
+	workflowID := scheduler.WorkflowID(request.GetDomain(), request.GetScheduleId())
+	reason := "schedule deleted by " + request.GetIdentity()
+	if err := sh.GetSDKClient().TerminateWorkflow(ctx, workflowID, "", reason, nil); err != nil {
+		return nil, sh.error(convertScheduleError(err), scope)
+	}
+	return &scheduleservice.DeleteScheduleResponse{}, nil
+}
+
+// ListSchedules returns the schedules of a domain
+func (sh *ScheduleHandler) ListSchedules(ctx context.Context, request *scheduleservice.ListSchedulesRequest) (_ *scheduleservice.ListSchedulesResponse, retError error) {
+	defer log.CapturePanicGRPC(sh.GetLogger(), &retError)
+
+	scope, sw := sh.startRequestProfile(metrics.FrontendListSchedulesScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, sh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return nil, sh.error(errDomainNotSet, scope)
+	}
+	pageSize := request.GetMaximumPageSize()
+	if pageSize <= 0 {
+		pageSize = defaultListSchedulesPageSize
+	}
+
+	// schedule workflows of all domains run in the system domain and visibility cannot filter them by domain,
+	// pages are read until the requested number of schedules of the domain is found, every read asks only
+	// for the missing entries so that the next page token never skips a schedule
+	prefix := scheduler.WorkflowID(request.GetDomain(), "")
+	var schedules []*scheduleservice.ScheduleListEntry
+	nextPageToken := request.GetNextPageToken()
+	for {
+		resp, err := sh.GetSDKClient().ListOpenWorkflow(ctx, &workflowservice.ListOpenWorkflowExecutionsRequest{
+			Domain:          common.SystemLocalDomainName,
+			MaximumPageSize: pageSize - int32(len(schedules)),
+			NextPageToken:   nextPageToken,
+			StartTimeFilter: &commonproto.StartTimeFilter{
+				EarliestTime: 0,
+				LatestTime:   time.Now().UnixNano(),
+			},
+			Filters: &workflowservice.ListOpenWorkflowExecutionsRequest_TypeFilter{
+				TypeFilter: &commonproto.WorkflowTypeFilter{Name: scheduler.SchedulerWFTypeName},
+			},
+		})
+		if err != nil {
+			return nil, sh.error(err, scope)
+		}
+
+		for _, execution := range resp.GetExecutions() {
+			workflowID := execution.GetExecution().GetWorkflowId()
+			if !strings.HasPrefix(workflowID, prefix) {
+				continue
+			}
+			schedules = append(schedules, &scheduleservice.ScheduleListEntry{
+				ScheduleId: strings.TrimPrefix(workflowID, prefix),
+				CreateTime: execution.GetStartTime().GetValue(),
+			})
+		}
+		nextPageToken = resp.GetNextPageToken()
+		if len(nextPageToken) == 0 || int32(len(schedules)) >= pageSize {
+			break
+		}
+	}
+	return &scheduleservice.ListSchedulesResponse{
+		Schedules:     schedules,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (sh *ScheduleHandler) signal(ctx context.Context, domainName string, scheduleID string, signalName string, arg interface{}) error {
+	workflowID := scheduler.WorkflowID(domainName, scheduleID)
+	return convertScheduleError(sh.GetSDKClient().SignalWorkflow(ctx, workflowID, "", signalName, arg))
+}
+
+func (sh *ScheduleHandler) validateScheduleID(domainName string, scheduleID string) error {
+	if domainName == "" {
+		return errDomainNotSet
+	}
+	if len(domainName) > sh.config.MaxIDLengthLimit() {
+		return errDomainTooLong
+	}
+	if scheduleID == "" {
+		return errScheduleIDNotSet
+	}
+	if len(scheduleID) > sh.config.MaxIDLengthLimit() {
+		return errScheduleIDTooLong
+	}
+	_, err := sh.GetDomainCache().GetDomain(domainName)
+	return err
+}
+
+func (sh *ScheduleHandler) toSchedule(schedule *scheduleservice.Schedule) (scheduler.Schedule, error) {
+	if schedule == nil {
+		return scheduler.Schedule{}, errScheduleNotSet
+	}
+	spec := schedule.GetSpec()
+	action := schedule.GetAction()
+	if len(action.GetWorkflowType().GetName()) > sh.config.MaxIDLengthLimit() {
+		return scheduler.Schedule{}, errWorkflowTypeTooLong
+	}
+	if len(action.GetTaskList().GetName()) > sh.config.MaxIDLengthLimit() {
+		return scheduler.Schedule{}, errTaskListTooLong
+	}
+	result := scheduler.Schedule{
+		Spec: scheduler.Spec{
+			CronExpressions: spec.GetCronExpressions(),
+			Interval:        time.Duration(spec.GetIntervalInSeconds()) * time.Second,
+			StartTime:       unixNanoToTime(spec.GetStartTime()),
+			EndTime:         unixNanoToTime(spec.GetEndTime()),
+			Jitter:          time.Duration(spec.GetJitterInSeconds()) * time.Second,
+		},
+		Action: scheduler.StartWorkflowAction{
+			WorkflowID:                   action.GetWorkflowId(),
+			WorkflowType:                 action.GetWorkflowType().GetName(),
+			TaskList:                     action.GetTaskList().GetName(),
+			Input:                        action.GetInput(),
+			ExecutionStartToCloseTimeout: time.Duration(action.GetExecutionStartToCloseTimeoutSeconds()) * time.Second,
+			TaskStartToCloseTimeout:      time.Duration(action.GetTaskStartToCloseTimeoutSeconds()) * time.Second,
+		},
+		Policies: scheduler.Policies{
+			OverlapPolicy: schedule.GetPolicies().GetOverlapPolicy(),
+			CatchupWindow: time.Duration(schedule.GetPolicies().GetCatchupWindowInSeconds()) * time.Second,
+		},
+		State: scheduler.State{
+			Paused: schedule.GetState().GetPaused(),
+			Notes:  schedule.GetState().GetNotes(),
+		},
+	}
+	if err := result.Validate(); err != nil {
+		return scheduler.Schedule{}, errInvalidSchedule.MessageArgs(err)
+	}
+	return result, nil
+}
+
+func (sh *ScheduleHandler) startRequestProfile(scope int) (metrics.Scope, metrics.Stopwatch) {
+	metricsScope := sh.GetMetricsClient().Scope(scope)
+	sw := metricsScope.StartTimer(metrics.ServiceLatency)
+	metricsScope.IncCounter(metrics.ServiceRequests)
+	return metricsScope, sw
+}
+
+func (sh *ScheduleHandler) error(err error, scope metrics.Scope) error {
+	switch err.(type) {
+	case *serviceerror.InvalidArgument:
+		scope.IncCounter(metrics.ServiceErrInvalidArgumentCounter)
+		return err
+	case *serviceerror.NotFound:
+		scope.IncCounter(metrics.ServiceErrNotFoundCounter)
+		return err
+	case *serviceerror.ResourceExhausted:
+		scope.IncCounter(metrics.ServiceErrResourceExhaustedCounter)
+		return err
+	}
+
+	sh.GetLogger().Error("Schedule request failed", tag.Error(err))
+	scope.IncCounter(metrics.ServiceFailures)
+	return err
+}
+
+func convertScheduleError(err error) error {
+	if _, ok := err.(*serviceerror.NotFound); ok {
+		return errScheduleNotFound
+	}
+	return err
+}
+
+func fromSchedule(schedule scheduler.Schedule) *scheduleservice.Schedule {
+	return &scheduleservice.Schedule{
+		Spec: &scheduleservice.ScheduleSpec{
+			CronExpressions:   schedule.Spec.CronExpressions,
+			IntervalInSeconds: int64(schedule.Spec.Interval / time.Second),
+			StartTime:         timeToUnixNano(schedule.Spec.StartTime),
+			EndTime:           timeToUnixNano(schedule.Spec.EndTime),
+			JitterInSeconds:   int64(schedule.Spec.Jitter / time.Second),
+		},
+		Action: &scheduleservice.ScheduleAction{
+			WorkflowId:                          schedule.Action.WorkflowID,
+			WorkflowType:                        &commonproto.WorkflowType{Name: schedule.Action.WorkflowType},
+			TaskList:                            &commonproto.TaskList{Name: schedule.Action.TaskList},
+			Input:                               schedule.Action.Input,
+			ExecutionStartToCloseTimeoutSeconds: int32(schedule.Action.ExecutionStartToCloseTimeout / time.Second),
+			TaskStartToCloseTimeoutSeconds:      int32(schedule.Action.TaskStartToCloseTimeout / time.Second),
+		},
+		Policies: &scheduleservice.SchedulePolicies{
+			OverlapPolicy:          schedule.Policies.OverlapPolicy,
+			CatchupWindowInSeconds: int64(schedule.Policies.CatchupWindow / time.Second),
+		},
+		State: &scheduleservice.ScheduleState{
+			Paused: schedule.State.Paused,
+			Notes:  schedule.State.Notes,
+		},
+	}
+}
+
+func fromScheduleInfo(info scheduler.Info) *scheduleservice.ScheduleInfo {
+	result := &scheduleservice.ScheduleInfo{
+		ActionCount:         info.ActionCount,
+		MissedCatchupWindow: info.MissedCatchupWindow,
+		OverlapSkipped:      info.OverlapSkipped,
+		CreateTime:          timeToUnixNano(info.CreateTime),
+		UpdateTime:          timeToUnixNano(info.UpdateTime),
+	}
+	for _, running := range info.RunningWorkflows {
+		result.RunningWorkflows = append(result.RunningWorkflows, &commonproto.WorkflowExecution{
+			WorkflowId: running.WorkflowID,
+			RunId:      running.RunID,
+		})
+	}
+	for _, action := range info.RecentActions {
+		result.RecentActions = append(result.RecentActions, &scheduleservice.ScheduleActionResult{
+			NominalTime: timeToUnixNano(action.NominalTime),
+			ActualTime:  timeToUnixNano(action.ActualTime),
+			StartedWorkflow: &commonproto.WorkflowExecution{
+				WorkflowId: action.WorkflowID,
+				RunId:      action.RunID,
+			},
+		})
+	}
+	for _, t := range info.FutureActionTimes {
+		result.FutureActionTimes = append(result.FutureActionTimes, timeToUnixNano(t))
+	}
+	return result
+}
+
+func unixNanoToTime(t int64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, t).UTC()
+}
+
+func timeToUnixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
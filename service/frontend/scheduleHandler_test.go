@@ -0,0 +1,143 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/workflowservice"
+	sdkmocks "go.temporal.io/temporal/mocks"
+
+	"github.com/temporalio/temporal/.gen/proto/scheduleservice"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/resource"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+	"github.com/temporalio/temporal/service/worker/scheduler"
+)
+
+type (
+	scheduleHandlerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller    *gomock.Controller
+		mockResource  *resource.Test
+		mockSDKClient *sdkmocks.Client
+
+		config  *Config
+		handler *ScheduleHandler
+	}
+)
+
+const (
+	testScheduleDomain = "test-domain"
+)
+
+func TestScheduleHandlerSuite(t *testing.T) {
+	s := new(scheduleHandlerSuite)
+	suite.Run(t, s)
+}
+
+func (s *scheduleHandlerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.controller = gomock.NewController(s.T())
+	s.mockResource = resource.NewTest(s.controller, metrics.Frontend)
+	s.mockSDKClient = s.mockResource.SDKClient.(*sdkmocks.Client)
+
+	s.config = NewConfig(dynamicconfig.NewCollection(dynamicconfig.NewNopClient(), s.mockResource.GetLogger()), 0, false)
+	s.handler = NewScheduleHandler(s.mockResource, s.config)
+}
+
+func (s *scheduleHandlerSuite) TearDownTest() {
+	s.controller.Finish()
+	s.mockResource.Finish(s.T())
+	s.mockSDKClient.AssertExpectations(s.T())
+}
+
+func (s *scheduleHandlerSuite) TestCreateSchedule_SchedulerNotEnabled() {
+	_, err := s.handler.CreateSchedule(context.Background(), &scheduleservice.CreateScheduleRequest{
+		Domain:     testScheduleDomain,
+		ScheduleId: "schedule-id",
+		Schedule:   &scheduleservice.Schedule{},
+	})
+	s.Equal(errSchedulerNotEnabled, err)
+}
+
+func (s *scheduleHandlerSuite) TestListSchedules_FillsPageAcrossVisibilityPages() {
+	s.mockSDKClient.On("ListOpenWorkflow", mock.Anything, mock.MatchedBy(func(request *workflowservice.ListOpenWorkflowExecutionsRequest) bool {
+		return request.GetMaximumPageSize() == 2 && len(request.GetNextPageToken()) == 0
+	})).Return(&workflowservice.ListOpenWorkflowExecutionsResponse{
+		Executions: []*commonproto.WorkflowExecutionInfo{
+			s.newScheduleExecution("other-domain", "schedule-1"),
+			s.newScheduleExecution(testScheduleDomain, "schedule-2"),
+		},
+		NextPageToken: []byte("page-2"),
+	}, nil).Once()
+	// only the missing entry is read, so that the returned token doesn't skip any schedule
+	s.mockSDKClient.On("ListOpenWorkflow", mock.Anything, mock.MatchedBy(func(request *workflowservice.ListOpenWorkflowExecutionsRequest) bool {
+		return request.GetMaximumPageSize() == 1 && string(request.GetNextPageToken()) == "page-2"
+	})).Return(&workflowservice.ListOpenWorkflowExecutionsResponse{
+		Executions: []*commonproto.WorkflowExecutionInfo{
+			s.newScheduleExecution(testScheduleDomain, "schedule-3"),
+		},
+		NextPageToken: []byte("page-3"),
+	}, nil).Once()
+
+	resp, err := s.handler.ListSchedules(context.Background(), &scheduleservice.ListSchedulesRequest{
+		Domain:          testScheduleDomain,
+		MaximumPageSize: 2,
+	})
+	s.NoError(err)
+	s.Len(resp.GetSchedules(), 2)
+	s.Equal("schedule-2", resp.GetSchedules()[0].GetScheduleId())
+	s.Equal("schedule-3", resp.GetSchedules()[1].GetScheduleId())
+	s.Equal([]byte("page-3"), resp.GetNextPageToken())
+}
+
+func (s *scheduleHandlerSuite) TestListSchedules_LastPage() {
+	s.mockSDKClient.On("ListOpenWorkflow", mock.Anything, mock.Anything).Return(&workflowservice.ListOpenWorkflowExecutionsResponse{
+		Executions: []*commonproto.WorkflowExecutionInfo{
+			s.newScheduleExecution("other-domain", "schedule-1"),
+		},
+	}, nil).Once()
+
+	resp, err := s.handler.ListSchedules(context.Background(), &scheduleservice.ListSchedulesRequest{
+		Domain:          testScheduleDomain,
+		MaximumPageSize: 2,
+	})
+	s.NoError(err)
+	s.Empty(resp.GetSchedules())
+	s.Empty(resp.GetNextPageToken())
+}
+
+func (s *scheduleHandlerSuite) newScheduleExecution(domainName string, scheduleID string) *commonproto.WorkflowExecutionInfo {
+	return &commonproto.WorkflowExecutionInfo{
+		Execution: &commonproto.WorkflowExecution{WorkflowId: scheduler.WorkflowID(domainName, scheduleID)},
+		Type:      &commonproto.WorkflowType{Name: scheduler.SchedulerWFTypeName},
+	}
+}
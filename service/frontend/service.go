@@ -23,14 +23,18 @@ package frontend
 import (
 	"context"
 	"sync/atomic"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"go.temporal.io/temporal-proto/serviceerror"
 	"go.temporal.io/temporal-proto/workflowservice"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/.gen/proto/healthservice"
+	"github.com/temporalio/temporal/.gen/proto/scheduleservice"
+	"github.com/temporalio/temporal/.gen/proto/updateservice"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/definition"
 	"github.com/temporalio/temporal/common/domain"
@@ -48,22 +52,33 @@ import (
 
 // Config represents configuration for frontend service
 type Config struct {
-	NumHistoryShards                int
-	PersistenceMaxQPS               dynamicconfig.IntPropertyFn
-	VisibilityMaxPageSize           dynamicconfig.IntPropertyFnWithDomainFilter
-	EnableVisibilitySampling        dynamicconfig.BoolPropertyFn
-	EnableReadFromClosedExecutionV2 dynamicconfig.BoolPropertyFn
-	VisibilityListMaxQPS            dynamicconfig.IntPropertyFnWithDomainFilter
-	EnableReadVisibilityFromES      dynamicconfig.BoolPropertyFnWithDomainFilter
-	ESVisibilityListMaxQPS          dynamicconfig.IntPropertyFnWithDomainFilter
-	ESIndexMaxResultWindow          dynamicconfig.IntPropertyFn
-	HistoryMaxPageSize              dynamicconfig.IntPropertyFnWithDomainFilter
-	RPS                             dynamicconfig.IntPropertyFn
-	DomainRPS                       dynamicconfig.IntPropertyFnWithDomainFilter
-	MaxIDLengthLimit                dynamicconfig.IntPropertyFn
-	EnableClientVersionCheck        dynamicconfig.BoolPropertyFn
-	MinRetentionDays                dynamicconfig.IntPropertyFn
-	DisallowQuery                   dynamicconfig.BoolPropertyFnWithDomainFilter
+	NumHistoryShards                 int
+	PersistenceMaxQPS                dynamicconfig.IntPropertyFn
+	VisibilityMaxPageSize            dynamicconfig.IntPropertyFnWithDomainFilter
+	EnableVisibilitySampling         dynamicconfig.BoolPropertyFn
+	EnableReadFromClosedExecutionV2  dynamicconfig.BoolPropertyFn
+	VisibilityListMaxQPS             dynamicconfig.IntPropertyFnWithDomainFilter
+	EnableReadVisibilityFromES       dynamicconfig.BoolPropertyFnWithDomainFilter
+	ESVisibilityListMaxQPS           dynamicconfig.IntPropertyFnWithDomainFilter
+	ESIndexMaxResultWindow           dynamicconfig.IntPropertyFn
+	HistoryMaxPageSize               dynamicconfig.IntPropertyFnWithDomainFilter
+	RPS                              dynamicconfig.IntPropertyFn
+	DomainRPS                        dynamicconfig.IntPropertyFnWithDomainFilter
+	DomainBurst                      dynamicconfig.IntPropertyFnWithDomainFilter
+	APIRPS                           dynamicconfig.FloatPropertyFnWithAPIFilters
+	APIBurst                         dynamicconfig.IntPropertyFnWithAPIFilters
+	APIPriority                      dynamicconfig.IntPropertyFnWithAPIFilter
+	NormalPriorityRPSRatio           dynamicconfig.FloatPropertyFn
+	LowPriorityRPSRatio              dynamicconfig.FloatPropertyFn
+	RateLimitMaxWait                 dynamicconfig.DurationPropertyFnWithDomainFilter
+	GlobalRPS                        dynamicconfig.IntPropertyFn
+	GlobalDomainRPS                  dynamicconfig.IntPropertyFnWithDomainFilter
+	GlobalRateLimitRebalance         dynamicconfig.BoolPropertyFn
+	GlobalRateLimitRebalanceInterval dynamicconfig.DurationPropertyFn
+	MaxIDLengthLimit                 dynamicconfig.IntPropertyFn
+	EnableClientVersionCheck         dynamicconfig.BoolPropertyFn
+	MinRetentionDays                 dynamicconfig.IntPropertyFn
+	DisallowQuery                    dynamicconfig.BoolPropertyFnWithDomainFilter
 
 	// Persistence settings
 	HistoryMgrNumConns dynamicconfig.IntPropertyFn
@@ -92,6 +107,9 @@ type Config struct {
 
 	// VisibilityArchival system protection
 	VisibilityArchivalQueryMaxPageSize dynamicconfig.IntPropertyFn
+
+	// EnableScheduler is whether the worker service runs the scheduler, schedules cannot be created without it
+	EnableScheduler dynamicconfig.BoolPropertyFn
 }
 
 // NewConfig returns new service config with default values
@@ -109,6 +127,17 @@ func NewConfig(dc *dynamicconfig.Collection, numHistoryShards int, enableReadFro
 		HistoryMaxPageSize:                  dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendHistoryMaxPageSize, common.GetHistoryMaxPageSize),
 		RPS:                                 dc.GetIntProperty(dynamicconfig.FrontendRPS, 1200),
 		DomainRPS:                           dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendDomainRPS, 1200),
+		DomainBurst:                         dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendDomainBurst, 0),
+		APIRPS:                              dc.GetFloat64PropertyFilteredByAPIInfo(dynamicconfig.FrontendAPIRPS, 0),
+		APIBurst:                            dc.GetIntPropertyFilteredByAPIInfo(dynamicconfig.FrontendAPIBurst, 0),
+		APIPriority:                         dc.GetIntPropertyFilteredByAPI(dynamicconfig.FrontendAPIPriority, -1),
+		NormalPriorityRPSRatio:              dc.GetFloat64Property(dynamicconfig.FrontendNormalPriorityRPSRatio, 0.8),
+		LowPriorityRPSRatio:                 dc.GetFloat64Property(dynamicconfig.FrontendLowPriorityRPSRatio, 0.4),
+		RateLimitMaxWait:                    dc.GetDurationPropertyFilteredByDomain(dynamicconfig.FrontendRateLimitMaxWait, 0),
+		GlobalRPS:                           dc.GetIntProperty(dynamicconfig.FrontendGlobalRPS, 0),
+		GlobalDomainRPS:                     dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendGlobalDomainRPS, 0),
+		GlobalRateLimitRebalance:            dc.GetBoolProperty(dynamicconfig.FrontendGlobalRateLimitRebalance, false),
+		GlobalRateLimitRebalanceInterval:    dc.GetDurationProperty(dynamicconfig.FrontendGlobalRateLimitRebalanceInterval, 10*time.Second),
 		MaxIDLengthLimit:                    dc.GetIntProperty(dynamicconfig.MaxIDLengthLimit, 1000),
 		HistoryMgrNumConns:                  dc.GetIntProperty(dynamicconfig.FrontendHistoryMgrNumConns, 10),
 		MaxBadBinaries:                      dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendMaxBadBinaries, domain.MaxBadBinaries),
@@ -127,6 +156,7 @@ func NewConfig(dc *dynamicconfig.Collection, numHistoryShards int, enableReadFro
 		MinRetentionDays:                    dc.GetIntProperty(dynamicconfig.MinRetentionDays, domain.MinRetentionDays),
 		VisibilityArchivalQueryMaxPageSize:  dc.GetIntProperty(dynamicconfig.VisibilityArchivalQueryMaxPageSize, 10000),
 		DisallowQuery:                       dc.GetBoolPropertyFnWithDomainFilter(dynamicconfig.DisallowQuery, false),
+		EnableScheduler:                     dc.GetBoolProperty(dynamicconfig.EnableScheduler, false),
 	}
 }
 
@@ -138,8 +168,9 @@ type Service struct {
 	config *Config
 	params *resource.BootstrapParams
 
-	adminHandler *AdminHandler
-	server       *grpc.Server
+	workflowHandler *WorkflowHandler
+	adminHandler    *AdminHandler
+	server          *grpc.Server
 }
 
 // NewService builds a new frontend service
@@ -230,24 +261,38 @@ func (s *Service) Start() {
 		replicationMessageSink.(*mocks.KafkaProducer).On("Publish", mock.Anything).Return(nil)
 	}
 
-	s.server = grpc.NewServer(grpc.UnaryInterceptor(interceptor))
+	grpcServerOptions := []grpc.ServerOption{grpc.UnaryInterceptor(interceptor)}
+	if tlsConfig := s.params.RPCFactory.GetGRPCServerTLSConfig(); tlsConfig != nil {
+		grpcServerOptions = append(grpcServerOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s.server = grpc.NewServer(grpcServerOptions...)
 
 	wfHandler := NewWorkflowHandler(s, s.config, replicationMessageSink)
 	dcRedirectionHandler := NewDCRedirectionHandler(wfHandler, s.params.DCRedirectionPolicy)
-	accessControlledWorkflowHandler := NewAccessControlledHandlerImpl(dcRedirectionHandler, s.params.Authorizer)
+	accessControlledWorkflowHandler := NewAccessControlledHandlerImpl(dcRedirectionHandler, s.params.Authorizer, s.params.ClaimMapper)
 	workflowNilCheckHandler := NewWorkflowNilCheckHandler(accessControlledWorkflowHandler)
 
 	workflowservice.RegisterWorkflowServiceServer(s.server, workflowNilCheckHandler)
 	healthservice.RegisterMetaServer(s.server, accessControlledWorkflowHandler)
 
+	s.workflowHandler = wfHandler
+
 	s.adminHandler = NewAdminHandler(s, s.params, s.config)
-	adminNilCheckHandler := NewAdminNilCheckHandler(s.adminHandler)
+	accessControlledAdminHandler := NewAccessControlledAdminHandler(s.adminHandler, s.params.Authorizer, s.params.ClaimMapper)
+	adminNilCheckHandler := NewAdminNilCheckHandler(accessControlledAdminHandler)
 
 	adminservice.RegisterAdminServiceServer(s.server, adminNilCheckHandler)
 
+	scheduleHandler := NewScheduleHandler(s, s.config)
+	scheduleservice.RegisterScheduleServiceServer(s.server, NewAccessControlledScheduleHandler(scheduleHandler, s.params.Authorizer, s.params.ClaimMapper))
+
+	updateHandler := NewUpdateHandler(s, s.config)
+	updateservice.RegisterUpdateServiceServer(s.server, NewAccessControlledUpdateHandler(updateHandler, s.params.Authorizer, s.params.ClaimMapper))
+
 	// must start resource first
 	s.Resource.Start()
 	s.adminHandler.Start()
+	s.workflowHandler.Start()
 
 	listener := s.GetGRPCListener()
 	logger.Info("Starting to serve on frontend listener")
@@ -264,6 +309,7 @@ func (s *Service) Stop() {
 
 	s.server.GracefulStop()
 
+	s.workflowHandler.Stop()
 	s.adminHandler.Stop()
 	s.Resource.Stop()
 
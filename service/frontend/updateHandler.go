@@ -0,0 +1,148 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/historyservice"
+	"github.com/temporalio/temporal/.gen/proto/updateservice"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/resource"
+)
+
+type (
+	// UpdateHandler - gRPC handler interface for updateservice
+	UpdateHandler struct {
+		resource.Resource
+
+		config *Config
+	}
+)
+
+var _ updateservice.UpdateServiceServer = (*UpdateHandler)(nil)
+
+// NewUpdateHandler creates a gRPC handler for the updateservice
+func NewUpdateHandler(
+	resource resource.Resource,
+	config *Config,
+) *UpdateHandler {
+	return &UpdateHandler{
+		Resource: resource,
+		config:   config,
+	}
+}
+
+// UpdateWorkflowExecution sends an update to a running workflow execution and waits for the workflow to handle it
+func (uh *UpdateHandler) UpdateWorkflowExecution(ctx context.Context, request *updateservice.UpdateWorkflowExecutionRequest) (_ *updateservice.UpdateWorkflowExecutionResponse, retError error) {
+	defer log.CapturePanicGRPC(uh.GetLogger(), &retError)
+
+	scope, sw := uh.startRequestProfile(metrics.FrontendUpdateWorkflowExecutionScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, uh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return nil, uh.error(errDomainNotSet, scope)
+	}
+	if len(request.GetDomain()) > uh.config.MaxIDLengthLimit() {
+		return nil, uh.error(errDomainTooLong, scope)
+	}
+	if err := validateExecution(request.GetWorkflowExecution()); err != nil {
+		return nil, uh.error(err, scope)
+	}
+	if request.GetUpdateName() == "" {
+		return nil, uh.error(errUpdateNameNotSet, scope)
+	}
+	if len(request.GetUpdateName()) > uh.config.MaxIDLengthLimit() {
+		return nil, uh.error(errUpdateNameTooLong, scope)
+	}
+	if len(request.GetIdentity()) > uh.config.MaxIDLengthLimit() {
+		return nil, uh.error(errIdentityTooLong, scope)
+	}
+
+	domainID, err := uh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, uh.error(err, scope)
+	}
+
+	if err := common.CheckEventBlobSizeLimit(
+		len(request.GetInput()),
+		uh.config.BlobSizeLimitWarn(request.GetDomain()),
+		uh.config.BlobSizeLimitError(request.GetDomain()),
+		domainID,
+		request.GetWorkflowExecution().GetWorkflowId(),
+		request.GetWorkflowExecution().GetRunId(),
+		scope,
+		uh.GetThrottledLogger(),
+	); err != nil {
+		return nil, uh.error(err, scope)
+	}
+
+	resp, err := uh.GetHistoryClient().UpdateWorkflowExecution(ctx, &historyservice.UpdateWorkflowExecutionRequest{
+		DomainUUID: domainID,
+		Request:    request,
+	})
+	if err != nil {
+		return nil, uh.error(err, scope)
+	}
+	return resp.GetResponse(), nil
+}
+
+func (uh *UpdateHandler) startRequestProfile(scope int) (metrics.Scope, metrics.Stopwatch) {
+	metricsScope := uh.GetMetricsClient().Scope(scope)
+	sw := metricsScope.StartTimer(metrics.ServiceLatency)
+	metricsScope.IncCounter(metrics.ServiceRequests)
+	return metricsScope, sw
+}
+
+func (uh *UpdateHandler) error(err error, scope metrics.Scope) error {
+	switch err.(type) {
+	case *serviceerror.InvalidArgument:
+		scope.IncCounter(metrics.ServiceErrInvalidArgumentCounter)
+		return err
+	case *serviceerror.NotFound:
+		scope.IncCounter(metrics.ServiceErrNotFoundCounter)
+		return err
+	case *serviceerror.DomainNotActive:
+		scope.IncCounter(metrics.ServiceErrDomainNotActiveCounter)
+		return err
+	case *serviceerror.ResourceExhausted:
+		scope.IncCounter(metrics.ServiceErrResourceExhaustedCounter)
+		return err
+	case *serviceerror.QueryFailed:
+		scope.IncCounter(metrics.ServiceErrQueryFailedCounter)
+		return err
+	case *serviceerror.DeadlineExceeded:
+		scope.IncCounter(metrics.ServiceErrContextTimeoutCounter)
+		return err
+	}
+
+	uh.GetLogger().Error("Update request failed", tag.Error(err))
+	scope.IncCounter(metrics.ServiceFailures)
+	return err
+}
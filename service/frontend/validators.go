@@ -23,6 +23,9 @@ package frontend
 import (
 	"github.com/pborman/uuid"
 	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
 )
 
 func validateExecution(w *commonproto.WorkflowExecution) error {
@@ -37,3 +40,23 @@ func validateExecution(w *commonproto.WorkflowExecution) error {
 	}
 	return nil
 }
+
+func validateTaskListBacklogRequest(taskList *commonproto.TaskList, taskListType enums.TaskListType) error {
+	if taskList.GetName() == "" {
+		return errTaskListNotSet
+	}
+	if taskListType != enums.TaskListTypeDecision && taskListType != enums.TaskListTypeActivity {
+		return errInvalidTaskListType
+	}
+	return nil
+}
+
+func validateTaskListDispatchConfig(dispatchConfig *persistenceblobs.TaskListDispatchConfig) error {
+	if dispatchConfig == nil {
+		return errDispatchConfigNotSet
+	}
+	if dispatchConfig.GetMaxTasksPerSecond() < 0 || dispatchConfig.GetMaxConcurrentPollers() < 0 {
+		return errInvalidDispatchConfig
+	}
+	return nil
+}
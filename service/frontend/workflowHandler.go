@@ -58,6 +58,7 @@ type (
 
 		tokenSerializer           common.TaskTokenSerializer
 		rateLimiter               quotas.Policy
+		clusterQuota              *quotas.ClusterQuota
 		config                    *Config
 		versionChecker            headers.VersionChecker
 		domainHandler             domain.Handler
@@ -77,19 +78,20 @@ func NewWorkflowHandler(
 	config *Config,
 	replicationMessageSink messaging.Producer,
 ) *WorkflowHandler {
+	clusterQuota := quotas.NewClusterQuota(
+		resource.GetFrontendServiceResolver(),
+		newMembershipDemandExchanger(resource.GetMembershipMonitor()),
+		func() bool { return config.GlobalRateLimitRebalance() },
+		func() time.Duration { return config.GlobalRateLimitRebalanceInterval() },
+		resource.GetLogger(),
+	)
 	handler := &WorkflowHandler{
 		Resource:        resource,
 		config:          config,
 		tokenSerializer: common.NewProtoTaskTokenSerializer(),
-		rateLimiter: quotas.NewMultiStageRateLimiter(
-			func() float64 {
-				return float64(config.RPS())
-			},
-			func(domain string) float64 {
-				return float64(config.DomainRPS(domain))
-			},
-		),
-		versionChecker: headers.NewVersionChecker(),
+		rateLimiter:     clusterQuota.Track(newRateLimiter(config, clusterQuota)),
+		clusterQuota:    clusterQuota,
+		versionChecker:  headers.NewVersionChecker(),
 		domainHandler: domain.NewHandler(
 			config.MinRetentionDays(),
 			config.MaxBadBinaries,
@@ -113,6 +115,16 @@ func NewWorkflowHandler(
 	return handler
 }
 
+// Start starts the handler
+func (wh *WorkflowHandler) Start() {
+	wh.clusterQuota.Start()
+}
+
+// Stop stops the handler
+func (wh *WorkflowHandler) Stop() {
+	wh.clusterQuota.Stop()
+}
+
 // RegisterDomain creates a new domain which can be used as a container for all resources.  Domain is a top level
 // entity within Cadence, used as a container for all resources like workflow executions, tasklists, etc.  Domain
 // acts as a sandbox and provides isolation for all resources within the domain.  All resources belongs to exactly one
@@ -282,7 +294,7 @@ func (wh *WorkflowHandler) StartWorkflowExecution(ctx context.Context, request *
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "StartWorkflowExecution", TaskList: request.GetTaskList().GetName()}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -311,6 +323,14 @@ func (wh *WorkflowHandler) StartWorkflowExecution(ctx context.Context, request *
 		return nil, wh.error(err, scope)
 	}
 
+	if err := common.ValidateTaskPriority(request.GetHeader()); err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	if err := common.ValidateFairnessKey(request.GetHeader()); err != nil {
+		return nil, wh.error(err, scope)
+	}
+
 	wh.GetLogger().Debug(
 		"Received StartWorkflowExecution. WorkflowID",
 		tag.WorkflowID(request.GetWorkflowId()))
@@ -400,7 +420,7 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(ctx context.Context, requ
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "GetWorkflowExecutionHistory"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -618,6 +638,10 @@ func (wh *WorkflowHandler) PollForDecisionTask(ctx context.Context, request *wor
 		return nil, wh.error(errRequestNotSet, scope, tagsForErrorLog...)
 	}
 
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "PollForDecisionTask", TaskList: request.GetTaskList().GetName()}); !ok {
+		return nil, wh.error(errServiceBusy, scope, tagsForErrorLog...)
+	}
+
 	wh.GetLogger().Debug("Received PollForDecisionTask")
 	if err := common.ValidateLongPollContextTimeout(
 		ctx,
@@ -654,14 +678,16 @@ func (wh *WorkflowHandler) PollForDecisionTask(ctx context.Context, request *wor
 		return nil, wh.error(err, scope, tagsForErrorLog...)
 	}
 
+	workerBuildID := headers.GetValues(ctx, headers.WorkerBuildIDHeaderName)[0]
 	pollerID := uuid.New()
 	var matchingResp *matchingservice.PollForDecisionTaskResponse
 	op := func() error {
 		var err error
 		matchingResp, err = wh.GetMatchingClient().PollForDecisionTask(ctx, &matchingservice.PollForDecisionTaskRequest{
-			DomainUUID:  domainID,
-			PollerID:    pollerID,
-			PollRequest: request,
+			DomainUUID:    domainID,
+			PollerID:      pollerID,
+			PollRequest:   request,
+			WorkerBuildId: workerBuildID,
 		})
 		return err
 	}
@@ -716,7 +742,7 @@ func (wh *WorkflowHandler) RespondDecisionTaskCompleted(ctx context.Context, req
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.allow("")
+	wh.rateLimiter.Allow(quotas.Info{API: "RespondDecisionTaskCompleted"})
 
 	if request.TaskToken == nil {
 		return nil, wh.error(errTaskTokenNotSet, scope)
@@ -796,7 +822,7 @@ func (wh *WorkflowHandler) RespondDecisionTaskFailed(ctx context.Context, reques
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.allow("")
+	wh.rateLimiter.Allow(quotas.Info{API: "RespondDecisionTaskFailed"})
 
 	if request.TaskToken == nil {
 		return nil, wh.error(errTaskTokenNotSet, scope)
@@ -875,6 +901,10 @@ func (wh *WorkflowHandler) PollForActivityTask(ctx context.Context, request *wor
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "PollForActivityTask", TaskList: request.GetTaskList().GetName()}); !ok {
+		return nil, wh.error(errServiceBusy, scope)
+	}
+
 	wh.GetLogger().Debug("Received PollForActivityTask")
 	if err := common.ValidateLongPollContextTimeout(
 		ctx,
@@ -977,7 +1007,7 @@ func (wh *WorkflowHandler) RecordActivityTaskHeartbeat(ctx context.Context, requ
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.allow("")
+	wh.rateLimiter.Allow(quotas.Info{API: "RecordActivityTaskHeartbeat"})
 
 	wh.GetLogger().Debug("Received RecordActivityTaskHeartbeat")
 	if request.TaskToken == nil {
@@ -1063,7 +1093,7 @@ func (wh *WorkflowHandler) RecordActivityTaskHeartbeatByID(ctx context.Context,
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.allow("")
+	wh.rateLimiter.Allow(quotas.Info{API: "RecordActivityTaskHeartbeatByID"})
 
 	wh.GetLogger().Debug("Received RecordActivityTaskHeartbeatByID")
 	domainID, err := wh.GetDomainCache().GetDomainID(request.GetDomain())
@@ -1168,7 +1198,7 @@ func (wh *WorkflowHandler) RespondActivityTaskCompleted(ctx context.Context, req
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.allow("")
+	wh.rateLimiter.Allow(quotas.Info{API: "RespondActivityTaskCompleted"})
 
 	if request.TaskToken == nil {
 		return nil, wh.error(errTaskTokenNotSet, scope)
@@ -1256,7 +1286,7 @@ func (wh *WorkflowHandler) RespondActivityTaskCompletedByID(ctx context.Context,
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.allow("")
+	wh.rateLimiter.Allow(quotas.Info{API: "RespondActivityTaskCompletedByID"})
 
 	domainID, err := wh.GetDomainCache().GetDomainID(request.GetDomain())
 	if err != nil {
@@ -1364,7 +1394,7 @@ func (wh *WorkflowHandler) RespondActivityTaskFailed(ctx context.Context, reques
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.allow("")
+	wh.rateLimiter.Allow(quotas.Info{API: "RespondActivityTaskFailed"})
 
 	if request.TaskToken == nil {
 		return nil, wh.error(errTaskTokenNotSet, scope)
@@ -1441,7 +1471,7 @@ func (wh *WorkflowHandler) RespondActivityTaskFailedByID(ctx context.Context, re
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.allow("")
+	wh.rateLimiter.Allow(quotas.Info{API: "RespondActivityTaskFailedByID"})
 
 	domainID, err := wh.GetDomainCache().GetDomainID(request.GetDomain())
 	if err != nil {
@@ -1537,7 +1567,7 @@ func (wh *WorkflowHandler) RespondActivityTaskCanceled(ctx context.Context, requ
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.allow("")
+	wh.rateLimiter.Allow(quotas.Info{API: "RespondActivityTaskCanceled"})
 
 	if request.TaskToken == nil {
 		return nil, wh.error(errTaskTokenNotSet, scope)
@@ -1628,7 +1658,7 @@ func (wh *WorkflowHandler) RespondActivityTaskCanceledByID(ctx context.Context,
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.allow("")
+	wh.rateLimiter.Allow(quotas.Info{API: "RespondActivityTaskCanceledByID"})
 
 	domainID, err := wh.GetDomainCache().GetDomainID(request.GetDomain())
 	if err != nil {
@@ -1735,7 +1765,7 @@ func (wh *WorkflowHandler) RequestCancelWorkflowExecution(ctx context.Context, r
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "RequestCancelWorkflowExecution"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -1779,7 +1809,7 @@ func (wh *WorkflowHandler) SignalWorkflowExecution(ctx context.Context, request
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "SignalWorkflowExecution"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -1857,7 +1887,7 @@ func (wh *WorkflowHandler) SignalWithStartWorkflowExecution(ctx context.Context,
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "SignalWithStartWorkflowExecution", TaskList: request.GetTaskList().GetName()}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -1918,6 +1948,14 @@ func (wh *WorkflowHandler) SignalWithStartWorkflowExecution(ctx context.Context,
 		return nil, wh.error(err, scope)
 	}
 
+	if err := common.ValidateTaskPriority(request.GetHeader()); err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	if err := common.ValidateFairnessKey(request.GetHeader()); err != nil {
+		return nil, wh.error(err, scope)
+	}
+
 	if err := wh.searchAttributesValidator.ValidateSearchAttributes(request.SearchAttributes, domainName); err != nil {
 		return nil, wh.error(err, scope)
 	}
@@ -1990,7 +2028,7 @@ func (wh *WorkflowHandler) ResetWorkflowExecution(ctx context.Context, request *
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "ResetWorkflowExecution"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -2034,7 +2072,7 @@ func (wh *WorkflowHandler) TerminateWorkflowExecution(ctx context.Context, reque
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "TerminateWorkflowExecution"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -2077,7 +2115,7 @@ func (wh *WorkflowHandler) ListOpenWorkflowExecutions(ctx context.Context, reque
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "ListOpenWorkflowExecutions"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -2169,7 +2207,7 @@ func (wh *WorkflowHandler) ListClosedWorkflowExecutions(ctx context.Context, req
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "ListClosedWorkflowExecutions"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -2272,7 +2310,7 @@ func (wh *WorkflowHandler) ListWorkflowExecutions(ctx context.Context, request *
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "ListWorkflowExecutions"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -2331,7 +2369,7 @@ func (wh *WorkflowHandler) ListArchivedWorkflowExecutions(ctx context.Context, r
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "ListArchivedWorkflowExecutions"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -2415,7 +2453,7 @@ func (wh *WorkflowHandler) ScanWorkflowExecutions(ctx context.Context, request *
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "ScanWorkflowExecutions"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -2475,7 +2513,7 @@ func (wh *WorkflowHandler) CountWorkflowExecutions(ctx context.Context, request
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "CountWorkflowExecutions"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -2543,7 +2581,7 @@ func (wh *WorkflowHandler) RespondQueryTaskCompleted(ctx context.Context, reques
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.allow("")
+	wh.rateLimiter.Allow(quotas.Info{API: "RespondQueryTaskCompleted"})
 
 	if request.TaskToken == nil {
 		return nil, wh.error(errTaskTokenNotSet, scope)
@@ -2731,7 +2769,7 @@ func (wh *WorkflowHandler) DescribeWorkflowExecution(ctx context.Context, reques
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "DescribeWorkflowExecution"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -2780,7 +2818,7 @@ func (wh *WorkflowHandler) DescribeTaskList(ctx context.Context, request *workfl
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "DescribeTaskList", TaskList: request.GetTaskList().GetName()}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -2831,7 +2869,7 @@ func (wh *WorkflowHandler) PollForWorkflowExecutionRawHistory(ctx context.Contex
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "PollForWorkflowExecutionRawHistory"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -3028,7 +3066,7 @@ func (wh *WorkflowHandler) GetWorkflowExecutionRawHistory(ctx context.Context, r
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "GetWorkflowExecutionRawHistory"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -3163,7 +3201,7 @@ func (wh *WorkflowHandler) GetClusterInfo(ctx context.Context, _ *workflowservic
 	defer log.CapturePanicGRPC(wh.GetLogger(), &retError)
 
 	scope := wh.getDefaultScope(metrics.FrontendClientGetClusterInfoScope)
-	if ok := wh.allow(""); !ok {
+	if ok := wh.allow(ctx, quotas.Info{API: "GetClusterInfo"}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -3186,7 +3224,7 @@ func (wh *WorkflowHandler) ListTaskListPartitions(ctx context.Context, request *
 		return nil, wh.error(errRequestNotSet, scope)
 	}
 
-	if ok := wh.allow(request.GetDomain()); !ok {
+	if ok := wh.allow(ctx, quotas.Info{Domain: request.GetDomain(), API: "ListTaskListPartitions", TaskList: request.GetTaskList().GetName()}); !ok {
 		return nil, wh.error(errServiceBusy, scope)
 	}
 
@@ -3697,9 +3735,58 @@ func (wh *WorkflowHandler) isListRequestPageSizeTooLarge(pageSize int32, domain
 		pageSize > int32(wh.config.ESIndexMaxResultWindow())
 }
 
-func (wh *WorkflowHandler) allow(domain string) bool {
-	return wh.rateLimiter.Allow(quotas.Info{Domain: domain})
+// allow checks the request against the quota policy, the request waits for
+// the rate limit tokens for up to RateLimitMaxWait if it is configured
+func (wh *WorkflowHandler) allow(ctx context.Context, info quotas.Info) bool {
+	maxWait := wh.config.RateLimitMaxWait(info.Domain)
+	if maxWait <= 0 {
+		return wh.rateLimiter.Allow(info)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+	return wh.rateLimiter.Wait(ctx, info) == nil
 }
+
+func newRateLimiter(config *Config, clusterQuota *quotas.ClusterQuota) quotas.Policy {
+	return quotas.NewPriorityRateLimiter(quotas.PriorityRateLimiterParams{
+		RPS: func() float64 {
+			if globalRPS := config.GlobalRPS(); globalRPS > 0 {
+				return float64(globalRPS) * clusterQuota.Share()
+			}
+			return float64(config.RPS())
+		},
+		PriorityRPSRatio: func(priority quotas.Priority) float64 {
+			switch priority {
+			case quotas.PriorityNormal:
+				return config.NormalPriorityRPSRatio()
+			case quotas.PriorityLow:
+				return config.LowPriorityRPSRatio()
+			default:
+				return 1
+			}
+		},
+		DomainRPS: func(domain string) float64 {
+			if globalDomainRPS := config.GlobalDomainRPS(domain); globalDomainRPS > 0 {
+				return float64(globalDomainRPS) * clusterQuota.DomainShare(domain)
+			}
+			return float64(config.DomainRPS(domain))
+		},
+		DomainBurst: func(domain string) int {
+			return config.DomainBurst(domain)
+		},
+		RuleRPS: func(info quotas.Info) float64 {
+			return config.APIRPS(info.Domain, info.TaskList, info.API)
+		},
+		RuleBurst: func(info quotas.Info) int {
+			return config.APIBurst(info.Domain, info.TaskList, info.API)
+		},
+		APIPriority: func(api string) quotas.Priority {
+			return quotas.Priority(config.APIPriority(api))
+		},
+	})
+}
+
 func (wh *WorkflowHandler) checkPermission(
 	config *Config,
 	securityToken string,
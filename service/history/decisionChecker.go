@@ -218,6 +218,14 @@ func (v *decisionAttrValidator) validateActivityScheduleAttributes(
 		return err
 	}
 
+	if err := common.ValidateTaskPriority(attributes.GetHeader()); err != nil {
+		return err
+	}
+
+	if err := common.ValidateFairnessKey(attributes.GetHeader()); err != nil {
+		return err
+	}
+
 	if len(attributes.GetActivityId()) > v.maxIDLengthLimit {
 		return serviceerror.NewInvalidArgument("ActivityID exceeds length limit.")
 	}
@@ -351,6 +359,14 @@ func (v *decisionAttrValidator) validateRecordMarkerAttributes(
 	if len(attributes.GetMarkerName()) > v.maxIDLengthLimit {
 		return serviceerror.NewInvalidArgument("MarkerName exceeds length limit.")
 	}
+	switch attributes.GetMarkerName() {
+	case updateAcceptedMarkerName, updateRejectedMarkerName:
+		return serviceerror.NewInvalidArgument("MarkerName is reserved for updates recorded by the server.")
+	case updateCompletedMarkerName:
+		if len(attributes.GetHeader().GetFields()[updateIDHeaderField]) == 0 {
+			return serviceerror.NewInvalidArgument("Update completed marker does not have an update ID.")
+		}
+	}
 
 	return nil
 }
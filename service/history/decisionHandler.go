@@ -208,6 +208,11 @@ func (handler *decisionHandlerImpl) handleDecisionTaskStarted(
 				// Unable to add DecisionTaskStarted event to history
 				return nil, serviceerror.NewInternal("Unable to add DecisionTaskStarted event to history.")
 			}
+			// pin the workflow to the build of the worker which processes its decisions, following
+			// decision tasks are only routed to that build or to the default build if it is compatible
+			if workerBuildID := req.GetWorkerBuildId(); workerBuildID != "" {
+				mutableState.GetExecutionInfo().WorkerBuildID = workerBuildID
+			}
 
 			resp, err = handler.createRecordDecisionTaskStartedResponse(domainID, mutableState, decision, req.PollRequest.GetIdentity())
 			if err != nil {
@@ -289,6 +294,11 @@ func (handler *decisionHandlerImpl) handleDecisionTaskCompleted(
 	clientFeatureVersion := clientHeaders[1]
 	clientImpl := clientHeaders[2]
 
+	decisionHeartbeating := request.GetForceCreateNewDecisionTask() && len(request.Decisions) == 0
+	// decision heartbeats are bounded by the decision timeout and are not rate limited,
+	// the limit is checked once, conflicting updates of the workflow are retried without spending more tokens
+	rateLimitChecked := decisionHeartbeating
+
 	weContext, release, err := handler.historyCache.getOrCreateWorkflowExecution(ctx, domainID, workflowExecution)
 	if err != nil {
 		return nil, err
@@ -304,6 +314,18 @@ Update_History_Loop:
 		if !msBuilder.IsWorkflowExecutionRunning() {
 			return nil, ErrWorkflowCompleted
 		}
+		if !rateLimitChecked {
+			if err := handler.historyEngine.workflowRateLimiter.allowDecisionCompletion(
+				domainID,
+				domainEntry.GetInfo().Name,
+				token.GetWorkflowId(),
+				msBuilder.GetExecutionInfo().WorkflowTypeName,
+				request.Decisions,
+			); err != nil {
+				return nil, err
+			}
+			rateLimitChecked = true
+		}
 		executionStats, err := weContext.loadExecutionStats()
 		if err != nil {
 			return nil, err
@@ -334,7 +356,6 @@ Update_History_Loop:
 			handler.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.AutoResetPointsLimitExceededCounter)
 		}
 
-		decisionHeartbeating := request.GetForceCreateNewDecisionTask() && len(request.Decisions) == 0
 		var decisionHeartbeatTimeout bool
 		var completedEvent *commonproto.HistoryEvent
 		if decisionHeartbeating {
@@ -368,8 +389,10 @@ Update_History_Loop:
 			continueAsNewBuilder        mutableState
 
 			hasUnhandledEvents bool
+			completedUpdates   map[string]*updateOutcome
 		)
 		hasUnhandledEvents = msBuilder.HasBufferedEvents()
+		queryResults, updateResults := splitUpdateResults(msBuilder, request.GetQueryResults())
 
 		if request.StickyAttributes == nil || request.StickyAttributes.WorkerTaskList == nil {
 			handler.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.CompleteDecisionWithStickyDisabledCounter)
@@ -393,13 +416,14 @@ Update_History_Loop:
 		} else {
 
 			domainName := domainEntry.GetInfo().Name
+			workflowTypeName := msBuilder.GetExecutionInfo().WorkflowTypeName
 			workflowSizeChecker := newWorkflowSizeChecker(
 				handler.config.BlobSizeLimitWarn(domainName),
 				handler.config.BlobSizeLimitError(domainName),
-				handler.config.HistorySizeLimitWarn(domainName),
-				handler.config.HistorySizeLimitError(domainName),
-				handler.config.HistoryCountLimitWarn(domainName),
-				handler.config.HistoryCountLimitError(domainName),
+				handler.config.HistorySizeLimitWarn(domainName, workflowTypeName),
+				handler.config.HistorySizeLimitError(domainName, workflowTypeName),
+				handler.config.HistoryCountLimitWarn(domainName, workflowTypeName),
+				handler.config.HistoryCountLimitError(domainName, workflowTypeName),
 				completedEvent.GetEventId(),
 				msBuilder,
 				executionStats,
@@ -438,6 +462,8 @@ Update_History_Loop:
 			continueAsNewBuilder = decisionTaskHandler.continueAsNewBuilder
 
 			hasUnhandledEvents = decisionTaskHandler.hasUnhandledEventsBeforeDecisions
+
+			completedUpdates = decisionTaskHandler.completedUpdates
 		}
 
 		if failDecision != nil {
@@ -454,6 +480,28 @@ Update_History_Loop:
 			continueAsNewBuilder = nil
 		}
 
+		var updateOutcomes map[string]*updateOutcome
+		if failDecision == nil {
+			var updateAccepted bool
+			updateOutcomes, updateAccepted, err = handler.handleDispatchedUpdates(
+				msBuilder,
+				completedEvent.GetEventId(),
+				clientImpl,
+				clientFeatureVersion,
+				updateResults,
+				completedUpdates,
+				hasUnhandledEvents,
+				decisionHeartbeating)
+			if err != nil {
+				return nil, err
+			}
+			// accepted updates are recorded as markers which the workflow must handle on a new decision task,
+			// and updates which arrived while the decision task was running have not been delivered yet
+			if updateAccepted || msBuilder.GetUpdateRegistry().hasBufferedUpdate() {
+				hasUnhandledEvents = true
+			}
+		}
+
 		createNewDecisionTask := msBuilder.IsWorkflowExecutionRunning() && (hasUnhandledEvents || request.GetForceCreateNewDecisionTask() || activityNotStartedCancelled)
 		var newDecisionTaskScheduledID int64
 		if createNewDecisionTask {
@@ -551,10 +599,11 @@ Update_History_Loop:
 			msBuilder,
 			clientImpl,
 			clientFeatureVersion,
-			req.GetCompleteRequest().GetQueryResults(),
+			queryResults,
 			createNewDecisionTask,
 			domainEntry,
 			decisionHeartbeating)
+		handler.completeUpdates(msBuilder, updateOutcomes)
 
 		if decisionHeartbeatTimeout {
 			// at this point, update is successful, but we still return an error to client so that the worker will give up this workflow
@@ -630,6 +679,10 @@ func (handler *decisionHandlerImpl) createRecordDecisionTaskStartedResponse(
 		}
 		queries[id] = input
 	}
+	// updates are delivered to the workflow the same way as the buffered queries
+	for id, input := range msBuilder.GetUpdateRegistry().dispatchUpdates() {
+		queries[id] = input
+	}
 	response.Queries = queries
 	return response, nil
 }
@@ -765,3 +818,163 @@ func (handler *decisionHandlerImpl) handleBufferedQueries(
 		}
 	}
 }
+
+// handleDispatchedUpdates resolves the updates delivered on the completed decision task. An update answered by the
+// workflow is accepted and an update the workflow failed is rejected, both are recorded as update markers so that the
+// workflow sees them when its history is replayed. An accepted update is completed later by the update completed marker
+// of the workflow. The returned outcomes must only be completed once the mutable state is persisted.
+func (handler *decisionHandlerImpl) handleDispatchedUpdates(
+	msBuilder mutableState,
+	decisionTaskCompletedID int64,
+	clientImpl string,
+	clientFeatureVersion string,
+	updateResults map[string]*commonproto.WorkflowQueryResult,
+	completedUpdates map[string]*updateOutcome,
+	hasUnhandledEvents bool,
+	decisionHeartbeating bool,
+) (map[string]*updateOutcome, bool, error) {
+
+	updateRegistry := msBuilder.GetUpdateRegistry()
+	if updateRegistry.getPendingCount() == 0 {
+		return nil, false, nil
+	}
+	dispatched := updateRegistry.getDispatchedIDs()
+
+	outcomes := make(map[string]*updateOutcome, len(dispatched)+len(completedUpdates))
+	for id, outcome := range completedUpdates {
+		outcomes[id] = outcome
+	}
+	// updates are delivered as consistent queries so they require the same worker support
+	if versionErr := handler.versionChecker.SupportsConsistentQuery(clientImpl, clientFeatureVersion); versionErr != nil {
+		handler.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.WorkerNotSupportsConsistentQueryCount)
+		for _, id := range dispatched {
+			outcomes[id] = &updateOutcome{failure: serviceerror.NewInvalidArgument(versionErr.Error())}
+		}
+		return outcomes, false, nil
+	}
+
+	// if its a heartbeat decision local activities may still be running on the worker,
+	// so the updates are delivered again on the next decision task
+	if decisionHeartbeating {
+		for _, id := range dispatched {
+			updateRegistry.rebufferUpdate(id)
+		}
+		return outcomes, false, nil
+	}
+
+	accepted := false
+	for _, id := range dispatched {
+		result, ok := updateResults[id]
+		switch {
+		case hasUnhandledEvents:
+			// the workflow must handle the events which came before the update first
+			updateRegistry.rebufferUpdate(id)
+		case !ok:
+			outcomes[id] = &updateOutcome{failure: ErrUpdateNotHandled}
+		case !msBuilder.IsWorkflowExecutionRunning():
+			outcomes[id] = &updateOutcome{failure: ErrWorkflowCompleted}
+		default:
+			input, err := updateRegistry.getUpdateInput(id)
+			if err != nil {
+				// the caller stopped waiting for the update
+				continue
+			}
+			if result.GetResultType() == enums.QueryResultTypeFailed {
+				if err := recordUpdateMarker(
+					msBuilder,
+					decisionTaskCompletedID,
+					updateRejectedMarkerName,
+					id,
+					input.GetQueryType(),
+					[]byte(result.GetErrorMessage()),
+				); err != nil {
+					return nil, false, serviceerror.NewInternal("Unable to record rejected update.")
+				}
+				outcomes[id] = &updateOutcome{failure: serviceerror.NewQueryFailed(result.GetErrorMessage())}
+				continue
+			}
+			if err := recordUpdateMarker(
+				msBuilder,
+				decisionTaskCompletedID,
+				updateAcceptedMarkerName,
+				id,
+				input.GetQueryType(),
+				input.GetQueryArgs(),
+			); err != nil {
+				return nil, false, serviceerror.NewInternal("Unable to record accepted update.")
+			}
+			if err := updateRegistry.acceptUpdate(id); err != nil {
+				continue
+			}
+			accepted = true
+		}
+	}
+
+	// the updates which were not completed can no longer be handled once the workflow is closed
+	if !msBuilder.IsWorkflowExecutionRunning() {
+		for _, id := range append(updateRegistry.getBufferedIDs(), updateRegistry.getAcceptedIDs()...) {
+			if _, ok := outcomes[id]; !ok {
+				outcomes[id] = &updateOutcome{failure: ErrWorkflowCompleted}
+			}
+		}
+	}
+	return outcomes, accepted, nil
+}
+
+func recordUpdateMarker(
+	msBuilder mutableState,
+	decisionTaskCompletedID int64,
+	markerName string,
+	updateID string,
+	updateName string,
+	details []byte,
+) error {
+
+	_, err := msBuilder.AddRecordMarkerEvent(decisionTaskCompletedID, &commonproto.RecordMarkerDecisionAttributes{
+		MarkerName: markerName,
+		Details:    details,
+		Header: &commonproto.Header{
+			Fields: map[string][]byte{
+				updateIDHeaderField:   []byte(updateID),
+				updateNameHeaderField: []byte(updateName),
+			},
+		},
+	})
+	return err
+}
+
+func (handler *decisionHandlerImpl) completeUpdates(
+	msBuilder mutableState,
+	outcomes map[string]*updateOutcome,
+) {
+	updateRegistry := msBuilder.GetUpdateRegistry()
+	for id, outcome := range outcomes {
+		if err := updateRegistry.completeUpdate(id, outcome); err != nil {
+			// the caller stopped waiting for the update
+			handler.logger.Debug(
+				"failed to complete update",
+				tag.WorkflowID(msBuilder.GetExecutionInfo().WorkflowID),
+				tag.WorkflowRunID(msBuilder.GetExecutionInfo().RunID),
+				tag.Error(err))
+		}
+	}
+}
+
+// splitUpdateResults separates the results of the updates from the results of the queries
+func splitUpdateResults(
+	msBuilder mutableState,
+	results map[string]*commonproto.WorkflowQueryResult,
+) (map[string]*commonproto.WorkflowQueryResult, map[string]*commonproto.WorkflowQueryResult) {
+
+	updateRegistry := msBuilder.GetUpdateRegistry()
+	queryResults := make(map[string]*commonproto.WorkflowQueryResult, len(results))
+	updateResults := make(map[string]*commonproto.WorkflowQueryResult)
+	for id, result := range results {
+		if _, err := updateRegistry.getUpdateInput(id); err == nil {
+			updateResults[id] = result
+		} else {
+			queryResults[id] = result
+		}
+	}
+	return queryResults, updateResults
+}
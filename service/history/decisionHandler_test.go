@@ -29,6 +29,7 @@ import (
 	"github.com/uber-go/tally"
 	commonproto "go.temporal.io/temporal-proto/common"
 	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
 
 	"github.com/temporalio/temporal/common/headers"
 	"github.com/temporalio/temporal/common/log/loggerimpl"
@@ -36,6 +37,10 @@ import (
 	"github.com/temporalio/temporal/common/persistence"
 )
 
+const (
+	testDecisionTaskCompletedID = int64(10)
+)
+
 type (
 	DecisionHandlerSuite struct {
 		*require.Assertions
@@ -47,6 +52,17 @@ type (
 		queryRegistry    queryRegistry
 		mockMutableState *MockmutableState
 	}
+
+	DecisionHandlerUpdateSuite struct {
+		*require.Assertions
+		suite.Suite
+
+		controller *gomock.Controller
+
+		decisionHandler  *decisionHandlerImpl
+		updateRegistry   updateRegistry
+		mockMutableState *MockmutableState
+	}
 )
 
 func TestDecisionHandlerSuite(t *testing.T) {
@@ -141,3 +157,132 @@ func (s *DecisionHandlerSuite) assertQueryCounts(queryRegistry queryRegistry, bu
 	s.Len(queryRegistry.getUnblockedIDs(), unblocked)
 	s.Len(queryRegistry.getFailedIDs(), failed)
 }
+
+func TestDecisionHandlerUpdateSuite(t *testing.T) {
+	suite.Run(t, new(DecisionHandlerUpdateSuite))
+}
+
+func (s *DecisionHandlerUpdateSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+
+	s.decisionHandler = &decisionHandlerImpl{
+		versionChecker: headers.NewVersionChecker(),
+		metricsClient:  metrics.NewClient(tally.NoopScope, metrics.History),
+		config:         NewDynamicConfigForTest(),
+		logger:         loggerimpl.NewNopLogger(),
+	}
+	s.updateRegistry = newUpdateRegistry()
+	s.mockMutableState = NewMockmutableState(s.controller)
+	s.mockMutableState.EXPECT().GetUpdateRegistry().Return(s.updateRegistry).AnyTimes()
+	workflowInfo := &persistence.WorkflowExecutionInfo{
+		WorkflowID: testWorkflowID,
+		RunID:      testRunID,
+	}
+	s.mockMutableState.EXPECT().GetExecutionInfo().Return(workflowInfo).AnyTimes()
+}
+
+func (s *DecisionHandlerUpdateSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *DecisionHandlerUpdateSuite) TestHandleDispatchedUpdates_Accepted() {
+	id, _ := s.updateRegistry.bufferUpdate(&commonproto.WorkflowQuery{QueryType: "set-value", QueryArgs: []byte{1}})
+	s.updateRegistry.dispatchUpdates()
+	results := map[string]*commonproto.WorkflowQueryResult{
+		id: {ResultType: enums.QueryResultTypeAnswered, Answer: []byte{2}},
+	}
+	s.mockMutableState.EXPECT().IsWorkflowExecutionRunning().Return(true).AnyTimes()
+	s.expectUpdateMarker(updateAcceptedMarkerName, id, []byte{1})
+
+	outcomes, accepted, err := s.decisionHandler.handleDispatchedUpdates(
+		s.mockMutableState, testDecisionTaskCompletedID, headers.GoSDK, headers.GoWorkerConsistentQueryVersion, results, nil, false, false)
+	s.NoError(err)
+	s.True(accepted)
+	// the answer of the validation is not the result, the update waits for the workflow to complete it
+	s.Empty(outcomes)
+	s.True(s.updateRegistry.isAccepted(id))
+}
+
+func (s *DecisionHandlerUpdateSuite) TestHandleDispatchedUpdates_Completed() {
+	id, _ := s.updateRegistry.bufferUpdate(&commonproto.WorkflowQuery{QueryType: "set-value"})
+	s.updateRegistry.dispatchUpdates()
+	s.NoError(s.updateRegistry.acceptUpdate(id))
+	completed := map[string]*updateOutcome{id: {result: []byte{2}}}
+	s.mockMutableState.EXPECT().IsWorkflowExecutionRunning().Return(true).AnyTimes()
+
+	outcomes, accepted, err := s.decisionHandler.handleDispatchedUpdates(
+		s.mockMutableState, testDecisionTaskCompletedID, headers.GoSDK, headers.GoWorkerConsistentQueryVersion, nil, completed, false, false)
+	s.NoError(err)
+	s.False(accepted)
+	s.Equal(&updateOutcome{result: []byte{2}}, outcomes[id])
+}
+
+func (s *DecisionHandlerUpdateSuite) TestHandleDispatchedUpdates_Rejected() {
+	rejectedID, _ := s.updateRegistry.bufferUpdate(&commonproto.WorkflowQuery{QueryType: "set-value"})
+	unhandledID, _ := s.updateRegistry.bufferUpdate(&commonproto.WorkflowQuery{QueryType: "set-value"})
+	s.updateRegistry.dispatchUpdates()
+	results := map[string]*commonproto.WorkflowQueryResult{
+		rejectedID: {ResultType: enums.QueryResultTypeFailed, ErrorMessage: "invalid value"},
+	}
+	s.mockMutableState.EXPECT().IsWorkflowExecutionRunning().Return(true).AnyTimes()
+	s.expectUpdateMarker(updateRejectedMarkerName, rejectedID, []byte("invalid value"))
+
+	outcomes, accepted, err := s.decisionHandler.handleDispatchedUpdates(
+		s.mockMutableState, testDecisionTaskCompletedID, headers.GoSDK, headers.GoWorkerConsistentQueryVersion, results, nil, false, false)
+	s.NoError(err)
+	s.False(accepted)
+	s.Equal(serviceerror.NewQueryFailed("invalid value"), outcomes[rejectedID].failure)
+	s.Equal(ErrUpdateNotHandled, outcomes[unhandledID].failure)
+}
+
+func (s *DecisionHandlerUpdateSuite) TestHandleDispatchedUpdates_UnhandledEvents() {
+	id, _ := s.updateRegistry.bufferUpdate(&commonproto.WorkflowQuery{QueryType: "set-value"})
+	s.updateRegistry.dispatchUpdates()
+	results := map[string]*commonproto.WorkflowQueryResult{
+		id: {ResultType: enums.QueryResultTypeAnswered},
+	}
+	s.mockMutableState.EXPECT().IsWorkflowExecutionRunning().Return(true).AnyTimes()
+
+	outcomes, accepted, err := s.decisionHandler.handleDispatchedUpdates(
+		s.mockMutableState, testDecisionTaskCompletedID, headers.GoSDK, headers.GoWorkerConsistentQueryVersion, results, nil, true, false)
+	s.NoError(err)
+	s.False(accepted)
+	s.Empty(outcomes)
+	s.Equal([]string{id}, s.updateRegistry.getBufferedIDs())
+}
+
+func (s *DecisionHandlerUpdateSuite) TestHandleDispatchedUpdates_WorkflowClosed() {
+	dispatchedID, _ := s.updateRegistry.bufferUpdate(&commonproto.WorkflowQuery{QueryType: "set-value"})
+	s.updateRegistry.dispatchUpdates()
+	bufferedID, _ := s.updateRegistry.bufferUpdate(&commonproto.WorkflowQuery{QueryType: "set-value"})
+	acceptedID, _ := s.updateRegistry.bufferUpdate(&commonproto.WorkflowQuery{QueryType: "set-value"})
+	s.updateRegistry.dispatchUpdates()
+	s.NoError(s.updateRegistry.acceptUpdate(acceptedID))
+	s.updateRegistry.rebufferUpdate(bufferedID)
+	results := map[string]*commonproto.WorkflowQueryResult{
+		dispatchedID: {ResultType: enums.QueryResultTypeAnswered},
+	}
+	s.mockMutableState.EXPECT().IsWorkflowExecutionRunning().Return(false).AnyTimes()
+
+	outcomes, accepted, err := s.decisionHandler.handleDispatchedUpdates(
+		s.mockMutableState, testDecisionTaskCompletedID, headers.GoSDK, headers.GoWorkerConsistentQueryVersion, results, nil, false, false)
+	s.NoError(err)
+	s.False(accepted)
+	s.Equal(ErrWorkflowCompleted, outcomes[dispatchedID].failure)
+	s.Equal(ErrWorkflowCompleted, outcomes[bufferedID].failure)
+	s.Equal(ErrWorkflowCompleted, outcomes[acceptedID].failure)
+}
+
+func (s *DecisionHandlerUpdateSuite) expectUpdateMarker(markerName string, updateID string, details []byte) {
+	s.mockMutableState.EXPECT().AddRecordMarkerEvent(testDecisionTaskCompletedID, &commonproto.RecordMarkerDecisionAttributes{
+		MarkerName: markerName,
+		Details:    details,
+		Header: &commonproto.Header{
+			Fields: map[string][]byte{
+				updateIDHeaderField:   []byte(updateID),
+				updateNameHeaderField: []byte("set-value"),
+			},
+		},
+	}).Return(&commonproto.HistoryEvent{}, nil)
+}
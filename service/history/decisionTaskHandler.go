@@ -51,6 +51,7 @@ type (
 		continueAsNewBuilder              mutableState
 		stopProcessing                    bool // should stop processing any more decisions
 		mutableState                      mutableState
+		completedUpdates                  map[string]*updateOutcome
 
 		// validation
 		attrValidator    *decisionAttrValidator
@@ -93,6 +94,7 @@ func newDecisionTaskHandler(
 		continueAsNewBuilder:              nil,
 		stopProcessing:                    false,
 		mutableState:                      mutableState,
+		completedUpdates:                  make(map[string]*updateOutcome),
 
 		// validation
 		attrValidator:    attrValidator,
@@ -620,8 +622,32 @@ func (handler *decisionTaskHandlerImpl) handleDecisionRecordMarker(
 		return err
 	}
 
-	_, err = handler.mutableState.AddRecordMarkerEvent(handler.decisionTaskCompletedID, attr)
-	return err
+	if _, err = handler.mutableState.AddRecordMarkerEvent(handler.decisionTaskCompletedID, attr); err != nil {
+		return err
+	}
+	if attr.GetMarkerName() == updateCompletedMarkerName {
+		handler.handleUpdateCompleted(attr)
+	}
+	return nil
+}
+
+// handleUpdateCompleted records the outcome of an accepted update completed by the workflow, the caller is only
+// answered once the update completed marker is persisted. Updates which were not accepted on an earlier decision
+// task, or whose caller stopped waiting, have no outcome to deliver.
+func (handler *decisionTaskHandlerImpl) handleUpdateCompleted(
+	attr *commonproto.RecordMarkerDecisionAttributes,
+) {
+
+	fields := attr.GetHeader().GetFields()
+	updateID := string(fields[updateIDHeaderField])
+	if !handler.mutableState.GetUpdateRegistry().isAccepted(updateID) {
+		return
+	}
+	if failure, ok := fields[updateFailureHeaderField]; ok {
+		handler.completedUpdates[updateID] = &updateOutcome{failure: serviceerror.NewQueryFailed(string(failure))}
+		return
+	}
+	handler.completedUpdates[updateID] = &updateOutcome{result: attr.GetDetails()}
 }
 
 func (handler *decisionTaskHandlerImpl) handleDecisionContinueAsNewWorkflow(
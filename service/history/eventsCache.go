@@ -86,9 +86,9 @@ var _ eventsCache = (*eventsCacheImpl)(nil)
 
 func newEventsCache(shardCtx ShardContext) eventsCache {
 	config := shardCtx.GetConfig()
-	shardID := common.IntPtr(shardCtx.GetShardID())
-	return newEventsCacheWithOptions(config.EventsCacheInitialSize(), config.EventsCacheMaxSize(), config.EventsCacheTTL(),
-		shardCtx.GetHistoryManager(), false, shardCtx.GetLogger(), shardCtx.GetMetricsClient(), shardID)
+	shardID := shardCtx.GetShardID()
+	return newEventsCacheWithOptions(config.EventsCacheInitialSize(int32(shardID)), config.EventsCacheMaxSize(int32(shardID)), config.EventsCacheTTL(),
+		shardCtx.GetHistoryManager(), false, shardCtx.GetLogger(), shardCtx.GetMetricsClient(), common.IntPtr(shardID))
 }
 
 func newEventsCacheWithOptions(initialSize, maxSize int, ttl time.Duration,
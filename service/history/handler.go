@@ -200,7 +200,7 @@ func (h *Handler) RecordActivityTaskHeartbeat(ctx context.Context, request *hist
 	}
 	workflowID := taskToken.GetWorkflowId()
 
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -235,7 +235,7 @@ func (h *Handler) RecordActivityTaskStarted(ctx context.Context, request *histor
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, workflowID)
 	}
 
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -279,7 +279,7 @@ func (h *Handler) RecordDecisionTaskStarted(ctx context.Context, request *histor
 		return nil, h.error(errTaskListNotSet, scope, domainID, workflowID)
 	}
 
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		h.GetLogger().Error("RecordDecisionTaskStarted failed.",
 			tag.Error(err1),
@@ -330,7 +330,7 @@ func (h *Handler) RespondActivityTaskCompleted(ctx context.Context, request *his
 	}
 	workflowID := taskToken.GetWorkflowId()
 
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -374,7 +374,7 @@ func (h *Handler) RespondActivityTaskFailed(ctx context.Context, request *histor
 	}
 	workflowID := taskToken.GetWorkflowId()
 
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -418,7 +418,7 @@ func (h *Handler) RespondActivityTaskCanceled(ctx context.Context, request *hist
 	}
 	workflowID := taskToken.GetWorkflowId()
 
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -471,7 +471,7 @@ func (h *Handler) RespondDecisionTaskCompleted(ctx context.Context, request *his
 	}
 	workflowID := token.GetWorkflowId()
 
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -535,7 +535,7 @@ func (h *Handler) RespondDecisionTaskFailed(ctx context.Context, request *histor
 	}
 	workflowID := token.GetWorkflowId()
 
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -570,7 +570,7 @@ func (h *Handler) StartWorkflowExecution(ctx context.Context, request *historyse
 
 	startRequest := request.StartRequest
 	workflowID := startRequest.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -634,6 +634,19 @@ func (h *Handler) CloseShard(_ context.Context, request *historyservice.CloseSha
 	return &historyservice.CloseShardResponse{}, nil
 }
 
+// DescribeShardLoad returns the load of a shard owned by the history host
+func (h *Handler) DescribeShardLoad(_ context.Context, request *historyservice.DescribeShardLoadRequest) (_ *historyservice.DescribeShardLoadResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+	h.startWG.Wait()
+
+	load, err := h.controller.describeShardLoad(int(request.GetShardID()))
+	if err != nil {
+		return nil, err
+	}
+	load.Address = h.GetHostInfo().GetAddress()
+	return &historyservice.DescribeShardLoadResponse{ShardLoad: load}, nil
+}
+
 // DescribeMutableState - returns the internal analysis of workflow execution state
 func (h *Handler) DescribeMutableState(ctx context.Context, request *historyservice.DescribeMutableStateRequest) (_ *historyservice.DescribeMutableStateResponse, retError error) {
 	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
@@ -684,7 +697,7 @@ func (h *Handler) GetMutableState(ctx context.Context, request *historyservice.G
 
 	workflowExecution := request.Execution
 	workflowID := workflowExecution.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -717,7 +730,7 @@ func (h *Handler) PollMutableState(ctx context.Context, request *historyservice.
 
 	workflowExecution := request.Execution
 	workflowID := workflowExecution.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -750,7 +763,7 @@ func (h *Handler) DescribeWorkflowExecution(ctx context.Context, request *histor
 
 	workflowExecution := request.Request.Execution
 	workflowID := workflowExecution.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -789,7 +802,7 @@ func (h *Handler) RequestCancelWorkflowExecution(ctx context.Context, request *h
 		tag.WorkflowRunID(cancelRequest.WorkflowExecution.GetRunId()))
 
 	workflowID := cancelRequest.WorkflowExecution.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -824,7 +837,7 @@ func (h *Handler) SignalWorkflowExecution(ctx context.Context, request *historys
 
 	workflowExecution := request.SignalRequest.WorkflowExecution
 	workflowID := workflowExecution.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -862,7 +875,7 @@ func (h *Handler) SignalWithStartWorkflowExecution(ctx context.Context, request
 
 	signalWithStartRequest := request.SignalWithStartRequest
 	workflowID := signalWithStartRequest.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -897,7 +910,7 @@ func (h *Handler) RemoveSignalMutableState(ctx context.Context, request *history
 
 	workflowExecution := request.WorkflowExecution
 	workflowID := workflowExecution.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -932,7 +945,7 @@ func (h *Handler) TerminateWorkflowExecution(ctx context.Context, request *histo
 
 	workflowExecution := request.TerminateRequest.WorkflowExecution
 	workflowID := workflowExecution.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -967,7 +980,7 @@ func (h *Handler) ResetWorkflowExecution(ctx context.Context, request *historyse
 
 	workflowExecution := request.ResetRequest.WorkflowExecution
 	workflowID := workflowExecution.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -1000,7 +1013,7 @@ func (h *Handler) QueryWorkflow(ctx context.Context, request *historyservice.Que
 	}
 
 	workflowID := request.GetRequest().GetExecution().GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -1013,6 +1026,39 @@ func (h *Handler) QueryWorkflow(ctx context.Context, request *historyservice.Que
 	return resp, nil
 }
 
+// UpdateWorkflowExecution sends an update to a running workflow and waits for the workflow to handle it.
+func (h *Handler) UpdateWorkflowExecution(ctx context.Context, request *historyservice.UpdateWorkflowExecutionRequest) (_ *historyservice.UpdateWorkflowExecutionResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+	h.startWG.Wait()
+
+	scope := metrics.HistoryUpdateWorkflowExecutionScope
+	h.GetMetricsClient().IncCounter(scope, metrics.ServiceRequests)
+	sw := h.GetMetricsClient().StartTimer(scope, metrics.ServiceLatency)
+	defer sw.Stop()
+
+	domainID := request.GetDomainUUID()
+	if domainID == "" {
+		return nil, h.error(errDomainNotSet, scope, domainID, "")
+	}
+
+	if ok := h.rateLimiter.Allow(); !ok {
+		return nil, h.error(errHistoryHostThrottle, scope, domainID, "")
+	}
+
+	workflowID := request.GetRequest().GetWorkflowExecution().GetWorkflowId()
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
+	if err1 != nil {
+		return nil, h.error(err1, scope, domainID, workflowID)
+	}
+
+	resp, err2 := engine.UpdateWorkflowExecution(ctx, request)
+	if err2 != nil {
+		return nil, h.error(err2, scope, domainID, workflowID)
+	}
+
+	return resp, nil
+}
+
 // ScheduleDecisionTask is used for creating a decision task for already started workflow execution.  This is mainly
 // used by transfer queue processor during the processing of StartChildWorkflowExecution task, where it first starts
 // child execution without creating the decision task and then calls this API after updating the mutable state of
@@ -1041,7 +1087,7 @@ func (h *Handler) ScheduleDecisionTask(ctx context.Context, request *historyserv
 
 	workflowExecution := request.WorkflowExecution
 	workflowID := workflowExecution.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -1080,7 +1126,7 @@ func (h *Handler) RecordChildExecutionCompleted(ctx context.Context, request *hi
 
 	workflowExecution := request.WorkflowExecution
 	workflowID := workflowExecution.GetWorkflowId()
-	engine, err1 := h.controller.GetEngine(workflowID)
+	engine, err1 := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
 	}
@@ -1120,7 +1166,7 @@ func (h *Handler) ResetStickyTaskList(ctx context.Context, request *historyservi
 	}
 
 	workflowID := request.Execution.GetWorkflowId()
-	engine, err := h.controller.GetEngine(workflowID)
+	engine, err := h.controller.getEngineForRequest(scope, domainID, workflowID)
 	if err != nil {
 		return nil, h.error(err, scope, domainID, workflowID)
 	}
@@ -1587,6 +1633,42 @@ func (h *Handler) RefreshWorkflowTasks(ctx context.Context, request *historyserv
 	return &historyservice.RefreshWorkflowTasksResponse{}, nil
 }
 
+// DeleteWorkflowExecution deletes a closed workflow execution
+func (h *Handler) DeleteWorkflowExecution(ctx context.Context, request *historyservice.DeleteWorkflowExecutionRequest) (_ *historyservice.DeleteWorkflowExecutionResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+
+	h.startWG.Wait()
+
+	scope := metrics.HistoryDeleteWorkflowExecutionScope
+	h.GetMetricsClient().IncCounter(scope, metrics.ServiceRequests)
+	sw := h.GetMetricsClient().StartTimer(scope, metrics.ServiceLatency)
+	defer sw.Stop()
+	domainID := request.GetDomainUUID()
+	execution := request.GetRequest().GetExecution()
+	workflowID := execution.GetWorkflowId()
+	engine, err := h.controller.GetEngine(workflowID)
+	if err != nil {
+		err = h.error(err, scope, domainID, workflowID)
+		return nil, err
+	}
+
+	err = engine.DeleteWorkflowExecution(
+		ctx,
+		domainID,
+		commonproto.WorkflowExecution{
+			WorkflowId: execution.WorkflowId,
+			RunId:      execution.RunId,
+		},
+	)
+
+	if err != nil {
+		err = h.error(err, scope, domainID, workflowID)
+		return nil, err
+	}
+
+	return &historyservice.DeleteWorkflowExecutionResponse{}, nil
+}
+
 // convertError is a helper method to convert ShardOwnershipLostError from persistence layer returned by various
 // HistoryEngine API calls to ShardOwnershipLost error return by HistoryService for client to be redirected to the
 // correct shard.
@@ -62,12 +62,13 @@ const (
 func newHistoryCache(shard ShardContext) *historyCache {
 	opts := &cache.Options{}
 	config := shard.GetConfig()
-	opts.InitialCapacity = config.HistoryCacheInitialSize()
+	shardID := int32(shard.GetShardID())
+	opts.InitialCapacity = config.HistoryCacheInitialSize(shardID)
 	opts.TTL = config.HistoryCacheTTL()
 	opts.Pin = true
 
 	return &historyCache{
-		Cache:            cache.New(config.HistoryCacheMaxSize(), opts),
+		Cache:            cache.New(config.HistoryCacheMaxSize(shardID), opts),
 		shard:            shard,
 		executionManager: shard.GetExecutionManager(),
 		logger:           shard.GetLogger().WithTags(tag.ComponentHistoryCache),
@@ -192,6 +193,7 @@ func (c *historyCache) getOrCreateWorkflowExecutionInternal(
 
 	key := definition.NewWorkflowIdentifier(domainID, execution.GetWorkflowId(), execution.GetRunId())
 	workflowCtx, cacheHit := c.Get(key).(workflowExecutionContext)
+	c.shard.GetLoadTracker().recordCacheAccess(cacheHit)
 	if !cacheHit {
 		c.metricsClient.IncCounter(scope, metrics.CacheMissCounter)
 		// Let's create the workflow execution workflowCtx
@@ -112,7 +112,7 @@ func (s *historyCacheSuite) TestHistoryCacheBasic() {
 }
 
 func (s *historyCacheSuite) TestHistoryCachePinning() {
-	s.mockShard.GetConfig().HistoryCacheMaxSize = dynamicconfig.GetIntPropertyFn(2)
+	s.mockShard.GetConfig().HistoryCacheMaxSize = dynamicconfig.GetIntPropertyFilteredByShardID(2)
 	domainID := "test_domain_id"
 	s.cache = newHistoryCache(s.mockShard)
 	we := commonproto.WorkflowExecution{
@@ -147,7 +147,7 @@ func (s *historyCacheSuite) TestHistoryCachePinning() {
 }
 
 func (s *historyCacheSuite) TestHistoryCacheClear() {
-	s.mockShard.GetConfig().HistoryCacheMaxSize = dynamicconfig.GetIntPropertyFn(20)
+	s.mockShard.GetConfig().HistoryCacheMaxSize = dynamicconfig.GetIntPropertyFilteredByShardID(20)
 	domainID := "test_domain_id"
 	s.cache = newHistoryCache(s.mockShard)
 	we := commonproto.WorkflowExecution{
@@ -178,7 +178,7 @@ func (s *historyCacheSuite) TestHistoryCacheClear() {
 }
 
 func (s *historyCacheSuite) TestHistoryCacheConcurrentAccess() {
-	s.mockShard.GetConfig().HistoryCacheMaxSize = dynamicconfig.GetIntPropertyFn(20)
+	s.mockShard.GetConfig().HistoryCacheMaxSize = dynamicconfig.GetIntPropertyFilteredByShardID(20)
 	domainID := "test_domain_id"
 	s.cache = newHistoryCache(s.mockShard)
 	we := commonproto.WorkflowExecution{
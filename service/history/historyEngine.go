@@ -41,6 +41,7 @@ import (
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
 	"github.com/temporalio/temporal/.gen/proto/matchingservice"
 	"github.com/temporalio/temporal/.gen/proto/replication"
+	"github.com/temporalio/temporal/.gen/proto/updateservice"
 	"github.com/temporalio/temporal/client/history"
 	"github.com/temporalio/temporal/client/matching"
 	"github.com/temporalio/temporal/common"
@@ -104,11 +105,13 @@ type (
 		GetReplicationMessages(ctx context.Context, pollingCluster string, lastReadMessageID int64) (*replication.ReplicationMessages, error)
 		GetDLQReplicationMessages(ctx context.Context, taskInfos []*replication.ReplicationTaskInfo) ([]*replication.ReplicationTask, error)
 		QueryWorkflow(ctx context.Context, request *historyservice.QueryWorkflowRequest) (*historyservice.QueryWorkflowResponse, error)
+		UpdateWorkflowExecution(ctx context.Context, request *historyservice.UpdateWorkflowExecutionRequest) (*historyservice.UpdateWorkflowExecutionResponse, error)
 		ReapplyEvents(ctx context.Context, domainUUID string, workflowID string, runID string, events []*commonproto.HistoryEvent) error
 		ReadDLQMessages(ctx context.Context, messagesRequest *historyservice.ReadDLQMessagesRequest) (*historyservice.ReadDLQMessagesResponse, error)
 		PurgeDLQMessages(ctx context.Context, messagesRequest *historyservice.PurgeDLQMessagesRequest) error
 		MergeDLQMessages(ctx context.Context, messagesRequest *historyservice.MergeDLQMessagesRequest) (*historyservice.MergeDLQMessagesResponse, error)
 		RefreshWorkflowTasks(ctx context.Context, domainUUID string, execution commonproto.WorkflowExecution) error
+		DeleteWorkflowExecution(ctx context.Context, domainUUID string, execution commonproto.WorkflowExecution) error
 
 		NotifyNewHistoryEvent(event *historyEventNotification)
 		NotifyNewTransferTasks(tasks []persistence.Task)
@@ -134,6 +137,7 @@ type (
 		historyEventNotifier      historyEventNotifier
 		tokenSerializer           common.TaskTokenSerializer
 		historyCache              *historyCache
+		workflowRateLimiter       *workflowRateLimiter
 		metricsClient             metrics.Client
 		logger                    log.Logger
 		throttledLogger           log.Logger
@@ -170,6 +174,8 @@ var (
 	ErrActivityTaskNotFound = serviceerror.NewNotFound("invalid activityID or activity already timed out or invoking workflow is completed")
 	// ErrWorkflowCompleted is the error to indicate workflow execution already completed
 	ErrWorkflowCompleted = serviceerror.NewNotFound("workflow execution already completed")
+	// ErrWorkflowNotClosed is the error to indicate workflow execution is still running
+	ErrWorkflowNotClosed = serviceerror.NewInvalidArgument("workflow execution is not closed")
 	// ErrWorkflowParent is the error to parent execution is given and mismatch
 	ErrWorkflowParent = serviceerror.NewNotFound("workflow parent does not match")
 	// ErrDeserializingToken is the error to indicate task token is invalid
@@ -190,6 +196,14 @@ var (
 	ErrConsistentQueryNotEnabled = serviceerror.NewInvalidArgument("cluster or domain does not enable strongly consistent query but strongly consistent query was requested")
 	// ErrConsistentQueryBufferExceeded is error indicating that too many consistent queries have been buffered and until buffered queries are finished new consistent queries cannot be buffered
 	ErrConsistentQueryBufferExceeded = serviceerror.NewInternal("consistent query buffer is full, cannot accept new consistent queries")
+	// ErrUpdateWorkflowBeforeFirstDecision is error indicating that update was attempted before the first decision task was scheduled
+	ErrUpdateWorkflowBeforeFirstDecision = serviceerror.NewInvalidArgument("workflow must have a decision task scheduled before it can be updated")
+	// ErrUpdateBufferExceeded is error indicating that too many updates are waiting to be handled by the workflow
+	ErrUpdateBufferExceeded = serviceerror.NewResourceExhausted("update buffer is full, cannot accept new updates")
+	// ErrUpdateNotHandled is error indicating that the worker completed the decision task without handling the update
+	ErrUpdateNotHandled = serviceerror.NewInternal("update was delivered to the worker but not handled")
+	// ErrWorkflowRateLimitExceeded is the error indicating a workflow issues requests faster than allowed
+	ErrWorkflowRateLimitExceeded = serviceerror.NewResourceExhausted("exceeded workflow rate limit")
 
 	// FailedWorkflowCloseState is a set of failed workflow close states, used for start workflow policy
 	// for start workflow execution API
@@ -235,6 +249,7 @@ func NewEngineWithShardContext(
 		metricsClient:        shard.GetMetricsClient(),
 		historyEventNotifier: historyEventNotifier,
 		config:               config,
+		workflowRateLimiter:  shard.GetWorkflowRateLimiter(),
 		archivalClient: archiver.NewClient(
 			shard.GetMetricsClient(),
 			logger,
@@ -922,6 +937,88 @@ func (e *historyEngineImpl) QueryWorkflow(
 	}
 }
 
+// UpdateWorkflowExecution buffers the update until it is delivered to the workflow on a decision task, the same way a
+// strongly consistent query is, and waits for the workflow to reject the update or to complete it once accepted.
+func (e *historyEngineImpl) UpdateWorkflowExecution(
+	ctx context.Context,
+	request *historyservice.UpdateWorkflowExecutionRequest,
+) (*historyservice.UpdateWorkflowExecutionResponse, error) {
+
+	domainEntry, err := e.getActiveDomainEntry(request.GetDomainUUID())
+	if err != nil {
+		return nil, err
+	}
+	domainID := domainEntry.GetInfo().ID
+
+	req := request.GetRequest()
+	execution := commonproto.WorkflowExecution{
+		WorkflowId: req.GetWorkflowExecution().GetWorkflowId(),
+		RunId:      req.GetWorkflowExecution().GetRunId(),
+	}
+	updateInput := &commonproto.WorkflowQuery{
+		QueryType: req.GetUpdateName(),
+		QueryArgs: req.GetInput(),
+	}
+
+	var updateReg updateRegistry
+	var updateID string
+	var termCh <-chan struct{}
+	err = e.updateWorkflow(
+		ctx,
+		domainID,
+		execution,
+		func(context workflowExecutionContext, mutableState mutableState) (*updateWorkflowAction, error) {
+			// the action is retried on a reloaded mutable state, which has its own registry
+			if updateReg != nil {
+				updateReg.removeUpdate(updateID)
+				updateReg = nil
+			}
+			if !mutableState.IsWorkflowExecutionRunning() {
+				return nil, ErrWorkflowCompleted
+			}
+			if !mutableState.HasProcessedOrPendingDecision() {
+				return nil, ErrUpdateWorkflowBeforeFirstDecision
+			}
+
+			registry := mutableState.GetUpdateRegistry()
+			if registry.getPendingCount() >= e.config.MaxBufferedQueryCount() {
+				return nil, ErrUpdateBufferExceeded
+			}
+			updateReg = registry
+			updateID, termCh = registry.bufferUpdate(updateInput)
+
+			// the update is delivered on the next decision task, only schedule one if there is none
+			if mutableState.HasPendingDecision() {
+				return &updateWorkflowAction{noop: true}, nil
+			}
+			return updateWorkflowWithNewDecision, nil
+		})
+	if updateReg != nil {
+		defer updateReg.removeUpdate(updateID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-termCh:
+		outcome, err := updateReg.getOutcome(updateID)
+		if err != nil {
+			return nil, err
+		}
+		if outcome.failure != nil {
+			return nil, outcome.failure
+		}
+		return &historyservice.UpdateWorkflowExecutionResponse{
+			Response: &updateservice.UpdateWorkflowExecutionResponse{
+				Result: outcome.result,
+			},
+		}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 func (e *historyEngineImpl) queryDirectlyThroughMatching(
 	ctx context.Context,
 	msResp *historyservice.GetMutableStateResponse,
@@ -1009,9 +1106,10 @@ func (e *historyEngineImpl) queryDirectlyThroughMatching(
 		tag.WorkflowNextEventID(msResp.GetNextEventId()))
 
 	nonStickyMatchingRequest := &matchingservice.QueryWorkflowRequest{
-		DomainUUID:   domainID,
-		QueryRequest: queryRequest,
-		TaskList:     msResp.TaskList,
+		DomainUUID:    domainID,
+		QueryRequest:  queryRequest,
+		TaskList:      msResp.TaskList,
+		PinnedBuildId: msResp.GetWorkerBuildId(),
 	}
 
 	nonStickyStopWatch := scope.StartTimer(metrics.DirectQueryDispatchNonStickyLatency)
@@ -1076,6 +1174,7 @@ func (e *historyEngineImpl) getMutableState(
 		WorkflowState:                        int32(workflowState),
 		WorkflowCloseState:                   int32(workflowCloseState),
 		IsStickyTaskListEnabled:              mutableState.IsStickyTaskListEnabled(),
+		WorkerBuildId:                        executionInfo.WorkerBuildID,
 	}
 	replicationState := mutableState.GetReplicationState()
 	if replicationState != nil {
@@ -1827,6 +1926,9 @@ func (e *historyEngineImpl) SignalWorkflowExecution(
 		RunId:      request.WorkflowExecution.RunId,
 	}
 
+	// the limit is checked once, conflicting updates of the workflow are retried without spending more tokens
+	rateLimitChecked := false
+
 	return e.updateWorkflow(
 		ctx,
 		domainID,
@@ -1846,6 +1948,18 @@ func (e *historyEngineImpl) SignalWorkflowExecution(
 				return nil, ErrWorkflowCompleted
 			}
 
+			if !rateLimitChecked {
+				if err := e.workflowRateLimiter.allowSignal(
+					domainID,
+					domainEntry.GetInfo().Name,
+					execution.GetWorkflowId(),
+					executionInfo.WorkflowTypeName,
+				); err != nil {
+					return nil, err
+				}
+				rateLimitChecked = true
+			}
+
 			maxAllowedSignals := e.config.MaximumSignalsPerExecution(domainEntry.GetInfo().Name)
 			if maxAllowedSignals > 0 && int(executionInfo.SignalCount) >= maxAllowedSignals {
 				e.logger.Info("Execution limit reached for maximum signals", tag.WorkflowSignalCount(executionInfo.SignalCount),
@@ -1899,6 +2013,15 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(
 		WorkflowId: sRequest.WorkflowId,
 	}
 
+	if err := e.workflowRateLimiter.allowSignal(
+		domainID,
+		domainEntry.GetInfo().Name,
+		execution.GetWorkflowId(),
+		sRequest.GetWorkflowType().GetName(),
+	); err != nil {
+		return nil, err
+	}
+
 	var prevMutableState mutableState
 	attempt := 0
 
@@ -3099,6 +3222,81 @@ func (e *historyEngineImpl) RefreshWorkflowTasks(
 	return nil
 }
 
+// DeleteWorkflowExecution deletes a closed workflow execution. The visibility record and the history are
+// deleted before the execution, so that a failed deletion leaves the execution behind and can be retried,
+// once the execution is gone a retry cannot find the history branches to delete anymore.
+func (e *historyEngineImpl) DeleteWorkflowExecution(
+	ctx context.Context,
+	domainUUID string,
+	execution commonproto.WorkflowExecution,
+) (retError error) {
+
+	domainEntry, err := e.shard.GetDomainCache().GetDomainByID(domainUUID)
+	if err != nil {
+		return err
+	}
+	domainID := domainEntry.GetInfo().ID
+
+	context, release, err := e.historyCache.getOrCreateWorkflowExecution(ctx, domainID, execution)
+	if err != nil {
+		return err
+	}
+	defer func() { release(retError) }()
+
+	mutableState, err := context.loadWorkflowExecution()
+	if err != nil {
+		return err
+	}
+	if mutableState.IsWorkflowExecutionRunning() {
+		return ErrWorkflowNotClosed
+	}
+
+	executionInfo := mutableState.GetExecutionInfo()
+	branchTokens := [][]byte{executionInfo.BranchToken}
+	if versionHistories := mutableState.GetVersionHistories(); versionHistories != nil {
+		// if VersionHistories is set, then all branch infos are stored in VersionHistories
+		branchTokens = [][]byte{}
+		for _, versionHistory := range versionHistories.Histories {
+			branchTokens = append(branchTokens, versionHistory.GetBranchToken())
+		}
+	}
+
+	if err := e.visibilityMgr.DeleteWorkflowExecution(&persistence.VisibilityDeleteWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: executionInfo.WorkflowID,
+		RunID:      executionInfo.RunID,
+	}); err != nil {
+		return err
+	}
+	for _, branchToken := range branchTokens {
+		if err := e.historyV2Mgr.DeleteHistoryBranch(&persistence.DeleteHistoryBranchRequest{
+			BranchToken: branchToken,
+			ShardID:     common.IntPtr(e.shard.GetShardID()),
+		}); err != nil {
+			return err
+		}
+	}
+
+	// the current record is only deleted when it still points to this run
+	if err := e.executionManager.DeleteCurrentWorkflowExecution(&persistence.DeleteCurrentWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: executionInfo.WorkflowID,
+		RunID:      executionInfo.RunID,
+	}); err != nil {
+		return err
+	}
+	if err := e.executionManager.DeleteWorkflowExecution(&persistence.DeleteWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: executionInfo.WorkflowID,
+		RunID:      executionInfo.RunID,
+	}); err != nil {
+		return err
+	}
+	// force the next access to read the deleted execution from the database
+	context.clear()
+	return nil
+}
+
 func (e *historyEngineImpl) loadWorkflowOnce(
 	ctx context.Context,
 	domainID string,
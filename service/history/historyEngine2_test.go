@@ -225,6 +225,51 @@ func (s *engine2Suite) TestRecordDecisionTaskStartedSuccessStickyExpired() {
 	s.Equal(&expectedResponse, response)
 }
 
+func (s *engine2Suite) TestRecordDecisionTaskStartedPinsWorkerBuildID() {
+	domainID := testDomainID
+	we := commonproto.WorkflowExecution{
+		WorkflowId: "wId",
+		RunId:      testRunID,
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilderWithEventV2(s.historyEngine.shard, s.mockEventsCache,
+		loggerimpl.NewDevelopmentForTest(s.Suite), we.GetRunId())
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	addDecisionTaskScheduledEvent(msBuilder)
+	msBuilder.GetExecutionInfo().WorkerBuildID = "v1"
+
+	ms := createMutableState(msBuilder)
+
+	gwmsResponse := &p.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryV2Mgr.On("AppendHistoryNodes", mock.Anything).Return(&p.AppendHistoryNodesResponse{Size: 0}, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *p.UpdateWorkflowExecutionRequest) bool {
+		return request.UpdateWorkflowMutation.ExecutionInfo.WorkerBuildID == "v2"
+	})).Return(&p.UpdateWorkflowExecutionResponse{
+		MutableStateUpdateSessionStats: &p.MutableStateUpdateSessionStats{},
+	}, nil).Once()
+
+	response, err := s.historyEngine.RecordDecisionTaskStarted(context.Background(), &historyservice.RecordDecisionTaskStartedRequest{
+		DomainUUID:        domainID,
+		WorkflowExecution: &we,
+		ScheduleId:        2,
+		TaskId:            100,
+		RequestId:         "reqId",
+		PollRequest: &workflowservice.PollForDecisionTaskRequest{
+			TaskList: &commonproto.TaskList{
+				Name: tl,
+			},
+			Identity: identity,
+		},
+		WorkerBuildId: "v2",
+	})
+	s.Nil(err)
+	s.NotNil(response)
+}
+
 func (s *engine2Suite) TestRecordDecisionTaskStartedSuccessStickyEnabled() {
 	domainID := testDomainID
 	we := commonproto.WorkflowExecution{
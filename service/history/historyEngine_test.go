@@ -4796,6 +4796,95 @@ func (s *engineSuite) TestSignalWorkflowExecution() {
 	s.Nil(err)
 }
 
+func (s *engineSuite) TestSignalWorkflowExecution_RateLimited() {
+	s.mockHistoryEngine.config.WorkflowSignalRPS = dynamicconfig.GetIntPropertyFilteredByWorkflowType(1)
+	s.mockHistoryEngine.workflowRateLimiter = newWorkflowRateLimiter(s.mockHistoryEngine.config, s.mockHistoryEngine.metricsClient)
+
+	we := commonproto.WorkflowExecution{
+		WorkflowId: "wId",
+		RunId:      testRunID,
+	}
+	tasklist := "testTaskList"
+	identity := "testIdentity"
+	signalRequest := &historyservice.SignalWorkflowExecutionRequest{
+		DomainUUID: testDomainID,
+		SignalRequest: &workflowservice.SignalWorkflowExecutionRequest{
+			Domain:            testDomainID,
+			WorkflowExecution: &we,
+			Identity:          identity,
+			SignalName:        "my signal name",
+			Input:             []byte("test input"),
+		},
+	}
+
+	msBuilder := newMutableStateBuilderWithEventV2(s.mockHistoryEngine.shard, s.eventsCache,
+		loggerimpl.NewDevelopmentForTest(s.Suite), we.GetRunId())
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tasklist, []byte("input"), 100, 200, identity)
+	addDecisionTaskScheduledEvent(msBuilder)
+	ms := createMutableState(msBuilder)
+	ms.ExecutionInfo.DomainID = testDomainID
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryV2Mgr.On("AppendHistoryNodes", mock.Anything).Return(&persistence.AppendHistoryNodesResponse{Size: 0}, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(&persistence.UpdateWorkflowExecutionResponse{MutableStateUpdateSessionStats: &persistence.MutableStateUpdateSessionStats{}}, nil).Once()
+
+	err := s.mockHistoryEngine.SignalWorkflowExecution(context.Background(), signalRequest)
+	s.Nil(err)
+
+	err = s.mockHistoryEngine.SignalWorkflowExecution(context.Background(), signalRequest)
+	s.Equal(ErrWorkflowRateLimitExceeded, err)
+}
+
+func (s *engineSuite) TestTerminateWorkflowExecution_NotRateLimited() {
+	s.mockHistoryEngine.config.WorkflowSignalRPS = dynamicconfig.GetIntPropertyFilteredByWorkflowType(1)
+	s.mockHistoryEngine.workflowRateLimiter = newWorkflowRateLimiter(s.mockHistoryEngine.config, s.mockHistoryEngine.metricsClient)
+
+	we := commonproto.WorkflowExecution{
+		WorkflowId: "wId",
+		RunId:      testRunID,
+	}
+	tasklist := "testTaskList"
+	identity := "testIdentity"
+	signalRequest := &historyservice.SignalWorkflowExecutionRequest{
+		DomainUUID: testDomainID,
+		SignalRequest: &workflowservice.SignalWorkflowExecutionRequest{
+			Domain:            testDomainID,
+			WorkflowExecution: &we,
+			Identity:          identity,
+			SignalName:        "my signal name",
+			Input:             []byte("test input"),
+		},
+	}
+
+	msBuilder := newMutableStateBuilderWithEventV2(s.mockHistoryEngine.shard, s.eventsCache,
+		loggerimpl.NewDevelopmentForTest(s.Suite), we.GetRunId())
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tasklist, []byte("input"), 100, 200, identity)
+	addDecisionTaskScheduledEvent(msBuilder)
+	ms := createMutableState(msBuilder)
+	ms.ExecutionInfo.DomainID = testDomainID
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryV2Mgr.On("AppendHistoryNodes", mock.Anything).Return(&persistence.AppendHistoryNodesResponse{Size: 0}, nil).Twice()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(&persistence.UpdateWorkflowExecutionResponse{MutableStateUpdateSessionStats: &persistence.MutableStateUpdateSessionStats{}}, nil).Twice()
+
+	err := s.mockHistoryEngine.SignalWorkflowExecution(context.Background(), signalRequest)
+	s.Nil(err)
+
+	// the signal limit is used up, operators must still be able to stop the workflow
+	err = s.mockHistoryEngine.TerminateWorkflowExecution(context.Background(), &historyservice.TerminateWorkflowExecutionRequest{
+		DomainUUID: testDomainID,
+		TerminateRequest: &workflowservice.TerminateWorkflowExecutionRequest{
+			Domain:            testDomainID,
+			WorkflowExecution: &we,
+			Reason:            "runaway workflow",
+			Identity:          identity,
+		},
+	})
+	s.Nil(err)
+}
+
 // Test signal decision by adding request ID
 func (s *engineSuite) TestSignalWorkflowExecution_DuplicateRequest() {
 	signalRequest := &historyservice.SignalWorkflowExecutionRequest{}
@@ -4912,6 +5001,47 @@ func (s *engineSuite) TestRemoveSignalMutableState() {
 	s.Nil(err)
 }
 
+func (s *engineSuite) TestDeleteWorkflowExecution_RetryAfterHistoryBranchDeletionFailure() {
+	s.mockHistoryEngine.visibilityMgr = s.mockShard.resource.VisibilityMgr
+
+	execution := commonproto.WorkflowExecution{
+		WorkflowId: "wId",
+		RunId:      testRunID,
+	}
+	tasklist := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilderWithEventV2(s.mockHistoryEngine.shard, s.eventsCache,
+		loggerimpl.NewDevelopmentForTest(s.Suite), testRunID)
+	addWorkflowExecutionStartedEvent(msBuilder, execution, "wType", tasklist, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	event := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tasklist, identity)
+	di.StartedID = event.GetEventId()
+	event = addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.StartedID, nil, identity)
+	addCompleteWorkflowEvent(msBuilder, event.GetEventId(), nil)
+	ms := createMutableState(msBuilder)
+	ms.ExecutionInfo.DomainID = testDomainID
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Twice()
+	s.mockShard.resource.VisibilityMgr.On("DeleteWorkflowExecution", mock.Anything).Return(nil).Twice()
+	s.mockHistoryV2Mgr.On("DeleteHistoryBranch", mock.Anything).Return(errors.New("some random error")).Once()
+
+	// the execution is kept when its history cannot be deleted
+	err := s.mockHistoryEngine.DeleteWorkflowExecution(context.Background(), testDomainID, execution)
+	s.EqualError(err, "some random error")
+	s.mockExecutionMgr.AssertNotCalled(s.T(), "DeleteCurrentWorkflowExecution", mock.Anything)
+	s.mockExecutionMgr.AssertNotCalled(s.T(), "DeleteWorkflowExecution", mock.Anything)
+
+	// so that a retry finds the execution and deletes the rest of it
+	s.mockHistoryV2Mgr.On("DeleteHistoryBranch", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("DeleteCurrentWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("DeleteWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	err = s.mockHistoryEngine.DeleteWorkflowExecution(context.Background(), testDomainID, execution)
+	s.NoError(err)
+}
+
 func (s *engineSuite) getBuilder(testDomainID string, we commonproto.WorkflowExecution) mutableState {
 	context, release, err := s.mockHistoryEngine.historyCache.getOrCreateWorkflowExecutionForBackground(testDomainID, we)
 	if err != nil {
@@ -5302,6 +5432,7 @@ func copyWorkflowExecutionInfo(sourceInfo *persistence.WorkflowExecutionInfo) *p
 		ClientLibraryVersion:               sourceInfo.ClientLibraryVersion,
 		ClientFeatureVersion:               sourceInfo.ClientFeatureVersion,
 		ClientImpl:                         sourceInfo.ClientImpl,
+		WorkerBuildID:                      sourceInfo.WorkerBuildID,
 		AutoResetPoints:                    sourceInfo.AutoResetPoints,
 		Memo:                               sourceInfo.Memo,
 		SearchAttributes:                   sourceInfo.SearchAttributes,
@@ -152,6 +152,7 @@ type (
 		GetWorkflowType() *commonproto.WorkflowType
 		GetWorkflowStateCloseStatus() (int, int)
 		GetQueryRegistry() queryRegistry
+		GetUpdateRegistry() updateRegistry
 		HasBufferedEvents() bool
 		HasInFlightDecision() bool
 		HasParentExecution() bool
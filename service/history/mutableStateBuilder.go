@@ -148,6 +148,7 @@ type (
 		taskGenerator       mutableStateTaskGenerator
 		decisionTaskManager mutableStateDecisionTaskManager
 		queryRegistry       queryRegistry
+		updateRegistry      updateRegistry
 
 		shard           ShardContext
 		clusterMetadata cluster.Metadata
@@ -202,7 +203,8 @@ func newMutableStateBuilder(
 		domainEntry:           domainEntry,
 		appliedEvents:         make(map[string]struct{}),
 
-		queryRegistry: newQueryRegistry(),
+		queryRegistry:  newQueryRegistry(),
+		updateRegistry: newUpdateRegistry(),
 
 		shard:           shard,
 		clusterMetadata: shard.GetClusterMetadata(),
@@ -940,6 +942,10 @@ func (e *mutableStateBuilder) GetQueryRegistry() queryRegistry {
 	return e.queryRegistry
 }
 
+func (e *mutableStateBuilder) GetUpdateRegistry() updateRegistry {
+	return e.updateRegistry
+}
+
 func (e *mutableStateBuilder) GetActivityScheduledEvent(
 	scheduleEventID int64,
 ) (*commonproto.HistoryEvent, error) {
@@ -1802,6 +1808,8 @@ func (e *mutableStateBuilder) ReplicateWorkflowExecutionStartedEvent(
 
 	e.executionInfo.CronSchedule = event.GetCronSchedule()
 	e.executionInfo.ParentDomainID = parentDomainID
+	e.executionInfo.TaskPriority = common.GetTaskPriority(event.Header)
+	e.executionInfo.TaskFairnessKey = common.GetFairnessKey(event.Header)
 
 	if event.ParentWorkflowExecution != nil {
 		e.executionInfo.ParentWorkflowID = event.ParentWorkflowExecution.GetWorkflowId()
@@ -2162,6 +2170,8 @@ func (e *mutableStateBuilder) ReplicateActivityTaskScheduledEvent(
 		TimerTaskStatus:          timerTaskStatusNone,
 		TaskList:                 attributes.TaskList.GetName(),
 		HasRetryPolicy:           attributes.RetryPolicy != nil,
+		Priority:                 common.GetTaskPriority(attributes.Header),
+		FairnessKey:              common.GetFairnessKey(attributes.Header),
 	}
 	ai.ExpirationTime = ai.ScheduledTime.Add(time.Duration(scheduleToCloseTimeout) * time.Second)
 	if ai.HasRetryPolicy {
@@ -1431,6 +1431,20 @@ func (mr *MockmutableStateMockRecorder) GetQueryRegistry() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueryRegistry", reflect.TypeOf((*MockmutableState)(nil).GetQueryRegistry))
 }
 
+// GetUpdateRegistry mocks base method
+func (m *MockmutableState) GetUpdateRegistry() updateRegistry {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpdateRegistry")
+	ret0, _ := ret[0].(updateRegistry)
+	return ret0
+}
+
+// GetUpdateRegistry indicates an expected call of GetUpdateRegistry
+func (mr *MockmutableStateMockRecorder) GetUpdateRegistry() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpdateRegistry", reflect.TypeOf((*MockmutableState)(nil).GetUpdateRegistry))
+}
+
 // HasBufferedEvents mocks base method
 func (m *MockmutableState) HasBufferedEvents() bool {
 	m.ctrl.T.Helper()
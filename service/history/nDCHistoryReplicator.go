@@ -307,6 +307,7 @@ func (r *nDCHistoryReplicatorImpl) applyStartEvents(
 		)
 		return err
 	}
+	applyWorkerBuildID(mutableState, task)
 
 	err = r.transactionMgr.createWorkflow(
 		ctx,
@@ -412,6 +413,7 @@ func (r *nDCHistoryReplicatorImpl) applyNonStartEventsToCurrentBranch(
 		)
 		return err
 	}
+	applyWorkerBuildID(mutableState, task)
 
 	targetWorkflow := newNDCWorkflow(
 		ctx,
@@ -666,6 +668,7 @@ func (r *nDCHistoryReplicatorImpl) applyNonStartEventsResetWorkflow(
 		)
 		return err
 	}
+	applyWorkerBuildID(mutableState, task)
 
 	targetWorkflow := newNDCWorkflow(
 		ctx,
@@ -692,6 +695,18 @@ func (r *nDCHistoryReplicatorImpl) applyNonStartEventsResetWorkflow(
 	return err
 }
 
+// applyWorkerBuildID pins the workflow to the worker build ID it is pinned to in the source cluster,
+// the build ID is not recorded in the history events so it is replicated along with them
+func applyWorkerBuildID(
+	mutableState mutableState,
+	task nDCReplicationTask,
+) {
+
+	if workerBuildID := task.getWorkerBuildID(); workerBuildID != "" {
+		mutableState.GetExecutionInfo().WorkerBuildID = workerBuildID
+	}
+}
+
 func (r *nDCHistoryReplicatorImpl) notify(
 	clusterName string,
 	now time.Time,
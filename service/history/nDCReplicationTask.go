@@ -52,6 +52,7 @@ type (
 		getNewEvents() []*commonproto.HistoryEvent
 		getLogger() log.Logger
 		getVersionHistory() *persistence.VersionHistory
+		getWorkerBuildID() string
 		isWorkflowReset() bool
 
 		splitTask(taskStartTime time.Time) (nDCReplicationTask, nDCReplicationTask, error)
@@ -68,6 +69,9 @@ type (
 		events         []*commonproto.HistoryEvent
 		newEvents      []*commonproto.HistoryEvent
 		versionHistory *persistence.VersionHistory
+		// workerBuildID is the worker build ID the workflow is pinned to
+		// in the source cluster, empty for new runs which are not pinned yet
+		workerBuildID string
 
 		startTime time.Time
 		logger    log.Logger
@@ -152,6 +156,7 @@ func newNDCReplicationTask(
 		events:         events,
 		newEvents:      newEvents,
 		versionHistory: persistence.NewVersionHistoryFromProto(versionHistory),
+		workerBuildID:  request.GetWorkerBuildId(),
 
 		startTime: taskStartTime,
 		logger:    logger,
@@ -210,6 +215,10 @@ func (t *nDCReplicationTaskImpl) getVersionHistory() *persistence.VersionHistory
 	return t.versionHistory
 }
 
+func (t *nDCReplicationTaskImpl) getWorkerBuildID() string {
+	return t.workerBuildID
+}
+
 func (t *nDCReplicationTaskImpl) isWorkflowReset() bool {
 	switch t.getFirstEvent().GetEventType() {
 	case enums.EventTypeDecisionTaskFailed:
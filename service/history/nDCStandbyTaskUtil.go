@@ -113,11 +113,18 @@ type (
 
 	pushActivityToMatchingInfo struct {
 		activityScheduleToStartTimeout int32
+		priority                       int32
+		fairnessKey                    string
+		workflowType                   string
 	}
 
 	pushDecisionToMatchingInfo struct {
 		decisionScheduleToStartTimeout int32
 		tasklist                       commonproto.TaskList
+		priority                       int32
+		fairnessKey                    string
+		workflowType                   string
+		pinnedBuildID                  string
 	}
 )
 
@@ -144,21 +151,35 @@ func newHistoryResendInfoFor2DC(
 
 func newPushActivityToMatchingInfo(
 	activityScheduleToStartTimeout int32,
+	priority int32,
+	fairnessKey string,
+	workflowType string,
 ) *pushActivityToMatchingInfo {
 
 	return &pushActivityToMatchingInfo{
 		activityScheduleToStartTimeout: activityScheduleToStartTimeout,
+		priority:                       priority,
+		fairnessKey:                    fairnessKey,
+		workflowType:                   workflowType,
 	}
 }
 
 func newPushDecisionToMatchingInfo(
 	decisionScheduleToStartTimeout int32,
 	tasklist commonproto.TaskList,
+	priority int32,
+	fairnessKey string,
+	workflowType string,
+	pinnedBuildID string,
 ) *pushDecisionToMatchingInfo {
 
 	return &pushDecisionToMatchingInfo{
 		decisionScheduleToStartTimeout: decisionScheduleToStartTimeout,
 		tasklist:                       tasklist,
+		priority:                       priority,
+		fairnessKey:                    fairnessKey,
+		workflowType:                   workflowType,
+		pinnedBuildID:                  pinnedBuildID,
 	}
 }
 
@@ -281,6 +281,14 @@ func (h *NilCheckHandler) CloseShard(ctx context.Context, request *historyservic
 	return resp, err
 }
 
+func (h *NilCheckHandler) DescribeShardLoad(ctx context.Context, request *historyservice.DescribeShardLoadRequest) (_ *historyservice.DescribeShardLoadResponse, retError error) {
+	resp, err := h.parentHandler.DescribeShardLoad(ctx, request)
+	if resp == nil && err == nil {
+		resp = &historyservice.DescribeShardLoadResponse{}
+	}
+	return resp, err
+}
+
 func (h *NilCheckHandler) RemoveTask(ctx context.Context, request *historyservice.RemoveTaskRequest) (_ *historyservice.RemoveTaskResponse, retError error) {
 	resp, err := h.parentHandler.RemoveTask(ctx, request)
 	if resp == nil && err == nil {
@@ -313,6 +321,14 @@ func (h *NilCheckHandler) QueryWorkflow(ctx context.Context, request *historyser
 	return resp, err
 }
 
+func (h *NilCheckHandler) UpdateWorkflowExecution(ctx context.Context, request *historyservice.UpdateWorkflowExecutionRequest) (_ *historyservice.UpdateWorkflowExecutionResponse, retError error) {
+	resp, err := h.parentHandler.UpdateWorkflowExecution(ctx, request)
+	if resp == nil && err == nil {
+		resp = &historyservice.UpdateWorkflowExecutionResponse{}
+	}
+	return resp, err
+}
+
 func (h *NilCheckHandler) ReapplyEvents(ctx context.Context, request *historyservice.ReapplyEventsRequest) (_ *historyservice.ReapplyEventsResponse, retError error) {
 	resp, err := h.parentHandler.ReapplyEvents(ctx, request)
 	if resp == nil && err == nil {
@@ -352,3 +368,11 @@ func (h *NilCheckHandler) RefreshWorkflowTasks(ctx context.Context, request *his
 	}
 	return resp, err
 }
+
+func (h *NilCheckHandler) DeleteWorkflowExecution(ctx context.Context, request *historyservice.DeleteWorkflowExecutionRequest) (*historyservice.DeleteWorkflowExecutionResponse, error) {
+	resp, err := h.parentHandler.DeleteWorkflowExecution(ctx, request)
+	if resp == nil && err == nil {
+		resp = &historyservice.DeleteWorkflowExecutionResponse{}
+	}
+	return resp, err
+}
@@ -117,6 +117,13 @@ func newQueueProcessorBase(
 	return p
 }
 
+// shardBatchSize binds a batch size filtered by shard ID to the given shard
+func shardBatchSize(batchSize dynamicconfig.IntPropertyFnWithShardIDFilter, shardID int) dynamicconfig.IntPropertyFn {
+	return func(...dynamicconfig.FilterOption) int {
+		return batchSize(int32(shardID))
+	}
+}
+
 func (p *queueProcessorBase) Start() {
 	if !atomic.CompareAndSwapInt32(&p.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
 		return
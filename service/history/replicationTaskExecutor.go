@@ -271,7 +271,8 @@ func (e *replicationTaskExecutorImpl) handleHistoryReplicationTaskV2(
 		VersionHistoryItems: attr.VersionHistoryItems,
 		Events:              attr.Events,
 		// new run events does not need version history since there is no prior events
-		NewRunEvents: attr.NewRunEvents,
+		NewRunEvents:  attr.NewRunEvents,
+		WorkerBuildId: attr.WorkerBuildId,
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), replicationTimeout)
 	defer cancel()
@@ -731,6 +731,7 @@ func (p *replicatorQueueProcessorImpl) generateHistoryReplicationTask(
 						VersionHistoryItems: versionHistoryItems,
 						Events:              eventsBlob,
 						NewRunEvents:        newRunEventsBlob,
+						WorkerBuildId:       mutableState.GetExecutionInfo().WorkerBuildID,
 					},
 				},
 			}
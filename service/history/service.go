@@ -62,20 +62,36 @@ type Config struct {
 
 	// HistoryCache settings
 	// Change of these configs require shard restart
-	HistoryCacheInitialSize dynamicconfig.IntPropertyFn
-	HistoryCacheMaxSize     dynamicconfig.IntPropertyFn
+	HistoryCacheInitialSize dynamicconfig.IntPropertyFnWithShardIDFilter
+	HistoryCacheMaxSize     dynamicconfig.IntPropertyFnWithShardIDFilter
 	HistoryCacheTTL         dynamicconfig.DurationPropertyFn
 
 	// EventsCache settings
 	// Change of these configs require shard restart
-	EventsCacheInitialSize dynamicconfig.IntPropertyFn
-	EventsCacheMaxSize     dynamicconfig.IntPropertyFn
+	EventsCacheInitialSize dynamicconfig.IntPropertyFnWithShardIDFilter
+	EventsCacheMaxSize     dynamicconfig.IntPropertyFnWithShardIDFilter
 	EventsCacheTTL         dynamicconfig.DurationPropertyFn
 
 	// ShardController settings
 	RangeSizeBits           uint
 	AcquireShardInterval    dynamicconfig.DurationPropertyFn
 	AcquireShardConcurrency dynamicconfig.IntPropertyFn
+	// EnableGracefulShardHandoff makes a shard owner drain and release its shards before another host acquires them
+	EnableGracefulShardHandoff dynamicconfig.BoolPropertyFn
+	// ShardLeaseDuration is how long an owner may write to a shard after the lease was last renewed
+	ShardLeaseDuration dynamicconfig.DurationPropertyFn
+	// ShardHandoffTimeout is the max time a new owner waits for the previous owner to release a shard
+	ShardHandoffTimeout dynamicconfig.DurationPropertyFn
+
+	// ShardLoadWindow is the length of the window over which the load of a shard is measured
+	ShardLoadWindow dynamicconfig.DurationPropertyFn
+	// HotShardRPSThreshold is the shard RPS above which a shard is considered hot, 0 disables the detection
+	HotShardRPSThreshold dynamicconfig.IntPropertyFn
+	// EnableHotWorkflowThrottling makes the workflows issuing most of the requests of a hot shard throttled
+	EnableHotWorkflowThrottling dynamicconfig.BoolPropertyFn
+	HotWorkflowShareThreshold   dynamicconfig.FloatPropertyFn
+	HotWorkflowMaxThrottled     dynamicconfig.IntPropertyFn
+	HotWorkflowThrottledRPS     dynamicconfig.IntPropertyFn
 
 	// the artificial delay added to standby cluster's view of active cluster's time
 	StandbyClusterDelay                  dynamicconfig.DurationPropertyFn
@@ -86,7 +102,7 @@ type Config struct {
 	TaskProcessRPS dynamicconfig.IntPropertyFnWithDomainFilter
 
 	// TimerQueueProcessor settings
-	TimerTaskBatchSize                               dynamicconfig.IntPropertyFn
+	TimerTaskBatchSize                               dynamicconfig.IntPropertyFnWithShardIDFilter
 	TimerTaskWorkerCount                             dynamicconfig.IntPropertyFn
 	TimerTaskMaxRetryCount                           dynamicconfig.IntPropertyFn
 	TimerProcessorGetFailureRetryCount               dynamicconfig.IntPropertyFn
@@ -103,7 +119,7 @@ type Config struct {
 	TimerProcessorArchivalTimeLimit                  dynamicconfig.DurationPropertyFn
 
 	// TransferQueueProcessor settings
-	TransferTaskBatchSize                               dynamicconfig.IntPropertyFn
+	TransferTaskBatchSize                               dynamicconfig.IntPropertyFnWithShardIDFilter
 	TransferTaskWorkerCount                             dynamicconfig.IntPropertyFn
 	TransferTaskMaxRetryCount                           dynamicconfig.IntPropertyFn
 	TransferProcessorCompleteTransferFailureRetryCount  dynamicconfig.IntPropertyFn
@@ -135,6 +151,11 @@ type Config struct {
 	MaximumBufferedEventsBatch dynamicconfig.IntPropertyFn
 	MaximumSignalsPerExecution dynamicconfig.IntPropertyFnWithDomainFilter
 
+	// Per workflow rate limits, filtered by domain and workflow type, 0 means unlimited
+	WorkflowSignalRPS             dynamicconfig.IntPropertyFnWithWorkflowTypeFilter
+	WorkflowDecisionCompletionRPS dynamicconfig.IntPropertyFnWithWorkflowTypeFilter
+	WorkflowActivityScheduleRPS   dynamicconfig.IntPropertyFnWithWorkflowTypeFilter
+
 	// ShardUpdateMinInterval the minimal time interval which the shard info can be updated
 	ShardUpdateMinInterval dynamicconfig.DurationPropertyFn
 	// ShardSyncMinInterval the minimal time interval which the shard info should be sync to remote
@@ -164,10 +185,10 @@ type Config struct {
 	// Size limit related settings
 	BlobSizeLimitError     dynamicconfig.IntPropertyFnWithDomainFilter
 	BlobSizeLimitWarn      dynamicconfig.IntPropertyFnWithDomainFilter
-	HistorySizeLimitError  dynamicconfig.IntPropertyFnWithDomainFilter
-	HistorySizeLimitWarn   dynamicconfig.IntPropertyFnWithDomainFilter
-	HistoryCountLimitError dynamicconfig.IntPropertyFnWithDomainFilter
-	HistoryCountLimitWarn  dynamicconfig.IntPropertyFnWithDomainFilter
+	HistorySizeLimitError  dynamicconfig.IntPropertyFnWithWorkflowTypeFilter
+	HistorySizeLimitWarn   dynamicconfig.IntPropertyFnWithWorkflowTypeFilter
+	HistoryCountLimitError dynamicconfig.IntPropertyFnWithWorkflowTypeFilter
+	HistoryCountLimitWarn  dynamicconfig.IntPropertyFnWithWorkflowTypeFilter
 
 	// ValidSearchAttributes is legal indexed keys that can be used in list APIs
 	ValidSearchAttributes             dynamicconfig.MapPropertyFn
@@ -227,20 +248,29 @@ func NewConfig(dc *dynamicconfig.Collection, numberOfShards int, storeType strin
 		MaxDecisionStartToCloseSeconds:                        dc.GetIntPropertyFilteredByDomain(dynamicconfig.MaxDecisionStartToCloseSeconds, 240),
 		AdvancedVisibilityWritingMode:                         dc.GetStringProperty(dynamicconfig.AdvancedVisibilityWritingMode, common.GetDefaultAdvancedVisibilityWritingMode(isAdvancedVisConfigExist)),
 		EmitShardDiffLog:                                      dc.GetBoolProperty(dynamicconfig.EmitShardDiffLog, false),
-		HistoryCacheInitialSize:                               dc.GetIntProperty(dynamicconfig.HistoryCacheInitialSize, 128),
-		HistoryCacheMaxSize:                                   dc.GetIntProperty(dynamicconfig.HistoryCacheMaxSize, 512),
+		HistoryCacheInitialSize:                               dc.GetIntPropertyFilteredByShardID(dynamicconfig.HistoryCacheInitialSize, 128),
+		HistoryCacheMaxSize:                                   dc.GetIntPropertyFilteredByShardID(dynamicconfig.HistoryCacheMaxSize, 512),
 		HistoryCacheTTL:                                       dc.GetDurationProperty(dynamicconfig.HistoryCacheTTL, time.Hour),
-		EventsCacheInitialSize:                                dc.GetIntProperty(dynamicconfig.EventsCacheInitialSize, 128),
-		EventsCacheMaxSize:                                    dc.GetIntProperty(dynamicconfig.EventsCacheMaxSize, 512),
+		EventsCacheInitialSize:                                dc.GetIntPropertyFilteredByShardID(dynamicconfig.EventsCacheInitialSize, 128),
+		EventsCacheMaxSize:                                    dc.GetIntPropertyFilteredByShardID(dynamicconfig.EventsCacheMaxSize, 512),
 		EventsCacheTTL:                                        dc.GetDurationProperty(dynamicconfig.EventsCacheTTL, time.Hour),
 		RangeSizeBits:                                         20, // 20 bits for sequencer, 2^20 sequence number for any range
 		AcquireShardInterval:                                  dc.GetDurationProperty(dynamicconfig.AcquireShardInterval, time.Minute),
 		AcquireShardConcurrency:                               dc.GetIntProperty(dynamicconfig.AcquireShardConcurrency, 1),
+		EnableGracefulShardHandoff:                            dc.GetBoolProperty(dynamicconfig.EnableGracefulShardHandoff, true),
+		ShardLeaseDuration:                                    dc.GetDurationProperty(dynamicconfig.ShardLeaseDuration, time.Minute),
+		ShardHandoffTimeout:                                   dc.GetDurationProperty(dynamicconfig.ShardHandoffTimeout, 10*time.Second),
+		ShardLoadWindow:                                       dc.GetDurationProperty(dynamicconfig.ShardLoadWindow, time.Minute),
+		HotShardRPSThreshold:                                  dc.GetIntProperty(dynamicconfig.HotShardRPSThreshold, 1000),
+		EnableHotWorkflowThrottling:                           dc.GetBoolProperty(dynamicconfig.EnableHotWorkflowThrottling, false),
+		HotWorkflowShareThreshold:                             dc.GetFloat64Property(dynamicconfig.HotWorkflowShareThreshold, 0.2),
+		HotWorkflowMaxThrottled:                               dc.GetIntProperty(dynamicconfig.HotWorkflowMaxThrottled, 3),
+		HotWorkflowThrottledRPS:                               dc.GetIntProperty(dynamicconfig.HotWorkflowThrottledRPS, 10),
 		StandbyClusterDelay:                                   dc.GetDurationProperty(dynamicconfig.StandbyClusterDelay, 5*time.Minute),
 		StandbyTaskMissingEventsResendDelay:                   dc.GetDurationProperty(dynamicconfig.StandbyTaskMissingEventsResendDelay, 15*time.Minute),
 		StandbyTaskMissingEventsDiscardDelay:                  dc.GetDurationProperty(dynamicconfig.StandbyTaskMissingEventsDiscardDelay, 25*time.Minute),
 		TaskProcessRPS:                                        dc.GetIntPropertyFilteredByDomain(dynamicconfig.TaskProcessRPS, 1000),
-		TimerTaskBatchSize:                                    dc.GetIntProperty(dynamicconfig.TimerTaskBatchSize, 100),
+		TimerTaskBatchSize:                                    dc.GetIntPropertyFilteredByShardID(dynamicconfig.TimerTaskBatchSize, 100),
 		TimerTaskWorkerCount:                                  dc.GetIntProperty(dynamicconfig.TimerTaskWorkerCount, 10),
 		TimerTaskMaxRetryCount:                                dc.GetIntProperty(dynamicconfig.TimerTaskMaxRetryCount, 100),
 		TimerProcessorGetFailureRetryCount:                    dc.GetIntProperty(dynamicconfig.TimerProcessorGetFailureRetryCount, 5),
@@ -255,7 +285,7 @@ func NewConfig(dc *dynamicconfig.Collection, numberOfShards int, storeType strin
 		TimerProcessorMaxTimeShift:                            dc.GetDurationProperty(dynamicconfig.TimerProcessorMaxTimeShift, 1*time.Second),
 		TimerProcessorHistoryArchivalSizeLimit:                dc.GetIntProperty(dynamicconfig.TimerProcessorHistoryArchivalSizeLimit, 500*1024),
 		TimerProcessorArchivalTimeLimit:                       dc.GetDurationProperty(dynamicconfig.TimerProcessorArchivalTimeLimit, 1*time.Second),
-		TransferTaskBatchSize:                                 dc.GetIntProperty(dynamicconfig.TransferTaskBatchSize, 100),
+		TransferTaskBatchSize:                                 dc.GetIntPropertyFilteredByShardID(dynamicconfig.TransferTaskBatchSize, 100),
 		TransferProcessorFailoverMaxPollRPS:                   dc.GetIntProperty(dynamicconfig.TransferProcessorFailoverMaxPollRPS, 1),
 		TransferProcessorMaxPollRPS:                           dc.GetIntProperty(dynamicconfig.TransferProcessorMaxPollRPS, 20),
 		TransferTaskWorkerCount:                               dc.GetIntProperty(dynamicconfig.TransferTaskWorkerCount, 10),
@@ -280,6 +310,9 @@ func NewConfig(dc *dynamicconfig.Collection, numberOfShards int, storeType strin
 		HistoryMgrNumConns:                                    dc.GetIntProperty(dynamicconfig.HistoryMgrNumConns, 50),
 		MaximumBufferedEventsBatch:                            dc.GetIntProperty(dynamicconfig.MaximumBufferedEventsBatch, 100),
 		MaximumSignalsPerExecution:                            dc.GetIntPropertyFilteredByDomain(dynamicconfig.MaximumSignalsPerExecution, 0),
+		WorkflowSignalRPS:                                     dc.GetIntPropertyFilteredByWorkflowType(dynamicconfig.WorkflowSignalRPS, 0),
+		WorkflowDecisionCompletionRPS:                         dc.GetIntPropertyFilteredByWorkflowType(dynamicconfig.WorkflowDecisionCompletionRPS, 0),
+		WorkflowActivityScheduleRPS:                           dc.GetIntPropertyFilteredByWorkflowType(dynamicconfig.WorkflowActivityScheduleRPS, 0),
 		ShardUpdateMinInterval:                                dc.GetDurationProperty(dynamicconfig.ShardUpdateMinInterval, 5*time.Minute),
 		ShardSyncMinInterval:                                  dc.GetDurationProperty(dynamicconfig.ShardSyncMinInterval, 2*time.Minute),
 		ShardSyncTimerJitterCoefficient:                       dc.GetFloat64Property(dynamicconfig.TransferProcessorMaxPollIntervalJitterCoefficient, 0.15),
@@ -297,10 +330,10 @@ func NewConfig(dc *dynamicconfig.Collection, numberOfShards int, storeType strin
 
 		BlobSizeLimitError:     dc.GetIntPropertyFilteredByDomain(dynamicconfig.BlobSizeLimitError, 2*1024*1024),
 		BlobSizeLimitWarn:      dc.GetIntPropertyFilteredByDomain(dynamicconfig.BlobSizeLimitWarn, 512*1024),
-		HistorySizeLimitError:  dc.GetIntPropertyFilteredByDomain(dynamicconfig.HistorySizeLimitError, 200*1024*1024),
-		HistorySizeLimitWarn:   dc.GetIntPropertyFilteredByDomain(dynamicconfig.HistorySizeLimitWarn, 50*1024*1024),
-		HistoryCountLimitError: dc.GetIntPropertyFilteredByDomain(dynamicconfig.HistoryCountLimitError, 200*1024),
-		HistoryCountLimitWarn:  dc.GetIntPropertyFilteredByDomain(dynamicconfig.HistoryCountLimitWarn, 50*1024),
+		HistorySizeLimitError:  dc.GetIntPropertyFilteredByWorkflowType(dynamicconfig.HistorySizeLimitError, 200*1024*1024),
+		HistorySizeLimitWarn:   dc.GetIntPropertyFilteredByWorkflowType(dynamicconfig.HistorySizeLimitWarn, 50*1024*1024),
+		HistoryCountLimitError: dc.GetIntPropertyFilteredByWorkflowType(dynamicconfig.HistoryCountLimitError, 200*1024),
+		HistoryCountLimitWarn:  dc.GetIntPropertyFilteredByWorkflowType(dynamicconfig.HistoryCountLimitWarn, 50*1024),
 
 		ThrottledLogRPS:   dc.GetIntProperty(dynamicconfig.HistoryThrottledLogRPS, 4),
 		EnableStickyQuery: dc.GetBoolPropertyFnWithDomainFilter(dynamicconfig.EnableStickyQuery, true),
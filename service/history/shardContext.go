@@ -58,6 +58,8 @@ type (
 		GetThrottledLogger() log.Logger
 		GetMetricsClient() metrics.Client
 		GetTimeSource() clock.TimeSource
+		GetLoadTracker() *shardLoadTracker
+		GetWorkflowRateLimiter() *workflowRateLimiter
 		PreviousShardOwnerWasDifferent() bool
 
 		GetEngine() Engine
@@ -124,6 +126,8 @@ type (
 		logger           log.Logger
 		throttledLogger  log.Logger
 		engine           Engine
+		loadTracker      *shardLoadTracker
+		rateLimiter      *workflowRateLimiter
 
 		sync.RWMutex
 		lastUpdated               time.Time
@@ -147,6 +151,9 @@ const (
 	logWarnTransferLevelDiff = 3000000 // 3 million
 	logWarnTimerLevelDiff    = time.Duration(30 * time.Minute)
 	historySizeLogThreshold  = 10 * 1024 * 1024
+
+	maxShardOwnershipHistorySize = 10
+	shardHandoffPollInterval     = 200 * time.Millisecond
 )
 
 func (s *shardContextImpl) GetShardID() int {
@@ -173,6 +180,10 @@ func (s *shardContextImpl) GenerateTransferTaskID() (int64, error) {
 	s.Lock()
 	defer s.Unlock()
 
+	if err := s.ensureLeaseLocked(); err != nil {
+		return -1, err
+	}
+
 	return s.generateTransferTaskIDLocked()
 }
 
@@ -180,6 +191,10 @@ func (s *shardContextImpl) GenerateTransferTaskIDs(number int) ([]int64, error)
 	s.Lock()
 	defer s.Unlock()
 
+	if err := s.ensureLeaseLocked(); err != nil {
+		return nil, err
+	}
+
 	result := []int64{}
 	for i := 0; i < number; i++ {
 		id, err := s.generateTransferTaskIDLocked()
@@ -471,6 +486,10 @@ func (s *shardContextImpl) CreateWorkflowExecution(
 	s.Lock()
 	defer s.Unlock()
 
+	if err := s.ensureLeaseLocked(); err != nil {
+		return nil, err
+	}
+
 	transferMaxReadLevel := int64(0)
 	if err := s.allocateTaskIDsLocked(
 		domainEntry,
@@ -489,7 +508,9 @@ Create_Loop:
 		currentRangeID := s.getRangeID()
 		request.RangeID = currentRangeID
 
+		startTime := time.Now()
 		response, err := s.executionManager.CreateWorkflowExecution(request)
+		s.loadTracker.recordPersistenceRequest(time.Since(startTime))
 		if err != nil {
 			switch err.(type) {
 			case *serviceerror.WorkflowExecutionAlreadyStarted,
@@ -552,6 +573,10 @@ func (s *shardContextImpl) UpdateWorkflowExecution(
 	s.Lock()
 	defer s.Unlock()
 
+	if err := s.ensureLeaseLocked(); err != nil {
+		return nil, err
+	}
+
 	transferMaxReadLevel := int64(0)
 	if err := s.allocateTaskIDsLocked(
 		domainEntry,
@@ -581,7 +606,9 @@ Update_Loop:
 	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
 		currentRangeID := s.getRangeID()
 		request.RangeID = currentRangeID
+		startTime := time.Now()
 		resp, err := s.executionManager.UpdateWorkflowExecution(request)
+		s.loadTracker.recordPersistenceRequest(time.Since(startTime))
 		if err != nil {
 			switch err.(type) {
 			case *persistence.ConditionFailedError,
@@ -636,6 +663,10 @@ func (s *shardContextImpl) ResetWorkflowExecution(request *persistence.ResetWork
 	s.Lock()
 	defer s.Unlock()
 
+	if err := s.ensureLeaseLocked(); err != nil {
+		return err
+	}
+
 	transferMaxReadLevel := int64(0)
 	if request.CurrentWorkflowMutation != nil {
 		if err := s.allocateTaskIDsLocked(
@@ -665,7 +696,9 @@ Reset_Loop:
 	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
 		currentRangeID := s.getRangeID()
 		request.RangeID = currentRangeID
+		startTime := time.Now()
 		err := s.executionManager.ResetWorkflowExecution(request)
+		s.loadTracker.recordPersistenceRequest(time.Since(startTime))
 		if err != nil {
 			switch err.(type) {
 			case *persistence.ConditionFailedError,
@@ -723,6 +756,10 @@ func (s *shardContextImpl) ConflictResolveWorkflowExecution(
 	s.Lock()
 	defer s.Unlock()
 
+	if err := s.ensureLeaseLocked(); err != nil {
+		return err
+	}
+
 	transferMaxReadLevel := int64(0)
 	if request.CurrentWorkflowMutation != nil {
 		if err := s.allocateTaskIDsLocked(
@@ -764,7 +801,9 @@ Reset_Loop:
 	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
 		currentRangeID := s.getRangeID()
 		request.RangeID = currentRangeID
+		startTime := time.Now()
 		err := s.executionManager.ConflictResolveWorkflowExecution(request)
+		s.loadTracker.recordPersistenceRequest(time.Since(startTime))
 		if err != nil {
 			switch err.(type) {
 			case *persistence.ConditionFailedError,
@@ -839,7 +878,9 @@ func (s *shardContextImpl) AppendHistoryV2Events(
 				tag.WorkflowHistorySizeBytes(size))
 		}
 	}()
+	startTime := time.Now()
 	resp, err0 := s.GetHistoryManager().AppendHistoryNodes(request)
+	s.loadTracker.recordPersistenceRequest(time.Since(startTime))
 	if resp != nil {
 		size = resp.Size
 	}
@@ -854,6 +895,14 @@ func (s *shardContextImpl) PreviousShardOwnerWasDifferent() bool {
 	return s.previousShardOwnerWasDifferent
 }
 
+func (s *shardContextImpl) GetLoadTracker() *shardLoadTracker {
+	return s.loadTracker
+}
+
+func (s *shardContextImpl) GetWorkflowRateLimiter() *workflowRateLimiter {
+	return s.rateLimiter
+}
+
 func (s *shardContextImpl) GetEventsCache() eventsCache {
 	return s.eventsCache
 }
@@ -915,6 +964,7 @@ func (s *shardContextImpl) renewRangeLocked(isStealing bool) error {
 	if isStealing {
 		updatedShardInfo.StolenSinceRenew++
 	}
+	updatedShardInfo.LeaseExpiry = s.newLeaseExpiry()
 
 	err := s.GetShardManager().UpdateShard(&persistence.UpdateShardRequest{
 		ShardInfo:       updatedShardInfo.ShardInfo,
@@ -957,13 +1007,32 @@ func (s *shardContextImpl) updateMaxReadLevelLocked(rl int64) {
 }
 
 func (s *shardContextImpl) updateShardInfoLocked() error {
-	var err error
 	now := clock.NewRealTimeSource().Now()
 	if s.lastUpdated.Add(s.config.ShardUpdateMinInterval()).After(now) {
 		return nil
 	}
-	updatedShardInfo := copyShardInfo(s.shardInfo)
 	s.emitShardInfoMetricsLogsLocked()
+	return s.persistShardInfoLocked(now)
+}
+
+// ensureLeaseLocked renews the lease on the shard once less than half of it is left, so that the shard
+// is never written to after its lease expired. The renewal is conditional on the range ID and fails if
+// another host acquired the shard in the meantime, in which case the shard is closed.
+func (s *shardContextImpl) ensureLeaseLocked() error {
+	if s.isClosed {
+		return &persistence.ShardOwnershipLostError{ShardID: s.shardID, Msg: "shard is closed"}
+	}
+	leaseExpiry, err := types.TimestampFromProto(s.shardInfo.LeaseExpiry)
+	if err == nil && leaseExpiry.Sub(s.GetTimeSource().Now()) > s.config.ShardLeaseDuration()/2 {
+		return nil
+	}
+	return s.persistShardInfoLocked(clock.NewRealTimeSource().Now())
+}
+
+func (s *shardContextImpl) persistShardInfoLocked(now time.Time) error {
+	var err error
+	updatedShardInfo := copyShardInfo(s.shardInfo)
+	updatedShardInfo.LeaseExpiry = s.newLeaseExpiry()
 
 	err = s.GetShardManager().UpdateShard(&persistence.UpdateShardRequest{
 		ShardInfo:       updatedShardInfo.ShardInfo,
@@ -976,12 +1045,58 @@ func (s *shardContextImpl) updateShardInfoLocked() error {
 			s.closeShard()
 		}
 	} else {
+		s.shardInfo.LeaseExpiry = updatedShardInfo.LeaseExpiry
 		s.lastUpdated = now
 	}
 
 	return err
 }
 
+func (s *shardContextImpl) newLeaseExpiry() *types.Timestamp {
+	leaseExpiry, _ := types.TimestampProto(s.GetTimeSource().Now().Add(s.config.ShardLeaseDuration()))
+	return leaseExpiry
+}
+
+// releaseShard persists the shard info with the latest ack levels and marks the shard as released,
+// which lets the next owner acquire it right away instead of waiting for the lease to expire.
+// It must be called after the engine is stopped; any write attempted afterwards fails as if the
+// shard was stolen.
+func (s *shardContextImpl) releaseShard() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.isClosed {
+		// shard is already fenced off
+		return nil
+	}
+
+	updatedShardInfo := copyShardInfo(s.shardInfo)
+	updatedShardInfo.Released = true
+	if count := len(updatedShardInfo.OwnershipHistory); count > 0 {
+		updatedShardInfo.OwnershipHistory[count-1].ReleasedAt, _ = types.TimestampProto(s.GetTimeSource().Now())
+	}
+
+	err := s.GetShardManager().UpdateShard(&persistence.UpdateShardRequest{
+		ShardInfo:       updatedShardInfo.ShardInfo,
+		PreviousRangeID: s.shardInfo.RangeID,
+	})
+
+	s.isClosed = true
+	s.shardInfo.RangeID = -1
+	atomic.StoreInt64(&s.rangeID, s.shardInfo.RangeID)
+	return err
+}
+
+// getQueueLag returns the number of transfer tasks not acked yet and how far behind the timer queue is
+func (s *shardContextImpl) getQueueLag() (int64, time.Duration) {
+	s.RLock()
+	defer s.RUnlock()
+
+	transferLag := s.transferMaxReadLevel - s.shardInfo.TransferAckLevel
+	timerAckLevel, _ := types.TimestampFromProto(s.shardInfo.TimerAckLevel)
+	return transferLag, s.GetTimeSource().Now().Sub(timerAckLevel)
+}
+
 func (s *shardContextImpl) emitShardInfoMetricsLogsLocked() {
 	currentCluster := s.GetClusterMetadata().GetCurrentClusterName()
 
@@ -1158,11 +1273,11 @@ func (s *shardContextImpl) GetLastUpdatedTime() time.Time {
 	return s.lastUpdated
 }
 
-func acquireShard(shardItem *historyShardsItem, closeCh chan<- int) (ShardContext,
+// acquireShard acquires the shard for the host, shardInfo is the shard info read while waiting for the
+// shard handoff, the shard info is read again if it is nil
+func acquireShard(shardItem *historyShardsItem, shardInfo *persistence.ShardInfoWithFailover, closeCh chan<- int) (*shardContextImpl,
 	error) {
 
-	var shardInfo *persistence.ShardInfoWithFailover
-
 	retryPolicy := backoff.NewExponentialRetryPolicy(50 * time.Millisecond)
 	retryPolicy.SetMaximumInterval(time.Second)
 	retryPolicy.SetExpirationInterval(5 * time.Second)
@@ -1199,15 +1314,44 @@ func acquireShard(shardItem *historyShardsItem, closeCh chan<- int) (ShardContex
 		return shardItem.GetShardManager().CreateShard(&persistence.CreateShardRequest{ShardInfo: shardInfo.ShardInfo})
 	}
 
-	err := backoff.Retry(getShard, retryPolicy, retryPredicate)
-	if err != nil {
-		shardItem.logger.Error("Fail to acquire shard.", tag.ShardID(shardItem.shardID), tag.Error(err))
-		return nil, err
+	if shardInfo == nil {
+		if err := backoff.Retry(getShard, retryPolicy, retryPredicate); err != nil {
+			shardItem.logger.Error("Fail to acquire shard.", tag.ShardID(shardItem.shardID), tag.Error(err))
+			return nil, err
+		}
+	}
+
+	acquisitionType := classifyShardAcquisition(shardItem, shardInfo)
+	if acquisitionType == persistenceblobs.AcquisitionUnknown {
+		shardItem.logger.Warn("Stealing shard which was not released by its previous owner.",
+			tag.ShardID(shardItem.shardID),
+			tag.Address(shardInfo.Owner),
+			tag.ShardRangeID(shardInfo.RangeID))
+		acquisitionType = persistenceblobs.AcquisitionStolen
+	}
+	switch acquisitionType {
+	case persistenceblobs.AcquisitionHandedOff:
+		shardItem.GetMetricsClient().IncCounter(metrics.ShardInfoScope, metrics.ShardHandedOffCounter)
+	case persistenceblobs.AcquisitionStolen:
+		shardItem.GetMetricsClient().IncCounter(metrics.ShardInfoScope, metrics.ShardStolenCounter)
 	}
 
 	updatedShardInfo := copyShardInfo(shardInfo)
 	ownershipChanged := shardInfo.Owner != shardItem.GetHostInfo().Identity()
 	updatedShardInfo.Owner = shardItem.GetHostInfo().Identity()
+	updatedShardInfo.Released = false
+	acquiredAt, _ := types.TimestampProto(shardItem.GetTimeSource().Now())
+	updatedShardInfo.OwnershipHistory = append(updatedShardInfo.OwnershipHistory, &persistenceblobs.ShardOwnershipRecord{
+		Owner: updatedShardInfo.Owner,
+		// the range ID the shard is acquired with by renewRangeLocked below
+		RangeID:         updatedShardInfo.RangeID + 1,
+		AcquiredAt:      acquiredAt,
+		AcquisitionType: acquisitionType,
+		PreviousOwner:   shardInfo.Owner,
+	})
+	if count := len(updatedShardInfo.OwnershipHistory); count > maxShardOwnershipHistorySize {
+		updatedShardInfo.OwnershipHistory = updatedShardInfo.OwnershipHistory[count-maxShardOwnershipHistorySize:]
+	}
 
 	// initialize the cluster current time to be the same as ack level
 	remoteClusterCurrentTime := make(map[string]time.Time)
@@ -1247,6 +1391,8 @@ func acquireShard(shardItem *historyShardsItem, closeCh chan<- int) (ShardContex
 		timerMaxReadLevelMap:           timerMaxReadLevelMap, // use ack to init read level
 		logger:                         shardItem.logger,
 		throttledLogger:                shardItem.throttledLogger,
+		loadTracker:                    shardItem.loadTracker,
+		rateLimiter:                    shardItem.rateLimiter,
 		previousShardOwnerWasDifferent: ownershipChanged,
 	}
 	shardContext.eventsCache = newEventsCache(shardContext)
@@ -1259,6 +1405,83 @@ func acquireShard(shardItem *historyShardsItem, closeCh chan<- int) (ShardContex
 	return shardContext, nil
 }
 
+// classifyShardAcquisition tells how the shard is being acquired based on the shard info persisted by
+// its current owner. AcquisitionUnknown is returned if the current owner still holds a valid lease.
+func classifyShardAcquisition(
+	shardItem *historyShardsItem,
+	shardInfo *persistence.ShardInfoWithFailover,
+) persistenceblobs.ShardOwnershipRecord_AcquisitionType {
+
+	switch {
+	case shardInfo.Owner == "":
+		return persistenceblobs.AcquisitionCreated
+	case shardInfo.Owner == shardItem.GetHostInfo().Identity():
+		return persistenceblobs.AcquisitionReacquired
+	case shardInfo.Released:
+		return persistenceblobs.AcquisitionHandedOff
+	case shardInfo.LeaseExpiry == nil:
+		// shard was last updated before leases were introduced
+		return persistenceblobs.AcquisitionLeaseExpired
+	}
+
+	leaseExpiry, err := types.TimestampFromProto(shardInfo.LeaseExpiry)
+	if err != nil || !leaseExpiry.After(shardItem.GetTimeSource().Now()) {
+		return persistenceblobs.AcquisitionLeaseExpired
+	}
+	return persistenceblobs.AcquisitionUnknown
+}
+
+// waitForShardHandoff waits for the current owner of the shard to release it or to let its lease expire.
+// The current owner is only waited for while it is still a member of the ring, and for no longer than
+// ShardHandoffTimeout, after which the shard is stolen; the range ID renewal on acquisition fences the
+// previous owner off in that case. The wait ends early once the shard item is no longer initialized.
+// It returns the last shard info read, which is nil if the shard does not exist yet.
+func waitForShardHandoff(shardItem *historyShardsItem) (*persistence.ShardInfoWithFailover, error) {
+	timeSource := shardItem.GetTimeSource()
+	startTime := timeSource.Now()
+	deadline := startTime.Add(shardItem.config.ShardHandoffTimeout())
+	defer func() {
+		shardItem.GetMetricsClient().RecordTimer(metrics.ShardInfoScope, metrics.ShardHandoffWaitLatency, timeSource.Now().Sub(startTime))
+	}()
+
+	var shardInfo *persistence.ShardInfoWithFailover
+	for shardItem.isInitialized() {
+		resp, err := shardItem.GetShardManager().GetShard(&persistence.GetShardRequest{
+			ShardID: int32(shardItem.shardID),
+		})
+		if err != nil {
+			if _, ok := err.(*serviceerror.NotFound); ok {
+				return nil, nil
+			}
+			return nil, err
+		}
+		shardInfo = &persistence.ShardInfoWithFailover{ShardInfo: resp.ShardInfo}
+		if classifyShardAcquisition(shardItem, shardInfo) != persistenceblobs.AcquisitionUnknown {
+			return shardInfo, nil
+		}
+
+		now := timeSource.Now()
+		if !now.Before(deadline) || !isHistoryHostMember(shardItem, shardInfo.Owner) {
+			return shardInfo, nil
+		}
+		waitTime := deadline.Sub(now)
+		if waitTime > shardHandoffPollInterval {
+			waitTime = shardHandoffPollInterval
+		}
+		time.Sleep(waitTime)
+	}
+	return shardInfo, nil
+}
+
+func isHistoryHostMember(shardItem *historyShardsItem, identity string) bool {
+	for _, host := range shardItem.GetHistoryServiceResolver().Members() {
+		if host.Identity() == identity {
+			return true
+		}
+	}
+	return false
+}
+
 func copyShardInfo(shardInfo *persistence.ShardInfoWithFailover) *persistence.ShardInfoWithFailover {
 	transferFailoverLevels := map[string]persistence.TransferFailoverLevel{}
 	for k, v := range shardInfo.TransferFailoverLevels {
@@ -1280,6 +1503,11 @@ func copyShardInfo(shardInfo *persistence.ShardInfoWithFailover) *persistence.Sh
 	for k, v := range shardInfo.ClusterReplicationLevel {
 		clusterReplicationLevel[k] = v
 	}
+	var ownershipHistory []*persistenceblobs.ShardOwnershipRecord
+	for _, record := range shardInfo.OwnershipHistory {
+		recordCopy := *record
+		ownershipHistory = append(ownershipHistory, &recordCopy)
+	}
 	shardInfoCopy := &persistence.ShardInfoWithFailover{
 		ShardInfo: &persistenceblobs.ShardInfo{
 			ShardID:                   shardInfo.ShardID,
@@ -1294,6 +1522,9 @@ func copyShardInfo(shardInfo *persistence.ShardInfoWithFailover) *persistence.Sh
 			DomainNotificationVersion: shardInfo.DomainNotificationVersion,
 			ClusterReplicationLevel:   clusterReplicationLevel,
 			UpdatedAt:                 shardInfo.UpdatedAt,
+			LeaseExpiry:               shardInfo.LeaseExpiry,
+			Released:                  shardInfo.Released,
+			OwnershipHistory:          ownershipHistory,
 		},
 		TransferFailoverLevels: transferFailoverLevels,
 		TimerFailoverLevels:    timerFailoverLevels,
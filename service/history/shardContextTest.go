@@ -23,6 +23,7 @@ package history
 import (
 	"time"
 
+	"github.com/gogo/protobuf/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/mock"
 
@@ -47,6 +48,10 @@ func newTestShardContext(
 ) *shardContextTest {
 	resource := resource.NewTest(ctrl, metrics.History)
 	eventsCache := NewMockeventsCache(ctrl)
+	if shardInfo.LeaseExpiry == nil {
+		// the lease outlives the test so that writes do not renew it
+		shardInfo.LeaseExpiry, _ = types.TimestampProto(time.Now().Add(time.Hour))
+	}
 	shard := &shardContextImpl{
 		Resource:                  resource,
 		shardID:                   int(shardInfo.ShardID),
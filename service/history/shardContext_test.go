@@ -0,0 +1,94 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common/persistence"
+)
+
+type (
+	shardContextSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller *gomock.Controller
+		mockShard  *shardContextTest
+	}
+)
+
+func TestShardContextSuite(t *testing.T) {
+	s := new(shardContextSuite)
+	suite.Run(t, s)
+}
+
+func (s *shardContextSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.controller = gomock.NewController(s.T())
+	leaseExpiry, _ := types.TimestampProto(time.Now().Add(time.Second))
+	s.mockShard = newTestShardContext(
+		s.controller,
+		&persistence.ShardInfoWithFailover{
+			ShardInfo: &persistenceblobs.ShardInfo{
+				ShardID:     0,
+				RangeID:     1,
+				LeaseExpiry: leaseExpiry,
+			}},
+		NewDynamicConfigForTest(),
+	)
+}
+
+func (s *shardContextSuite) TearDownTest() {
+	s.controller.Finish()
+	s.mockShard.Finish(s.T())
+}
+
+func (s *shardContextSuite) TestWriteRenewsLease() {
+	s.mockShard.resource.ShardMgr.On("UpdateShard", mock.Anything).Return(nil).Once()
+
+	_, err := s.mockShard.GenerateTransferTaskID()
+	s.NoError(err)
+	leaseExpiry, err := types.TimestampFromProto(s.mockShard.shardInfo.LeaseExpiry)
+	s.NoError(err)
+	s.True(leaseExpiry.After(time.Now().Add(s.mockShard.config.ShardLeaseDuration() / 2)))
+
+	// the lease is not renewed again while more than half of it is left
+	_, err = s.mockShard.GenerateTransferTaskID()
+	s.NoError(err)
+}
+
+func (s *shardContextSuite) TestWriteRejectedWhenLeaseNotRenewed() {
+	s.mockShard.resource.ShardMgr.On("UpdateShard", mock.Anything).Return(&persistence.TimeoutError{Msg: "timeout"}).Once()
+
+	_, err := s.mockShard.GenerateTransferTaskID()
+	s.IsType(&persistence.TimeoutError{}, err)
+	s.Equal(int64(1), s.mockShard.transferSequenceNumber)
+}
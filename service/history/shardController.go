@@ -26,6 +26,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
@@ -68,10 +69,15 @@ type (
 		logger          log.Logger
 		throttledLogger log.Logger
 		engineFactory   EngineFactory
+		rateLimiter     *workflowRateLimiter
+		loadTracker     *shardLoadTracker
 
 		sync.RWMutex
 		status historyShardsItemStatus
 		engine Engine
+		shard  *shardContextImpl
+		// handoffLock makes concurrent engine creations wait for a single shard handoff
+		handoffLock sync.Mutex
 	}
 )
 
@@ -81,6 +87,18 @@ const (
 	historyShardsItemStatusStopped
 )
 
+var (
+	// requests which terminate, cancel or reset a workflow, or which unblock it, go through even when the workflow
+	// is throttled, otherwise operators could not stop a runaway workflow and its parent could be stuck
+	hotWorkflowExemptScopes = map[int]bool{
+		metrics.HistoryTerminateWorkflowExecutionScope:     true,
+		metrics.HistoryRequestCancelWorkflowExecutionScope: true,
+		metrics.HistoryResetWorkflowExecutionScope:         true,
+		metrics.HistoryScheduleDecisionTaskScope:           true,
+		metrics.HistoryRecordChildExecutionCompletedScope:  true,
+	}
+)
+
 func newShardController(
 	resource resource.Resource,
 	factory EngineFactory,
@@ -110,14 +128,18 @@ func newHistoryShardsItem(
 ) (*historyShardsItem, error) {
 
 	hostIdentity := resource.GetHostInfo().Identity()
+	logger := resource.GetLogger().WithTags(tag.ShardID(shardID), tag.Address(hostIdentity))
+	rateLimiter := newWorkflowRateLimiter(config, resource.GetMetricsClient())
 	return &historyShardsItem{
 		Resource:        resource,
 		shardID:         shardID,
 		status:          historyShardsItemStatusInitialized,
 		engineFactory:   factory,
 		config:          config,
-		logger:          resource.GetLogger().WithTags(tag.ShardID(shardID), tag.Address(hostIdentity)),
+		logger:          logger,
 		throttledLogger: resource.GetThrottledLogger().WithTags(tag.ShardID(shardID), tag.Address(hostIdentity)),
+		rateLimiter:     rateLimiter,
+		loadTracker:     newShardLoadTracker(shardID, config, resource.GetTimeSource(), resource.GetMetricsClient(), logger, rateLimiter),
 	}, nil
 }
 
@@ -170,6 +192,41 @@ func (c *shardController) getEngineForShard(shardID int) (Engine, error) {
 	return item.getOrCreateEngine(c.shardClosedCh)
 }
 
+// getEngineForRequest returns the engine of the shard owning the workflow after accounting the request
+// in the load of the shard, it fails if the workflow is throttled because of its load on a hot shard.
+// Requests which stop or unblock a workflow are never throttled.
+func (c *shardController) getEngineForRequest(scope int, domainID string, workflowID string) (Engine, error) {
+	sw := c.metricsScope.StartTimer(metrics.GetEngineForShardLatency)
+	defer sw.Stop()
+	item, err := c.getOrCreateHistoryShardItem(c.config.GetShardID(workflowID))
+	if err != nil {
+		return nil, err
+	}
+	engine, err := item.getOrCreateEngine(c.shardClosedCh)
+	if err != nil {
+		return nil, err
+	}
+	if !hotWorkflowExemptScopes[scope] {
+		if err := item.rateLimiter.allowRequest(domainID, workflowID); err != nil {
+			return nil, err
+		}
+	}
+	item.loadTracker.recordRequest(scope, domainID, workflowID)
+	return engine, nil
+}
+
+// describeShardLoad returns the load of a shard owned by the host
+func (c *shardController) describeShardLoad(shardID int) (*adminservice.ShardLoad, error) {
+	item, err := c.getOrCreateHistoryShardItem(shardID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := item.getOrCreateEngine(c.shardClosedCh); err != nil {
+		return nil, err
+	}
+	return item.describeLoad(), nil
+}
+
 func (c *shardController) removeEngineForShard(shardID int) {
 	sw := c.metricsScope.StartTimer(metrics.RemoveEngineForShardLatency)
 	defer sw.Stop()
@@ -179,6 +236,17 @@ func (c *shardController) removeEngineForShard(shardID int) {
 	}
 }
 
+// releaseEngineForShard stops the engine of a shard which now belongs to another host
+// and hands the shard off to it
+func (c *shardController) releaseEngineForShard(shardID int) {
+	sw := c.metricsScope.StartTimer(metrics.RemoveEngineForShardLatency)
+	defer sw.Stop()
+	item, _ := c.removeHistoryShardItem(shardID)
+	if item != nil {
+		item.releaseEngine()
+	}
+}
+
 func (c *shardController) getOrCreateHistoryShardItem(shardID int) (*historyShardsItem, error) {
 	c.RLock()
 	if item, ok := c.historyShards[shardID]; ok {
@@ -250,9 +318,10 @@ func (c *shardController) removeHistoryShardItem(shardID int) (*historyShardsIte
 // shardController. It is responsible for acquiring /
 // releasing shards in response to any event that can
 // change the shard ownership. These events are
-//   a. Ring membership change
-//   b. Periodic ticker
-//   c. ShardOwnershipLostError and subsequent ShardClosedEvents from engine
+//
+//	a. Ring membership change
+//	b. Periodic ticker
+//	c. ShardOwnershipLostError and subsequent ShardClosedEvents from engine
 func (c *shardController) shardManagementPump() {
 
 	defer c.shutdownWG.Done()
@@ -318,7 +387,7 @@ func (c *shardController) acquireShards() {
 							c.logger.Error("Unable to create history shard engine", tag.Error(err1), tag.OperationFailed, tag.ShardID(shardID))
 						}
 					} else {
-						c.removeEngineForShard(shardID)
+						c.releaseEngineForShard(shardID)
 					}
 				}
 			}
@@ -340,7 +409,7 @@ func (c *shardController) doShutdown() {
 	c.Lock()
 	defer c.Unlock()
 	for _, item := range c.historyShards {
-		item.stopEngine()
+		item.releaseEngine()
 	}
 	c.historyShards = nil
 }
@@ -385,12 +454,25 @@ func (i *historyShardsItem) getOrCreateEngine(shardClosedCh chan<- int) (Engine,
 	}
 	i.RUnlock()
 
+	var shardInfo *persistence.ShardInfoWithFailover
+	if i.config.EnableGracefulShardHandoff() {
+		// the previous owner is waited for without holding the item lock, so that the item can be stopped meanwhile
+		var err error
+		i.handoffLock.Lock()
+		shardInfo, err = waitForShardHandoff(i)
+		i.handoffLock.Unlock()
+		if err != nil {
+			i.logger.Error("Fail to acquire shard.", tag.ShardID(i.shardID), tag.Error(err))
+			return nil, err
+		}
+	}
+
 	i.Lock()
 	defer i.Unlock()
 	switch i.status {
 	case historyShardsItemStatusInitialized:
 		i.logger.Info("", tag.LifeCycleStarting, tag.ComponentShardEngine)
-		context, err := acquireShard(i, shardClosedCh)
+		context, err := acquireShard(i, shardInfo, shardClosedCh)
 		if err != nil {
 			return nil, err
 		}
@@ -398,6 +480,7 @@ func (i *historyShardsItem) getOrCreateEngine(shardClosedCh chan<- int) (Engine,
 			i.GetMetricsClient().RecordTimer(metrics.ShardInfoScope, metrics.ShardItemAcquisitionLatency,
 				context.GetCurrentTime(i.GetClusterMetadata().GetCurrentClusterName()).Sub(context.GetLastUpdatedTime()))
 		}
+		i.shard = context
 		i.engine = i.engineFactory.CreateEngine(context)
 		i.engine.Start()
 		i.logger.Info("", tag.LifeCycleStarted, tag.ComponentShardEngine)
@@ -413,6 +496,16 @@ func (i *historyShardsItem) getOrCreateEngine(shardClosedCh chan<- int) (Engine,
 }
 
 func (i *historyShardsItem) stopEngine() {
+	i.stopEngineAndRelease(false)
+}
+
+// releaseEngine stops the engine, which drains the in-flight transfer and timer tasks,
+// then flushes the ack levels and marks the shard as released for the next owner
+func (i *historyShardsItem) releaseEngine() {
+	i.stopEngineAndRelease(i.config.EnableGracefulShardHandoff())
+}
+
+func (i *historyShardsItem) stopEngineAndRelease(release bool) {
 	i.Lock()
 	defer i.Unlock()
 
@@ -423,6 +516,10 @@ func (i *historyShardsItem) stopEngine() {
 		i.logger.Info("", tag.LifeCycleStopping, tag.ComponentShardEngine)
 		i.engine.Stop()
 		i.engine = nil
+		if release {
+			i.releaseShard()
+		}
+		i.shard = nil
 		i.logger.Info("", tag.LifeCycleStopped, tag.ComponentShardEngine)
 		i.status = historyShardsItemStatusStopped
 	case historyShardsItemStatusStopped:
@@ -432,6 +529,35 @@ func (i *historyShardsItem) stopEngine() {
 	}
 }
 
+func (i *historyShardsItem) releaseShard() {
+	if err := i.shard.releaseShard(); err != nil {
+		i.GetMetricsClient().IncCounter(metrics.ShardInfoScope, metrics.ShardReleaseFailedCounter)
+		i.logger.Warn("Failed to release shard.", tag.Error(err))
+		return
+	}
+	i.GetMetricsClient().IncCounter(metrics.ShardInfoScope, metrics.ShardReleasedCounter)
+	i.logger.Info("Shard released.", tag.ComponentShardEngine)
+}
+
+func (i *historyShardsItem) describeLoad() *adminservice.ShardLoad {
+	load := i.loadTracker.describe()
+
+	i.RLock()
+	defer i.RUnlock()
+	if i.shard != nil {
+		transferLag, timerLag := i.shard.getQueueLag()
+		load.TransferQueueLag = transferLag
+		load.TimerQueueLagSeconds = int64(timerLag / time.Second)
+	}
+	return load
+}
+
+func (i *historyShardsItem) isInitialized() bool {
+	i.RLock()
+	defer i.RUnlock()
+	return i.status == historyShardsItemStatusInitialized
+}
+
 func (i *historyShardsItem) isValid() bool {
 	i.RLock()
 	defer i.RUnlock()
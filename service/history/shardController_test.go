@@ -36,6 +36,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/temporalio/temporal/common/clock"
 	"github.com/temporalio/temporal/common/cluster"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
@@ -57,6 +58,7 @@ type (
 		mockHistoryEngine   *MockEngine
 		mockClusterMetadata *cluster.MockMetadata
 		mockServiceResolver *membership.MockServiceResolver
+		timeSource          *clock.EventTimeSource
 
 		hostInfo          *membership.HostInfo
 		mockShardManager  *mmocks.ShardManager
@@ -85,6 +87,8 @@ func (s *shardControllerSuite) SetupTest() {
 	s.mockServiceResolver = s.mockResource.HistoryServiceResolver
 	s.mockClusterMetadata = s.mockResource.ClusterMetadata
 	s.hostInfo = s.mockResource.GetHostInfo()
+	s.timeSource = clock.NewEventTimeSource().Update(time.Now())
+	s.mockResource.TimeSource = s.timeSource
 
 	s.logger = s.mockResource.Logger
 	s.config = NewDynamicConfigForTest()
@@ -154,6 +158,8 @@ func (s *shardControllerSuite) TestAcquireShardSuccess() {
 						cluster.TestAlternativeClusterName: alternativeClusterTimerAck,
 					},
 					ClusterReplicationLevel: map[string]int64{},
+					LeaseExpiry:             s.expectedLeaseExpiry(),
+					OwnershipHistory:        s.expectedOwnershipHistory(6),
 				},
 				PreviousRangeID: 5,
 			}).Return(nil).Once()
@@ -234,6 +240,8 @@ func (s *shardControllerSuite) TestAcquireShardsConcurrently() {
 						cluster.TestAlternativeClusterName: alternativeClusterTimerAck,
 					},
 					ClusterReplicationLevel: map[string]int64{},
+					LeaseExpiry:             s.expectedLeaseExpiry(),
+					OwnershipHistory:        s.expectedOwnershipHistory(6),
 				},
 				PreviousRangeID: 5,
 			}).Return(nil).Once()
@@ -321,6 +329,8 @@ func (s *shardControllerSuite) TestAcquireShardRenewSuccess() {
 					cluster.TestAlternativeClusterName: alternativeClusterTimerAck,
 				},
 				ClusterReplicationLevel: map[string]int64{},
+				LeaseExpiry:             s.expectedLeaseExpiry(),
+				OwnershipHistory:        s.expectedOwnershipHistory(6),
 			},
 			PreviousRangeID: 5,
 		}).Return(nil).Once()
@@ -393,6 +403,8 @@ func (s *shardControllerSuite) TestAcquireShardRenewLookupFailed() {
 					cluster.TestAlternativeClusterName: alternativeClusterTimerAck,
 				},
 				ClusterReplicationLevel: map[string]int64{},
+				LeaseExpiry:             s.expectedLeaseExpiry(),
+				OwnershipHistory:        s.expectedOwnershipHistory(6),
 			},
 			PreviousRangeID: 5,
 		}).Return(nil).Once()
@@ -413,6 +425,72 @@ func (s *shardControllerSuite) TestAcquireShardRenewLookupFailed() {
 	}
 }
 
+func (s *shardControllerSuite) TestAcquireShardWaitsForHandoff() {
+	s.config.NumberOfShards = 1
+	previousHostInfo := membership.NewHostInfo("test-previous-host", nil)
+	leaseExpiry := s.timeSource.Now().Add(time.Minute)
+
+	s.mockServiceResolver.EXPECT().Lookup(string(0)).Return(s.hostInfo, nil).Times(1)
+	s.mockServiceResolver.EXPECT().Members().Return([]*membership.HostInfo{previousHostInfo, s.hostInfo}).Times(1)
+	s.mockShardManager.On("GetShard", &persistence.GetShardRequest{ShardID: 0}).Return(
+		&persistence.GetShardResponse{
+			ShardInfo: s.newPreviousOwnerShardInfo(previousHostInfo.Identity(), leaseExpiry, false),
+		}, nil).Once()
+	s.mockShardManager.On("GetShard", &persistence.GetShardRequest{ShardID: 0}).Return(
+		&persistence.GetShardResponse{
+			ShardInfo: s.newPreviousOwnerShardInfo(previousHostInfo.Identity(), leaseExpiry, true),
+		}, nil).Once()
+	s.setupMocksForAcquireShardFromPreviousOwner(
+		s.newPreviousOwnerShardInfo(previousHostInfo.Identity(), leaseExpiry, true),
+		persistenceblobs.AcquisitionHandedOff,
+	)
+
+	engine, err := s.shardController.getEngineForShard(0)
+	s.NoError(err)
+	s.NotNil(engine)
+}
+
+func (s *shardControllerSuite) TestAcquireShardStealsFromDepartedOwner() {
+	s.config.NumberOfShards = 1
+	previousHostInfo := membership.NewHostInfo("test-previous-host", nil)
+	leaseExpiry := s.timeSource.Now().Add(time.Minute)
+
+	s.mockServiceResolver.EXPECT().Lookup(string(0)).Return(s.hostInfo, nil).Times(1)
+	s.mockServiceResolver.EXPECT().Members().Return([]*membership.HostInfo{s.hostInfo}).Times(1)
+	s.mockShardManager.On("GetShard", &persistence.GetShardRequest{ShardID: 0}).Return(
+		&persistence.GetShardResponse{
+			ShardInfo: s.newPreviousOwnerShardInfo(previousHostInfo.Identity(), leaseExpiry, false),
+		}, nil).Once()
+	s.setupMocksForAcquireShardFromPreviousOwner(
+		s.newPreviousOwnerShardInfo(previousHostInfo.Identity(), leaseExpiry, false),
+		persistenceblobs.AcquisitionStolen,
+	)
+
+	engine, err := s.shardController.getEngineForShard(0)
+	s.NoError(err)
+	s.NotNil(engine)
+}
+
+func (s *shardControllerSuite) TestAcquireShardLeaseExpired() {
+	s.config.NumberOfShards = 1
+	previousHostInfo := membership.NewHostInfo("test-previous-host", nil)
+	leaseExpiry := s.timeSource.Now().Add(-time.Second)
+
+	s.mockServiceResolver.EXPECT().Lookup(string(0)).Return(s.hostInfo, nil).Times(1)
+	s.mockShardManager.On("GetShard", &persistence.GetShardRequest{ShardID: 0}).Return(
+		&persistence.GetShardResponse{
+			ShardInfo: s.newPreviousOwnerShardInfo(previousHostInfo.Identity(), leaseExpiry, false),
+		}, nil).Once()
+	s.setupMocksForAcquireShardFromPreviousOwner(
+		s.newPreviousOwnerShardInfo(previousHostInfo.Identity(), leaseExpiry, false),
+		persistenceblobs.AcquisitionLeaseExpired,
+	)
+
+	engine, err := s.shardController.getEngineForShard(0)
+	s.NoError(err)
+	s.NotNil(engine)
+}
+
 func (s *shardControllerSuite) TestHistoryEngineClosed() {
 	numShards := 4
 	s.config.NumberOfShards = numShards
@@ -497,6 +575,7 @@ func (s *shardControllerSuite) TestHistoryEngineClosed() {
 		mockEngine := historyEngines[shardID]
 		mockEngine.EXPECT().Stop().Return().Times(1)
 		s.mockServiceResolver.EXPECT().Lookup(string(shardID)).Return(s.hostInfo, nil).AnyTimes()
+		s.setupMocksForReleaseShard(shardID, 6)
 	}
 	s.shardController.Stop()
 }
@@ -523,6 +602,7 @@ func (s *shardControllerSuite) TestRingUpdated() {
 		mockEngine := historyEngines[shardID]
 		mockEngine.EXPECT().Stop().Times(1)
 		s.mockServiceResolver.EXPECT().Lookup(string(shardID)).Return(differentHostInfo, nil).AnyTimes()
+		s.setupMocksForReleaseShard(shardID, 6)
 	}
 	s.mockServiceResolver.EXPECT().Lookup(string(2)).Return(s.hostInfo, nil).AnyTimes()
 	s.mockServiceResolver.EXPECT().Lookup(string(3)).Return(s.hostInfo, nil).AnyTimes()
@@ -571,6 +651,7 @@ func (s *shardControllerSuite) TestRingUpdated() {
 		mockEngine := historyEngines[shardID]
 		mockEngine.EXPECT().Stop().Times(1)
 		s.mockServiceResolver.EXPECT().Lookup(string(shardID)).Return(s.hostInfo, nil).AnyTimes()
+		s.setupMocksForReleaseShard(shardID, 6)
 	}
 	s.shardController.Stop()
 }
@@ -618,6 +699,7 @@ func (s *shardControllerSuite) TestShardControllerClosed() {
 		mockEngine := historyEngines[shardID]
 		mockEngine.EXPECT().Stop().Times(1)
 		s.mockServiceResolver.EXPECT().Lookup(string(shardID)).Return(s.hostInfo, nil).AnyTimes()
+		s.setupMocksForReleaseShard(shardID, 6)
 	}
 	s.shardController.Stop()
 	workerWG.Wait()
@@ -626,55 +708,131 @@ func (s *shardControllerSuite) TestShardControllerClosed() {
 func (s *shardControllerSuite) setupMocksForAcquireShard(shardID int, mockEngine *MockEngine, currentRangeID,
 	newRangeID int64) {
 
-	replicationAck := int64(201)
-	currentClusterTransferAck := int64(210)
-	alternativeClusterTransferAck := int64(320)
-	currentClusterTimerAck := gogoProtoTimestampNowAddDuration(-100)
-	alternativeClusterTimerAck := gogoProtoTimestampNowAddDuration(-200)
-
 	// s.mockResource.ExecutionMgr.On("Close").Return()
 	mockEngine.EXPECT().Start().Times(1)
 	s.mockServiceResolver.EXPECT().Lookup(string(shardID)).Return(s.hostInfo, nil).Times(2)
 	s.mockEngineFactory.On("CreateEngine", mock.Anything).Return(mockEngine).Once()
 	s.mockShardManager.On("GetShard", &persistence.GetShardRequest{ShardID: int32(shardID)}).Return(
 		&persistence.GetShardResponse{
-			ShardInfo: &persistenceblobs.ShardInfo{
-				ShardID:             int32(shardID),
-				Owner:               s.hostInfo.Identity(),
-				RangeID:             currentRangeID,
-				ReplicationAckLevel: replicationAck,
-				TransferAckLevel:    currentClusterTransferAck,
-				TimerAckLevel:       currentClusterTimerAck,
-				ClusterTransferAckLevel: map[string]int64{
-					cluster.TestCurrentClusterName:     currentClusterTransferAck,
-					cluster.TestAlternativeClusterName: alternativeClusterTransferAck,
-				},
-				ClusterTimerAckLevel: map[string]*types.Timestamp{
-					cluster.TestCurrentClusterName:     currentClusterTimerAck,
-					cluster.TestAlternativeClusterName: alternativeClusterTimerAck,
-				},
-				ClusterReplicationLevel: map[string]int64{},
-			},
+			ShardInfo: s.newTestShardInfo(shardID, currentRangeID),
 		}, nil).Once()
+
+	acquiredShardInfo := s.newTestShardInfo(shardID, newRangeID)
+	acquiredShardInfo.StolenSinceRenew = 1
+	acquiredShardInfo.LeaseExpiry = s.expectedLeaseExpiry()
+	acquiredShardInfo.OwnershipHistory = s.expectedOwnershipHistory(newRangeID)
 	s.mockShardManager.On("UpdateShard", &persistence.UpdateShardRequest{
-		ShardInfo: &persistenceblobs.ShardInfo{
-			ShardID:             int32(shardID),
-			Owner:               s.hostInfo.Identity(),
-			RangeID:             newRangeID,
-			StolenSinceRenew:    1,
-			ReplicationAckLevel: replicationAck,
-			TransferAckLevel:    currentClusterTransferAck,
-			TimerAckLevel:       currentClusterTimerAck,
-			ClusterTransferAckLevel: map[string]int64{
-				cluster.TestCurrentClusterName:     currentClusterTransferAck,
-				cluster.TestAlternativeClusterName: alternativeClusterTransferAck,
-			},
-			ClusterTimerAckLevel: map[string]*types.Timestamp{
-				cluster.TestCurrentClusterName:     currentClusterTimerAck,
-				cluster.TestAlternativeClusterName: alternativeClusterTimerAck,
-			},
-			ClusterReplicationLevel: map[string]int64{},
-		},
+		ShardInfo:       acquiredShardInfo,
 		PreviousRangeID: currentRangeID,
 	}).Return(nil).Once()
 }
+
+func (s *shardControllerSuite) setupMocksForReleaseShard(shardID int, rangeID int64) {
+	releasedShardInfo := s.newTestShardInfo(shardID, rangeID)
+	releasedShardInfo.StolenSinceRenew = 1
+	releasedShardInfo.LeaseExpiry = s.expectedLeaseExpiry()
+	releasedShardInfo.Released = true
+	releasedShardInfo.OwnershipHistory = s.expectedOwnershipHistory(rangeID)
+	releasedShardInfo.OwnershipHistory[0].ReleasedAt, _ = types.TimestampProto(s.timeSource.Now())
+	s.mockShardManager.On("UpdateShard", &persistence.UpdateShardRequest{
+		ShardInfo:       releasedShardInfo,
+		PreviousRangeID: rangeID,
+	}).Return(nil).Once()
+}
+
+func (s *shardControllerSuite) setupMocksForAcquireShardFromPreviousOwner(
+	previousShardInfo *persistenceblobs.ShardInfo,
+	acquisitionType persistenceblobs.ShardOwnershipRecord_AcquisitionType,
+) {
+
+	s.mockHistoryEngine.EXPECT().Start().Times(1)
+	s.mockEngineFactory.On("CreateEngine", mock.Anything).Return(s.mockHistoryEngine).Once()
+	// when shard is initialized, it will use the 2 mock function below to initialize the "current" time of each cluster
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+
+	acquiredAt, _ := types.TimestampProto(s.timeSource.Now())
+	acquiredShardInfo := s.newTestShardInfo(int(previousShardInfo.ShardID), previousShardInfo.RangeID+1)
+	acquiredShardInfo.StolenSinceRenew = 1
+	acquiredShardInfo.LeaseExpiry = s.expectedLeaseExpiry()
+	acquiredShardInfo.OwnershipHistory = append(previousShardInfo.OwnershipHistory, &persistenceblobs.ShardOwnershipRecord{
+		Owner:           s.hostInfo.Identity(),
+		RangeID:         previousShardInfo.RangeID + 1,
+		AcquiredAt:      acquiredAt,
+		AcquisitionType: acquisitionType,
+		PreviousOwner:   previousShardInfo.Owner,
+	})
+	s.mockShardManager.On("UpdateShard", &persistence.UpdateShardRequest{
+		ShardInfo:       acquiredShardInfo,
+		PreviousRangeID: previousShardInfo.RangeID,
+	}).Return(nil).Once()
+}
+
+func (s *shardControllerSuite) newPreviousOwnerShardInfo(
+	previousOwner string,
+	leaseExpiry time.Time,
+	released bool,
+) *persistenceblobs.ShardInfo {
+
+	shardInfo := s.newTestShardInfo(0, 5)
+	shardInfo.Owner = previousOwner
+	shardInfo.LeaseExpiry, _ = types.TimestampProto(leaseExpiry)
+	shardInfo.Released = released
+	acquiredAt, _ := types.TimestampProto(s.timeSource.Now().Add(-time.Hour))
+	shardInfo.OwnershipHistory = []*persistenceblobs.ShardOwnershipRecord{
+		{
+			Owner:           previousOwner,
+			RangeID:         5,
+			AcquiredAt:      acquiredAt,
+			AcquisitionType: persistenceblobs.AcquisitionCreated,
+		},
+	}
+	if released {
+		shardInfo.OwnershipHistory[0].ReleasedAt, _ = types.TimestampProto(s.timeSource.Now())
+	}
+	return shardInfo
+}
+
+func (s *shardControllerSuite) newTestShardInfo(shardID int, rangeID int64) *persistenceblobs.ShardInfo {
+	replicationAck := int64(201)
+	currentClusterTransferAck := int64(210)
+	alternativeClusterTransferAck := int64(320)
+	currentClusterTimerAck, _ := types.TimestampProto(s.timeSource.Now().Add(-100 * time.Second))
+	alternativeClusterTimerAck, _ := types.TimestampProto(s.timeSource.Now().Add(-200 * time.Second))
+
+	return &persistenceblobs.ShardInfo{
+		ShardID:             int32(shardID),
+		Owner:               s.hostInfo.Identity(),
+		RangeID:             rangeID,
+		ReplicationAckLevel: replicationAck,
+		TransferAckLevel:    currentClusterTransferAck,
+		TimerAckLevel:       currentClusterTimerAck,
+		ClusterTransferAckLevel: map[string]int64{
+			cluster.TestCurrentClusterName:     currentClusterTransferAck,
+			cluster.TestAlternativeClusterName: alternativeClusterTransferAck,
+		},
+		ClusterTimerAckLevel: map[string]*types.Timestamp{
+			cluster.TestCurrentClusterName:     currentClusterTimerAck,
+			cluster.TestAlternativeClusterName: alternativeClusterTimerAck,
+		},
+		ClusterReplicationLevel: map[string]int64{},
+	}
+}
+
+func (s *shardControllerSuite) expectedLeaseExpiry() *types.Timestamp {
+	leaseExpiry, _ := types.TimestampProto(s.timeSource.Now().Add(s.config.ShardLeaseDuration()))
+	return leaseExpiry
+}
+
+func (s *shardControllerSuite) expectedOwnershipHistory(rangeID int64) []*persistenceblobs.ShardOwnershipRecord {
+	acquiredAt, _ := types.TimestampProto(s.timeSource.Now())
+	return []*persistenceblobs.ShardOwnershipRecord{
+		{
+			Owner:           s.hostInfo.Identity(),
+			RangeID:         rangeID,
+			AcquiredAt:      acquiredAt,
+			AcquisitionType: persistenceblobs.AcquisitionReacquired,
+			PreviousOwner:   s.hostInfo.Identity(),
+		},
+	}
+}
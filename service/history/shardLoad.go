@@ -0,0 +1,283 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/common/clock"
+	"github.com/temporalio/temporal/common/definition"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+)
+
+type (
+	// shardLoadTracker accounts the load of a shard over a sliding window, detects when the shard is hot
+	// and has the workflows issuing most of its requests throttled by the rate limiter of the shard.
+	// A nil tracker records nothing, which is what shards created by tests rely on.
+	shardLoadTracker struct {
+		shardID       int
+		config        *Config
+		timeSource    clock.TimeSource
+		metricsClient metrics.Client
+		logger        log.Logger
+		rateLimiter   *workflowRateLimiter
+
+		sync.Mutex
+		current   *shardLoadWindow
+		previous  *shardLoadWindow
+		throttled int
+	}
+
+	shardLoadWindow struct {
+		start               time.Time
+		requests            int64
+		apiRequests         map[int]int64                           // metrics scope -> requests
+		workflowRequests    map[definition.WorkflowIdentifier]int64 // domainID + workflowID -> requests
+		cacheHits           int64
+		cacheMisses         int64
+		persistenceRequests int64
+		persistenceLatency  time.Duration
+	}
+
+	workflowLoad struct {
+		key      definition.WorkflowIdentifier
+		requests int64
+	}
+)
+
+const (
+	// bounds the memory used by a shard to account the requests per workflow,
+	// workflows showing up once the limit is reached are only accounted in the shard totals
+	maxTrackedWorkflowsPerShard = 10000
+	// number of workflows reported by DescribeShardLoad
+	topWorkflowsReported = 10
+)
+
+func newShardLoadTracker(
+	shardID int,
+	config *Config,
+	timeSource clock.TimeSource,
+	metricsClient metrics.Client,
+	logger log.Logger,
+	rateLimiter *workflowRateLimiter,
+) *shardLoadTracker {
+
+	return &shardLoadTracker{
+		shardID:       shardID,
+		config:        config,
+		timeSource:    timeSource,
+		metricsClient: metricsClient,
+		logger:        logger,
+		rateLimiter:   rateLimiter,
+		current:       newShardLoadWindow(timeSource.Now()),
+	}
+}
+
+func newShardLoadWindow(start time.Time) *shardLoadWindow {
+	return &shardLoadWindow{
+		start:            start,
+		apiRequests:      make(map[int]int64),
+		workflowRequests: make(map[definition.WorkflowIdentifier]int64),
+	}
+}
+
+// recordRequest accounts a request of the given API for a workflow, across all its runs
+func (t *shardLoadTracker) recordRequest(scope int, domainID string, workflowID string) {
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.rotateLocked()
+	t.current.requests++
+	t.current.apiRequests[scope]++
+	key := newWorkflowRateLimiterKey(domainID, workflowID)
+	if _, ok := t.current.workflowRequests[key]; ok || len(t.current.workflowRequests) < maxTrackedWorkflowsPerShard {
+		t.current.workflowRequests[key]++
+	}
+}
+
+func (t *shardLoadTracker) recordCacheAccess(hit bool) {
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.rotateLocked()
+	if hit {
+		t.current.cacheHits++
+	} else {
+		t.current.cacheMisses++
+	}
+}
+
+func (t *shardLoadTracker) recordPersistenceRequest(latency time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.rotateLocked()
+	t.current.persistenceRequests++
+	t.current.persistenceLatency += latency
+}
+
+// describe returns the load of the shard over the last complete window,
+// or over the current window if the first one is not complete yet
+func (t *shardLoadTracker) describe() *adminservice.ShardLoad {
+	t.Lock()
+	defer t.Unlock()
+
+	t.rotateLocked()
+	window := t.previous
+	duration := t.config.ShardLoadWindow()
+	if window == nil {
+		window = t.current
+		duration = t.timeSource.Now().Sub(window.start)
+	}
+	seconds := duration.Seconds()
+	if seconds <= 0 {
+		seconds = 1
+	}
+
+	load := &adminservice.ShardLoad{
+		ShardID:             int32(t.shardID),
+		WindowSeconds:       int32(duration / time.Second),
+		RequestsPerSecond:   float64(window.requests) / seconds,
+		Hot:                 t.isHot(window, seconds),
+		CacheHits:           window.cacheHits,
+		CacheMisses:         window.cacheMisses,
+		PersistenceRequests: window.persistenceRequests,
+	}
+	if window.persistenceRequests > 0 {
+		load.PersistenceAverageLatencyMillis = int64(window.persistenceLatency/time.Millisecond) / window.persistenceRequests
+	}
+	for scope, requests := range window.apiRequests {
+		load.Apis = append(load.Apis, &adminservice.ShardAPILoad{
+			Api:               metrics.GetScopeOperation(metrics.History, scope),
+			Requests:          requests,
+			RequestsPerSecond: float64(requests) / seconds,
+		})
+	}
+	sort.Slice(load.Apis, func(i, j int) bool {
+		return load.Apis[i].Requests > load.Apis[j].Requests
+	})
+	for _, w := range window.topWorkflows(topWorkflowsReported) {
+		load.TopWorkflows = append(load.TopWorkflows, &adminservice.ShardWorkflowLoad{
+			DomainID:          w.key.DomainID,
+			WorkflowID:        w.key.WorkflowID,
+			Requests:          w.requests,
+			RequestsPerSecond: float64(w.requests) / seconds,
+			Share:             float64(w.requests) / float64(window.requests),
+			Throttled:         t.rateLimiter.isHot(w.key),
+		})
+	}
+	return load
+}
+
+// rotateLocked starts a new window once the current one is complete,
+// and re-evaluates the throttled workflows based on the window just completed
+func (t *shardLoadTracker) rotateLocked() {
+	now := t.timeSource.Now()
+	window := t.config.ShardLoadWindow()
+	elapsed := now.Sub(t.current.start)
+	if elapsed < window {
+		return
+	}
+
+	if elapsed < 2*window {
+		t.previous = t.current
+	} else {
+		// no request was received for a whole window
+		t.previous = newShardLoadWindow(now.Add(-window))
+	}
+	t.current = newShardLoadWindow(now)
+	t.updateThrottledWorkflowsLocked(window.Seconds())
+}
+
+func (t *shardLoadTracker) updateThrottledWorkflowsLocked(seconds float64) {
+	if !t.isHot(t.previous, seconds) {
+		if t.throttled > 0 {
+			t.logger.Info("Shard is not hot anymore, releasing throttled workflows.", tag.Counter(t.throttled))
+			t.rateLimiter.setHotWorkflows(nil)
+			t.throttled = 0
+		}
+		return
+	}
+
+	t.metricsClient.IncCounter(metrics.ShardInfoScope, metrics.HotShardDetectedCounter)
+	t.logger.Warn("Hot shard detected.", tag.Number(t.previous.requests))
+
+	var throttled []definition.WorkflowIdentifier
+	if t.config.EnableHotWorkflowThrottling() {
+		shareThreshold := t.config.HotWorkflowShareThreshold()
+		for _, w := range t.previous.topWorkflows(t.config.HotWorkflowMaxThrottled()) {
+			if float64(w.requests) < shareThreshold*float64(t.previous.requests) {
+				break
+			}
+			if !t.rateLimiter.isHot(w.key) {
+				t.metricsClient.IncCounter(metrics.ShardInfoScope, metrics.HotWorkflowThrottledCounter)
+				t.logger.Warn("Throttling hot workflow.",
+					tag.WorkflowDomainID(w.key.DomainID), tag.WorkflowID(w.key.WorkflowID), tag.Number(w.requests))
+			}
+			throttled = append(throttled, w.key)
+		}
+	}
+	t.rateLimiter.setHotWorkflows(throttled)
+	t.throttled = len(throttled)
+}
+
+func (t *shardLoadTracker) isHot(window *shardLoadWindow, seconds float64) bool {
+	threshold := t.config.HotShardRPSThreshold()
+	return threshold > 0 && float64(window.requests)/seconds >= float64(threshold)
+}
+
+// topWorkflows returns the workflows with the most requests in the window, busiest first
+func (w *shardLoadWindow) topWorkflows(limit int) []workflowLoad {
+	loads := make([]workflowLoad, 0, len(w.workflowRequests))
+	for key, requests := range w.workflowRequests {
+		loads = append(loads, workflowLoad{key: key, requests: requests})
+	}
+	sort.Slice(loads, func(i, j int) bool {
+		if loads[i].requests == loads[j].requests {
+			if loads[i].key.DomainID == loads[j].key.DomainID {
+				return loads[i].key.WorkflowID < loads[j].key.WorkflowID
+			}
+			return loads[i].key.DomainID < loads[j].key.DomainID
+		}
+		return loads[i].requests > loads[j].requests
+	})
+	if len(loads) > limit {
+		loads = loads[:limit]
+	}
+	return loads
+}
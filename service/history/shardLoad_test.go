@@ -0,0 +1,208 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+
+	"github.com/temporalio/temporal/common/clock"
+	"github.com/temporalio/temporal/common/log/loggerimpl"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+)
+
+type (
+	shardLoadTrackerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		timeSource *clock.EventTimeSource
+		config     *Config
+		limiter    *workflowRateLimiter
+		tracker    *shardLoadTracker
+	}
+)
+
+func TestShardLoadTrackerSuite(t *testing.T) {
+	s := new(shardLoadTrackerSuite)
+	suite.Run(t, s)
+}
+
+func (s *shardLoadTrackerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.timeSource = clock.NewEventTimeSource().Update(time.Now())
+	s.config = NewDynamicConfigForTest()
+	s.config.ShardLoadWindow = dynamicconfig.GetDurationPropertyFn(10 * time.Second)
+	s.config.HotShardRPSThreshold = dynamicconfig.GetIntPropertyFn(10)
+	s.config.EnableHotWorkflowThrottling = dynamicconfig.GetBoolPropertyFn(true)
+	s.config.HotWorkflowShareThreshold = dynamicconfig.GetFloatPropertyFn(0.5)
+	s.config.HotWorkflowMaxThrottled = dynamicconfig.GetIntPropertyFn(1)
+	s.config.HotWorkflowThrottledRPS = dynamicconfig.GetIntPropertyFn(1)
+	metricsClient := metrics.NewClient(tally.NoopScope, metrics.History)
+	s.limiter = newWorkflowRateLimiter(s.config, metricsClient)
+	s.tracker = newShardLoadTracker(
+		1,
+		s.config,
+		s.timeSource,
+		metricsClient,
+		loggerimpl.NewDevelopmentForTest(s.Suite),
+		s.limiter,
+	)
+}
+
+func (s *shardLoadTrackerSuite) TestDescribe_CurrentWindow() {
+	s.tracker.recordRequest(metrics.HistorySignalWorkflowExecutionScope, testDomainID, "wid1")
+	s.tracker.recordRequest(metrics.HistorySignalWorkflowExecutionScope, testDomainID, "wid1")
+	s.tracker.recordRequest(metrics.HistoryStartWorkflowExecutionScope, testDomainID, "wid2")
+	s.tracker.recordCacheAccess(true)
+	s.tracker.recordCacheAccess(false)
+	s.tracker.recordPersistenceRequest(10 * time.Millisecond)
+	s.tracker.recordPersistenceRequest(30 * time.Millisecond)
+	s.timeSource.Update(s.timeSource.Now().Add(3 * time.Second))
+
+	load := s.tracker.describe()
+	s.Equal(int32(1), load.ShardID)
+	s.Equal(int32(3), load.WindowSeconds)
+	s.Equal(float64(1), load.RequestsPerSecond)
+	s.False(load.Hot)
+	s.Equal(int64(1), load.CacheHits)
+	s.Equal(int64(1), load.CacheMisses)
+	s.Equal(int64(2), load.PersistenceRequests)
+	s.Equal(int64(20), load.PersistenceAverageLatencyMillis)
+
+	s.Len(load.Apis, 2)
+	s.Equal("SignalWorkflowExecution", load.Apis[0].Api)
+	s.Equal(int64(2), load.Apis[0].Requests)
+	s.Equal("StartWorkflowExecution", load.Apis[1].Api)
+	s.Equal(int64(1), load.Apis[1].Requests)
+
+	s.Len(load.TopWorkflows, 2)
+	s.Equal(testDomainID, load.TopWorkflows[0].DomainID)
+	s.Equal("wid1", load.TopWorkflows[0].WorkflowID)
+	s.Equal(int64(2), load.TopWorkflows[0].Requests)
+	s.False(load.TopWorkflows[0].Throttled)
+	s.Equal("wid2", load.TopWorkflows[1].WorkflowID)
+}
+
+func (s *shardLoadTrackerSuite) TestHotShard_ThrottlesTopWorkflow() {
+	s.recordRequests("hot-wid", 150)
+	s.recordRequests("wid", 10)
+	s.timeSource.Update(s.timeSource.Now().Add(10 * time.Second))
+
+	load := s.tracker.describe()
+	s.True(load.Hot)
+	s.Equal(int32(10), load.WindowSeconds)
+	s.Equal(float64(16), load.RequestsPerSecond)
+	s.Equal("hot-wid", load.TopWorkflows[0].WorkflowID)
+	s.True(load.TopWorkflows[0].Throttled)
+	s.False(load.TopWorkflows[1].Throttled)
+
+	// the throttled workflow only gets the burst of its limiter
+	s.NoError(s.limiter.allowRequest(testDomainID, "hot-wid"))
+	s.Equal(ErrWorkflowRateLimitExceeded, s.limiter.allowRequest(testDomainID, "hot-wid"))
+	s.NoError(s.limiter.allowRequest(testDomainID, "wid"))
+}
+
+func (s *shardLoadTrackerSuite) TestHotShard_ThrottlingDisabled() {
+	s.config.EnableHotWorkflowThrottling = dynamicconfig.GetBoolPropertyFn(false)
+	s.recordRequests("hot-wid", 150)
+	s.timeSource.Update(s.timeSource.Now().Add(10 * time.Second))
+
+	load := s.tracker.describe()
+	s.True(load.Hot)
+	s.NoError(s.limiter.allowRequest(testDomainID, "hot-wid"))
+	s.NoError(s.limiter.allowRequest(testDomainID, "hot-wid"))
+	s.False(load.TopWorkflows[0].Throttled)
+}
+
+func (s *shardLoadTrackerSuite) TestHotShard_ReleasesWorkflowOnceCooledDown() {
+	s.recordRequests("hot-wid", 150)
+	s.timeSource.Update(s.timeSource.Now().Add(10 * time.Second))
+	s.True(s.tracker.describe().Hot)
+	s.NoError(s.limiter.allowRequest(testDomainID, "hot-wid"))
+	s.Equal(ErrWorkflowRateLimitExceeded, s.limiter.allowRequest(testDomainID, "hot-wid"))
+
+	s.timeSource.Update(s.timeSource.Now().Add(10 * time.Second))
+	load := s.tracker.describe()
+	s.False(load.Hot)
+	s.NoError(s.limiter.allowRequest(testDomainID, "hot-wid"))
+	s.NoError(s.limiter.allowRequest(testDomainID, "hot-wid"))
+}
+
+func (s *shardLoadTrackerSuite) TestHotShard_NoWorkflowAboveShareThreshold() {
+	for i := 0; i < 150; i++ {
+		s.tracker.recordRequest(metrics.HistorySignalWorkflowExecutionScope, testDomainID, fmt.Sprintf("wid-%v", i%3))
+	}
+	s.timeSource.Update(s.timeSource.Now().Add(10 * time.Second))
+
+	load := s.tracker.describe()
+	s.True(load.Hot)
+	for _, w := range load.TopWorkflows {
+		s.False(w.Throttled)
+	}
+}
+
+func (s *shardLoadTrackerSuite) TestHotShard_WorkflowsKeyedByDomain() {
+	s.recordRequests("hot-wid", 150)
+	for i := 0; i < 10; i++ {
+		s.tracker.recordRequest(metrics.HistorySignalWorkflowExecutionScope, "other-domain-id", "hot-wid")
+	}
+	s.timeSource.Update(s.timeSource.Now().Add(10 * time.Second))
+
+	load := s.tracker.describe()
+	s.Len(load.TopWorkflows, 2)
+	s.Equal(testDomainID, load.TopWorkflows[0].DomainID)
+	s.True(load.TopWorkflows[0].Throttled)
+	s.Equal("other-domain-id", load.TopWorkflows[1].DomainID)
+	s.False(load.TopWorkflows[1].Throttled)
+	s.NoError(s.limiter.allowRequest("other-domain-id", "hot-wid"))
+	s.NoError(s.limiter.allowRequest("other-domain-id", "hot-wid"))
+}
+
+func (s *shardLoadTrackerSuite) TestIdleWindow() {
+	s.recordRequests("hot-wid", 150)
+	s.timeSource.Update(s.timeSource.Now().Add(25 * time.Second))
+
+	load := s.tracker.describe()
+	s.False(load.Hot)
+	s.Equal(float64(0), load.RequestsPerSecond)
+	s.Empty(load.TopWorkflows)
+}
+
+func (s *shardLoadTrackerSuite) TestNilTracker() {
+	var tracker *shardLoadTracker
+	tracker.recordRequest(metrics.HistorySignalWorkflowExecutionScope, testDomainID, "wid")
+	tracker.recordCacheAccess(true)
+	tracker.recordPersistenceRequest(time.Millisecond)
+}
+
+func (s *shardLoadTrackerSuite) recordRequests(workflowID string, count int) {
+	for i := 0; i < count; i++ {
+		s.tracker.recordRequest(metrics.HistorySignalWorkflowExecutionScope, testDomainID, workflowID)
+	}
+}
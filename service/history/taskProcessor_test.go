@@ -99,7 +99,7 @@ func (s *taskProcessorSuite) SetupTest() {
 		metricsClient: s.mockShard.GetMetricsClient(),
 	}
 	options := taskProcessorOptions{
-		queueSize:   s.mockShard.GetConfig().TimerTaskBatchSize() * s.mockShard.GetConfig().TimerTaskWorkerCount(),
+		queueSize:   s.mockShard.GetConfig().TimerTaskBatchSize(int32(s.mockShard.GetShardID())) * s.mockShard.GetConfig().TimerTaskWorkerCount(),
 		workerCount: s.mockShard.GetConfig().TimerTaskWorkerCount(),
 	}
 	s.taskProcessor = newTaskProcessor(options, s.mockShard, h.historyCache, s.logger)
@@ -217,7 +217,7 @@ func (t *timerQueueAckMgrImpl) readTimerTasks() ([]*persistenceblobs.TimerTaskIn
 	morePage := false
 	var err error
 	if minQueryLevel.Before(maxQueryLevel) {
-		tasks, pageToken, err = t.getTimerTasks(minQueryLevel, maxQueryLevel, t.config.TimerTaskBatchSize(), pageToken)
+		tasks, pageToken, err = t.getTimerTasks(minQueryLevel, maxQueryLevel, t.config.TimerTaskBatchSize(int32(t.shard.GetShardID())), pageToken)
 		if err != nil {
 			return nil, nil, false, err
 		}
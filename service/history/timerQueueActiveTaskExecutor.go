@@ -429,6 +429,9 @@ func (t *timerQueueActiveTaskExecutor) executeActivityRetryTimerTask(
 		Name: activityInfo.TaskList,
 	}
 	scheduleToStartTimeout := activityInfo.ScheduleToStartTimeout
+	priority := activityInfo.Priority
+	fairnessKey := activityInfo.FairnessKey
+	workflowType := mutableState.GetExecutionInfo().WorkflowTypeName
 
 	release(nil) // release earlier as we don't need the lock anymore
 
@@ -439,6 +442,9 @@ func (t *timerQueueActiveTaskExecutor) executeActivityRetryTimerTask(
 		TaskList:                      taskList,
 		ScheduleId:                    scheduledID,
 		ScheduleToStartTimeoutSeconds: scheduleToStartTimeout,
+		Priority:                      priority,
+		FairnessKey:                   fairnessKey,
+		WorkflowType:                  workflowType,
 	})
 
 	return retError
@@ -1108,6 +1108,7 @@ func (s *timerQueueActiveTaskExecutorSuite) TestActivityRetryTimer_Fire() {
 			},
 			ScheduleId:                    activityInfo.ScheduleID,
 			ScheduleToStartTimeoutSeconds: activityInfo.ScheduleToStartTimeout,
+			WorkflowType:                  mutableState.GetExecutionInfo().WorkflowTypeName,
 		},
 	).Return(&matchingservice.AddActivityTaskResponse{}, nil).Times(1)
 
@@ -26,6 +26,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -69,6 +70,7 @@ type (
 		isStarted              int32
 		isStopped              int32
 		shutdownChan           chan struct{}
+		shutdownWG             sync.WaitGroup
 		activeTimerProcessor   *timerQueueActiveProcessorImpl
 		standbyTimerProcessors map[string]*timerQueueStandbyProcessorImpl
 	}
@@ -151,6 +153,7 @@ func (t *timerQueueProcessorImpl) Start() {
 			standbyTimerProcessor.Start()
 		}
 	}
+	t.shutdownWG.Add(1)
 	go t.completeTimersLoop()
 }
 
@@ -165,6 +168,11 @@ func (t *timerQueueProcessorImpl) Stop() {
 		}
 	}
 	close(t.shutdownChan)
+
+	// wait for the final ack level update so that the shard can be handed off with it
+	if success := common.AwaitWaitGroup(&t.shutdownWG, time.Minute); !success {
+		t.logger.Warn("", tag.LifeCycleStopTimedout)
+	}
 }
 
 // NotifyNewTimers - Notify the processor about the new active / standby timer arrival.
@@ -246,6 +254,8 @@ func (t *timerQueueProcessorImpl) UnlockTaskProcessing() {
 }
 
 func (t *timerQueueProcessorImpl) completeTimersLoop() {
+	defer t.shutdownWG.Done()
+
 	timer := time.NewTimer(t.config.TimerProcessorCompleteTimerInterval())
 	defer timer.Stop()
 	for {
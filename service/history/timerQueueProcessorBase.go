@@ -91,7 +91,7 @@ func newTimerQueueProcessorBase(
 	log := logger.WithTags(tag.ComponentTimerQueue)
 	options := taskProcessorOptions{
 		workerCount: shard.GetConfig().TimerTaskWorkerCount(),
-		queueSize:   shard.GetConfig().TimerTaskWorkerCount() * shard.GetConfig().TimerTaskBatchSize(),
+		queueSize:   shard.GetConfig().TimerTaskWorkerCount() * shard.GetConfig().TimerTaskBatchSize(int32(shard.GetShardID())),
 	}
 	taskProcessor := newTaskProcessor(options, shard, historyService.historyCache, log)
 	base := &timerQueueProcessorBase{
@@ -62,7 +62,7 @@ func newTransferQueueActiveProcessor(
 
 	config := shard.GetConfig()
 	options := &QueueProcessorOptions{
-		BatchSize:                          config.TransferTaskBatchSize,
+		BatchSize:                          shardBatchSize(config.TransferTaskBatchSize, shard.GetShardID()),
 		WorkerCount:                        config.TransferTaskWorkerCount,
 		MaxPollRPS:                         config.TransferProcessorMaxPollRPS,
 		MaxPollInterval:                    config.TransferProcessorMaxPollInterval,
@@ -139,7 +139,7 @@ func newTransferQueueFailoverProcessor(
 
 	config := shard.GetConfig()
 	options := &QueueProcessorOptions{
-		BatchSize:                          config.TransferTaskBatchSize,
+		BatchSize:                          shardBatchSize(config.TransferTaskBatchSize, shard.GetShardID()),
 		WorkerCount:                        config.TransferTaskWorkerCount,
 		MaxPollRPS:                         config.TransferProcessorFailoverMaxPollRPS,
 		MaxPollInterval:                    config.TransferProcessorMaxPollInterval,
@@ -149,10 +149,13 @@ func (t *transferQueueActiveTaskExecutor) processActivityTask(
 	}
 
 	timeout := common.MinInt32(ai.ScheduleToStartTimeout, common.MaxTaskTimeout)
+	priority := ai.Priority
+	fairnessKey := ai.FairnessKey
+	workflowType := mutableState.GetExecutionInfo().WorkflowTypeName
 	// release the context lock since we no longer need mutable state builder and
 	// the rest of logic is making RPC call, which takes time.
 	release(nil)
-	return t.pushActivity(task, timeout)
+	return t.pushActivity(task, timeout, priority, fairnessKey, workflowType)
 }
 
 func (t *transferQueueActiveTaskExecutor) processDecisionTask(
@@ -203,11 +206,15 @@ func (t *transferQueueActiveTaskExecutor) processDecisionTask(
 		taskList.Kind = enums.TaskListKindSticky
 		decisionTimeout = executionInfo.StickyScheduleToStartTimeout
 	}
+	priority := executionInfo.TaskPriority
+	fairnessKey := executionInfo.TaskFairnessKey
+	workflowType := executionInfo.WorkflowTypeName
+	pinnedBuildID := executionInfo.WorkerBuildID
 
 	// release the context lock since we no longer need mutable state builder and
 	// the rest of logic is making RPC call, which takes time.
 	release(nil)
-	return t.pushDecision(task, taskList, decisionTimeout)
+	return t.pushDecision(task, taskList, decisionTimeout, priority, fairnessKey, workflowType, pinnedBuildID)
 }
 
 func (t *transferQueueActiveTaskExecutor) processCloseExecution(
@@ -261,7 +261,7 @@ func (s *transferQueueActiveTaskExecutorSuite) TestProcessActivityTask_Success()
 
 	persistenceMutableState := s.createPersistenceMutableState(mutableState, event.GetEventId(), event.GetVersion())
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(&persistence.GetWorkflowExecutionResponse{State: persistenceMutableState}, nil)
-	s.mockMatchingClient.EXPECT().AddActivityTask(gomock.Any(), s.createAddActivityTaskRequest(transferTask, ai)).Return(&matchingservice.AddActivityTaskResponse{}, nil).Times(1)
+	s.mockMatchingClient.EXPECT().AddActivityTask(gomock.Any(), s.createAddActivityTaskRequest(transferTask, ai, mutableState)).Return(&matchingservice.AddActivityTaskResponse{}, nil).Times(1)
 
 	err = s.transferQueueActiveTaskExecutor.execute(transferTask, true)
 	s.Nil(err)
@@ -1856,6 +1856,7 @@ func (s *transferQueueActiveTaskExecutorSuite) TestCopySearchAttributes() {
 func (s *transferQueueActiveTaskExecutorSuite) createAddActivityTaskRequest(
 	task *persistenceblobs.TransferTaskInfo,
 	ai *persistence.ActivityInfo,
+	mutableState mutableState,
 ) *matchingservice.AddActivityTaskRequest {
 	return &matchingservice.AddActivityTaskRequest{
 		DomainUUID:       primitives.UUID(task.TargetDomainID).String(),
@@ -1867,6 +1868,7 @@ func (s *transferQueueActiveTaskExecutorSuite) createAddActivityTaskRequest(
 		TaskList:                      &commonproto.TaskList{Name: task.TaskList},
 		ScheduleId:                    task.ScheduleID,
 		ScheduleToStartTimeoutSeconds: ai.ScheduleToStartTimeout,
+		WorkflowType:                  mutableState.GetExecutionInfo().WorkflowTypeName,
 	}
 }
 
@@ -1893,6 +1895,8 @@ func (s *transferQueueActiveTaskExecutorSuite) createAddDecisionTaskRequest(
 		TaskList:                      taskList,
 		ScheduleId:                    task.ScheduleID,
 		ScheduleToStartTimeoutSeconds: timeout,
+		WorkflowType:                  executionInfo.WorkflowTypeName,
+		PinnedBuildId:                 executionInfo.WorkerBuildID,
 	}
 }
 
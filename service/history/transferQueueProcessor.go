@@ -26,6 +26,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -71,6 +72,7 @@ type (
 		isStarted             int32
 		isStopped             int32
 		shutdownChan          chan struct{}
+		shutdownWG            sync.WaitGroup
 		activeTaskProcessor   *transferQueueActiveProcessorImpl
 		standbyTaskProcessors map[string]*transferQueueStandbyProcessorImpl
 	}
@@ -167,6 +169,7 @@ func (t *transferQueueProcessorImpl) Start() {
 		}
 	}
 
+	t.shutdownWG.Add(1)
 	go t.completeTransferLoop()
 }
 
@@ -181,6 +184,11 @@ func (t *transferQueueProcessorImpl) Stop() {
 		}
 	}
 	close(t.shutdownChan)
+
+	// wait for the final ack level update so that the shard can be handed off with it
+	if success := common.AwaitWaitGroup(&t.shutdownWG, time.Minute); !success {
+		t.logger.Warn("", tag.LifeCycleStopTimedout)
+	}
 }
 
 // NotifyNewTask - Notify the processor about the new active / standby transfer task arrival.
@@ -267,6 +275,8 @@ func (t *transferQueueProcessorImpl) UnlockTaskPrrocessing() {
 }
 
 func (t *transferQueueProcessorImpl) completeTransferLoop() {
+	defer t.shutdownWG.Done()
+
 	timer := time.NewTimer(t.config.TransferProcessorCompleteTransferInterval())
 	defer timer.Stop()
 
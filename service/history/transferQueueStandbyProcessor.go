@@ -61,7 +61,7 @@ func newTransferQueueStandbyProcessor(
 
 	config := shard.GetConfig()
 	options := &QueueProcessorOptions{
-		BatchSize:                          config.TransferTaskBatchSize,
+		BatchSize:                          shardBatchSize(config.TransferTaskBatchSize, shard.GetShardID()),
 		WorkerCount:                        config.TransferTaskWorkerCount,
 		MaxPollRPS:                         config.TransferProcessorMaxPollRPS,
 		MaxPollInterval:                    config.TransferProcessorMaxPollInterval,
@@ -132,6 +132,9 @@ func (t *transferQueueStandbyTaskExecutor) processActivityTask(
 		if activityInfo.StartedID == common.EmptyEventID {
 			return newPushActivityToMatchingInfo(
 				activityInfo.ScheduleToStartTimeout,
+				activityInfo.Priority,
+				activityInfo.FairnessKey,
+				mutableState.GetExecutionInfo().WorkflowTypeName,
 			), nil
 		}
 
@@ -178,6 +181,10 @@ func (t *transferQueueStandbyTaskExecutor) processDecisionTask(
 			return newPushDecisionToMatchingInfo(
 				decisionTimeout,
 				commonproto.TaskList{Name: transferTask.TaskList},
+				executionInfo.TaskPriority,
+				executionInfo.TaskFairnessKey,
+				executionInfo.WorkflowTypeName,
+				executionInfo.WorkerBuildID,
 			), nil
 		}
 
@@ -521,6 +528,9 @@ func (t *transferQueueStandbyTaskExecutor) pushActivity(
 	return t.transferQueueTaskExecutorBase.pushActivity(
 		task.(*persistenceblobs.TransferTaskInfo),
 		timeout,
+		pushActivityInfo.priority,
+		pushActivityInfo.fairnessKey,
+		pushActivityInfo.workflowType,
 	)
 }
 
@@ -540,6 +550,10 @@ func (t *transferQueueStandbyTaskExecutor) pushDecision(
 		task.(*persistenceblobs.TransferTaskInfo),
 		&pushDecisionInfo.tasklist,
 		timeout,
+		pushDecisionInfo.priority,
+		pushDecisionInfo.fairnessKey,
+		pushDecisionInfo.workflowType,
+		pushDecisionInfo.pinnedBuildID,
 	)
 }
 
@@ -90,6 +90,9 @@ func (t *transferQueueTaskExecutorBase) getDomainIDAndWorkflowExecution(
 func (t *transferQueueTaskExecutorBase) pushActivity(
 	task *persistenceblobs.TransferTaskInfo,
 	activityScheduleToStartTimeout int32,
+	priority int32,
+	fairnessKey string,
+	workflowType string,
 ) error {
 
 	ctx, cancel := context.WithTimeout(context.Background(), transferActiveTaskDefaultTimeout)
@@ -109,6 +112,9 @@ func (t *transferQueueTaskExecutorBase) pushActivity(
 		TaskList:                      &commonproto.TaskList{Name: task.TaskList},
 		ScheduleId:                    task.ScheduleID,
 		ScheduleToStartTimeoutSeconds: activityScheduleToStartTimeout,
+		Priority:                      priority,
+		FairnessKey:                   fairnessKey,
+		WorkflowType:                  workflowType,
 	})
 
 	return err
@@ -118,6 +124,10 @@ func (t *transferQueueTaskExecutorBase) pushDecision(
 	task *persistenceblobs.TransferTaskInfo,
 	tasklist *commonproto.TaskList,
 	decisionScheduleToStartTimeout int32,
+	priority int32,
+	fairnessKey string,
+	workflowType string,
+	pinnedBuildID string,
 ) error {
 
 	ctx, cancel := context.WithTimeout(context.Background(), transferActiveTaskDefaultTimeout)
@@ -136,6 +146,10 @@ func (t *transferQueueTaskExecutorBase) pushDecision(
 		TaskList:                      tasklist,
 		ScheduleId:                    task.ScheduleID,
 		ScheduleToStartTimeoutSeconds: decisionScheduleToStartTimeout,
+		Priority:                      priority,
+		FairnessKey:                   fairnessKey,
+		WorkflowType:                  workflowType,
+		PinnedBuildId:                 pinnedBuildID,
 	})
 	return err
 }
@@ -0,0 +1,281 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"sync"
+
+	"github.com/pborman/uuid"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/serviceerror"
+)
+
+const (
+	// updateAcceptedMarkerName is the name of the marker recorded when the workflow accepts an update,
+	// its details are the update input
+	updateAcceptedMarkerName = "temporal-update-accepted"
+	// updateRejectedMarkerName is the name of the marker recorded when the workflow rejects an update,
+	// its details are the rejection reason
+	updateRejectedMarkerName = "temporal-update-rejected"
+	// updateCompletedMarkerName is the name of the marker the workflow records to complete an accepted update,
+	// its details are the update result
+	updateCompletedMarkerName = "temporal-update-completed"
+
+	// updateIDHeaderField identifies the update of an update marker
+	updateIDHeaderField = "update-id"
+	// updateNameHeaderField is the name of the update of an update marker
+	updateNameHeaderField = "update-name"
+	// updateFailureHeaderField is set on an update completed marker when the accepted update failed
+	updateFailureHeaderField = "update-failure"
+)
+
+var (
+	errUpdateNotExists          = serviceerror.NewInternal("update does not exist")
+	errUpdateNotInTerminalState = serviceerror.NewInternal("update not in terminal state")
+)
+
+type (
+	// updateRegistry tracks the updates of a workflow execution which are waiting to be handled by the workflow.
+	// An update is buffered until it is delivered on a decision task, it is then dispatched until the decision task
+	// completes, at which point it is either accepted, rejected or buffered again for the next decision task.
+	// An accepted update is completed once the workflow records its update completed marker.
+	updateRegistry interface {
+		hasBufferedUpdate() bool
+		getBufferedIDs() []string
+		getDispatchedIDs() []string
+		getAcceptedIDs() []string
+		getPendingCount() int
+		isAccepted(string) bool
+
+		getUpdateTermCh(string) (<-chan struct{}, error)
+		getUpdateInput(string) (*commonproto.WorkflowQuery, error)
+		getOutcome(string) (*updateOutcome, error)
+
+		bufferUpdate(updateInput *commonproto.WorkflowQuery) (string, <-chan struct{})
+		dispatchUpdates() map[string]*commonproto.WorkflowQuery
+		rebufferUpdate(string)
+		acceptUpdate(string) error
+		completeUpdate(string, *updateOutcome) error
+		removeUpdate(id string)
+	}
+
+	// updateOutcome is the result of an update, failure is set when the update is rejected or cannot be handled
+	updateOutcome struct {
+		result  []byte
+		failure error
+	}
+
+	update struct {
+		input   *commonproto.WorkflowQuery
+		termCh  chan struct{}
+		outcome *updateOutcome
+	}
+
+	updateRegistryImpl struct {
+		sync.RWMutex
+
+		buffered   map[string]*update
+		dispatched map[string]*update
+		accepted   map[string]*update
+		completed  map[string]*update
+	}
+)
+
+func newUpdateRegistry() updateRegistry {
+	return &updateRegistryImpl{
+		buffered:   make(map[string]*update),
+		dispatched: make(map[string]*update),
+		accepted:   make(map[string]*update),
+		completed:  make(map[string]*update),
+	}
+}
+
+func (r *updateRegistryImpl) hasBufferedUpdate() bool {
+	r.RLock()
+	defer r.RUnlock()
+	return len(r.buffered) > 0
+}
+
+func (r *updateRegistryImpl) getBufferedIDs() []string {
+	r.RLock()
+	defer r.RUnlock()
+	return r.getIDs(r.buffered)
+}
+
+func (r *updateRegistryImpl) getDispatchedIDs() []string {
+	r.RLock()
+	defer r.RUnlock()
+	return r.getIDs(r.dispatched)
+}
+
+func (r *updateRegistryImpl) getAcceptedIDs() []string {
+	r.RLock()
+	defer r.RUnlock()
+	return r.getIDs(r.accepted)
+}
+
+func (r *updateRegistryImpl) getPendingCount() int {
+	r.RLock()
+	defer r.RUnlock()
+	return len(r.buffered) + len(r.dispatched) + len(r.accepted)
+}
+
+func (r *updateRegistryImpl) isAccepted(id string) bool {
+	r.RLock()
+	defer r.RUnlock()
+	_, ok := r.accepted[id]
+	return ok
+}
+
+func (r *updateRegistryImpl) getUpdateTermCh(id string) (<-chan struct{}, error) {
+	r.RLock()
+	defer r.RUnlock()
+	u, err := r.getUpdateNoLock(id)
+	if err != nil {
+		return nil, err
+	}
+	return u.termCh, nil
+}
+
+func (r *updateRegistryImpl) getUpdateInput(id string) (*commonproto.WorkflowQuery, error) {
+	r.RLock()
+	defer r.RUnlock()
+	u, err := r.getUpdateNoLock(id)
+	if err != nil {
+		return nil, err
+	}
+	return u.input, nil
+}
+
+func (r *updateRegistryImpl) getOutcome(id string) (*updateOutcome, error) {
+	r.RLock()
+	defer r.RUnlock()
+	u, ok := r.completed[id]
+	if !ok {
+		if _, err := r.getUpdateNoLock(id); err != nil {
+			return nil, err
+		}
+		return nil, errUpdateNotInTerminalState
+	}
+	return u.outcome, nil
+}
+
+func (r *updateRegistryImpl) bufferUpdate(updateInput *commonproto.WorkflowQuery) (string, <-chan struct{}) {
+	r.Lock()
+	defer r.Unlock()
+	id := uuid.New()
+	u := &update{
+		input:  updateInput,
+		termCh: make(chan struct{}),
+	}
+	r.buffered[id] = u
+	return id, u.termCh
+}
+
+// dispatchUpdates marks all the pending updates as dispatched and returns their inputs.
+// Updates which were dispatched on a decision task that did not complete are dispatched again.
+func (r *updateRegistryImpl) dispatchUpdates() map[string]*commonproto.WorkflowQuery {
+	r.Lock()
+	defer r.Unlock()
+	for id, u := range r.buffered {
+		r.dispatched[id] = u
+		delete(r.buffered, id)
+	}
+	result := make(map[string]*commonproto.WorkflowQuery, len(r.dispatched))
+	for id, u := range r.dispatched {
+		result[id] = u.input
+	}
+	return result
+}
+
+func (r *updateRegistryImpl) rebufferUpdate(id string) {
+	r.Lock()
+	defer r.Unlock()
+	if u, ok := r.dispatched[id]; ok {
+		delete(r.dispatched, id)
+		r.buffered[id] = u
+	}
+}
+
+// acceptUpdate marks a dispatched update as accepted, it then waits for the workflow to complete it
+func (r *updateRegistryImpl) acceptUpdate(id string) error {
+	r.Lock()
+	defer r.Unlock()
+	u, ok := r.dispatched[id]
+	if !ok {
+		return errUpdateNotExists
+	}
+	delete(r.dispatched, id)
+	r.accepted[id] = u
+	return nil
+}
+
+func (r *updateRegistryImpl) completeUpdate(id string, outcome *updateOutcome) error {
+	r.Lock()
+	defer r.Unlock()
+	u, err := r.getUpdateNoLock(id)
+	if err != nil {
+		return err
+	}
+	if _, ok := r.completed[id]; ok {
+		return errUpdateNotExists
+	}
+	delete(r.buffered, id)
+	delete(r.dispatched, id)
+	delete(r.accepted, id)
+	u.outcome = outcome
+	r.completed[id] = u
+	close(u.termCh)
+	return nil
+}
+
+func (r *updateRegistryImpl) removeUpdate(id string) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.buffered, id)
+	delete(r.dispatched, id)
+	delete(r.accepted, id)
+	delete(r.completed, id)
+}
+
+func (r *updateRegistryImpl) getUpdateNoLock(id string) (*update, error) {
+	if u, ok := r.buffered[id]; ok {
+		return u, nil
+	}
+	if u, ok := r.dispatched[id]; ok {
+		return u, nil
+	}
+	if u, ok := r.accepted[id]; ok {
+		return u, nil
+	}
+	if u, ok := r.completed[id]; ok {
+		return u, nil
+	}
+	return nil, errUpdateNotExists
+}
+
+func (r *updateRegistryImpl) getIDs(m map[string]*update) []string {
+	result := make([]string, 0, len(m))
+	for id := range m {
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/serviceerror"
+)
+
+type UpdateRegistrySuite struct {
+	suite.Suite
+	*require.Assertions
+}
+
+func TestUpdateRegistrySuite(t *testing.T) {
+	suite.Run(t, new(UpdateRegistrySuite))
+}
+
+func (s *UpdateRegistrySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *UpdateRegistrySuite) TestDispatchAndComplete() {
+	ur := newUpdateRegistry()
+	input := &commonproto.WorkflowQuery{QueryType: "set-value", QueryArgs: []byte{1}}
+	id1, termCh1 := ur.bufferUpdate(input)
+	id2, termCh2 := ur.bufferUpdate(input)
+	s.True(ur.hasBufferedUpdate())
+	s.Equal(2, ur.getPendingCount())
+
+	dispatched := ur.dispatchUpdates()
+	s.Len(dispatched, 2)
+	s.Equal(input, dispatched[id1])
+	s.False(ur.hasBufferedUpdate())
+	s.ElementsMatch([]string{id1, id2}, ur.getDispatchedIDs())
+
+	// an update arriving while the decision task is running is not dispatched
+	id3, _ := ur.bufferUpdate(input)
+	s.Equal([]string{id3}, ur.getBufferedIDs())
+	s.Equal(3, ur.getPendingCount())
+
+	s.NoError(ur.completeUpdate(id1, &updateOutcome{result: []byte{2}}))
+	s.True(closed(termCh1))
+	outcome, err := ur.getOutcome(id1)
+	s.NoError(err)
+	s.Equal([]byte{2}, outcome.result)
+
+	ur.rebufferUpdate(id2)
+	s.False(closed(termCh2))
+	s.ElementsMatch([]string{id2, id3}, ur.getBufferedIDs())
+	_, err = ur.getOutcome(id2)
+	s.Equal(errUpdateNotInTerminalState, err)
+
+	rejected := serviceerror.NewQueryFailed("rejected")
+	s.NoError(ur.completeUpdate(id2, &updateOutcome{failure: rejected}))
+	outcome, err = ur.getOutcome(id2)
+	s.NoError(err)
+	s.Equal(rejected, outcome.failure)
+	s.Equal(errUpdateNotExists, ur.completeUpdate(id2, &updateOutcome{}))
+}
+
+func (s *UpdateRegistrySuite) TestRedispatch() {
+	ur := newUpdateRegistry()
+	id, _ := ur.bufferUpdate(&commonproto.WorkflowQuery{})
+	s.Len(ur.dispatchUpdates(), 1)
+	// the updates of a decision task which failed or timed out are dispatched again
+	s.Len(ur.dispatchUpdates(), 1)
+	s.Equal([]string{id}, ur.getDispatchedIDs())
+
+	ur.removeUpdate(id)
+	s.Equal(0, ur.getPendingCount())
+	_, err := ur.getUpdateInput(id)
+	s.Equal(errUpdateNotExists, err)
+}
+
+func (s *UpdateRegistrySuite) TestAcceptAndComplete() {
+	ur := newUpdateRegistry()
+	id, termCh := ur.bufferUpdate(&commonproto.WorkflowQuery{QueryType: "set-value"})
+	s.Equal(errUpdateNotExists, ur.acceptUpdate(id))
+	ur.dispatchUpdates()
+
+	s.NoError(ur.acceptUpdate(id))
+	s.True(ur.isAccepted(id))
+	s.Equal([]string{id}, ur.getAcceptedIDs())
+	s.Empty(ur.getDispatchedIDs())
+	s.Equal(1, ur.getPendingCount())
+	// accepted updates are not delivered again
+	s.Empty(ur.dispatchUpdates())
+	s.False(closed(termCh))
+
+	s.NoError(ur.completeUpdate(id, &updateOutcome{result: []byte{3}}))
+	s.True(closed(termCh))
+	s.False(ur.isAccepted(id))
+	s.Equal(0, ur.getPendingCount())
+	outcome, err := ur.getOutcome(id)
+	s.NoError(err)
+	s.Equal([]byte{3}, outcome.result)
+}
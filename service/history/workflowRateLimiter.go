@@ -0,0 +1,258 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package history
+
+import (
+	"sync"
+	"time"
+
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"golang.org/x/time/rate"
+
+	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/definition"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/quotas"
+)
+
+type (
+	// workflowRateLimiter limits the rate of the requests of a single workflow, across all its runs, so that a
+	// runaway workflow cannot monopolize its shard. It limits how fast the workflow is signaled, completes decision
+	// tasks and schedules activities, with limits configured by domain and workflow type, and throttles all the
+	// requests of the workflows the shard load tracker found to be hot. A nil limiter allows everything, which is
+	// what shards created by tests rely on.
+	workflowRateLimiter struct {
+		config        *Config
+		metricsClient metrics.Client
+		limiters      cache.Cache // workflowLimitersKey -> *workflowLimiters
+
+		sync.RWMutex
+		hot map[definition.WorkflowIdentifier]*quotas.DynamicRateLimiter
+	}
+
+	// workflowLimitersKey identifies a workflow across its runs together with its workflow type,
+	// the limits of a workflow depend on its type
+	workflowLimitersKey struct {
+		domainID     string
+		workflowID   string
+		workflowType string
+	}
+
+	workflowLimiters struct {
+		signal             *quotas.DynamicRateLimiter
+		decisionCompletion *quotas.DynamicRateLimiter
+		activitySchedule   *quotas.DynamicRateLimiter
+	}
+)
+
+const (
+	// max number of workflows a shard keeps limiters for, the least recently used ones are
+	// evicted first and start again with a full burst
+	workflowRateLimiterCacheSize = 10000
+)
+
+func newWorkflowRateLimiter(
+	config *Config,
+	metricsClient metrics.Client,
+) *workflowRateLimiter {
+
+	return &workflowRateLimiter{
+		config:        config,
+		metricsClient: metricsClient,
+		limiters:      cache.New(workflowRateLimiterCacheSize, &cache.Options{}),
+		hot:           make(map[definition.WorkflowIdentifier]*quotas.DynamicRateLimiter),
+	}
+}
+
+// allowRequest returns ErrWorkflowRateLimitExceeded if the workflow is throttled because of its load on a hot shard
+func (l *workflowRateLimiter) allowRequest(
+	domainID string,
+	workflowID string,
+) error {
+
+	if l == nil {
+		return nil
+	}
+
+	l.RLock()
+	limiter, ok := l.hot[newWorkflowRateLimiterKey(domainID, workflowID)]
+	l.RUnlock()
+	if !ok || limiter.Allow() {
+		return nil
+	}
+	l.metricsClient.IncCounter(metrics.ShardInfoScope, metrics.HotWorkflowRequestThrottledCounter)
+	return ErrWorkflowRateLimitExceeded
+}
+
+// allowSignal returns ErrWorkflowRateLimitExceeded if the workflow is signaled too often
+func (l *workflowRateLimiter) allowSignal(
+	domainID string,
+	domainName string,
+	workflowID string,
+	workflowType string,
+) error {
+
+	if l == nil || l.config.WorkflowSignalRPS(domainName, workflowType) <= 0 {
+		return nil
+	}
+
+	if !l.getLimiters(domainID, domainName, workflowID, workflowType).signal.Allow() {
+		l.metricsClient.Scope(metrics.HistorySignalWorkflowExecutionScope, metrics.DomainTag(domainName)).
+			IncCounter(metrics.WorkflowRateLimitedCounter)
+		return ErrWorkflowRateLimitExceeded
+	}
+	return nil
+}
+
+// allowDecisionCompletion returns ErrWorkflowRateLimitExceeded if the workflow completes decision tasks too often or
+// if the decisions schedule activities too fast. No token is spent unless both limits allow the decision task.
+// A decision scheduling more activities than the burst of the activity limiter is allowed once the burst is available,
+// so that large fan-outs are slowed down without being rejected forever.
+func (l *workflowRateLimiter) allowDecisionCompletion(
+	domainID string,
+	domainName string,
+	workflowID string,
+	workflowType string,
+	decisions []*commonproto.Decision,
+) error {
+
+	if l == nil {
+		return nil
+	}
+
+	activities := 0
+	for _, decision := range decisions {
+		if decision.GetDecisionType() == enums.DecisionTypeScheduleActivityTask {
+			activities++
+		}
+	}
+	limitDecisions := l.config.WorkflowDecisionCompletionRPS(domainName, workflowType) > 0
+	limitActivities := activities > 0 && l.config.WorkflowActivityScheduleRPS(domainName, workflowType) > 0
+	if !limitDecisions && !limitActivities {
+		return nil
+	}
+
+	limiters := l.getLimiters(domainID, domainName, workflowID, workflowType)
+	now := time.Now()
+	var reservations []*rate.Reservation
+	if limitDecisions {
+		reservations = append(reservations, limiters.decisionCompletion.ReserveN(now, 1))
+	}
+	if limitActivities {
+		if burst := limiters.activitySchedule.Burst(); activities > burst {
+			activities = burst
+		}
+		reservations = append(reservations, limiters.activitySchedule.ReserveN(now, activities))
+	}
+
+	for _, reservation := range reservations {
+		if !reservation.OK() || reservation.DelayFrom(now) > 0 {
+			for _, r := range reservations {
+				r.CancelAt(now)
+			}
+			l.metricsClient.Scope(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.DomainTag(domainName)).
+				IncCounter(metrics.WorkflowRateLimitedCounter)
+			return ErrWorkflowRateLimitExceeded
+		}
+	}
+	return nil
+}
+
+// setHotWorkflows throttles the requests of the given workflows and releases the workflows throttled before,
+// the workflows which stay hot keep their limiter
+func (l *workflowRateLimiter) setHotWorkflows(
+	keys []definition.WorkflowIdentifier,
+) {
+
+	if l == nil {
+		return
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	hot := make(map[definition.WorkflowIdentifier]*quotas.DynamicRateLimiter, len(keys))
+	for _, key := range keys {
+		limiter, ok := l.hot[key]
+		if !ok {
+			limiter = quotas.NewDynamicRateLimiter(func() float64 {
+				return float64(l.config.HotWorkflowThrottledRPS())
+			})
+		}
+		hot[key] = limiter
+	}
+	l.hot = hot
+}
+
+func (l *workflowRateLimiter) isHot(
+	key definition.WorkflowIdentifier,
+) bool {
+
+	if l == nil {
+		return false
+	}
+
+	l.RLock()
+	defer l.RUnlock()
+
+	_, ok := l.hot[key]
+	return ok
+}
+
+func (l *workflowRateLimiter) getLimiters(
+	domainID string,
+	domainName string,
+	workflowID string,
+	workflowType string,
+) *workflowLimiters {
+
+	key := workflowLimitersKey{domainID: domainID, workflowID: workflowID, workflowType: workflowType}
+	if limiters, ok := l.limiters.Get(key).(*workflowLimiters); ok {
+		return limiters
+	}
+
+	limiters := &workflowLimiters{
+		signal: quotas.NewDynamicRateLimiter(func() float64 {
+			return float64(l.config.WorkflowSignalRPS(domainName, workflowType))
+		}),
+		decisionCompletion: quotas.NewDynamicRateLimiter(func() float64 {
+			return float64(l.config.WorkflowDecisionCompletionRPS(domainName, workflowType))
+		}),
+		activitySchedule: quotas.NewDynamicRateLimiter(func() float64 {
+			return float64(l.config.WorkflowActivityScheduleRPS(domainName, workflowType))
+		}),
+	}
+	// another request of the same workflow may have created the limiters concurrently
+	existing, err := l.limiters.PutIfNotExist(key, limiters)
+	if err != nil {
+		return limiters
+	}
+	return existing.(*workflowLimiters)
+}
+
+// newWorkflowRateLimiterKey identifies a workflow across its runs
+func newWorkflowRateLimiterKey(
+	domainID string,
+	workflowID string,
+) definition.WorkflowIdentifier {
+
+	return definition.NewWorkflowIdentifier(domainID, workflowID, "")
+}
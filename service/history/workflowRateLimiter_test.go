@@ -0,0 +1,206 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+
+	"github.com/temporalio/temporal/common/definition"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+)
+
+type (
+	workflowRateLimiterSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		config  *Config
+		limiter *workflowRateLimiter
+	}
+)
+
+const (
+	testRateLimitedWorkflowID   = "wId"
+	testRateLimitedWorkflowType = "wType"
+)
+
+func TestWorkflowRateLimiterSuite(t *testing.T) {
+	s := new(workflowRateLimiterSuite)
+	suite.Run(t, s)
+}
+
+func (s *workflowRateLimiterSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.config = NewDynamicConfigForTest()
+	s.config.HotWorkflowThrottledRPS = dynamicconfig.GetIntPropertyFn(1)
+	s.limiter = newWorkflowRateLimiter(s.config, metrics.NewClient(tally.NoopScope, metrics.History))
+}
+
+func (s *workflowRateLimiterSuite) TestUnlimitedByDefault() {
+	decisions := s.newScheduleActivityDecisions(100)
+	for i := 0; i < 100; i++ {
+		s.NoError(s.limiter.allowRequest(testDomainID, testRateLimitedWorkflowID))
+		s.NoError(s.limiter.allowSignal(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+		s.NoError(s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, decisions))
+	}
+}
+
+func (s *workflowRateLimiterSuite) TestNilLimiter() {
+	var limiter *workflowRateLimiter
+	s.NoError(limiter.allowRequest(testDomainID, testRateLimitedWorkflowID))
+	s.NoError(limiter.allowSignal(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+	s.NoError(limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, s.newScheduleActivityDecisions(1)))
+	limiter.setHotWorkflows([]definition.WorkflowIdentifier{newWorkflowRateLimiterKey(testDomainID, testRateLimitedWorkflowID)})
+	s.False(limiter.isHot(newWorkflowRateLimiterKey(testDomainID, testRateLimitedWorkflowID)))
+}
+
+func (s *workflowRateLimiterSuite) TestSignal() {
+	s.config.WorkflowSignalRPS = dynamicconfig.GetIntPropertyFilteredByWorkflowType(2)
+
+	s.NoError(s.limiter.allowSignal(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+	s.NoError(s.limiter.allowSignal(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+	s.Equal(ErrWorkflowRateLimitExceeded, s.limiter.allowSignal(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+
+	// limits apply per workflow, the same workflow ID in another domain is another workflow
+	s.NoError(s.limiter.allowSignal("other-domain-id", testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+	s.NoError(s.limiter.allowSignal(testDomainID, testDomainName, "other-workflow-id", testRateLimitedWorkflowType))
+}
+
+func (s *workflowRateLimiterSuite) TestSignal_FilteredByDomain() {
+	s.config.WorkflowSignalRPS = func(domain, workflowType string) int {
+		if domain == testDomainName {
+			return 1
+		}
+		return 0
+	}
+
+	s.NoError(s.limiter.allowSignal(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+	s.Equal(ErrWorkflowRateLimitExceeded, s.limiter.allowSignal(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+
+	s.NoError(s.limiter.allowSignal("other-domain-id", "other-domain", testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+	s.NoError(s.limiter.allowSignal("other-domain-id", "other-domain", testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+}
+
+func (s *workflowRateLimiterSuite) TestSignal_FilteredByWorkflowType() {
+	s.config.WorkflowSignalRPS = func(domain, workflowType string) int {
+		if workflowType == testRateLimitedWorkflowType {
+			return 1
+		}
+		return 0
+	}
+
+	s.NoError(s.limiter.allowSignal(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+	s.Equal(ErrWorkflowRateLimitExceeded,
+		s.limiter.allowSignal(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType))
+
+	// limiters are keyed by workflow type, a new run of the workflow ID with another type gets its own limits
+	s.NoError(s.limiter.allowSignal(testDomainID, testDomainName, testRateLimitedWorkflowID, "other-workflow-type"))
+	s.NoError(s.limiter.allowSignal(testDomainID, testDomainName, testRateLimitedWorkflowID, "other-workflow-type"))
+}
+
+func (s *workflowRateLimiterSuite) TestDecisionCompletion_FilteredByWorkflowType() {
+	s.config.WorkflowDecisionCompletionRPS = func(domain, workflowType string) int {
+		if workflowType == testRateLimitedWorkflowType {
+			return 1
+		}
+		return 0
+	}
+
+	s.NoError(s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, nil))
+	s.Equal(ErrWorkflowRateLimitExceeded,
+		s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, nil))
+	s.NoError(s.limiter.allowDecisionCompletion(testDomainID, testDomainName, "other-workflow-id", "other-workflow-type", nil))
+	s.NoError(s.limiter.allowDecisionCompletion(testDomainID, testDomainName, "other-workflow-id", "other-workflow-type", nil))
+}
+
+func (s *workflowRateLimiterSuite) TestDecisionCompletion() {
+	s.config.WorkflowDecisionCompletionRPS = dynamicconfig.GetIntPropertyFilteredByWorkflowType(1)
+
+	s.NoError(s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, nil))
+	s.Equal(ErrWorkflowRateLimitExceeded, s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, nil))
+}
+
+func (s *workflowRateLimiterSuite) TestActivitySchedule() {
+	s.config.WorkflowActivityScheduleRPS = dynamicconfig.GetIntPropertyFilteredByWorkflowType(5)
+
+	s.NoError(s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, s.newScheduleActivityDecisions(3)))
+	s.Equal(ErrWorkflowRateLimitExceeded,
+		s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, s.newScheduleActivityDecisions(3)))
+	// decisions not scheduling activities are not limited
+	s.NoError(s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, []*commonproto.Decision{
+		{DecisionType: enums.DecisionTypeCompleteWorkflowExecution},
+	}))
+}
+
+func (s *workflowRateLimiterSuite) TestActivitySchedule_FanOutAboveBurst() {
+	s.config.WorkflowActivityScheduleRPS = dynamicconfig.GetIntPropertyFilteredByWorkflowType(5)
+
+	s.NoError(s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, s.newScheduleActivityDecisions(50)))
+	s.Equal(ErrWorkflowRateLimitExceeded,
+		s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, s.newScheduleActivityDecisions(1)))
+}
+
+func (s *workflowRateLimiterSuite) TestDecisionCompletion_RejectedWithoutSpendingTokens() {
+	s.config.WorkflowDecisionCompletionRPS = dynamicconfig.GetIntPropertyFilteredByWorkflowType(2)
+	s.config.WorkflowActivityScheduleRPS = dynamicconfig.GetIntPropertyFilteredByWorkflowType(5)
+
+	s.NoError(s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, s.newScheduleActivityDecisions(5)))
+	// the activity limit rejects the decision task, the decision completion token must not be spent
+	s.Equal(ErrWorkflowRateLimitExceeded,
+		s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, s.newScheduleActivityDecisions(1)))
+	s.NoError(s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, nil))
+	s.Equal(ErrWorkflowRateLimitExceeded, s.limiter.allowDecisionCompletion(testDomainID, testDomainName, testRateLimitedWorkflowID, testRateLimitedWorkflowType, nil))
+}
+
+func (s *workflowRateLimiterSuite) TestHotWorkflows() {
+	key := newWorkflowRateLimiterKey(testDomainID, testRateLimitedWorkflowID)
+	s.limiter.setHotWorkflows([]definition.WorkflowIdentifier{key})
+	s.True(s.limiter.isHot(key))
+
+	// the throttled workflow only gets the burst of its limiter
+	s.NoError(s.limiter.allowRequest(testDomainID, testRateLimitedWorkflowID))
+	s.Equal(ErrWorkflowRateLimitExceeded, s.limiter.allowRequest(testDomainID, testRateLimitedWorkflowID))
+	s.NoError(s.limiter.allowRequest(testDomainID, "other-workflow-id"))
+
+	// a workflow staying hot keeps its limiter
+	s.limiter.setHotWorkflows([]definition.WorkflowIdentifier{key})
+	s.Equal(ErrWorkflowRateLimitExceeded, s.limiter.allowRequest(testDomainID, testRateLimitedWorkflowID))
+
+	s.limiter.setHotWorkflows(nil)
+	s.False(s.limiter.isHot(key))
+	s.NoError(s.limiter.allowRequest(testDomainID, testRateLimitedWorkflowID))
+	s.NoError(s.limiter.allowRequest(testDomainID, testRateLimitedWorkflowID))
+}
+
+func (s *workflowRateLimiterSuite) newScheduleActivityDecisions(count int) []*commonproto.Decision {
+	decisions := make([]*commonproto.Decision, count)
+	for i := range decisions {
+		decisions[i] = &commonproto.Decision{DecisionType: enums.DecisionTypeScheduleActivityTask}
+	}
+	return decisions
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+	"sync"
+
+	"github.com/temporalio/temporal/common"
+)
+
+type (
+	// backlogDispatcher takes turns among the backlog tasks of a task list and its subqueues.
+	// Each of them reads its own backlog and offers one task at a time to pollers, only the
+	// task holding the turn is offered. When the turn is released, it goes to the next waiting
+	// task of a priorityTaskQueue, so higher priorities are dispatched first without starving
+	// the lower ones.
+	backlogDispatcher struct {
+		sync.Mutex
+		queue *priorityTaskQueue
+		busy  bool
+		// number of tasks waiting for or holding the turn, by priority
+		pending [common.MaxTaskPriority + 1]int
+	}
+
+	// waitingTask is a backlog task waiting for its turn to be offered to pollers
+	waitingTask struct {
+		*internalTask
+		turnC    chan struct{}
+		granted  bool
+		canceled bool
+	}
+)
+
+func newBacklogDispatcher(config *taskListConfig) *backlogDispatcher {
+	return &backlogDispatcher{queue: newPriorityTaskQueue(config)}
+}
+
+// dispatch waits for the turn of the task and offers it with the given function. Returns
+// the error of the offer, or the context error when the context is done before the turn.
+func (d *backlogDispatcher) dispatch(
+	ctx context.Context,
+	task *internalTask,
+	offer func(context.Context, *internalTask) error,
+) error {
+	priority := taskPriority(task.event.Data)
+
+	d.Lock()
+	d.pending[priority]++
+	if !d.busy {
+		d.busy = true
+		d.Unlock()
+	} else {
+		waiting := &waitingTask{internalTask: task, turnC: make(chan struct{})}
+		d.queue.add(waiting)
+		d.Unlock()
+
+		select {
+		case <-waiting.turnC:
+		case <-ctx.Done():
+			d.Lock()
+			// the turn may have been granted while the context was done
+			granted := waiting.granted
+			if !granted {
+				waiting.canceled = true
+				d.pending[priority]--
+			}
+			d.Unlock()
+			if granted {
+				d.release(priority)
+			}
+			return ctx.Err()
+		}
+	}
+
+	err := offer(ctx, task)
+	d.release(priority)
+	return err
+}
+
+// hasPendingAbove returns true when backlog tasks of a higher priority than
+// the given one are waiting for or holding the turn
+func (d *backlogDispatcher) hasPendingAbove(priority int32) bool {
+	d.Lock()
+	defer d.Unlock()
+	for p := int(priority) + 1; p < len(d.pending); p++ {
+		if d.pending[p] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// release gives the turn of a task of the given priority to the next waiting task
+func (d *backlogDispatcher) release(priority int32) {
+	d.Lock()
+	defer d.Unlock()
+	d.pending[priority]--
+	for d.queue.len() > 0 {
+		next := d.queue.next()
+		if !next.canceled {
+			next.granted = true
+			close(next.turnC)
+			return
+		}
+	}
+	d.busy = false
+}
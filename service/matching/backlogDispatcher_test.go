@@ -0,0 +1,115 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBacklogDispatcher_HigherPriorityTakesTurnFirst(t *testing.T) {
+	d := newBacklogDispatcher(newPriorityTestConfig(false, nil))
+	heldC := make(chan struct{})
+	releaseC := make(chan struct{})
+	go func() {
+		_ = d.dispatch(context.Background(), newPriorityTestTask(1, 0).internalTask, func(context.Context, *internalTask) error {
+			close(heldC)
+			<-releaseC
+			return nil
+		})
+	}()
+	<-heldC
+
+	var lock sync.Mutex
+	var dispatched []int64
+	var wg sync.WaitGroup
+	for _, task := range []*waitingTask{newPriorityTestTask(2, 0), newPriorityTestTask(3, 2)} {
+		wg.Add(1)
+		go func(task *internalTask) {
+			defer wg.Done()
+			err := d.dispatch(context.Background(), task, func(_ context.Context, task *internalTask) error {
+				lock.Lock()
+				defer lock.Unlock()
+				dispatched = append(dispatched, task.event.GetTaskID())
+				return nil
+			})
+			require.NoError(t, err)
+		}(task.internalTask)
+	}
+	require.Eventually(t, func() bool { return queuedTasks(d) == 2 }, time.Second, time.Millisecond)
+	require.True(t, d.hasPendingAbove(0))
+	require.True(t, d.hasPendingAbove(1))
+	require.False(t, d.hasPendingAbove(2))
+
+	close(releaseC)
+	wg.Wait()
+	require.Equal(t, []int64{3, 2}, dispatched)
+	require.False(t, d.hasPendingAbove(0))
+}
+
+func TestBacklogDispatcher_CanceledTaskGivesUpTurn(t *testing.T) {
+	d := newBacklogDispatcher(newPriorityTestConfig(false, nil))
+	heldC := make(chan struct{})
+	releaseC := make(chan struct{})
+	go func() {
+		_ = d.dispatch(context.Background(), newPriorityTestTask(1, 0).internalTask, func(context.Context, *internalTask) error {
+			close(heldC)
+			<-releaseC
+			return nil
+		})
+	}()
+	<-heldC
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errC := make(chan error, 1)
+	go func() {
+		errC <- d.dispatch(ctx, newPriorityTestTask(2, 3).internalTask, func(context.Context, *internalTask) error {
+			require.Fail(t, "canceled task must not be offered")
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool { return queuedTasks(d) == 1 }, time.Second, time.Millisecond)
+	require.True(t, d.hasPendingAbove(0))
+	cancel()
+	require.Equal(t, context.Canceled, <-errC)
+	require.False(t, d.hasPendingAbove(0))
+
+	close(releaseC)
+	// the turn is free again once the canceled task is skipped
+	require.Eventually(t, func() bool {
+		d.Lock()
+		defer d.Unlock()
+		return !d.busy
+	}, time.Second, time.Millisecond)
+	require.NoError(t, d.dispatch(context.Background(), newPriorityTestTask(3, 0).internalTask, func(context.Context, *internalTask) error {
+		return nil
+	}))
+}
+
+func queuedTasks(d *backlogDispatcher) int {
+	d.Lock()
+	defer d.Unlock()
+	return d.queue.len()
+}
@@ -47,11 +47,31 @@ type (
 		ForwarderMaxOutstandingTasks dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 		ForwarderMaxRatePerSecond    dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 		ForwarderMaxChildrenPerNode  dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		ForwarderMinRatePerSecond    dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		// Forwarding rate recovered per second after the parent partition was busy
+		ForwarderRateRecoveryPerSecond dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		ForwarderRateBackoffRatio      dynamicconfig.FloatPropertyFn
+
+		// partition auto scaling configuration
+		EnablePartitionAutoScaling     dynamicconfig.BoolPropertyFnWithTaskListInfoFilters
+		PartitionAutoScalingInterval   dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
+		MaxTasklistPartitions          dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		PartitionTargetAddRate         dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		PartitionTargetBacklog         dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		MinPollersPerPartition         dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		PartitionConfigRefreshInterval dynamicconfig.DurationPropertyFn
 
 		// Time to hold a poll request before returning an empty response if there are no tasks
 		LongPollExpirationInterval dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
 		MinTaskThrottlingBurstSize dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 		MaxTaskDeleteBatchSize     dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		// Weight of a task priority relative to the next lower priority when dispatching backlog
+		TaskPriorityWeightRatio dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		// Share the backlog dispatch among fairness keys in proportion to their weights
+		EnableFairnessDispatch   dynamicconfig.BoolPropertyFnWithTaskListInfoFilters
+		FairnessKeyWeights       dynamicconfig.MapPropertyFnWithTaskListInfoFilters
+		FairnessKeyDefaultWeight dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		MaxFairnessKeys          dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 
 		// taskWriter configuration
 		OutstandingTaskAppendsThreshold dynamicconfig.IntPropertyFnWithTaskListInfoFilters
@@ -61,14 +81,29 @@ type (
 	}
 
 	forwarderConfig struct {
-		ForwarderMaxOutstandingPolls func() int
-		ForwarderMaxOutstandingTasks func() int
-		ForwarderMaxRatePerSecond    func() int
-		ForwarderMaxChildrenPerNode  func() int
+		ForwarderMaxOutstandingPolls   func() int
+		ForwarderMaxOutstandingTasks   func() int
+		ForwarderMaxRatePerSecond      func() int
+		ForwarderMaxChildrenPerNode    func() int
+		ForwarderMinRatePerSecond      func() int
+		ForwarderRateRecoveryPerSecond func() int
+		ForwarderRateBackoffRatio      func() float64
+	}
+
+	partitionScalerConfig struct {
+		EnablePartitionAutoScaling   func() bool
+		PartitionAutoScalingInterval func() time.Duration
+		MaxTasklistPartitions        func() int
+		PartitionTargetAddRate       func() int
+		PartitionTargetBacklog       func() int
+		MinPollersPerPartition       func() int
+		// Time clients may keep sending tasks to a partition after it was removed from the write partitions
+		PartitionConfigRefreshInterval func() time.Duration
 	}
 
 	taskListConfig struct {
 		forwarderConfig
+		partitionScalerConfig
 		EnableSyncMatch func() bool
 		// Time to hold a poll request before returning an empty response if there are no tasks
 		LongPollExpirationInterval func() time.Duration
@@ -79,6 +114,11 @@ type (
 		MaxTasklistIdleTime        func() time.Duration
 		MinTaskThrottlingBurstSize func() int
 		MaxTaskDeleteBatchSize     func() int
+		TaskPriorityWeightRatio    func() int
+		EnableFairnessDispatch     func() bool
+		FairnessKeyWeights         func() map[string]int
+		FairnessKeyDefaultWeight   func() int
+		MaxFairnessKeys            func() int
 		// taskWriter configuration
 		OutstandingTaskAppendsThreshold func() int
 		MaxTaskBatchSize                func() int
@@ -101,6 +141,11 @@ func NewConfig(dc *dynamicconfig.Collection) *Config {
 		LongPollExpirationInterval:      dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MatchingLongPollExpirationInterval, time.Minute),
 		MinTaskThrottlingBurstSize:      dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMinTaskThrottlingBurstSize, 1),
 		MaxTaskDeleteBatchSize:          dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMaxTaskDeleteBatchSize, 100),
+		TaskPriorityWeightRatio:         dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingTaskPriorityWeightRatio, 2),
+		EnableFairnessDispatch:          dc.GetBoolPropertyFilteredByTaskListInfo(dynamicconfig.MatchingEnableFairnessDispatch, false),
+		FairnessKeyWeights:              dc.GetMapPropertyFilteredByTaskListInfo(dynamicconfig.MatchingFairnessKeyWeights, nil),
+		FairnessKeyDefaultWeight:        dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingFairnessKeyDefaultWeight, 1),
+		MaxFairnessKeys:                 dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMaxFairnessKeys, 100),
 		OutstandingTaskAppendsThreshold: dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingOutstandingTaskAppendsThreshold, 250),
 		MaxTaskBatchSize:                dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMaxTaskBatchSize, 100),
 		ThrottledLogRPS:                 dc.GetIntProperty(dynamicconfig.MatchingThrottledLogRPS, 20),
@@ -110,6 +155,16 @@ func NewConfig(dc *dynamicconfig.Collection) *Config {
 		ForwarderMaxOutstandingTasks:    dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingForwarderMaxOutstandingTasks, 1),
 		ForwarderMaxRatePerSecond:       dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingForwarderMaxRatePerSecond, 10),
 		ForwarderMaxChildrenPerNode:     dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingForwarderMaxChildrenPerNode, 20),
+		ForwarderMinRatePerSecond:       dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingForwarderMinRatePerSecond, 1),
+		ForwarderRateRecoveryPerSecond:  dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingForwarderRateRecoveryPerSecond, 1),
+		ForwarderRateBackoffRatio:       dc.GetFloat64Property(dynamicconfig.MatchingForwarderRateBackoffRatio, 0.5),
+		EnablePartitionAutoScaling:      dc.GetBoolPropertyFilteredByTaskListInfo(dynamicconfig.MatchingEnablePartitionAutoScaling, false),
+		PartitionAutoScalingInterval:    dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MatchingPartitionAutoScalingInterval, time.Minute),
+		MaxTasklistPartitions:           dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMaxTasklistPartitions, 32),
+		PartitionTargetAddRate:          dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingPartitionTargetAddRate, 200),
+		PartitionTargetBacklog:          dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingPartitionTargetBacklog, 10000),
+		MinPollersPerPartition:          dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMinPollersPerPartition, 1),
+		PartitionConfigRefreshInterval:  dc.GetDurationProperty(dynamicconfig.MatchingPartitionConfigRefreshInterval, 30*time.Second),
 	}
 }
 
@@ -148,6 +203,21 @@ func newTaskListConfig(id *taskListID, config *Config, domainCache cache.DomainC
 		MaxTaskDeleteBatchSize: func() int {
 			return config.MaxTaskDeleteBatchSize(domain, taskListName, taskType)
 		},
+		TaskPriorityWeightRatio: func() int {
+			return config.TaskPriorityWeightRatio(domain, taskListName, taskType)
+		},
+		EnableFairnessDispatch: func() bool {
+			return config.EnableFairnessDispatch(domain, taskListName, taskType)
+		},
+		FairnessKeyWeights: func() map[string]int {
+			return toFairnessKeyWeights(config.FairnessKeyWeights(domain, taskListName, taskType))
+		},
+		FairnessKeyDefaultWeight: func() int {
+			return config.FairnessKeyDefaultWeight(domain, taskListName, taskType)
+		},
+		MaxFairnessKeys: func() int {
+			return config.MaxFairnessKeys(domain, taskListName, taskType)
+		},
 		OutstandingTaskAppendsThreshold: func() int {
 			return config.OutstandingTaskAppendsThreshold(domain, taskListName, taskType)
 		},
@@ -173,6 +243,58 @@ func newTaskListConfig(id *taskListID, config *Config, domainCache cache.DomainC
 			ForwarderMaxChildrenPerNode: func() int {
 				return common.MaxInt(1, config.ForwarderMaxChildrenPerNode(domain, taskListName, taskType))
 			},
+			ForwarderMinRatePerSecond: func() int {
+				return config.ForwarderMinRatePerSecond(domain, taskListName, taskType)
+			},
+			ForwarderRateRecoveryPerSecond: func() int {
+				return config.ForwarderRateRecoveryPerSecond(domain, taskListName, taskType)
+			},
+			ForwarderRateBackoffRatio: func() float64 {
+				return config.ForwarderRateBackoffRatio()
+			},
+		},
+		partitionScalerConfig: partitionScalerConfig{
+			EnablePartitionAutoScaling: func() bool {
+				return config.EnablePartitionAutoScaling(domain, taskListName, taskType)
+			},
+			PartitionAutoScalingInterval: func() time.Duration {
+				return config.PartitionAutoScalingInterval(domain, taskListName, taskType)
+			},
+			MaxTasklistPartitions: func() int {
+				return common.MaxInt(1, config.MaxTasklistPartitions(domain, taskListName, taskType))
+			},
+			PartitionTargetAddRate: func() int {
+				return common.MaxInt(1, config.PartitionTargetAddRate(domain, taskListName, taskType))
+			},
+			PartitionTargetBacklog: func() int {
+				return common.MaxInt(1, config.PartitionTargetBacklog(domain, taskListName, taskType))
+			},
+			MinPollersPerPartition: func() int {
+				return common.MaxInt(1, config.MinPollersPerPartition(domain, taskListName, taskType))
+			},
+			PartitionConfigRefreshInterval: func() time.Duration {
+				return config.PartitionConfigRefreshInterval()
+			},
 		},
 	}, nil
 }
+
+// toFairnessKeyWeights converts the dynamic config map of fairness key weights, ignoring invalid entries
+func toFairnessKeyWeights(m map[string]interface{}) map[string]int {
+	weights := make(map[string]int, len(m))
+	for key, value := range m {
+		var weight int
+		switch v := value.(type) {
+		case int:
+			weight = v
+		case float64:
+			weight = int(v)
+		default:
+			continue
+		}
+		if weight > 0 {
+			weights[key] = weight
+		}
+	}
+	return weights
+}
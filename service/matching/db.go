@@ -40,12 +40,20 @@ type (
 		taskType     int32
 		rangeID      int64
 		ackLevel     int64
-		store        persistence.TaskManager
-		logger       log.Logger
+		// dispatchConfig, partitionState and subqueues are written back on
+		// every update of the task list, so they survive ownership moves
+		dispatchConfig *persistenceblobs.TaskListDispatchConfig
+		partitionState *persistenceblobs.TaskListPartitionState
+		subqueues      []*persistenceblobs.TaskListSubqueue
+		store          persistence.TaskManager
+		logger         log.Logger
 	}
 	taskListState struct {
-		rangeID  int64
-		ackLevel int64
+		rangeID        int64
+		ackLevel       int64
+		dispatchConfig *persistenceblobs.TaskListDispatchConfig
+		partitionState *persistenceblobs.TaskListPartitionState
+		subqueues      []*persistenceblobs.TaskListSubqueue
 	}
 )
 
@@ -94,22 +102,27 @@ func (db *taskListDB) RenewLease() (taskListState, error) {
 	}
 	db.ackLevel = resp.TaskListInfo.Data.AckLevel
 	db.rangeID = resp.TaskListInfo.RangeID
-	return taskListState{rangeID: db.rangeID, ackLevel: db.ackLevel}, nil
+	db.dispatchConfig = resp.TaskListInfo.Data.DispatchConfig
+	db.partitionState = resp.TaskListInfo.Data.PartitionState
+	db.subqueues = resp.TaskListInfo.Data.Subqueues
+	return taskListState{
+		rangeID:        db.rangeID,
+		ackLevel:       db.ackLevel,
+		dispatchConfig: db.dispatchConfig,
+		partitionState: db.partitionState,
+		subqueues:      db.subqueues,
+	}, nil
 }
 
 // UpdateState updates the taskList state with the given value
 func (db *taskListDB) UpdateState(ackLevel int64) error {
 	db.Lock()
 	defer db.Unlock()
+	info := db.taskListInfoLocked()
+	info.AckLevel = ackLevel
 	_, err := db.store.UpdateTaskList(&persistence.UpdateTaskListRequest{
-		TaskListInfo: &persistenceblobs.TaskListInfo{
-			DomainID: db.domainID,
-			Name:     db.taskListName,
-			TaskType: db.taskType,
-			AckLevel: ackLevel,
-			Kind:     db.taskListKind,
-		},
-		RangeID: db.rangeID,
+		TaskListInfo: info,
+		RangeID:      db.rangeID,
 	})
 	if err == nil {
 		db.ackLevel = ackLevel
@@ -117,6 +130,54 @@ func (db *taskListDB) UpdateState(ackLevel int64) error {
 	return err
 }
 
+// UpdateDispatchConfig updates the dispatch config of the taskList with the given value
+func (db *taskListDB) UpdateDispatchConfig(dispatchConfig *persistenceblobs.TaskListDispatchConfig) error {
+	db.Lock()
+	defer db.Unlock()
+	info := db.taskListInfoLocked()
+	info.DispatchConfig = dispatchConfig
+	_, err := db.store.UpdateTaskList(&persistence.UpdateTaskListRequest{
+		TaskListInfo: info,
+		RangeID:      db.rangeID,
+	})
+	if err == nil {
+		db.dispatchConfig = dispatchConfig
+	}
+	return err
+}
+
+// UpdatePartitionState updates the partition state of the taskList with the given value
+func (db *taskListDB) UpdatePartitionState(partitionState *persistenceblobs.TaskListPartitionState) error {
+	db.Lock()
+	defer db.Unlock()
+	info := db.taskListInfoLocked()
+	info.PartitionState = partitionState
+	_, err := db.store.UpdateTaskList(&persistence.UpdateTaskListRequest{
+		TaskListInfo: info,
+		RangeID:      db.rangeID,
+	})
+	if err == nil {
+		db.partitionState = partitionState
+	}
+	return err
+}
+
+// UpdateSubqueues updates the subqueues of the taskList with the given value
+func (db *taskListDB) UpdateSubqueues(subqueues []*persistenceblobs.TaskListSubqueue) error {
+	db.Lock()
+	defer db.Unlock()
+	info := db.taskListInfoLocked()
+	info.Subqueues = subqueues
+	_, err := db.store.UpdateTaskList(&persistence.UpdateTaskListRequest{
+		TaskListInfo: info,
+		RangeID:      db.rangeID,
+	})
+	if err == nil {
+		db.subqueues = subqueues
+	}
+	return err
+}
+
 // CreateTasks creates a batch of given tasks for this task list
 func (db *taskListDB) CreateTasks(tasks []*persistenceblobs.AllocatedTaskInfo) (*persistence.CreateTasksResponse, error) {
 	db.Lock()
@@ -124,13 +185,7 @@ func (db *taskListDB) CreateTasks(tasks []*persistenceblobs.AllocatedTaskInfo) (
 	return db.store.CreateTasks(
 		&persistence.CreateTasksRequest{
 			TaskListInfo: &persistence.PersistedTaskListInfo{
-				Data: &persistenceblobs.TaskListInfo{
-					DomainID: db.domainID,
-					Name:     db.taskListName,
-					TaskType: db.taskType,
-					AckLevel: db.ackLevel,
-					Kind:     db.taskListKind,
-				},
+				Data:    db.taskListInfoLocked(),
 				RangeID: db.rangeID,
 			},
 			Tasks: tasks,
@@ -191,3 +246,16 @@ func (db *taskListDB) CompleteTasksLessThan(taskID int64, limit int) (int, error
 	}
 	return n, err
 }
+
+func (db *taskListDB) taskListInfoLocked() *persistenceblobs.TaskListInfo {
+	return &persistenceblobs.TaskListInfo{
+		DomainID:       db.domainID,
+		Name:           db.taskListName,
+		TaskType:       db.taskType,
+		AckLevel:       db.ackLevel,
+		Kind:           db.taskListKind,
+		DispatchConfig: db.dispatchConfig,
+		PartitionState: db.partitionState,
+		Subqueues:      db.subqueues,
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"container/list"
+)
+
+type (
+	// fairTaskQueue shares the dispatch of tasks among fairness keys by smooth weighted
+	// round robin, so a key flooding the task list only gets its weighted share of the
+	// dispatch. Tasks of the same key are dispatched in FIFO order. Not safe for concurrent use.
+	fairTaskQueue struct {
+		// keys with pending tasks, in the order they became active
+		keys  []*fairKeyQueue
+		index map[string]*fairKeyQueue
+		size  int
+	}
+
+	fairKeyQueue struct {
+		key   string
+		tasks *list.List
+		// current weight of the smooth weighted round robin
+		current int
+	}
+)
+
+func newFairTaskQueue() *fairTaskQueue {
+	return &fairTaskQueue{
+		index: make(map[string]*fairKeyQueue),
+	}
+}
+
+func (q *fairTaskQueue) add(key string, task *waitingTask) {
+	keyQueue, ok := q.index[key]
+	if !ok {
+		keyQueue = &fairKeyQueue{key: key, tasks: list.New()}
+		q.index[key] = keyQueue
+		q.keys = append(q.keys, keyQueue)
+	}
+	keyQueue.tasks.PushBack(task)
+	q.size++
+}
+
+// next removes and returns the task to dispatch next, or nil if the queue is empty.
+// The weight function is only called when more than one key has pending tasks.
+func (q *fairTaskQueue) next(weight func(key string) int) *waitingTask {
+	if q.size == 0 {
+		return nil
+	}
+
+	selected := 0
+	if len(q.keys) == 1 {
+		q.keys[0].current = 0
+	} else {
+		total := 0
+		for i, keyQueue := range q.keys {
+			w := weight(keyQueue.key)
+			keyQueue.current += w
+			total += w
+			if keyQueue.current > q.keys[selected].current {
+				selected = i
+			}
+		}
+		q.keys[selected].current -= total
+	}
+
+	keyQueue := q.keys[selected]
+	task := keyQueue.tasks.Remove(keyQueue.tasks.Front()).(*waitingTask)
+	q.size--
+	if keyQueue.tasks.Len() == 0 {
+		// a key that becomes active again starts over with no accumulated weight
+		delete(q.index, keyQueue.key)
+		q.keys = append(q.keys[:selected], q.keys[selected+1:]...)
+	}
+	return task
+}
+
+func (q *fairTaskQueue) len() int {
+	return q.size
+}
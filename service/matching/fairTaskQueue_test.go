@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFairTaskQueue_FIFOWithinKey(t *testing.T) {
+	q := newFairTaskQueue()
+	require.Nil(t, q.next(nil))
+
+	for i := int64(1); i <= 3; i++ {
+		q.add("key", newPriorityTestTask(i, 0))
+	}
+	require.Equal(t, 3, q.len())
+	// the weight is not needed with a single key
+	for i := int64(1); i <= 3; i++ {
+		require.Equal(t, i, q.next(nil).event.GetTaskID())
+	}
+	require.Equal(t, 0, q.len())
+	require.Nil(t, q.next(nil))
+}
+
+func TestFairTaskQueue_WeightedByKey(t *testing.T) {
+	q := newFairTaskQueue()
+	for i := int64(1); i <= 6; i++ {
+		q.add("heavy", newPriorityTestTask(i, 0))
+	}
+	for i := int64(11); i <= 13; i++ {
+		q.add("light", newPriorityTestTask(i, 0))
+	}
+	weight := func(key string) int {
+		if key == "heavy" {
+			return 3
+		}
+		return 1
+	}
+
+	var dispatched []int64
+	for q.len() > 0 {
+		dispatched = append(dispatched, q.next(weight).event.GetTaskID())
+	}
+	// heavy gets three times the share of light while both have tasks
+	require.Equal(t, []int64{1, 2, 11, 3, 4, 5, 12, 6, 13}, dispatched)
+}
+
+func TestFairTaskQueue_NoisyKeyDoesNotBlockOthers(t *testing.T) {
+	q := newFairTaskQueue()
+	for i := int64(100); i < 1100; i++ {
+		q.add("noisy", newPriorityTestTask(i, 0))
+	}
+	q.add("quiet", newPriorityTestTask(1, 0))
+	weight := func(string) int { return 1 }
+
+	require.Equal(t, int64(100), q.next(weight).event.GetTaskID())
+	require.Equal(t, int64(1), q.next(weight).event.GetTaskID())
+	require.Equal(t, 999, q.len())
+
+	// a key which becomes active again gets its share in the next round
+	q.add("quiet", newPriorityTestTask(2, 0))
+	require.Equal(t, int64(101), q.next(weight).event.GetTaskID())
+	require.Equal(t, int64(2), q.next(weight).event.GetTaskID())
+}
@@ -64,9 +64,14 @@ type (
 		outstandingTasksLimit int32
 		outstandingPollsLimit int32
 
-		// todo: implement a rate limiter that automatically
-		// adjusts rate based on ServiceBusy errors from API calls
-		limiter *quotas.DynamicRateLimiter
+		// rate limiters that back off whenever the parent partition
+		// rejects a forwarded call with ResourceExhausted and slowly
+		// recover to ForwarderMaxRatePerSecond afterwards. Tasks are
+		// always rate limited, queries and polls only while the parent
+		// is pushing back
+		taskLimiter  *quotas.AdaptiveRateLimiter
+		queryLimiter *quotas.AdaptiveRateLimiter
+		pollLimiter  *quotas.AdaptiveRateLimiter
 
 		// cached metric scopes for API calls
 		//nolint
@@ -112,7 +117,19 @@ func newForwarder(
 	client matching.Client,
 	scopeFunc func() metrics.Scope,
 ) *Forwarder {
-	rpsFunc := func() float64 { return float64(cfg.ForwarderMaxRatePerSecond()) }
+	params := func(rateGauge int) quotas.AdaptiveRateLimiterParams {
+		return quotas.AdaptiveRateLimiterParams{
+			MaxRPS: func() float64 { return float64(cfg.ForwarderMaxRatePerSecond()) },
+			MinRPS: func() float64 { return float64(cfg.ForwarderMinRatePerSecond()) },
+			RecoveryRPS: func() float64 {
+				return float64(cfg.ForwarderRateRecoveryPerSecond())
+			},
+			BackoffRatio: cfg.ForwarderRateBackoffRatio,
+			OnRateChange: func(rps float64) {
+				scopeFunc().UpdateGauge(rateGauge, rps)
+			},
+		}
+	}
 	fwdr := &Forwarder{
 		cfg:                   cfg,
 		client:                client,
@@ -120,7 +137,9 @@ func newForwarder(
 		taskListKind:          kind,
 		outstandingTasksLimit: int32(cfg.ForwarderMaxOutstandingTasks()),
 		outstandingPollsLimit: int32(cfg.ForwarderMaxOutstandingPolls()),
-		limiter:               quotas.NewDynamicRateLimiter(rpsFunc),
+		taskLimiter:           quotas.NewAdaptiveRateLimiter(params(metrics.ForwardTaskRateGauge)),
+		queryLimiter:          quotas.NewAdaptiveRateLimiter(params(metrics.ForwardQueryRateGauge)),
+		pollLimiter:           quotas.NewAdaptiveRateLimiter(params(metrics.ForwardPollRateGauge)),
 		scopeFunc:             scopeFunc,
 	}
 	fwdr.addReqToken.Store(newForwarderReqToken(cfg.ForwarderMaxOutstandingTasks()))
@@ -139,7 +158,7 @@ func (fwdr *Forwarder) ForwardTask(ctx context.Context, task *internalTask) erro
 		return errNoParent
 	}
 
-	if !fwdr.limiter.Allow() {
+	if !fwdr.taskLimiter.Allow() {
 		return errForwarderSlowDown
 	}
 
@@ -171,6 +190,9 @@ func (fwdr *Forwarder) ForwardTask(ctx context.Context, task *internalTask) erro
 			Source:                        task.source,
 			ScheduleToStartTimeoutSeconds: newScheduleToStartTimeout,
 			ForwardedFrom:                 fwdr.taskListID.name,
+			Priority:                      task.event.Data.GetPriority(),
+			FairnessKey:                   task.event.Data.GetFairnessKey(),
+			WorkflowType:                  task.event.Data.GetWorkflowType(),
 		})
 	case persistence.TaskListTypeActivity:
 		_, err = fwdr.client.AddActivityTask(ctx, &matchingservice.AddActivityTaskRequest{
@@ -185,12 +207,15 @@ func (fwdr *Forwarder) ForwardTask(ctx context.Context, task *internalTask) erro
 			Source:                        task.source,
 			ScheduleToStartTimeoutSeconds: newScheduleToStartTimeout,
 			ForwardedFrom:                 fwdr.taskListID.name,
+			Priority:                      task.event.Data.GetPriority(),
+			FairnessKey:                   task.event.Data.GetFairnessKey(),
+			WorkflowType:                  task.event.Data.GetWorkflowType(),
 		})
 	default:
 		return errInvalidTaskListType
 	}
 
-	return fwdr.handleErr(err)
+	return fwdr.handleErr(err, fwdr.taskLimiter)
 }
 
 // ForwardQueryTask forwards a query task to parent task list partition, if it exist
@@ -208,6 +233,10 @@ func (fwdr *Forwarder) ForwardQueryTask(
 		return nil, errNoParent
 	}
 
+	if fwdr.queryLimiter.Throttled() && !fwdr.queryLimiter.Allow() {
+		return nil, errForwarderSlowDown
+	}
+
 	resp, err := fwdr.client.QueryWorkflow(ctx, &matchingservice.QueryWorkflowRequest{
 		DomainUUID: task.query.request.GetDomainUUID(),
 		TaskList: &commonproto.TaskList{
@@ -218,7 +247,7 @@ func (fwdr *Forwarder) ForwardQueryTask(
 		ForwardedFrom: fwdr.taskListID.name,
 	})
 
-	return resp, fwdr.handleErr(err)
+	return resp, fwdr.handleErr(err, fwdr.queryLimiter)
 }
 
 // ForwardPoll forwards a poll request to parent task list partition if it exist
@@ -232,8 +261,13 @@ func (fwdr *Forwarder) ForwardPoll(ctx context.Context) (*internalTask, error) {
 		return nil, errNoParent
 	}
 
+	if fwdr.pollLimiter.Throttled() && !fwdr.pollLimiter.Allow() {
+		return nil, errForwarderSlowDown
+	}
+
 	pollerID, _ := ctx.Value(pollerIDKey).(string)
 	identity, _ := ctx.Value(identityKey).(string)
+	workerBuildID, _ := ctx.Value(workerBuildIDKey).(string)
 
 	switch fwdr.taskListID.taskType {
 	case persistence.TaskListTypeDecision:
@@ -248,9 +282,10 @@ func (fwdr *Forwarder) ForwardPoll(ctx context.Context) (*internalTask, error) {
 				Identity: identity,
 			},
 			ForwardedFrom: fwdr.taskListID.name,
+			WorkerBuildId: workerBuildID,
 		})
 		if err != nil {
-			return nil, fwdr.handleErr(err)
+			return nil, fwdr.handleErr(err, fwdr.pollLimiter)
 		}
 		return newInternalStartedTask(&startedTaskInfo{decisionTaskInfo: resp}), nil
 	case persistence.TaskListTypeActivity:
@@ -267,7 +302,7 @@ func (fwdr *Forwarder) ForwardPoll(ctx context.Context) (*internalTask, error) {
 			ForwardedFrom: fwdr.taskListID.name,
 		})
 		if err != nil {
-			return nil, fwdr.handleErr(err)
+			return nil, fwdr.handleErr(err, fwdr.pollLimiter)
 		}
 		return newInternalStartedTask(&startedTaskInfo{activityTaskInfo: resp}), nil
 	}
@@ -300,8 +335,10 @@ func (fwdr *Forwarder) refreshTokenC(value *atomic.Value, curr *int32, maxLimit
 	}
 }
 
-func (fwdr *Forwarder) handleErr(err error) error {
+func (fwdr *Forwarder) handleErr(err error, limiter *quotas.AdaptiveRateLimiter) error {
 	if _, ok := err.(*serviceerror.ResourceExhausted); ok {
+		limiter.Backoff()
+		fwdr.scopeFunc().IncCounter(metrics.ForwardBackoffCounter)
 		return errForwarderSlowDown
 	}
 	return err
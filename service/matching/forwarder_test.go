@@ -32,6 +32,7 @@ import (
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/suite"
 	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
 
 	"github.com/temporalio/temporal/.gen/proto/matchingservice"
 	"github.com/temporalio/temporal/.gen/proto/matchingservicemock"
@@ -60,10 +61,13 @@ func (t *ForwarderTestSuite) SetupTest() {
 	t.controller = gomock.NewController(t.T())
 	t.client = matchingservicemock.NewMockMatchingServiceClient(t.controller)
 	t.cfg = &forwarderConfig{
-		ForwarderMaxOutstandingPolls: func() int { return 1 },
-		ForwarderMaxRatePerSecond:    func() int { return 2 },
-		ForwarderMaxChildrenPerNode:  func() int { return 20 },
-		ForwarderMaxOutstandingTasks: func() int { return 1 },
+		ForwarderMaxOutstandingPolls:   func() int { return 1 },
+		ForwarderMaxRatePerSecond:      func() int { return 2 },
+		ForwarderMaxChildrenPerNode:    func() int { return 20 },
+		ForwarderMinRatePerSecond:      func() int { return 1 },
+		ForwarderRateRecoveryPerSecond: func() int { return 1 },
+		ForwarderRateBackoffRatio:      func() float64 { return 0.5 },
+		ForwarderMaxOutstandingTasks:   func() int { return 1 },
 	}
 	t.taskList = newTestTaskListID("fwdr", "tl0", persistence.TaskListTypeDecision)
 	scope := func() metrics.Scope { return metrics.NoopScope(metrics.Matching) }
@@ -148,6 +152,33 @@ func (t *ForwarderTestSuite) TestForwardTaskRateExceeded() {
 	t.Equal(errForwarderSlowDown, t.fwdr.ForwardTask(context.Background(), task))
 }
 
+func (t *ForwarderTestSuite) TestForwardTaskBackoffOnResourceExhausted() {
+	t.usingTasklistPartition(persistence.TaskListTypeActivity)
+
+	t.client.EXPECT().AddActivityTask(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, serviceerror.NewResourceExhausted("busy")).Times(1)
+	taskInfo := randomTaskInfo()
+	task := newInternalTask(taskInfo, nil, enums.TaskSourceHistory, "", false)
+	t.False(t.fwdr.taskLimiter.Throttled())
+	t.Equal(errForwarderSlowDown, t.fwdr.ForwardTask(context.Background(), task))
+	t.True(t.fwdr.taskLimiter.Throttled())
+	t.True(t.fwdr.taskLimiter.Limit() < float64(t.cfg.ForwarderMaxRatePerSecond()))
+	t.False(t.fwdr.queryLimiter.Throttled())
+	t.False(t.fwdr.pollLimiter.Throttled())
+}
+
+func (t *ForwarderTestSuite) TestForwardQueryTaskBackoffOnResourceExhausted() {
+	t.usingTasklistPartition(persistence.TaskListTypeDecision)
+
+	t.client.EXPECT().QueryWorkflow(gomock.Any(), gomock.Any()).
+		Return(nil, serviceerror.NewResourceExhausted("busy")).Times(1)
+	task := newInternalQueryTask("id1", &matchingservice.QueryWorkflowRequest{})
+	_, err := t.fwdr.ForwardQueryTask(context.Background(), task)
+	t.Equal(errForwarderSlowDown, err)
+	t.True(t.fwdr.queryLimiter.Throttled())
+	t.False(t.fwdr.taskLimiter.Throttled())
+}
+
 func (t *ForwarderTestSuite) TestForwardQueryTaskError() {
 	task := newInternalQueryTask("id1", &matchingservice.QueryWorkflowRequest{})
 	_, err := t.fwdr.ForwardQueryTask(context.Background(), task)
@@ -209,6 +240,7 @@ func (t *ForwarderTestSuite) TestForwardPollForDecision() {
 	pollerID := uuid.New()
 	ctx := context.WithValue(context.Background(), pollerIDKey, pollerID)
 	ctx = context.WithValue(ctx, identityKey, "id1")
+	ctx = context.WithValue(ctx, workerBuildIDKey, "build1")
 	resp := &matchingservice.PollForDecisionTaskResponse{}
 
 	var request *matchingservice.PollForDecisionTaskRequest
@@ -225,6 +257,7 @@ func (t *ForwarderTestSuite) TestForwardPollForDecision() {
 	t.Equal(pollerID, request.GetPollerID())
 	t.Equal(t.taskList.domainID, request.GetDomainUUID())
 	t.Equal("id1", request.GetPollRequest().GetIdentity())
+	t.Equal("build1", request.GetWorkerBuildId())
 	t.Equal(t.taskList.Parent(20), request.GetPollRequest().GetTaskList().GetName())
 	t.Equal(enums.TaskListKind(t.fwdr.taskListKind), request.GetPollRequest().GetTaskList().GetKind())
 	t.Equal(resp, task.pollForDecisionResponse())
@@ -294,6 +294,51 @@ func (h *Handler) ListTaskListPartitions(ctx context.Context, request *matchings
 	return response, h.handleErr(err, scope)
 }
 
+// UpdateTaskListDispatchConfig persists the dispatch limits of a task list with its root partition and applies them
+func (h *Handler) UpdateTaskListDispatchConfig(ctx context.Context, request *matchingservice.UpdateTaskListDispatchConfigRequest) (_ *matchingservice.UpdateTaskListDispatchConfigResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+	scope := metrics.MatchingUpdateTaskListDispatchConfigScope
+	sw := h.startRequestProfile("UpdateTaskListDispatchConfig", scope)
+	defer sw.Stop()
+
+	if ok := h.rateLimiter.Allow(); !ok {
+		return nil, h.handleErr(errMatchingHostThrottle, scope)
+	}
+
+	err := h.engine.UpdateTaskListDispatchConfig(ctx, request)
+	return &matchingservice.UpdateTaskListDispatchConfigResponse{}, h.handleErr(err, scope)
+}
+
+// ListTaskListTasks returns the backlog tasks of a task list partition and its subqueues page by page
+func (h *Handler) ListTaskListTasks(ctx context.Context, request *matchingservice.ListTaskListTasksRequest) (_ *matchingservice.ListTaskListTasksResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+	scope := metrics.MatchingListTaskListTasksScope
+	sw := h.startRequestProfile("ListTaskListTasks", scope)
+	defer sw.Stop()
+
+	if ok := h.rateLimiter.Allow(); !ok {
+		return nil, h.handleErr(errMatchingHostThrottle, scope)
+	}
+
+	response, err := h.engine.ListTaskListTasks(ctx, request)
+	return response, h.handleErr(err, scope)
+}
+
+// DeleteTaskListTasks drops tasks from the backlog of a task list partition or one of its subqueues
+func (h *Handler) DeleteTaskListTasks(ctx context.Context, request *matchingservice.DeleteTaskListTasksRequest) (_ *matchingservice.DeleteTaskListTasksResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+	scope := metrics.MatchingDeleteTaskListTasksScope
+	sw := h.startRequestProfile("DeleteTaskListTasks", scope)
+	defer sw.Stop()
+
+	if ok := h.rateLimiter.Allow(); !ok {
+		return nil, h.handleErr(errMatchingHostThrottle, scope)
+	}
+
+	err := h.engine.DeleteTaskListTasks(ctx, request)
+	return &matchingservice.DeleteTaskListTasksResponse{}, h.handleErr(err, scope)
+}
+
 func (h *Handler) handleErr(err error, scope int) error {
 
 	if err == nil {
@@ -294,13 +294,31 @@ func (tm *TaskMatcher) UpdateRatelimit(rps *float64) {
 	if rps == nil {
 		return
 	}
-	rate := *rps
+	rate := tm.partitionRate(*rps)
+	tm.limiter.UpdateMaxDispatch(&rate)
+}
+
+// SetRatelimit sets the task dispatch rate immediately, unlike UpdateRatelimit
+// it also applies rates higher than the current one
+func (tm *TaskMatcher) SetRatelimit(rps float64) {
+	rate := tm.partitionRate(rps)
+	tm.limiter.SetMaxDispatch(&rate)
+}
+
+// ResetRatelimit restores the default task dispatch rate, which pollers
+// lower to their own rate again from then on
+func (tm *TaskMatcher) ResetRatelimit() {
+	rate := _defaultTaskDispatchRPS
+	tm.limiter.SetMaxDispatch(&rate)
+}
+
+func (tm *TaskMatcher) partitionRate(rate float64) float64 {
 	nPartitions := tm.numPartitions()
 	if rate > float64(nPartitions) {
 		// divide the rate equally across all partitions
-		rate = rate / float64(tm.numPartitions())
+		rate = rate / float64(nPartitions)
 	}
-	tm.limiter.UpdateMaxDispatch(&rate)
+	return rate
 }
 
 // Rate returns the current rate at which tasks are dispatched
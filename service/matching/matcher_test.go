@@ -66,10 +66,13 @@ func (t *MatcherTestSuite) SetupTest() {
 	tlCfg, err := newTaskListConfig(t.taskList, cfg, t.newDomainCache())
 	t.NoError(err)
 	tlCfg.forwarderConfig = forwarderConfig{
-		ForwarderMaxOutstandingPolls: func() int { return 1 },
-		ForwarderMaxOutstandingTasks: func() int { return 1 },
-		ForwarderMaxRatePerSecond:    func() int { return 2 },
-		ForwarderMaxChildrenPerNode:  func() int { return 20 },
+		ForwarderMaxOutstandingPolls:   func() int { return 1 },
+		ForwarderMaxOutstandingTasks:   func() int { return 1 },
+		ForwarderMaxRatePerSecond:      func() int { return 2 },
+		ForwarderMaxChildrenPerNode:    func() int { return 20 },
+		ForwarderMinRatePerSecond:      func() int { return 1 },
+		ForwarderRateRecoveryPerSecond: func() int { return 1 },
+		ForwarderRateBackoffRatio:      func() float64 { return 0.5 },
 	}
 	t.cfg = tlCfg
 	scope := func() metrics.Scope { return metrics.NoopScope(metrics.Matching) }
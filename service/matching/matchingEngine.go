@@ -58,8 +58,9 @@ import (
 // TODO: Switch implementation from lock/channel based to a partitioned agent
 // to simplify code and reduce possibility of synchronization errors.
 type (
-	pollerIDCtxKey string
-	identityCtxKey string
+	pollerIDCtxKey      string
+	identityCtxKey      string
+	workerBuildIDCtxKey string
 
 	// lockableQueryTaskMap maps query TaskID (which is a UUID generated in QueryWorkflow() call) to a channel
 	// that QueryWorkflow() will block on. The channel is unblocked either by worker sending response through
@@ -99,8 +100,9 @@ var (
 	ErrNoTasks    = errors.New("No tasks")
 	errPumpClosed = errors.New("Task list pump closed its channel")
 
-	pollerIDKey pollerIDCtxKey = "pollerID"
-	identityKey identityCtxKey = "identity"
+	pollerIDKey      pollerIDCtxKey      = "pollerID"
+	identityKey      identityCtxKey      = "identity"
+	workerBuildIDKey workerBuildIDCtxKey = "workerBuildID"
 )
 
 var _ Engine = (*matchingEngineImpl)(nil) // Asserts that interface is indeed implemented
@@ -233,6 +235,11 @@ func (e *matchingEngineImpl) AddDecisionTask(ctx context.Context, addRequest *ma
 		return false, err
 	}
 
+	taskList, err = e.getDecisionTaskListForBuild(taskList, taskListKind, addRequest.GetPinnedBuildId())
+	if err != nil {
+		return false, err
+	}
+
 	tlMgr, err := e.getTaskListManager(taskList, taskListKind)
 	if err != nil {
 		return false, err
@@ -243,12 +250,15 @@ func (e *matchingEngineImpl) AddDecisionTask(ctx context.Context, addRequest *ma
 	expiry := types.TimestampNow()
 	expiry.Seconds += int64(addRequest.ScheduleToStartTimeoutSeconds)
 	taskInfo := &persistenceblobs.TaskInfo{
-		DomainID:    primitives.MustParseUUID(domainID),
-		RunID:       primitives.MustParseUUID(addRequest.Execution.GetRunId()),
-		WorkflowID:  addRequest.Execution.GetWorkflowId(),
-		ScheduleID:  addRequest.GetScheduleId(),
-		Expiry:      expiry,
-		CreatedTime: now,
+		DomainID:     primitives.MustParseUUID(domainID),
+		RunID:        primitives.MustParseUUID(addRequest.Execution.GetRunId()),
+		WorkflowID:   addRequest.Execution.GetWorkflowId(),
+		ScheduleID:   addRequest.GetScheduleId(),
+		Expiry:       expiry,
+		CreatedTime:  now,
+		Priority:     addRequest.GetPriority(),
+		FairnessKey:  addRequest.GetFairnessKey(),
+		WorkflowType: addRequest.GetWorkflowType(),
 	}
 
 	return tlMgr.AddTask(ctx, addTaskParams{
@@ -287,12 +297,15 @@ func (e *matchingEngineImpl) AddActivityTask(ctx context.Context, addRequest *ma
 	expiry := types.TimestampNow()
 	expiry.Seconds += int64(addRequest.GetScheduleToStartTimeoutSeconds())
 	taskInfo := &persistenceblobs.TaskInfo{
-		DomainID:    sourceDomainID,
-		RunID:       runID,
-		WorkflowID:  addRequest.Execution.GetWorkflowId(),
-		ScheduleID:  addRequest.GetScheduleId(),
-		CreatedTime: now,
-		Expiry:      expiry,
+		DomainID:     sourceDomainID,
+		RunID:        runID,
+		WorkflowID:   addRequest.Execution.GetWorkflowId(),
+		ScheduleID:   addRequest.GetScheduleId(),
+		CreatedTime:  now,
+		Expiry:       expiry,
+		Priority:     addRequest.GetPriority(),
+		FairnessKey:  addRequest.GetFairnessKey(),
+		WorkflowType: addRequest.GetWorkflowType(),
 	}
 
 	return tlMgr.AddTask(ctx, addTaskParams{
@@ -320,11 +333,16 @@ pollLoop:
 		// long-poll when frontend calls CancelOutstandingPoll API
 		pollerCtx := context.WithValue(ctx, pollerIDKey, pollerID)
 		pollerCtx = context.WithValue(pollerCtx, identityKey, request.GetIdentity())
+		pollerCtx = context.WithValue(pollerCtx, workerBuildIDKey, req.GetWorkerBuildId())
 		taskList, err := newTaskListID(domainID, taskListName, persistence.TaskListTypeDecision)
 		if err != nil {
 			return nil, err
 		}
 		taskListKind := request.TaskList.GetKind()
+		taskList, err = e.getPollerTaskListForBuild(taskList, taskListKind, req.GetWorkerBuildId())
+		if err != nil {
+			return nil, err
+		}
 		task, err := e.getTask(pollerCtx, taskList, nil, taskListKind)
 		if err != nil {
 			// TODO: Is empty poll the best reply for errPumpClosed?
@@ -373,7 +391,7 @@ pollLoop:
 			return e.createPollForDecisionTaskResponse(task, resp), nil
 		}
 
-		resp, err := e.recordDecisionTaskStarted(ctx, request, task)
+		resp, err := e.recordDecisionTaskStarted(ctx, request, req.GetWorkerBuildId(), task)
 		if err != nil {
 			switch err.(type) {
 			case *serviceerror.NotFound, *serviceerror.EventAlreadyStarted:
@@ -471,6 +489,11 @@ func (e *matchingEngineImpl) QueryWorkflow(ctx context.Context, queryRequest *ma
 		return nil, err
 	}
 
+	taskList, err = e.getDecisionTaskListForBuild(taskList, taskListKind, queryRequest.GetPinnedBuildId())
+	if err != nil {
+		return nil, err
+	}
+
 	tlMgr, err := e.getTaskListManager(taskList, taskListKind)
 	if err != nil {
 		return nil, err
@@ -577,6 +600,60 @@ func (e *matchingEngineImpl) DescribeTaskList(ctx context.Context, request *matc
 	return tlMgr.DescribeTaskList(request.DescRequest.GetIncludeTaskListStatus()), nil
 }
 
+// UpdateTaskListDispatchConfig persists the dispatch limits of a task list with its root partition and applies them
+func (e *matchingEngineImpl) UpdateTaskListDispatchConfig(ctx context.Context, request *matchingservice.UpdateTaskListDispatchConfigRequest) error {
+	domainID := request.GetDomainUUID()
+	taskListType := request.GetTaskListType()
+	taskListName := request.TaskList.GetName()
+
+	taskList, err := newTaskListID(domainID, taskListName, taskListType)
+	if err != nil {
+		return err
+	}
+	taskListKind := request.TaskList.GetKind()
+	tlMgr, err := e.getTaskListManager(taskList, taskListKind)
+	if err != nil {
+		return err
+	}
+
+	return tlMgr.UpdateDispatchConfig(request.GetDispatchConfig())
+}
+
+// ListTaskListTasks returns the backlog tasks of a task list partition and its subqueues page by page
+func (e *matchingEngineImpl) ListTaskListTasks(ctx context.Context, request *matchingservice.ListTaskListTasksRequest) (*matchingservice.ListTaskListTasksResponse, error) {
+	taskList, err := newTaskListID(request.GetDomainUUID(), request.TaskList.GetName(), request.GetTaskListType())
+	if err != nil {
+		return nil, err
+	}
+	tlMgr, err := e.getTaskListManager(taskList, request.TaskList.GetKind())
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, nextPageToken, err := tlMgr.ListTasks(int(request.GetMaximumPageSize()), request.NextPageToken)
+	if err != nil {
+		return nil, err
+	}
+	return &matchingservice.ListTaskListTasksResponse{
+		Tasks:         tasks,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// DeleteTaskListTasks drops tasks from the backlog of a task list partition or one of its subqueues
+func (e *matchingEngineImpl) DeleteTaskListTasks(ctx context.Context, request *matchingservice.DeleteTaskListTasksRequest) error {
+	taskList, err := newTaskListID(request.GetDomainUUID(), request.TaskList.GetName(), request.GetTaskListType())
+	if err != nil {
+		return err
+	}
+	tlMgr, err := e.getTaskListManager(taskList, request.TaskList.GetKind())
+	if err != nil {
+		return err
+	}
+
+	return tlMgr.DeleteTasks(request.GetBacklog(), request.GetTaskIds())
+}
+
 func (e *matchingEngineImpl) ListTaskListPartitions(ctx context.Context, request *matchingservice.ListTaskListPartitionsRequest) (*matchingservice.ListTaskListPartitionsResponse, error) {
 	activityTaskListInfo, err := e.listTaskListPartitions(request, persistence.TaskListTypeActivity)
 	if err != nil {
@@ -602,13 +679,9 @@ func (e *matchingEngineImpl) listTaskListPartitions(request *matchingservice.Lis
 	if err != nil {
 		return nil, err
 	}
-	partitionHostInfo := make([]*commonproto.TaskListPartitionMetadata, len(partitions))
-
-	if err != nil {
-		return nil, err
-	}
+	partitionHostInfo := make([]*commonproto.TaskListPartitionMetadata, 0, len(partitions))
 	for _, partition := range partitions {
-		if host, err := e.getHostInfo(partition); err != nil {
+		if host, err := e.getHostInfo(partition); err == nil {
 			partitionHostInfo = append(partitionHostInfo,
 				&commonproto.TaskListPartitionMetadata{
 					Key:           partition,
@@ -637,19 +710,26 @@ func (e *matchingEngineImpl) getAllPartitions(
 	if err != nil {
 		return partitionKeys, err
 	}
-	taskListID, err := newTaskListID(domainID, taskList.GetName(), persistence.TaskListTypeDecision)
-	rootPartition := taskListID.GetRoot()
-
-	partitionKeys = append(partitionKeys, rootPartition)
+	taskListName, err := newTaskListName(taskList.GetName())
+	if err != nil {
+		return partitionKeys, err
+	}
+	rootID, err := newTaskListID(domainID, taskListName.GetRoot(), taskListType)
+	if err != nil {
+		return partitionKeys, err
+	}
 
-	nWritePartitions := e.config.GetTasksBatchSize
-	n := nWritePartitions(domain, rootPartition, taskListType)
-	if n <= 0 {
-		return partitionKeys, nil
+	// the root partition owns the partition config of the task list, read partitions
+	// include the ones that are still draining after being removed by auto scaling
+	rootMgr, err := e.getTaskListManager(rootID, enums.TaskListKindNormal)
+	if err != nil {
+		return partitionKeys, err
 	}
+	n := int(rootMgr.DescribeTaskList(false).GetPartitionConfig().GetNumReadPartitions())
 
+	partitionKeys = append(partitionKeys, rootID.name)
 	for i := 1; i < n; i++ {
-		partitionKeys = append(partitionKeys, fmt.Sprintf("%v%v/%v", taskListPartitionPrefix, rootPartition, i))
+		partitionKeys = append(partitionKeys, rootID.mkName(i))
 	}
 
 	return partitionKeys, nil
@@ -776,6 +856,7 @@ func (e *matchingEngineImpl) createPollForActivityTaskResponse(
 func (e *matchingEngineImpl) recordDecisionTaskStarted(
 	ctx context.Context,
 	pollReq *workflowservice.PollForDecisionTaskRequest,
+	workerBuildID string,
 	task *internalTask,
 ) (*historyservice.RecordDecisionTaskStartedResponse, error) {
 	request := &historyservice.RecordDecisionTaskStartedRequest{
@@ -785,6 +866,7 @@ func (e *matchingEngineImpl) recordDecisionTaskStarted(
 		TaskId:            task.event.TaskID,
 		RequestId:         uuid.New(),
 		PollRequest:       pollReq,
+		WorkerBuildId:     workerBuildID,
 	}
 	var resp *historyservice.RecordDecisionTaskStartedResponse
 	op := func() error {
@@ -831,6 +913,64 @@ func (e *matchingEngineImpl) recordActivityTaskStarted(
 	return resp, err
 }
 
+// getDecisionTaskListForBuild returns the task list which decision tasks of a workflow pinned
+// to the given worker build ID are added to, when worker versioning is enabled for the domain
+func (e *matchingEngineImpl) getDecisionTaskListForBuild(
+	taskList *taskListID,
+	taskListKind enums.TaskListKind,
+	pinnedBuildID string,
+) (*taskListID, error) {
+	// sticky task lists are already bound to a single worker and
+	// tasks forwarded from child partitions are already routed
+	if taskListKind == enums.TaskListKindSticky || taskList.IsVersioned() {
+		return taskList, nil
+	}
+	domainEntry, err := e.domainCache.GetDomainByID(taskList.domainID)
+	if err != nil {
+		return nil, err
+	}
+	buildID := domainEntry.GetDecisionTaskBuildID(pinnedBuildID)
+	if buildID == "" {
+		return taskList, nil
+	}
+	return taskList.withBuildID(buildID), nil
+}
+
+// getPollerTaskListForBuild returns the task list which a worker of the given build ID polls
+// decision tasks from, workers without a build ID keep polling the task list they asked for.
+// Tasks added before versioning was enabled or before the routing of a build ID changed wait
+// in the task lists they were added to, workers of the build they are routed to now drain them.
+func (e *matchingEngineImpl) getPollerTaskListForBuild(
+	taskList *taskListID,
+	taskListKind enums.TaskListKind,
+	workerBuildID string,
+) (*taskListID, error) {
+	if workerBuildID == "" || taskListKind == enums.TaskListKindSticky || taskList.IsVersioned() {
+		return taskList, nil
+	}
+	domainEntry, err := e.domainCache.GetDomainByID(taskList.domainID)
+	if err != nil {
+		return nil, err
+	}
+	if !domainEntry.IsWorkerVersioningEnabled() {
+		return taskList, nil
+	}
+	for _, routedBuildID := range domainEntry.GetRoutedWorkerBuildIDs(workerBuildID) {
+		routedTaskList := taskList
+		if routedBuildID != "" {
+			routedTaskList = taskList.withBuildID(routedBuildID)
+		}
+		tlMgr, err := e.getTaskListManager(routedTaskList, taskListKind)
+		if err != nil {
+			return nil, err
+		}
+		if tlMgr.DescribeTaskList(true).GetTaskListStatus().GetBacklogCountHint() > 0 {
+			return routedTaskList, nil
+		}
+	}
+	return taskList.withBuildID(workerBuildID), nil
+}
+
 func (e *matchingEngineImpl) emitForwardedFromStats(scope int, isTaskForwarded bool, pollForwardedFrom string) {
 	isPollForwarded := len(pollForwardedFrom) > 0
 	switch {
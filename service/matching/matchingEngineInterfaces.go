@@ -39,5 +39,8 @@ type (
 		CancelOutstandingPoll(ctx context.Context, request *matchingservice.CancelOutstandingPollRequest) error
 		DescribeTaskList(ctx context.Context, request *matchingservice.DescribeTaskListRequest) (*matchingservice.DescribeTaskListResponse, error)
 		ListTaskListPartitions(ctx context.Context, request *matchingservice.ListTaskListPartitionsRequest) (*matchingservice.ListTaskListPartitionsResponse, error)
+		UpdateTaskListDispatchConfig(ctx context.Context, request *matchingservice.UpdateTaskListDispatchConfigRequest) error
+		ListTaskListTasks(ctx context.Context, request *matchingservice.ListTaskListTasksRequest) (*matchingservice.ListTaskListTasksResponse, error)
+		DeleteTaskListTasks(ctx context.Context, request *matchingservice.DeleteTaskListTasksRequest) error
 	}
 )
@@ -384,6 +384,314 @@ func (s *matchingEngineSuite) TestAddDecisionTasksForwarded() {
 	s.AddTasksTest(persistence.TaskListTypeDecision, true)
 }
 
+func (s *matchingEngineSuite) TestDecisionTasksRoutedByWorkerBuildID() {
+	domainID := primitives.UUID(uuid.NewRandom())
+	tl := "makeToast"
+	taskList := &commonproto.TaskList{Name: tl}
+
+	entry := cache.NewLocalDomainCacheEntryForTest(
+		&persistence.DomainInfo{
+			ID:   domainID.String(),
+			Name: "domainName",
+			Data: map[string]string{
+				cache.DefaultWorkerBuildIDKey:     "v3",
+				cache.CompatibleWorkerBuildIDsKey: "v2",
+			},
+		},
+		&persistence.DomainConfig{},
+		"",
+		nil)
+	domainCache := cache.NewMockDomainCache(s.controller)
+	domainCache.EXPECT().GetDomainByID(gomock.Any()).Return(entry, nil).AnyTimes()
+	config := defaultTestConfig()
+	config.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(10 * time.Millisecond)
+	engine := newMatchingEngine(config, s.taskManager, s.mockHistoryClient, s.logger, domainCache)
+	engine.Start()
+	defer engine.Stop()
+
+	runID := primitives.UUID(uuid.NewRandom())
+	execution := &commonproto.WorkflowExecution{RunId: runID.String(), WorkflowId: "workflow1"}
+	for i, pinnedBuildID := range []string{"", "v2", "v3", "v1"} {
+		_, err := engine.AddDecisionTask(context.Background(), &matchingservice.AddDecisionTaskRequest{
+			DomainUUID:                    domainID.String(),
+			Execution:                     execution,
+			ScheduleId:                    int64(i),
+			TaskList:                      taskList,
+			ScheduleToStartTimeoutSeconds: 100,
+			PinnedBuildId:                 pinnedBuildID,
+		})
+		s.NoError(err)
+	}
+
+	tlID := newTestTaskListID(domainID.String(), tl, persistence.TaskListTypeDecision)
+	s.EqualValues(0, s.taskManager.getTaskCount(tlID))
+	s.EqualValues(3, s.taskManager.getTaskCount(tlID.withBuildID("v3")))
+	s.EqualValues(1, s.taskManager.getTaskCount(tlID.withBuildID("v1")))
+
+	s.mockHistoryClient.EXPECT().RecordDecisionTaskStarted(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, taskRequest *historyservice.RecordDecisionTaskStartedRequest) (*historyservice.RecordDecisionTaskStartedResponse, error) {
+			s.Equal("v1", taskRequest.GetWorkerBuildId())
+			s.Equal(int64(3), taskRequest.GetScheduleId())
+			return &historyservice.RecordDecisionTaskStartedResponse{
+				WorkflowType:     &commonproto.WorkflowType{Name: "workflow"},
+				ScheduledEventId: taskRequest.GetScheduleId(),
+			}, nil
+		}).Times(1)
+
+	resp, err := engine.PollForDecisionTask(s.callContext, &matchingservice.PollForDecisionTaskRequest{
+		DomainUUID:    domainID.String(),
+		PollRequest:   &workflowservice.PollForDecisionTaskRequest{TaskList: taskList, Identity: "worker"},
+		WorkerBuildId: "v1",
+	})
+	s.NoError(err)
+	s.Equal(execution, resp.GetWorkflowExecution())
+}
+
+func (s *matchingEngineSuite) TestDecisionTaskBacklogDrainedAfterRoutingChanged() {
+	domainID := primitives.UUID(uuid.NewRandom())
+	tl := "makeToast"
+	taskList := &commonproto.TaskList{Name: tl}
+
+	newEntry := func(data map[string]string) *cache.DomainCacheEntry {
+		return cache.NewLocalDomainCacheEntryForTest(
+			&persistence.DomainInfo{ID: domainID.String(), Name: "domainName", Data: data},
+			&persistence.DomainConfig{},
+			"",
+			nil)
+	}
+	var entry atomic.Value
+	entry.Store(newEntry(map[string]string{}))
+	domainCache := cache.NewMockDomainCache(s.controller)
+	domainCache.EXPECT().GetDomainByID(gomock.Any()).DoAndReturn(func(string) (*cache.DomainCacheEntry, error) {
+		return entry.Load().(*cache.DomainCacheEntry), nil
+	}).AnyTimes()
+	config := defaultTestConfig()
+	config.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(10 * time.Millisecond)
+	engine := newMatchingEngine(config, s.taskManager, s.mockHistoryClient, s.logger, domainCache)
+	engine.Start()
+	defer engine.Stop()
+
+	runID := primitives.UUID(uuid.NewRandom())
+	execution := &commonproto.WorkflowExecution{RunId: runID.String(), WorkflowId: "workflow1"}
+	addTask := func(scheduleID int64, pinnedBuildID string) {
+		_, err := engine.AddDecisionTask(context.Background(), &matchingservice.AddDecisionTaskRequest{
+			DomainUUID:                    domainID.String(),
+			Execution:                     execution,
+			ScheduleId:                    scheduleID,
+			TaskList:                      taskList,
+			ScheduleToStartTimeoutSeconds: 100,
+			PinnedBuildId:                 pinnedBuildID,
+		})
+		s.NoError(err)
+	}
+	// added before versioning is enabled and while v1 is still in use
+	addTask(0, "")
+	entry.Store(newEntry(map[string]string{cache.DefaultWorkerBuildIDKey: "v3"}))
+	addTask(1, "v1")
+
+	tlID := newTestTaskListID(domainID.String(), tl, persistence.TaskListTypeDecision)
+	s.EqualValues(1, s.taskManager.getTaskCount(tlID))
+	s.EqualValues(1, s.taskManager.getTaskCount(tlID.withBuildID("v1")))
+
+	// v1 is retired, workers of v3 pick up the backlogs of both task lists
+	entry.Store(newEntry(map[string]string{cache.DefaultWorkerBuildIDKey: "v3", cache.WorkerBuildIDRedirectsKey: "v1=v3"}))
+	s.mockHistoryClient.EXPECT().RecordDecisionTaskStarted(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, taskRequest *historyservice.RecordDecisionTaskStartedRequest) (*historyservice.RecordDecisionTaskStartedResponse, error) {
+			s.Equal("v3", taskRequest.GetWorkerBuildId())
+			return &historyservice.RecordDecisionTaskStartedResponse{
+				WorkflowType:     &commonproto.WorkflowType{Name: "workflow"},
+				ScheduledEventId: taskRequest.GetScheduleId(),
+			}, nil
+		}).Times(2)
+
+	scheduleIDs := make(map[int64]bool)
+	for i := 0; i < 100 && len(scheduleIDs) < 2; i++ {
+		resp, err := engine.PollForDecisionTask(s.callContext, &matchingservice.PollForDecisionTaskRequest{
+			DomainUUID:    domainID.String(),
+			PollRequest:   &workflowservice.PollForDecisionTaskRequest{TaskList: taskList, Identity: "worker"},
+			WorkerBuildId: "v3",
+		})
+		s.NoError(err)
+		if len(resp.TaskToken) == 0 {
+			continue
+		}
+		token, err := engine.tokenSerializer.Deserialize(resp.TaskToken)
+		s.NoError(err)
+		scheduleIDs[token.GetScheduleId()] = true
+	}
+	s.Equal(map[int64]bool{0: true, 1: true}, scheduleIDs)
+}
+
+func (s *matchingEngineSuite) TestActivityTasksBackloggedByPriority() {
+	s.matchingEngine.config.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(10 * time.Millisecond)
+
+	domainID := primitives.UUID(uuid.NewRandom())
+	tl := "makeToast"
+	tlID := newTestTaskListID(domainID.String(), tl, persistence.TaskListTypeActivity)
+	taskList := &commonproto.TaskList{Name: tl}
+
+	runID := primitives.UUID(uuid.NewRandom())
+	execution := &commonproto.WorkflowExecution{RunId: runID.String(), WorkflowId: "workflow1"}
+	for i, priority := range []int32{0, 0, 2} {
+		_, err := s.matchingEngine.AddActivityTask(context.Background(), &matchingservice.AddActivityTaskRequest{
+			SourceDomainUUID:              domainID.String(),
+			DomainUUID:                    domainID.String(),
+			Execution:                     execution,
+			ScheduleId:                    int64(i),
+			TaskList:                      taskList,
+			ScheduleToStartTimeoutSeconds: 100,
+			Priority:                      priority,
+		})
+		s.NoError(err)
+	}
+
+	s.EqualValues(2, s.taskManager.getTaskCount(tlID))
+	s.EqualValues(1, s.taskManager.getTaskCount(tlID.withSubqueue(subqueueKey{priority: 2})))
+	subqueues := s.taskManager.getTaskListManager(tlID).subqueues
+	s.Len(subqueues, 1)
+	s.EqualValues(2, subqueues[0].GetPriority())
+
+	tlKind := enums.TaskListKindNormal
+	tlMgr, err := s.matchingEngine.getTaskListManager(tlID, tlKind)
+	s.NoError(err)
+	// the task of the subqueue waits for its turn ahead of the rest of the backlog
+	s.True(s.awaitCondition(func() bool { return tlMgr.(*taskListManagerImpl).dispatcher.hasPendingAbove(0) }, time.Second))
+
+	s.mockHistoryClient.EXPECT().RecordActivityTaskStarted(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, taskRequest *historyservice.RecordActivityTaskStartedRequest) (*historyservice.RecordActivityTaskStartedResponse, error) {
+			return &historyservice.RecordActivityTaskStartedResponse{
+				ScheduledEvent: newActivityTaskScheduledEvent(taskRequest.ScheduleId, 0,
+					&commonproto.ScheduleActivityTaskDecisionAttributes{
+						ActivityId:                    "activityId1",
+						TaskList:                      taskList,
+						ActivityType:                  &commonproto.ActivityType{Name: "activity1"},
+						ScheduleToCloseTimeoutSeconds: 100,
+						ScheduleToStartTimeoutSeconds: 50,
+						StartToCloseTimeoutSeconds:    50,
+					}),
+			}, nil
+		}).Times(3)
+
+	var scheduleIDs []int64
+	for len(scheduleIDs) < 3 {
+		result, err := s.matchingEngine.PollForActivityTask(s.callContext, &matchingservice.PollForActivityTaskRequest{
+			DomainUUID:  domainID.String(),
+			PollRequest: &workflowservice.PollForActivityTaskRequest{TaskList: taskList, Identity: "nobody"},
+		})
+		s.NoError(err)
+		if len(result.TaskToken) == 0 {
+			continue
+		}
+		token, err := s.matchingEngine.tokenSerializer.Deserialize(result.TaskToken)
+		s.NoError(err)
+		scheduleIDs = append(scheduleIDs, token.GetScheduleId())
+	}
+	// at most the task which already held the turn is dispatched ahead of the higher priority task
+	s.EqualValues(1, scheduleIDs[2])
+}
+
+func (s *matchingEngineSuite) TestListAndDeleteTaskListTasks() {
+	domainID := primitives.UUID(uuid.NewRandom())
+	tl := "makeToast"
+	tlID := newTestTaskListID(domainID.String(), tl, persistence.TaskListTypeActivity)
+	taskList := &commonproto.TaskList{Name: tl}
+	subqueueID := tlID.withSubqueue(subqueueKey{priority: 2})
+
+	runID := primitives.UUID(uuid.NewRandom())
+	execution := &commonproto.WorkflowExecution{RunId: runID.String(), WorkflowId: "workflow1"}
+	for i, priority := range []int32{0, 0, 0, 2, 2} {
+		_, err := s.matchingEngine.AddActivityTask(context.Background(), &matchingservice.AddActivityTaskRequest{
+			SourceDomainUUID:              domainID.String(),
+			DomainUUID:                    domainID.String(),
+			Execution:                     execution,
+			ScheduleId:                    int64(i),
+			TaskList:                      taskList,
+			ScheduleToStartTimeoutSeconds: 100,
+			Priority:                      priority,
+			WorkflowType:                  "workflowType",
+		})
+		s.NoError(err)
+	}
+
+	// pages go through the backlog of the task list first and then through the subqueues
+	var tasks []*matchingservice.BacklogTask
+	var nextPageToken []byte
+	for {
+		resp, err := s.matchingEngine.ListTaskListTasks(context.Background(), &matchingservice.ListTaskListTasksRequest{
+			DomainUUID:      domainID.String(),
+			TaskListType:    persistence.TaskListTypeActivity,
+			TaskList:        taskList,
+			MaximumPageSize: 2,
+			NextPageToken:   nextPageToken,
+		})
+		s.NoError(err)
+		tasks = append(tasks, resp.Tasks...)
+		nextPageToken = resp.NextPageToken
+		if len(nextPageToken) == 0 {
+			break
+		}
+	}
+	s.Len(tasks, 5)
+	for i, task := range tasks {
+		s.EqualValues(i, task.Task.Data.GetScheduleID())
+		s.Equal("workflowType", task.Task.Data.GetWorkflowType())
+	}
+	s.Equal("", tasks[0].Backlog)
+	s.Equal(subqueueID.name, tasks[3].Backlog)
+
+	err := s.matchingEngine.DeleteTaskListTasks(context.Background(), &matchingservice.DeleteTaskListTasksRequest{
+		DomainUUID:   domainID.String(),
+		TaskListType: persistence.TaskListTypeActivity,
+		TaskList:     taskList,
+		Backlog:      tasks[4].Backlog,
+		TaskIds:      []int64{tasks[4].Task.GetTaskID()},
+	})
+	s.NoError(err)
+	s.EqualValues(3, s.taskManager.getTaskCount(tlID))
+	s.EqualValues(1, s.taskManager.getTaskCount(subqueueID))
+
+	err = s.matchingEngine.DeleteTaskListTasks(context.Background(), &matchingservice.DeleteTaskListTasksRequest{
+		DomainUUID:   domainID.String(),
+		TaskListType: persistence.TaskListTypeActivity,
+		TaskList:     taskList,
+		Backlog:      "unknown",
+		TaskIds:      []int64{tasks[0].Task.GetTaskID()},
+	})
+	s.IsType(&serviceerror.NotFound{}, err)
+}
+
+func (s *matchingEngineSuite) TestActivityTasksBackloggedByFairnessKey() {
+	s.matchingEngine.config.EnableFairnessDispatch = dynamicconfig.GetBoolPropertyFnFilteredByTaskListInfo(true)
+	s.matchingEngine.config.MaxFairnessKeys = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(1)
+
+	domainID := primitives.UUID(uuid.NewRandom())
+	tl := "makeToast"
+	tlID := newTestTaskListID(domainID.String(), tl, persistence.TaskListTypeActivity)
+	taskList := &commonproto.TaskList{Name: tl}
+
+	runID := primitives.UUID(uuid.NewRandom())
+	execution := &commonproto.WorkflowExecution{RunId: runID.String(), WorkflowId: "workflow1"}
+	for i, fairnessKey := range []string{"", "tenantA", "tenantA", "tenantB"} {
+		_, err := s.matchingEngine.AddActivityTask(context.Background(), &matchingservice.AddActivityTaskRequest{
+			SourceDomainUUID:              domainID.String(),
+			DomainUUID:                    domainID.String(),
+			Execution:                     execution,
+			ScheduleId:                    int64(i),
+			TaskList:                      taskList,
+			ScheduleToStartTimeoutSeconds: 100,
+			FairnessKey:                   fairnessKey,
+		})
+		s.NoError(err)
+	}
+
+	// tenantB is over the limit of fairness keys and shares the backlog of the task list
+	s.EqualValues(2, s.taskManager.getTaskCount(tlID))
+	s.EqualValues(2, s.taskManager.getTaskCount(tlID.withSubqueue(subqueueKey{fairnessKey: "tenantA"})))
+	subqueues := s.taskManager.getTaskListManager(tlID).subqueues
+	s.Len(subqueues, 1)
+	s.Equal("tenantA", subqueues[0].GetFairnessKey())
+}
+
 func (s *matchingEngineSuite) AddTasksTest(taskType int32, isForwarded bool) {
 	s.matchingEngine.config.RangeSize = 300 // override to low number for the test
 
@@ -1732,6 +2040,9 @@ type testTaskListManager struct {
 	sync.Mutex
 	rangeID         int64
 	ackLevel        int64
+	dispatchConfig  *persistenceblobs.TaskListDispatchConfig
+	partitionState  *persistenceblobs.TaskListPartitionState
+	subqueues       []*persistenceblobs.TaskListSubqueue
 	createTaskCount int
 	tasks           *treemap.Map
 }
@@ -1772,11 +2083,14 @@ func (m *testTaskManager) LeaseTaskList(request *persistence.LeaseTaskListReques
 	return &persistence.LeaseTaskListResponse{
 		TaskListInfo: &persistence.PersistedTaskListInfo{
 			Data: &persistenceblobs.TaskListInfo{
-				AckLevel: tlm.ackLevel,
-				DomainID: request.DomainID,
-				Name:     request.TaskList,
-				TaskType: request.TaskType,
-				Kind:     request.TaskListKind,
+				AckLevel:       tlm.ackLevel,
+				DomainID:       request.DomainID,
+				Name:           request.TaskList,
+				TaskType:       request.TaskType,
+				Kind:           request.TaskListKind,
+				DispatchConfig: tlm.dispatchConfig,
+				PartitionState: tlm.partitionState,
+				Subqueues:      tlm.subqueues,
 			},
 			RangeID: tlm.rangeID,
 		},
@@ -1798,6 +2112,9 @@ func (m *testTaskManager) UpdateTaskList(request *persistence.UpdateTaskListRequ
 		}
 	}
 	tlm.ackLevel = tli.AckLevel
+	tlm.dispatchConfig = tli.DispatchConfig
+	tlm.partitionState = tli.PartitionState
+	tlm.subqueues = tli.Subqueues
 	return &persistence.UpdateTaskListResponse{}, nil
 }
 
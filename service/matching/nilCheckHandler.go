@@ -120,3 +120,29 @@ func (h *NilCheckHandler) ListTaskListPartitions(ctx context.Context, request *m
 	}
 	return resp, err
 }
+
+func (h *NilCheckHandler) UpdateTaskListDispatchConfig(ctx context.Context, request *matchingservice.UpdateTaskListDispatchConfigRequest) (*matchingservice.UpdateTaskListDispatchConfigResponse, error) {
+	resp, err := h.parentHandler.UpdateTaskListDispatchConfig(ctx, request)
+	if resp == nil && err == nil {
+		resp = &matchingservice.UpdateTaskListDispatchConfigResponse{}
+	}
+	return resp, err
+}
+
+// ListTaskListTasks returns the backlog tasks of a task list partition and its subqueues page by page
+func (h *NilCheckHandler) ListTaskListTasks(ctx context.Context, request *matchingservice.ListTaskListTasksRequest) (*matchingservice.ListTaskListTasksResponse, error) {
+	resp, err := h.parentHandler.ListTaskListTasks(ctx, request)
+	if resp == nil && err == nil {
+		resp = &matchingservice.ListTaskListTasksResponse{}
+	}
+	return resp, err
+}
+
+// DeleteTaskListTasks drops tasks from the backlog of a task list partition or one of its subqueues
+func (h *NilCheckHandler) DeleteTaskListTasks(ctx context.Context, request *matchingservice.DeleteTaskListTasksRequest) (*matchingservice.DeleteTaskListTasksResponse, error) {
+	resp, err := h.parentHandler.DeleteTaskListTasks(ctx, request)
+	if resp == nil && err == nil {
+		resp = &matchingservice.DeleteTaskListTasksResponse{}
+	}
+	return resp, err
+}
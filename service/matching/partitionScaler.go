@@ -0,0 +1,351 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/workflowservice"
+
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/clock"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+)
+
+const (
+	// addRateWindow is the window over which the add task rate of a partition is computed
+	addRateWindow = time.Minute
+	// describePartitionTimeout is the timeout for collecting the load of a single partition
+	describePartitionTimeout = 5 * time.Second
+)
+
+type (
+	// taskListLoad is the load of all partitions of a task list as seen by the root partition
+	taskListLoad struct {
+		addRate float64
+		// backlog of every read partition, indexed by partition number
+		backlogs []int64
+		// number of distinct pollers across all partitions
+		pollers int
+	}
+
+	// partitionScaler runs on the root partition of a task list and sizes the number of
+	// read and write partitions from the add rate, backlog and pollers of all partitions.
+	// Partitions are added to reads and writes at once. When scaling down, the partition
+	// is first removed from the write partitions and stays readable until clients have
+	// refreshed their partition config and its backlog is drained. Every decision is
+	// persisted with the root partition before it is applied, so a new owner of the root
+	// partition resumes from it and never drops partitions that are still draining.
+	partitionScaler struct {
+		tlMgr      *taskListManagerImpl
+		config     *taskListConfig
+		timeSource clock.TimeSource
+
+		// only updated by the scale loop, reads from other goroutines need the lock
+		sync.RWMutex
+		// zero until the first scaling decision, the configured partitions are used until then
+		numRead    int
+		numWrite   int
+		drainStart time.Time
+
+		shutdownCh chan struct{}
+	}
+
+	// addRateTracker approximates the rate of tasks added to a partition over a sliding window
+	addRateTracker struct {
+		sync.Mutex
+		timeSource  clock.TimeSource
+		windowStart time.Time
+		count       int64
+		prevCount   int64
+	}
+)
+
+func newPartitionScaler(tlMgr *taskListManagerImpl, timeSource clock.TimeSource) *partitionScaler {
+	return &partitionScaler{
+		tlMgr:      tlMgr,
+		config:     tlMgr.config,
+		timeSource: timeSource,
+		shutdownCh: make(chan struct{}),
+	}
+}
+
+func (s *partitionScaler) start() {
+	go s.scaleLoop()
+}
+
+func (s *partitionScaler) stop() {
+	close(s.shutdownCh)
+}
+
+// load restores the partitions persisted with the root partition, it must be called before start
+func (s *partitionScaler) load(state *persistenceblobs.TaskListPartitionState) {
+	drainStart := time.Time{}
+	if state.GetDrainStartTime() != nil {
+		if t, err := types.TimestampFromProto(state.GetDrainStartTime()); err == nil {
+			drainStart = t
+		}
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.numRead = int(state.GetNumReadPartitions())
+	s.numWrite = int(state.GetNumWritePartitions())
+	s.drainStart = drainStart
+}
+
+// partitionConfig returns the current number of read and write partitions
+// of the task list, they never go below the configured number of partitions
+func (s *partitionScaler) partitionConfig() (int, int) {
+	numRead := s.config.NumReadPartitions()
+	numWrite := s.config.NumWritePartitions()
+	if !s.config.EnablePartitionAutoScaling() {
+		return numRead, numWrite
+	}
+	s.RLock()
+	defer s.RUnlock()
+	return common.MaxInt(numRead, s.numRead), common.MaxInt(numWrite, s.numWrite)
+}
+
+func (s *partitionScaler) scaleLoop() {
+	timer := time.NewTimer(s.config.PartitionAutoScalingInterval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			return
+		case <-timer.C:
+			timer.Reset(s.config.PartitionAutoScalingInterval())
+			if !s.config.EnablePartitionAutoScaling() {
+				s.reset()
+				continue
+			}
+			numRead, _ := s.partitionConfig()
+			load, err := s.collectLoad(numRead)
+			if err != nil {
+				// never scale on partial information
+				s.tlMgr.logger.Warn("Failed to collect task list partition load", tag.Error(err))
+				continue
+			}
+			s.scale(load)
+		}
+	}
+}
+
+// scale applies a single scaling decision for the given load
+func (s *partitionScaler) scale(load *taskListLoad) {
+	numRead, numWrite := s.partitionConfig()
+	desired := s.desiredPartitions(load)
+	now := s.timeSource.Now()
+	drainStart := s.drainStart
+
+	switch {
+	case desired > numWrite:
+		// this also cancels an ongoing drain, partitions that were
+		// drained but not removed yet become writable again
+		numWrite = desired
+		numRead = common.MaxInt(numRead, desired)
+		drainStart = time.Time{}
+	case numRead > numWrite:
+		if s.isDrained(load, numWrite, numRead, drainStart, now) {
+			numRead = numWrite
+			drainStart = time.Time{}
+		}
+	case desired < numWrite:
+		// scale down one partition at a time, the next one is
+		// only removed once this one is fully drained
+		numWrite--
+		drainStart = now
+	}
+
+	if numRead != s.numRead || numWrite != s.numWrite || !drainStart.Equal(s.drainStart) {
+		if err := s.update(numRead, numWrite, drainStart); err != nil {
+			// keep the current partitions, the decision is retried on the next interval
+			s.tlMgr.logger.Warn("Failed to persist task list partitions", tag.Error(err))
+			return
+		}
+		s.tlMgr.logger.Info("Scaled task list partitions",
+			tag.TaskListReadPartitions(numRead), tag.TaskListWritePartitions(numWrite))
+	}
+
+	scope := s.tlMgr.domainScope()
+	scope.UpdateGauge(metrics.TaskListReadPartitionsGauge, float64(numRead))
+	scope.UpdateGauge(metrics.TaskListWritePartitionsGauge, float64(numWrite))
+}
+
+// desiredPartitions returns the number of partitions needed for the add rate and
+// backlog, limited by the number of pollers since every partition needs pollers
+func (s *partitionScaler) desiredPartitions(load *taskListLoad) int {
+	var backlog int64
+	for _, b := range load.backlogs {
+		backlog += b
+	}
+	desired := common.MaxInt(
+		int(math.Ceil(load.addRate/float64(s.config.PartitionTargetAddRate()))),
+		int(math.Ceil(float64(backlog)/float64(s.config.PartitionTargetBacklog()))),
+	)
+	desired = common.MinInt(desired, load.pollers/s.config.MinPollersPerPartition())
+	desired = common.MinInt(desired, s.config.MaxTasklistPartitions())
+	return common.MaxInt(desired, s.config.NumWritePartitions())
+}
+
+// isDrained returns true when the partitions that were removed from the write partitions
+// no longer receive tasks from clients with a stale partition config and have no backlog
+func (s *partitionScaler) isDrained(load *taskListLoad, numWrite int, numRead int, drainStart time.Time, now time.Time) bool {
+	if now.Sub(drainStart) < s.config.PartitionConfigRefreshInterval() {
+		return false
+	}
+	for i := numWrite; i < numRead; i++ {
+		if i >= len(load.backlogs) || load.backlogs[i] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *partitionScaler) reset() {
+	if s.numRead == 0 && s.numWrite == 0 && s.drainStart.IsZero() {
+		return
+	}
+	if err := s.update(0, 0, time.Time{}); err != nil {
+		s.tlMgr.logger.Warn("Failed to persist task list partitions", tag.Error(err))
+	}
+}
+
+// update persists the given partitions with the root partition and then applies them
+func (s *partitionScaler) update(numRead int, numWrite int, drainStart time.Time) error {
+	state := &persistenceblobs.TaskListPartitionState{
+		NumReadPartitions:  int32(numRead),
+		NumWritePartitions: int32(numWrite),
+	}
+	if !drainStart.IsZero() {
+		drainStartTime, err := types.TimestampProto(drainStart)
+		if err != nil {
+			return err
+		}
+		state.DrainStartTime = drainStartTime
+	}
+	_, err := s.tlMgr.executeWithRetry(func() (interface{}, error) {
+		return nil, s.tlMgr.db.UpdatePartitionState(state)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.numRead = numRead
+	s.numWrite = numWrite
+	s.drainStart = drainStart
+	return nil
+}
+
+func (s *partitionScaler) collectLoad(numRead int) (*taskListLoad, error) {
+	load := &taskListLoad{backlogs: make([]int64, numRead)}
+	pollers := make(map[string]struct{})
+	for i := 0; i < numRead; i++ {
+		resp, err := s.describePartition(i)
+		if err != nil {
+			return nil, err
+		}
+		load.addRate += resp.GetAddRatePerSecond()
+		load.backlogs[i] = resp.GetTaskListStatus().GetBacklogCountHint()
+		for _, poller := range resp.GetPollers() {
+			pollers[poller.GetIdentity()] = struct{}{}
+		}
+	}
+	load.pollers = len(pollers)
+	return load, nil
+}
+
+func (s *partitionScaler) describePartition(partition int) (*matchingservice.DescribeTaskListResponse, error) {
+	if partition == 0 {
+		return s.tlMgr.DescribeTaskList(true), nil
+	}
+
+	taskListType := enums.TaskListTypeDecision
+	if s.tlMgr.taskListID.taskType == persistence.TaskListTypeActivity {
+		taskListType = enums.TaskListTypeActivity
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), describePartitionTimeout)
+	defer cancel()
+	return s.tlMgr.engine.matchingClient.DescribeTaskList(ctx, &matchingservice.DescribeTaskListRequest{
+		DomainUUID: s.tlMgr.taskListID.domainID,
+		DescRequest: &workflowservice.DescribeTaskListRequest{
+			TaskList: &commonproto.TaskList{
+				Name: s.tlMgr.taskListID.mkName(partition),
+				Kind: enums.TaskListKindNormal,
+			},
+			TaskListType:          taskListType,
+			IncludeTaskListStatus: true,
+		},
+	})
+}
+
+func newAddRateTracker(timeSource clock.TimeSource) *addRateTracker {
+	return &addRateTracker{
+		timeSource:  timeSource,
+		windowStart: timeSource.Now(),
+	}
+}
+
+func (t *addRateTracker) record() {
+	t.Lock()
+	defer t.Unlock()
+	t.advance(t.timeSource.Now())
+	t.count++
+}
+
+// rate returns the add rate per second, the count of the previous window
+// is weighted by how much of it still overlaps with the sliding window
+func (t *addRateTracker) rate() float64 {
+	t.Lock()
+	defer t.Unlock()
+	now := t.timeSource.Now()
+	t.advance(now)
+	overlap := float64(addRateWindow-now.Sub(t.windowStart)) / float64(addRateWindow)
+	return (float64(t.prevCount)*overlap + float64(t.count)) / addRateWindow.Seconds()
+}
+
+func (t *addRateTracker) advance(now time.Time) {
+	elapsed := now.Sub(t.windowStart)
+	if elapsed < addRateWindow {
+		return
+	}
+	if elapsed < 2*addRateWindow {
+		t.prevCount = t.count
+		t.windowStart = t.windowStart.Add(addRateWindow)
+	} else {
+		t.prevCount = 0
+		t.windowStart = now
+	}
+	t.count = 0
+}
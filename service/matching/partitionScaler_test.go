@@ -0,0 +1,128 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/temporalio/temporal/common/clock"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+)
+
+func TestPartitionScalerScaleUpAndDrain(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.EnablePartitionAutoScaling = dynamicconfig.GetBoolPropertyFnFilteredByTaskListInfo(true)
+	cfg.PartitionTargetAddRate = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(100)
+	cfg.PartitionTargetBacklog = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(1000)
+	cfg.MaxTasklistPartitions = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(8)
+	cfg.PartitionConfigRefreshInterval = dynamicconfig.GetDurationPropertyFn(30 * time.Second)
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	scaler := tlm.partitionScaler
+	require.NotNil(t, scaler)
+	scaler.timeSource = timeSource
+
+	assertPartitions := func(numRead int, numWrite int) {
+		read, write := scaler.partitionConfig()
+		require.Equal(t, numRead, read)
+		require.Equal(t, numWrite, write)
+		partitionConfig := tlm.DescribeTaskList(false).GetPartitionConfig()
+		require.EqualValues(t, numRead, partitionConfig.GetNumReadPartitions())
+		require.EqualValues(t, numWrite, partitionConfig.GetNumWritePartitions())
+	}
+	assertPartitions(1, 1)
+
+	// scale up for the add rate
+	scaler.scale(&taskListLoad{addRate: 350, backlogs: []int64{0}, pollers: 10})
+	assertPartitions(4, 4)
+
+	// scale up for the backlog, limited by the pollers
+	scaler.scale(&taskListLoad{addRate: 100, backlogs: []int64{5000, 5000, 0, 0}, pollers: 6})
+	assertPartitions(6, 6)
+
+	// scale down removes a single write partition and keeps it readable
+	idleLoad := &taskListLoad{addRate: 10, backlogs: make([]int64, 6), pollers: 10}
+	scaler.scale(idleLoad)
+	assertPartitions(6, 5)
+
+	// clients may still use the old config
+	scaler.scale(idleLoad)
+	assertPartitions(6, 5)
+
+	// a new owner of the root partition resumes the drain from the persisted partitions
+	state, err := tlm.db.RenewLease()
+	require.NoError(t, err)
+	restored := newPartitionScaler(tlm, timeSource)
+	restored.load(state.partitionState)
+	require.Equal(t, scaler.numRead, restored.numRead)
+	require.Equal(t, scaler.numWrite, restored.numWrite)
+	require.True(t, scaler.drainStart.Equal(restored.drainStart))
+
+	// the removed partition still has backlog
+	timeSource.Update(timeSource.Now().Add(31 * time.Second))
+	scaler.scale(&taskListLoad{addRate: 10, backlogs: []int64{0, 0, 0, 0, 0, 3}, pollers: 10})
+	assertPartitions(6, 5)
+
+	scaler.scale(idleLoad)
+	assertPartitions(5, 5)
+
+	// scale up again while draining makes the partition writable again
+	scaler.scale(&taskListLoad{addRate: 10, backlogs: make([]int64, 5), pollers: 10})
+	assertPartitions(5, 4)
+	scaler.scale(&taskListLoad{addRate: 500, backlogs: make([]int64, 5), pollers: 10})
+	assertPartitions(5, 5)
+
+	// never below the configured partitions and back to the configured ones when disabled
+	cfg.NumTasklistReadPartitions = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(7)
+	cfg.NumTasklistWritePartitions = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(7)
+	assertPartitions(7, 7)
+	cfg.NumTasklistReadPartitions = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(2)
+	cfg.NumTasklistWritePartitions = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(1)
+	cfg.EnablePartitionAutoScaling = dynamicconfig.GetBoolPropertyFnFilteredByTaskListInfo(false)
+	assertPartitions(2, 1)
+}
+
+func TestAddRateTracker(t *testing.T) {
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	tracker := newAddRateTracker(timeSource)
+	require.Zero(t, tracker.rate())
+
+	for i := 0; i < 60; i++ {
+		tracker.record()
+	}
+	require.InDelta(t, 1.0, tracker.rate(), 0.001)
+
+	timeSource.Update(timeSource.Now().Add(addRateWindow))
+	require.InDelta(t, 1.0, tracker.rate(), 0.001)
+
+	timeSource.Update(timeSource.Now().Add(addRateWindow / 2))
+	require.InDelta(t, 0.5, tracker.rate(), 0.001)
+
+	timeSource.Update(timeSource.Now().Add(2 * addRateWindow))
+	require.Zero(t, tracker.rate())
+}
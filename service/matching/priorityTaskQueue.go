@@ -0,0 +1,118 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+)
+
+type (
+	// priorityTaskQueue orders the backlog tasks waiting for their turn by priority. Every priority
+	// has its own queue and the queues are served by smooth weighted round robin,
+	// the weight of a priority is weightRatio times the weight of the next lower one.
+	// Higher priorities are dispatched first while lower priorities still get their
+	// share of dispatch and are never starved. When fairness dispatch is enabled, the
+	// tasks of a priority are further shared among their fairness keys, otherwise they
+	// are dispatched in FIFO order. Not safe for concurrent use.
+	priorityTaskQueue struct {
+		config *taskListConfig
+		queues [common.MaxTaskPriority + 1]*fairTaskQueue
+		// current weights of the smooth weighted round robin
+		current [common.MaxTaskPriority + 1]int
+		size    int
+	}
+)
+
+func newPriorityTaskQueue(config *taskListConfig) *priorityTaskQueue {
+	q := &priorityTaskQueue{config: config}
+	for i := range q.queues {
+		q.queues[i] = newFairTaskQueue()
+	}
+	return q
+}
+
+func (q *priorityTaskQueue) add(task *waitingTask) {
+	key := ""
+	if q.config.EnableFairnessDispatch() {
+		key = task.event.Data.GetFairnessKey()
+	}
+	q.queues[taskPriority(task.event.Data)].add(key, task)
+	q.size++
+}
+
+// next removes and returns the task to dispatch next, or nil if the queue is empty
+func (q *priorityTaskQueue) next() *waitingTask {
+	if q.size == 0 {
+		return nil
+	}
+
+	ratio := common.MaxInt(1, q.config.TaskPriorityWeightRatio())
+	total := 0
+	selected := -1
+	weight := 1
+	for priority := range q.queues {
+		if q.queues[priority].len() > 0 {
+			q.current[priority] += weight
+			total += weight
+			// ties go to the higher priority
+			if selected < 0 || q.current[priority] >= q.current[selected] {
+				selected = priority
+			}
+		} else {
+			q.current[priority] = 0
+		}
+		weight *= ratio
+	}
+	q.current[selected] -= total
+
+	q.size--
+	return q.queues[selected].next(q.fairnessKeyWeight())
+}
+
+// fairnessKeyWeight returns the weight function of fairness keys, which reads
+// the dynamic config once per dispatched task at most
+func (q *priorityTaskQueue) fairnessKeyWeight() func(key string) int {
+	var weights map[string]int
+	defaultWeight := 1
+	return func(key string) int {
+		if weights == nil {
+			weights = q.config.FairnessKeyWeights()
+			defaultWeight = common.MaxInt(1, q.config.FairnessKeyDefaultWeight())
+		}
+		if weight, ok := weights[key]; ok {
+			return weight
+		}
+		return defaultWeight
+	}
+}
+
+func (q *priorityTaskQueue) len() int {
+	return q.size
+}
+
+func taskPriority(taskInfo *persistenceblobs.TaskInfo) int32 {
+	priority := taskInfo.GetPriority()
+	if priority < common.DefaultTaskPriority || priority > common.MaxTaskPriority {
+		return common.DefaultTaskPriority
+	}
+	return priority
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/temporal-proto/enums"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+)
+
+func TestPriorityTaskQueue_FIFOWithinPriority(t *testing.T) {
+	q := newPriorityTaskQueue(newPriorityTestConfig(false, nil))
+	require.Nil(t, q.next())
+
+	for i := int64(1); i <= 3; i++ {
+		q.add(newPriorityTestTask(i, 0))
+	}
+	require.Equal(t, 3, q.len())
+	for i := int64(1); i <= 3; i++ {
+		require.Equal(t, i, q.next().event.GetTaskID())
+	}
+	require.Equal(t, 0, q.len())
+	require.Nil(t, q.next())
+}
+
+func TestPriorityTaskQueue_WeightedByPriority(t *testing.T) {
+	q := newPriorityTaskQueue(newPriorityTestConfig(false, nil))
+	for i := int64(1); i <= 6; i++ {
+		q.add(newPriorityTestTask(i, 0))
+		q.add(newPriorityTestTask(10+i, 1))
+	}
+
+	var dispatched []int64
+	for q.len() > 0 {
+		dispatched = append(dispatched, q.next().event.GetTaskID())
+	}
+	// priority 1 gets twice the share of priority 0 while both have tasks
+	require.Equal(t, []int64{11, 1, 12, 13, 2, 14, 15, 3, 16, 4, 5, 6}, dispatched)
+}
+
+func TestPriorityTaskQueue_LowPriorityNotStarved(t *testing.T) {
+	q := newPriorityTaskQueue(newPriorityTestConfig(false, nil))
+	q.add(newPriorityTestTask(1, 0))
+	for i := int64(100); i < 200; i++ {
+		q.add(newPriorityTestTask(i, 4))
+	}
+
+	// the weight of priority 4 is 16 times the weight of priority 0
+	for i := 0; i < 17; i++ {
+		if q.next().event.GetTaskID() == 1 {
+			return
+		}
+	}
+	require.Fail(t, "low priority task was not dispatched")
+}
+
+func TestPriorityTaskQueue_InvalidPriority(t *testing.T) {
+	q := newPriorityTaskQueue(newPriorityTestConfig(false, nil))
+	q.add(newPriorityTestTask(1, -1))
+	q.add(newPriorityTestTask(2, 100))
+	require.Equal(t, int64(1), q.next().event.GetTaskID())
+	require.Equal(t, int64(2), q.next().event.GetTaskID())
+}
+
+func TestPriorityTaskQueue_FairnessWithinPriority(t *testing.T) {
+	q := newPriorityTaskQueue(newPriorityTestConfig(true, nil))
+	for i := int64(1); i <= 3; i++ {
+		q.add(newFairnessTestTask(i, 1, "noisy"))
+	}
+	q.add(newFairnessTestTask(4, 1, "quiet"))
+	q.add(newFairnessTestTask(5, 0, "quiet"))
+
+	var dispatched []int64
+	for q.len() > 0 {
+		dispatched = append(dispatched, q.next().event.GetTaskID())
+	}
+	require.Equal(t, []int64{1, 5, 4, 2, 3}, dispatched)
+}
+
+func TestPriorityTaskQueue_FairnessDisabled(t *testing.T) {
+	q := newPriorityTaskQueue(newPriorityTestConfig(false, nil))
+	for i := int64(1); i <= 3; i++ {
+		q.add(newFairnessTestTask(i, 0, "noisy"))
+	}
+	q.add(newFairnessTestTask(4, 0, "quiet"))
+	for i := int64(1); i <= 4; i++ {
+		require.Equal(t, i, q.next().event.GetTaskID())
+	}
+}
+
+func newPriorityTestConfig(enableFairness bool, fairnessKeyWeights map[string]int) *taskListConfig {
+	return &taskListConfig{
+		TaskPriorityWeightRatio:  func() int { return 2 },
+		EnableFairnessDispatch:   func() bool { return enableFairness },
+		FairnessKeyWeights:       func() map[string]int { return fairnessKeyWeights },
+		FairnessKeyDefaultWeight: func() int { return 1 },
+	}
+}
+
+func newFairnessTestTask(taskID int64, priority int32, fairnessKey string) *waitingTask {
+	task := newPriorityTestTask(taskID, priority)
+	task.event.Data.FairnessKey = fairnessKey
+	return task
+}
+
+func newPriorityTestTask(taskID int64, priority int32) *waitingTask {
+	info := &persistenceblobs.AllocatedTaskInfo{
+		Data:   &persistenceblobs.TaskInfo{Priority: priority},
+		TaskID: taskID,
+	}
+	return &waitingTask{internalTask: newInternalTask(info, nil, enums.TaskSourceDbBacklog, "", false)}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
+)
+
+type (
+	// backlogTasksPageToken is the page token of ListTasks, pages go through the backlog
+	// of the task list first and then through the backlogs of its subqueues by name
+	backlogTasksPageToken struct {
+		Backlog   string
+		ReadLevel int64
+	}
+)
+
+var (
+	errInvalidBacklogPageSize  = serviceerror.NewInvalidArgument("Page size must be positive.")
+	errInvalidBacklogPageToken = serviceerror.NewInvalidArgument("Invalid next page token.")
+	errBacklogNotFound         = serviceerror.NewNotFound("Backlog of the task list not found, it may have been drained.")
+)
+
+// ListTasks returns a page of the backlog of the task list and its subqueues, the
+// tasks of each backlog are listed in task ID order starting at its ack level
+func (c *taskListManagerImpl) ListTasks(pageSize int, nextPageToken []byte) ([]*matchingservice.BacklogTask, []byte, error) {
+	if pageSize <= 0 {
+		return nil, nil, errInvalidBacklogPageSize
+	}
+
+	backlogs := c.backlogs()
+	i := 0
+	readLevel := c.taskAckManager.getAckLevel()
+	if len(nextPageToken) != 0 {
+		token := &backlogTasksPageToken{}
+		if err := json.Unmarshal(nextPageToken, token); err != nil {
+			return nil, nil, errInvalidBacklogPageToken
+		}
+		// subqueues drained since the previous page are skipped
+		i = sort.Search(len(backlogs), func(i int) bool { return backlogs[i].backlogName() >= token.Backlog })
+		if i < len(backlogs) {
+			readLevel = backlogs[i].taskAckManager.getAckLevel()
+			if backlogs[i].backlogName() == token.Backlog {
+				readLevel = token.ReadLevel
+			}
+		}
+	}
+
+	var tasks []*matchingservice.BacklogTask
+	for i < len(backlogs) {
+		backlog := backlogs[i]
+		batchSize := pageSize - len(tasks)
+		resp, err := backlog.db.GetTasks(readLevel, math.MaxInt64, batchSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, task := range resp.Tasks {
+			tasks = append(tasks, &matchingservice.BacklogTask{Backlog: backlog.backlogName(), Task: task})
+		}
+
+		if len(resp.Tasks) == batchSize {
+			token, err := json.Marshal(&backlogTasksPageToken{
+				Backlog:   backlog.backlogName(),
+				ReadLevel: resp.Tasks[len(resp.Tasks)-1].GetTaskID(),
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			return tasks, token, nil
+		}
+		i++
+		if i < len(backlogs) {
+			readLevel = backlogs[i].taskAckManager.getAckLevel()
+		}
+	}
+	return tasks, nil, nil
+}
+
+// DeleteTasks drops tasks from the backlog of the task list or one of its subqueues. The
+// task currently offered to pollers, if any, may still be dispatched.
+func (c *taskListManagerImpl) DeleteTasks(backlog string, taskIDs []int64) error {
+	for _, b := range c.backlogs() {
+		if b.backlogName() == backlog {
+			return b.deleteTasks(taskIDs)
+		}
+	}
+	return errBacklogNotFound
+}
+
+func (c *taskListManagerImpl) deleteTasks(taskIDs []int64) error {
+	ackLevel := c.taskAckManager.getAckLevel()
+	c.deletedTasksLock.Lock()
+	if c.deletedTasks == nil {
+		c.deletedTasks = make(map[int64]struct{})
+	}
+	for taskID := range c.deletedTasks {
+		if taskID <= ackLevel {
+			delete(c.deletedTasks, taskID)
+		}
+	}
+	for _, taskID := range taskIDs {
+		if taskID > ackLevel {
+			c.deletedTasks[taskID] = struct{}{}
+		}
+	}
+	c.deletedTasksLock.Unlock()
+
+	for _, taskID := range taskIDs {
+		if taskID <= ackLevel {
+			// already completed
+			continue
+		}
+		if _, err := c.executeWithRetry(func() (interface{}, error) {
+			return nil, c.db.CompleteTask(taskID)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isTaskDeleted returns true when the task has been dropped from the backlog by operators
+func (c *taskListManagerImpl) isTaskDeleted(taskID int64) bool {
+	c.deletedTasksLock.Lock()
+	defer c.deletedTasksLock.Unlock()
+	if _, ok := c.deletedTasks[taskID]; !ok {
+		return false
+	}
+	delete(c.deletedTasks, taskID)
+	return true
+}
+
+// backlogs returns the task list followed by its subqueues ordered by name
+func (c *taskListManagerImpl) backlogs() []*taskListManagerImpl {
+	c.subqueuesLock.RLock()
+	backlogs := make([]*taskListManagerImpl, 0, len(c.subqueues)+1)
+	for _, subqueue := range c.subqueues {
+		backlogs = append(backlogs, subqueue)
+	}
+	c.subqueuesLock.RUnlock()
+
+	sort.Slice(backlogs, func(i, j int) bool {
+		return backlogs[i].backlogName() < backlogs[j].backlogName()
+	})
+	return append([]*taskListManagerImpl{c}, backlogs...)
+}
+
+// backlogName identifies the backlog of a subqueue to operators, the backlog of the task list itself has no name
+func (c *taskListManagerImpl) backlogName() string {
+	if c.parent == nil {
+		return ""
+	}
+	return c.taskListID.name
+}
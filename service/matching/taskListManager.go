@@ -31,6 +31,8 @@ import (
 
 	commonproto "go.temporal.io/temporal-proto/common"
 	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+	"go.temporal.io/temporal-proto/workflowservice"
 
 	"github.com/temporalio/temporal/.gen/proto/matchingservice"
 
@@ -38,6 +40,7 @@ import (
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/backoff"
 	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/clock"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
 	"github.com/temporalio/temporal/common/metrics"
@@ -83,6 +86,12 @@ type (
 		GetAllPollerInfo() []*commonproto.PollerInfo
 		// DescribeTaskList returns information about the target task list
 		DescribeTaskList(includeTaskListStatus bool) *matchingservice.DescribeTaskListResponse
+		// UpdateDispatchConfig persists the dispatch limits set by operators and applies them
+		UpdateDispatchConfig(dispatchConfig *persistenceblobs.TaskListDispatchConfig) error
+		// ListTasks returns a page of the backlog of the task list and its subqueues
+		ListTasks(pageSize int, nextPageToken []byte) ([]*matchingservice.BacklogTask, []byte, error)
+		// DeleteTasks drops tasks from the backlog of the task list or one of its subqueues
+		DeleteTasks(backlog string, taskIDs []int64) error
 		String() string
 	}
 
@@ -112,6 +121,32 @@ type (
 		// prevent tasks being dispatched to zombie pollers.
 		outstandingPollsLock sync.Mutex
 		outstandingPollsMap  map[string]context.CancelFunc
+		// numPollers is the number of polls currently blocked on this task list
+		numPollers int32
+		// dispatchConfigValue holds the *persistenceblobs.TaskListDispatchConfig set by operators,
+		// it is persisted with the root partition and other partitions refresh it from there
+		dispatchConfigValue atomic.Value
+		// rootNumReadPartitions is the number of read partitions last reported by the root
+		// partition, only set on other partitions and zero until the first refresh
+		rootNumReadPartitions int32
+		// addRate tracks tasks added to this partition by clients, forwarded tasks are not counted
+		addRate *addRateTracker
+		// partitionScaler sizes the partitions of the task list, only set on the root partition
+		partitionScaler *partitionScaler
+		// dispatcher takes turns among the backlog tasks of the task list and its subqueues
+		dispatcher *backlogDispatcher
+		// subqueues keep the backlog of tasks of other priorities or fairness keys than the
+		// default ones, so that the backlog of every key is read on its own. Sticky task lists
+		// and subqueues have no subqueues.
+		subqueuesLock sync.RWMutex
+		subqueues     map[subqueueKey]*taskListManagerImpl
+		// parent is the task list the subqueue belongs to, only set on subqueues
+		parent      *taskListManagerImpl
+		subqueueKey subqueueKey
+		// deletedTasks holds the IDs of backlog tasks dropped by operators, so that
+		// the tasks already read into the task buffer are not dispatched
+		deletedTasksLock sync.Mutex
+		deletedTasks     map[int64]struct{}
 
 		shutdownCh chan struct{}  // Delivers stop to the pump that populates taskBuffer
 		startWG    sync.WaitGroup // ensures that background processes do not start until setup is ready
@@ -128,6 +163,10 @@ var _ taskListManager = (*taskListManagerImpl)(nil)
 
 var errRemoteSyncMatchFailed = errors.New("remote sync match failed")
 
+var errTooManyPollers = serviceerror.NewResourceExhausted("Too many outstanding pollers on the TaskList")
+
+var errDispatchConfigOnPartition = serviceerror.NewInvalidArgument("Dispatch config can only be set on the root partition of the TaskList")
+
 func newTaskListManager(
 	e *matchingEngineImpl,
 	taskList *taskListID,
@@ -155,9 +194,15 @@ func newTaskListManager(
 		config:              taskListConfig,
 		pollerHistory:       newPollerHistory(),
 		outstandingPollsMap: make(map[string]context.CancelFunc),
+		addRate:             newAddRateTracker(clock.NewRealTimeSource()),
+		dispatcher:          newBacklogDispatcher(taskListConfig),
 		taskListKind:        int(taskListKind),
 	}
+	if taskListKind != enums.TaskListKindSticky {
+		tlMgr.subqueues = make(map[subqueueKey]*taskListManagerImpl)
+	}
 	tlMgr.domainNameValue.Store("")
+	tlMgr.dispatchConfigValue.Store(&persistenceblobs.TaskListDispatchConfig{})
 	tlMgr.domainScopeValue.Store(e.metricsClient.Scope(metrics.MatchingTaskListMgrScope, metrics.DomainUnknownTag()))
 	tlMgr.tryInitDomainNameAndScope()
 	tlMgr.taskWriter = newTaskWriter(tlMgr)
@@ -167,6 +212,11 @@ func newTaskListManager(
 		fwdr = newForwarder(&taskListConfig.forwarderConfig, taskList, taskListKind, e.matchingClient, tlMgr.domainScope)
 	}
 	tlMgr.matcher = newTaskMatcher(taskListConfig, fwdr, tlMgr.domainScope)
+	tlMgr.matcher.numPartitions = tlMgr.numReadPartitions
+	// versioned task lists share the partitions of the task list they are derived from
+	if taskList.IsRoot() && !taskList.IsVersioned() && taskListKind != enums.TaskListKindSticky {
+		tlMgr.partitionScaler = newPartitionScaler(tlMgr, clock.NewRealTimeSource())
+	}
 	tlMgr.startWG.Add(1)
 	return tlMgr, nil
 }
@@ -184,30 +234,63 @@ func (c *taskListManagerImpl) Start() error {
 	}
 
 	c.taskAckManager.setAckLevel(state.ackLevel)
+	if c.taskListID.IsRoot() {
+		c.setDispatchConfig(state.dispatchConfig)
+	} else {
+		go c.refreshRootConfigLoop()
+	}
 	c.taskWriter.Start(c.rangeIDToTaskIDBlock(state.rangeID))
 	c.taskReader.Start()
+	if err := c.startSubqueues(state.subqueues); err != nil {
+		return err
+	}
+	if c.partitionScaler != nil {
+		c.partitionScaler.load(state.partitionState)
+		c.partitionScaler.start()
+	}
 
 	return nil
 }
 
 // Stops pump that fills up taskBuffer from persistence.
 func (c *taskListManagerImpl) Stop() {
-	if !atomic.CompareAndSwapInt32(&c.stopped, 0, 1) {
+	if c.parent != nil {
+		// subqueues are owned together with their task list
+		c.stop()
+		c.parent.Stop()
 		return
 	}
-	close(c.shutdownCh)
-	c.taskWriter.Stop()
-	c.taskReader.Stop()
+	if !c.stop() {
+		return
+	}
+	c.stopSubqueues()
+	if c.partitionScaler != nil {
+		c.partitionScaler.stop()
+	}
 	c.engine.removeTaskListManager(c.taskListID)
 	c.engine.removeTaskListManager(c.taskListID)
 	c.logger.Info("", tag.LifeCycleStopped)
 }
 
+// stop stops reading and writing the backlog, returns false when already stopped
+func (c *taskListManagerImpl) stop() bool {
+	if !atomic.CompareAndSwapInt32(&c.stopped, 0, 1) {
+		return false
+	}
+	close(c.shutdownCh)
+	c.taskWriter.Stop()
+	c.taskReader.Stop()
+	return true
+}
+
 // AddTask adds a task to the task list. This method will first attempt a synchronous
 // match with a poller. When there are no pollers or if ratelimit is exceeded, task will
 // be written to database and later asynchronously matched with a poller
 func (c *taskListManagerImpl) AddTask(ctx context.Context, params addTaskParams) (bool, error) {
 	c.startWG.Wait()
+	if params.forwardedFrom == "" {
+		c.addRate.record()
+	}
 	var syncMatch bool
 	_, err := c.executeWithRetry(func() (interface{}, error) {
 		td := params.taskInfo
@@ -218,14 +301,17 @@ func (c *taskListManagerImpl) AddTask(ctx context.Context, params addTaskParams)
 		}
 
 		if domainEntry.GetDomainNotActiveErr() != nil {
-			r, err := c.taskWriter.appendTask(params.execution, td)
+			r, err := c.appendTask(params.execution, td)
 			syncMatch = false
 			return r, err
 		}
 
-		syncMatch, err = c.trySyncMatch(ctx, params)
-		if syncMatch {
-			return &persistence.CreateTasksResponse{}, err
+		// backlog tasks of higher priorities go first
+		if !c.dispatcher.hasPendingAbove(taskPriority(td)) {
+			syncMatch, err = c.trySyncMatch(ctx, params)
+			if syncMatch {
+				return &persistence.CreateTasksResponse{}, err
+			}
 		}
 
 		if params.forwardedFrom != "" {
@@ -234,7 +320,7 @@ func (c *taskListManagerImpl) AddTask(ctx context.Context, params addTaskParams)
 			return &persistence.CreateTasksResponse{}, errRemoteSyncMatchFailed
 		}
 
-		return c.taskWriter.appendTask(params.execution, params.taskInfo)
+		return c.appendTask(params.execution, params.taskInfo)
 	})
 	if err == nil {
 		c.taskReader.Signal()
@@ -246,7 +332,10 @@ func (c *taskListManagerImpl) AddTask(ctx context.Context, params addTaskParams)
 // up the task or if rate limit is exceeded, this method will return error. Task
 // *will not* be persisted to db
 func (c *taskListManagerImpl) DispatchTask(ctx context.Context, task *internalTask) error {
-	return c.matcher.MustOffer(ctx, task)
+	if c.parent != nil {
+		return c.parent.DispatchTask(ctx, task)
+	}
+	return c.dispatcher.dispatch(ctx, task, c.matcher.MustOffer)
 }
 
 // DispatchQueryTask will dispatch query to local or remote poller. If forwarded then result or error is returned,
@@ -274,7 +363,7 @@ func (c *taskListManagerImpl) GetTask(
 		return nil, err
 	}
 	task.domainName = c.domainName()
-	task.backlogCountHint = c.taskAckManager.getBacklogCountHint()
+	task.backlogCountHint = c.backlogCountHint()
 	return task, nil
 }
 
@@ -283,6 +372,14 @@ func (c *taskListManagerImpl) getTask(ctx context.Context, maxDispatchPerSecond
 	// reached, instead of emptyTask, context timeout error is returned to the frontend by the rpc stack,
 	// which counts against our SLO. By shortening the timeout by a very small amount, the emptyTask can be
 	// returned to the handler before a context timeout error is generated.
+	dispatchConfig := c.getDispatchConfig()
+	numPollers := atomic.AddInt32(&c.numPollers, 1)
+	defer atomic.AddInt32(&c.numPollers, -1)
+	if maxPollers := c.maxPollersPerPartition(dispatchConfig); maxPollers > 0 && numPollers > maxPollers {
+		c.domainScope().IncCounter(metrics.PollThrottledCounter)
+		return nil, errTooManyPollers
+	}
+
 	childCtx, cancel := c.newChildContext(ctx, c.config.LongPollExpirationInterval(), returnEmptyTaskTimeBudget)
 	defer cancel()
 
@@ -314,8 +411,11 @@ func (c *taskListManagerImpl) getTask(ctx context.Context, maxDispatchPerSecond
 	// poller, which lives inside the client side worker. There is
 	// one rateLimiter for this entire task list and as we get polls,
 	// we update the ratelimiter rps if it has changed from the last
-	// value. Last poller wins if different pollers provide different values
-	c.matcher.UpdateRatelimit(maxDispatchPerSecond)
+	// value. Last poller wins if different pollers provide different values,
+	// unless operators pinned the rate of the task list
+	if !dispatchConfig.GetMaxTasksPerSecondPinned() {
+		c.matcher.UpdateRatelimit(maxDispatchPerSecond)
+	}
 
 	if domainEntry.GetDomainNotActiveErr() != nil {
 		return c.matcher.PollForQuery(childCtx)
@@ -324,6 +424,122 @@ func (c *taskListManagerImpl) getTask(ctx context.Context, maxDispatchPerSecond
 	return c.matcher.Poll(childCtx)
 }
 
+// UpdateDispatchConfig persists the dispatch limits set by operators and applies them, the
+// limits are only set on the root partition and the other partitions pick them up from there
+func (c *taskListManagerImpl) UpdateDispatchConfig(dispatchConfig *persistenceblobs.TaskListDispatchConfig) error {
+	if !c.taskListID.IsRoot() {
+		return errDispatchConfigOnPartition
+	}
+	c.startWG.Wait()
+	if dispatchConfig == nil {
+		dispatchConfig = &persistenceblobs.TaskListDispatchConfig{}
+	}
+	_, err := c.executeWithRetry(func() (interface{}, error) {
+		return nil, c.db.UpdateDispatchConfig(dispatchConfig)
+	})
+	if err != nil {
+		return err
+	}
+	c.setDispatchConfig(dispatchConfig)
+	return nil
+}
+
+// setDispatchConfig applies the given dispatch limits when they changed, a cleared rate
+// restores the default rate until the next poller updates it
+func (c *taskListManagerImpl) setDispatchConfig(dispatchConfig *persistenceblobs.TaskListDispatchConfig) {
+	if dispatchConfig == nil {
+		dispatchConfig = &persistenceblobs.TaskListDispatchConfig{}
+	}
+	previous := c.getDispatchConfig()
+	if previous.Equal(dispatchConfig) {
+		return
+	}
+	c.dispatchConfigValue.Store(dispatchConfig)
+	if rps := dispatchConfig.GetMaxTasksPerSecond(); rps > 0 {
+		c.matcher.SetRatelimit(rps)
+	} else if previous.GetMaxTasksPerSecond() > 0 {
+		c.matcher.ResetRatelimit()
+	}
+}
+
+func (c *taskListManagerImpl) getDispatchConfig() *persistenceblobs.TaskListDispatchConfig {
+	return c.dispatchConfigValue.Load().(*persistenceblobs.TaskListDispatchConfig)
+}
+
+// maxPollersPerPartition divides the max concurrent pollers of the task list
+// equally across its partitions, it returns 0 when pollers are not limited
+func (c *taskListManagerImpl) maxPollersPerPartition(dispatchConfig *persistenceblobs.TaskListDispatchConfig) int32 {
+	maxPollers := dispatchConfig.GetMaxConcurrentPollers()
+	if maxPollers <= 0 {
+		return 0
+	}
+	nPartitions := int32(c.numReadPartitions())
+	if nPartitions <= 1 {
+		return maxPollers
+	}
+	return (maxPollers + nPartitions - 1) / nPartitions
+}
+
+// numReadPartitions returns the number of read partitions of the task list, including the ones
+// added by auto scaling. Partitions other than the root use the number last reported by the root.
+func (c *taskListManagerImpl) numReadPartitions() int {
+	if c.partitionScaler != nil {
+		numRead, _ := c.partitionScaler.partitionConfig()
+		return numRead
+	}
+	if numRead := atomic.LoadInt32(&c.rootNumReadPartitions); numRead > 0 {
+		return int(numRead)
+	}
+	return c.config.NumReadPartitions()
+}
+
+// refreshRootConfigLoop keeps the dispatch config and the number of read partitions of a
+// partition other than the root in sync with the root partition, which owns both
+func (c *taskListManagerImpl) refreshRootConfigLoop() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-c.shutdownCh:
+			return
+		case <-timer.C:
+			if err := c.refreshRootConfig(); err != nil {
+				c.logger.Warn("Failed to refresh the config of the root partition", tag.Error(err))
+			}
+			timer.Reset(c.config.PartitionConfigRefreshInterval())
+		}
+	}
+}
+
+func (c *taskListManagerImpl) refreshRootConfig() error {
+	taskListType := enums.TaskListTypeDecision
+	if c.taskListID.taskType == persistence.TaskListTypeActivity {
+		taskListType = enums.TaskListTypeActivity
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), describePartitionTimeout)
+	defer cancel()
+	resp, err := c.engine.matchingClient.DescribeTaskList(ctx, &matchingservice.DescribeTaskListRequest{
+		DomainUUID: c.taskListID.domainID,
+		DescRequest: &workflowservice.DescribeTaskListRequest{
+			TaskList: &commonproto.TaskList{
+				Name: c.taskListID.GetRoot(),
+				Kind: enums.TaskListKindNormal,
+			},
+			TaskListType: taskListType,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if numRead := resp.GetPartitionConfig().GetNumReadPartitions(); numRead > 0 {
+		atomic.StoreInt32(&c.rootNumReadPartitions, numRead)
+	}
+	c.setDispatchConfig(resp.GetDispatchConfig())
+	return nil
+}
+
 // GetAllPollerInfo returns all pollers that polled from this tasklist in last few minutes
 func (c *taskListManagerImpl) GetAllPollerInfo() []*commonproto.PollerInfo {
 	return c.pollerHistory.getAllPollerInfo()
@@ -340,19 +556,33 @@ func (c *taskListManagerImpl) CancelPoller(pollerID string) {
 }
 
 // DescribeTaskList returns information about the target tasklist, right now this API returns the
-// pollers which polled this tasklist in last few minutes and status of tasklist's ackManager
-// (readLevel, ackLevel, backlogCountHint and taskIDBlock).
+// pollers which polled this tasklist in last few minutes, the partition config when this is the
+// root partition, the add rate and status of tasklist's ackManager (readLevel, ackLevel,
+// backlogCountHint and taskIDBlock).
 func (c *taskListManagerImpl) DescribeTaskList(includeTaskListStatus bool) *matchingservice.DescribeTaskListResponse {
-	response := &matchingservice.DescribeTaskListResponse{Pollers: c.GetAllPollerInfo()}
+	response := &matchingservice.DescribeTaskListResponse{
+		Pollers:        c.GetAllPollerInfo(),
+		DispatchConfig: c.getDispatchConfig(),
+	}
+	if c.partitionScaler != nil {
+		numRead, numWrite := c.partitionScaler.partitionConfig()
+		response.PartitionConfig = &matchingservice.TaskListPartitionConfig{
+			NumReadPartitions:  int32(numRead),
+			NumWritePartitions: int32(numWrite),
+		}
+	}
 	if !includeTaskListStatus {
 		return response
 	}
 
+	response.AddRatePerSecond = c.addRate.rate()
+	response.OutstandingPollers = atomic.LoadInt32(&c.numPollers)
+
 	taskIDBlock := c.rangeIDToTaskIDBlock(c.db.RangeID())
 	response.TaskListStatus = &commonproto.TaskListStatus{
 		ReadLevel:        c.taskAckManager.getReadLevel(),
 		AckLevel:         c.taskAckManager.getAckLevel(),
-		BacklogCountHint: c.taskAckManager.getBacklogCountHint(),
+		BacklogCountHint: c.backlogCountHint(),
 		RatePerSecond:    c.matcher.Rate(),
 		TaskIDBlock: &commonproto.TaskIDBlock{
 			StartID: taskIDBlock.start,
@@ -32,6 +32,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.temporal.io/temporal-proto/enums"
 
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
+	"github.com/temporalio/temporal/.gen/proto/matchingservicemock"
 	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
 
 	"github.com/temporalio/temporal/common/cache"
@@ -281,3 +283,95 @@ func TestCheckIdleTaskList(t *testing.T) {
 	tlm.Stop()
 	require.Equal(t, int32(1), tlm.stopped)
 }
+
+func TestUpdateDispatchConfig(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(10 * time.Millisecond)
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	require.NoError(t, tlm.Start())
+	defer tlm.Stop()
+
+	dispatchConfig := &persistenceblobs.TaskListDispatchConfig{
+		MaxTasksPerSecond:       10,
+		MaxTasksPerSecondPinned: true,
+		MaxConcurrentPollers:    1,
+	}
+	require.NoError(t, tlm.UpdateDispatchConfig(dispatchConfig))
+	require.Equal(t, dispatchConfig, tlm.DescribeTaskList(false).GetDispatchConfig())
+	require.Equal(t, 10.0, tlm.matcher.Rate())
+
+	// pinned rate is not overridden by pollers
+	rps := 100.0
+	_, err := tlm.GetTask(context.Background(), &rps)
+	require.Equal(t, ErrNoTasks, err)
+	require.Equal(t, 10.0, tlm.matcher.Rate())
+
+	// polls beyond the max concurrent pollers are rejected
+	atomic.StoreInt32(&tlm.numPollers, 1)
+	_, err = tlm.GetTask(context.Background(), &rps)
+	require.Equal(t, errTooManyPollers, err)
+	atomic.StoreInt32(&tlm.numPollers, 0)
+
+	// dispatch config survives ownership moves
+	tlm2, err := newTaskListManager(tlm.engine, tlm.taskListID, enums.TaskListKindNormal, cfg)
+	require.NoError(t, err)
+	require.NoError(t, tlm2.Start())
+	defer tlm2.Stop()
+	require.Equal(t, dispatchConfig, tlm2.DescribeTaskList(false).GetDispatchConfig())
+	require.Equal(t, 10.0, tlm2.(*taskListManagerImpl).matcher.Rate())
+
+	// operators can raise the rate immediately
+	require.NoError(t, tlm2.UpdateDispatchConfig(&persistenceblobs.TaskListDispatchConfig{MaxTasksPerSecond: 50}))
+	require.Equal(t, 50.0, tlm2.(*taskListManagerImpl).matcher.Rate())
+
+	// unpinned rate follows pollers again
+	rps = 5.0
+	_, err = tlm2.GetTask(context.Background(), &rps)
+	require.Equal(t, ErrNoTasks, err)
+	require.Equal(t, 5.0, tlm2.(*taskListManagerImpl).matcher.Rate())
+
+	// clearing the rate restores the default rate
+	require.NoError(t, tlm2.UpdateDispatchConfig(&persistenceblobs.TaskListDispatchConfig{MaxTasksPerSecond: 50}))
+	require.NoError(t, tlm2.UpdateDispatchConfig(nil))
+	require.Equal(t, _defaultTaskDispatchRPS, tlm2.(*taskListManagerImpl).matcher.Rate())
+}
+
+func TestDispatchConfigOfPartition(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(10 * time.Millisecond)
+	root := createTestTaskListManagerWithConfig(controller, cfg)
+	mockMatchingClient := matchingservicemock.NewMockMatchingServiceClient(controller)
+	root.engine.matchingClient = mockMatchingClient
+
+	dispatchConfig := &persistenceblobs.TaskListDispatchConfig{MaxTasksPerSecond: 10, MaxConcurrentPollers: 4}
+	mockMatchingClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, request *matchingservice.DescribeTaskListRequest) (*matchingservice.DescribeTaskListResponse, error) {
+			require.Equal(t, root.taskListID.name, request.DescRequest.TaskList.GetName())
+			return &matchingservice.DescribeTaskListResponse{
+				DispatchConfig:  dispatchConfig,
+				PartitionConfig: &matchingservice.TaskListPartitionConfig{NumReadPartitions: 2, NumWritePartitions: 2},
+			}, nil
+		}).MinTimes(1)
+
+	partitionID := newTestTaskListID(root.taskListID.domainID, root.taskListID.mkName(1), root.taskListID.taskType)
+	tlm, err := newTaskListManager(root.engine, partitionID, enums.TaskListKindNormal, cfg)
+	require.NoError(t, err)
+	partition := tlm.(*taskListManagerImpl)
+	require.NoError(t, partition.Start())
+	defer partition.Stop()
+
+	// the partition reads the dispatch config and the partitions from the root partition
+	require.Equal(t, errDispatchConfigOnPartition, partition.UpdateDispatchConfig(dispatchConfig))
+	require.Eventually(t, func() bool {
+		return partition.getDispatchConfig().Equal(dispatchConfig)
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, 2, partition.numReadPartitions())
+	require.Equal(t, int32(2), partition.maxPollersPerPartition(dispatchConfig))
+	require.Equal(t, 5.0, partition.matcher.Rate())
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/dgryski/go-farm"
+	commonproto "go.temporal.io/temporal-proto/common"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+type (
+	// subqueueKey identifies the subqueue of a task list which keeps the backlog of tasks of one
+	// priority and fairness key. Tasks are only keyed by fairness key when fairness dispatch is enabled.
+	subqueueKey struct {
+		priority    int32
+		fairnessKey string
+	}
+)
+
+// defaultSubqueueKey is the key of the tasks kept in the backlog of the task list itself
+var defaultSubqueueKey = subqueueKey{priority: common.DefaultTaskPriority}
+
+func newSubqueueKey(config *taskListConfig, taskInfo *persistenceblobs.TaskInfo) subqueueKey {
+	key := subqueueKey{priority: taskPriority(taskInfo)}
+	if config.EnableFairnessDispatch() {
+		key.fairnessKey = taskInfo.GetFairnessKey()
+	}
+	return key
+}
+
+func (k subqueueKey) String() string {
+	if k.fairnessKey == "" {
+		return strconv.Itoa(int(k.priority))
+	}
+	// fairness keys are user provided, the fingerprint keeps the task list name valid and short
+	return fmt.Sprintf("%v/%x", k.priority, farm.Fingerprint64([]byte(k.fairnessKey)))
+}
+
+// newTaskListSubqueue returns a subqueue of the given task list. A subqueue is a task list of its own
+// in persistence, which only has a backlog: its tasks are offered to the pollers of the parent task list.
+func newTaskListSubqueue(parent *taskListManagerImpl, key subqueueKey) *taskListManagerImpl {
+	e := parent.engine
+	taskList := parent.taskListID.withSubqueue(key)
+	db := newTaskListDB(e.taskManager, primitives.MustParseUUID(taskList.domainID), taskList.name, taskList.taskType, int32(parent.taskListKind), e.logger)
+	subqueue := &taskListManagerImpl{
+		domainCache:   e.domainCache,
+		metricsClient: e.metricsClient,
+		engine:        e,
+		shutdownCh:    make(chan struct{}),
+		taskListID:    taskList,
+		logger: e.logger.WithTags(tag.WorkflowTaskListName(taskList.name),
+			tag.WorkflowTaskListType(taskList.taskType)),
+		db:             db,
+		taskAckManager: newAckManager(e.logger),
+		taskGC:         newTaskGC(db, parent.config),
+		config:         parent.config,
+		taskListKind:   parent.taskListKind,
+		parent:         parent,
+		subqueueKey:    key,
+	}
+	subqueue.domainNameValue.Store(parent.domainName())
+	subqueue.domainScopeValue.Store(parent.domainScope())
+	subqueue.taskWriter = newTaskWriter(subqueue)
+	subqueue.taskReader = newTaskReader(subqueue)
+	subqueue.startWG.Add(1)
+	return subqueue
+}
+
+// startSubqueue acquires the lease of the subqueue and starts reading its backlog
+func (c *taskListManagerImpl) startSubqueue() error {
+	defer c.startWG.Done()
+
+	state, err := c.renewLeaseWithRetry()
+	if err != nil {
+		c.Stop()
+		return err
+	}
+
+	c.taskAckManager.setAckLevel(state.ackLevel)
+	c.taskWriter.Start(c.rangeIDToTaskIDBlock(state.rangeID))
+	c.taskReader.Start()
+	return nil
+}
+
+// appendTask persists the task to the backlog of the subqueue of its priority and fairness key,
+// tasks of the default priority without fairness key are kept in the backlog of the task list itself
+func (c *taskListManagerImpl) appendTask(
+	execution *commonproto.WorkflowExecution,
+	taskInfo *persistenceblobs.TaskInfo,
+) (*persistence.CreateTasksResponse, error) {
+
+	key := newSubqueueKey(c.config, taskInfo)
+	if c.subqueues == nil || key == defaultSubqueueKey {
+		return c.taskWriter.appendTask(execution, taskInfo)
+	}
+
+	for {
+		c.subqueuesLock.RLock()
+		if subqueue, ok := c.subqueues[key]; ok {
+			// subqueues are not retired while tasks are appended to them
+			resp, err := subqueue.taskWriter.appendTask(execution, taskInfo)
+			c.subqueuesLock.RUnlock()
+			if err == nil {
+				subqueue.taskReader.Signal()
+			}
+			return resp, err
+		}
+		c.subqueuesLock.RUnlock()
+
+		var err error
+		if key, err = c.createSubqueue(key); err != nil {
+			return nil, err
+		}
+		if key == defaultSubqueueKey {
+			return c.taskWriter.appendTask(execution, taskInfo)
+		}
+	}
+}
+
+// createSubqueue persists a new subqueue with the task list and starts it. Once the task list
+// has MaxFairnessKeys fairness keys with a subqueue, tasks of further keys share the subqueue
+// of their priority. Returns the key of the subqueue the task goes to.
+func (c *taskListManagerImpl) createSubqueue(key subqueueKey) (subqueueKey, error) {
+	c.subqueuesLock.Lock()
+	if key.fairnessKey != "" && c.fairnessKeyCountLocked() >= c.config.MaxFairnessKeys() {
+		key.fairnessKey = ""
+		if key == defaultSubqueueKey {
+			c.subqueuesLock.Unlock()
+			return key, nil
+		}
+	}
+	if _, ok := c.subqueues[key]; ok {
+		c.subqueuesLock.Unlock()
+		return key, nil
+	}
+	if c.isStopped() {
+		c.subqueuesLock.Unlock()
+		return key, errShutdown
+	}
+	subqueue := newTaskListSubqueue(c, key)
+	c.subqueues[key] = subqueue
+	if err := c.db.UpdateSubqueues(c.subqueueInfosLocked()); err != nil {
+		delete(c.subqueues, key)
+		c.subqueuesLock.Unlock()
+		return key, err
+	}
+	c.subqueuesLock.Unlock()
+
+	// started outside of the lock, failing to start the subqueue stops the task list
+	return key, subqueue.startSubqueue()
+}
+
+// fairnessKeyCountLocked returns the number of distinct fairness keys with a subqueue
+func (c *taskListManagerImpl) fairnessKeyCountLocked() int {
+	keys := make(map[string]struct{})
+	for key := range c.subqueues {
+		if key.fairnessKey != "" {
+			keys[key.fairnessKey] = struct{}{}
+		}
+	}
+	return len(keys)
+}
+
+// startSubqueues starts the subqueues persisted with the task list
+func (c *taskListManagerImpl) startSubqueues(infos []*persistenceblobs.TaskListSubqueue) error {
+	if c.subqueues == nil {
+		return nil
+	}
+
+	var subqueues []*taskListManagerImpl
+	c.subqueuesLock.Lock()
+	for _, info := range infos {
+		key := subqueueKey{priority: info.GetPriority(), fairnessKey: info.GetFairnessKey()}
+		if _, ok := c.subqueues[key]; ok {
+			continue
+		}
+		subqueue := newTaskListSubqueue(c, key)
+		c.subqueues[key] = subqueue
+		subqueues = append(subqueues, subqueue)
+	}
+	c.subqueuesLock.Unlock()
+
+	for _, subqueue := range subqueues {
+		if err := subqueue.startSubqueue(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *taskListManagerImpl) stopSubqueues() {
+	c.subqueuesLock.Lock()
+	defer c.subqueuesLock.Unlock()
+	for key, subqueue := range c.subqueues {
+		subqueue.stop()
+		delete(c.subqueues, key)
+	}
+}
+
+// retireSubqueue stops the subqueue and removes it from the task list once its backlog
+// is drained. Returns false when the subqueue got new tasks and has to keep running.
+func (c *taskListManagerImpl) retireSubqueue(subqueue *taskListManagerImpl) bool {
+	c.subqueuesLock.Lock()
+	defer c.subqueuesLock.Unlock()
+	if c.subqueues[subqueue.subqueueKey] != subqueue {
+		// already stopped with the task list
+		return true
+	}
+	if !subqueue.isBacklogDrained() {
+		return false
+	}
+
+	// every task up to the read level is completed
+	ackLevel := subqueue.taskAckManager.getReadLevel()
+	if err := subqueue.db.UpdateState(ackLevel); err == nil {
+		subqueue.taskGC.RunNow(ackLevel)
+	}
+	subqueue.stop()
+	delete(c.subqueues, subqueue.subqueueKey)
+	if err := c.db.UpdateSubqueues(c.subqueueInfosLocked()); err != nil {
+		// the subqueue is loaded again with the task list and retired once more
+		c.logger.Warn("Failed to persist subqueues of task list", tag.Error(err))
+	}
+	return true
+}
+
+func (c *taskListManagerImpl) subqueueInfosLocked() []*persistenceblobs.TaskListSubqueue {
+	infos := make([]*persistenceblobs.TaskListSubqueue, 0, len(c.subqueues))
+	for key := range c.subqueues {
+		infos = append(infos, &persistenceblobs.TaskListSubqueue{Priority: key.priority, FairnessKey: key.fairnessKey})
+	}
+	return infos
+}
+
+// isBacklogDrained returns true when every task written to the task list has been read and completed
+func (c *taskListManagerImpl) isBacklogDrained() bool {
+	return c.taskAckManager.getReadLevel() >= c.taskWriter.GetMaxReadLevel() &&
+		c.taskAckManager.getBacklogCountHint() == 0
+}
+
+// backlogCountHint returns the backlog count hint of the task list and its subqueues
+func (c *taskListManagerImpl) backlogCountHint() int64 {
+	count := c.taskAckManager.getBacklogCountHint()
+	c.subqueuesLock.RLock()
+	defer c.subqueuesLock.RUnlock()
+	for _, subqueue := range c.subqueues {
+		count += subqueue.taskAckManager.getBacklogCountHint()
+	}
+	return count
+}
+
+func (c *taskListManagerImpl) isStopped() bool {
+	return atomic.LoadInt32(&c.stopped) == 1
+}
@@ -94,6 +94,11 @@ dispatchLoop:
 			if !ok { // Task list getTasks pump is shutdown
 				break dispatchLoop
 			}
+			if tr.tlMgr.isTaskDeleted(taskInfo.GetTaskID()) {
+				// dropped from the backlog by operators after it was read
+				tr.tlMgr.completeTask(taskInfo, nil)
+				continue dispatchLoop
+			}
 			task := newInternalTask(taskInfo, tr.tlMgr.completeTask, enums.TaskSourceDbBacklog, "", false)
 			for {
 				err := tr.tlMgr.DispatchTask(tr.cancelCtx, task)
@@ -171,8 +176,7 @@ getTasksPumpLoop:
 			}
 		case <-checkIdleTaskListTimer.C:
 			{
-				if tr.isIdle(lastTimeWriteTask) {
-					tr.handleIdleTimeout()
+				if tr.isIdle(lastTimeWriteTask) && tr.handleIdleTimeout() {
 					break getTasksPumpLoop
 				}
 				checkIdleTaskListTimer = time.NewTimer(tr.tlMgr.config.IdleTasklistCheckInterval())
@@ -222,13 +226,22 @@ func (tr *taskReader) getTaskBatch() ([]*persistenceblobs.AllocatedTaskInfo, int
 }
 
 func (tr *taskReader) isIdle(lastWriteTime time.Time) bool {
+	if tr.tlMgr.parent != nil {
+		// subqueues have no pollers of their own, they are idle once their backlog is drained
+		return tr.tlMgr.isBacklogDrained()
+	}
 	return !tr.isTaskAddedRecently(lastWriteTime) && len(tr.tlMgr.GetAllPollerInfo()) == 0
 }
 
-func (tr *taskReader) handleIdleTimeout() {
+// handleIdleTimeout unloads the idle task list, returns false when it has to keep running
+func (tr *taskReader) handleIdleTimeout() bool {
+	if tr.tlMgr.parent != nil {
+		return tr.tlMgr.parent.retireSubqueue(tr.tlMgr)
+	}
 	_ = tr.persistAckLevel()
 	tr.tlMgr.taskGC.RunNow(tr.tlMgr.taskAckManager.getAckLevel())
 	tr.tlMgr.Stop()
+	return true
 }
 
 func (tr *taskReader) addTasksToBuffer(tasks []*persistenceblobs.AllocatedTaskInfo, lastWriteTime time.Time, idleTimer *time.Timer) bool {
@@ -255,8 +268,7 @@ func (tr *taskReader) addSingleTaskToBuffer(
 		case tr.taskBuffer <- task:
 			return true
 		case <-idleTimer.C:
-			if tr.isIdle(lastWriteTime) {
-				tr.handleIdleTimeout()
+			if tr.isIdle(lastWriteTime) && tr.handleIdleTimeout() {
 				return false
 			}
 		case <-tr.tlMgr.shutdownCh:
@@ -47,6 +47,10 @@ type (
 const (
 	// taskListPartitionPrefix is the required naming prefix for any task list partition other than partition 0
 	taskListPartitionPrefix = "/__temporal_sys/"
+	// taskListBuildPrefix is the naming prefix of task lists which only dispatch to workers of one build ID
+	taskListBuildPrefix = "/__temporal_build/"
+	// taskListSubqueuePrefix is the naming prefix of the task lists which keep part of the backlog of another task list
+	taskListSubqueuePrefix = "/__temporal_subqueue/"
 )
 
 // newTaskListName returns a fully qualified task list name.
@@ -99,6 +103,22 @@ func (tn *qualifiedTaskListName) Parent(degree int) string {
 	return tn.mkName(pid)
 }
 
+// IsVersioned returns true if this task list only dispatches to workers of one build ID
+func (tn *qualifiedTaskListName) IsVersioned() bool {
+	return strings.HasPrefix(tn.baseName, taskListBuildPrefix)
+}
+
+// WithBuildID returns the name of the same partition of the task list
+// which only dispatches to workers of the given build ID
+func (tn *qualifiedTaskListName) WithBuildID(buildID string) qualifiedTaskListName {
+	result := qualifiedTaskListName{
+		baseName:  fmt.Sprintf("%v%v/%v", taskListBuildPrefix, buildID, tn.baseName),
+		partition: tn.partition,
+	}
+	result.name = result.mkName(tn.partition)
+	return result
+}
+
 func (tn *qualifiedTaskListName) mkName(partition int) string {
 	if partition == 0 {
 		return tn.baseName
@@ -139,6 +159,25 @@ func newTaskListID(domainID string, taskListName string, taskType int32) (*taskL
 	}, nil
 }
 
+// withBuildID returns the taskListID of the same partition which only dispatches to workers of the given build ID
+func (tid *taskListID) withBuildID(buildID string) *taskListID {
+	return &taskListID{
+		qualifiedTaskListName: tid.WithBuildID(buildID),
+		domainID:              tid.domainID,
+		taskType:              tid.taskType,
+	}
+}
+
+// withSubqueue returns the taskListID of the subqueue which keeps the backlog of this task list for the given key
+func (tid *taskListID) withSubqueue(key subqueueKey) *taskListID {
+	name := fmt.Sprintf("%v%v/%v", taskListSubqueuePrefix, key, tid.name)
+	return &taskListID{
+		qualifiedTaskListName: qualifiedTaskListName{name: name, baseName: name},
+		domainID:              tid.domainID,
+		taskType:              tid.taskType,
+	}
+}
+
 func (tid *taskListID) String() string {
 	var b bytes.Buffer
 	b.WriteString("[")
@@ -115,3 +115,56 @@ func TestInvalidTasklistNames(t *testing.T) {
 		})
 	}
 }
+
+func TestVersionedTaskListNames(t *testing.T) {
+	testCases := []struct {
+		input  string
+		output string
+		parent string
+	}{
+		{"list0", "/__temporal_build/v1/list0", ""},
+		{"/__temporal_sys/list0/1", "/__temporal_sys//__temporal_build/v1/list0/1", "/__temporal_build/v1/list0"},
+		{"/__temporal_sys/list0/5", "/__temporal_sys//__temporal_build/v1/list0/5", "/__temporal_sys//__temporal_build/v1/list0/2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			tn, err := newTaskListName(tc.input)
+			require.NoError(t, err)
+			require.False(t, tn.IsVersioned())
+			versioned := tn.WithBuildID("v1")
+			require.True(t, versioned.IsVersioned())
+			require.Equal(t, tc.output, versioned.name)
+			require.Equal(t, tn.partition, versioned.partition)
+			require.Equal(t, tc.parent, versioned.Parent(2))
+
+			parsed, err := newTaskListName(versioned.name)
+			require.NoError(t, err)
+			require.Equal(t, versioned, parsed)
+		})
+	}
+}
+
+func TestSubqueueTaskListNames(t *testing.T) {
+	testCases := []struct {
+		input  string
+		key    subqueueKey
+		output string
+	}{
+		{"list0", subqueueKey{priority: 2}, "/__temporal_subqueue/2/list0"},
+		{"/__temporal_sys/list0/1", subqueueKey{priority: 2}, "/__temporal_subqueue/2//__temporal_sys/list0/1"},
+		{"list0", subqueueKey{priority: 0, fairnessKey: "tenant/1"}, "/__temporal_subqueue/0/10f92e161d2c2b4e/list0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			tlID, err := newTaskListID("domain", tc.input, 0)
+			require.NoError(t, err)
+			subqueue := tlID.withSubqueue(tc.key)
+			require.Equal(t, tc.output, subqueue.name)
+			require.True(t, subqueue.IsRoot())
+			require.Equal(t, tlID.domainID, subqueue.domainID)
+			require.Equal(t, tlID.taskType, subqueue.taskType)
+		})
+	}
+}
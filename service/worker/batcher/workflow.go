@@ -22,18 +22,22 @@ package batcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"go.temporal.io/temporal"
 	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
 	"go.temporal.io/temporal-proto/serviceerror"
 	"go.temporal.io/temporal-proto/workflowservice"
 	"go.temporal.io/temporal/activity"
 	"go.temporal.io/temporal/workflow"
 	"golang.org/x/time/rate"
 
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/client/admin"
 	"github.com/temporalio/temporal/client/frontend"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/log"
@@ -69,10 +73,31 @@ const (
 	BatchTypeCancel = "cancel"
 	// BatchTypeSignal is batch type for signaling workflows
 	BatchTypeSignal = "signal"
+	// BatchTypeReset is batch type for resetting workflows
+	BatchTypeReset = "reset"
+	// BatchTypeDelete is batch type for deleting closed workflows
+	BatchTypeDelete = "delete"
+	// BatchTypeRefreshTasks is batch type for refreshing the tasks of workflows
+	BatchTypeRefreshTasks = "refresh_tasks"
 )
 
 // AllBatchTypes is the batch types we supported
-var AllBatchTypes = []string{BatchTypeTerminate, BatchTypeCancel, BatchTypeSignal}
+var AllBatchTypes = []string{BatchTypeTerminate, BatchTypeCancel, BatchTypeSignal, BatchTypeReset, BatchTypeDelete, BatchTypeRefreshTasks}
+
+const (
+	// ResetTypeFirstDecisionCompleted resets to the first decision task completed event
+	ResetTypeFirstDecisionCompleted = "FirstDecisionCompleted"
+	// ResetTypeLastDecisionCompleted resets to the last decision task completed event
+	ResetTypeLastDecisionCompleted = "LastDecisionCompleted"
+	// ResetTypeBadBinary resets to the first decision task completed by a bad binary
+	ResetTypeBadBinary = "BadBinary"
+)
+
+// AllResetTypes is the reset types we supported
+var AllResetTypes = []string{ResetTypeFirstDecisionCompleted, ResetTypeLastDecisionCompleted, ResetTypeBadBinary}
+
+// errNoResetPoint is returned when a workflow has no event to reset to, it is not retried
+var errNoResetPoint = errors.New("no reset point found for workflow")
 
 type (
 	// TerminateParams is the parameters for terminating workflow
@@ -97,6 +122,14 @@ type (
 		Input      string
 	}
 
+	// ResetParams is the parameters for resetting workflow
+	ResetParams struct {
+		// ResetType is where to reset, one of AllResetTypes
+		ResetType string
+		// BadBinaryChecksum is the binary checksum to reset for ResetTypeBadBinary
+		BadBinaryChecksum string
+	}
+
 	// BatchParams is the parameters for batch operation workflow
 	BatchParams struct {
 		// Target domain to execute batch operation
@@ -105,7 +138,7 @@ type (
 		Query string
 		// Reason for the operation
 		Reason string
-		// Supporting: reset,terminate
+		// Supporting: terminate,cancel,signal,reset,delete,refresh_tasks
 		BatchType string
 
 		// Below are all optional
@@ -115,6 +148,8 @@ type (
 		CancelParams CancelParams
 		// SignalParams is params only for BatchTypeSignal
 		SignalParams SignalParams
+		// ResetParams is params only for BatchTypeReset
+		ResetParams ResetParams
 		// RPS of processing. Default to DefaultRPS
 		// TODO we will implement smarter way than this static rate limiter: https://github.com/temporalio/temporal/issues/2138
 		RPS int
@@ -192,9 +227,19 @@ func validateParams(params BatchParams) error {
 			return fmt.Errorf("must provide signal name")
 		}
 		return nil
-	case BatchTypeCancel:
-		fallthrough
-	case BatchTypeTerminate:
+	case BatchTypeReset:
+		switch params.ResetParams.ResetType {
+		case ResetTypeFirstDecisionCompleted, ResetTypeLastDecisionCompleted:
+			return nil
+		case ResetTypeBadBinary:
+			if params.ResetParams.BadBinaryChecksum == "" {
+				return fmt.Errorf("must provide bad binary checksum")
+			}
+			return nil
+		default:
+			return fmt.Errorf("not supported reset type: %v", params.ResetParams.ResetType)
+		}
+	case BatchTypeCancel, BatchTypeTerminate, BatchTypeDelete, BatchTypeRefreshTasks:
 		return nil
 	default:
 		return fmt.Errorf("not supported batch type: %v", params.BatchType)
@@ -330,6 +375,7 @@ func startTaskProcessor(
 	client frontend.Client,
 ) {
 	batcher := ctx.Value(batcherContextKey).(*Batcher)
+	adminClient := batcher.clientBean.GetRemoteAdminClient(batcher.cfg.ClusterMetadata.GetCurrentClusterName())
 	for {
 		select {
 		case <-ctx.Done():
@@ -388,13 +434,27 @@ func startTaskProcessor(
 						})
 						return err
 					})
+			case BatchTypeReset:
+				err = processTask(ctx, limiter, task, batchParams, client, common.BoolPtr(false),
+					func(workflowID, runID string) error {
+						return resetWorkflow(ctx, client, batchParams, workflowID, runID, requestID)
+					})
+			case BatchTypeDelete:
+				err = processTask(ctx, limiter, task, batchParams, client, common.BoolPtr(false),
+					func(workflowID, runID string) error {
+						return deleteWorkflow(ctx, adminClient, batchParams, workflowID, runID)
+					})
+			case BatchTypeRefreshTasks:
+				err = processTask(ctx, limiter, task, batchParams, client, common.BoolPtr(false),
+					func(workflowID, runID string) error {
+						return refreshWorkflowTasks(ctx, adminClient, batchParams, workflowID, runID)
+					})
 			}
 			if err != nil {
 				batcher.metricsClient.IncCounter(metrics.BatcherScope, metrics.BatcherProcessorFailures)
 				getActivityLogger(ctx).Error("Failed to process batch operation task", tag.Error(err))
 
-				_, ok := batchParams._nonRetryableErrors[err.Error()]
-				if ok || task.attempts >= batchParams.AttemptsOnRetryableError {
+				if isNonRetryableError(err, batchParams) || task.attempts >= batchParams.AttemptsOnRetryableError {
 					respCh <- err
 				} else {
 					// put back to the channel if less than attemptsOnError
@@ -467,6 +527,143 @@ func processTask(
 	return nil
 }
 
+func resetWorkflow(
+	ctx context.Context,
+	client frontend.Client,
+	batchParams BatchParams,
+	workflowID string,
+	runID string,
+	requestID string,
+) error {
+	decisionFinishID, err := getResetEventID(ctx, client, batchParams, workflowID, runID)
+	if err != nil {
+		return err
+	}
+	_, err = client.ResetWorkflowExecution(ctx, &workflowservice.ResetWorkflowExecutionRequest{
+		Domain: batchParams.DomainName,
+		WorkflowExecution: &commonproto.WorkflowExecution{
+			WorkflowId: workflowID,
+			RunId:      runID,
+		},
+		Reason:                batchParams.Reason,
+		DecisionFinishEventId: decisionFinishID,
+		RequestId:             requestID,
+	})
+	return err
+}
+
+// getResetEventID returns the ID of the decision task completed event to reset the workflow to
+func getResetEventID(
+	ctx context.Context,
+	client frontend.Client,
+	batchParams BatchParams,
+	workflowID string,
+	runID string,
+) (int64, error) {
+	execution := &commonproto.WorkflowExecution{
+		WorkflowId: workflowID,
+		RunId:      runID,
+	}
+
+	if batchParams.ResetParams.ResetType == ResetTypeBadBinary {
+		resp, err := client.DescribeWorkflowExecution(ctx, &workflowservice.DescribeWorkflowExecutionRequest{
+			Domain:    batchParams.DomainName,
+			Execution: execution,
+		})
+		if err != nil {
+			return 0, err
+		}
+		now := time.Now().UnixNano()
+		for _, p := range resp.WorkflowExecutionInfo.GetAutoResetPoints().GetPoints() {
+			if p.GetBinaryChecksum() != batchParams.ResetParams.BadBinaryChecksum || !p.GetResettable() {
+				continue
+			}
+			// reset point has expired and the history may already be deleted
+			if p.GetExpiringTimeNano() > 0 && now > p.GetExpiringTimeNano() {
+				continue
+			}
+			return p.GetFirstDecisionCompletedId(), nil
+		}
+		return 0, errNoResetPoint
+	}
+
+	var decisionFinishID int64
+	request := &workflowservice.GetWorkflowExecutionHistoryRequest{
+		Domain:          batchParams.DomainName,
+		Execution:       execution,
+		MaximumPageSize: pageSize,
+	}
+	for {
+		resp, err := client.GetWorkflowExecutionHistory(ctx, request)
+		if err != nil {
+			return 0, err
+		}
+		for _, e := range resp.GetHistory().GetEvents() {
+			if e.GetEventType() != enums.EventTypeDecisionTaskCompleted {
+				continue
+			}
+			decisionFinishID = e.GetEventId()
+			if batchParams.ResetParams.ResetType == ResetTypeFirstDecisionCompleted {
+				return decisionFinishID, nil
+			}
+		}
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		request.NextPageToken = resp.NextPageToken
+	}
+	if decisionFinishID == 0 {
+		return 0, errNoResetPoint
+	}
+	return decisionFinishID, nil
+}
+
+func deleteWorkflow(
+	ctx context.Context,
+	adminClient admin.Client,
+	batchParams BatchParams,
+	workflowID string,
+	runID string,
+) error {
+	_, err := adminClient.DeleteWorkflowExecution(ctx, &adminservice.DeleteWorkflowExecutionRequest{
+		Domain: batchParams.DomainName,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: workflowID,
+			RunId:      runID,
+		},
+	})
+	return err
+}
+
+func refreshWorkflowTasks(
+	ctx context.Context,
+	adminClient admin.Client,
+	batchParams BatchParams,
+	workflowID string,
+	runID string,
+) error {
+	_, err := adminClient.RefreshWorkflowTasks(ctx, &adminservice.RefreshWorkflowTasksRequest{
+		Domain: batchParams.DomainName,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: workflowID,
+			RunId:      runID,
+		},
+	})
+	return err
+}
+
+func isNonRetryableError(err error, batchParams BatchParams) bool {
+	if _, ok := batchParams._nonRetryableErrors[err.Error()]; ok {
+		return true
+	}
+	if err == errNoResetPoint {
+		return true
+	}
+	// e.g. deleting a workflow which is still running
+	_, ok := err.(*serviceerror.InvalidArgument)
+	return ok
+}
+
 func isDone(ctx context.Context) bool {
 	select {
 	case <-ctx.Done():
@@ -0,0 +1,226 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package batcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+	"go.temporal.io/temporal-proto/workflowservice"
+	"go.temporal.io/temporal-proto/workflowservicemock"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/.gen/proto/adminservicemock"
+)
+
+const (
+	testDomain     = "test-domain"
+	testWorkflowID = "test-workflow-id"
+	testRunID      = "test-run-id"
+)
+
+type (
+	workflowSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		frontendClient *workflowservicemock.MockWorkflowServiceClient
+		adminClient    *adminservicemock.MockAdminServiceClient
+	}
+)
+
+func TestWorkflowSuite(t *testing.T) {
+	suite.Run(t, new(workflowSuite))
+}
+
+func (s *workflowSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.frontendClient = workflowservicemock.NewMockWorkflowServiceClient(s.controller)
+	s.adminClient = adminservicemock.NewMockAdminServiceClient(s.controller)
+}
+
+func (s *workflowSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *workflowSuite) TestValidateParams() {
+	params := BatchParams{DomainName: testDomain, Query: "WorkflowType='test'", Reason: "test"}
+
+	for _, batchType := range []string{BatchTypeDelete, BatchTypeRefreshTasks} {
+		params.BatchType = batchType
+		s.NoError(validateParams(params))
+	}
+
+	params.BatchType = BatchTypeReset
+	s.Error(validateParams(params), "reset type is required")
+	params.ResetParams.ResetType = ResetTypeLastDecisionCompleted
+	s.NoError(validateParams(params))
+	params.ResetParams.ResetType = ResetTypeBadBinary
+	s.Error(validateParams(params), "bad binary checksum is required")
+	params.ResetParams.BadBinaryChecksum = "bad-checksum"
+	s.NoError(validateParams(params))
+}
+
+func (s *workflowSuite) TestResetWorkflow_LastDecisionCompleted() {
+	params := s.newBatchParams(BatchTypeReset)
+	params.ResetParams.ResetType = ResetTypeLastDecisionCompleted
+
+	s.frontendClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any()).Return(
+		&workflowservice.GetWorkflowExecutionHistoryResponse{
+			History:       s.newHistory(1, 4),
+			NextPageToken: []byte("next-page"),
+		}, nil)
+	s.frontendClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *workflowservice.GetWorkflowExecutionHistoryRequest, _ ...interface{}) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
+			s.Equal([]byte("next-page"), request.NextPageToken)
+			return &workflowservice.GetWorkflowExecutionHistoryResponse{History: s.newHistory(5, 10)}, nil
+		})
+	s.frontendClient.EXPECT().ResetWorkflowExecution(gomock.Any(), &workflowservice.ResetWorkflowExecutionRequest{
+		Domain:                testDomain,
+		WorkflowExecution:     &commonproto.WorkflowExecution{WorkflowId: testWorkflowID, RunId: testRunID},
+		Reason:                params.Reason,
+		DecisionFinishEventId: 9,
+		RequestId:             "request-id",
+	}).Return(&workflowservice.ResetWorkflowExecutionResponse{}, nil)
+
+	s.NoError(resetWorkflow(context.Background(), s.frontendClient, params, testWorkflowID, testRunID, "request-id"))
+}
+
+func (s *workflowSuite) TestResetWorkflow_FirstDecisionCompleted() {
+	params := s.newBatchParams(BatchTypeReset)
+	params.ResetParams.ResetType = ResetTypeFirstDecisionCompleted
+
+	s.frontendClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any()).Return(
+		&workflowservice.GetWorkflowExecutionHistoryResponse{
+			History:       s.newHistory(1, 10),
+			NextPageToken: []byte("next-page"),
+		}, nil)
+	s.frontendClient.EXPECT().ResetWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *workflowservice.ResetWorkflowExecutionRequest, _ ...interface{}) (*workflowservice.ResetWorkflowExecutionResponse, error) {
+			s.Equal(int64(4), request.DecisionFinishEventId)
+			return &workflowservice.ResetWorkflowExecutionResponse{}, nil
+		})
+
+	s.NoError(resetWorkflow(context.Background(), s.frontendClient, params, testWorkflowID, testRunID, "request-id"))
+}
+
+func (s *workflowSuite) TestResetWorkflow_BadBinary() {
+	params := s.newBatchParams(BatchTypeReset)
+	params.ResetParams.ResetType = ResetTypeBadBinary
+	params.ResetParams.BadBinaryChecksum = "bad-checksum"
+
+	s.frontendClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(
+		&workflowservice.DescribeWorkflowExecutionResponse{
+			WorkflowExecutionInfo: &commonproto.WorkflowExecutionInfo{
+				AutoResetPoints: &commonproto.ResetPoints{Points: []*commonproto.ResetPointInfo{
+					{BinaryChecksum: "good-checksum", FirstDecisionCompletedId: 4, Resettable: true},
+					{BinaryChecksum: "bad-checksum", FirstDecisionCompletedId: 9, Resettable: false},
+					{BinaryChecksum: "bad-checksum", FirstDecisionCompletedId: 15, Resettable: true},
+				}},
+			},
+		}, nil)
+	s.frontendClient.EXPECT().ResetWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *workflowservice.ResetWorkflowExecutionRequest, _ ...interface{}) (*workflowservice.ResetWorkflowExecutionResponse, error) {
+			s.Equal(int64(15), request.DecisionFinishEventId)
+			return &workflowservice.ResetWorkflowExecutionResponse{}, nil
+		})
+
+	s.NoError(resetWorkflow(context.Background(), s.frontendClient, params, testWorkflowID, testRunID, "request-id"))
+}
+
+func (s *workflowSuite) TestResetWorkflow_NoResetPoint() {
+	params := s.newBatchParams(BatchTypeReset)
+	params.ResetParams.ResetType = ResetTypeBadBinary
+	params.ResetParams.BadBinaryChecksum = "bad-checksum"
+
+	s.frontendClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(
+		&workflowservice.DescribeWorkflowExecutionResponse{
+			WorkflowExecutionInfo: &commonproto.WorkflowExecutionInfo{},
+		}, nil)
+
+	err := resetWorkflow(context.Background(), s.frontendClient, params, testWorkflowID, testRunID, "request-id")
+	s.Equal(errNoResetPoint, err)
+	s.True(isNonRetryableError(err, params))
+}
+
+func (s *workflowSuite) TestDeleteWorkflow() {
+	params := s.newBatchParams(BatchTypeDelete)
+	request := &adminservice.DeleteWorkflowExecutionRequest{
+		Domain:    testDomain,
+		Execution: &commonproto.WorkflowExecution{WorkflowId: testWorkflowID, RunId: testRunID},
+	}
+
+	s.adminClient.EXPECT().DeleteWorkflowExecution(gomock.Any(), request).Return(&adminservice.DeleteWorkflowExecutionResponse{}, nil)
+	s.NoError(deleteWorkflow(context.Background(), s.adminClient, params, testWorkflowID, testRunID))
+
+	// running workflows can not be deleted and are not retried
+	s.adminClient.EXPECT().DeleteWorkflowExecution(gomock.Any(), request).Return(nil, serviceerror.NewInvalidArgument("workflow execution is not closed"))
+	err := deleteWorkflow(context.Background(), s.adminClient, params, testWorkflowID, testRunID)
+	s.Error(err)
+	s.True(isNonRetryableError(err, params))
+}
+
+func (s *workflowSuite) TestRefreshWorkflowTasks() {
+	params := s.newBatchParams(BatchTypeRefreshTasks)
+	request := &adminservice.RefreshWorkflowTasksRequest{
+		Domain:    testDomain,
+		Execution: &commonproto.WorkflowExecution{WorkflowId: testWorkflowID, RunId: testRunID},
+	}
+
+	s.adminClient.EXPECT().RefreshWorkflowTasks(gomock.Any(), request).Return(&adminservice.RefreshWorkflowTasksResponse{}, nil)
+	s.NoError(refreshWorkflowTasks(context.Background(), s.adminClient, params, testWorkflowID, testRunID))
+
+	s.adminClient.EXPECT().RefreshWorkflowTasks(gomock.Any(), request).Return(nil, serviceerror.NewUnavailable("unavailable"))
+	err := refreshWorkflowTasks(context.Background(), s.adminClient, params, testWorkflowID, testRunID)
+	s.Error(err)
+	s.False(isNonRetryableError(err, params))
+}
+
+func (s *workflowSuite) newBatchParams(batchType string) BatchParams {
+	params := BatchParams{
+		DomainName: testDomain,
+		Query:      "WorkflowType='test'",
+		Reason:     "test",
+		BatchType:  batchType,
+	}
+	return setDefaultParams(params)
+}
+
+// newHistory returns the events between the IDs, events 4, 9, 14 and so on are decision task completed events
+func (s *workflowSuite) newHistory(firstEventID int64, lastEventID int64) *commonproto.History {
+	history := &commonproto.History{}
+	for eventID := firstEventID; eventID <= lastEventID; eventID++ {
+		eventType := enums.EventTypeDecisionTaskScheduled
+		if eventID%5 == 4 {
+			eventType = enums.EventTypeDecisionTaskCompleted
+		}
+		history.Events = append(history.Events, &commonproto.HistoryEvent{EventId: eventID, EventType: eventType})
+	}
+	return history
+}
@@ -293,6 +293,7 @@ func newHistoryReplicationV2Task(
 			VersionHistoryItems: attr.VersionHistoryItems,
 			Events:              attr.Events,
 			NewRunEvents:        attr.NewRunEvents,
+			WorkerBuildId:       attr.WorkerBuildId,
 		},
 		nDCHistoryResender: nDCHistoryResender,
 	}
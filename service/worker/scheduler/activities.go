@@ -0,0 +1,146 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+	"go.temporal.io/temporal-proto/workflowservice"
+	"go.temporal.io/temporal/activity"
+
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+)
+
+const (
+	schedulerIdentity = "temporal-sys-scheduler"
+	// watchPollTimeout bounds a single long poll so that the watch activity heartbeats in time
+	watchPollTimeout = 30 * time.Second
+)
+
+type (
+	startWorkflowRequest struct {
+		DomainName  string
+		ScheduleID  string
+		Action      StartWorkflowAction
+		NominalTime time.Time
+	}
+)
+
+// StartWorkflowActivity starts the workflow of a schedule action.
+// The workflow ID and the request ID are derived from the nominal time so that retries never start it twice.
+func StartWorkflowActivity(ctx context.Context, request startWorkflowRequest) (RunningWorkflow, error) {
+	scheduler := ctx.Value(schedulerContextKey).(*Scheduler)
+	workflowID := fmt.Sprintf("%v-%v", request.Action.WorkflowID, request.NominalTime.UTC().Format(time.RFC3339))
+	requestID := uuid.NewSHA1(uuid.NameSpaceURL, []byte(request.DomainName+"/"+request.ScheduleID+"/"+workflowID)).String()
+
+	resp, err := scheduler.frontendClient.StartWorkflowExecution(ctx, &workflowservice.StartWorkflowExecutionRequest{
+		Domain:                              request.DomainName,
+		WorkflowId:                          workflowID,
+		WorkflowType:                        &commonproto.WorkflowType{Name: request.Action.WorkflowType},
+		TaskList:                            &commonproto.TaskList{Name: request.Action.TaskList},
+		Input:                               request.Action.Input,
+		ExecutionStartToCloseTimeoutSeconds: int32(request.Action.ExecutionStartToCloseTimeout.Seconds()),
+		TaskStartToCloseTimeoutSeconds:      int32(request.Action.TaskStartToCloseTimeout.Seconds()),
+		Identity:                            schedulerIdentity,
+		RequestId:                           requestID,
+		WorkflowIdReusePolicy:               enums.WorkflowIdReusePolicyRejectDuplicate,
+	})
+	if err != nil {
+		if alreadyStarted, ok := err.(*serviceerror.WorkflowExecutionAlreadyStarted); ok && alreadyStarted.StartRequestId == requestID {
+			return RunningWorkflow{WorkflowID: workflowID, RunID: alreadyStarted.RunId}, nil
+		}
+		scheduler.metricsClient.IncCounter(metrics.SchedulerScope, metrics.SchedulerActionFailures)
+		getActivityLogger(ctx).Error("Failed to start scheduled workflow", tag.WorkflowID(workflowID), tag.Error(err))
+		return RunningWorkflow{}, err
+	}
+	scheduler.metricsClient.IncCounter(metrics.SchedulerScope, metrics.SchedulerActionsTaken)
+	return RunningWorkflow{WorkflowID: workflowID, RunID: resp.GetRunId()}, nil
+}
+
+// WatchWorkflowActivity returns once the workflow started by a schedule action has closed.
+// The workflow is followed across continue as new.
+func WatchWorkflowActivity(ctx context.Context, domainName string, running RunningWorkflow) error {
+	scheduler := ctx.Value(schedulerContextKey).(*Scheduler)
+	for {
+		activity.RecordHeartbeat(ctx)
+		pollCtx, cancel := context.WithTimeout(ctx, watchPollTimeout)
+		resp, err := scheduler.frontendClient.GetWorkflowExecutionHistory(pollCtx, &workflowservice.GetWorkflowExecutionHistoryRequest{
+			Domain:                 domainName,
+			Execution:              &commonproto.WorkflowExecution{WorkflowId: running.WorkflowID},
+			WaitForNewEvent:        true,
+			HistoryEventFilterType: enums.HistoryEventFilterTypeCloseEvent,
+		})
+		cancel()
+
+		switch err.(type) {
+		case nil:
+		case *serviceerror.NotFound:
+			return nil
+		default:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if pollCtx.Err() == context.DeadlineExceeded {
+				continue
+			}
+			return err
+		}
+
+		events := resp.GetHistory().GetEvents()
+		if len(events) > 0 && events[len(events)-1].GetEventType() != enums.EventTypeWorkflowExecutionContinuedAsNew {
+			return nil
+		}
+	}
+}
+
+// CancelWorkflowActivity requests the cancellation of a workflow started by a schedule action
+func CancelWorkflowActivity(ctx context.Context, domainName string, running RunningWorkflow) error {
+	scheduler := ctx.Value(schedulerContextKey).(*Scheduler)
+	_, err := scheduler.frontendClient.RequestCancelWorkflowExecution(ctx, &workflowservice.RequestCancelWorkflowExecutionRequest{
+		Domain:            domainName,
+		WorkflowExecution: &commonproto.WorkflowExecution{WorkflowId: running.WorkflowID},
+		Identity:          schedulerIdentity,
+	})
+	switch err.(type) {
+	case nil, *serviceerror.NotFound, *serviceerror.CancellationAlreadyRequested:
+		return nil
+	default:
+		return err
+	}
+}
+
+func getActivityLogger(ctx context.Context) log.Logger {
+	scheduler := ctx.Value(schedulerContextKey).(*Scheduler)
+	wfInfo := activity.GetInfo(ctx)
+	return scheduler.logger.WithTags(
+		tag.WorkflowID(wfInfo.WorkflowExecution.ID),
+		tag.WorkflowRunID(wfInfo.WorkflowExecution.RunID),
+		tag.WorkflowDomainName(wfInfo.WorkflowDomain),
+	)
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// OverlapPolicySkip skips an action while a previously started workflow is still running
+	OverlapPolicySkip = "skip"
+	// OverlapPolicyBufferOne starts at most one workflow after the running one has closed, further actions are skipped
+	OverlapPolicyBufferOne = "buffer_one"
+	// OverlapPolicyCancelOther requests cancellation of the running workflows and starts a new one
+	OverlapPolicyCancelOther = "cancel_other"
+	// OverlapPolicyAllowAll starts a new workflow regardless of the running ones
+	OverlapPolicyAllowAll = "allow_all"
+
+	// DefaultCatchupWindow is the default period after which a missed action is no longer taken
+	DefaultCatchupWindow = time.Hour
+
+	// WorkflowIDPrefix is the prefix of the IDs of schedule workflows
+	WorkflowIDPrefix = "temporal-sys-scheduler:"
+)
+
+// AllOverlapPolicies is the overlap policies we supported
+var AllOverlapPolicies = []string{OverlapPolicySkip, OverlapPolicyBufferOne, OverlapPolicyCancelOther, OverlapPolicyAllowAll}
+
+type (
+	// StartWorkflowAction is the workflow started by a schedule.
+	// The ID of every started workflow is the WorkflowID suffixed with the nominal time of the action.
+	StartWorkflowAction struct {
+		WorkflowID                   string
+		WorkflowType                 string
+		TaskList                     string
+		Input                        []byte
+		ExecutionStartToCloseTimeout time.Duration
+		TaskStartToCloseTimeout      time.Duration
+	}
+
+	// Policies controls how a schedule behaves when its actions overlap or are missed
+	Policies struct {
+		// OverlapPolicy is one of AllOverlapPolicies, defaults to OverlapPolicySkip
+		OverlapPolicy string
+		// CatchupWindow is how late an action can still be taken after an outage, defaults to DefaultCatchupWindow
+		CatchupWindow time.Duration
+	}
+
+	// State is the user controlled state of a schedule
+	State struct {
+		Paused bool
+		Notes  string
+	}
+
+	// Schedule is the full definition of a schedule
+	Schedule struct {
+		Spec     Spec
+		Action   StartWorkflowAction
+		Policies Policies
+		State    State
+	}
+
+	// RunningWorkflow is a workflow started by a schedule which has not closed yet
+	RunningWorkflow struct {
+		WorkflowID string
+		RunID      string
+	}
+
+	// ActionResult is the result of an action taken by a schedule
+	ActionResult struct {
+		NominalTime time.Time
+		ActualTime  time.Time
+		WorkflowID  string
+		RunID       string
+	}
+
+	// Info is the runtime information of a schedule
+	Info struct {
+		ActionCount         int64
+		MissedCatchupWindow int64
+		OverlapSkipped      int64
+		RunningWorkflows    []RunningWorkflow
+		RecentActions       []ActionResult
+		FutureActionTimes   []time.Time
+		CreateTime          time.Time
+		UpdateTime          time.Time
+	}
+
+	// BackfillRequest takes the actions of all nominal times in [StartTime, EndTime] as if they were on time
+	BackfillRequest struct {
+		StartTime time.Time
+		EndTime   time.Time
+		// OverlapPolicy overrides the overlap policy of the schedule for the backfilled actions
+		OverlapPolicy string
+	}
+
+	// DescribeResult is the result of the describe query
+	DescribeResult struct {
+		Schedule Schedule
+		Info     Info
+	}
+)
+
+// WorkflowID returns the ID of the workflow driving the schedule
+func WorkflowID(domainName string, scheduleID string) string {
+	return fmt.Sprintf("%v%v:%v", WorkflowIDPrefix, domainName, scheduleID)
+}
+
+// Validate checks that the schedule is well formed
+func (s *Schedule) Validate() error {
+	if err := s.Spec.Validate(); err != nil {
+		return err
+	}
+	if s.Action.WorkflowID == "" || s.Action.WorkflowType == "" || s.Action.TaskList == "" {
+		return errors.New("schedule action must provide WorkflowID/WorkflowType/TaskList")
+	}
+	if s.Action.ExecutionStartToCloseTimeout <= 0 {
+		return errors.New("schedule action must provide a positive execution timeout")
+	}
+	if s.Action.TaskStartToCloseTimeout < 0 {
+		return errors.New("schedule action task timeout must not be negative")
+	}
+	if s.Policies.CatchupWindow < 0 {
+		return errors.New("schedule catchup window must not be negative")
+	}
+	return validateOverlapPolicy(s.Policies.OverlapPolicy)
+}
+
+func (s *Schedule) setDefaults() {
+	if s.Policies.OverlapPolicy == "" {
+		s.Policies.OverlapPolicy = OverlapPolicySkip
+	}
+	if s.Policies.CatchupWindow == 0 {
+		s.Policies.CatchupWindow = DefaultCatchupWindow
+	}
+}
+
+// Validate checks that the backfill request is well formed
+func (r *BackfillRequest) Validate() error {
+	if r.StartTime.IsZero() || r.EndTime.IsZero() || r.EndTime.Before(r.StartTime) {
+		return errors.New("backfill must provide a StartTime before its EndTime")
+	}
+	return validateOverlapPolicy(r.OverlapPolicy)
+}
+
+func validateOverlapPolicy(policy string) error {
+	if policy == "" {
+		return nil
+	}
+	for _, p := range AllOverlapPolicies {
+		if p == policy {
+			return nil
+		}
+	}
+	return fmt.Errorf("not supported overlap policy: %v", policy)
+}
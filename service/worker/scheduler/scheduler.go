@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package scheduler
+
+import (
+	"context"
+
+	"go.temporal.io/temporal-proto/workflowservice"
+	"go.temporal.io/temporal/activity"
+	sdkclient "go.temporal.io/temporal/client"
+	"go.temporal.io/temporal/worker"
+	"go.temporal.io/temporal/workflow"
+
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+)
+
+type (
+	// BootstrapParams contains the set of params needed to bootstrap
+	// the scheduler sub-system
+	BootstrapParams struct {
+		// ServiceClient is an instance of cadence service client
+		ServiceClient sdkclient.Client
+		// FrontendClient is used by the activities to act on the scheduled workflows
+		FrontendClient workflowservice.WorkflowServiceClient
+		// MetricsClient is an instance of metrics object for emitting stats
+		MetricsClient metrics.Client
+		Logger        log.Logger
+	}
+
+	// Scheduler is the background sub-system that executes the schedule workflows
+	// It is also the context object that get's passed around within the schedule activities
+	Scheduler struct {
+		svcClient      sdkclient.Client
+		frontendClient workflowservice.WorkflowServiceClient
+		metricsClient  metrics.Client
+		logger         log.Logger
+	}
+)
+
+// New returns a new instance of scheduler daemon Scheduler
+func New(params *BootstrapParams) *Scheduler {
+	return &Scheduler{
+		svcClient:      params.ServiceClient,
+		frontendClient: params.FrontendClient,
+		metricsClient:  params.MetricsClient,
+		logger:         params.Logger.WithTags(tag.ComponentScheduler),
+	}
+}
+
+// Start starts the scheduler
+func (s *Scheduler) Start() error {
+	ctx := context.WithValue(context.Background(), schedulerContextKey, s)
+	workerOpts := worker.Options{
+		BackgroundActivityContext: ctx,
+	}
+	scheduleWorker := worker.New(s.svcClient, SchedulerTaskListName, workerOpts)
+	scheduleWorker.RegisterWorkflowWithOptions(ScheduleWorkflow, workflow.RegisterOptions{Name: SchedulerWFTypeName})
+	scheduleWorker.RegisterActivityWithOptions(StartWorkflowActivity, activity.RegisterOptions{Name: startWorkflowActivityName})
+	scheduleWorker.RegisterActivityWithOptions(WatchWorkflowActivity, activity.RegisterOptions{Name: watchWorkflowActivityName})
+	scheduleWorker.RegisterActivityWithOptions(CancelWorkflowActivity, activity.RegisterOptions{Name: cancelWorkflowActivityName})
+
+	return scheduleWorker.Start()
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+var unixEpoch = time.Unix(0, 0).UTC()
+
+type (
+	// Spec describes the times at which a schedule takes its action.
+	// The nominal times are the union of the cron expressions and the interval,
+	// bounded by StartTime and EndTime when they are set.
+	Spec struct {
+		// CronExpressions are standard cron expressions evaluated in UTC
+		CronExpressions []string
+		// Interval takes an action at every multiple of the interval since the unix epoch
+		Interval time.Duration
+		// StartTime is the earliest nominal time, unbounded when zero
+		StartTime time.Time
+		// EndTime is the latest nominal time, unbounded when zero
+		EndTime time.Time
+		// Jitter delays every action by a deterministic random duration in [0, Jitter)
+		Jitter time.Duration
+	}
+
+	compiledSpec struct {
+		spec      Spec
+		schedules []cron.Schedule
+	}
+)
+
+// Validate checks that the spec is well formed
+func (s *Spec) Validate() error {
+	if len(s.CronExpressions) == 0 && s.Interval <= 0 {
+		return errors.New("schedule spec must have a cron expression or an interval")
+	}
+	if s.Interval < 0 {
+		return errors.New("schedule interval must be positive")
+	}
+	if s.Jitter < 0 {
+		return errors.New("schedule jitter must not be negative")
+	}
+	if !s.StartTime.IsZero() && !s.EndTime.IsZero() && s.EndTime.Before(s.StartTime) {
+		return errors.New("schedule end time must be after its start time")
+	}
+	for _, expression := range s.CronExpressions {
+		if _, err := cron.ParseStandard(expression); err != nil {
+			return fmt.Errorf("invalid cron expression %q: %v", expression, err)
+		}
+	}
+	return nil
+}
+
+func compileSpec(spec Spec) (*compiledSpec, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	schedules := make([]cron.Schedule, 0, len(spec.CronExpressions))
+	for _, expression := range spec.CronExpressions {
+		schedule, _ := cron.ParseStandard(expression)
+		schedules = append(schedules, schedule)
+	}
+	return &compiledSpec{
+		spec:      spec,
+		schedules: schedules,
+	}, nil
+}
+
+// nextNominalTime returns the first nominal time strictly after the given time,
+// or the zero time when the spec has no more nominal times.
+func (c *compiledSpec) nextNominalTime(after time.Time) time.Time {
+	after = after.UTC()
+	if !c.spec.StartTime.IsZero() && after.Before(c.spec.StartTime) {
+		after = c.spec.StartTime.UTC().Add(-time.Nanosecond)
+	}
+
+	var next time.Time
+	for _, schedule := range c.schedules {
+		candidate := schedule.Next(after)
+		if !candidate.IsZero() && (next.IsZero() || candidate.Before(next)) {
+			next = candidate
+		}
+	}
+	if c.spec.Interval > 0 {
+		// intervals are aligned on the unix epoch
+		elapsed := after.Sub(unixEpoch)
+		remainder := elapsed % c.spec.Interval
+		if remainder < 0 {
+			remainder += c.spec.Interval
+		}
+		candidate := after.Add(c.spec.Interval - remainder)
+		if next.IsZero() || candidate.Before(next) {
+			next = candidate
+		}
+	}
+
+	if !c.spec.EndTime.IsZero() && next.After(c.spec.EndTime) {
+		return time.Time{}
+	}
+	return next
+}
+
+// actualTime returns the time at which the action of the nominal time is taken.
+// The jitter is derived from the schedule ID and the nominal time so that it is deterministic.
+func (c *compiledSpec) actualTime(scheduleID string, nominal time.Time) time.Time {
+	if c.spec.Jitter <= 0 {
+		return nominal
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(scheduleID))
+	hash.Write([]byte(nominal.UTC().Format(time.RFC3339Nano)))
+	return nominal.Add(time.Duration(hash.Sum64() % uint64(c.spec.Jitter)))
+}
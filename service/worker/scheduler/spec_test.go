@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	specSuite struct {
+		*require.Assertions
+		suite.Suite
+	}
+)
+
+func TestSpecSuite(t *testing.T) {
+	suite.Run(t, new(specSuite))
+}
+
+func (s *specSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *specSuite) TestValidate() {
+	s.Error((&Spec{}).Validate())
+	s.Error((&Spec{CronExpressions: []string{"* * *"}}).Validate())
+	s.Error((&Spec{Interval: time.Minute, Jitter: -time.Second}).Validate())
+	s.Error((&Spec{
+		Interval:  time.Minute,
+		StartTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}).Validate())
+	s.NoError((&Spec{CronExpressions: []string{"*/5 * * * *", "@daily"}}).Validate())
+}
+
+func (s *specSuite) TestNextNominalTime() {
+	spec, err := compileSpec(Spec{
+		CronExpressions: []string{"0 12 * * *"},
+		Interval:        5 * time.Hour,
+		StartTime:       time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC),
+		EndTime:         time.Date(2020, 1, 1, 20, 0, 0, 0, time.UTC),
+	})
+	s.NoError(err)
+
+	var times []time.Time
+	for t := spec.nextNominalTime(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)); !t.IsZero(); t = spec.nextNominalTime(t) {
+		times = append(times, t)
+	}
+	// the 5 hours interval is aligned on the unix epoch and coincides with the cron expression at 12:00
+	s.Equal([]time.Time{
+		time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 1, 17, 0, 0, 0, time.UTC),
+	}, times)
+}
+
+func (s *specSuite) TestActualTime() {
+	spec, err := compileSpec(Spec{Interval: time.Hour, Jitter: 10 * time.Minute})
+	s.NoError(err)
+
+	nominal := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	actual := spec.actualTime("schedule-id", nominal)
+	s.False(actual.Before(nominal))
+	s.True(actual.Before(nominal.Add(10 * time.Minute)))
+	s.Equal(actual, spec.actualTime("schedule-id", nominal))
+}
@@ -0,0 +1,465 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	"go.temporal.io/temporal"
+	"go.temporal.io/temporal/workflow"
+	"go.uber.org/zap"
+)
+
+const (
+	schedulerContextKey = "schedulerContext"
+	// SchedulerTaskListName is the tasklist name
+	SchedulerTaskListName = "temporal-sys-scheduler-tasklist"
+	// SchedulerWFTypeName is the workflow type
+	SchedulerWFTypeName        = "temporal-sys-scheduler-workflow"
+	startWorkflowActivityName  = "temporal-sys-scheduler-start-workflow-activity"
+	watchWorkflowActivityName  = "temporal-sys-scheduler-watch-workflow-activity"
+	cancelWorkflowActivityName = "temporal-sys-scheduler-cancel-workflow-activity"
+
+	// SignalNameUpdate is the signal replacing the schedule, its input is a Schedule
+	SignalNameUpdate = "update"
+	// SignalNamePause is the signal pausing the schedule, its input is the notes of the pause
+	SignalNamePause = "pause"
+	// SignalNameResume is the signal resuming the schedule, its input is the notes of the resume
+	SignalNameResume = "resume"
+	// SignalNameBackfill is the signal backfilling the schedule, its input is a BackfillRequest
+	SignalNameBackfill = "backfill"
+	// QueryNameDescribe is the query returning a DescribeResult
+	QueryNameDescribe = "describe"
+
+	// InfiniteDuration is a long duration(20 yrs) we used for infinite workflow running
+	InfiniteDuration = 20 * 365 * 24 * time.Hour
+
+	maxRecentActions              = 10
+	maxFutureActionTimes          = 5
+	maxBufferedStarts             = 1000
+	maxNominalTimesPerIteration   = 1000
+	iterationsBeforeContinueAsNew = 500
+)
+
+type (
+	// Params is the input of the schedule workflow.
+	// It holds the whole state of the schedule and is carried over when the workflow continues as new.
+	Params struct {
+		DomainName string
+		ScheduleID string
+		Schedule   Schedule
+		Info       Info
+		// LastProcessedTime is the time up to which the nominal times of the schedule have been considered
+		LastProcessedTime time.Time
+		// BufferedStarts are the actions waiting to be taken
+		BufferedStarts []BufferedStart
+	}
+
+	// BufferedStart is an action waiting to be taken
+	BufferedStart struct {
+		NominalTime time.Time
+		ActualTime  time.Time
+		// OverlapPolicy overrides the overlap policy of the schedule when set
+		OverlapPolicy string
+	}
+
+	scheduleWorkflow struct {
+		ctx      workflow.Context
+		logger   *zap.Logger
+		params   Params
+		spec     *compiledSpec
+		watchers map[string]workflow.Future
+	}
+)
+
+var (
+	scheduleActivityRetryPolicy = temporal.RetryPolicy{
+		InitialInterval:    time.Second,
+		BackoffCoefficient: 2,
+		MaximumInterval:    time.Minute,
+		ExpirationInterval: 10 * time.Minute,
+	}
+
+	scheduleActivityOptions = workflow.ActivityOptions{
+		ScheduleToStartTimeout: 5 * time.Minute,
+		StartToCloseTimeout:    time.Minute,
+		RetryPolicy:            &scheduleActivityRetryPolicy,
+	}
+
+	watchActivityRetryPolicy = temporal.RetryPolicy{
+		InitialInterval:    10 * time.Second,
+		BackoffCoefficient: 1.7,
+		MaximumInterval:    5 * time.Minute,
+		ExpirationInterval: InfiniteDuration,
+	}
+
+	watchActivityOptions = workflow.ActivityOptions{
+		ScheduleToStartTimeout: 5 * time.Minute,
+		StartToCloseTimeout:    InfiniteDuration,
+		HeartbeatTimeout:       time.Minute,
+		RetryPolicy:            &watchActivityRetryPolicy,
+	}
+)
+
+// ScheduleWorkflow is the workflow that takes the actions of a schedule.
+// It wakes up at the next action time or when it is signaled, and continues as new periodically.
+func ScheduleWorkflow(ctx workflow.Context, params Params) error {
+	params.Schedule.setDefaults()
+	if err := params.Schedule.Validate(); err != nil {
+		return err
+	}
+	spec, err := compileSpec(params.Schedule.Spec)
+	if err != nil {
+		return err
+	}
+	w := &scheduleWorkflow{
+		ctx:      ctx,
+		logger:   workflow.GetLogger(ctx).With(zap.String("schedule-id", params.ScheduleID)),
+		params:   params,
+		spec:     spec,
+		watchers: make(map[string]workflow.Future),
+	}
+	return w.run()
+}
+
+func (w *scheduleWorkflow) run() error {
+	now := workflow.Now(w.ctx)
+	if w.params.Info.CreateTime.IsZero() {
+		w.params.Info.CreateTime = now
+		w.params.Info.UpdateTime = now
+	}
+	if w.params.LastProcessedTime.IsZero() {
+		w.params.LastProcessedTime = now
+	}
+	if err := workflow.SetQueryHandler(w.ctx, QueryNameDescribe, w.describe); err != nil {
+		return err
+	}
+	for _, running := range w.params.Info.RunningWorkflows {
+		w.watch(running)
+	}
+
+	for i := 0; i < iterationsBeforeContinueAsNew; i++ {
+		w.processTimeRange(workflow.Now(w.ctx))
+		w.processBuffer()
+		if w.isDone() {
+			w.logger.Info("Schedule has no more actions to take.")
+			return nil
+		}
+		w.waitForEvent()
+	}
+
+	w.drainSignals()
+	return workflow.NewContinueAsNewError(w.ctx, SchedulerWFTypeName, w.params)
+}
+
+// processTimeRange buffers the actions whose actual time is in (LastProcessedTime, now]
+func (w *scheduleWorkflow) processTimeRange(now time.Time) {
+	start := w.params.LastProcessedTime
+	w.params.LastProcessedTime = now
+	if w.params.Schedule.State.Paused || !now.After(start) {
+		return
+	}
+
+	jitter := w.params.Schedule.Spec.Jitter
+	catchupWindow := w.params.Schedule.Policies.CatchupWindow
+	// skip over the nominal times which are certainly out of the catchup window without inspecting them one by one
+	if catchupStart := now.Add(-catchupWindow - jitter); start.Before(catchupStart) {
+		w.params.Info.MissedCatchupWindow += w.countNominalTimes(start, catchupStart.Add(-jitter))
+		start = catchupStart
+	}
+
+	t := w.spec.nextNominalTime(start.Add(-jitter))
+	for i := 0; !t.IsZero() && !t.After(now) && i < maxNominalTimesPerIteration; i++ {
+		actual := w.spec.actualTime(w.params.ScheduleID, t)
+		switch {
+		case !actual.After(start) || actual.After(now):
+			// taken by the previous or the next time range
+		case now.Sub(actual) >= catchupWindow:
+			w.params.Info.MissedCatchupWindow++
+		default:
+			w.bufferStart(BufferedStart{NominalTime: t, ActualTime: actual})
+		}
+		t = w.spec.nextNominalTime(t)
+	}
+}
+
+func (w *scheduleWorkflow) countNominalTimes(start time.Time, end time.Time) int64 {
+	var count int64
+	t := w.spec.nextNominalTime(start)
+	for ; !t.IsZero() && !t.After(end) && count < maxNominalTimesPerIteration; count++ {
+		t = w.spec.nextNominalTime(t)
+	}
+	return count
+}
+
+func (w *scheduleWorkflow) bufferStart(start BufferedStart) {
+	if len(w.params.BufferedStarts) >= maxBufferedStarts {
+		w.params.Info.OverlapSkipped++
+		return
+	}
+	w.params.BufferedStarts = append(w.params.BufferedStarts, start)
+}
+
+// processBuffer takes the buffered actions according to their overlap policy
+func (w *scheduleWorkflow) processBuffer() {
+	for len(w.params.BufferedStarts) > 0 {
+		start := w.params.BufferedStarts[0]
+		if len(w.params.Info.RunningWorkflows) > 0 {
+			switch w.getOverlapPolicy(start) {
+			case OverlapPolicySkip:
+				w.params.Info.OverlapSkipped++
+				w.params.BufferedStarts = w.params.BufferedStarts[1:]
+				continue
+			case OverlapPolicyBufferOne:
+				w.dropBufferedAfterFirst()
+				return
+			case OverlapPolicyCancelOther:
+				w.cancelRunning()
+			}
+		}
+		w.params.BufferedStarts = w.params.BufferedStarts[1:]
+		w.startWorkflow(start)
+	}
+}
+
+// dropBufferedAfterFirst keeps a single waiting action among the ones which must not overlap
+func (w *scheduleWorkflow) dropBufferedAfterFirst() {
+	buffered := w.params.BufferedStarts[:1]
+	for _, start := range w.params.BufferedStarts[1:] {
+		switch w.getOverlapPolicy(start) {
+		case OverlapPolicySkip, OverlapPolicyBufferOne:
+			w.params.Info.OverlapSkipped++
+		default:
+			buffered = append(buffered, start)
+		}
+	}
+	w.params.BufferedStarts = buffered
+}
+
+func (w *scheduleWorkflow) getOverlapPolicy(start BufferedStart) string {
+	if start.OverlapPolicy != "" {
+		return start.OverlapPolicy
+	}
+	return w.params.Schedule.Policies.OverlapPolicy
+}
+
+func (w *scheduleWorkflow) startWorkflow(start BufferedStart) {
+	request := startWorkflowRequest{
+		DomainName:  w.params.DomainName,
+		ScheduleID:  w.params.ScheduleID,
+		Action:      w.params.Schedule.Action,
+		NominalTime: start.NominalTime,
+	}
+	var running RunningWorkflow
+	ctx := workflow.WithActivityOptions(w.ctx, scheduleActivityOptions)
+	if err := workflow.ExecuteActivity(ctx, startWorkflowActivityName, request).Get(ctx, &running); err != nil {
+		w.logger.Error("Failed to start scheduled workflow.", zap.Time("nominal-time", start.NominalTime), zap.Error(err))
+		return
+	}
+
+	info := &w.params.Info
+	info.ActionCount++
+	info.RecentActions = append(info.RecentActions, ActionResult{
+		NominalTime: start.NominalTime,
+		ActualTime:  workflow.Now(w.ctx),
+		WorkflowID:  running.WorkflowID,
+		RunID:       running.RunID,
+	})
+	if len(info.RecentActions) > maxRecentActions {
+		info.RecentActions = info.RecentActions[len(info.RecentActions)-maxRecentActions:]
+	}
+	info.RunningWorkflows = append(info.RunningWorkflows, running)
+	w.watch(running)
+}
+
+func (w *scheduleWorkflow) cancelRunning() {
+	ctx := workflow.WithActivityOptions(w.ctx, scheduleActivityOptions)
+	futures := make([]workflow.Future, 0, len(w.params.Info.RunningWorkflows))
+	for _, running := range w.params.Info.RunningWorkflows {
+		futures = append(futures, workflow.ExecuteActivity(ctx, cancelWorkflowActivityName, w.params.DomainName, running))
+	}
+	for _, future := range futures {
+		if err := future.Get(ctx, nil); err != nil {
+			w.logger.Error("Failed to cancel scheduled workflow.", zap.Error(err))
+		}
+	}
+}
+
+func (w *scheduleWorkflow) watch(running RunningWorkflow) {
+	ctx := workflow.WithActivityOptions(w.ctx, watchActivityOptions)
+	w.watchers[running.WorkflowID] = workflow.ExecuteActivity(ctx, watchWorkflowActivityName, w.params.DomainName, running)
+}
+
+func (w *scheduleWorkflow) onWorkflowClosed(workflowID string) {
+	delete(w.watchers, workflowID)
+	running := w.params.Info.RunningWorkflows[:0]
+	for _, r := range w.params.Info.RunningWorkflows {
+		if r.WorkflowID != workflowID {
+			running = append(running, r)
+		}
+	}
+	w.params.Info.RunningWorkflows = running
+}
+
+// waitForEvent blocks until the next action time, a signal or the close of a running workflow
+func (w *scheduleWorkflow) waitForEvent() {
+	timerCtx, cancelTimer := workflow.WithCancel(w.ctx)
+	defer cancelTimer()
+
+	selector := workflow.NewSelector(w.ctx)
+	now := workflow.Now(w.ctx)
+	if !w.params.Schedule.State.Paused {
+		if next := w.nextActionTimes(w.params.LastProcessedTime, 1); len(next) > 0 {
+			selector.AddFuture(workflow.NewTimer(timerCtx, next[0].Sub(now)), func(workflow.Future) {})
+		}
+	}
+	w.addSignalHandlers(selector)
+	// iterate over the slice rather than the map for the selector to be deterministic
+	for _, running := range w.params.Info.RunningWorkflows {
+		workflowID := running.WorkflowID
+		selector.AddFuture(w.watchers[workflowID], func(f workflow.Future) {
+			if err := f.Get(w.ctx, nil); err != nil {
+				w.logger.Warn("Failed to watch scheduled workflow.", zap.String("workflow-id", workflowID), zap.Error(err))
+			}
+			w.onWorkflowClosed(workflowID)
+		})
+	}
+	selector.Select(w.ctx)
+}
+
+func (w *scheduleWorkflow) addSignalHandlers(selector workflow.Selector) {
+	selector.AddReceive(workflow.GetSignalChannel(w.ctx, SignalNameUpdate), func(c workflow.Channel, more bool) {
+		var schedule Schedule
+		c.Receive(w.ctx, &schedule)
+		w.update(schedule)
+	})
+	selector.AddReceive(workflow.GetSignalChannel(w.ctx, SignalNamePause), func(c workflow.Channel, more bool) {
+		var notes string
+		c.Receive(w.ctx, &notes)
+		w.setPaused(true, notes)
+	})
+	selector.AddReceive(workflow.GetSignalChannel(w.ctx, SignalNameResume), func(c workflow.Channel, more bool) {
+		var notes string
+		c.Receive(w.ctx, &notes)
+		w.setPaused(false, notes)
+	})
+	selector.AddReceive(workflow.GetSignalChannel(w.ctx, SignalNameBackfill), func(c workflow.Channel, more bool) {
+		var request BackfillRequest
+		c.Receive(w.ctx, &request)
+		w.backfill(request)
+	})
+}
+
+// drainSignals handles the pending signals before continuing as new so that none of them is lost
+func (w *scheduleWorkflow) drainSignals() {
+	for {
+		selector := workflow.NewSelector(w.ctx)
+		w.addSignalHandlers(selector)
+		received := true
+		selector.AddDefault(func() { received = false })
+		selector.Select(w.ctx)
+		if !received {
+			return
+		}
+	}
+}
+
+func (w *scheduleWorkflow) update(schedule Schedule) {
+	schedule.setDefaults()
+	if err := schedule.Validate(); err != nil {
+		w.logger.Error("Ignored invalid schedule update.", zap.Error(err))
+		return
+	}
+	spec, err := compileSpec(schedule.Spec)
+	if err != nil {
+		w.logger.Error("Ignored invalid schedule update.", zap.Error(err))
+		return
+	}
+	w.params.Schedule = schedule
+	w.params.Info.UpdateTime = workflow.Now(w.ctx)
+	w.spec = spec
+}
+
+func (w *scheduleWorkflow) setPaused(paused bool, notes string) {
+	now := workflow.Now(w.ctx)
+	if w.params.Schedule.State.Paused && !paused {
+		// the actions of the paused period are not taken
+		w.params.LastProcessedTime = now
+	}
+	w.params.Schedule.State.Paused = paused
+	w.params.Schedule.State.Notes = notes
+	w.params.Info.UpdateTime = now
+}
+
+func (w *scheduleWorkflow) backfill(request BackfillRequest) {
+	if err := request.Validate(); err != nil {
+		w.logger.Error("Ignored invalid backfill request.", zap.Error(err))
+		return
+	}
+	t := w.spec.nextNominalTime(request.StartTime.Add(-time.Nanosecond))
+	for ; !t.IsZero() && !t.After(request.EndTime); t = w.spec.nextNominalTime(t) {
+		if len(w.params.BufferedStarts) >= maxBufferedStarts {
+			w.logger.Warn("Backfill truncated because too many actions are buffered.", zap.Time("nominal-time", t))
+			return
+		}
+		w.bufferStart(BufferedStart{NominalTime: t, ActualTime: t, OverlapPolicy: request.OverlapPolicy})
+	}
+}
+
+// nextActionTimes returns the first actual times strictly after the given time in ascending order
+func (w *scheduleWorkflow) nextActionTimes(after time.Time, count int) []time.Time {
+	var result []time.Time
+	t := w.spec.nextNominalTime(after.Add(-w.params.Schedule.Spec.Jitter))
+	for i := 0; !t.IsZero() && i < maxNominalTimesPerIteration; i++ {
+		// the jitter only delays the actions so no later nominal time can produce an earlier action
+		if len(result) == count && t.After(result[count-1]) {
+			break
+		}
+		if actual := w.spec.actualTime(w.params.ScheduleID, t); actual.After(after) {
+			result = append(result, actual)
+			sort.Slice(result, func(i, j int) bool { return result[i].Before(result[j]) })
+			if len(result) > count {
+				result = result[:count]
+			}
+		}
+		t = w.spec.nextNominalTime(t)
+	}
+	return result
+}
+
+func (w *scheduleWorkflow) isDone() bool {
+	return len(w.params.Info.RunningWorkflows) == 0 &&
+		len(w.params.BufferedStarts) == 0 &&
+		len(w.nextActionTimes(w.params.LastProcessedTime, 1)) == 0
+}
+
+func (w *scheduleWorkflow) describe() (DescribeResult, error) {
+	info := w.params.Info
+	info.FutureActionTimes = nil
+	if !w.params.Schedule.State.Paused {
+		info.FutureActionTimes = w.nextActionTimes(workflow.Now(w.ctx), maxFutureActionTimes)
+	}
+	return DescribeResult{
+		Schedule: w.params.Schedule,
+		Info:     info,
+	}, nil
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/temporal/activity"
+	"go.temporal.io/temporal/testsuite"
+	"go.temporal.io/temporal/workflow"
+)
+
+type (
+	scheduleWorkflowTestSuite struct {
+		suite.Suite
+		testsuite.WorkflowTestSuite
+
+		env       *testsuite.TestWorkflowEnvironment
+		startTime time.Time
+
+		sync.Mutex
+		started  []startWorkflowRequest
+		canceled []RunningWorkflow
+	}
+)
+
+func TestScheduleWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(scheduleWorkflowTestSuite))
+}
+
+func (s *scheduleWorkflowTestSuite) SetupTest() {
+	s.started = nil
+	s.canceled = nil
+	s.startTime = time.Date(2020, 1, 1, 0, 0, 30, 0, time.UTC)
+
+	s.env = s.NewTestWorkflowEnvironment()
+	s.env.SetStartTime(s.startTime)
+	s.env.RegisterWorkflowWithOptions(ScheduleWorkflow, workflow.RegisterOptions{Name: SchedulerWFTypeName})
+	s.env.RegisterActivityWithOptions(StartWorkflowActivity, activity.RegisterOptions{Name: startWorkflowActivityName})
+	s.env.RegisterActivityWithOptions(WatchWorkflowActivity, activity.RegisterOptions{Name: watchWorkflowActivityName})
+	s.env.RegisterActivityWithOptions(CancelWorkflowActivity, activity.RegisterOptions{Name: cancelWorkflowActivityName})
+
+	s.env.OnActivity(startWorkflowActivityName, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, request startWorkflowRequest) (RunningWorkflow, error) {
+			s.Lock()
+			defer s.Unlock()
+			s.started = append(s.started, request)
+			return RunningWorkflow{WorkflowID: request.NominalTime.String(), RunID: "run-id"}, nil
+		})
+	s.env.OnActivity(watchWorkflowActivityName, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	s.env.OnActivity(cancelWorkflowActivityName, mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, domainName string, running RunningWorkflow) error {
+			s.Lock()
+			defer s.Unlock()
+			s.canceled = append(s.canceled, running)
+			return nil
+		})
+}
+
+func (s *scheduleWorkflowTestSuite) TestInterval() {
+	params := s.newParams(Spec{
+		Interval: time.Minute,
+		EndTime:  s.startTime.Add(5 * time.Minute),
+	})
+	s.env.ExecuteWorkflow(SchedulerWFTypeName, params)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	s.Equal(5, len(s.started))
+	for i, request := range s.started {
+		s.Equal(s.startTime.Truncate(time.Minute).Add(time.Duration(i+1)*time.Minute), request.NominalTime)
+		s.Equal("domain", request.DomainName)
+		s.Equal(params.Schedule.Action, request.Action)
+	}
+}
+
+func (s *scheduleWorkflowTestSuite) TestInvalidSchedule() {
+	params := s.newParams(Spec{CronExpressions: []string{"not a cron"}})
+	s.env.ExecuteWorkflow(SchedulerWFTypeName, params)
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+}
+
+func (s *scheduleWorkflowTestSuite) TestCatchupWindow() {
+	params := s.newParams(Spec{
+		Interval: 10 * time.Minute,
+		EndTime:  s.startTime,
+	})
+	params.Schedule.Policies.CatchupWindow = 30 * time.Minute
+	params.Schedule.Policies.OverlapPolicy = OverlapPolicyAllowAll
+	params.LastProcessedTime = s.startTime.Add(-2 * time.Hour)
+	s.env.ExecuteWorkflow(SchedulerWFTypeName, params)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	var result DescribeResult
+	s.describe(&result)
+
+	// the actions of 22:10 to 23:30 are out of the catchup window
+	s.Equal(3, len(s.started))
+	s.Equal(s.startTime.Truncate(time.Minute).Add(-20*time.Minute), s.started[0].NominalTime)
+	s.EqualValues(9, result.Info.MissedCatchupWindow)
+	s.EqualValues(3, result.Info.ActionCount)
+}
+
+func (s *scheduleWorkflowTestSuite) TestOverlapPolicies() {
+	testCases := []struct {
+		policy          string
+		expectedStarted int
+		expectedSkipped int64
+	}{
+		{policy: OverlapPolicySkip, expectedStarted: 2, expectedSkipped: 3},
+		{policy: OverlapPolicyBufferOne, expectedStarted: 3, expectedSkipped: 2},
+		{policy: OverlapPolicyCancelOther, expectedStarted: 5, expectedSkipped: 0},
+		{policy: OverlapPolicyAllowAll, expectedStarted: 5, expectedSkipped: 0},
+	}
+
+	for _, tc := range testCases {
+		s.SetupTest()
+		params := s.newParams(Spec{
+			Interval: time.Minute,
+			EndTime:  s.startTime.Add(2 * time.Minute),
+		})
+		params.Schedule.Policies.OverlapPolicy = tc.policy
+		params.LastProcessedTime = s.startTime.Add(-3 * time.Minute)
+		// the previous run is still considered running when the missed actions are processed
+		params.Info.RunningWorkflows = []RunningWorkflow{{WorkflowID: "previous", RunID: "run-id"}}
+		var result DescribeResult
+		s.env.RegisterDelayedCallback(func() {
+			s.describe(&result)
+		}, time.Second)
+		s.env.ExecuteWorkflow(SchedulerWFTypeName, params)
+		s.True(s.env.IsWorkflowCompleted())
+		s.NoError(s.env.GetWorkflowError())
+
+		s.Equal(tc.expectedStarted, len(s.started), tc.policy)
+		s.Equal(tc.expectedSkipped, result.Info.OverlapSkipped, tc.policy)
+		if tc.policy == OverlapPolicyCancelOther {
+			s.Equal("previous", s.canceled[0].WorkflowID)
+		} else {
+			s.Empty(s.canceled, tc.policy)
+		}
+	}
+}
+
+func (s *scheduleWorkflowTestSuite) TestPauseResume() {
+	params := s.newParams(Spec{
+		CronExpressions: []string{"0 * * * *"},
+		EndTime:         s.startTime.Add(3 * time.Hour),
+	})
+	var paused DescribeResult
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalNamePause, "maintenance")
+	}, 10*time.Minute)
+	s.env.RegisterDelayedCallback(func() {
+		s.describe(&paused)
+	}, 20*time.Minute)
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalNameResume, "")
+	}, 90*time.Minute)
+	s.env.ExecuteWorkflow(SchedulerWFTypeName, params)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	s.True(paused.Schedule.State.Paused)
+	s.Equal("maintenance", paused.Schedule.State.Notes)
+	s.Empty(paused.Info.FutureActionTimes)
+	// the action of 01:00 is not taken while paused
+	s.Equal(2, len(s.started))
+	s.Equal(s.startTime.Truncate(time.Hour).Add(2*time.Hour), s.started[0].NominalTime)
+}
+
+func (s *scheduleWorkflowTestSuite) TestUpdate() {
+	params := s.newParams(Spec{
+		CronExpressions: []string{"0 * * * *"},
+		EndTime:         s.startTime.Add(3 * time.Hour),
+	})
+	updated := params.Schedule
+	updated.Spec = Spec{
+		Interval: 20 * time.Minute,
+		EndTime:  s.startTime.Add(time.Hour),
+	}
+	var result DescribeResult
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalNameUpdate, updated)
+	}, time.Minute)
+	s.env.RegisterDelayedCallback(func() {
+		s.describe(&result)
+	}, 2*time.Minute)
+	s.env.ExecuteWorkflow(SchedulerWFTypeName, params)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	s.Equal(updated.Spec.Interval, result.Schedule.Spec.Interval)
+	s.Equal([]time.Time{
+		s.startTime.Truncate(time.Minute).Add(20 * time.Minute),
+		s.startTime.Truncate(time.Minute).Add(40 * time.Minute),
+		s.startTime.Truncate(time.Minute).Add(60 * time.Minute),
+	}, result.Info.FutureActionTimes)
+	s.Equal(3, len(s.started))
+}
+
+func (s *scheduleWorkflowTestSuite) TestBackfill() {
+	params := s.newParams(Spec{
+		CronExpressions: []string{"0 * * * *"},
+		EndTime:         s.startTime.Add(90 * time.Minute),
+	})
+	params.Schedule.State.Paused = true
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalNameBackfill, BackfillRequest{
+			StartTime:     s.startTime.Add(-5 * time.Hour),
+			EndTime:       s.startTime.Add(-time.Hour),
+			OverlapPolicy: OverlapPolicyAllowAll,
+		})
+	}, time.Minute)
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalNameResume, "")
+	}, 2*time.Minute)
+	s.env.ExecuteWorkflow(SchedulerWFTypeName, params)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	// 4 backfilled actions from 19:00 to 22:00 and the one of 01:00
+	s.Equal(5, len(s.started))
+	s.Equal(time.Date(2019, 12, 31, 20, 0, 0, 0, time.UTC), s.started[0].NominalTime)
+	s.Equal(time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC), s.started[4].NominalTime)
+}
+
+func (s *scheduleWorkflowTestSuite) TestContinueAsNew() {
+	params := s.newParams(Spec{Interval: time.Minute})
+	s.env.ExecuteWorkflow(SchedulerWFTypeName, params)
+	s.True(s.env.IsWorkflowCompleted())
+	_, ok := s.env.GetWorkflowError().(*workflow.ContinueAsNewError)
+	s.True(ok)
+	s.NotEmpty(s.started)
+}
+
+func (s *scheduleWorkflowTestSuite) describe(result *DescribeResult) {
+	value, err := s.env.QueryWorkflow(QueryNameDescribe)
+	s.NoError(err)
+	s.NoError(value.Get(result))
+}
+
+func (s *scheduleWorkflowTestSuite) newParams(spec Spec) Params {
+	return Params{
+		DomainName: "domain",
+		ScheduleID: "schedule-id",
+		Schedule: Schedule{
+			Spec: spec,
+			Action: StartWorkflowAction{
+				WorkflowID:                   "workflow-id",
+				WorkflowType:                 "workflow-type",
+				TaskList:                     "tasklist",
+				ExecutionStartToCloseTimeout: time.Hour,
+			},
+		},
+	}
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/temporalio/temporal/service/worker/parentclosepolicy"
 	"github.com/temporalio/temporal/service/worker/replicator"
 	"github.com/temporalio/temporal/service/worker/scanner"
+	"github.com/temporalio/temporal/service/worker/scheduler"
 )
 
 type (
@@ -66,6 +67,7 @@ type (
 		BatcherCfg                    *batcher.Config
 		ThrottledLogRPS               dynamicconfig.IntPropertyFn
 		EnableBatcher                 dynamicconfig.BoolPropertyFn
+		EnableScheduler               dynamicconfig.BoolPropertyFn
 		EnableParentClosePolicyWorker dynamicconfig.BoolPropertyFn
 	}
 )
@@ -135,6 +137,7 @@ func NewConfig(params *resource.BootstrapParams) *Config {
 			ClusterMetadata:     params.ClusterMetadata,
 		},
 		EnableBatcher:                 dc.GetBoolProperty(dynamicconfig.EnableBatcher, false),
+		EnableScheduler:               dc.GetBoolProperty(dynamicconfig.EnableScheduler, false),
 		EnableParentClosePolicyWorker: dc.GetBoolProperty(dynamicconfig.EnableParentClosePolicyWorker, true),
 		ThrottledLogRPS:               dc.GetIntProperty(dynamicconfig.WorkerThrottledLogRPS, 20),
 	}
@@ -181,6 +184,9 @@ func (s *Service) Start() {
 	if s.config.EnableBatcher() {
 		s.startBatcher()
 	}
+	if s.config.EnableScheduler() {
+		s.startScheduler()
+	}
 	if s.config.EnableParentClosePolicyWorker() {
 		s.startParentClosePolicyProcessor()
 	}
@@ -228,6 +234,18 @@ func (s *Service) startBatcher() {
 	}
 }
 
+func (s *Service) startScheduler() {
+	params := &scheduler.BootstrapParams{
+		ServiceClient:  s.params.PublicClient,
+		FrontendClient: s.GetClientBean().GetFrontendClient(),
+		MetricsClient:  s.GetMetricsClient(),
+		Logger:         s.GetLogger(),
+	}
+	if err := scheduler.New(params).Start(); err != nil {
+		s.GetLogger().Fatal("error starting scheduler", tag.Error(err))
+	}
+}
+
 func (s *Service) startScanner() {
 	params := &scanner.BootstrapParams{
 		Config: *s.config.ScannerCfg,
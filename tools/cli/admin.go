@@ -199,6 +199,52 @@ func newAdminWorkflowCommands() []cli.Command {
 				AdminDeleteWorkflow(c)
 			},
 		},
+		{
+			Name:    "export",
+			Aliases: []string{"exp"},
+			Usage:   "Export the history of a workflow execution to a file",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagWorkflowIDWithAlias,
+					Usage: "WorkflowID",
+				},
+				cli.StringFlag{
+					Name:  FlagRunIDWithAlias,
+					Usage: "RunID, the current run is exported if not set",
+				},
+				cli.StringFlag{
+					Name:  FlagOutputFilenameWithAlias,
+					Usage: "File to write the exported workflow execution to",
+				},
+				cli.IntFlag{
+					Name:  FlagPageSizeWithAlias,
+					Value: 100,
+					Usage: "Number of history batches to fetch per request",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminExportWorkflow(c)
+			},
+		},
+		{
+			Name:    "import",
+			Aliases: []string{"imp"},
+			Usage:   "Import a workflow execution exported from another cluster, the domain must exist and be active in this cluster",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagInputFileWithAlias,
+					Usage: "File with the exported workflow execution",
+				},
+				cli.IntFlag{
+					Name:  FlagBatchSizeWithAlias,
+					Value: 100,
+					Usage: "Number of history batches to send per request",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminImportWorkflow(c)
+			},
+		},
 	}
 }
 
@@ -240,6 +286,34 @@ func newAdminShardManagementCommands() []cli.Command {
 				AdminRemoveTask(c)
 			},
 		},
+		{
+			Name:    "describeOwnership",
+			Aliases: []string{"dsow"},
+			Usage:   "Describe the current owner, lease and ownership history of a shard",
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  FlagShardID,
+					Usage: "ShardID for the temporal cluster to manage",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminDescribeShardOwnership(c)
+			},
+		},
+		{
+			Name:    "describeLoad",
+			Aliases: []string{"dsl"},
+			Usage:   "Describe the request rates, top workflows, cache and persistence usage and queue lag of a shard",
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  FlagShardID,
+					Usage: "ShardID for the temporal cluster to manage",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminDescribeShardLoad(c)
+			},
+		},
 	}
 }
 
@@ -763,6 +837,182 @@ func newAdminTaskListCommands() []cli.Command {
 				AdminDescribeTaskList(c)
 			},
 		},
+		{
+			Name:    "list-tasks",
+			Aliases: []string{"lt"},
+			Usage:   "List the backlog tasks of tasklist",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+				cli.IntFlag{
+					Name:  FlagPageSizeWithAlias,
+					Value: 100,
+					Usage: "Number of tasks to list per page",
+				},
+				cli.BoolFlag{
+					Name:  FlagMoreWithAlias,
+					Usage: "List more pages, default is to list one page",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminListTaskListTasks(c)
+			},
+		},
+		{
+			Name:    "count-tasks",
+			Aliases: []string{"ct"},
+			Usage:   "Count the backlog tasks of tasklist by workflow type",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+				cli.IntFlag{
+					Name:  FlagPageSizeWithAlias,
+					Value: 1000,
+					Usage: "Number of tasks to count per request",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminCountTaskListTasks(c)
+			},
+		},
+		{
+			Name:    "delete-tasks",
+			Aliases: []string{"dt"},
+			Usage:   "Drop tasks from the backlog of tasklist",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskIDsWithAlias,
+					Usage: "Comma separated IDs of the backlog tasks",
+				},
+				cli.StringFlag{
+					Name:  FlagBacklogWithAlias,
+					Usage: "Optional backlog keeping the tasks given by ID, as listed by list-tasks, default is the backlog of the tasklist itself",
+				},
+				cli.StringFlag{
+					Name:  FlagWorkflowIDWithAlias,
+					Usage: "Select all backlog tasks of this WorkflowID instead of task IDs",
+				},
+				cli.StringFlag{
+					Name:  FlagRunIDWithAlias,
+					Usage: "Optional RunID to narrow down the tasks selected by WorkflowID",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminDeleteTaskListTasks(c)
+			},
+		},
+		{
+			Name:    "move-tasks",
+			Aliases: []string{"mt"},
+			Usage:   "Move tasks from the backlog of tasklist to another tasklist of the same type",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskIDsWithAlias,
+					Usage: "Comma separated IDs of the backlog tasks",
+				},
+				cli.StringFlag{
+					Name:  FlagBacklogWithAlias,
+					Usage: "Optional backlog keeping the tasks given by ID, as listed by list-tasks, default is the backlog of the tasklist itself",
+				},
+				cli.StringFlag{
+					Name:  FlagWorkflowIDWithAlias,
+					Usage: "Select all backlog tasks of this WorkflowID instead of task IDs",
+				},
+				cli.StringFlag{
+					Name:  FlagRunIDWithAlias,
+					Usage: "Optional RunID to narrow down the tasks selected by WorkflowID",
+				},
+				cli.StringFlag{
+					Name:  FlagTargetTaskListWithAlias,
+					Usage: "TaskList to move the tasks to",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminMoveTaskListTasks(c)
+			},
+		},
+		{
+			Name:    "describe-dispatch-config",
+			Aliases: []string{"ddc"},
+			Usage:   "Describe the dispatch rate and poller limits of tasklist",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminDescribeTaskListDispatchConfig(c)
+			},
+		},
+		{
+			Name:    "update-dispatch-config",
+			Aliases: []string{"udc"},
+			Usage:   "Update the dispatch rate and poller limits of tasklist, options not given keep their current value",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+				cli.Float64Flag{
+					Name:  FlagMaxTasksPerSecondWithAlias,
+					Usage: "Max tasks dispatched per second by the whole tasklist, 0 leaves the rate to pollers",
+				},
+				cli.BoolTFlag{
+					Name:  FlagPinRate,
+					Usage: "Whether pollers are prevented from overriding the rate [true|false]",
+				},
+				cli.IntFlag{
+					Name:  FlagMaxConcurrentPollersWithAlias,
+					Usage: "Max concurrent pollers of the whole tasklist, 0 means unlimited",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminUpdateTaskListDispatchConfig(c)
+			},
+		},
 	}
 }
 
@@ -880,3 +1130,26 @@ func newAdminDLQCommands() []cli.Command {
 		},
 	}
 }
+
+func newAdminConfigCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:    "list",
+			Aliases: []string{"l"},
+			Usage:   "List the effective dynamic config values for a set of filters",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagNameWithAlias,
+					Usage: "Optional dynamic config key, all keys are listed if not set",
+				},
+				cli.StringSliceFlag{
+					Name:  FlagDynamicConfigFilter,
+					Usage: "Filter in the format of name=value, e.g. domainName=samples, can be repeated",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminListDynamicConfig(c)
+			},
+		},
+	}
+}
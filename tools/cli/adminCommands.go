@@ -464,3 +464,108 @@ func AdminRefreshWorkflowTasks(c *cli.Context) {
 		fmt.Println("Refresh workflow task succeeded.")
 	}
 }
+
+// workflowExport is the portable file format of an exported workflow execution
+type workflowExport struct {
+	Domain         string                         `json:"domain"`
+	Execution      *commonproto.WorkflowExecution `json:"execution"`
+	VersionHistory *commonproto.VersionHistory    `json:"versionHistory"`
+	HistoryBatches []*commonproto.DataBlob        `json:"historyBatches"`
+}
+
+// AdminExportWorkflow exports the history of a workflow execution to a file
+func AdminExportWorkflow(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	wid := getRequiredOption(c, FlagWorkflowID)
+	rid := c.String(FlagRunID)
+	outputFileName := getRequiredOption(c, FlagOutputFilename)
+
+	export := &workflowExport{
+		Domain: domain,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: wid,
+			RunId:      rid,
+		},
+	}
+	var token []byte
+	for {
+		ctx, cancel := newContext(c)
+		resp, err := adminClient.ExportWorkflowExecution(ctx, &adminservice.ExportWorkflowExecutionRequest{
+			Domain:          domain,
+			Execution:       export.Execution,
+			MaximumPageSize: int32(c.Int(FlagPageSize)),
+			NextPageToken:   token,
+		})
+		cancel()
+		if err != nil {
+			ErrorAndExit("Export workflow execution failed.", err)
+		}
+		if token == nil {
+			export.Execution = resp.GetExecution()
+		}
+		export.VersionHistory = resp.GetVersionHistory()
+		export.HistoryBatches = append(export.HistoryBatches, resp.GetHistoryBatches()...)
+
+		token = resp.GetNextPageToken()
+		if len(token) == 0 {
+			break
+		}
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		ErrorAndExit("Failed to serialize exported workflow execution.", err)
+	}
+	if err := ioutil.WriteFile(outputFileName, data, 0644); err != nil {
+		ErrorAndExit("Failed to write export file.", err)
+	}
+	fmt.Printf("Exported %v history batches of workflow %v, run %v to %v.\n",
+		len(export.HistoryBatches), export.Execution.GetWorkflowId(), export.Execution.GetRunId(), outputFileName)
+}
+
+// AdminImportWorkflow imports a workflow execution exported by AdminExportWorkflow
+func AdminImportWorkflow(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+
+	inputFileName := getRequiredOption(c, FlagInputFile)
+	data, err := ioutil.ReadFile(inputFileName)
+	if err != nil {
+		ErrorAndExit("Failed to read export file.", err)
+	}
+	export := &workflowExport{}
+	if err := json.Unmarshal(data, export); err != nil {
+		ErrorAndExit("Failed to deserialize exported workflow execution.", err)
+	}
+
+	// the domain of the exported workflow is used unless a target domain is given
+	domain := c.GlobalString(FlagDomain)
+	if domain == "" {
+		domain = export.Domain
+	}
+	batchSize := c.Int(FlagBatchSize)
+	if batchSize <= 0 {
+		ErrorAndExit("Batch size must be positive.", nil)
+	}
+
+	for start := 0; start < len(export.HistoryBatches); start += batchSize {
+		end := start + batchSize
+		if end > len(export.HistoryBatches) {
+			end = len(export.HistoryBatches)
+		}
+		ctx, cancel := newContext(c)
+		_, err := adminClient.ImportWorkflowExecution(ctx, &adminservice.ImportWorkflowExecutionRequest{
+			Domain:         domain,
+			Execution:      export.Execution,
+			VersionHistory: export.VersionHistory,
+			HistoryBatches: export.HistoryBatches[start:end],
+		})
+		cancel()
+		if err != nil {
+			ErrorAndExit("Import workflow execution failed.", err)
+		}
+	}
+	fmt.Printf("Imported %v history batches of workflow %v, run %v into domain %v.\n",
+		len(export.HistoryBatches), export.Execution.GetWorkflowId(), export.Execution.GetRunId(), domain)
+}
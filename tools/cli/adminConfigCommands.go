@@ -0,0 +1,68 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+)
+
+// AdminListDynamicConfig lists the effective dynamic config values for a set of filters
+func AdminListDynamicConfig(c *cli.Context) {
+	var filters []*adminservice.DynamicConfigFilter
+	for _, f := range c.StringSlice(FlagDynamicConfigFilter) {
+		parts := strings.SplitN(f, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			ErrorAndExit("Filter must be in the format of name=value.", nil)
+		}
+		filters = append(filters, &adminservice.DynamicConfigFilter{
+			Name:  parts[0],
+			Value: parts[1],
+		})
+	}
+
+	adminClient := cFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+	response, err := adminClient.ListDynamicConfig(ctx, &adminservice.ListDynamicConfigRequest{
+		Name:    c.String(FlagName),
+		Filters: filters,
+	})
+	if err != nil {
+		ErrorAndExit("Operation ListDynamicConfig failed.", err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("|")
+	table.SetHeader([]string{"Name", "Value"})
+	table.SetHeaderLine(false)
+	table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue)
+	for _, entry := range response.GetEntries() {
+		table.Append([]string{entry.GetName(), entry.GetValue()})
+	}
+	table.Render()
+}
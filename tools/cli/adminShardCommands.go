@@ -0,0 +1,150 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+)
+
+// AdminDescribeShardOwnership describes the current owner and ownership history of a shard
+func AdminDescribeShardOwnership(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+	sid := getRequiredIntOption(c, FlagShardID)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	resp, err := adminClient.DescribeShardOwnership(ctx, &adminservice.DescribeShardOwnershipRequest{
+		ShardID: int32(sid),
+	})
+	if err != nil {
+		ErrorAndExit("Operation DescribeShardOwnership failed.", err)
+	}
+
+	fmt.Printf("Owner: %v\n", resp.GetOwner())
+	fmt.Printf("Range ID: %v\n", resp.GetRangeID())
+	fmt.Printf("Lease expiry: %v\n", formatShardOwnershipTime(resp.GetLeaseExpiry()))
+	fmt.Printf("Released: %v\n\n", resp.GetReleased())
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("|")
+	table.SetHeader([]string{"Owner", "Range ID", "Acquired", "Released", "Acquisition", "Previous Owner"})
+	table.SetHeaderLine(false)
+	table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue)
+	for _, record := range resp.GetOwnershipHistory() {
+		table.Append([]string{
+			record.GetOwner(),
+			fmt.Sprintf("%v", record.GetRangeID()),
+			formatShardOwnershipTime(record.GetAcquiredAt()),
+			formatShardOwnershipTime(record.GetReleasedAt()),
+			strings.TrimPrefix(record.GetAcquisitionType().String(), "Acquisition"),
+			record.GetPreviousOwner(),
+		})
+	}
+	table.Render()
+}
+
+func formatShardOwnershipTime(ts *types.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	t, err := types.TimestampFromProto(ts)
+	if err != nil {
+		return ""
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+// AdminDescribeShardLoad describes the load of a shard and the workflows issuing most of its requests
+func AdminDescribeShardLoad(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+	sid := getRequiredIntOption(c, FlagShardID)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	resp, err := adminClient.DescribeShardLoad(ctx, &adminservice.DescribeShardLoadRequest{
+		ShardID: int32(sid),
+	})
+	if err != nil {
+		ErrorAndExit("Operation DescribeShardLoad failed.", err)
+	}
+
+	load := resp.GetShardLoad()
+	fmt.Printf("Shard: %v\n", load.GetShardID())
+	fmt.Printf("Address: %v\n", load.GetAddress())
+	fmt.Printf("Window: %v\n", time.Duration(load.GetWindowSeconds())*time.Second)
+	fmt.Printf("RPS: %.2f\n", load.GetRequestsPerSecond())
+	fmt.Printf("Hot: %v\n", load.GetHot())
+	fmt.Printf("Cache hits/misses: %v/%v\n", load.GetCacheHits(), load.GetCacheMisses())
+	fmt.Printf("Persistence requests: %v, average latency: %v\n",
+		load.GetPersistenceRequests(), time.Duration(load.GetPersistenceAverageLatencyMillis())*time.Millisecond)
+	fmt.Printf("Transfer queue lag: %v tasks\n", load.GetTransferQueueLag())
+	fmt.Printf("Timer queue lag: %v\n\n", time.Duration(load.GetTimerQueueLagSeconds())*time.Second)
+
+	table := newShardLoadTable([]string{"API", "Requests", "RPS"})
+	for _, api := range load.GetApis() {
+		table.Append([]string{
+			api.GetApi(),
+			fmt.Sprintf("%v", api.GetRequests()),
+			fmt.Sprintf("%.2f", api.GetRequestsPerSecond()),
+		})
+	}
+	table.Render()
+	fmt.Println()
+
+	table = newShardLoadTable([]string{"Domain ID", "Workflow ID", "Requests", "RPS", "Share", "Throttled"})
+	for _, workflow := range load.GetTopWorkflows() {
+		table.Append([]string{
+			workflow.GetDomainID(),
+			workflow.GetWorkflowID(),
+			fmt.Sprintf("%v", workflow.GetRequests()),
+			fmt.Sprintf("%.2f", workflow.GetRequestsPerSecond()),
+			fmt.Sprintf("%.1f%%", workflow.GetShare()*100),
+			fmt.Sprintf("%v", workflow.GetThrottled()),
+		})
+	}
+	table.Render()
+}
+
+func newShardLoadTable(header []string) *tablewriter.Table {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("|")
+	table.SetHeader(header)
+	table.SetHeaderLine(false)
+	colors := make([]tablewriter.Colors, len(header))
+	for i := range colors {
+		colors[i] = tableHeaderBlue
+	}
+	table.SetHeaderColor(colors...)
+	return table
+}
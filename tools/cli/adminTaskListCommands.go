@@ -23,6 +23,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -31,6 +32,9 @@ import (
 	commonproto "go.temporal.io/temporal-proto/common"
 	"go.temporal.io/temporal-proto/enums"
 	"go.temporal.io/temporal-proto/workflowservice"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
 )
 
 // AdminDescribeTaskList displays poller and status information of task list.
@@ -38,10 +42,7 @@ func AdminDescribeTaskList(c *cli.Context) {
 	frontendClient := cFactory.FrontendClient(c)
 	domain := getRequiredGlobalOption(c, FlagDomain)
 	taskList := getRequiredOption(c, FlagTaskList)
-	taskListType := enums.TaskListTypeDecision
-	if strings.ToLower(c.String(FlagTaskListType)) == "activity" {
-		taskListType = enums.TaskListTypeActivity
-	}
+	taskListType := getTaskListType(c)
 
 	ctx, cancel := newContext(c)
 	defer cancel()
@@ -104,3 +105,326 @@ func printPollerInfo(pollers []*commonproto.PollerInfo, taskListType enums.TaskL
 	}
 	table.Render()
 }
+
+// AdminListTaskListTasks displays the backlog tasks of task list
+func AdminListTaskListTasks(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := getTaskListType(c)
+	more := c.Bool(FlagMore)
+
+	var nextPageToken []byte
+	for {
+		ctx, cancel := newContext(c)
+		resp, err := adminClient.ListTaskListTasks(ctx, &adminservice.ListTaskListTasksRequest{
+			Domain:          domain,
+			TaskList:        &commonproto.TaskList{Name: taskList},
+			TaskListType:    taskListType,
+			MaximumPageSize: int32(c.Int(FlagPageSize)),
+			NextPageToken:   nextPageToken,
+		})
+		cancel()
+		if err != nil {
+			ErrorAndExit("Operation ListTaskListTasks failed.", err)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetBorder(false)
+		table.SetColumnSeparator("|")
+		table.SetHeader([]string{"Task ID", "Workflow ID", "Run ID", "Schedule ID", "Created Time", "Expiry", "Backlog"})
+		table.SetHeaderLine(false)
+		table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue)
+		for _, task := range resp.Tasks {
+			table.Append([]string{strconv.FormatInt(task.GetTaskId(), 10),
+				task.GetExecution().GetWorkflowId(),
+				task.GetExecution().GetRunId(),
+				strconv.FormatInt(task.GetScheduleId(), 10),
+				convertTime(task.GetCreatedTimeUnixNano(), false),
+				convertTime(task.GetExpiryUnixNano(), false),
+				task.GetBacklog()})
+		}
+		table.Render()
+
+		nextPageToken = resp.GetNextPageToken()
+		if !more || len(nextPageToken) == 0 || !showNextPage() {
+			break
+		}
+	}
+}
+
+// AdminCountTaskListTasks displays the number of backlog tasks of task list by workflow type
+func AdminCountTaskListTasks(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := getTaskListType(c)
+
+	counts := make(map[string]int64)
+	var nextPageToken []byte
+	for {
+		ctx, cancel := newContext(c)
+		resp, err := adminClient.CountTaskListTasks(ctx, &adminservice.CountTaskListTasksRequest{
+			Domain:          domain,
+			TaskList:        &commonproto.TaskList{Name: taskList},
+			TaskListType:    taskListType,
+			MaximumPageSize: int32(c.Int(FlagPageSize)),
+			NextPageToken:   nextPageToken,
+		})
+		cancel()
+		if err != nil {
+			ErrorAndExit("Operation CountTaskListTasks failed.", err)
+		}
+		for workflowType, count := range resp.GetCountByWorkflowType() {
+			counts[workflowType] += count
+		}
+
+		nextPageToken = resp.GetNextPageToken()
+		if len(nextPageToken) == 0 {
+			break
+		}
+	}
+
+	workflowTypes := make([]string, 0, len(counts))
+	for workflowType := range counts {
+		workflowTypes = append(workflowTypes, workflowType)
+	}
+	sort.Slice(workflowTypes, func(i, j int) bool {
+		return counts[workflowTypes[i]] > counts[workflowTypes[j]]
+	})
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("|")
+	table.SetHeader([]string{"Workflow Type", "Task Count"})
+	table.SetHeaderLine(false)
+	table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue)
+	for _, workflowType := range workflowTypes {
+		name := workflowType
+		if name == "" {
+			name = "<workflow not found>"
+		}
+		table.Append([]string{name, strconv.FormatInt(counts[workflowType], 10)})
+	}
+	table.Render()
+}
+
+// AdminDeleteTaskListTasks drops the selected tasks from the backlog of task list
+func AdminDeleteTaskListTasks(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := getTaskListType(c)
+	backlogs, taskIDs := getSelectedTaskListTasks(c, domain, taskList, taskListType)
+	if len(backlogs) == 0 {
+		fmt.Println("No tasks selected.")
+		return
+	}
+
+	var deletedCount int
+	for _, backlog := range backlogs {
+		ctx, cancel := newContext(c)
+		_, err := adminClient.DeleteTaskListTasks(ctx, &adminservice.DeleteTaskListTasksRequest{
+			Domain:       domain,
+			TaskList:     &commonproto.TaskList{Name: taskList},
+			TaskListType: taskListType,
+			TaskIds:      taskIDs[backlog],
+			Backlog:      backlog,
+		})
+		cancel()
+		if err != nil {
+			ErrorAndExit("Operation DeleteTaskListTasks failed.", err)
+		}
+		deletedCount += len(taskIDs[backlog])
+	}
+	fmt.Printf("Deleted %v tasks from tasklist %v.\n", deletedCount, taskList)
+}
+
+// AdminMoveTaskListTasks moves the selected tasks from the backlog of task list to another task list
+func AdminMoveTaskListTasks(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	targetTaskList := getRequiredOption(c, FlagTargetTaskList)
+	taskListType := getTaskListType(c)
+	backlogs, taskIDs := getSelectedTaskListTasks(c, domain, taskList, taskListType)
+	if len(backlogs) == 0 {
+		fmt.Println("No tasks selected.")
+		return
+	}
+
+	var movedCount int32
+	var selectedCount int
+	for _, backlog := range backlogs {
+		ctx, cancel := newContext(c)
+		resp, err := adminClient.MoveTaskListTasks(ctx, &adminservice.MoveTaskListTasksRequest{
+			Domain:         domain,
+			TaskList:       &commonproto.TaskList{Name: taskList},
+			TaskListType:   taskListType,
+			TaskIds:        taskIDs[backlog],
+			TargetTaskList: &commonproto.TaskList{Name: targetTaskList},
+			Backlog:        backlog,
+		})
+		cancel()
+		if err != nil {
+			ErrorAndExit("Operation MoveTaskListTasks failed.", err)
+		}
+		movedCount += resp.GetMovedCount()
+		selectedCount += len(taskIDs[backlog])
+	}
+	fmt.Printf("Moved %v of %v tasks from tasklist %v to %v.\n", movedCount, selectedCount, taskList, targetTaskList)
+}
+
+// AdminDescribeTaskListDispatchConfig displays the dispatch config of task list and the dispatch status of its partitions
+func AdminDescribeTaskListDispatchConfig(c *cli.Context) {
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := getTaskListType(c)
+
+	resp := describeTaskListDispatchConfig(c, domain, taskList, taskListType)
+	dispatchConfig := resp.GetDispatchConfig()
+	fmt.Printf("Max tasks per second: %v\n", formatDispatchLimit(strconv.FormatFloat(dispatchConfig.GetMaxTasksPerSecond(), 'f', -1, 64)))
+	fmt.Printf("Rate pinned: %v\n", dispatchConfig.GetMaxTasksPerSecondPinned())
+	fmt.Printf("Max concurrent pollers: %v\n\n", formatDispatchLimit(strconv.Itoa(int(dispatchConfig.GetMaxConcurrentPollers()))))
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("|")
+	table.SetHeader([]string{"Partition", "Dispatch Rate", "Outstanding Pollers"})
+	table.SetHeaderLine(false)
+	table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue, tableHeaderBlue)
+	for _, partition := range resp.Partitions {
+		table.Append([]string{partition.GetPartition(),
+			strconv.FormatFloat(partition.GetRatePerSecond(), 'f', 2, 64),
+			strconv.Itoa(int(partition.GetOutstandingPollers()))})
+	}
+	table.Render()
+}
+
+// AdminUpdateTaskListDispatchConfig updates the dispatch rate and poller limits of task list
+func AdminUpdateTaskListDispatchConfig(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := getTaskListType(c)
+	if !c.IsSet(FlagMaxTasksPerSecond) && !c.IsSet(FlagPinRate) && !c.IsSet(FlagMaxConcurrentPollers) {
+		ErrorAndExit(fmt.Sprintf("Option %s, %s or %s is required", FlagMaxTasksPerSecond, FlagPinRate, FlagMaxConcurrentPollers), nil)
+	}
+
+	dispatchConfig := &persistenceblobs.TaskListDispatchConfig{}
+	if current := describeTaskListDispatchConfig(c, domain, taskList, taskListType).GetDispatchConfig(); current != nil {
+		*dispatchConfig = *current
+	}
+	if c.IsSet(FlagMaxTasksPerSecond) {
+		dispatchConfig.MaxTasksPerSecond = c.Float64(FlagMaxTasksPerSecond)
+	}
+	if c.IsSet(FlagPinRate) {
+		dispatchConfig.MaxTasksPerSecondPinned = c.BoolT(FlagPinRate)
+	}
+	if c.IsSet(FlagMaxConcurrentPollers) {
+		dispatchConfig.MaxConcurrentPollers = int32(c.Int(FlagMaxConcurrentPollers))
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	_, err := adminClient.UpdateTaskListDispatchConfig(ctx, &adminservice.UpdateTaskListDispatchConfigRequest{
+		Domain:         domain,
+		TaskList:       &commonproto.TaskList{Name: taskList},
+		TaskListType:   taskListType,
+		DispatchConfig: dispatchConfig,
+	})
+	if err != nil {
+		ErrorAndExit("Operation UpdateTaskListDispatchConfig failed.", err)
+	}
+	fmt.Printf("Updated dispatch config of tasklist %v.\n", taskList)
+}
+
+func describeTaskListDispatchConfig(
+	c *cli.Context,
+	domain string,
+	taskList string,
+	taskListType enums.TaskListType,
+) *adminservice.DescribeTaskListDispatchConfigResponse {
+
+	adminClient := cFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+	resp, err := adminClient.DescribeTaskListDispatchConfig(ctx, &adminservice.DescribeTaskListDispatchConfigRequest{
+		Domain:       domain,
+		TaskList:     &commonproto.TaskList{Name: taskList},
+		TaskListType: taskListType,
+	})
+	if err != nil {
+		ErrorAndExit("Operation DescribeTaskListDispatchConfig failed.", err)
+	}
+	return resp
+}
+
+func formatDispatchLimit(limit string) string {
+	if limit == "0" {
+		return "unlimited"
+	}
+	return limit
+}
+
+func getTaskListType(c *cli.Context) enums.TaskListType {
+	if strings.ToLower(c.String(FlagTaskListType)) == "activity" {
+		return enums.TaskListTypeActivity
+	}
+	return enums.TaskListTypeDecision
+}
+
+// getSelectedTaskListTasks returns the task IDs given by flag, or the IDs of the backlog tasks of the given workflow,
+// grouped by the backlog keeping them. The backlogs are returned in the order they are listed.
+func getSelectedTaskListTasks(c *cli.Context, domain string, taskList string, taskListType enums.TaskListType) ([]string, map[string][]int64) {
+	var backlogs []string
+	taskIDs := make(map[string][]int64)
+	if c.IsSet(FlagTaskIDs) {
+		backlog := c.String(FlagBacklog)
+		for _, value := range strings.Split(c.String(FlagTaskIDs), ",") {
+			taskID, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				ErrorAndExit(fmt.Sprintf("Invalid task ID %v.", value), err)
+			}
+			taskIDs[backlog] = append(taskIDs[backlog], taskID)
+		}
+		return []string{backlog}, taskIDs
+	}
+
+	if !c.IsSet(FlagWorkflowID) {
+		ErrorAndExit(fmt.Sprintf("Option %s or %s is required", FlagTaskIDs, FlagWorkflowID), nil)
+	}
+	wid := c.String(FlagWorkflowID)
+	rid := c.String(FlagRunID)
+
+	adminClient := cFactory.AdminClient(c)
+	var nextPageToken []byte
+	for {
+		ctx, cancel := newContext(c)
+		resp, err := adminClient.ListTaskListTasks(ctx, &adminservice.ListTaskListTasksRequest{
+			Domain:          domain,
+			TaskList:        &commonproto.TaskList{Name: taskList},
+			TaskListType:    taskListType,
+			MaximumPageSize: 1000,
+			NextPageToken:   nextPageToken,
+		})
+		cancel()
+		if err != nil {
+			ErrorAndExit("Operation ListTaskListTasks failed.", err)
+		}
+		for _, task := range resp.Tasks {
+			if task.GetExecution().GetWorkflowId() == wid && (rid == "" || task.GetExecution().GetRunId() == rid) {
+				if _, ok := taskIDs[task.GetBacklog()]; !ok {
+					backlogs = append(backlogs, task.GetBacklog())
+				}
+				taskIDs[task.GetBacklog()] = append(taskIDs[task.GetBacklog()], task.GetTaskId())
+			}
+		}
+
+		nextPageToken = resp.GetNextPageToken()
+		if len(nextPageToken) == 0 {
+			break
+		}
+	}
+	return backlogs, taskIDs
+}
@@ -80,6 +80,12 @@ func NewCliApp() *cli.App {
 			Usage:       "Operate temporal tasklist",
 			Subcommands: newTaskListCommands(),
 		},
+		{
+			Name:        "schedule",
+			Aliases:     []string{"sch"},
+			Usage:       "Operate temporal schedule",
+			Subcommands: newScheduleCommands(),
+		},
 		{
 			Name:    "admin",
 			Aliases: []string{"adm"},
@@ -139,6 +145,12 @@ func NewCliApp() *cli.App {
 					Usage:       "Run admin operation on DLQ",
 					Subcommands: newAdminDLQCommands(),
 				},
+				{
+					Name:        "config",
+					Aliases:     []string{"conf"},
+					Usage:       "Run admin operation on dynamic config",
+					Subcommands: newAdminConfigCommands(),
+				},
 			},
 		},
 		{
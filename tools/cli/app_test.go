@@ -42,6 +42,8 @@ import (
 
 	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/.gen/proto/adminservicemock"
+	"github.com/temporalio/temporal/.gen/proto/scheduleservice"
+	"github.com/temporalio/temporal/.gen/proto/updateservice"
 )
 
 type cliAppSuite struct {
@@ -56,6 +58,8 @@ type cliAppSuite struct {
 type clientFactoryMock struct {
 	frontendClient    workflowservice.WorkflowServiceClient
 	serverAdminClient adminservice.AdminServiceClient
+	scheduleClient    scheduleservice.ScheduleServiceClient
+	updateClient      updateservice.UpdateServiceClient
 	sdkClient         *sdkmocks.Client
 }
 
@@ -67,6 +71,14 @@ func (m *clientFactoryMock) AdminClient(c *cli.Context) adminservice.AdminServic
 	return m.serverAdminClient
 }
 
+func (m *clientFactoryMock) ScheduleClient(c *cli.Context) scheduleservice.ScheduleServiceClient {
+	return m.scheduleClient
+}
+
+func (m *clientFactoryMock) UpdateClient(c *cli.Context) updateservice.UpdateServiceClient {
+	return m.updateClient
+}
+
 func (m *clientFactoryMock) SDKClient(c *cli.Context, domain string) sdkclient.Client {
 	return m.sdkClient
 }
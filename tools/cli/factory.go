@@ -28,6 +28,8 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/.gen/proto/scheduleservice"
+	"github.com/temporalio/temporal/.gen/proto/updateservice"
 	"github.com/temporalio/temporal/common/rpc"
 )
 
@@ -35,6 +37,8 @@ import (
 type ClientFactory interface {
 	FrontendClient(c *cli.Context) workflowservice.WorkflowServiceClient
 	AdminClient(c *cli.Context) adminservice.AdminServiceClient
+	ScheduleClient(c *cli.Context) scheduleservice.ScheduleServiceClient
+	UpdateClient(c *cli.Context) updateservice.UpdateServiceClient
 	SDKClient(c *cli.Context, domain string) sdkclient.Client
 }
 
@@ -68,6 +72,20 @@ func (b *clientFactory) AdminClient(c *cli.Context) adminservice.AdminServiceCli
 	return adminservice.NewAdminServiceClient(connection)
 }
 
+// ScheduleClient builds a schedule client
+func (b *clientFactory) ScheduleClient(c *cli.Context) scheduleservice.ScheduleServiceClient {
+	connection := b.createGRPCConnection(c.GlobalString(FlagAddress))
+
+	return scheduleservice.NewScheduleServiceClient(connection)
+}
+
+// UpdateClient builds a workflow update client
+func (b *clientFactory) UpdateClient(c *cli.Context) updateservice.UpdateServiceClient {
+	connection := b.createGRPCConnection(c.GlobalString(FlagAddress))
+
+	return updateservice.NewUpdateServiceClient(connection)
+}
+
 // AdminClient builds an admin client (based on server side thrift interface)
 func (b *clientFactory) SDKClient(c *cli.Context, domain string) sdkclient.Client {
 	hostPort := c.GlobalString(FlagAddress)
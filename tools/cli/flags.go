@@ -210,6 +210,28 @@ const (
 	FlagMaxMessageCountWithAlias          = FlagMaxMessageCount + ", mmc"
 	FlagLastMessageID                     = "last_message_id"
 	FlagLastMessageIDWithAlias            = FlagLastMessageID + ", lm"
+	FlagScheduleID                        = "schedule_id"
+	FlagScheduleIDWithAlias               = FlagScheduleID + ", sid"
+	FlagInterval                          = "interval"
+	FlagJitter                            = "jitter"
+	FlagStartTime                         = "start_time"
+	FlagEndTime                           = "end_time"
+	FlagOverlapPolicy                     = "overlap_policy"
+	FlagCatchupWindow                     = "catchup_window"
+	FlagPaused                            = "paused"
+	FlagNotes                             = "notes"
+	FlagDynamicConfigFilter               = "filter"
+	FlagTaskIDs                           = "task_ids"
+	FlagTaskIDsWithAlias                  = FlagTaskIDs + ", tids"
+	FlagBacklog                           = "backlog"
+	FlagBacklogWithAlias                  = FlagBacklog + ", bl"
+	FlagTargetTaskList                    = "target_tasklist"
+	FlagTargetTaskListWithAlias           = FlagTargetTaskList + ", ttl"
+	FlagMaxTasksPerSecond                 = "max_tasks_per_second"
+	FlagMaxTasksPerSecondWithAlias        = FlagMaxTasksPerSecond + ", mtps"
+	FlagPinRate                           = "pin_rate"
+	FlagMaxConcurrentPollers              = "max_concurrent_pollers"
+	FlagMaxConcurrentPollersWithAlias     = FlagMaxConcurrentPollers + ", mcp"
 )
 
 var flagsForExecution = []cli.Flag{
@@ -0,0 +1,213 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import "github.com/urfave/cli"
+
+func newScheduleCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:    "create",
+			Aliases: []string{"c"},
+			Usage:   "Create a schedule which starts a workflow at the times of its spec",
+			Flags:   append(getFlagsForScheduleID(), getFlagsForScheduleDefinition()...),
+			Action: func(c *cli.Context) {
+				CreateSchedule(c)
+			},
+		},
+		{
+			Name:    "describe",
+			Aliases: []string{"desc"},
+			Usage:   "Describe the definition and the state of a schedule",
+			Flags: append(getFlagsForScheduleID(),
+				cli.BoolFlag{
+					Name:  FlagPrintJSONWithAlias,
+					Usage: "Print in raw json format",
+				}),
+			Action: func(c *cli.Context) {
+				DescribeSchedule(c)
+			},
+		},
+		{
+			Name:    "update",
+			Aliases: []string{"u"},
+			Usage:   "Replace the definition of a schedule",
+			Flags:   append(getFlagsForScheduleID(), getFlagsForScheduleDefinition()...),
+			Action: func(c *cli.Context) {
+				UpdateSchedule(c)
+			},
+		},
+		{
+			Name:  "pause",
+			Usage: "Pause a schedule",
+			Flags: append(getFlagsForScheduleID(),
+				cli.StringFlag{
+					Name:  FlagNotes,
+					Usage: "Optional notes about the pause",
+				}),
+			Action: func(c *cli.Context) {
+				PauseSchedule(c)
+			},
+		},
+		{
+			Name:  "resume",
+			Usage: "Resume a paused schedule, the times of the paused period are not caught up",
+			Flags: append(getFlagsForScheduleID(),
+				cli.StringFlag{
+					Name:  FlagNotes,
+					Usage: "Optional notes about the resume",
+				}),
+			Action: func(c *cli.Context) {
+				ResumeSchedule(c)
+			},
+		},
+		{
+			Name:  "backfill",
+			Usage: "Take the actions of all the times of a schedule within a past time range",
+			Flags: append(getFlagsForScheduleID(),
+				cli.StringFlag{
+					Name: FlagStartTime,
+					Usage: "Start of the backfill range. Supported formats are '2006-01-02T15:04:05+07:00', raw UnixNano and " +
+						"time range (N<duration>), where 0 < N < 1000000 and duration (full-notation/short-notation) can be " +
+						"second/s, minute/m, hour/h, day/d, week/w, month/M or year/y",
+				},
+				cli.StringFlag{
+					Name:  FlagEndTime,
+					Usage: "End of the backfill range, defaults to now. Same formats as start_time",
+				},
+				cli.StringFlag{
+					Name:  FlagOverlapPolicy,
+					Usage: "Optional overlap policy of the backfilled actions [skip|buffer_one|cancel_other|allow_all], defaults to the policy of the schedule",
+				}),
+			Action: func(c *cli.Context) {
+				BackfillSchedule(c)
+			},
+		},
+		{
+			Name:    "delete",
+			Aliases: []string{"del"},
+			Usage:   "Delete a schedule, the workflows it started are not affected",
+			Flags:   getFlagsForScheduleID(),
+			Action: func(c *cli.Context) {
+				DeleteSchedule(c)
+			},
+		},
+		{
+			Name:    "list",
+			Aliases: []string{"l"},
+			Usage:   "List the schedules of a domain",
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  FlagPageSizeWithAlias,
+					Value: 100,
+					Usage: "Result page size",
+				},
+				cli.BoolFlag{
+					Name:  FlagMoreWithAlias,
+					Usage: "List more pages, default is to list one page of default page size 100",
+				},
+			},
+			Action: func(c *cli.Context) {
+				ListSchedules(c)
+			},
+		},
+	}
+}
+
+func getFlagsForScheduleID() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:  FlagScheduleIDWithAlias,
+			Usage: "Schedule Id",
+		},
+	}
+}
+
+func getFlagsForScheduleDefinition() []cli.Flag {
+	return []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  FlagCronSchedule,
+			Usage: "Cron expression of the schedule times, can be repeated",
+		},
+		cli.IntFlag{
+			Name:  FlagInterval,
+			Usage: "Interval of the schedule times in seconds, aligned on the unix epoch",
+		},
+		cli.IntFlag{
+			Name:  FlagJitter,
+			Usage: "Optional maximum random delay of the actions in seconds",
+		},
+		cli.StringFlag{
+			Name:  FlagStartTime,
+			Usage: "Optional earliest schedule time, in format '2006-01-02T15:04:05+07:00' or raw UnixNano",
+		},
+		cli.StringFlag{
+			Name:  FlagEndTime,
+			Usage: "Optional latest schedule time, in format '2006-01-02T15:04:05+07:00' or raw UnixNano",
+		},
+		cli.StringFlag{
+			Name:  FlagWorkflowIDWithAlias,
+			Usage: "WorkflowId prefix of the started workflows, the schedule time is appended to it",
+		},
+		cli.StringFlag{
+			Name:  FlagWorkflowTypeWithAlias,
+			Usage: "WorkflowTypeName of the started workflows",
+		},
+		cli.StringFlag{
+			Name:  FlagTaskListWithAlias,
+			Usage: "TaskList of the started workflows",
+		},
+		cli.IntFlag{
+			Name:  FlagExecutionTimeoutWithAlias,
+			Usage: "Execution start to close timeout of the started workflows in seconds",
+		},
+		cli.IntFlag{
+			Name:  FlagDecisionTimeoutWithAlias,
+			Value: defaultDecisionTimeoutInSeconds,
+			Usage: "Decision task start to close timeout of the started workflows in seconds",
+		},
+		cli.StringFlag{
+			Name:  FlagInputWithAlias,
+			Usage: "Optional input of the started workflows, in JSON format. For multiple parameters split them by space",
+		},
+		cli.StringFlag{
+			Name:  FlagInputFileWithAlias,
+			Usage: "Optional input of the started workflows from JSON file",
+		},
+		cli.StringFlag{
+			Name:  FlagOverlapPolicy,
+			Value: "skip",
+			Usage: "Policy when an action overlaps a running workflow of the schedule [skip|buffer_one|cancel_other|allow_all]",
+		},
+		cli.IntFlag{
+			Name:  FlagCatchupWindow,
+			Usage: "Optional window in seconds within which missed actions are still taken, defaults to one hour",
+		},
+		cli.BoolFlag{
+			Name:  FlagPaused,
+			Usage: "Create or update the schedule in paused state",
+		},
+		cli.StringFlag{
+			Name:  FlagNotes,
+			Usage: "Optional notes about the state of the schedule",
+		},
+	}
+}
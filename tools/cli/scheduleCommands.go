@@ -0,0 +1,278 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+	commonproto "go.temporal.io/temporal-proto/common"
+
+	"github.com/temporalio/temporal/.gen/proto/scheduleservice"
+)
+
+// CreateSchedule creates a new schedule
+func CreateSchedule(c *cli.Context) {
+	scheduleClient := cFactory.ScheduleClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	scheduleID := getRequiredOption(c, FlagScheduleID)
+
+	request := &scheduleservice.CreateScheduleRequest{
+		Domain:     domain,
+		ScheduleId: scheduleID,
+		Schedule:   getScheduleDefinition(c),
+		Identity:   getCliIdentity(),
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	if _, err := scheduleClient.CreateSchedule(ctx, request); err != nil {
+		ErrorAndExit("Operation CreateSchedule failed.", err)
+	}
+	fmt.Printf("Schedule %s is successfully created.\n", scheduleID)
+}
+
+// DescribeSchedule shows the definition and the state of a schedule
+func DescribeSchedule(c *cli.Context) {
+	scheduleClient := cFactory.ScheduleClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	scheduleID := getRequiredOption(c, FlagScheduleID)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	response, err := scheduleClient.DescribeSchedule(ctx, &scheduleservice.DescribeScheduleRequest{
+		Domain:     domain,
+		ScheduleId: scheduleID,
+	})
+	if err != nil {
+		ErrorAndExit("Operation DescribeSchedule failed.", err)
+	}
+
+	if c.Bool(FlagPrintJSON) {
+		prettyPrintJSONObject(response)
+		return
+	}
+
+	prettyPrintJSONObject(response.GetSchedule())
+	info := response.GetInfo()
+	fmt.Printf("Action count: %d, missed catchup window: %d, skipped by overlap policy: %d\n",
+		info.GetActionCount(), info.GetMissedCatchupWindow(), info.GetOverlapSkipped())
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("|")
+	table.SetHeader([]string{"Nominal Time", "Actual Time", "Workflow Id", "Run Id"})
+	table.SetHeaderLine(false)
+	table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue)
+	for _, action := range info.GetRecentActions() {
+		table.Append([]string{
+			convertTime(action.GetNominalTime(), false),
+			convertTime(action.GetActualTime(), false),
+			action.GetStartedWorkflow().GetWorkflowId(),
+			action.GetStartedWorkflow().GetRunId(),
+		})
+	}
+	table.Render()
+
+	for _, running := range info.GetRunningWorkflows() {
+		fmt.Printf("Running Workflow Id: %s, run Id: %s\n", running.GetWorkflowId(), running.GetRunId())
+	}
+	for _, t := range info.GetFutureActionTimes() {
+		fmt.Printf("Next action time: %s\n", convertTime(t, false))
+	}
+}
+
+// UpdateSchedule replaces the definition of a schedule
+func UpdateSchedule(c *cli.Context) {
+	scheduleClient := cFactory.ScheduleClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	scheduleID := getRequiredOption(c, FlagScheduleID)
+
+	request := &scheduleservice.UpdateScheduleRequest{
+		Domain:     domain,
+		ScheduleId: scheduleID,
+		Schedule:   getScheduleDefinition(c),
+		Identity:   getCliIdentity(),
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	if _, err := scheduleClient.UpdateSchedule(ctx, request); err != nil {
+		ErrorAndExit("Operation UpdateSchedule failed.", err)
+	}
+	fmt.Printf("Schedule %s is successfully updated.\n", scheduleID)
+}
+
+// PauseSchedule pauses a schedule
+func PauseSchedule(c *cli.Context) {
+	scheduleClient := cFactory.ScheduleClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	scheduleID := getRequiredOption(c, FlagScheduleID)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	_, err := scheduleClient.PauseSchedule(ctx, &scheduleservice.PauseScheduleRequest{
+		Domain:     domain,
+		ScheduleId: scheduleID,
+		Notes:      c.String(FlagNotes),
+		Identity:   getCliIdentity(),
+	})
+	if err != nil {
+		ErrorAndExit("Operation PauseSchedule failed.", err)
+	}
+	fmt.Printf("Schedule %s is paused.\n", scheduleID)
+}
+
+// ResumeSchedule resumes a paused schedule
+func ResumeSchedule(c *cli.Context) {
+	scheduleClient := cFactory.ScheduleClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	scheduleID := getRequiredOption(c, FlagScheduleID)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	_, err := scheduleClient.ResumeSchedule(ctx, &scheduleservice.ResumeScheduleRequest{
+		Domain:     domain,
+		ScheduleId: scheduleID,
+		Notes:      c.String(FlagNotes),
+		Identity:   getCliIdentity(),
+	})
+	if err != nil {
+		ErrorAndExit("Operation ResumeSchedule failed.", err)
+	}
+	fmt.Printf("Schedule %s is resumed.\n", scheduleID)
+}
+
+// BackfillSchedule takes the actions of a schedule within a past time range
+func BackfillSchedule(c *cli.Context) {
+	scheduleClient := cFactory.ScheduleClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	scheduleID := getRequiredOption(c, FlagScheduleID)
+	now := time.Now()
+	startTime := parseTime(getRequiredOption(c, FlagStartTime), 0, now)
+	endTime := parseTime(c.String(FlagEndTime), now.UnixNano(), now)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	_, err := scheduleClient.BackfillSchedule(ctx, &scheduleservice.BackfillScheduleRequest{
+		Domain:        domain,
+		ScheduleId:    scheduleID,
+		StartTime:     startTime,
+		EndTime:       endTime,
+		OverlapPolicy: c.String(FlagOverlapPolicy),
+		Identity:      getCliIdentity(),
+	})
+	if err != nil {
+		ErrorAndExit("Operation BackfillSchedule failed.", err)
+	}
+	fmt.Printf("Backfill of schedule %s from %s to %s is requested.\n",
+		scheduleID, convertTime(startTime, false), convertTime(endTime, false))
+}
+
+// DeleteSchedule deletes a schedule
+func DeleteSchedule(c *cli.Context) {
+	scheduleClient := cFactory.ScheduleClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	scheduleID := getRequiredOption(c, FlagScheduleID)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	_, err := scheduleClient.DeleteSchedule(ctx, &scheduleservice.DeleteScheduleRequest{
+		Domain:     domain,
+		ScheduleId: scheduleID,
+		Identity:   getCliIdentity(),
+	})
+	if err != nil {
+		ErrorAndExit("Operation DeleteSchedule failed.", err)
+	}
+	fmt.Printf("Schedule %s is successfully deleted.\n", scheduleID)
+}
+
+// ListSchedules lists the schedules of a domain
+func ListSchedules(c *cli.Context) {
+	scheduleClient := cFactory.ScheduleClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	more := c.Bool(FlagMore)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("|")
+	table.SetHeader([]string{"Schedule Id", "Create Time"})
+	table.SetHeaderLine(false)
+	table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue)
+
+	var nextPageToken []byte
+	for {
+		ctx, cancel := newContext(c)
+		response, err := scheduleClient.ListSchedules(ctx, &scheduleservice.ListSchedulesRequest{
+			Domain:          domain,
+			MaximumPageSize: int32(c.Int(FlagPageSize)),
+			NextPageToken:   nextPageToken,
+		})
+		cancel()
+		if err != nil {
+			ErrorAndExit("Operation ListSchedules failed.", err)
+		}
+
+		for _, schedule := range response.GetSchedules() {
+			table.Append([]string{schedule.GetScheduleId(), convertTime(schedule.GetCreateTime(), false)})
+		}
+		table.Render()
+		table.ClearRows()
+
+		nextPageToken = response.GetNextPageToken()
+		if !more || len(nextPageToken) == 0 || !showNextPage() {
+			break
+		}
+	}
+}
+
+func getScheduleDefinition(c *cli.Context) *scheduleservice.Schedule {
+	now := time.Now()
+	return &scheduleservice.Schedule{
+		Spec: &scheduleservice.ScheduleSpec{
+			CronExpressions:   c.StringSlice(FlagCronSchedule),
+			IntervalInSeconds: int64(c.Int(FlagInterval)),
+			StartTime:         parseTime(c.String(FlagStartTime), 0, now),
+			EndTime:           parseTime(c.String(FlagEndTime), 0, now),
+			JitterInSeconds:   int64(c.Int(FlagJitter)),
+		},
+		Action: &scheduleservice.ScheduleAction{
+			WorkflowId:                          getRequiredOption(c, FlagWorkflowID),
+			WorkflowType:                        &commonproto.WorkflowType{Name: getRequiredOption(c, FlagWorkflowType)},
+			TaskList:                            &commonproto.TaskList{Name: getRequiredOption(c, FlagTaskList)},
+			Input:                               []byte(processJSONInput(c)),
+			ExecutionStartToCloseTimeoutSeconds: int32(c.Int(FlagExecutionTimeout)),
+			TaskStartToCloseTimeoutSeconds:      int32(c.Int(FlagDecisionTimeout)),
+		},
+		Policies: &scheduleservice.SchedulePolicies{
+			OverlapPolicy:          c.String(FlagOverlapPolicy),
+			CatchupWindowInSeconds: int64(c.Int(FlagCatchupWindow)),
+		},
+		State: &scheduleservice.ScheduleState{
+			Paused: c.Bool(FlagPaused),
+			Notes:  c.String(FlagNotes),
+		},
+	}
+}
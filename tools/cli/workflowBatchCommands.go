@@ -138,6 +138,13 @@ func StartBatchJob(c *cli.Context) {
 		sigName = getRequiredOption(c, FlagSignalName)
 		sigVal = getRequiredOption(c, FlagInput)
 	}
+	var resetType, badBinaryChecksum string
+	if batchType == batcher.BatchTypeReset {
+		resetType = getRequiredOption(c, FlagResetType)
+		if resetType == batcher.ResetTypeBadBinary {
+			badBinaryChecksum = getRequiredOption(c, FlagResetBadBinaryChecksum)
+		}
+	}
 	rps := c.Int(FlagRPS)
 
 	client := cFactory.SDKClient(c, common.SystemLocalDomainName)
@@ -190,6 +197,10 @@ func StartBatchJob(c *cli.Context) {
 			SignalName: sigName,
 			Input:      sigVal,
 		},
+		ResetParams: batcher.ResetParams{
+			ResetType:         resetType,
+			BadBinaryChecksum: badBinaryChecksum,
+		},
 		RPS: rps,
 	}
 	wf, err := client.ExecuteWorkflow(tcCtx, options, batcher.BatchWFTypeName, params)
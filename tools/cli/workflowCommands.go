@@ -47,6 +47,7 @@ import (
 	"go.temporal.io/temporal/client"
 
 	cliproto "github.com/temporalio/temporal/.gen/proto/cli"
+	"github.com/temporalio/temporal/.gen/proto/updateservice"
 	"github.com/temporalio/temporal/common/clock"
 	"github.com/temporalio/temporal/common/codec"
 	"github.com/temporalio/temporal/service/history"
@@ -469,6 +470,37 @@ func SignalWorkflow(c *cli.Context) {
 	}
 }
 
+// UpdateWorkflow sends an update to a workflow execution and prints the result
+func UpdateWorkflow(c *cli.Context) {
+	updateClient := cFactory.UpdateClient(c)
+
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	wid := getRequiredOption(c, FlagWorkflowID)
+	rid := c.String(FlagRunID)
+	name := getRequiredOption(c, FlagName)
+	input := processJSONInput(c)
+
+	tcCtx, cancel := newContext(c)
+	defer cancel()
+	resp, err := updateClient.UpdateWorkflowExecution(tcCtx, &updateservice.UpdateWorkflowExecutionRequest{
+		Domain: domain,
+		WorkflowExecution: &commonproto.WorkflowExecution{
+			WorkflowId: wid,
+			RunId:      rid,
+		},
+		UpdateName: name,
+		Input:      []byte(input),
+		Identity:   getCliIdentity(),
+	})
+	if err != nil {
+		ErrorAndExit("Update workflow failed.", err)
+		return
+	}
+
+	// assume it is json encoded
+	fmt.Printf("Update result as JSON:\n%v\n", string(resp.GetResult()))
+}
+
 // QueryWorkflow query workflow execution
 func QueryWorkflow(c *cli.Context) {
 	getRequiredGlobalOption(c, FlagDomain) // for pre-check and alert if not provided